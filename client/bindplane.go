@@ -16,15 +16,19 @@ package client
 
 import (
 	"context"
+	"crypto/tls"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/go-resty/resty/v2"
+	"github.com/gorilla/websocket"
 	"github.com/hashicorp/go-multierror"
 	jsoniter "github.com/json-iterator/go"
 	"go.uber.org/zap"
@@ -50,17 +54,21 @@ type AgentInstallOptions struct {
 	SecretKey string
 	// RemoteURL TODO(doc)
 	RemoteURL string
+	// EnrollmentToken is used by the agent instead of the SecretKey to enroll with BindPlane.
+	EnrollmentToken string
 }
 
 // ----------------------------------------------------------------------
 
 // queryOptions represents the set of options available for a store query
 type queryOptions struct {
-	selector string
-	query    string
-	offset   int
-	limit    int
-	sort     string
+	selector      string
+	query         string
+	offset        int
+	limit         int
+	sort          string
+	continueToken string
+	next          *string
 }
 
 func makeQueryOptions(options []QueryOption) queryOptions {
@@ -105,14 +113,54 @@ func WithLimit(limit int) QueryOption {
 }
 
 // WithSort sets the sort order for the request. The sort value is the name of the field, sorted ascending. To sort
-// descending, prefix the field with a minus sign (-). Some Stores only allow sorting by certain fields. Sort values not
-// supported will be ignored.
+// descending, prefix the field with a minus sign (-). Agents can be sorted by id, name, status, or version. Resources
+// can be sorted by name or displayName, sources, processors, and destinations by type, and agent versions by version.
 func WithSort(field string) QueryOption {
 	return func(opts *queryOptions) {
 		opts.sort = field
 	}
 }
 
+// WithContinue sets the continue token returned with the previous page of results. The results will start after the
+// last item of that page. The sort must be the same as the sort of the previous page.
+func WithContinue(token string) QueryOption {
+	return func(opts *queryOptions) {
+		opts.continueToken = token
+	}
+}
+
+// WithNextContinue stores the continue token returned with the results in next. It will be empty if there are no more
+// results.
+func WithNextContinue(next *string) QueryOption {
+	return func(opts *queryOptions) {
+		opts.next = next
+	}
+}
+
+// queryParams returns the query parameters used to request a list of resources
+func (opts queryOptions) queryParams() map[string]string {
+	params := map[string]string{}
+	if opts.selector != "" {
+		params["selector"] = opts.selector
+	}
+	if opts.query != "" {
+		params["query"] = opts.query
+	}
+	if opts.offset != 0 {
+		params["offset"] = fmt.Sprintf("%d", opts.offset)
+	}
+	if opts.limit != 0 {
+		params["limit"] = fmt.Sprintf("%d", opts.limit)
+	}
+	if opts.sort != "" {
+		params["sort"] = opts.sort
+	}
+	if opts.continueToken != "" {
+		params["continue"] = opts.continueToken
+	}
+	return params
+}
+
 // BindPlane TODO(doc)
 type BindPlane interface {
 	// Agents TODO(doc)
@@ -121,7 +169,7 @@ type BindPlane interface {
 	Agent(ctx context.Context, id string) (*model.Agent, error)
 	DeleteAgents(ctx context.Context, agentIDs []string) ([]*model.Agent, error)
 
-	AgentVersions(ctx context.Context) ([]*model.AgentVersion, error)
+	AgentVersions(ctx context.Context, options ...QueryOption) ([]*model.AgentVersion, error)
 	AgentVersion(ctx context.Context, name string) (*model.AgentVersion, error)
 	DeleteAgentVersion(ctx context.Context, name string) error
 
@@ -130,7 +178,7 @@ type BindPlane interface {
 	SyncAgentVersions(ctx context.Context, version string) ([]*model.AnyResourceStatus, error)
 
 	// Configurations TODO(doc)
-	Configurations(ctx context.Context) ([]*model.Configuration, error)
+	Configurations(ctx context.Context, options ...QueryOption) ([]*model.Configuration, error)
 	// Configuration TODO(doc)
 	Configuration(ctx context.Context, name string) (*model.Configuration, error)
 	// DeleteConfiguration TODO(doc)
@@ -139,32 +187,39 @@ type BindPlane interface {
 	RawConfiguration(ctx context.Context, name string) (string, error)
 	CopyConfig(ctx context.Context, name, copyName string) error
 
-	Sources(ctx context.Context) ([]*model.Source, error)
+	Sources(ctx context.Context, options ...QueryOption) ([]*model.Source, error)
 	Source(ctx context.Context, name string) (*model.Source, error)
 	DeleteSource(ctx context.Context, name string) error
 
-	SourceTypes(ctx context.Context) ([]*model.SourceType, error)
+	SourceTypes(ctx context.Context, options ...QueryOption) ([]*model.SourceType, error)
 	SourceType(ctx context.Context, name string) (*model.SourceType, error)
 	DeleteSourceType(ctx context.Context, name string) error
 
-	Processors(ctx context.Context) ([]*model.Processor, error)
+	Processors(ctx context.Context, options ...QueryOption) ([]*model.Processor, error)
 	Processor(ctx context.Context, name string) (*model.Processor, error)
 	DeleteProcessor(ctx context.Context, name string) error
 
-	ProcessorTypes(ctx context.Context) ([]*model.ProcessorType, error)
+	ProcessorTypes(ctx context.Context, options ...QueryOption) ([]*model.ProcessorType, error)
 	ProcessorType(ctx context.Context, name string) (*model.ProcessorType, error)
 	DeleteProcessorType(ctx context.Context, name string) error
 
-	Destinations(ctx context.Context) ([]*model.Destination, error)
+	Destinations(ctx context.Context, options ...QueryOption) ([]*model.Destination, error)
 	Destination(ctx context.Context, name string) (*model.Destination, error)
 	DeleteDestination(ctx context.Context, name string) error
 
-	DestinationTypes(ctx context.Context) ([]*model.DestinationType, error)
+	DestinationTypes(ctx context.Context, options ...QueryOption) ([]*model.DestinationType, error)
 	DestinationType(ctx context.Context, name string) (*model.DestinationType, error)
 	DeleteDestinationType(ctx context.Context, name string) error
 
+	// ResourceUsage returns the resources that use the resource with the specified kind and name, directly or
+	// transitively, and the agents with a configuration that uses it
+	ResourceUsage(ctx context.Context, kind model.Kind, name string) (*model.ResourceUsage, error)
+
 	// Apply TODO(doc)
 	Apply(ctx context.Context, r []*model.AnyResource) ([]*model.AnyResourceStatus, error)
+	// ApplyWithPreconditions applies the resources if none of the resources in the preconditions changed on the server.
+	// It returns an error wrapping ErrPreconditionFailed if a precondition is not met.
+	ApplyWithPreconditions(ctx context.Context, r []*model.AnyResource, preconditions []model.ResourcePrecondition) ([]*model.AnyResourceStatus, error)
 	// Delete TODO(doc)
 	Delete(ctx context.Context, r []*model.AnyResource) ([]*model.AnyResourceStatus, error)
 
@@ -181,21 +236,91 @@ type BindPlane interface {
 	// ApplyAgentLabels applies the specified labels to an agent, merging the specified labels with the existing labels
 	// and returning the labels of the agent
 	ApplyAgentLabels(ctx context.Context, id string, labels *model.Labels, override bool) (*model.Labels, error)
+	// RotateAgentCredential issues a new credential to the agent
+	RotateAgentCredential(ctx context.Context, id string) error
+	// ResolveAgentConflict assigns a new ID to each host that connected with the ID of the agent while it was already
+	// connected
+	ResolveAgentConflict(ctx context.Context, id string) error
+	// RevokeAgentCertificate revokes the client certificates issued to the agent by the agent CA
+	RevokeAgentCertificate(ctx context.Context, id string) error
+
+	// EnrollmentTokens returns the enrollment tokens without their secrets
+	EnrollmentTokens(ctx context.Context) ([]*model.EnrollmentToken, error)
+	// CreateEnrollmentToken creates an enrollment token and returns it with the token value to give to agents
+	CreateEnrollmentToken(ctx context.Context, request *model.PostEnrollmentTokenRequest) (*model.EnrollmentTokenResponse, error)
+	// DeleteEnrollmentToken deletes the enrollment token with the specified id
+	DeleteEnrollmentToken(ctx context.Context, id string) error
+
+	// ApproveAgents approves pending agents and returns the IDs of the agents that were approved
+	ApproveAgents(ctx context.Context, request *model.PostApproveAgentsRequest) ([]string, error)
+	// AgentDenyRules returns the agent deny rules
+	AgentDenyRules(ctx context.Context) ([]*model.AgentDenyRule, error)
+	// CreateAgentDenyRule creates an agent deny rule, disconnecting matching agents
+	CreateAgentDenyRule(ctx context.Context, request *model.PostAgentDenyRuleRequest) (*model.AgentDenyRuleResponse, error)
+	// DeleteAgentDenyRule deletes the agent deny rule with the specified id
+	DeleteAgentDenyRule(ctx context.Context, id string) error
+
+	// UserSessions returns the active sessions of users logged in to the UI
+	UserSessions(ctx context.Context) ([]*model.UserSession, error)
+	// RevokeUserSession revokes the user session with the specified id
+	RevokeUserSession(ctx context.Context, id string) error
+	// RevokeUserSessions revokes every session of the user and returns the IDs of the revoked sessions
+	RevokeUserSessions(ctx context.Context, user string) ([]string, error)
+
+	// GitOpsSyncStatus returns the status of the most recent sync of resources from the GitOps repository
+	GitOpsSyncStatus(ctx context.Context) (*model.GitOpsSyncStatus, error)
+
+	// OIDCConfig returns the configuration of the OpenID Connect provider used to log in
+	OIDCConfig(ctx context.Context) (*model.OIDCConfigResponse, error)
+
+	// Watch calls the handler with each batch of changes to resources of the kind until the context is done. The name
+	// and selector are optional and limit the resources watched. The handler is first called with no events once the
+	// watch has started, so that resources listed from then on are not missing changes.
+	Watch(ctx context.Context, kind model.Kind, name string, selector string, handler func([]*model.WatchEvent) error) error
 }
 
 type bindplaneClient struct {
-	client *resty.Client
-	config *common.Client
+	client    *resty.Client
+	config    *common.Client
+	tlsConfig *tls.Config
 	*zap.Logger
 }
 
 var _ BindPlane = (*bindplaneClient)(nil)
 
+var (
+	// ErrResourceNotFound is returned when the server responds with 404 Not Found
+	ErrResourceNotFound = errors.New("resource not found")
+
+	// ErrPreconditionFailed is returned by ApplyWithPreconditions when a resource changed on the server
+	ErrPreconditionFailed = errors.New("precondition failed")
+)
+
+// statusCodeError is an error for an unexpected status code that can be matched against a sentinel error with
+// errors.Is
+type statusCodeError struct {
+	message string
+	err     error
+}
+
+func (e *statusCodeError) Error() string {
+	return e.message
+}
+
+func (e *statusCodeError) Unwrap() error {
+	return e.err
+}
+
 // NewBindPlane takes a client configuration, logger and returns a new BindPlane.
 func NewBindPlane(config *common.Client, logger *zap.Logger) (BindPlane, error) {
 	client := resty.New()
 	client.SetTimeout(time.Second * 20)
-	client.SetBasicAuth(config.Username, config.Password)
+	if config.Token != "" {
+		// ID token saved by bindplanectl login
+		client.SetAuthToken(config.Token)
+	} else {
+		client.SetBasicAuth(config.Username, config.Password)
+	}
 	client.SetBaseURL(fmt.Sprintf("%s/v1", config.BindPlaneURL()))
 
 	tlsConfig, err := tlsClient(config.Certificate, config.PrivateKey, config.CertificateAuthority, config.InsecureSkipVerify)
@@ -205,9 +330,10 @@ func NewBindPlane(config *common.Client, logger *zap.Logger) (BindPlane, error)
 	client.SetTLSClientConfig(tlsConfig)
 
 	return &bindplaneClient{
-		client: client,
-		config: config,
-		Logger: logger.Named("bindplane-client"),
+		client:    client,
+		config:    config,
+		tlsConfig: tlsConfig,
+		Logger:    logger.Named("bindplane-client"),
 	}, nil
 }
 
@@ -215,22 +341,9 @@ func NewBindPlane(config *common.Client, logger *zap.Logger) (BindPlane, error)
 func (c *bindplaneClient) Agents(ctx context.Context, options ...QueryOption) ([]*model.Agent, error) {
 	c.Debug("Agents called")
 
-	opts := makeQueryOptions(options)
-	ar := &model.AgentsResponse{}
-	resp, err := c.client.R().
-		SetResult(ar).
-		SetQueryParam("selector", opts.selector).
-		SetQueryParam("query", opts.query).
-		SetQueryParam("offset", fmt.Sprintf("%d", opts.offset)).
-		SetQueryParam("limit", fmt.Sprintf("%d", opts.limit)).
-		SetQueryParam("sort", opts.sort).
-		Get("/agents")
-	if err != nil {
-		logRequestError(c.Logger, err, "/agents")
-		return nil, err
-	}
-
-	return ar.Agents, c.statusError(resp, err, "unable to get agents")
+	result := model.AgentsResponse{}
+	err := c.resources(ctx, "/agents", &result, options)
+	return result.Agents, err
 }
 
 // Agent TODO(doc)
@@ -261,9 +374,9 @@ func (c *bindplaneClient) DeleteAgents(ctx context.Context, ids []string) ([]*mo
 
 // ----------------------------------------------------------------------
 
-func (c *bindplaneClient) AgentVersions(ctx context.Context) ([]*model.AgentVersion, error) {
+func (c *bindplaneClient) AgentVersions(ctx context.Context, options ...QueryOption) ([]*model.AgentVersion, error) {
 	result := model.AgentVersionsResponse{}
-	err := c.resources(ctx, "/agent-versions", &result)
+	err := c.resources(ctx, "/agent-versions", &result, options)
 	return result.AgentVersions, err
 }
 
@@ -295,12 +408,12 @@ func (c *bindplaneClient) SyncAgentVersions(ctx context.Context, version string)
 // ----------------------------------------------------------------------
 
 // Configurations TODO(doc)
-func (c *bindplaneClient) Configurations(ctx context.Context) ([]*model.Configuration, error) {
+func (c *bindplaneClient) Configurations(ctx context.Context, options ...QueryOption) ([]*model.Configuration, error) {
 	c.Debug("Configurations called")
 
-	pr := &model.ConfigurationsResponse{}
-	resp, err := c.client.R().SetResult(pr).Get("/configurations")
-	return pr.Configurations, c.statusError(resp, err, "unable to get configurations")
+	result := model.ConfigurationsResponse{}
+	err := c.resources(ctx, "/configurations", &result, options)
+	return result.Configurations, err
 }
 
 // Configuration TODO(doc)
@@ -326,9 +439,9 @@ func (c *bindplaneClient) RawConfiguration(ctx context.Context, name string) (st
 
 // ----------------------------------------------------------------------
 
-func (c *bindplaneClient) Sources(ctx context.Context) ([]*model.Source, error) {
+func (c *bindplaneClient) Sources(ctx context.Context, options ...QueryOption) ([]*model.Source, error) {
 	result := model.SourcesResponse{}
-	err := c.resources(ctx, "/sources", &result)
+	err := c.resources(ctx, "/sources", &result, options)
 	return result.Sources, err
 }
 
@@ -344,9 +457,9 @@ func (c *bindplaneClient) DeleteSource(ctx context.Context, name string) error {
 
 // ----------------------------------------------------------------------
 
-func (c *bindplaneClient) SourceTypes(ctx context.Context) ([]*model.SourceType, error) {
+func (c *bindplaneClient) SourceTypes(ctx context.Context, options ...QueryOption) ([]*model.SourceType, error) {
 	result := model.SourceTypesResponse{}
-	err := c.resources(ctx, "/source-types", &result)
+	err := c.resources(ctx, "/source-types", &result, options)
 	return result.SourceTypes, err
 }
 
@@ -362,9 +475,9 @@ func (c *bindplaneClient) DeleteSourceType(ctx context.Context, name string) err
 
 // ----------------------------------------------------------------------
 
-func (c *bindplaneClient) Processors(ctx context.Context) ([]*model.Processor, error) {
+func (c *bindplaneClient) Processors(ctx context.Context, options ...QueryOption) ([]*model.Processor, error) {
 	result := model.ProcessorsResponse{}
-	err := c.resources(ctx, "/processors", &result)
+	err := c.resources(ctx, "/processors", &result, options)
 	return result.Processors, err
 }
 
@@ -380,9 +493,9 @@ func (c *bindplaneClient) DeleteProcessor(ctx context.Context, name string) erro
 
 // ----------------------------------------------------------------------
 
-func (c *bindplaneClient) ProcessorTypes(ctx context.Context) ([]*model.ProcessorType, error) {
+func (c *bindplaneClient) ProcessorTypes(ctx context.Context, options ...QueryOption) ([]*model.ProcessorType, error) {
 	result := model.ProcessorTypesResponse{}
-	err := c.resources(ctx, "/processor-types", &result)
+	err := c.resources(ctx, "/processor-types", &result, options)
 	return result.ProcessorTypes, err
 }
 
@@ -398,9 +511,9 @@ func (c *bindplaneClient) DeleteProcessorType(ctx context.Context, name string)
 
 // ----------------------------------------------------------------------
 
-func (c *bindplaneClient) Destinations(ctx context.Context) ([]*model.Destination, error) {
+func (c *bindplaneClient) Destinations(ctx context.Context, options ...QueryOption) ([]*model.Destination, error) {
 	result := model.DestinationsResponse{}
-	err := c.resources(ctx, "/destinations", &result)
+	err := c.resources(ctx, "/destinations", &result, options)
 	return result.Destinations, err
 }
 
@@ -416,9 +529,9 @@ func (c *bindplaneClient) DeleteDestination(ctx context.Context, name string) er
 
 // ----------------------------------------------------------------------
 
-func (c *bindplaneClient) DestinationTypes(ctx context.Context) ([]*model.DestinationType, error) {
+func (c *bindplaneClient) DestinationTypes(ctx context.Context, options ...QueryOption) ([]*model.DestinationType, error) {
 	result := model.DestinationTypesResponse{}
-	err := c.resources(ctx, "/destination-types", &result)
+	err := c.resources(ctx, "/destination-types", &result, options)
 	return result.DestinationTypes, err
 }
 
@@ -434,12 +547,47 @@ func (c *bindplaneClient) DeleteDestinationType(ctx context.Context, name string
 
 // ----------------------------------------------------------------------
 
+// usageResourcesURLs are the REST resources that support /:name/usage
+var usageResourcesURLs = map[model.Kind]string{
+	model.KindConfiguration:   "/configurations",
+	model.KindSource:          "/sources",
+	model.KindSourceType:      "/source-types",
+	model.KindProcessor:       "/processors",
+	model.KindProcessorType:   "/processor-types",
+	model.KindDestination:     "/destinations",
+	model.KindDestinationType: "/destination-types",
+}
+
+// ResourceUsage returns the resources that use the resource with the specified kind and name, directly or
+// transitively, and the agents with a configuration that uses it
+func (c *bindplaneClient) ResourceUsage(ctx context.Context, kind model.Kind, name string) (*model.ResourceUsage, error) {
+	resourcesURL, ok := usageResourcesURLs[kind]
+	if !ok {
+		return nil, fmt.Errorf("usage is not available for %s", kind)
+	}
+	result := model.ResourceUsageResponse{}
+	err := c.get(ctx, fmt.Sprintf("%s/%s/usage", resourcesURL, name), &result)
+	return result.Usage, err
+}
+
+// ----------------------------------------------------------------------
+
 // Apply TODO(doc)
 func (c *bindplaneClient) Apply(ctx context.Context, resources []*model.AnyResource) ([]*model.AnyResourceStatus, error) {
 	c.Debug("Apply called")
+	return c.apply(ctx, resources, nil)
+}
 
+// ApplyWithPreconditions applies the resources if none of the resources in the preconditions changed on the server
+func (c *bindplaneClient) ApplyWithPreconditions(ctx context.Context, resources []*model.AnyResource, preconditions []model.ResourcePrecondition) ([]*model.AnyResourceStatus, error) {
+	c.Debug("ApplyWithPreconditions called")
+	return c.apply(ctx, resources, preconditions)
+}
+
+func (c *bindplaneClient) apply(ctx context.Context, resources []*model.AnyResource, preconditions []model.ResourcePrecondition) ([]*model.AnyResourceStatus, error) {
 	payload := model.ApplyPayload{
-		Resources: resources,
+		Resources:     resources,
+		Preconditions: preconditions,
 	}
 
 	data, err := jsoniter.Marshal(payload)
@@ -448,8 +596,15 @@ func (c *bindplaneClient) Apply(ctx context.Context, resources []*model.AnyResou
 	}
 
 	ar := &model.ApplyResponseClientSide{}
-	resp, err := c.client.R().SetHeader("Content-Type", "application/json").
+	resp, err := c.client.R().SetContext(ctx).SetHeader("Content-Type", "application/json").
 		SetBody(data).SetResult(ar).Post("/apply")
+	if err == nil && resp.StatusCode() == http.StatusConflict {
+		errorResponse := &rest.ErrorResponse{}
+		if err := json.Unmarshal(resp.Body(), errorResponse); err != nil || len(errorResponse.Errors) == 0 {
+			return nil, ErrPreconditionFailed
+		}
+		return nil, &statusCodeError{message: strings.Join(errorResponse.Errors, ", "), err: ErrPreconditionFailed}
+	}
 	return ar.Updates, c.statusError(resp, err, "unable to apply resources")
 }
 
@@ -466,14 +621,18 @@ func (c *bindplaneClient) Delete(ctx context.Context, resources []*model.AnyReso
 		return nil, fmt.Errorf("error marshaling data to json: %w", err)
 	}
 
-	resp, err := c.client.R().SetHeader("Content-Type", "application/json").
+	resp, err := c.client.R().SetContext(ctx).SetHeader("Content-Type", "application/json").
 		SetBody(data).Post("/delete")
 	if err != nil {
 		logRequestError(c.Logger, err, "/delete")
 		return nil, err
 	}
 
+	// errors are also returned in the body so it is decoded before checking the status
 	dr := &model.DeleteResponseClientSide{}
+	if err := json.Unmarshal(resp.Body(), dr); err != nil && resp.StatusCode() == http.StatusAccepted {
+		return nil, err
+	}
 
 	switch resp.StatusCode() {
 	case http.StatusAccepted:
@@ -489,11 +648,6 @@ func (c *bindplaneClient) Delete(ctx context.Context, resources []*model.AnyReso
 		return nil, fmt.Errorf("%s", dr.Errors[0])
 	}
 
-	err = json.Unmarshal(resp.Body(), dr)
-	if err != nil {
-		return nil, err
-	}
-
 	return nil, fmt.Errorf("unknown response from bindplane server")
 }
 
@@ -519,6 +673,7 @@ func (c *bindplaneClient) AgentInstallCommand(ctx context.Context, options Agent
 		SetQueryParam("labels", options.Labels).
 		SetQueryParam("remote-url", options.RemoteURL).
 		SetQueryParam("secret-key", options.SecretKey).
+		SetQueryParam("enrollment-token", options.EnrollmentToken).
 		SetResult(&command).
 		Get(endpoint)
 
@@ -613,6 +768,239 @@ func (c *bindplaneClient) ApplyAgentLabels(ctx context.Context, id string, label
 	return response.Labels, err
 }
 
+// RotateAgentCredential issues a new credential to the agent
+func (c *bindplaneClient) RotateAgentCredential(ctx context.Context, id string) error {
+	endpoint := fmt.Sprintf("/agents/%s/credentials/rotate", id)
+	resp, err := c.client.R().
+		SetContext(ctx).
+		Put(endpoint)
+	return c.statusError(resp, err, fmt.Sprintf("unable to rotate the credential of agent %s", id))
+}
+
+// ResolveAgentConflict assigns a new ID to each host that connected with the ID of the agent while it was already
+// connected
+func (c *bindplaneClient) ResolveAgentConflict(ctx context.Context, id string) error {
+	endpoint := fmt.Sprintf("/agents/%s/conflict/resolve", id)
+	resp, err := c.client.R().
+		SetContext(ctx).
+		Put(endpoint)
+	return c.statusError(resp, err, fmt.Sprintf("unable to resolve the conflict of agent %s", id))
+}
+
+// RevokeAgentCertificate revokes the client certificates issued to the agent by the agent CA
+func (c *bindplaneClient) RevokeAgentCertificate(ctx context.Context, id string) error {
+	endpoint := fmt.Sprintf("/agents/%s/certificate/revoke", id)
+	resp, err := c.client.R().
+		SetContext(ctx).
+		Put(endpoint)
+	return c.statusError(resp, err, fmt.Sprintf("unable to revoke the certificate of agent %s", id))
+}
+
+// ----------------------------------------------------------------------
+
+// EnrollmentTokens returns the enrollment tokens without their secrets
+func (c *bindplaneClient) EnrollmentTokens(ctx context.Context) ([]*model.EnrollmentToken, error) {
+	result := model.EnrollmentTokensResponse{}
+	err := c.resources(ctx, "/enrollment-tokens", &result, nil)
+	return result.EnrollmentTokens, err
+}
+
+// CreateEnrollmentToken creates an enrollment token and returns it with the token value to give to agents
+func (c *bindplaneClient) CreateEnrollmentToken(ctx context.Context, request *model.PostEnrollmentTokenRequest) (*model.EnrollmentTokenResponse, error) {
+	result := &model.EnrollmentTokenResponse{}
+	resp, err := c.client.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", "application/json").
+		SetBody(request).
+		SetResult(result).
+		Post("/enrollment-tokens")
+	if err := c.statusError(resp, err, "unable to create enrollment token"); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// DeleteEnrollmentToken deletes the enrollment token with the specified id
+func (c *bindplaneClient) DeleteEnrollmentToken(ctx context.Context, id string) error {
+	return c.deleteResource(ctx, "/enrollment-tokens", id)
+}
+
+// ApproveAgents approves pending agents and returns the IDs of the agents that were approved
+func (c *bindplaneClient) ApproveAgents(ctx context.Context, request *model.PostApproveAgentsRequest) ([]string, error) {
+	result := &model.ApproveAgentsResponse{}
+	resp, err := c.client.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", "application/json").
+		SetBody(request).
+		SetResult(result).
+		Post("/agents/approve")
+	if err := c.statusError(resp, err, "unable to approve agents"); err != nil {
+		return nil, err
+	}
+	return result.Approved, nil
+}
+
+// AgentDenyRules returns the agent deny rules
+func (c *bindplaneClient) AgentDenyRules(ctx context.Context) ([]*model.AgentDenyRule, error) {
+	result := model.AgentDenyRulesResponse{}
+	err := c.resources(ctx, "/agent-deny-rules", &result, nil)
+	return result.Rules, err
+}
+
+// CreateAgentDenyRule creates an agent deny rule, disconnecting matching agents
+func (c *bindplaneClient) CreateAgentDenyRule(ctx context.Context, request *model.PostAgentDenyRuleRequest) (*model.AgentDenyRuleResponse, error) {
+	result := &model.AgentDenyRuleResponse{}
+	resp, err := c.client.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", "application/json").
+		SetBody(request).
+		SetResult(result).
+		Post("/agent-deny-rules")
+	if err := c.statusError(resp, err, "unable to create agent deny rule"); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// DeleteAgentDenyRule deletes the agent deny rule with the specified id
+func (c *bindplaneClient) DeleteAgentDenyRule(ctx context.Context, id string) error {
+	return c.deleteResource(ctx, "/agent-deny-rules", id)
+}
+
+// UserSessions returns the active sessions of users logged in to the UI
+func (c *bindplaneClient) UserSessions(ctx context.Context) ([]*model.UserSession, error) {
+	result := model.UserSessionsResponse{}
+	err := c.resources(ctx, "/sessions", &result, nil)
+	return result.Sessions, err
+}
+
+// RevokeUserSession revokes the user session with the specified id
+func (c *bindplaneClient) RevokeUserSession(ctx context.Context, id string) error {
+	return c.deleteResource(ctx, "/sessions", id)
+}
+
+// RevokeUserSessions revokes every session of the user and returns the IDs of the revoked sessions
+func (c *bindplaneClient) RevokeUserSessions(ctx context.Context, user string) ([]string, error) {
+	result := &model.RevokeUserSessionsResponse{}
+	resp, err := c.client.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", "application/json").
+		SetBody(&model.PostRevokeUserSessionsRequest{User: user}).
+		SetResult(result).
+		Post("/sessions/revoke")
+	if err := c.statusError(resp, err, "unable to revoke user sessions"); err != nil {
+		return nil, err
+	}
+	return result.Revoked, nil
+}
+
+// ----------------------------------------------------------------------
+
+// GitOpsSyncStatus returns the status of the most recent sync of resources from the GitOps repository
+func (c *bindplaneClient) GitOpsSyncStatus(ctx context.Context) (*model.GitOpsSyncStatus, error) {
+	result := &model.GitOpsSyncResponse{}
+	resp, err := c.client.R().
+		SetContext(ctx).
+		SetResult(result).
+		Get("/gitops/sync")
+	if resp != nil && resp.StatusCode() == http.StatusNotFound {
+		return nil, errors.New("resources have not been synced from a gitops repository")
+	}
+	return result.Status, c.statusError(resp, err, "unable to get the gitops sync status")
+}
+
+// ----------------------------------------------------------------------
+
+// OIDCConfig returns the configuration of the OpenID Connect provider used to log in
+func (c *bindplaneClient) OIDCConfig(ctx context.Context) (*model.OIDCConfigResponse, error) {
+	result := &model.OIDCConfigResponse{}
+	// /oidc/config is not part of the v1 api
+	resp, err := c.client.R().
+		SetContext(ctx).
+		SetResult(result).
+		Get(c.config.BindPlaneURL() + "/oidc/config")
+	if resp != nil && resp.StatusCode() == http.StatusNotFound {
+		return nil, errors.New("single sign-on is not enabled on the server")
+	}
+	return result, c.statusError(resp, err, "unable to get the single sign-on configuration")
+}
+
+// ----------------------------------------------------------------------
+
+// Watch calls the handler with each batch of changes to resources of the kind until the context is done
+func (c *bindplaneClient) Watch(ctx context.Context, kind model.Kind, name string, selector string, handler func([]*model.WatchEvent) error) error {
+	watchURL, err := url.Parse(c.config.BindPlaneURL() + "/v1/watch")
+	if err != nil {
+		return fmt.Errorf("unable to parse the watch url: %w", err)
+	}
+	switch watchURL.Scheme {
+	case "https":
+		watchURL.Scheme = "wss"
+	default:
+		watchURL.Scheme = "ws"
+	}
+	query := url.Values{}
+	query.Set("kind", string(kind))
+	if name != "" {
+		query.Set("name", name)
+	}
+	if selector != "" {
+		query.Set("selector", selector)
+	}
+	watchURL.RawQuery = query.Encode()
+
+	header := http.Header{}
+	if c.config.Token != "" {
+		header.Set("Authorization", "Bearer "+c.config.Token)
+	} else {
+		credentials := base64.StdEncoding.EncodeToString([]byte(c.config.Username + ":" + c.config.Password))
+		header.Set("Authorization", "Basic "+credentials)
+	}
+
+	dialer := &websocket.Dialer{
+		Proxy:            http.ProxyFromEnvironment,
+		HandshakeTimeout: 20 * time.Second,
+		TLSClientConfig:  c.tlsConfig,
+	}
+	conn, resp, err := dialer.DialContext(ctx, watchURL.String(), header)
+	if err != nil {
+		if resp != nil {
+			defer resp.Body.Close()
+			errResponse := &model.ErrorResponse{}
+			if json.NewDecoder(resp.Body).Decode(errResponse) == nil && len(errResponse.Errors) > 0 {
+				return fmt.Errorf("unable to watch %s, got %s: %s", kind, resp.Status, strings.Join(errResponse.Errors, ", "))
+			}
+			return fmt.Errorf("unable to watch %s, got %s", kind, resp.Status)
+		}
+		return fmt.Errorf("unable to watch %s: %w", kind, err)
+	}
+	defer conn.Close()
+
+	// close the connection to stop reading when the context is done
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	for {
+		events := []*model.WatchEvent{}
+		if err := conn.ReadJSON(&events); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("watch of %s ended: %w", kind, err)
+		}
+		if err := handler(events); err != nil {
+			return err
+		}
+	}
+}
+
 // ----------------------------------------------------------------------
 
 func (c *bindplaneClient) CopyConfig(ctx context.Context, name, copyName string) error {
@@ -658,9 +1046,34 @@ func (c *bindplaneClient) CopyConfig(ctx context.Context, name, copyName string)
 
 // ----------------------------------------------------------------------
 
-// resources gets the resources from the REST server and stores them in the provided result.
-func (c *bindplaneClient) resources(ctx context.Context, resourcesURL string, result any) error {
-	return c.get(ctx, resourcesURL, result)
+// resources gets the resources matching the query options from the REST server and stores them in the provided result.
+func (c *bindplaneClient) resources(ctx context.Context, resourcesURL string, result any, options []QueryOption) error {
+	opts := makeQueryOptions(options)
+	resp, err := c.client.R().
+		SetContext(ctx).
+		SetResult(result).
+		SetQueryParams(opts.queryParams()).
+		Get(resourcesURL)
+
+	if err != nil {
+		logRequestError(c.Logger, err, resourcesURL)
+		return err
+	}
+	if err := c.statusError(resp, err, fmt.Sprintf("unable to get %s", resourcesURL)); err != nil {
+		return err
+	}
+
+	if opts.next != nil {
+		// every list response includes the continue token for the next page
+		page := struct {
+			Continue string `json:"continue"`
+		}{}
+		if err := json.Unmarshal(resp.Body(), &page); err != nil {
+			return fmt.Errorf("unable to read the continue token: %w", err)
+		}
+		*opts.next = page.Continue
+	}
+	return nil
 }
 
 // resource gets the resource with the specified name from the REST server and stores it in the provided result.
@@ -752,6 +1165,10 @@ func (c *bindplaneClient) statusError(resp *resty.Response, err error, message s
 		return nil
 	case http.StatusNoContent:
 		return nil
+	case http.StatusNotFound:
+		err := &statusCodeError{message: fmt.Sprintf("%s, got %s", message, resp.Status()), err: ErrResourceNotFound}
+		logRequestError(c.Logger, err, resp.Request.URL)
+		return err
 
 	default:
 		err := fmt.Errorf("%s, got %s", message, resp.Status())
@@ -23,6 +23,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/gorilla/websocket"
+
 	"github.com/observiq/bindplane-op/common"
 	"github.com/observiq/bindplane-op/model"
 	"github.com/stretchr/testify/require"
@@ -120,6 +122,16 @@ func TestQueryOption(t *testing.T) {
 			},
 			expect: queryOptions{},
 		},
+		// WithContinue
+		{
+			name: "continue",
+			optFunc: func() []QueryOption {
+				return []QueryOption{WithContinue("token")}
+			},
+			expect: queryOptions{
+				continueToken: "token",
+			},
+		},
 		// Multiple Options
 		{
 			name: "multi",
@@ -372,6 +384,167 @@ func TestCopyConfig(t *testing.T) {
 	}
 }
 
+func TestListContinue(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/sources", r.URL.Path)
+		require.Equal(t, "env=prod", r.URL.Query().Get("selector"))
+		require.Equal(t, "-name", r.URL.Query().Get("sort"))
+		require.Equal(t, "1", r.URL.Query().Get("limit"))
+		require.Equal(t, "token-1", r.URL.Query().Get("continue"))
+		require.False(t, r.URL.Query().Has("offset"))
+
+		w.Header().Set("Content-Type", "application/json")
+		err := json.NewEncoder(w).Encode(model.SourcesResponse{
+			Sources:  []*model.Source{model.NewSource("source-1", "macos", nil)},
+			Continue: "token-2",
+		})
+		require.NoError(t, err)
+	}
+
+	url, closeFunc := newTestServer(handler)
+	defer closeFunc()
+
+	bp, err := NewBindPlane(&common.Client{}, zap.NewNop())
+	require.NoError(t, err)
+	bp.(*bindplaneClient).client.SetBaseURL(url)
+
+	var next string
+	sources, err := bp.Sources(context.TODO(),
+		WithSelector("env=prod"),
+		WithSort("-name"),
+		WithLimit(1),
+		WithContinue("token-1"),
+		WithNextContinue(&next),
+	)
+	require.NoError(t, err)
+	require.Len(t, sources, 1)
+	require.Equal(t, "source-1", sources[0].Name())
+	require.Equal(t, "token-2", next)
+}
+
+func TestApplyWithPreconditions(t *testing.T) {
+	preconditions := []model.ResourcePrecondition{{Kind: model.KindSource, Name: "source-1", Hash: "hash"}}
+
+	testCases := []struct {
+		description    string
+		responseStatus int
+		responseBody   string
+		expectErr      error
+		errMsg         string
+	}{
+		{
+			description:    "202 Accepted, no error",
+			responseStatus: http.StatusAccepted,
+			responseBody:   `{"updates":[]}`,
+		},
+		{
+			description:    "409 Conflict, precondition failed",
+			responseStatus: http.StatusConflict,
+			responseBody:   `{"errors":["precondition failed: Source source-1 was changed"]}`,
+			expectErr:      ErrPreconditionFailed,
+			errMsg:         "precondition failed: Source source-1 was changed",
+		},
+		{
+			description:    "500 Internal Server Error",
+			responseStatus: http.StatusInternalServerError,
+			responseBody:   `{"errors":["failed"]}`,
+			errMsg:         "unable to apply resources, got 500 Internal Server Error",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, "/apply", r.URL.Path)
+
+				payload := &model.ApplyPayload{}
+				require.NoError(t, json.NewDecoder(r.Body).Decode(payload))
+				require.Equal(t, preconditions, payload.Preconditions)
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(test.responseStatus)
+				_, _ = w.Write([]byte(test.responseBody))
+			}
+
+			url, closeFunc := newTestServer(handler)
+			defer closeFunc()
+
+			bp, err := NewBindPlane(&common.Client{}, zap.NewNop())
+			require.NoError(t, err)
+			bp.(*bindplaneClient).client.SetBaseURL(url)
+
+			_, err = bp.ApplyWithPreconditions(context.TODO(), nil, preconditions)
+			if test.errMsg == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, test.errMsg)
+			if test.expectErr != nil {
+				require.ErrorIs(t, err, test.expectErr)
+			}
+		})
+	}
+}
+
+func TestResourceNotFound(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}
+
+	url, closeFunc := newTestServer(handler)
+	defer closeFunc()
+
+	bp, err := NewBindPlane(&common.Client{}, zap.NewNop())
+	require.NoError(t, err)
+	bp.(*bindplaneClient).client.SetBaseURL(url)
+
+	_, err = bp.Source(context.TODO(), "source-1")
+	require.EqualError(t, err, "unable to get /sources/source-1, got 404 Not Found")
+	require.ErrorIs(t, err, ErrResourceNotFound)
+}
+
+func TestWatch(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v1/watch", r.URL.Path)
+		require.Equal(t, "Agent", r.URL.Query().Get("kind"))
+		require.Equal(t, "env=prod", r.URL.Query().Get("selector"))
+		require.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		require.NoError(t, err)
+		defer conn.Close()
+		require.NoError(t, conn.WriteJSON([]*model.WatchEvent{
+			{Type: model.WatchEventInsert, Kind: model.KindAgent, Name: "1", Resource: json.RawMessage(`{"id":"1"}`)},
+		}))
+		// wait for the client to disconnect
+		_, _, _ = conn.ReadMessage()
+	}
+
+	url, closeFunc := newTestServer(handler)
+	defer closeFunc()
+
+	config := &common.Client{}
+	config.ServerURL = url
+	config.Token = "token"
+	bp, err := NewBindPlane(config, zap.NewNop())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	received := []*model.WatchEvent{}
+	err = bp.Watch(ctx, model.KindAgent, "", "env=prod", func(events []*model.WatchEvent) error {
+		received = append(received, events...)
+		cancel()
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, received, 1)
+	require.Equal(t, "1", received[0].Name)
+	require.JSONEq(t, `{"id":"1"}`, string(received[0].Resource))
+}
+
 func newTestServer(handler http.HandlerFunc) (url string, closeFunc func()) {
 	server := httptest.NewServer(handler)
 	return server.URL, func() { server.Close() }
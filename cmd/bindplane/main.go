@@ -22,14 +22,21 @@ import (
 	"github.com/observiq/bindplane-op/internal/cli"
 	"github.com/observiq/bindplane-op/internal/cli/commands"
 	"github.com/observiq/bindplane-op/internal/cli/commands/apply"
+	"github.com/observiq/bindplane-op/internal/cli/commands/approve"
+	"github.com/observiq/bindplane-op/internal/cli/commands/create"
 	"github.com/observiq/bindplane-op/internal/cli/commands/delete"
 	"github.com/observiq/bindplane-op/internal/cli/commands/get"
 	"github.com/observiq/bindplane-op/internal/cli/commands/initialize"
 	"github.com/observiq/bindplane-op/internal/cli/commands/install"
 	"github.com/observiq/bindplane-op/internal/cli/commands/label"
+	"github.com/observiq/bindplane-op/internal/cli/commands/login"
 	"github.com/observiq/bindplane-op/internal/cli/commands/profile"
+	"github.com/observiq/bindplane-op/internal/cli/commands/resolve"
+	"github.com/observiq/bindplane-op/internal/cli/commands/revoke"
+	"github.com/observiq/bindplane-op/internal/cli/commands/rotate"
 	"github.com/observiq/bindplane-op/internal/cli/commands/serve"
 	"github.com/observiq/bindplane-op/internal/cli/commands/sync"
+	"github.com/observiq/bindplane-op/internal/cli/commands/test"
 	"github.com/observiq/bindplane-op/internal/cli/commands/update"
 	"github.com/observiq/bindplane-op/internal/cli/commands/validate"
 	"github.com/observiq/bindplane-op/internal/cli/commands/version"
@@ -67,8 +74,15 @@ func main() {
 		initialize.Command(bindplane, h, initialize.DualMode),
 		install.Command(bindplane),
 		sync.Command(bindplane),
+		test.Command(bindplane),
 		update.Command(bindplane),
 		validate.Command(bindplane),
+		create.Command(bindplane),
+		rotate.Command(bindplane),
+		resolve.Command(bindplane),
+		revoke.Command(bindplane),
+		login.Command(bindplane, h),
+		approve.Command(bindplane),
 	)
 
 	cobra.CheckErr(rootCmd.Execute())
@@ -22,14 +22,22 @@ import (
 	"github.com/observiq/bindplane-op/internal/cli"
 	"github.com/observiq/bindplane-op/internal/cli/commands"
 	"github.com/observiq/bindplane-op/internal/cli/commands/apply"
+	"github.com/observiq/bindplane-op/internal/cli/commands/approve"
 	"github.com/observiq/bindplane-op/internal/cli/commands/copy"
+	"github.com/observiq/bindplane-op/internal/cli/commands/create"
 	"github.com/observiq/bindplane-op/internal/cli/commands/delete"
+	"github.com/observiq/bindplane-op/internal/cli/commands/edit"
 	"github.com/observiq/bindplane-op/internal/cli/commands/get"
 	"github.com/observiq/bindplane-op/internal/cli/commands/initialize"
 	"github.com/observiq/bindplane-op/internal/cli/commands/install"
 	"github.com/observiq/bindplane-op/internal/cli/commands/label"
+	"github.com/observiq/bindplane-op/internal/cli/commands/login"
 	"github.com/observiq/bindplane-op/internal/cli/commands/profile"
+	"github.com/observiq/bindplane-op/internal/cli/commands/resolve"
+	"github.com/observiq/bindplane-op/internal/cli/commands/revoke"
+	"github.com/observiq/bindplane-op/internal/cli/commands/rotate"
 	"github.com/observiq/bindplane-op/internal/cli/commands/sync"
+	"github.com/observiq/bindplane-op/internal/cli/commands/test"
 	"github.com/observiq/bindplane-op/internal/cli/commands/update"
 	"github.com/observiq/bindplane-op/internal/cli/commands/validate"
 	"github.com/observiq/bindplane-op/internal/cli/commands/version"
@@ -66,9 +74,17 @@ func main() {
 		initialize.Command(bindplane, h, initialize.ClientMode),
 		install.Command(bindplane),
 		sync.Command(bindplane),
+		test.Command(bindplane),
 		update.Command(bindplane),
 		validate.Command(bindplane),
+		create.Command(bindplane),
+		rotate.Command(bindplane),
+		resolve.Command(bindplane),
+		revoke.Command(bindplane),
+		login.Command(bindplane, h),
+		approve.Command(bindplane),
 		copy.Command(bindplane),
+		edit.Command(bindplane),
 	)
 
 	cobra.CheckErr(rootCmd.Execute())
@@ -36,6 +36,22 @@ const (
 	BindPlaneLogName = "bindplane.log"
 	// DefaultProfileName is the name of the default profile
 	DefaultProfileName = "default"
+	// AgentCACertificateName is the name of the agent CA certificate file
+	AgentCACertificateName = "agent-ca.crt"
+	// AgentCAPrivateKeyName is the name of the agent CA private key file
+	AgentCAPrivateKeyName = "agent-ca.key"
+	// DefaultAgentCertificateTTL is the default lifetime of agent certificates issued by the agent CA
+	DefaultAgentCertificateTTL = 90 * 24 * time.Hour
+	// DefaultSessionTimeout is the default maximum lifetime of a user session
+	DefaultSessionTimeout = 12 * time.Hour
+	// DefaultSessionIdleTimeout is the default time after which a user session without requests expires
+	DefaultSessionIdleTimeout = time.Hour
+	// GitOpsDirectoryName is the name of the directory where the GitOps repository is cloned
+	GitOpsDirectoryName = "gitops"
+	// DefaultGitOpsBranch is the default branch of the GitOps repository
+	DefaultGitOpsBranch = "main"
+	// DefaultGitOpsInterval is the default interval at which the GitOps repository is synced
+	DefaultGitOpsInterval = time.Minute
 )
 
 // LogOutput is an enum of possible values for the LogOutput configuration setting
@@ -96,6 +112,8 @@ type Common struct {
 	Username string `mapstructure:"username" yaml:"username,omitempty"`
 	// The basic auth password used for communication between client and server.
 	Password string `mapstructure:"password" yaml:"password,omitempty"`
+	// Token is an OpenID Connect ID token used instead of basic auth by clients. It is set by bindplanectl login.
+	Token string `mapstructure:"token" yaml:"token,omitempty"`
 
 	// TLSConfig is an optional TLS configuration for communication between client and server.
 	TLSConfig `yaml:",inline" mapstructure:",squash"`
@@ -155,6 +173,13 @@ const (
 	StoreTypeGoogleCloud = "googlecloud"
 )
 
+const (
+	// EventBusTypeNATS uses a NATS server to send events between nodes
+	EventBusTypeNATS = "nats"
+	// EventBusTypeRedis uses Redis Streams to send events between nodes
+	EventBusTypeRedis = "redis"
+)
+
 // Server TODO(doc)
 type Server struct {
 	// StoreType indicates the type of store to use. "map", "bbolt", and "googlecloud" are currently supported.
@@ -166,12 +191,36 @@ type Server struct {
 	// GoogleCloudPubSub contains configuration for contacting Google Could Pub/Sub and is used if StoreType == "googlecloud"
 	GoogleCloudPubSub *GoogleCloudPubSub `yaml:"pubsub,omitempty" mapstructure:"pubsub,omitempty"`
 
+	// EventBus contains configuration for the bus used to send events between BindPlane nodes. By default, the
+	// googlecloud store uses Pub/Sub and other stores only send events within a single node.
+	EventBus *EventBus `mapstructure:"eventBus,omitempty" yaml:"eventBus,omitempty"`
+
 	// StorageFilePath TODO(doc)
 	StorageFilePath string `mapstructure:"storageFilePath,omitempty" yaml:"storageFilePath,omitempty"`
 
 	// SecretKey is a shared secret between the server and the agent to ensure agents are authorized to communicate with the server.
 	SecretKey string `mapstructure:"secretKey,omitempty" yaml:"secretKey,omitempty"`
 
+	// DisableSecretKeyAfterEnrollment indicates if agents enrolled with an enrollment token are rejected when they
+	// connect with SecretKey. Agents that were issued a credential must always use it.
+	DisableSecretKeyAfterEnrollment bool `mapstructure:"disableSecretKeyAfterEnrollment,omitempty" yaml:"disableSecretKeyAfterEnrollment,omitempty"`
+
+	// RequireEnrollmentToken indicates if new agents are rejected when they connect with SecretKey. New agents must
+	// enroll with an enrollment token and agents that have connected before can continue to use SecretKey.
+	RequireEnrollmentToken bool `mapstructure:"requireEnrollmentToken,omitempty" yaml:"requireEnrollmentToken,omitempty"`
+
+	// RequireApproval indicates if new agents must be approved before they receive configuration. Agents that were
+	// connected before approval was required are not affected.
+	RequireApproval bool `mapstructure:"requireApproval,omitempty" yaml:"requireApproval,omitempty"`
+
+	// DisconnectedAgentTTL is the time after which agents that are disconnected are removed. Set to 0 to keep
+	// disconnected agents. Defaults to 0.
+	DisconnectedAgentTTL time.Duration `mapstructure:"disconnectedAgentTTL,omitempty" yaml:"disconnectedAgentTTL,omitempty"`
+
+	// AgentCA contains configuration for the built-in certificate authority that issues client certificates to agents
+	// during enrollment. Requires TLS to be enabled.
+	AgentCA *AgentCA `mapstructure:"agentCA,omitempty" yaml:"agentCA,omitempty"`
+
 	// RemoteURL is the URL that agents should use to contact the server
 	RemoteURL string `mapstructure:"remoteURL,omitempty" yaml:"remoteURL,omitempty"`
 
@@ -182,11 +231,39 @@ type Server struct {
 	// SessionSecret is used to encode the user sessions cookies.  It should be a uuid.
 	SessionsSecret string `mapstructure:"sessionsSecret,omitempty" yaml:"sessionsSecret,omitempty"`
 
+	// SessionTimeout is the maximum lifetime of a user session, after which the user must log in again. Defaults to
+	// 12h.
+	SessionTimeout time.Duration `mapstructure:"sessionTimeout,omitempty" yaml:"sessionTimeout,omitempty"`
+
+	// SessionIdleTimeout is the time after which a user session without requests expires. Defaults to 1h.
+	SessionIdleTimeout time.Duration `mapstructure:"sessionIdleTimeout,omitempty" yaml:"sessionIdleTimeout,omitempty"`
+
+	// OIDC contains configuration for OpenID Connect single sign-on. Users can still log in with Username and Password.
+	OIDC *OIDC `mapstructure:"oidc,omitempty" yaml:"oidc,omitempty"`
+
+	// GitOps contains configuration for syncing resources from a git repository
+	GitOps *GitOps `mapstructure:"gitOps,omitempty" yaml:"gitOps,omitempty"`
+
 	Common `yaml:",inline" mapstructure:",squash"`
 
 	// SyncAgentVersionsInterval is the interval at which agent-versions will be synchronized with GitHub. Set to 0 to
 	// turn off synchronization. Disabled if Offline is true.
 	SyncAgentVersionsInterval time.Duration `mapstructure:"syncAgentVersionsInterval,omitempty" yaml:"syncAgentVersionsInterval,omitempty"`
+
+	// ResourceTypeDirs are directories containing SourceType, ProcessorType, and DestinationType yaml files that will be
+	// loaded in addition to the ResourceTypes bundled with BindPlane.
+	ResourceTypeDirs []string `mapstructure:"resourceTypeDirs,omitempty" yaml:"resourceTypeDirs,omitempty"`
+
+	// ResourceTypeCatalogURL is the URL of a catalog index listing yaml files containing ResourceTypes to load.
+	ResourceTypeCatalogURL string `mapstructure:"resourceTypeCatalogURL,omitempty" yaml:"resourceTypeCatalogURL,omitempty"`
+
+	// ResourceTypeSyncInterval is the interval at which ResourceTypeDirs and ResourceTypeCatalogURL are checked for
+	// changes. Set to 0 to only load ResourceTypes at startup.
+	ResourceTypeSyncInterval time.Duration `mapstructure:"resourceTypeSyncInterval,omitempty" yaml:"resourceTypeSyncInterval,omitempty"`
+
+	// ResourceTypeConflicts determines which ResourceType is used when a loaded ResourceType has the same kind and name
+	// as a bundled ResourceType. One of "external" (default) or "builtin".
+	ResourceTypeConflicts string `mapstructure:"resourceTypeConflicts,omitempty" yaml:"resourceTypeConflicts,omitempty"`
 }
 
 // GoogleCloudDatastore contains the configuration for google cloud datastore
@@ -208,6 +285,111 @@ type GoogleCloudPubSub struct {
 	Subscription string `mapstructure:"subscription,omitempty" yaml:"subscription,omitempty"`
 }
 
+// EventBus is configuration for the bus used to send events between BindPlane nodes
+type EventBus struct {
+	// Type is the type of bus to use. "nats" and "redis" are currently supported. If empty, the default for the store is
+	// used.
+	Type string `mapstructure:"type,omitempty" yaml:"type,omitempty"`
+
+	// NATS contains configuration for contacting a NATS server and is used if Type == "nats"
+	NATS *NATS `mapstructure:"nats,omitempty" yaml:"nats,omitempty"`
+
+	// Redis contains configuration for contacting a Redis server and is used if Type == "redis"
+	Redis *Redis `mapstructure:"redis,omitempty" yaml:"redis,omitempty"`
+}
+
+// NATS is configuration for a server's NATS connection
+type NATS struct {
+	URL             string `mapstructure:"url,omitempty" yaml:"url,omitempty"`
+	CredentialsFile string `mapstructure:"credentialsFile,omitempty" yaml:"credentialsFile,omitempty"`
+}
+
+// Redis is configuration for a server's Redis connection. Events are sent with Redis Streams.
+type Redis struct {
+	Address  string `mapstructure:"address,omitempty" yaml:"address,omitempty"`
+	Username string `mapstructure:"username,omitempty" yaml:"username,omitempty"`
+	Password string `mapstructure:"password,omitempty" yaml:"password,omitempty"`
+	DB       int    `mapstructure:"db,omitempty" yaml:"db,omitempty"`
+
+	// MaxLen is the approximate maximum number of events kept in each stream
+	MaxLen int64 `mapstructure:"maxLen,omitempty" yaml:"maxLen,omitempty"`
+}
+
+// OIDC is configuration for OpenID Connect single sign-on
+type OIDC struct {
+	// Issuer is the URL of the OpenID Connect provider, e.g. https://accounts.google.com
+	Issuer string `mapstructure:"issuer,omitempty" yaml:"issuer,omitempty"`
+
+	// ClientID is the ID of the client registered with the provider for the UI
+	ClientID string `mapstructure:"clientID,omitempty" yaml:"clientID,omitempty"`
+
+	// ClientSecret is the secret of the client registered with the provider for the UI. It is optional if the provider
+	// allows public clients using PKCE.
+	ClientSecret string `mapstructure:"clientSecret,omitempty" yaml:"clientSecret,omitempty"`
+
+	// CLIClientID is the ID of the public client used by bindplanectl login with the device authorization flow.
+	// Defaults to ClientID.
+	CLIClientID string `mapstructure:"cliClientID,omitempty" yaml:"cliClientID,omitempty"`
+
+	// RedirectURL is the URL the provider redirects to after login. Defaults to /oidc/callback on the ServerURL.
+	RedirectURL string `mapstructure:"redirectURL,omitempty" yaml:"redirectURL,omitempty"`
+
+	// Scopes are the scopes requested in addition to openid. Defaults to profile, email, and groups.
+	Scopes []string `mapstructure:"scopes,omitempty" yaml:"scopes,omitempty"`
+
+	// GroupsClaim is the ID token claim that contains the groups of the user. Defaults to groups.
+	GroupsClaim string `mapstructure:"groupsClaim,omitempty" yaml:"groupsClaim,omitempty"`
+
+	// GroupRoles maps groups of the provider to BindPlane roles, admin or viewer. A user in several groups is given the
+	// role with the most access.
+	GroupRoles map[string]string `mapstructure:"groupRoles,omitempty" yaml:"groupRoles,omitempty"`
+
+	// DefaultRole is the role of users that are not in any of the GroupRoles. If empty, those users can't log in.
+	DefaultRole string `mapstructure:"defaultRole,omitempty" yaml:"defaultRole,omitempty"`
+}
+
+// GitOps is configuration for syncing resources from a git repository
+type GitOps struct {
+	// Repository is the URL or path of the git repository, e.g. https://github.com/example/bindplane-config.git
+	Repository string `mapstructure:"repository,omitempty" yaml:"repository,omitempty"`
+
+	// Branch is the branch of the repository to sync. Defaults to main.
+	Branch string `mapstructure:"branch,omitempty" yaml:"branch,omitempty"`
+
+	// Path is the directory of the repository containing resource yaml files. Defaults to the root of the repository.
+	Path string `mapstructure:"path,omitempty" yaml:"path,omitempty"`
+
+	// Interval is the interval at which the repository is pulled. Defaults to 1m.
+	Interval time.Duration `mapstructure:"interval,omitempty" yaml:"interval,omitempty"`
+
+	// Prune removes resources that were synced from the repository and have been removed from it
+	Prune bool `mapstructure:"prune,omitempty" yaml:"prune,omitempty"`
+
+	// Directory is where the repository is cloned. Defaults to gitops in the BindPlane home directory.
+	Directory string `mapstructure:"directory,omitempty" yaml:"directory,omitempty"`
+}
+
+// AgentCA is configuration for the built-in certificate authority used to issue agent client certificates
+type AgentCA struct {
+	// Enabled indicates if client certificates should be issued to agents during enrollment
+	Enabled bool `mapstructure:"enabled,omitempty" yaml:"enabled,omitempty"`
+
+	// Certificate is the path to the x509 PEM encoded CA certificate. It is generated along with PrivateKey if neither
+	// file exists. Defaults to agent-ca.crt in the BindPlane home directory.
+	Certificate string `mapstructure:"certificate,omitempty" yaml:"certificate,omitempty"`
+
+	// PrivateKey is the path to the x509 PEM encoded CA private key. Defaults to agent-ca.key in the BindPlane home
+	// directory.
+	PrivateKey string `mapstructure:"privateKey,omitempty" yaml:"privateKey,omitempty"`
+
+	// CertificateTTL is the lifetime of issued agent certificates. Certificates are renewed after two thirds of their
+	// lifetime has passed. Defaults to 90 days.
+	CertificateTTL time.Duration `mapstructure:"certificateTTL,omitempty" yaml:"certificateTTL,omitempty"`
+
+	// RequireCertificate rejects agents that have been issued a certificate but connect without one
+	RequireCertificate bool `mapstructure:"requireCertificate,omitempty" yaml:"requireCertificate,omitempty"`
+}
+
 // Client TODO(doc)
 type Client struct {
 	Common
@@ -217,6 +399,12 @@ type Client struct {
 type Command struct {
 	// TODO(doc)
 	Output string `mapstructure:"output" yaml:"output"`
+
+	// SortBy is a jsonpath used to sort the resources printed by get, e.g. .metadata.name
+	SortBy string `mapstructure:"sortBy" yaml:"-"`
+
+	// NoHeaders omits the header of table, csv, and custom-columns output
+	NoHeaders bool `mapstructure:"noHeaders" yaml:"-"`
 }
 
 // InitConfig returns a Config struct with zero values, which will be assigned during Command's PersistentPreRun
@@ -241,6 +429,125 @@ func (c *Server) BindAddress() string {
 	return fmt.Sprintf("%s:%s", c.Host, c.Port)
 }
 
+// EventBusConfig returns the EventBus configuration, creating it if it doesn't exist
+func (c *Server) EventBusConfig() *EventBus {
+	if c.EventBus == nil {
+		c.EventBus = &EventBus{}
+	}
+	return c.EventBus
+}
+
+// AgentCAConfig returns the AgentCA configuration, creating it if it doesn't exist
+func (c *Server) AgentCAConfig() *AgentCA {
+	if c.AgentCA == nil {
+		c.AgentCA = &AgentCA{}
+	}
+	return c.AgentCA
+}
+
+// OIDCConfig returns the OIDC configuration, creating it if it doesn't exist
+func (c *Server) OIDCConfig() *OIDC {
+	if c.OIDC == nil {
+		c.OIDC = &OIDC{}
+	}
+	return c.OIDC
+}
+
+// OIDCEnabled returns true if OpenID Connect single sign-on is configured
+func (c *Server) OIDCEnabled() bool {
+	return c.OIDC != nil && c.OIDC.Issuer != ""
+}
+
+// OIDCRedirectURL returns the URL the OpenID Connect provider redirects to after login
+func (c *Server) OIDCRedirectURL() string {
+	if c.OIDC != nil && c.OIDC.RedirectURL != "" {
+		return c.OIDC.RedirectURL
+	}
+	return c.BindPlaneURL() + "/oidc/callback"
+}
+
+// AgentCAEnabled returns true if the built-in agent certificate authority is enabled
+func (c *Server) AgentCAEnabled() bool {
+	return c.AgentCA != nil && c.AgentCA.Enabled
+}
+
+// AgentCACertificatePath returns the path to the agent CA certificate
+func (c *Server) AgentCACertificatePath() string {
+	if c.AgentCA != nil && c.AgentCA.Certificate != "" {
+		return c.AgentCA.Certificate
+	}
+	return path.Join(c.BindPlaneHomePath(), AgentCACertificateName)
+}
+
+// AgentCAPrivateKeyPath returns the path to the agent CA private key
+func (c *Server) AgentCAPrivateKeyPath() string {
+	if c.AgentCA != nil && c.AgentCA.PrivateKey != "" {
+		return c.AgentCA.PrivateKey
+	}
+	return path.Join(c.BindPlaneHomePath(), AgentCAPrivateKeyName)
+}
+
+// AgentCertificateTTL returns the lifetime of issued agent certificates
+func (c *Server) AgentCertificateTTL() time.Duration {
+	if c.AgentCA != nil && c.AgentCA.CertificateTTL > 0 {
+		return c.AgentCA.CertificateTTL
+	}
+	return DefaultAgentCertificateTTL
+}
+
+// GitOpsConfig returns the GitOps configuration, creating it if it doesn't exist
+func (c *Server) GitOpsConfig() *GitOps {
+	if c.GitOps == nil {
+		c.GitOps = &GitOps{}
+	}
+	return c.GitOps
+}
+
+// GitOpsEnabled returns true if resources are synced from a git repository
+func (c *Server) GitOpsEnabled() bool {
+	return c.GitOps != nil && c.GitOps.Repository != ""
+}
+
+// GitOpsBranch returns the branch of the GitOps repository to sync
+func (c *Server) GitOpsBranch() string {
+	if c.GitOps != nil && c.GitOps.Branch != "" {
+		return c.GitOps.Branch
+	}
+	return DefaultGitOpsBranch
+}
+
+// GitOpsInterval returns the interval at which the GitOps repository is synced
+func (c *Server) GitOpsInterval() time.Duration {
+	if c.GitOps != nil && c.GitOps.Interval > 0 {
+		return c.GitOps.Interval
+	}
+	return DefaultGitOpsInterval
+}
+
+// GitOpsDirectory returns the path where the GitOps repository is cloned
+func (c *Server) GitOpsDirectory() string {
+	if c.GitOps != nil && c.GitOps.Directory != "" {
+		return c.GitOps.Directory
+	}
+	return path.Join(c.BindPlaneHomePath(), GitOpsDirectoryName)
+}
+
+// SessionTimeoutOrDefault returns the maximum lifetime of a user session
+func (c *Server) SessionTimeoutOrDefault() time.Duration {
+	if c.SessionTimeout > 0 {
+		return c.SessionTimeout
+	}
+	return DefaultSessionTimeout
+}
+
+// SessionIdleTimeoutOrDefault returns the time after which a user session without requests expires
+func (c *Server) SessionIdleTimeoutOrDefault() time.Duration {
+	if c.SessionIdleTimeout > 0 {
+		return c.SessionIdleTimeout
+	}
+	return DefaultSessionIdleTimeout
+}
+
 // WebsocketURL is the URL that should be used for agents connecting to the server
 func (c *Server) WebsocketURL() string {
 	if c.RemoteURL != "" {
@@ -252,6 +559,11 @@ func (c *Server) WebsocketURL() string {
 	return fmt.Sprintf("%s://%s:%s", c.WebsocketScheme(), c.Host, c.Port)
 }
 
+// LoadResourceTypes returns true if ResourceTypes should be loaded from directories or a catalog
+func (c *Server) LoadResourceTypes() bool {
+	return len(c.ResourceTypeDirs) > 0 || c.ResourceTypeCatalogURL != ""
+}
+
 // BoltDatabasePath returns the path to the bolt database file
 func (c *Server) BoltDatabasePath() string {
 	if c.StorageFilePath != "" {
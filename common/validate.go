@@ -19,7 +19,9 @@ import (
 	"fmt"
 	"net/url"
 	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/hashicorp/go-multierror"
@@ -59,6 +61,42 @@ func (s *Server) validate() (errGroup error) {
 		errGroup = multierror.Append(errGroup, err)
 	}
 
+	for _, dir := range s.ResourceTypeDirs {
+		if _, err := os.Stat(dir); err != nil {
+			err = fmt.Errorf("failed to lookup resource type directory %s: %w", dir, err)
+			errGroup = multierror.Append(errGroup, err)
+		}
+	}
+
+	if err := validateURL(s.ResourceTypeCatalogURL, []string{"http", "https"}); err != nil {
+		err = fmt.Errorf("failed to validate resource type catalog url %s: %w", s.ResourceTypeCatalogURL, err)
+		errGroup = multierror.Append(errGroup, err)
+	}
+
+	switch s.ResourceTypeConflicts {
+	case "", "external", "builtin":
+	default:
+		err := fmt.Errorf("invalid resource type conflicts %s: must be one of [external builtin]", s.ResourceTypeConflicts)
+		errGroup = multierror.Append(errGroup, err)
+	}
+
+	if s.SessionTimeout < 0 || s.SessionIdleTimeout < 0 {
+		err := errors.New("session timeouts must not be negative")
+		errGroup = multierror.Append(errGroup, err)
+	}
+
+	if err := s.validateOIDC(); err != nil {
+		errGroup = multierror.Append(errGroup, err)
+	}
+
+	if err := s.validateAgentCA(); err != nil {
+		errGroup = multierror.Append(errGroup, err)
+	}
+
+	if err := s.validateGitOps(); err != nil {
+		errGroup = multierror.Append(errGroup, err)
+	}
+
 	if err := s.Common.validate(); err != nil {
 		errGroup = multierror.Append(errGroup, err)
 	}
@@ -66,6 +104,89 @@ func (s *Server) validate() (errGroup error) {
 	return errGroup
 }
 
+func (s *Server) validateGitOps() error {
+	if s.GitOps == nil {
+		return nil
+	}
+	if s.GitOps.Interval < 0 {
+		return errors.New("gitops interval must not be negative")
+	}
+	if !s.GitOpsEnabled() {
+		return nil
+	}
+
+	// values starting with - would be parsed as options by git
+	if strings.HasPrefix(s.GitOps.Repository, "-") {
+		return fmt.Errorf("invalid gitops repository %s", s.GitOps.Repository)
+	}
+	if strings.HasPrefix(s.GitOps.Branch, "-") {
+		return fmt.Errorf("invalid gitops branch %s", s.GitOps.Branch)
+	}
+	if filepath.IsAbs(s.GitOps.Path) || strings.HasPrefix(filepath.Clean(s.GitOps.Path), "..") {
+		return fmt.Errorf("invalid gitops path %s: must be a relative path within the repository", s.GitOps.Path)
+	}
+	return nil
+}
+
+func (s *Server) validateOIDC() error {
+	if s.OIDC == nil {
+		return nil
+	}
+	if !s.OIDCEnabled() {
+		if s.OIDC.ClientID != "" {
+			return errors.New("oidc issuer must be set when oidc client id is set")
+		}
+		return nil
+	}
+
+	if err := validateURL(s.OIDC.Issuer, []string{"http", "https"}); err != nil {
+		return fmt.Errorf("failed to validate oidc issuer %s: %w", s.OIDC.Issuer, err)
+	}
+	if s.OIDC.ClientID == "" {
+		return errors.New("oidc client id must be set when oidc issuer is set")
+	}
+	if err := validateURL(s.OIDC.RedirectURL, []string{"http", "https"}); err != nil {
+		return fmt.Errorf("failed to validate oidc redirect url %s: %w", s.OIDC.RedirectURL, err)
+	}
+
+	// roles are defined by model.Role which can't be imported here
+	validRole := func(role string) bool {
+		return role == "admin" || role == "viewer"
+	}
+	for group, role := range s.OIDC.GroupRoles {
+		if !validRole(role) {
+			return fmt.Errorf("invalid oidc role %s for group %s: must be one of [admin viewer]", role, group)
+		}
+	}
+	if s.OIDC.DefaultRole != "" && !validRole(s.OIDC.DefaultRole) {
+		return fmt.Errorf("invalid oidc default role %s: must be one of [admin viewer]", s.OIDC.DefaultRole)
+	}
+	return nil
+}
+
+func (s *Server) validateAgentCA() error {
+	if !s.AgentCAEnabled() {
+		return nil
+	}
+
+	if !s.EnableTLS() {
+		return errors.New("tls certificate and private key must be set when the agent ca is enabled")
+	}
+
+	if s.AgentCA.CertificateTTL < 0 {
+		return fmt.Errorf("invalid agent certificate ttl %s: must not be negative", s.AgentCA.CertificateTTL)
+	}
+
+	// the certificate and key are generated together so either both or neither must exist
+	_, certErr := os.Stat(s.AgentCACertificatePath())
+	_, keyErr := os.Stat(s.AgentCAPrivateKeyPath())
+	if (certErr == nil) != (keyErr == nil) {
+		return fmt.Errorf("agent ca certificate %s and private key %s must both exist or both be missing", s.AgentCACertificatePath(), s.AgentCAPrivateKeyPath())
+	}
+
+	return nil
+}
+
 func (c *Client) validate() (errGroup error) {
 	return c.Common.validate()
 }
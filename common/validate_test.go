@@ -16,6 +16,7 @@ package common
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -459,6 +460,176 @@ func TestValidate(t *testing.T) {
 			},
 			"failed to lookup storage file path",
 		},
+		{
+			"valid-resource-types",
+			Config{
+				Server: Server{
+					ResourceTypeDirs:       []string{"./testdata"},
+					ResourceTypeCatalogURL: "https://example.com/catalog.yaml",
+					ResourceTypeConflicts:  "builtin",
+				},
+			},
+			"",
+		},
+		{
+			"invalid-resource-type-dir",
+			Config{
+				Server: Server{
+					ResourceTypeDirs: []string{"/invalid/resource/types"},
+				},
+			},
+			"failed to lookup resource type directory /invalid/resource/types",
+		},
+		{
+			"invalid-resource-type-catalog-url",
+			Config{
+				Server: Server{
+					ResourceTypeCatalogURL: "ws://example.com/catalog.yaml",
+				},
+			},
+			"failed to validate resource type catalog url ws://example.com/catalog.yaml: scheme ws is invalid: valid schemes are [http https]",
+		},
+		{
+			"invalid-resource-type-conflicts",
+			Config{
+				Server: Server{
+					ResourceTypeConflicts: "newest",
+				},
+			},
+			"invalid resource type conflicts newest: must be one of [external builtin]",
+		},
+		{
+			"valid-oidc",
+			Config{
+				Server: Server{
+					OIDC: &OIDC{
+						Issuer:      "https://accounts.example.com",
+						ClientID:    "bindplane",
+						GroupRoles:  map[string]string{"ops": "admin", "dev": "viewer"},
+						DefaultRole: "viewer",
+					},
+				},
+			},
+			"",
+		},
+		{
+			"oidc-missing-client-id",
+			Config{
+				Server: Server{
+					OIDC: &OIDC{
+						Issuer: "https://accounts.example.com",
+					},
+				},
+			},
+			"oidc client id must be set when oidc issuer is set",
+		},
+		{
+			"oidc-invalid-role",
+			Config{
+				Server: Server{
+					OIDC: &OIDC{
+						Issuer:     "https://accounts.example.com",
+						ClientID:   "bindplane",
+						GroupRoles: map[string]string{"ops": "owner"},
+					},
+				},
+			},
+			"invalid oidc role owner for group ops: must be one of [admin viewer]",
+		},
+		{
+			"negative-session-timeout",
+			Config{
+				Server: Server{
+					SessionIdleTimeout: -time.Minute,
+				},
+			},
+			"session timeouts must not be negative",
+		},
+		{
+			"valid-gitops",
+			Config{
+				Server: Server{
+					GitOps: &GitOps{
+						Repository: "https://github.com/example/bindplane-config.git",
+						Path:       "bindplane/prod",
+					},
+				},
+			},
+			"",
+		},
+		{
+			"invalid-gitops-branch",
+			Config{
+				Server: Server{
+					GitOps: &GitOps{
+						Repository: "https://github.com/example/bindplane-config.git",
+						Branch:     "--upload-pack=touch",
+					},
+				},
+			},
+			"invalid gitops branch --upload-pack=touch",
+		},
+		{
+			"invalid-gitops-path",
+			Config{
+				Server: Server{
+					GitOps: &GitOps{
+						Repository: "https://github.com/example/bindplane-config.git",
+						Path:       "../secrets",
+					},
+				},
+			},
+			"invalid gitops path ../secrets: must be a relative path within the repository",
+		},
+		{
+			"valid-agent-ca",
+			Config{
+				Server: Server{
+					AgentCA: &AgentCA{
+						Enabled:     true,
+						Certificate: "./testdata/tls/client.crt.test",
+						PrivateKey:  "./testdata/tls/client.key.test",
+					},
+					Common: Common{
+						TLSConfig: TLSConfig{
+							Certificate: "./testdata/tls/server.crt.test",
+							PrivateKey:  "./testdata/tls/server.key.test",
+						},
+					},
+				},
+			},
+			"",
+		},
+		{
+			"agent-ca-without-tls",
+			Config{
+				Server: Server{
+					AgentCA: &AgentCA{
+						Enabled: true,
+					},
+				},
+			},
+			"tls certificate and private key must be set when the agent ca is enabled",
+		},
+		{
+			"agent-ca-missing-private-key",
+			Config{
+				Server: Server{
+					AgentCA: &AgentCA{
+						Enabled:     true,
+						Certificate: "./testdata/tls/ca.crt.test",
+						PrivateKey:  "./testdata/tls/missing.key",
+					},
+					Common: Common{
+						TLSConfig: TLSConfig{
+							Certificate: "./testdata/tls/server.crt.test",
+							PrivateKey:  "./testdata/tls/server.key.test",
+						},
+					},
+				},
+			},
+			"agent ca certificate ./testdata/tls/ca.crt.test and private key ./testdata/tls/missing.key must both exist or both be missing",
+		},
 	}
 
 	for _, tc := range cases {
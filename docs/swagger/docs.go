@@ -30,12 +30,145 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/agent-deny-rules": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "List agent deny rules",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.AgentDenyRulesResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Denies agents with the specified id, hostname, or macAddress. Matching agents are removed and disconnected and cannot connect until the rule is deleted.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Create agent deny rule",
+                "parameters": [
+                    {
+                        "description": "field and value to deny",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.PostAgentDenyRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.AgentDenyRuleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/agent-deny-rules/{id}": {
+            "delete": {
+                "description": "Deletes the rule. Agents matching the rule can connect again.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Delete agent deny rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the id of the rule to delete, e.g. hostname:web-1",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Successful Delete, no content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/agent-versions": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "summary": "List agent versions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "label selector used to filter the results, e.g. env=prod",
+                        "name": "selector",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "search query used to filter the results",
+                        "name": "query",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "field used to sort the results, prefixed with - to sort descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of results to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of results to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "continue token returned with the previous page of results",
+                        "name": "continue",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -43,6 +176,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/model.AgentVersionsResponse"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -142,6 +281,12 @@ const docTemplate = `{
                         "name": "secret-key",
                         "in": "query"
                     },
+                    {
+                        "type": "string",
+                        "description": "enrollment token value, used instead of the secret key",
+                        "name": "enrollment-token",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "description": "http%3A%2F%2Flocalhost%3A3001",
@@ -215,6 +360,44 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "summary": "List agents",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "label selector used to filter the results, e.g. env=prod",
+                        "name": "selector",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "search query used to filter the results",
+                        "name": "query",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "field used to sort the results, prefixed with - to sort descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of results to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of results to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "continue token returned with the previous page of results",
+                        "name": "continue",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -222,6 +405,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/model.AgentsResponse"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -271,6 +460,46 @@ const docTemplate = `{
                 }
             }
         },
+        "/agents/approve": {
+            "post": {
+                "description": "Approves pending agents with the specified ids and pending agents matching the selector and query. Approved agents are sent their configuration.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Approve pending agents",
+                "parameters": [
+                    {
+                        "description": "ids, selector, and query of the agents to approve",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.PostApproveAgentsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ApproveAgentsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/agents/labels": {
             "patch": {
                 "produces": [
@@ -412,12 +641,13 @@ const docTemplate = `{
                 }
             }
         },
-        "/agents/{id}/labels": {
-            "get": {
+        "/agents/{id}/certificate/revoke": {
+            "put": {
+                "description": "Revokes the client certificates issued to the agent by the agent CA. If the agent is connected, it is sent a new credential and certificate.",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Get agent labels by agent id",
+                "summary": "Revoke agent certificate",
                 "parameters": [
                     {
                         "type": "string",
@@ -428,11 +658,8 @@ const docTemplate = `{
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/model.AgentLabelsResponse"
-                        }
+                    "202": {
+                        "description": "Certificate revoked"
                     },
                     "404": {
                         "description": "Not Found",
@@ -440,6 +667,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/rest.ErrorResponse"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -447,12 +680,15 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/agents/{id}/conflict/resolve": {
+            "put": {
+                "description": "Assigns a new ID to each host that connected with the ID of the agent while it was already connected, e.g. virtual machines cloned from an image that includes the agent configuration.",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Patch agent labels by agent id",
+                "summary": "Resolve agent conflict",
                 "parameters": [
                     {
                         "type": "string",
@@ -460,29 +696,11 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "if true, overwrite any existing labels with the same names",
-                        "name": "overwrite",
-                        "in": "query"
-                    },
-                    {
-                        "description": "Labels to be merged with existing labels, empty values will delete existing labels",
-                        "name": "labels",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.AgentLabelsPayload"
-                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/model.AgentLabelsResponse"
-                        }
+                    "202": {
+                        "description": "New IDs assigned"
                     },
                     "404": {
                         "description": "Not Found",
@@ -505,12 +723,13 @@ const docTemplate = `{
                 }
             }
         },
-        "/agents/{id}/restart": {
+        "/agents/{id}/credentials/rotate": {
             "put": {
+                "description": "Issues a new credential to an agent that accepts OpAMP connection settings. The previous credential is accepted until the agent reconnects with the new credential.",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "TODO restart agent",
+                "summary": "Rotate agent credential",
                 "parameters": [
                     {
                         "type": "string",
@@ -520,34 +739,16 @@ const docTemplate = `{
                         "required": true
                     }
                 ],
-                "responses": {}
-            }
-        },
-        "/agents/{id}/version": {
-            "post": {
-                "produces": [
-                    "application/json"
-                ],
-                "summary": "Upgrade agent",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "the id of the agent",
-                        "name": "name",
-                        "in": "path",
-                        "required": true
+                "responses": {
+                    "202": {
+                        "description": "Rotation requested"
                     },
-                    {
-                        "description": "request body containing version",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/model.PostAgentVersionRequest"
+                            "$ref": "#/definitions/rest.ErrorResponse"
                         }
-                    }
-                ],
-                "responses": {
+                    },
                     "409": {
                         "description": "Conflict",
                         "schema": {
@@ -563,10 +764,183 @@ const docTemplate = `{
                 }
             }
         },
-        "/apply": {
-            "post": {
-                "description": "The /apply route will try to parse resources\nand upsert them into the store.  Additionally\nit will send reconfigure tasks to affected agents.",
-                "produces": [
+        "/agents/{id}/labels": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get agent labels by agent id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the id of the agent",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.AgentLabelsResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Patch agent labels by agent id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the id of the agent",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "if true, overwrite any existing labels with the same names",
+                        "name": "overwrite",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Labels to be merged with existing labels, empty values will delete existing labels",
+                        "name": "labels",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.AgentLabelsPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.AgentLabelsResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/agents/{id}/restart": {
+            "put": {
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Restart agent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the id of the agent",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Restart requested"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/agents/{id}/version": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Upgrade agent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the id of the agent",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "request body containing version",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.PostAgentVersionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/apply": {
+            "post": {
+                "description": "The /apply route will try to parse resources\nand upsert them into the store.  Additionally\nit will send reconfigure tasks to affected agents.",
+                "produces": [
                     "application/json"
                 ],
                 "summary": "Create, edit, and configure multiple resources.",
@@ -597,6 +971,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/rest.ErrorResponse"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -612,6 +992,44 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "summary": "List Configurations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "label selector used to filter the results, e.g. env=prod",
+                        "name": "selector",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "search query used to filter the results",
+                        "name": "query",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "field used to sort the results, prefixed with - to sort descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of results to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of results to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "continue token returned with the previous page of results",
+                        "name": "continue",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -619,6 +1037,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/model.ConfigurationsResponse"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -673,11 +1097,544 @@ const docTemplate = `{
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "Successful Delete, no content"
+                    "204": {
+                        "description": "Successful Delete, no content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/configurations/{name}/copy": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Duplicate an existing configuration",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the name of the configuration to duplicate",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "the desired name of the duplicate configuration",
+                        "name": "name",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successful Copy, created"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/configurations/{name}/usage": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get configuration usage",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the name of the configuration",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ResourceUsageResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/delete": {
+            "post": {
+                "description": "/delete endpoint will try to parse resources\nand delete them from the store.  Additionally\nit will send reconfigure tasks to affected agents.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Delete multiple resources",
+                "parameters": [
+                    {
+                        "description": "Resources",
+                        "name": "resources",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.AnyResource"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.DeleteResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/destination-types": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "List destination types",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "label selector used to filter the results, e.g. env=prod",
+                        "name": "selector",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "search query used to filter the results",
+                        "name": "query",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "field used to sort the results, prefixed with - to sort descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of results to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of results to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "continue token returned with the previous page of results",
+                        "name": "continue",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.DestinationTypesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/destination-types/{name}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get destination type by name",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the name of the destination type",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.DestinationTypeResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Delete destination type by name",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the name of the destination type to delete",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Successful Delete, no content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/destination-types/{name}/usage": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get destination type usage",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the name of the destination type",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ResourceUsageResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/destinations": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "List destinations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "label selector used to filter the results, e.g. env=prod",
+                        "name": "selector",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "search query used to filter the results",
+                        "name": "query",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "field used to sort the results, prefixed with - to sort descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of results to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of results to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "continue token returned with the previous page of results",
+                        "name": "continue",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.DestinationsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/destinations/{name}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get destination by name",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the name of the destination",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.DestinationResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Delete destination by name",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the name of the destination to delete",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Successful Delete, no content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/destinations/{name}/usage": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get destination usage",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the name of the destination",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ResourceUsageResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/enrollment-tokens": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "List enrollment tokens",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.EnrollmentTokensResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates a token used by agents to enroll. The token value is only returned when the token is created.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Create enrollment token",
+                "parameters": [
+                    {
+                        "description": "labels, maximum uses, and expiration of the token",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.PostEnrollmentTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.EnrollmentTokenResponse"
+                        }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/rest.ErrorResponse"
                         }
@@ -691,33 +1648,25 @@ const docTemplate = `{
                 }
             }
         },
-        "/configurations/{name}/copy": {
-            "post": {
+        "/enrollment-tokens/{id}": {
+            "delete": {
+                "description": "Deletes the token. Agents that have not been issued a credential can no longer connect with the token.",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Duplicate an existing configuration",
+                "summary": "Delete enrollment token",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "the name of the configuration to duplicate",
-                        "name": "name",
+                        "description": "the id of the enrollment token to delete",
+                        "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "the desired name of the duplicate configuration",
-                        "name": "name",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "string"
-                        }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Successful Copy, created"
+                    "204": {
+                        "description": "Successful Delete, no content"
                     },
                     "404": {
                         "description": "Not Found",
@@ -725,12 +1674,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/rest.ErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
-                        "schema": {
-                            "$ref": "#/definitions/rest.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -740,32 +1683,18 @@ const docTemplate = `{
                 }
             }
         },
-        "/delete": {
-            "post": {
-                "description": "/delete endpoint will try to parse resources\nand delete them from the store.  Additionally\nit will send reconfigure tasks to affected agents.",
+        "/gitops/sync": {
+            "get": {
+                "description": "Returns the status of the most recent sync of resources from the GitOps repository.",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Delete multiple resources",
-                "parameters": [
-                    {
-                        "description": "Resources",
-                        "name": "resources",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.AnyResource"
-                            }
-                        }
-                    }
-                ],
+                "summary": "Get GitOps sync status",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.DeleteResponse"
+                            "$ref": "#/definitions/model.GitOpsSyncResponse"
                         }
                     },
                     "404": {
@@ -783,17 +1712,61 @@ const docTemplate = `{
                 }
             }
         },
-        "/destination-types": {
+        "/processor-types": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
-                "summary": "List destination types",
+                "summary": "List processor types",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "label selector used to filter the results, e.g. env=prod",
+                        "name": "selector",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "search query used to filter the results",
+                        "name": "query",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "field used to sort the results, prefixed with - to sort descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of results to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of results to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "continue token returned with the previous page of results",
+                        "name": "continue",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.DestinationTypesResponse"
+                            "$ref": "#/definitions/model.ProcessorTypesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
                         }
                     },
                     "500": {
@@ -805,16 +1778,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/destination-types/{name}": {
+        "/processor-types/{name}": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Get destination type by name",
+                "summary": "Get processor type by name",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "the name of the destination type",
+                        "description": "the name of the processor type",
                         "name": "name",
                         "in": "path",
                         "required": true
@@ -824,7 +1797,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.DestinationTypeResponse"
+                            "$ref": "#/definitions/model.ProcessorTypeResponse"
                         }
                     },
                     "401": {
@@ -845,11 +1818,11 @@ const docTemplate = `{
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Delete destination type by name",
+                "summary": "Delete processor type by name",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "the name of the destination type to delete",
+                        "description": "the name of the processor type to delete",
                         "name": "name",
                         "in": "path",
                         "required": true
@@ -874,17 +1847,32 @@ const docTemplate = `{
                 }
             }
         },
-        "/destinations": {
+        "/processor-types/{name}/usage": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
-                "summary": "List destinations",
+                "summary": "Get processor type usage",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the name of the processor type",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.DestinationsResponse"
+                            "$ref": "#/definitions/model.ResourceUsageResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
                         }
                     },
                     "500": {
@@ -896,30 +1884,59 @@ const docTemplate = `{
                 }
             }
         },
-        "/destinations/{name}": {
+        "/processors": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Get destination by name",
+                "summary": "List processors",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "the name of the destination",
-                        "name": "name",
-                        "in": "path",
-                        "required": true
+                        "description": "label selector used to filter the results, e.g. env=prod",
+                        "name": "selector",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "search query used to filter the results",
+                        "name": "query",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "field used to sort the results, prefixed with - to sort descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of results to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of results to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "continue token returned with the previous page of results",
+                        "name": "continue",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.DestinationResponse"
+                            "$ref": "#/definitions/model.ProcessorsResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/rest.ErrorResponse"
                         }
@@ -931,52 +1948,35 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/processors/{name}": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Delete destination by name",
+                "summary": "Get processor by name",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "the name of the destination to delete",
+                        "description": "the name of the processor",
                         "name": "name",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "Successful Delete, no content"
-                    },
-                    "404": {
-                        "description": "Not Found",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/rest.ErrorResponse"
+                            "$ref": "#/definitions/model.ProcessorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/rest.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/processor-types": {
-            "get": {
-                "produces": [
-                    "application/json"
-                ],
-                "summary": "List processor types",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/model.ProcessorTypesResponse"
-                        }
                     },
                     "500": {
                         "description": "Internal Server Error",
@@ -985,32 +1985,27 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/processor-types/{name}": {
-            "get": {
+            },
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Get processor type by name",
+                "summary": "Delete processor by name",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "the name of the processor type",
+                        "description": "the name of the processor to delete",
                         "name": "name",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/model.ProcessorTypeResponse"
-                        }
+                    "204": {
+                        "description": "Successful Delete, no content"
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/rest.ErrorResponse"
                         }
@@ -1022,24 +2017,29 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/processors/{name}/usage": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Delete processor type by name",
+                "summary": "Get processor usage",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "the name of the processor type to delete",
+                        "description": "the name of the processor",
                         "name": "name",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "Successful Delete, no content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ResourceUsageResponse"
+                        }
                     },
                     "404": {
                         "description": "Not Found",
@@ -1056,17 +2056,24 @@ const docTemplate = `{
                 }
             }
         },
-        "/processors": {
+        "/sessions": {
             "get": {
+                "description": "Lists the sessions of users logged in to the UI. Requires the admin role.",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "List processors",
+                "summary": "List user sessions",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.ProcessorsResponse"
+                            "$ref": "#/definitions/model.UserSessionsResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
                         }
                     },
                     "500": {
@@ -1078,30 +2085,39 @@ const docTemplate = `{
                 }
             }
         },
-        "/processors/{name}": {
-            "get": {
+        "/sessions/revoke": {
+            "post": {
+                "description": "Revokes every session of the user. Requires the admin role.",
                 "produces": [
-                    "application/json"
-                ],
-                "summary": "Get processor by name",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "the name of the processor",
-                        "name": "name",
-                        "in": "path",
-                        "required": true
+                    "application/json"
+                ],
+                "summary": "Revoke the sessions of a user",
+                "parameters": [
+                    {
+                        "description": "user whose sessions are revoked",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.PostRevokeUserSessionsRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.ProcessorResponse"
+                            "$ref": "#/definitions/model.RevokeUserSessionsResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/rest.ErrorResponse"
                         }
@@ -1113,17 +2129,20 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/sessions/{id}": {
             "delete": {
+                "description": "Revokes the session. The user must log in again. Requires the admin role.",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Delete processor by name",
+                "summary": "Revoke user session",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "the name of the processor to delete",
-                        "name": "name",
+                        "description": "the id of the session to revoke",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -1132,6 +2151,12 @@ const docTemplate = `{
                     "204": {
                         "description": "Successful Delete, no content"
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -1153,6 +2178,44 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "summary": "List source types",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "label selector used to filter the results, e.g. env=prod",
+                        "name": "selector",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "search query used to filter the results",
+                        "name": "query",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "field used to sort the results, prefixed with - to sort descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of results to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of results to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "continue token returned with the previous page of results",
+                        "name": "continue",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -1160,6 +2223,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/model.SourceTypesResponse"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1238,12 +2307,87 @@ const docTemplate = `{
                 }
             }
         },
+        "/source-types/{name}/usage": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get source type usage",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the name of the source type",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ResourceUsageResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/sources": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "summary": "List sources",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "label selector used to filter the results, e.g. env=prod",
+                        "name": "selector",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "search query used to filter the results",
+                        "name": "query",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "field used to sort the results, prefixed with - to sort descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of results to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of results to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "continue token returned with the previous page of results",
+                        "name": "continue",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -1251,6 +2395,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/model.SourcesResponse"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1329,6 +2479,43 @@ const docTemplate = `{
                 }
             }
         },
+        "/sources/{name}/usage": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get source usage",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the name of the source",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ResourceUsageResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/version": {
             "get": {
                 "description": "Returns the current bindplane version of the server.",
@@ -1345,6 +2532,53 @@ const docTemplate = `{
                     }
                 }
             }
+        },
+        "/watch": {
+            "get": {
+                "description": "Upgrades to a websocket that sends a JSON array of events each time resources of the kind change.\nUpdates to resources that no longer match the selector are sent as removes.\nAn empty array is sent once the watch has started so that clients can list resources without missing changes.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Watch resources",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the kind of resources to watch, e.g. Agent or Configuration",
+                        "name": "kind",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "the name of the resource to watch, or the ID of an agent",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "label selector to filter resources, e.g. app=foo",
+                        "name": "selector",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.WatchEvent"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
         }
     },
     "definitions": {
@@ -1357,6 +2591,10 @@ const docTemplate = `{
                 "configuration": {
                     "description": "tracked by BindPlane"
                 },
+                "conflict": {
+                    "description": "Conflict describes another host that connected with the ID of this agent",
+                    "$ref": "#/definitions/model.AgentConflict"
+                },
                 "connectedAt": {
                     "type": "string"
                 },
@@ -1414,6 +2652,70 @@ const docTemplate = `{
                 }
             }
         },
+        "model.AgentConflict": {
+            "type": "object",
+            "properties": {
+                "detectedAt": {
+                    "type": "string"
+                },
+                "hostname": {
+                    "type": "string"
+                },
+                "macAddress": {
+                    "type": "string"
+                },
+                "nodeID": {
+                    "description": "NodeID is the ID of the BindPlane node connected to the conflicting host",
+                    "type": "string"
+                },
+                "remoteAddress": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.AgentDenyRule": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "field": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.AgentDenyRuleResponse": {
+            "type": "object",
+            "properties": {
+                "disconnected": {
+                    "description": "Disconnected are the IDs of the agents that were disconnected because they match the rule",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "rule": {
+                    "$ref": "#/definitions/model.AgentDenyRule"
+                }
+            }
+        },
+        "model.AgentDenyRulesResponse": {
+            "type": "object",
+            "properties": {
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.AgentDenyRule"
+                    }
+                }
+            }
+        },
         "model.AgentDownload": {
             "type": "object",
             "properties": {
@@ -1563,6 +2865,10 @@ const docTemplate = `{
                     "items": {
                         "$ref": "#/definitions/model.AgentVersion"
                     }
+                },
+                "continue": {
+                    "description": "Continue is the token used to request the next page of results. It is empty if there are no more results.",
+                    "type": "string"
                 }
             }
         },
@@ -1574,6 +2880,10 @@ const docTemplate = `{
                     "items": {
                         "$ref": "#/definitions/model.Agent"
                     }
+                },
+                "continue": {
+                    "description": "Continue is the token used to request the next page of results. It is empty if there are no more results.",
+                    "type": "string"
                 }
             }
         },
@@ -1598,10 +2908,22 @@ const docTemplate = `{
         "model.ApplyResponse": {
             "type": "object",
             "properties": {
-                "updates": {
+                "updates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ResourceStatus"
+                    }
+                }
+            }
+        },
+        "model.ApproveAgentsResponse": {
+            "type": "object",
+            "properties": {
+                "approved": {
+                    "description": "Approved are the IDs of the agents that were approved",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/model.ResourceStatus"
+                        "type": "string"
                     }
                 }
             }
@@ -1680,6 +3002,10 @@ const docTemplate = `{
                     "items": {
                         "$ref": "#/definitions/model.Configuration"
                     }
+                },
+                "continue": {
+                    "description": "Continue is the token used to request the next page of results. It is empty if there are no more results.",
+                    "type": "string"
                 }
             }
         },
@@ -1765,6 +3091,10 @@ const docTemplate = `{
         "model.DestinationTypesResponse": {
             "type": "object",
             "properties": {
+                "continue": {
+                    "description": "Continue is the token used to request the next page of results. It is empty if there are no more results.",
+                    "type": "string"
+                },
                 "destinationTypes": {
                     "type": "array",
                     "items": {
@@ -1776,6 +3106,10 @@ const docTemplate = `{
         "model.DestinationsResponse": {
             "type": "object",
             "properties": {
+                "continue": {
+                    "description": "Continue is the token used to request the next page of results. It is empty if there are no more results.",
+                    "type": "string"
+                },
                 "destinations": {
                     "type": "array",
                     "items": {
@@ -1795,6 +3129,123 @@ const docTemplate = `{
                 }
             }
         },
+        "model.EnrollmentToken": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "type": "string"
+                },
+                "hash": {
+                    "description": "Hash is the hex-encoded SHA-256 hash of the token secret",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "labels": {
+                    "$ref": "#/definitions/model.Labels"
+                },
+                "maxUses": {
+                    "description": "MaxUses is the maximum number of agents that can enroll with the token. 0 is unlimited.",
+                    "type": "integer"
+                },
+                "uses": {
+                    "description": "Uses is the number of agents that have enrolled with the token",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.EnrollmentTokenResponse": {
+            "type": "object",
+            "properties": {
+                "enrollmentToken": {
+                    "$ref": "#/definitions/model.EnrollmentToken"
+                },
+                "token": {
+                    "description": "Token is the value given to agents to enroll. It is only available when the token is created.",
+                    "type": "string"
+                }
+            }
+        },
+        "model.EnrollmentTokensResponse": {
+            "type": "object",
+            "properties": {
+                "enrollmentTokens": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.EnrollmentToken"
+                    }
+                }
+            }
+        },
+        "model.GitOpsChange": {
+            "type": "object",
+            "properties": {
+                "kind": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "reason": {
+                    "description": "Reason will be set if status is invalid or error",
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.GitOpsSyncResponse": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "$ref": "#/definitions/model.GitOpsSyncStatus"
+                }
+            }
+        },
+        "model.GitOpsSyncStatus": {
+            "type": "object",
+            "properties": {
+                "branch": {
+                    "type": "string"
+                },
+                "changes": {
+                    "description": "Changes are the resources that were created, configured, or deleted by the most recent sync. Unchanged resources\nare not included.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.GitOpsChange"
+                    }
+                },
+                "commit": {
+                    "description": "Commit is the commit of the repository that was synced",
+                    "type": "string"
+                },
+                "errors": {
+                    "description": "Errors are the errors of the most recent sync, e.g. the repository could not be pulled or a resource is invalid",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "path": {
+                    "type": "string"
+                },
+                "prune": {
+                    "type": "boolean"
+                },
+                "repository": {
+                    "type": "string"
+                },
+                "syncedAt": {
+                    "description": "SyncedAt is the time of the most recent sync, successful or not",
+                    "type": "string"
+                }
+            }
+        },
         "model.InstallCommandResponse": {
             "type": "object",
             "properties": {
@@ -1939,6 +3390,22 @@ const docTemplate = `{
                 }
             }
         },
+        "model.PostAgentDenyRuleRequest": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "description": "Field is the agent field to match, one of id, hostname, or macAddress",
+                    "type": "string"
+                },
+                "reason": {
+                    "description": "Reason is an optional description of why the agent is denied",
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
         "model.PostAgentVersionRequest": {
             "type": "object",
             "properties": {
@@ -1947,6 +3414,54 @@ const docTemplate = `{
                 }
             }
         },
+        "model.PostApproveAgentsRequest": {
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "query": {
+                    "description": "Query is a search query, e.g. hostname:web-1",
+                    "type": "string"
+                },
+                "selector": {
+                    "description": "Selector is a label selector, e.g. env=prod",
+                    "type": "string"
+                }
+            }
+        },
+        "model.PostEnrollmentTokenRequest": {
+            "type": "object",
+            "properties": {
+                "expiresIn": {
+                    "description": "ExpiresIn is the duration until the token expires, e.g. 24h. If empty, the token does not expire.",
+                    "type": "string"
+                },
+                "labels": {
+                    "description": "Labels are applied to every agent enrolled with the token",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "maxUses": {
+                    "description": "MaxUses is the maximum number of agents that can enroll with the token. 0 is unlimited.",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.PostRevokeUserSessionsRequest": {
+            "type": "object",
+            "properties": {
+                "user": {
+                    "description": "User is the user whose sessions are revoked",
+                    "type": "string"
+                }
+            }
+        },
         "model.Processor": {
             "type": "object",
             "properties": {
@@ -2001,6 +3516,10 @@ const docTemplate = `{
         "model.ProcessorTypesResponse": {
             "type": "object",
             "properties": {
+                "continue": {
+                    "description": "Continue is the token used to request the next page of results. It is empty if there are no more results.",
+                    "type": "string"
+                },
                 "processorTypes": {
                     "type": "array",
                     "items": {
@@ -2012,6 +3531,10 @@ const docTemplate = `{
         "model.ProcessorsResponse": {
             "type": "object",
             "properties": {
+                "continue": {
+                    "description": "Continue is the token used to request the next page of results. It is empty if there are no more results.",
+                    "type": "string"
+                },
                 "processors": {
                     "type": "array",
                     "items": {
@@ -2133,6 +3656,67 @@ const docTemplate = `{
                 }
             }
         },
+        "model.ResourceUsage": {
+            "type": "object",
+            "properties": {
+                "agents": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "configurations": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "destinations": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "processors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "sources": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.ResourceUsageResponse": {
+            "type": "object",
+            "properties": {
+                "usage": {
+                    "$ref": "#/definitions/model.ResourceUsage"
+                }
+            }
+        },
+        "model.RevokeUserSessionsResponse": {
+            "type": "object",
+            "properties": {
+                "revoked": {
+                    "description": "Revoked are the IDs of the sessions that were revoked",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
         "model.Source": {
             "type": "object",
             "properties": {
@@ -2187,6 +3771,10 @@ const docTemplate = `{
         "model.SourceTypesResponse": {
             "type": "object",
             "properties": {
+                "continue": {
+                    "description": "Continue is the token used to request the next page of results. It is empty if there are no more results.",
+                    "type": "string"
+                },
                 "sourceTypes": {
                     "type": "array",
                     "items": {
@@ -2198,6 +3786,10 @@ const docTemplate = `{
         "model.SourcesResponse": {
             "type": "object",
             "properties": {
+                "continue": {
+                    "description": "Continue is the token used to request the next page of results. It is empty if there are no more results.",
+                    "type": "string"
+                },
                 "sources": {
                     "type": "array",
                     "items": {
@@ -2206,6 +3798,77 @@ const docTemplate = `{
                 }
             }
         },
+        "model.UserSession": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "csrfHash": {
+                    "description": "CSRFHash is the hex-encoded SHA-256 hash of the CSRF token that must accompany requests that modify resources",
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "description": "ExpiresAt is the time at which the session expires regardless of activity",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "idleTimeout": {
+                    "description": "IdleTimeout is the time after LastSeenAt at which the session expires",
+                    "type": "integer"
+                },
+                "lastSeenAt": {
+                    "type": "string"
+                },
+                "remoteAddress": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "user": {
+                    "type": "string"
+                },
+                "userAgent": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.UserSessionsResponse": {
+            "type": "object",
+            "properties": {
+                "sessions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.UserSession"
+                    }
+                }
+            }
+        },
+        "model.WatchEvent": {
+            "type": "object",
+            "properties": {
+                "kind": {
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name is the unique key of the resource, which is the ID of an Agent and the name of other resources",
+                    "type": "string"
+                },
+                "resource": {
+                    "description": "Resource is the JSON of the resource. Agents are sent in the format of GET /v1/agents/{id} and other resources in\nthe format of apply.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
         "rest.ErrorResponse": {
             "type": "object",
             "properties": {
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/observiq/bindplane-op/internal/cluster"
 	"github.com/observiq/bindplane-op/internal/eventbus"
 	"github.com/observiq/bindplane-op/internal/store"
 	"github.com/observiq/bindplane-op/internal/util"
@@ -53,6 +54,10 @@ type VersionsSettings struct {
 	// Offline is true if the server is in offline mode and should not contact GitHub automatically. Sync methods called
 	// by 'bindplanectl sync' commands will still attempt to contact GitHub.
 	Offline bool
+
+	// Leadership determines if this node periodically syncs agent versions. In a cluster, only the leader syncs agent
+	// versions. If nil, this node always syncs.
+	Leadership cluster.Leadership
 }
 
 // The latest version cache keeps the latest version in memory to avoid hitting the store to get the latest version.
@@ -68,6 +73,7 @@ type versions struct {
 	client        Client
 	store         store.Store
 	latestVersion util.Remember[model.AgentVersion]
+	leadership    cluster.Leadership
 	logger        *zap.Logger
 }
 
@@ -80,6 +86,7 @@ func NewVersions(ctx context.Context, client Client, store store.Store, settings
 		client:        client,
 		store:         store,
 		latestVersion: util.NewRemember[model.AgentVersion](latestVersionCacheDuration),
+		leadership:    settings.Leadership,
 		logger:        settings.Logger,
 	}
 	if settings.SyncAgentVersionsInterval > 0 && !settings.Offline {
@@ -183,6 +190,11 @@ func (v *versions) syncAgentVersions(ctx context.Context, interval time.Duration
 }
 
 func (v *versions) syncAgentVersionsOnce() {
+	// only the leader syncs agent versions so that nodes don't race to apply them
+	if v.leadership != nil && !v.leadership.IsLeader() {
+		return
+	}
+
 	agentVersions, err := v.SyncVersions()
 	if err != nil {
 		v.logger.Error("error during syncAgentVersions SyncVersions", zap.Error(err))
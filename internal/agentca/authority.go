@@ -0,0 +1,271 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package agentca provides the built-in certificate authority that issues client certificates to agents
+package agentca
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// caValidity is the lifetime of a generated CA certificate
+	caValidity = 10 * 365 * 24 * time.Hour
+
+	// clockSkew is subtracted from NotBefore so that certificates are valid on hosts with slightly slow clocks
+	clockSkew = 5 * time.Minute
+
+	caCommonName = "BindPlane Agent CA"
+)
+
+// ErrInvalidCertificate is returned by Verify when a certificate was not issued by the Authority or is not valid
+var ErrInvalidCertificate = errors.New("invalid agent certificate")
+
+// Authority issues and verifies agent client certificates
+type Authority struct {
+	certificate    *x509.Certificate
+	certificatePEM []byte
+	privateKey     *ecdsa.PrivateKey
+	ttl            time.Duration
+	pool           *x509.CertPool
+}
+
+// Certificate is a client certificate issued to an agent
+type Certificate struct {
+	// CertificatePEM is the PEM encoded client certificate
+	CertificatePEM []byte
+
+	// PrivateKeyPEM is the PEM encoded private key for the client certificate
+	PrivateKeyPEM []byte
+
+	// CAPEM is the PEM encoded certificate of the Authority that issued the client certificate
+	CAPEM []byte
+
+	// Serial is the serial number of the client certificate in hex
+	Serial string
+
+	// ExpiresAt is the time at which the client certificate expires
+	ExpiresAt time.Time
+}
+
+// Load reads the CA certificate and private key from the specified files. If neither file exists, a new CA is
+// generated and written to them.
+func Load(certFile, keyFile string, ttl time.Duration) (*Authority, error) {
+	_, certErr := os.Stat(certFile)
+	_, keyErr := os.Stat(keyFile)
+	if errors.Is(certErr, os.ErrNotExist) && errors.Is(keyErr, os.ErrNotExist) {
+		if err := generate(certFile, keyFile); err != nil {
+			return nil, fmt.Errorf("failed to generate agent ca: %w", err)
+		}
+	}
+
+	certPEM, err := os.ReadFile(filepath.Clean(certFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read agent ca certificate: %w", err)
+	}
+	keyPEM, err := os.ReadFile(filepath.Clean(keyFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read agent ca private key: %w", err)
+	}
+	return New(certPEM, keyPEM, ttl)
+}
+
+// New returns an Authority using the PEM encoded CA certificate and private key
+func New(certPEM, keyPEM []byte, ttl time.Duration) (*Authority, error) {
+	certBlock, _ := pem.Decode(certPEM)
+	if certBlock == nil {
+		return nil, errors.New("failed to decode agent ca certificate")
+	}
+	certificate, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse agent ca certificate: %w", err)
+	}
+	if !certificate.IsCA {
+		return nil, errors.New("agent ca certificate is not a certificate authority")
+	}
+
+	keyBlock, _ := pem.Decode(keyPEM)
+	if keyBlock == nil {
+		return nil, errors.New("failed to decode agent ca private key")
+	}
+	privateKey, err := x509.ParseECPrivateKey(keyBlock.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse agent ca private key: %w", err)
+	}
+	if !privateKey.PublicKey.Equal(certificate.PublicKey) {
+		return nil, errors.New("agent ca private key does not match certificate")
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(certificate)
+
+	return &Authority{
+		certificate:    certificate,
+		certificatePEM: certPEM,
+		privateKey:     privateKey,
+		ttl:            ttl,
+		pool:           pool,
+	}, nil
+}
+
+// CertPool returns a pool containing the CA certificate, used to verify client certificates during the TLS handshake
+func (a *Authority) CertPool() *x509.CertPool {
+	return a.pool
+}
+
+// CertificatePEM returns the PEM encoded CA certificate
+func (a *Authority) CertificatePEM() []byte {
+	return a.certificatePEM
+}
+
+// TTL returns the lifetime of issued certificates
+func (a *Authority) TTL() time.Duration {
+	return a.ttl
+}
+
+// Issue creates a client certificate for the agent. The subject common name of the certificate is the agent ID.
+func (a *Authority) Issue(agentID string) (*Certificate, error) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate private key: %w", err)
+	}
+
+	serial, err := newSerial()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: agentID},
+		NotBefore:    now.Add(-clockSkew),
+		NotAfter:     now.Add(a.ttl),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, a.certificate, &privateKey.PublicKey, a.privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create certificate: %w", err)
+	}
+
+	keyPEM, err := encodePrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Certificate{
+		CertificatePEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		PrivateKeyPEM:  keyPEM,
+		CAPEM:          a.certificatePEM,
+		Serial:         Serial(template),
+		ExpiresAt:      template.NotAfter,
+	}, nil
+}
+
+// Issued returns true if the certificate is signed by the CA certificate. It does not check that the certificate is
+// valid.
+func (a *Authority) Issued(certificate *x509.Certificate) bool {
+	return certificate.CheckSignatureFrom(a.certificate) == nil
+}
+
+// Verify returns ErrInvalidCertificate if the certificate does not chain to the CA certificate or has expired
+func (a *Authority) Verify(certificate *x509.Certificate) error {
+	_, err := certificate.Verify(x509.VerifyOptions{
+		Roots:     a.pool,
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidCertificate, err)
+	}
+	return nil
+}
+
+// Serial returns the serial number of the certificate in hex
+func Serial(certificate *x509.Certificate) string {
+	return certificate.SerialNumber.Text(16)
+}
+
+// ----------------------------------------------------------------------
+
+func generate(certFile, keyFile string) error {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return fmt.Errorf("failed to generate private key: %w", err)
+	}
+
+	serial, err := newSerial()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: caCommonName},
+		NotBefore:             now.Add(-clockSkew),
+		NotAfter:              now.Add(caValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	if err != nil {
+		return fmt.Errorf("failed to create certificate: %w", err)
+	}
+
+	keyPEM, err := encodePrivateKey(privateKey)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(keyFile, keyPEM, 0600); err != nil {
+		return fmt.Errorf("failed to write private key: %w", err)
+	}
+	// #nosec G306 - the CA certificate is public
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644); err != nil {
+		return fmt.Errorf("failed to write certificate: %w", err)
+	}
+	return nil
+}
+
+func newSerial() (*big.Int, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate serial number: %w", err)
+	}
+	return serial, nil
+}
+
+func encodePrivateKey(privateKey *ecdsa.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalECPrivateKey(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal private key: %w", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), nil
+}
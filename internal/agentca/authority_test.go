@@ -0,0 +1,140 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agentca
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "agent-ca.crt")
+	keyFile := filepath.Join(dir, "agent-ca.key")
+
+	// generated when missing
+	authority, err := Load(certFile, keyFile, time.Hour)
+	require.NoError(t, err)
+	require.FileExists(t, certFile)
+	require.FileExists(t, keyFile)
+
+	info, err := os.Stat(keyFile)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	// loaded when present
+	loaded, err := Load(certFile, keyFile, time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, authority.CertificatePEM(), loaded.CertificatePEM())
+
+	// not generated when only one file is missing
+	require.NoError(t, os.Remove(keyFile))
+	_, err = Load(certFile, keyFile, time.Hour)
+	require.ErrorContains(t, err, "failed to read agent ca private key")
+}
+
+func TestNew(t *testing.T) {
+	dir := t.TempDir()
+	authority, err := Load(filepath.Join(dir, "ca.crt"), filepath.Join(dir, "ca.key"), time.Hour)
+	require.NoError(t, err)
+	_, err = Load(filepath.Join(dir, "other.crt"), filepath.Join(dir, "other.key"), time.Hour)
+	require.NoError(t, err)
+
+	issued, err := authority.Issue("agent-1")
+	require.NoError(t, err)
+
+	keyPEM, err := os.ReadFile(filepath.Join(dir, "ca.key"))
+	require.NoError(t, err)
+	otherKeyPEM, err := os.ReadFile(filepath.Join(dir, "other.key"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		certPEM     []byte
+		keyPEM      []byte
+		expectError string
+	}{
+		{
+			name:    "valid",
+			certPEM: authority.CertificatePEM(),
+			keyPEM:  keyPEM,
+		},
+		{
+			name:        "invalid certificate",
+			certPEM:     []byte("not a certificate"),
+			keyPEM:      keyPEM,
+			expectError: "failed to decode agent ca certificate",
+		},
+		{
+			name:        "not a ca",
+			certPEM:     issued.CertificatePEM,
+			keyPEM:      issued.PrivateKeyPEM,
+			expectError: "agent ca certificate is not a certificate authority",
+		},
+		{
+			name:        "mismatched key",
+			certPEM:     authority.CertificatePEM(),
+			keyPEM:      otherKeyPEM,
+			expectError: "agent ca private key does not match certificate",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := New(test.certPEM, test.keyPEM, time.Hour)
+			if test.expectError != "" {
+				require.ErrorContains(t, err, test.expectError)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestIssueAndVerify(t *testing.T) {
+	dir := t.TempDir()
+	authority, err := Load(filepath.Join(dir, "ca.crt"), filepath.Join(dir, "ca.key"), time.Hour)
+	require.NoError(t, err)
+	other, err := Load(filepath.Join(dir, "other.crt"), filepath.Join(dir, "other.key"), time.Hour)
+	require.NoError(t, err)
+
+	issued, err := authority.Issue("agent-1")
+	require.NoError(t, err)
+	require.Equal(t, authority.CertificatePEM(), issued.CAPEM)
+	require.WithinDuration(t, time.Now().Add(time.Hour), issued.ExpiresAt, time.Minute)
+
+	block, _ := pem.Decode(issued.CertificatePEM)
+	require.NotNil(t, block)
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+	require.Equal(t, "agent-1", certificate.Subject.CommonName)
+	require.Equal(t, issued.Serial, Serial(certificate))
+
+	require.True(t, authority.Issued(certificate))
+	require.False(t, other.Issued(certificate))
+	require.NoError(t, authority.Verify(certificate))
+	require.ErrorIs(t, other.Verify(certificate), ErrInvalidCertificate)
+
+	// each certificate has a unique serial
+	again, err := authority.Issue("agent-1")
+	require.NoError(t, err)
+	require.NotEqual(t, issued.Serial, again.Serial)
+}
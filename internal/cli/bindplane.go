@@ -122,21 +122,27 @@ func (i *BindPlane) Logger() *zap.Logger {
 func (i *BindPlane) Printer() printer.Printer {
 	i.initPrinter.Do(func() {
 		if i.printer == nil {
-			switch i.Config.Output {
-			case "json":
-				i.printer = printer.NewJSONPrinter(i.writer, i.Logger())
-			case "yaml":
-				i.printer = printer.NewYamlPrinter(i.writer, i.Logger())
-			case "table":
-				fallthrough
-			default:
-				i.printer = printer.NewTablePrinter(i.writer)
+			p, err := printer.NewPrinter(i.writer, i.Logger(), i.PrinterOptions())
+			if err != nil {
+				// the options are validated by the root command before other commands run
+				i.Logger().Error("invalid output options, printing a table", zap.Error(err))
+				p = printer.NewTablePrinter(i.writer)
 			}
+			i.printer = p
 		}
 	})
 	return i.printer
 }
 
+// PrinterOptions returns the options used to create the printer
+func (i *BindPlane) PrinterOptions() printer.Options {
+	return printer.Options{
+		Output:    i.Config.Output,
+		SortBy:    i.Config.SortBy,
+		NoHeaders: i.Config.NoHeaders,
+	}
+}
+
 // ShutdownHook is called at shutdown when added using AddShutdownHook
 type ShutdownHook func()
 
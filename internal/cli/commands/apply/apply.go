@@ -15,11 +15,19 @@
 package apply
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/spf13/cobra"
 
+	"github.com/observiq/bindplane-op/client"
 	"github.com/observiq/bindplane-op/internal/cli"
 	"github.com/observiq/bindplane-op/model"
 )
@@ -27,11 +35,28 @@ import (
 // Command returns the bindplane apply cobra command.
 func Command(bindplane *cli.BindPlane) *cobra.Command {
 	var fileFlag []string
+	var recursiveFlag bool
+	var pruneFlag bool
+	var selectorFlag string
+	var dryRunFlag bool
 
 	cmd := &cobra.Command{
 		Use:   "apply [file]",
 		Short: "Apply resources",
-		Long:  `Apply resources from a file with a filepath or use 'bindplane apply -' to apply resources from stdin.`,
+		Long: `Apply resources from a file with a filepath or use 'bindplane apply -' to apply resources from stdin.
+
+The yaml files in a directory are applied when a directory is specified. Use -R to apply the files in its
+subdirectories as well.
+
+Use --prune with a label selector to delete resources that were previously applied with the same selector but are no
+longer in the applied files. The labels of the selector are added to each applied resource to mark it as owned by the
+apply. Resources that don't have the labels are never pruned.
+
+Use --dry-run to validate the resources and print what would be applied and pruned. Resources are validated without
+the server, so the parameters of sources, processors, and destinations are not checked against their types.`,
+		Example: `  bindplanectl apply -f resources.yaml
+  bindplanectl apply -R -f ./bindplane --prune -l app=foo
+  bindplanectl apply -R -f ./bindplane --prune -l app=foo --dry-run`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			c, err := bindplane.Client()
 			if err != nil {
@@ -49,12 +74,20 @@ func Command(bindplane *cli.BindPlane) *cobra.Command {
 				return nil
 			}
 
+			owner, err := ownerLabels(selectorFlag)
+			if err != nil {
+				return err
+			}
+			if pruneFlag && len(owner.Set) == 0 {
+				return errors.New("--prune requires a label selector to identify the resources to prune, e.g. -l app=foo")
+			}
+
 			var errs error
 			var resources []*model.AnyResource
 
 			// read all of the files
 			for _, fileArg := range fileArgs {
-				fileResources, err := readResources(cmd, fileArg)
+				fileResources, err := readResources(cmd, fileArg, recursiveFlag)
 				if err != nil {
 					errs = multierror.Append(errs, err)
 					continue
@@ -67,25 +100,225 @@ func Command(bindplane *cli.BindPlane) *cobra.Command {
 				return errs
 			}
 
+			for _, resource := range resources {
+				if err := addOwnerLabels(resource, owner); err != nil {
+					errs = multierror.Append(errs, err)
+				}
+			}
+			if errs != nil {
+				return errs
+			}
+
+			var prune []*model.AnyResource
+			if pruneFlag {
+				prune, err = pruneResources(cmd.Context(), c, owner, resources)
+				if err != nil {
+					return err
+				}
+			}
+
+			if dryRunFlag {
+				if err := validateResources(resources); err != nil {
+					return err
+				}
+				for _, resource := range resources {
+					fmt.Fprintf(cmd.OutOrStdout(), "%s %s applied (dry run)\n", resource.Kind, resource.Name())
+				}
+				for _, resource := range prune {
+					fmt.Fprintf(cmd.OutOrStdout(), "%s %s pruned (dry run)\n", resource.Kind, resource.Name())
+				}
+				return nil
+			}
+
 			// apply them all together
 			resourceStatuses, err := c.Apply(cmd.Context(), resources)
 			if err != nil {
 				return err
 			}
 
+			model.PrintResourceUpdates(cmd.OutOrStdout(), resourceStatuses)
+
+			if len(prune) == 0 {
+				return nil
+			}
+
+			resourceStatuses, err = c.Delete(cmd.Context(), prune)
+			if err != nil {
+				return fmt.Errorf("failed to prune resources: %w", err)
+			}
+
 			model.PrintResourceUpdates(cmd.OutOrStdout(), resourceStatuses)
 			return nil
 		},
 	}
 
-	cmd.Flags().StringSliceVarP(&fileFlag, "file", "f", []string{}, "path to a yaml file that specifies bindplane resources")
+	cmd.Flags().StringSliceVarP(&fileFlag, "file", "f", []string{}, "path to a yaml file or directory that specifies bindplane resources")
+	cmd.Flags().BoolVarP(&recursiveFlag, "recursive", "R", false, "apply the yaml files in subdirectories of directories specified with -f")
+	cmd.Flags().BoolVar(&pruneFlag, "prune", false, "delete resources with the labels of --selector that are not in the applied files")
+	cmd.Flags().StringVarP(&selectorFlag, "selector", "l", "", "labels that identify the resources owned by this apply, e.g. app=foo, added to each applied resource")
+	cmd.Flags().BoolVar(&dryRunFlag, "dry-run", false, "validate and print the resources that would be applied and pruned without changing them")
 
 	return cmd
 }
 
-func readResources(cmd *cobra.Command, fileArg string) ([]*model.AnyResource, error) {
+func readResources(cmd *cobra.Command, fileArg string, recursive bool) ([]*model.AnyResource, error) {
 	if fileArg == "-" {
 		return model.ResourcesFromReader(cmd.InOrStdin())
 	}
-	return model.ResourcesFromFile(fileArg)
+
+	info, err := os.Stat(fileArg)
+	if err != nil || !info.IsDir() {
+		return model.ResourcesFromFile(fileArg)
+	}
+
+	var errs error
+	var resources []*model.AnyResource
+	err = filepath.WalkDir(fileArg, func(path string, d fs.DirEntry, err error) error {
+		switch {
+		case err != nil:
+			return err
+		case d.IsDir():
+			if path != fileArg && !recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		case !isYAML(path):
+			return nil
+		}
+
+		fileResources, err := model.ResourcesFromFile(path)
+		if err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("%s: %w", path, err))
+			return nil
+		}
+		resources = append(resources, fileResources...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resources, errs
+}
+
+func isYAML(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return true
+	}
+	return false
+}
+
+// ownerLabels parses the selector, which can only contain equality requirements because its labels are added to
+// resources
+func ownerLabels(selector string) (model.Labels, error) {
+	if selector == "" {
+		return model.MakeLabels(), nil
+	}
+	owner, err := model.LabelsFromSelector(selector)
+	if err != nil {
+		return owner, fmt.Errorf("invalid selector %s: %w", selector, err)
+	}
+	return owner, nil
+}
+
+func addOwnerLabels(resource *model.AnyResource, owner model.Labels) error {
+	if len(owner.Set) == 0 {
+		return nil
+	}
+	if resource.Metadata.Labels.Set == nil {
+		resource.Metadata.Labels = model.MakeLabels()
+	}
+	for name, value := range owner.Set {
+		if current, ok := resource.Metadata.Labels.Set[name]; ok && current != value {
+			return fmt.Errorf("%s %s has label %s=%s which conflicts with the selector", resource.Kind, resource.Name(), name, current)
+		}
+		resource.Metadata.Labels.Set[name] = value
+	}
+	return nil
+}
+
+// pruneResources returns the resources on the server with the owner labels that are not being applied, in the order
+// they must be deleted
+func pruneResources(ctx context.Context, c client.BindPlane, owner model.Labels, applied []*model.AnyResource) ([]*model.AnyResource, error) {
+	keep := map[string]bool{}
+	for _, resource := range applied {
+		keep[resourceKey(resource.Kind, resource.Name())] = true
+	}
+
+	var prune []*model.AnyResource
+	add := func(resources []model.Resource) {
+		for _, resource := range resources {
+			if keep[resourceKey(resource.GetKind(), resource.Name())] {
+				continue
+			}
+			prune = append(prune, &model.AnyResource{
+				ResourceMeta: model.ResourceMeta{
+					APIVersion: model.V1,
+					Kind:       resource.GetKind(),
+					Metadata:   model.Metadata{Name: resource.Name()},
+				},
+			})
+		}
+	}
+
+	for _, list := range []func(ctx context.Context, c client.BindPlane, options ...client.QueryOption) ([]model.Resource, error){
+		listResources(client.BindPlane.Configurations),
+		listResources(client.BindPlane.Sources),
+		listResources(client.BindPlane.Processors),
+		listResources(client.BindPlane.Destinations),
+		listResources(client.BindPlane.SourceTypes),
+		listResources(client.BindPlane.ProcessorTypes),
+		listResources(client.BindPlane.DestinationTypes),
+		listResources(client.BindPlane.AgentVersions),
+	} {
+		// the server only returns resources with the owner labels
+		resources, err := list(ctx, c, client.WithSelector(owner.AsSelector().String()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list resources to prune: %w", err)
+		}
+		add(resources)
+	}
+
+	// delete resources before the resources they depend on
+	sort.SliceStable(prune, func(i, j int) bool {
+		if prune[i].Kind.DependencyOrder() != prune[j].Kind.DependencyOrder() {
+			return prune[i].Kind.DependencyOrder() > prune[j].Kind.DependencyOrder()
+		}
+		return prune[i].Name() < prune[j].Name()
+	})
+	return prune, nil
+}
+
+// validateResources returns the validation errors of the resources. Validation that requires the server, like checking
+// parameters against their resource types, is not done.
+func validateResources(resources []*model.AnyResource) error {
+	var errs error
+	for _, resource := range resources {
+		parsed, err := model.ParseResource(resource)
+		if err == nil {
+			_, err = parsed.Validate()
+		}
+		if err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("%s %s: %w", resource.Kind, resource.Name(), err))
+		}
+	}
+	return errs
+}
+
+func listResources[T model.Resource](list func(c client.BindPlane, ctx context.Context, options ...client.QueryOption) ([]T, error)) func(ctx context.Context, c client.BindPlane, options ...client.QueryOption) ([]model.Resource, error) {
+	return func(ctx context.Context, c client.BindPlane, options ...client.QueryOption) ([]model.Resource, error) {
+		items, err := list(c, ctx, options...)
+		if err != nil {
+			return nil, err
+		}
+		resources := make([]model.Resource, 0, len(items))
+		for _, item := range items {
+			resources = append(resources, item)
+		}
+		return resources, nil
+	}
+}
+
+func resourceKey(kind model.Kind, name string) string {
+	return fmt.Sprintf("%s|%s", kind, name)
 }
@@ -19,6 +19,7 @@ import (
 	"context"
 	"fmt"
 	"io/ioutil"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -218,3 +219,199 @@ spec:
 	// 	require.Error(t, err)
 	// })
 }
+
+// pruneClient returns the configurations and sources on the server for apply --prune. The server filters them by the
+// selector, so they should all have the owner labels.
+type pruneClient struct {
+	mockClient
+	configurations []*model.Configuration
+	sources        []*model.Source
+}
+
+func (s *pruneClient) Delete(ctx context.Context, r []*model.AnyResource) ([]*model.AnyResourceStatus, error) {
+	args := s.Called(ctx, r)
+	result, _ := args.Get(0).([]*model.AnyResourceStatus)
+	return result, args.Error(1)
+}
+
+func (s *pruneClient) Configurations(ctx context.Context, options ...client.QueryOption) ([]*model.Configuration, error) {
+	return s.configurations, nil
+}
+
+func (s *pruneClient) Sources(ctx context.Context, options ...client.QueryOption) ([]*model.Source, error) {
+	return s.sources, nil
+}
+
+func (s *pruneClient) Processors(ctx context.Context, options ...client.QueryOption) ([]*model.Processor, error) {
+	return nil, nil
+}
+
+func (s *pruneClient) Destinations(ctx context.Context, options ...client.QueryOption) ([]*model.Destination, error) {
+	return nil, nil
+}
+
+func (s *pruneClient) SourceTypes(ctx context.Context, options ...client.QueryOption) ([]*model.SourceType, error) {
+	return nil, nil
+}
+
+func (s *pruneClient) ProcessorTypes(ctx context.Context, options ...client.QueryOption) ([]*model.ProcessorType, error) {
+	return nil, nil
+}
+
+func (s *pruneClient) DestinationTypes(ctx context.Context, options ...client.QueryOption) ([]*model.DestinationType, error) {
+	return nil, nil
+}
+
+func (s *pruneClient) AgentVersions(ctx context.Context, options ...client.QueryOption) ([]*model.AgentVersion, error) {
+	return nil, nil
+}
+
+func resourceNames(resources []*model.AnyResource) []string {
+	names := []string{}
+	for _, r := range resources {
+		names = append(names, fmt.Sprintf("%s %s", r.Kind, r.Name()))
+	}
+	return names
+}
+
+func TestApplyDirectory(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		expect []string
+	}{
+		{
+			name:   "directory",
+			args:   []string{"-f", "testfiles/directory"},
+			expect: []string{"Source macos"},
+		},
+		{
+			name:   "recursive",
+			args:   []string{"-R", "-f", "testfiles/directory"},
+			expect: []string{"Configuration macos", "Source macos"},
+		},
+		{
+			name:   "recursive positional argument",
+			args:   []string{"-R", "testfiles/directory"},
+			expect: []string{"Configuration macos", "Source macos"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var applied []*model.AnyResource
+			client := &mockClient{}
+			client.On("Apply", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				applied = args.Get(1).([]*model.AnyResource)
+			}).Return([]*model.AnyResourceStatus{}, nil)
+			stub := &cli.BindPlane{}
+			stub.SetClient(client)
+
+			apply := Command(stub)
+			apply.SetArgs(test.args)
+			apply.SetOut(bytes.NewBufferString(""))
+			require.NoError(t, apply.Execute())
+			require.ElementsMatch(t, test.expect, resourceNames(applied))
+		})
+	}
+}
+
+func TestApplyPrune(t *testing.T) {
+	labeled := func(name string, labels map[string]string) model.ResourceMeta {
+		return model.ResourceMeta{
+			Kind:     model.KindSource,
+			Metadata: model.Metadata{Name: name, Labels: model.LabelsFromValidatedMap(labels)},
+		}
+	}
+	newClient := func() *pruneClient {
+		client := &pruneClient{
+			sources: []*model.Source{
+				{ResourceMeta: labeled("macos", map[string]string{"app": "foo"})},
+				{ResourceMeta: labeled("removed", map[string]string{"app": "foo"})},
+			},
+			configurations: []*model.Configuration{
+				{ResourceMeta: model.ResourceMeta{
+					Kind:     model.KindConfiguration,
+					Metadata: model.Metadata{Name: "removed", Labels: model.LabelsFromValidatedMap(map[string]string{"app": "foo"})},
+				}},
+			},
+		}
+		client.On("Apply", mock.Anything, mock.Anything).Return([]*model.AnyResourceStatus{}, nil)
+		client.On("Delete", mock.Anything, mock.Anything).Return([]*model.AnyResourceStatus{}, nil)
+		return client
+	}
+	execute := func(t *testing.T, client *pruneClient, args ...string) (string, error) {
+		stub := &cli.BindPlane{}
+		stub.SetClient(client)
+		apply := Command(stub)
+		apply.SetArgs(args)
+		out := bytes.NewBufferString("")
+		apply.SetOut(out)
+		err := apply.Execute()
+		return out.String(), err
+	}
+
+	t.Run("requires a selector", func(t *testing.T) {
+		client := newClient()
+		_, err := execute(t, client, "-f", "testfiles/directory", "--prune")
+		require.Error(t, err)
+		client.AssertNotCalled(t, "Apply", mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects selectors that can't be added as labels", func(t *testing.T) {
+		_, err := execute(t, newClient(), "-f", "testfiles/directory", "--prune", "-l", "app!=foo")
+		require.Error(t, err)
+	})
+
+	t.Run("rejects resources with conflicting labels", func(t *testing.T) {
+		client := newClient()
+		_, err := execute(t, client, "-R", "-f", "testfiles/directory", "-l", "platform=linux")
+		require.Error(t, err)
+		client.AssertNotCalled(t, "Apply", mock.Anything, mock.Anything)
+	})
+
+	t.Run("adds owner labels and deletes owned resources that were not applied", func(t *testing.T) {
+		client := newClient()
+		_, err := execute(t, client, "-R", "-f", "testfiles/directory", "--prune", "-l", "app=foo")
+		require.NoError(t, err)
+
+		applied := client.Calls[0].Arguments.Get(1).([]*model.AnyResource)
+		for _, r := range applied {
+			require.Equal(t, "foo", r.Metadata.Labels.Get("app"))
+		}
+
+		// configurations are deleted before the sources they use
+		client.AssertCalled(t, "Delete", mock.Anything, mock.Anything)
+		deleted := client.Calls[1].Arguments.Get(1).([]*model.AnyResource)
+		require.Equal(t, []string{"Configuration removed", "Source removed"}, resourceNames(deleted))
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		client := newClient()
+		out, err := execute(t, client, "-R", "-f", "testfiles/directory", "--prune", "-l", "app=foo", "--dry-run")
+		require.NoError(t, err)
+		require.Equal(t, `Configuration macos applied (dry run)
+Source macos applied (dry run)
+Configuration removed pruned (dry run)
+Source removed pruned (dry run)
+`, out)
+		client.AssertNotCalled(t, "Apply", mock.Anything, mock.Anything)
+		client.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+
+	t.Run("dry run validates resources", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "invalid.yaml")
+		require.NoError(t, ioutil.WriteFile(file, []byte(`apiVersion: bindplane.observiq.com/v1
+kind: Source
+metadata:
+  name: bad name
+spec:
+  type: macos
+`), 0600))
+
+		client := newClient()
+		out, err := execute(t, client, "-f", file, "--prune", "-l", "app=foo", "--dry-run")
+		require.ErrorContains(t, err, "Source bad name")
+		require.NotContains(t, out, "(dry run)")
+		client.AssertNotCalled(t, "Apply", mock.Anything, mock.Anything)
+	})
+}
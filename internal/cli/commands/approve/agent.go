@@ -0,0 +1,76 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package approve
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/observiq/bindplane-op/internal/cli"
+	"github.com/observiq/bindplane-op/model"
+)
+
+var (
+	selectorFlag string
+	queryFlag    string
+)
+
+// AgentCommand returns the BindPlane approve agent cobra command
+func AgentCommand(bindplane *cli.BindPlane) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "agent [id]...",
+		Aliases: []string{"agents"},
+		Short:   "Approves pending agents",
+		Long: `When the server requires approval, new agents are Pending and do not receive configuration until they are
+approved. Agents can be approved by id or by selector and query.`,
+		Example: `bindplanectl approve agent 01GD6X4YRJ1WZ8X5E3ZSQ6M6XN
+bindplanectl approve agents --selector env=prod`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 && selectorFlag == "" && queryFlag == "" {
+				return errors.New("ids of the agents, --selector, or --query must be specified")
+			}
+
+			c, err := bindplane.Client()
+			if err != nil {
+				return fmt.Errorf("error creating client: %w", err)
+			}
+
+			approved, err := c.ApproveAgents(cmd.Context(), &model.PostApproveAgentsRequest{
+				IDs:      args,
+				Selector: selectorFlag,
+				Query:    queryFlag,
+			})
+			if err != nil {
+				return err
+			}
+
+			if len(approved) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No pending agents found")
+				return nil
+			}
+			for _, id := range approved {
+				fmt.Fprintf(cmd.OutOrStdout(), "Approved agent '%s'\n", id)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&selectorFlag, "selector", "", "label selector of the agents to approve, e.g. env=prod")
+	cmd.Flags().StringVar(&queryFlag, "query", "", "search query of the agents to approve, e.g. hostname:web-1")
+
+	return cmd
+}
@@ -0,0 +1,87 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package create
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/observiq/bindplane-op/internal/cli"
+	"github.com/observiq/bindplane-op/model"
+)
+
+var (
+	denyIDFlag         string
+	denyHostnameFlag   string
+	denyMacAddressFlag string
+	denyReasonFlag     string
+)
+
+// AgentDenyRuleCommand returns the BindPlane create agent-deny-rule cobra command
+func AgentDenyRuleCommand(bindplane *cli.BindPlane) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "agent-deny-rule",
+		Aliases: []string{"agent-deny-rules"},
+		Short:   "Denies agents with an ID, host name, or MAC address",
+		Long: `Agents matching a deny rule are removed from BindPlane, disconnected, and rejected when they reconnect until the
+rule is deleted.`,
+		Example: "bindplanectl create agent-deny-rule --mac-address 00-11-22-33-44-55 --reason cloned",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			req := &model.PostAgentDenyRuleRequest{Reason: denyReasonFlag}
+			for field, value := range map[model.AgentDenyField]string{
+				model.AgentDenyFieldID:         denyIDFlag,
+				model.AgentDenyFieldHostName:   denyHostnameFlag,
+				model.AgentDenyFieldMacAddress: denyMacAddressFlag,
+			} {
+				if value == "" {
+					continue
+				}
+				if req.Field != "" {
+					return errors.New("only one of --id, --hostname, or --mac-address can be specified")
+				}
+				req.Field = field
+				req.Value = value
+			}
+			if req.Field == "" {
+				return errors.New("one of --id, --hostname, or --mac-address must be specified")
+			}
+
+			c, err := bindplane.Client()
+			if err != nil {
+				return fmt.Errorf("error creating client: %w", err)
+			}
+
+			response, err := c.CreateAgentDenyRule(cmd.Context(), req)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Created agent-deny-rule '%s'\n", response.Rule.ID())
+			for _, id := range response.Disconnected {
+				fmt.Fprintf(cmd.OutOrStdout(), "Disconnected agent '%s'\n", id)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&denyIDFlag, "id", "", "id of the agent to deny")
+	cmd.Flags().StringVar(&denyHostnameFlag, "hostname", "", "host name of the agents to deny")
+	cmd.Flags().StringVar(&denyMacAddressFlag, "mac-address", "", "MAC address of the agents to deny")
+	cmd.Flags().StringVar(&denyReasonFlag, "reason", "", "description of why the agents are denied")
+
+	return cmd
+}
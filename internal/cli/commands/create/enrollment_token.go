@@ -0,0 +1,72 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package create
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/observiq/bindplane-op/internal/cli"
+	"github.com/observiq/bindplane-op/model"
+)
+
+var (
+	labelsFlag    string
+	maxUsesFlag   int
+	expiresInFlag string
+)
+
+// EnrollmentTokenCommand returns the BindPlane create enrollment-token cobra command
+func EnrollmentTokenCommand(bindplane *cli.BindPlane) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "enrollment-token",
+		Aliases: []string{"enrollment-tokens"},
+		Short:   "Creates a token used by agents to enroll with BindPlane",
+		Long: `An enrollment token is used by agents to enroll with BindPlane instead of the server secret key. The token
+labels are applied to every agent enrolled with it. The token value is only displayed once.`,
+		Example: "bindplanectl create enrollment-token --labels env=prod --max-uses 10 --expires-in 24h",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			labels, err := model.LabelsFromSelector(labelsFlag)
+			if err != nil {
+				return fmt.Errorf("invalid labels: %w", err)
+			}
+
+			c, err := bindplane.Client()
+			if err != nil {
+				return fmt.Errorf("error creating client: %w", err)
+			}
+
+			response, err := c.CreateEnrollmentToken(cmd.Context(), &model.PostEnrollmentTokenRequest{
+				Labels:    labels.AsMap(),
+				MaxUses:   maxUsesFlag,
+				ExpiresIn: expiresInFlag,
+			})
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Created enrollment-token '%s'\n", response.EnrollmentToken.ID)
+			fmt.Fprintf(cmd.OutOrStdout(), "%s\n", response.Token)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&labelsFlag, "labels", "", "labels to apply to agents enrolled with the token, e.g. env=prod,app=web")
+	cmd.Flags().IntVar(&maxUsesFlag, "max-uses", 0, "maximum number of agents that can enroll with the token, 0 is unlimited")
+	cmd.Flags().StringVar(&expiresInFlag, "expires-in", "", "duration until the token expires, e.g. 24h, the token does not expire if empty")
+
+	return cmd
+}
@@ -69,6 +69,8 @@ func Command(bindplane *cli.BindPlane) *cobra.Command {
 		deleteResourceCommand(bindplane, "processor-type", []string{"processor-types", "processorType", "processorTypes"}),
 		deleteResourceCommand(bindplane, "destination", []string{"destinations"}),
 		deleteResourceCommand(bindplane, "destination-type", []string{"destination-types", "destinationType", "destinationTypes"}),
+		deleteResourceCommand(bindplane, "enrollment-token", []string{"enrollment-tokens"}),
+		deleteResourceCommand(bindplane, "agent-deny-rule", []string{"agent-deny-rules"}),
 	)
 
 	return cmd
@@ -112,6 +114,10 @@ func deleteResourceCommand(bindplane *cli.BindPlane, resourceType string, aliase
 				err = c.DeleteDestination(ctx, name)
 			case "destination-type":
 				err = c.DeleteDestinationType(ctx, name)
+			case "enrollment-token":
+				err = c.DeleteEnrollmentToken(ctx, name)
+			case "agent-deny-rule":
+				err = c.DeleteAgentDenyRule(ctx, name)
 			default:
 				return fmt.Errorf("unknown type, unable to delete %s '%s'", resourceType, name)
 			}
@@ -0,0 +1,274 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"reflect"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/observiq/bindplane-op/client"
+	"github.com/observiq/bindplane-op/internal/cli"
+	"github.com/observiq/bindplane-op/model"
+)
+
+// Command returns the BindPlane edit cobra command
+func Command(bindplane *cli.BindPlane) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "edit <kind> <name>",
+		Short: "Edit a resource on the server",
+		Long: `Opens a configuration, source, source-type, processor, processor-type, destination, destination-type, or
+agent-version in the editor specified by $EDITOR and applies it when the editor exits. The editor is opened again if the
+resource is invalid. The edit is not applied if the resource was changed on the server while it was being edited.`,
+		Example: "  bindplanectl edit source my-source",
+		Args:    cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kind := model.ParseKind(strings.ReplaceAll(args[0], "-", ""))
+			if kind == model.KindUnknown {
+				return fmt.Errorf("%s is not a valid resource kind", args[0])
+			}
+			name := args[1]
+
+			c, err := bindplane.Client()
+			if err != nil {
+				return fmt.Errorf("error creating client: %w", err)
+			}
+			ctx := cmd.Context()
+
+			resource, err := getResource(ctx, c, kind, name)
+			if err != nil {
+				return err
+			}
+			if resource == nil {
+				return fmt.Errorf("no %s found with name %s", kind, name)
+			}
+			original, err := yaml.Marshal(resource)
+			if err != nil {
+				return fmt.Errorf("failed to marshal %s %s: %w", kind, name, err)
+			}
+			hash, err := model.ResourceHash(resource)
+			if err != nil {
+				return fmt.Errorf("failed to hash %s %s: %w", kind, name, err)
+			}
+
+			edited, err := editResource(cmd, newClientStore(ctx, c), kind, name, string(original))
+			if err != nil || edited == nil {
+				return err
+			}
+
+			resources, err := model.ResourcesFromReader(bytes.NewReader(edited))
+			if err != nil {
+				return err
+			}
+
+			// optimistic concurrency: the server doesn't apply the edit if the resource was changed while it was being
+			// edited
+			preconditions := []model.ResourcePrecondition{{Kind: kind, Name: name, Hash: hash}}
+			resourceStatuses, err := c.ApplyWithPreconditions(ctx, resources, preconditions)
+			if errors.Is(err, client.ErrPreconditionFailed) {
+				path, saveErr := saveEdit(edited)
+				if saveErr != nil {
+					return fmt.Errorf("%s %s was changed on the server while it was being edited and the edit could not be saved: %w", kind, name, saveErr)
+				}
+				return fmt.Errorf("%s %s was changed on the server while it was being edited, the edit was saved to %s and was not applied", kind, name, path)
+			}
+			if err != nil {
+				return err
+			}
+
+			model.PrintResourceUpdates(cmd.OutOrStdout(), resourceStatuses)
+			return nil
+		},
+	}
+	return cmd
+}
+
+// editResource opens the resource in the editor until it is valid. It returns nil if the edit was cancelled.
+func editResource(cmd *cobra.Command, store model.ResourceStore, kind model.Kind, name, original string) ([]byte, error) {
+	file, err := os.CreateTemp("", fmt.Sprintf("bindplane-%s-*.yaml", strings.ToLower(string(kind))))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create a file to edit: %w", err)
+	}
+	path := file.Name()
+	_ = file.Close()
+	defer os.Remove(path)
+
+	content := []byte(original)
+	var problems []string
+	for {
+		if err := os.WriteFile(path, withHeader(kind, content, problems), 0600); err != nil {
+			return nil, fmt.Errorf("failed to write the file to edit: %w", err)
+		}
+		if err := openEditor(cmd, path); err != nil {
+			return nil, err
+		}
+		saved, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the edited file: %w", err)
+		}
+
+		edited := withoutHeader(saved)
+		if len(bytes.TrimSpace(edited)) == 0 || string(edited) == original {
+			fmt.Fprintln(cmd.OutOrStdout(), "Edit cancelled, no changes made.")
+			return nil, nil
+		}
+
+		problems = validate(store, kind, name, edited)
+		if len(problems) == 0 {
+			return edited, nil
+		}
+		content = edited
+	}
+}
+
+// validate returns the problems with the edited resource, using the same validation as the server
+func validate(store model.ResourceStore, kind model.Kind, name string, edited []byte) []string {
+	resources, err := model.ResourcesFromReader(bytes.NewReader(edited))
+	if err != nil {
+		return []string{err.Error()}
+	}
+	if len(resources) != 1 {
+		return []string{fmt.Sprintf("expected 1 resource, found %d", len(resources))}
+	}
+	if resources[0].Kind != kind || resources[0].Name() != name {
+		return []string{fmt.Sprintf("the kind and name can't be changed from %s %s", kind, name)}
+	}
+
+	resource, err := model.ParseResource(resources[0])
+	if err != nil {
+		return []string{err.Error()}
+	}
+	if _, err := resource.ValidateWithStore(store); err != nil {
+		return strings.Split(strings.TrimSpace(err.Error()), "\n")
+	}
+	return nil
+}
+
+const headerPrefix = "#"
+
+// withHeader adds comments with instructions and the problems found with the previous edit
+func withHeader(kind model.Kind, content []byte, problems []string) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "# Edit the %s below. Lines at the top beginning with '#' are ignored. Save an empty file or the\n", kind)
+	fmt.Fprintln(&b, "# file without changes to cancel the edit.")
+	if len(problems) > 0 {
+		fmt.Fprintln(&b, "#")
+		fmt.Fprintf(&b, "# %s is invalid:\n", kind)
+		for _, problem := range problems {
+			fmt.Fprintf(&b, "#   %s\n", strings.TrimSpace(problem))
+		}
+	}
+	fmt.Fprintln(&b, "#")
+	b.Write(content)
+	return b.Bytes()
+}
+
+// withoutHeader removes the comments at the top of the file. Comments in the resource are kept because they may be part
+// of a multi-line value, like the raw configuration of a Configuration.
+func withoutHeader(saved []byte) []byte {
+	scanner := bufio.NewScanner(bytes.NewReader(saved))
+	scanner.Buffer(nil, len(saved)+1)
+	var b bytes.Buffer
+	header := true
+	for scanner.Scan() {
+		line := scanner.Text()
+		if header && strings.HasPrefix(strings.TrimSpace(line), headerPrefix) {
+			continue
+		}
+		header = false
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	return b.Bytes()
+}
+
+// saveEdit saves an edit that could not be applied so that it isn't lost
+func saveEdit(edited []byte) (string, error) {
+	file, err := os.CreateTemp("", "bindplane-edit-*.yaml")
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	if _, err := file.Write(edited); err != nil {
+		return "", err
+	}
+	return file.Name(), nil
+}
+
+// openEditor opens the file with $EDITOR and waits for the editor to exit. It is a variable so that tests can replace
+// the editor.
+var openEditor = func(cmd *cobra.Command, path string) error {
+	editor := strings.Fields(os.Getenv("EDITOR"))
+	if len(editor) == 0 {
+		editor = []string{"vi"}
+		if runtime.GOOS == "windows" {
+			editor = []string{"notepad"}
+		}
+	}
+
+	// #nosec G204 - the editor is chosen by the user running the command
+	editorCmd := exec.CommandContext(cmd.Context(), editor[0], append(editor[1:], path)...)
+	editorCmd.Stdin = os.Stdin
+	editorCmd.Stdout = os.Stdout
+	editorCmd.Stderr = os.Stderr
+	if err := editorCmd.Run(); err != nil {
+		return fmt.Errorf("failed to run the editor %s: %w", strings.Join(editor, " "), err)
+	}
+	return nil
+}
+
+// getResource returns the resource on the server or nil if it doesn't exist
+func getResource(ctx context.Context, c client.BindPlane, kind model.Kind, name string) (model.Resource, error) {
+	switch kind {
+	case model.KindConfiguration:
+		return asResource(c.Configuration(ctx, name))
+	case model.KindSource:
+		return asResource(c.Source(ctx, name))
+	case model.KindSourceType:
+		return asResource(c.SourceType(ctx, name))
+	case model.KindProcessor:
+		return asResource(c.Processor(ctx, name))
+	case model.KindProcessorType:
+		return asResource(c.ProcessorType(ctx, name))
+	case model.KindDestination:
+		return asResource(c.Destination(ctx, name))
+	case model.KindDestinationType:
+		return asResource(c.DestinationType(ctx, name))
+	case model.KindAgentVersion:
+		return asResource(c.AgentVersion(ctx, name))
+	}
+	return nil, errors.New("only configurations, sources, processors, destinations, their types, and agent-versions can be edited")
+}
+
+// asResource converts a resource returned by the client to a Resource that is nil if the resource was not found
+func asResource[T model.Resource](resource T, err error) (model.Resource, error) {
+	if errors.Is(err, client.ErrResourceNotFound) {
+		return nil, nil
+	}
+	if err != nil || reflect.ValueOf(resource).IsNil() {
+		return nil, err
+	}
+	return resource, nil
+}
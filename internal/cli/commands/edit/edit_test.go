@@ -0,0 +1,219 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edit
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+
+	"github.com/observiq/bindplane-op/client"
+	"github.com/observiq/bindplane-op/common"
+	"github.com/observiq/bindplane-op/internal/cli"
+	"github.com/observiq/bindplane-op/model"
+)
+
+type mockClient struct {
+	client.BindPlane
+	// sources are returned by successive calls to Source, the last one is repeated and is the source on the server when
+	// the edit is applied
+	sources    []*model.Source
+	sourceType *model.SourceType
+	applied    []*model.AnyResource
+}
+
+func (c *mockClient) Source(ctx context.Context, name string) (*model.Source, error) {
+	if len(c.sources) == 0 {
+		return nil, fmt.Errorf("unable to get /sources/%s, got 404 Not Found: %w", name, client.ErrResourceNotFound)
+	}
+	source := c.sources[0]
+	if len(c.sources) > 1 {
+		c.sources = c.sources[1:]
+	}
+	return source, nil
+}
+
+func (c *mockClient) SourceType(ctx context.Context, name string) (*model.SourceType, error) {
+	if c.sourceType == nil || c.sourceType.Name() != name {
+		return nil, fmt.Errorf("unable to get /source-types/%s, got 404 Not Found: %w", name, client.ErrResourceNotFound)
+	}
+	return c.sourceType, nil
+}
+
+func (c *mockClient) ApplyWithPreconditions(ctx context.Context, r []*model.AnyResource, preconditions []model.ResourcePrecondition) ([]*model.AnyResourceStatus, error) {
+	for _, precondition := range preconditions {
+		hash, err := model.ResourceHash(c.sources[0])
+		if err != nil {
+			return nil, err
+		}
+		if precondition.Hash != hash {
+			return nil, fmt.Errorf("%w: %s %s was changed", client.ErrPreconditionFailed, precondition.Kind, precondition.Name)
+		}
+	}
+	return c.Apply(ctx, r)
+}
+
+func (c *mockClient) Apply(ctx context.Context, r []*model.AnyResource) ([]*model.AnyResourceStatus, error) {
+	c.applied = append(c.applied, r...)
+	statuses := []*model.AnyResourceStatus{}
+	for _, resource := range r {
+		statuses = append(statuses, &model.AnyResourceStatus{Resource: *resource, Status: model.StatusConfigured})
+	}
+	return statuses, nil
+}
+
+func testSource(startAt string) *model.Source {
+	source := model.NewSource("macos", "macos", []model.Parameter{{Name: "start_at", Value: startAt}})
+	source.Metadata.ID = "source-1"
+	return source
+}
+
+func newClient() *mockClient {
+	return &mockClient{
+		sources: []*model.Source{testSource("end")},
+		sourceType: model.NewSourceType("macos", []model.ParameterDefinition{
+			{Name: "start_at", Type: "enum", ValidValues: []string{"beginning", "end"}},
+		}),
+	}
+}
+
+// useEditor replaces the editor with edits that are made in order each time the editor is opened. It returns the
+// contents of the file each time the editor was opened.
+func useEditor(t *testing.T, edits ...func(content string) string) *[]string {
+	opened := []string{}
+	previous := openEditor
+	t.Cleanup(func() { openEditor = previous })
+
+	openEditor = func(cmd *cobra.Command, path string) error {
+		require.Less(t, len(opened), len(edits), "editor opened too many times")
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		edit := edits[len(opened)]
+		opened = append(opened, string(content))
+		return os.WriteFile(path, []byte(edit(string(content))), 0600)
+	}
+	return &opened
+}
+
+func replace(old, new string) func(string) string {
+	return func(content string) string {
+		return strings.Replace(content, old, new, 1)
+	}
+}
+
+func execute(c *mockClient, args ...string) (string, error) {
+	out := bytes.NewBufferString("")
+	bindplane := cli.NewBindPlane(common.InitConfig(""), out)
+	bindplane.SetClient(c)
+
+	cmd := Command(bindplane)
+	cmd.SetArgs(args)
+	cmd.SetOut(out)
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+func TestEdit(t *testing.T) {
+	t.Run("applies the edit", func(t *testing.T) {
+		c := newClient()
+		opened := useEditor(t, replace("value: end", "value: beginning"))
+
+		out, err := execute(c, "source", "macos")
+		require.NoError(t, err)
+		require.Equal(t, "Source macos configured\n", out)
+		require.Contains(t, (*opened)[0], "# Edit the Source below.")
+
+		require.Len(t, c.applied, 1)
+		applied, err := model.ParseResource(c.applied[0])
+		require.NoError(t, err)
+		require.Equal(t, "beginning", applied.(*model.Source).Spec.Parameters[0].Value)
+		require.Equal(t, "source-1", applied.ID())
+	})
+
+	t.Run("cancels without changes", func(t *testing.T) {
+		c := newClient()
+		useEditor(t, func(content string) string { return content })
+
+		out, err := execute(c, "source", "macos")
+		require.NoError(t, err)
+		require.Equal(t, "Edit cancelled, no changes made.\n", out)
+		require.Empty(t, c.applied)
+	})
+
+	t.Run("cancels an empty file", func(t *testing.T) {
+		c := newClient()
+		useEditor(t, func(content string) string { return "" })
+
+		_, err := execute(c, "source", "macos")
+		require.NoError(t, err)
+		require.Empty(t, c.applied)
+	})
+
+	t.Run("reopens an invalid edit with errors", func(t *testing.T) {
+		c := newClient()
+		opened := useEditor(t,
+			replace("value: end", "value: middle"),
+			replace("value: middle", "value: beginning"),
+		)
+
+		_, err := execute(c, "source", "macos")
+		require.NoError(t, err)
+		require.Len(t, *opened, 2)
+		require.Contains(t, (*opened)[1], "# Source is invalid:")
+		require.Contains(t, (*opened)[1], "value: middle", "the invalid edit is kept")
+		require.Len(t, c.applied, 1)
+	})
+
+	t.Run("name can't be changed", func(t *testing.T) {
+		c := newClient()
+		opened := useEditor(t,
+			replace("name: macos", "name: linux"),
+			func(content string) string { return "" },
+		)
+
+		_, err := execute(c, "source", "macos")
+		require.NoError(t, err)
+		require.Contains(t, (*opened)[1], "the kind and name can't be changed from Source macos")
+		require.Empty(t, c.applied)
+	})
+
+	t.Run("does not apply if the resource changed on the server", func(t *testing.T) {
+		c := newClient()
+		c.sources = append(c.sources, testSource("beginning"))
+		useEditor(t, replace("value: end", "value: beginning"))
+
+		_, err := execute(c, "source", "macos")
+		require.ErrorContains(t, err, "was changed on the server while it was being edited")
+		require.Empty(t, c.applied)
+	})
+
+	t.Run("unknown kind", func(t *testing.T) {
+		_, err := execute(newClient(), "widget", "macos")
+		require.Error(t, err)
+	})
+
+	t.Run("missing resource", func(t *testing.T) {
+		c := newClient()
+		c.sources = nil
+		_, err := execute(c, "source", "macos")
+		require.ErrorContains(t, err, "no Source found with name macos")
+	})
+}
@@ -0,0 +1,79 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edit
+
+import (
+	"context"
+	"errors"
+
+	"github.com/observiq/bindplane-op/client"
+	"github.com/observiq/bindplane-op/model"
+)
+
+// clientStore is a model.ResourceStore that gets resources from the server so that edits can be validated the same way
+// they are validated by the server
+type clientStore struct {
+	ctx    context.Context
+	client client.BindPlane
+}
+
+var _ model.ResourceStore = (*clientStore)(nil)
+
+func newClientStore(ctx context.Context, client client.BindPlane) *clientStore {
+	return &clientStore{
+		ctx:    ctx,
+		client: client,
+	}
+}
+
+// Source returns the source with the specified name or nil if it doesn't exist
+func (s *clientStore) Source(name string) (*model.Source, error) {
+	return find(s.client.Source(s.ctx, name))
+}
+
+// SourceType returns the source type with the specified name or nil if it doesn't exist
+func (s *clientStore) SourceType(name string) (*model.SourceType, error) {
+	return find(s.client.SourceType(s.ctx, name))
+}
+
+// Processor returns the processor with the specified name or nil if it doesn't exist
+func (s *clientStore) Processor(name string) (*model.Processor, error) {
+	return find(s.client.Processor(s.ctx, name))
+}
+
+// ProcessorType returns the processor type with the specified name or nil if it doesn't exist
+func (s *clientStore) ProcessorType(name string) (*model.ProcessorType, error) {
+	return find(s.client.ProcessorType(s.ctx, name))
+}
+
+// Destination returns the destination with the specified name or nil if it doesn't exist
+func (s *clientStore) Destination(name string) (*model.Destination, error) {
+	return find(s.client.Destination(s.ctx, name))
+}
+
+// DestinationType returns the destination type with the specified name or nil if it doesn't exist
+func (s *clientStore) DestinationType(name string) (*model.DestinationType, error) {
+	return find(s.client.DestinationType(s.ctx, name))
+}
+
+// find returns nil for resources that don't exist because the client returns an error for them, while a
+// model.ResourceStore returns nil
+func find[T model.Resource](resource T, err error) (T, error) {
+	if errors.Is(err, client.ErrResourceNotFound) {
+		var missing T
+		return missing, nil
+	}
+	return resource, err
+}
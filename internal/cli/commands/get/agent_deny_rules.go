@@ -0,0 +1,52 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package get
+
+import (
+	"context"
+
+	"github.com/observiq/bindplane-op/client"
+	"github.com/observiq/bindplane-op/internal/cli"
+	"github.com/observiq/bindplane-op/model"
+	"github.com/spf13/cobra"
+)
+
+// AgentDenyRulesCommand returns the BindPlane get agent-deny-rules cobra command
+func AgentDenyRulesCommand(bindplane *cli.BindPlane) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "agent-deny-rules [id]",
+		Aliases: []string{"agent-deny-rule"},
+		Short:   "Displays the agent deny rules",
+		Long:    `Agents matching a deny rule are disconnected and cannot connect until the rule is deleted.`,
+		RunE: getImpl(bindplane, "agent-deny-rule", getter[*model.AgentDenyRule]{
+			one: func(ctx context.Context, client client.BindPlane, id string) (*model.AgentDenyRule, bool, error) {
+				rules, err := client.AgentDenyRules(ctx)
+				if err != nil {
+					return nil, false, err
+				}
+				for _, rule := range rules {
+					if rule.ID() == id {
+						return rule, true, nil
+					}
+				}
+				return nil, false, nil
+			},
+			all: func(ctx context.Context, client client.BindPlane) ([]*model.AgentDenyRule, error) {
+				return client.AgentDenyRules(ctx)
+			},
+		}),
+	}
+	return cmd
+}
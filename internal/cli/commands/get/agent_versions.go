@@ -31,6 +31,7 @@ func AgentVersionsCommand(bindplane *cli.BindPlane) *cobra.Command {
 		Short:   "Displays the agent versions",
 		Long:    `An agent version defines a specific version of an agent with links to the release package.`,
 		RunE: getImpl(bindplane, "agent-version", getter[*model.AgentVersion]{
+			kind: model.KindAgentVersion,
 			one: func(ctx context.Context, client client.BindPlane, name string) (*model.AgentVersion, bool, error) {
 				item, err := client.AgentVersion(ctx, name)
 				return item, item != nil, err
@@ -22,6 +22,7 @@ import (
 	"github.com/observiq/bindplane-op/client"
 	"github.com/observiq/bindplane-op/internal/cli"
 	"github.com/observiq/bindplane-op/internal/cli/printer"
+	"github.com/observiq/bindplane-op/model"
 )
 
 // AgentsCommand returns the BindPlane get agents cobra command
@@ -54,20 +55,28 @@ func AgentsCommand(bindplane *cli.BindPlane) *cobra.Command {
 					return fmt.Errorf("no agent found with ID %s", id)
 				}
 
+				if watching(cmd) {
+					return watchImpl(cmd, bindplane, model.KindAgent, id, "", listOne(c.Agent, id))
+				}
 				printer.PrintResource(bindplane.Printer(), agent)
 				return nil
 			}
 
-			agents, err := c.Agents(cmd.Context(),
+			options := []client.QueryOption{
 				client.WithSelector(selector),
 				client.WithQuery(query),
 				client.WithOffset(offset),
 				client.WithLimit(limit),
-			)
+			}
+
+			if watching(cmd) {
+				return watchImpl(cmd, bindplane, model.KindAgent, "", selector, listAll(c.Agents, options...))
+			}
+
+			agents, err := c.Agents(cmd.Context(), options...)
 			if err != nil {
 				return err
 			}
-
 			printer.PrintResources(bindplane.Printer(), agents)
 			return nil
 		},
@@ -21,6 +21,7 @@ import (
 
 	"github.com/observiq/bindplane-op/internal/cli"
 	"github.com/observiq/bindplane-op/internal/cli/printer"
+	"github.com/observiq/bindplane-op/model"
 )
 
 // ConfigurationsCommand returns the BindPlane get configurations cobra command
@@ -57,10 +58,17 @@ func ConfigurationsCommand(bindplane *cli.BindPlane) *cobra.Command {
 					return nil
 				}
 
+				if watching(cmd) {
+					return watchImpl(cmd, bindplane, model.KindConfiguration, name, "", listOne(c.Configuration, name))
+				}
 				printer.PrintResource(bindplane.Printer(), configuration)
 				return nil
 			}
 
+			if watching(cmd) {
+				return watchImpl(cmd, bindplane, model.KindConfiguration, "", "", listAll(c.Configurations))
+			}
+
 			configurations, err := c.Configurations(cmd.Context())
 			if err != nil {
 				return err
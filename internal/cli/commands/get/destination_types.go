@@ -19,6 +19,7 @@ import (
 
 	"github.com/observiq/bindplane-op/internal/cli"
 	"github.com/observiq/bindplane-op/internal/cli/printer"
+	"github.com/observiq/bindplane-op/model"
 	"github.com/spf13/cobra"
 )
 
@@ -46,10 +47,17 @@ func DestinationTypesCommand(bindplane *cli.BindPlane) *cobra.Command {
 					return fmt.Errorf("no destination-type found with name %s", name)
 				}
 
+				if watching(cmd) {
+					return watchImpl(cmd, bindplane, model.KindDestinationType, name, "", listOne(c.DestinationType, name))
+				}
 				printer.PrintResource(bindplane.Printer(), destinationType)
 				return nil
 			}
 
+			if watching(cmd) {
+				return watchImpl(cmd, bindplane, model.KindDestinationType, "", "", listAll(c.DestinationTypes))
+			}
+
 			destinationTypes, err := c.DestinationTypes(cmd.Context())
 			if err != nil {
 				return err
@@ -19,6 +19,7 @@ import (
 
 	"github.com/observiq/bindplane-op/internal/cli"
 	"github.com/observiq/bindplane-op/internal/cli/printer"
+	"github.com/observiq/bindplane-op/model"
 	"github.com/spf13/cobra"
 )
 
@@ -46,10 +47,17 @@ func DestinationsCommand(bindplane *cli.BindPlane) *cobra.Command {
 					return fmt.Errorf("no destination found with name %s", name)
 				}
 
+				if watching(cmd) {
+					return watchImpl(cmd, bindplane, model.KindDestination, name, "", listOne(c.Destination, name))
+				}
 				printer.PrintResource(bindplane.Printer(), destination)
 				return nil
 			}
 
+			if watching(cmd) {
+				return watchImpl(cmd, bindplane, model.KindDestination, "", "", listAll(c.Destinations))
+			}
+
 			destinations, err := c.Destinations(cmd.Context())
 			if err != nil {
 				return err
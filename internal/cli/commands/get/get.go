@@ -22,6 +22,7 @@ import (
 
 	"github.com/observiq/bindplane-op/client"
 	"github.com/observiq/bindplane-op/internal/cli"
+	"github.com/observiq/bindplane-op/internal/cli/flags"
 	"github.com/observiq/bindplane-op/internal/cli/printer"
 	"github.com/observiq/bindplane-op/model"
 )
@@ -39,12 +40,20 @@ func Command(bindplane *cli.BindPlane) *cobra.Command {
 		ConfigurationsCommand(bindplane),
 		DestinationsCommand(bindplane),
 		DestinationTypesCommand(bindplane),
+		EnrollmentTokensCommand(bindplane),
+		GitOpsSyncCommand(bindplane),
+		AgentDenyRulesCommand(bindplane),
 		ProcessorsCommand(bindplane),
 		ProcessorTypesCommand(bindplane),
 		SourcesCommand(bindplane),
 		SourceTypesCommand(bindplane),
+		UsageCommand(bindplane),
+		UserSessionsCommand(bindplane),
 	)
 
+	flags.Printer(cmd)
+	cmd.PersistentFlags().BoolP(watchFlag, "w", false, "after printing the resources, watch for changes and print them until interrupted")
+
 	return cmd
 }
 
@@ -52,8 +61,10 @@ func Command(bindplane *cli.BindPlane) *cobra.Command {
 // generic implementations for get
 
 type getter[T model.Printable] struct {
-	one func(ctx context.Context, client client.BindPlane, name string) (T, bool, error)
-	all func(ctx context.Context, client client.BindPlane) ([]T, error)
+	// kind is the kind of resource used for --watch. Leave it empty if the resource can't be watched.
+	kind model.Kind
+	one  func(ctx context.Context, client client.BindPlane, name string) (T, bool, error)
+	all  func(ctx context.Context, client client.BindPlane) ([]T, error)
 }
 
 func getImpl[T model.Printable](bindplane *cli.BindPlane, resourceName string, g getter[T]) func(cmd *cobra.Command, args []string) error {
@@ -74,15 +85,29 @@ func getImpl[T model.Printable](bindplane *cli.BindPlane, resourceName string, g
 				return fmt.Errorf("no %s found with name %s", resourceName, name)
 			}
 
+			if watching(cmd) {
+				return watchImpl(cmd, bindplane, g.kind, name, "", func(ctx context.Context) ([]T, error) {
+					item, exists, err := g.one(ctx, c, name)
+					if err != nil || !exists {
+						return nil, err
+					}
+					return []T{item}, nil
+				})
+			}
 			printer.PrintResource(bindplane.Printer(), item)
 			return nil
 		}
 
+		if watching(cmd) {
+			return watchImpl(cmd, bindplane, g.kind, "", "", func(ctx context.Context) ([]T, error) {
+				return g.all(ctx, c)
+			})
+		}
+
 		items, err := g.all(cmd.Context(), c)
 		if err != nil {
 			return err
 		}
-
 		printer.PrintResources(bindplane.Printer(), items)
 		return nil
 	}
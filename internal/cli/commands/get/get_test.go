@@ -25,6 +25,7 @@ import (
 
 	"github.com/observiq/bindplane-op/common"
 	"github.com/observiq/bindplane-op/internal/cli"
+	"github.com/observiq/bindplane-op/internal/cli/printer"
 )
 
 type testArgs struct {
@@ -72,6 +73,41 @@ func TestGetIndividualCommand(t *testing.T) {
 			args:         []string{"agent", "1"},
 			expectOutput: "ID\tNAME   \tVERSION\tSTATUS   \tCONNECTED\tDISCONNECTED\tLABELS \n1 \tAgent 1\t1.0.0  \tConnected\t-        \t-           \t      \t\n",
 		},
+		{
+			description:  "get usage destination destination-1",
+			args:         []string{"usage", "destination", "destination-1"},
+			expectOutput: "KIND       \tNAME         \tSOURCES\tPROCESSORS\tDESTINATIONS\tCONFIGURATIONS                 \tAGENTS \nDestination\tdestination-1\t-      \t-         \t-           \tconfiguration-1,configuration-2\t2     \t\n",
+		},
+		{
+			description:  "get usage Destinations destination-1",
+			args:         []string{"usage", "Destinations", "destination-1"},
+			expectOutput: "KIND       \tNAME         \tSOURCES\tPROCESSORS\tDESTINATIONS\tCONFIGURATIONS                 \tAGENTS \nDestination\tdestination-1\t-      \t-         \t-           \tconfiguration-1,configuration-2\t2     \t\n",
+		},
+		{
+			description:  "get enrollment-token token-1",
+			args:         []string{"enrollment-token", "token-1"},
+			expectOutput: "ID     \tUSES\tEXPIRES\tLABELS   \ntoken-1\t0/1 \tnever  \tenv=prod\t\n",
+		},
+		{
+			description:  "get enrollment-tokens",
+			args:         []string{"enrollment-tokens"},
+			expectOutput: "ID     \tUSES\tEXPIRES\tLABELS   \ntoken-1\t0/1 \tnever  \tenv=prod\t\ntoken-2\t3   \tnever  \t        \t\n",
+		},
+		{
+			description:  "get agent-deny-rules",
+			args:         []string{"agent-deny-rules"},
+			expectOutput: "ID            \tFIELD   \tVALUE\tREASON\tCREATED              \nhostname:web-1\thostname\tweb-1\tcloned\t2022-09-01T00:00:00Z\t\n",
+		},
+		{
+			description:  "get sync",
+			args:         []string{"sync"},
+			expectOutput: "REPOSITORY                               \tBRANCH\tPATH     \tCOMMIT \tSYNCED              \tCHANGES\tERRORS \nhttps://github.com/observiq/resources.git\tmain  \tbindplane\t8d2f0f5\t2022-09-01T00:00:00Z\t1      \t0     \t\n",
+		},
+		{
+			description:  "get session session-1",
+			args:         []string{"session", "session-1"},
+			expectOutput: "ID       \tUSER \tROLE \tADDRESS \tCREATED             \tLAST SEEN           \tEXPIRES              \nsession-1\tadmin\tadmin\t10.0.0.1\t2022-09-01T00:00:00Z\t2022-09-01T01:00:00Z\t2022-09-01T12:00:00Z\t\n",
+		},
 	}
 
 	for _, test := range tests {
@@ -93,3 +129,126 @@ func TestGetIndividualCommand(t *testing.T) {
 		})
 	}
 }
+
+func TestGetWatch(t *testing.T) {
+	var tests = []struct {
+		description  string
+		args         []string
+		output       string
+		expectOutput string
+		expectErr    string
+	}{
+		{
+			description:  "get agents --watch",
+			args:         []string{"agents", "--watch"},
+			output:       tableOutput,
+			expectOutput: "EVENT \tID\tNAME   \tVERSION\tSTATUS      \tCONNECTED\tDISCONNECTED\tLABELS \ninsert\t1 \tAgent 1\t1.0.0  \tConnected   \t-        \t-           \t      \t\ninsert\t2 \tAgent 2\t1.0.0  \tDisconnected\t-        \t-           \t      \t\nupdate\t2\tAgent 2\t1.0.0\tConnected\t-\t-\t\t\n",
+		},
+		{
+			description:  "get agent 1 -w only prints the agent",
+			args:         []string{"agent", "1", "-w"},
+			output:       tableOutput,
+			expectOutput: "EVENT \tID\tNAME   \tVERSION\tSTATUS   \tCONNECTED\tDISCONNECTED\tLABELS \ninsert\t1 \tAgent 1\t1.0.0  \tConnected\t-        \t-           \t      \t\n",
+		},
+		{
+			description: "get agents -w -o json prints an event per line",
+			args:        []string{"agents", "-w"},
+			output:      jsonOutput,
+			expectOutput: "{\"type\":\"insert\",\"kind\":\"Agent\",\"name\":\"1\",\"resource\":{\"id\":\"1\",\"name\":\"Agent 1\",\"type\":\"stanza\",\"arch\":\"amd64\",\"hostname\":\"local\",\"labels\":{},\"version\":\"1.0.0\",\"home\":\"/stanza\",\"platform\":\"linux\",\"operatingSystem\":\"Ubuntu 20.10\",\"macAddress\":\"00:00:ac:00:00:00\",\"status\":1}}\n" +
+				"{\"type\":\"insert\",\"kind\":\"Agent\",\"name\":\"2\",\"resource\":{\"id\":\"2\",\"name\":\"Agent 2\",\"type\":\"\",\"arch\":\"\",\"hostname\":\"\",\"labels\":{},\"version\":\"1.0.0\",\"home\":\"\",\"platform\":\"\",\"operatingSystem\":\"\",\"macAddress\":\"\",\"status\":0}}\n" +
+				"{\"type\":\"update\",\"kind\":\"Agent\",\"name\":\"2\",\"resource\":{\"id\":\"2\",\"name\":\"Agent 2\",\"type\":\"\",\"arch\":\"\",\"hostname\":\"\",\"labels\":{},\"version\":\"1.0.0\",\"home\":\"\",\"platform\":\"\",\"operatingSystem\":\"\",\"macAddress\":\"\",\"status\":1}}\n",
+		},
+		{
+			description: "get enrollment-tokens --watch is not supported",
+			args:        []string{"enrollment-tokens", "--watch"},
+			output:      tableOutput,
+			expectErr:   "get enrollment-tokens does not support --watch",
+		},
+		{
+			description: "get sync --watch is not supported",
+			args:        []string{"sync", "--watch"},
+			output:      tableOutput,
+			expectErr:   "get sync does not support --watch",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			buffer := bytes.NewBufferString("")
+			bindplane := setupBindPlane(buffer)
+			bindplane.Config.Output = test.output
+
+			cmd := Command(bindplane)
+			cmd.SetOut(buffer)
+			cmd.SetErr(ioutil.Discard)
+			cmd.SetArgs(test.args)
+
+			err := cmd.Execute()
+			if test.expectErr != "" {
+				require.EqualError(t, err, test.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.expectOutput, buffer.String())
+		})
+	}
+}
+
+func TestGetWatchListsAfterStart(t *testing.T) {
+	buffer := bytes.NewBufferString("")
+	bindplane := setupBindPlane(buffer)
+	c := &watchStartClient{mockClient: &mockClient{}}
+	bindplane.SetClient(c)
+
+	cmd := Command(bindplane)
+	cmd.SetOut(buffer)
+	cmd.SetArgs([]string{"agents", "--watch"})
+
+	require.NoError(t, cmd.Execute())
+	require.True(t, c.listedAfterStart, "agents are listed once the watch has started")
+	require.False(t, c.listedBeforeStart, "agents are not listed before the watch has started")
+	require.Contains(t, buffer.String(), "Agent 1")
+}
+
+func TestGetOutputFormats(t *testing.T) {
+	var tests = []struct {
+		description  string
+		args         []string
+		options      printer.Options
+		expectOutput string
+	}{
+		{
+			description:  "get agents -o csv --sort-by .status --no-headers",
+			args:         []string{"agents"},
+			options:      printer.Options{Output: "csv", SortBy: ".status", NoHeaders: true},
+			expectOutput: "2,Agent 2,1.0.0,Disconnected,-,-,\n1,Agent 1,1.0.0,Connected,-,-,\n",
+		},
+		{
+			description:  "get agent 1 -o jsonpath",
+			args:         []string{"agent", "1"},
+			options:      printer.Options{Output: "jsonpath={.hostname}"},
+			expectOutput: "local",
+		},
+		{
+			description:  "get agents -o custom-columns",
+			args:         []string{"agents"},
+			options:      printer.Options{Output: "custom-columns=ID:.id,OS:.operatingSystem"},
+			expectOutput: "ID\tOS           \n1 \tUbuntu 20.10\t\n2 \t<none>      \t\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			buffer := bytes.NewBufferString("")
+			bindplane := setupBindPlane(buffer)
+			bindplane.Config.Output = test.options.Output
+			bindplane.Config.SortBy = test.options.SortBy
+			bindplane.Config.NoHeaders = test.options.NoHeaders
+
+			cmd := Command(bindplane)
+			cmd.SetOut(buffer)
+			cmd.SetArgs(test.args)
+			executeAndAssertOutput(t, cmd, buffer, test.expectOutput)
+		})
+	}
+}
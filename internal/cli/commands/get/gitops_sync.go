@@ -0,0 +1,54 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package get
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/observiq/bindplane-op/internal/cli"
+	"github.com/observiq/bindplane-op/internal/cli/printer"
+)
+
+// GitOpsSyncCommand returns the BindPlane get sync cobra command
+func GitOpsSyncCommand(bindplane *cli.BindPlane) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Displays the status of the GitOps sync",
+		Long: `Displays the commit most recently synced from the GitOps repository configured on the server, along with the
+resources that were changed and any errors. Use -o yaml to see the details of each change and error.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if watching(cmd) {
+				return errWatchNotSupported(cmd)
+			}
+
+			c, err := bindplane.Client()
+			if err != nil {
+				return fmt.Errorf("error creating client: %w", err)
+			}
+
+			status, err := c.GitOpsSyncStatus(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			printer.PrintResource(bindplane.Printer(), status)
+			return nil
+		},
+	}
+	return cmd
+}
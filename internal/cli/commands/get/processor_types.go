@@ -19,6 +19,7 @@ import (
 
 	"github.com/observiq/bindplane-op/internal/cli"
 	"github.com/observiq/bindplane-op/internal/cli/printer"
+	"github.com/observiq/bindplane-op/model"
 	"github.com/spf13/cobra"
 )
 
@@ -46,10 +47,17 @@ func ProcessorTypesCommand(bindplane *cli.BindPlane) *cobra.Command {
 					return fmt.Errorf("no processor-type found with name %s", name)
 				}
 
+				if watching(cmd) {
+					return watchImpl(cmd, bindplane, model.KindProcessorType, name, "", listOne(c.ProcessorType, name))
+				}
 				printer.PrintResource(bindplane.Printer(), processorType)
 				return nil
 			}
 
+			if watching(cmd) {
+				return watchImpl(cmd, bindplane, model.KindProcessorType, "", "", listAll(c.ProcessorTypes))
+			}
+
 			processorTypes, err := c.ProcessorTypes(cmd.Context())
 			if err != nil {
 				return err
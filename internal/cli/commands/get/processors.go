@@ -19,6 +19,7 @@ import (
 
 	"github.com/observiq/bindplane-op/internal/cli"
 	"github.com/observiq/bindplane-op/internal/cli/printer"
+	"github.com/observiq/bindplane-op/model"
 	"github.com/spf13/cobra"
 )
 
@@ -46,10 +47,17 @@ func ProcessorsCommand(bindplane *cli.BindPlane) *cobra.Command {
 					return fmt.Errorf("no processor found with name %s", name)
 				}
 
+				if watching(cmd) {
+					return watchImpl(cmd, bindplane, model.KindProcessor, name, "", listOne(c.Processor, name))
+				}
 				printer.PrintResource(bindplane.Printer(), processor)
 				return nil
 			}
 
+			if watching(cmd) {
+				return watchImpl(cmd, bindplane, model.KindProcessor, "", "", listAll(c.Processors))
+			}
+
 			processors, err := c.Processors(cmd.Context())
 			if err != nil {
 				return err
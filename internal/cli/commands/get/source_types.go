@@ -31,6 +31,7 @@ func SourceTypesCommand(bindplane *cli.BindPlane) *cobra.Command {
 		Short:   "Displays the source types",
 		Long:    `A source type is a type of source that collects logs, metrics, and traces.`,
 		RunE: getImpl(bindplane, "source-type", getter[*model.SourceType]{
+			kind: model.KindSourceType,
 			one: func(ctx context.Context, client client.BindPlane, name string) (*model.SourceType, bool, error) {
 				item, err := client.SourceType(ctx, name)
 				return item, item != nil, err
@@ -19,6 +19,7 @@ import (
 
 	"github.com/observiq/bindplane-op/internal/cli"
 	"github.com/observiq/bindplane-op/internal/cli/printer"
+	"github.com/observiq/bindplane-op/model"
 	"github.com/spf13/cobra"
 )
 
@@ -46,10 +47,17 @@ func SourcesCommand(bindplane *cli.BindPlane) *cobra.Command {
 					return fmt.Errorf("no source found with name %s", name)
 				}
 
+				if watching(cmd) {
+					return watchImpl(cmd, bindplane, model.KindSource, name, "", listOne(c.Source, name))
+				}
 				printer.PrintResource(bindplane.Printer(), source)
 				return nil
 			}
 
+			if watching(cmd) {
+				return watchImpl(cmd, bindplane, model.KindSource, "", "", listAll(c.Sources))
+			}
+
 			sources, err := c.Sources(cmd.Context())
 			if err != nil {
 				return err
@@ -17,8 +17,11 @@ package get
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"testing"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/mock"
@@ -81,6 +84,109 @@ func (c *mockClient) Agent(ctx context.Context, id string) (*model.Agent, error)
 	return nil, nil
 }
 
+// ResourceUsage returns the usage of destination-1 and an error for other resources
+func (c *mockClient) ResourceUsage(ctx context.Context, kind model.Kind, name string) (*model.ResourceUsage, error) {
+	if kind != model.KindDestination || name != "destination-1" {
+		return nil, fmt.Errorf("unable to get /%s/%s/usage, got 404 Not Found", kind, name)
+	}
+	return &model.ResourceUsage{
+		Kind:           kind,
+		Name:           name,
+		Sources:        []string{},
+		Processors:     []string{},
+		Destinations:   []string{},
+		Configurations: []string{"configuration-1", "configuration-2"},
+		Agents:         []string{"1", "2"},
+	}, nil
+}
+
+// EnrollmentTokens returns a single-use token and an unlimited token
+func (c *mockClient) EnrollmentTokens(ctx context.Context) ([]*model.EnrollmentToken, error) {
+	labels, _ := model.LabelsFromSelector("env=prod")
+	return []*model.EnrollmentToken{
+		{ID: "token-1", MaxUses: 1, Labels: labels},
+		{ID: "token-2", Uses: 3, Labels: model.MakeLabels()},
+	}, nil
+}
+
+// AgentDenyRules returns a host name rule
+func (c *mockClient) AgentDenyRules(ctx context.Context) ([]*model.AgentDenyRule, error) {
+	return []*model.AgentDenyRule{
+		{Field: model.AgentDenyFieldHostName, Value: "web-1", Reason: "cloned", CreatedAt: time.Date(2022, 9, 1, 0, 0, 0, 0, time.UTC)},
+	}, nil
+}
+
+// GitOpsSyncStatus returns a sync that configured a source
+func (c *mockClient) GitOpsSyncStatus(ctx context.Context) (*model.GitOpsSyncStatus, error) {
+	return &model.GitOpsSyncStatus{
+		Repository: "https://github.com/observiq/resources.git",
+		Branch:     "main",
+		Path:       "bindplane",
+		Prune:      true,
+		Commit:     "8d2f0f5c4c1a6e2d8b9f3e7a1c5d4b6a2e9f8c7d",
+		SyncedAt:   time.Date(2022, 9, 1, 0, 0, 0, 0, time.UTC),
+		Changes: []model.GitOpsChange{
+			{Kind: model.KindSource, Name: "source-1", Status: model.StatusConfigured},
+		},
+	}, nil
+}
+
+// UserSessions returns a session of an admin
+func (c *mockClient) UserSessions(ctx context.Context) ([]*model.UserSession, error) {
+	created := time.Date(2022, 9, 1, 0, 0, 0, 0, time.UTC)
+	return []*model.UserSession{
+		{
+			ID:            "session-1",
+			User:          "admin",
+			Role:          model.RoleAdmin,
+			RemoteAddress: "10.0.0.1",
+			CreatedAt:     created,
+			LastSeenAt:    created.Add(time.Hour),
+			ExpiresAt:     created.Add(12 * time.Hour),
+		},
+	}, nil
+}
+
+// Watch starts the watch and sends a single update of Agent 2 and returns
+func (c *mockClient) Watch(ctx context.Context, kind model.Kind, name string, selector string, handler func([]*model.WatchEvent) error) error {
+	if err := handler(nil); err != nil {
+		return err
+	}
+	agent := &model.Agent{ID: "2", Name: "Agent 2", Version: "1.0.0", Status: model.Connected}
+	if kind != model.KindAgent || (name != "" && name != agent.ID) {
+		return nil
+	}
+	resource, err := json.Marshal(agent)
+	if err != nil {
+		return err
+	}
+	return handler([]*model.WatchEvent{
+		{Type: model.WatchEventUpdate, Kind: model.KindAgent, Name: agent.ID, Resource: resource},
+	})
+}
+
+// watchStartClient records whether agents were listed after the watch started
+type watchStartClient struct {
+	*mockClient
+	started           bool
+	listedAfterStart  bool
+	listedBeforeStart bool
+}
+
+func (c *watchStartClient) Agents(ctx context.Context, options ...client.QueryOption) ([]*model.Agent, error) {
+	if c.started {
+		c.listedAfterStart = true
+	} else {
+		c.listedBeforeStart = true
+	}
+	return c.mockClient.Agents(ctx, options...)
+}
+
+func (c *watchStartClient) Watch(ctx context.Context, kind model.Kind, name string, selector string, handler func([]*model.WatchEvent) error) error {
+	c.started = true
+	return handler(nil)
+}
+
 func executeAndAssertOutput(t *testing.T, cmd *cobra.Command, buffer *bytes.Buffer, expected string) {
 	executeErr := cmd.Execute()
 	require.NoError(t, executeErr, "error while executing command")
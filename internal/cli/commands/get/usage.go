@@ -0,0 +1,62 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package get
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/observiq/bindplane-op/internal/cli"
+	"github.com/observiq/bindplane-op/internal/cli/printer"
+	"github.com/observiq/bindplane-op/model"
+)
+
+// UsageCommand returns the BindPlane get usage cobra command
+func UsageCommand(bindplane *cli.BindPlane) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "usage <kind> <name>",
+		Short: "Displays the resources and agents that use a resource",
+		Long: `Displays the resources that use a configuration, source, source-type, processor, processor-type, destination,
+or destination-type, directly or transitively, and the agents with a configuration that uses it.`,
+		Example: "  bindplanectl get usage destination my-destination",
+		Args:    cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if watching(cmd) {
+				return errWatchNotSupported(cmd)
+			}
+
+			kind := model.ParseKind(strings.ReplaceAll(args[0], "-", ""))
+			if kind == model.KindUnknown {
+				return fmt.Errorf("%s is not a valid resource kind", args[0])
+			}
+
+			c, err := bindplane.Client()
+			if err != nil {
+				return fmt.Errorf("error creating client: %w", err)
+			}
+
+			usage, err := c.ResourceUsage(cmd.Context(), kind, args[1])
+			if err != nil {
+				return err
+			}
+
+			printer.PrintResource(bindplane.Printer(), usage)
+			return nil
+		},
+	}
+	return cmd
+}
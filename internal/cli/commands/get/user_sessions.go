@@ -0,0 +1,52 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package get
+
+import (
+	"context"
+
+	"github.com/observiq/bindplane-op/client"
+	"github.com/observiq/bindplane-op/internal/cli"
+	"github.com/observiq/bindplane-op/model"
+	"github.com/spf13/cobra"
+)
+
+// UserSessionsCommand returns the BindPlane get sessions cobra command
+func UserSessionsCommand(bindplane *cli.BindPlane) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "sessions [id]",
+		Aliases: []string{"session"},
+		Short:   "Displays the sessions of users logged in to the UI",
+		Long:    `Displays the active sessions of users logged in to the UI. Requires the admin role.`,
+		RunE: getImpl(bindplane, "session", getter[*model.UserSession]{
+			one: func(ctx context.Context, client client.BindPlane, id string) (*model.UserSession, bool, error) {
+				sessions, err := client.UserSessions(ctx)
+				if err != nil {
+					return nil, false, err
+				}
+				for _, session := range sessions {
+					if session.ID == id {
+						return session, true, nil
+					}
+				}
+				return nil, false, nil
+			},
+			all: func(ctx context.Context, client client.BindPlane) ([]*model.UserSession, error) {
+				return client.UserSessions(ctx)
+			},
+		}),
+	}
+	return cmd
+}
@@ -0,0 +1,135 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package get
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/spf13/cobra"
+
+	"github.com/observiq/bindplane-op/client"
+	"github.com/observiq/bindplane-op/internal/cli"
+	"github.com/observiq/bindplane-op/internal/cli/printer"
+	"github.com/observiq/bindplane-op/model"
+)
+
+// watchFlag is the persistent flag of the get command used to watch for changes after printing resources
+const watchFlag = "watch"
+
+// watching returns true if --watch was specified
+func watching(cmd *cobra.Command) bool {
+	watch, _ := cmd.Flags().GetBool(watchFlag)
+	return watch
+}
+
+// errWatchNotSupported returns the error for commands that can't watch for changes
+func errWatchNotSupported(cmd *cobra.Command) error {
+	return fmt.Errorf("get %s does not support --%s", cmd.Name(), watchFlag)
+}
+
+// watchImpl prints the items returned by list as insert events followed by changes to resources of the kind until the
+// command is interrupted. The name and selector are optional and limit the resources watched. The items are listed
+// after the watch has started so that changes made while listing are printed after the items instead of being lost.
+func watchImpl[T model.Printable](cmd *cobra.Command, bindplane *cli.BindPlane, kind model.Kind, name, selector string, list func(ctx context.Context) ([]T, error)) error {
+	if kind == "" {
+		return errWatchNotSupported(cmd)
+	}
+
+	c, err := bindplane.Client()
+	if err != nil {
+		return fmt.Errorf("error creating client: %w", err)
+	}
+
+	p := bindplane.Printer()
+
+	listed := false
+	return c.Watch(cmd.Context(), kind, name, selector, func(events []*model.WatchEvent) error {
+		// the first batch is sent when the watch has started, changes are buffered by the connection while listing
+		if !listed {
+			listed = true
+			items, err := list(cmd.Context())
+			if err != nil {
+				return err
+			}
+			printInsertEvents(p, kind, items)
+		}
+		if len(events) == 0 {
+			return nil
+		}
+
+		printables := make([]model.Printable, 0, len(events))
+		for _, event := range events {
+			item, err := decodeWatchEvent[T](event)
+			if err != nil {
+				return err
+			}
+			printables = append(printables, item)
+		}
+		p.PrintWatchEvents(events, printables)
+		return nil
+	})
+}
+
+// printInsertEvents prints the items as insert events
+func printInsertEvents[T model.Printable](p printer.Printer, kind model.Kind, items []T) {
+	if len(items) == 0 {
+		return
+	}
+	events := make([]*model.WatchEvent, len(items))
+	printables := make([]model.Printable, len(items))
+	for i, item := range items {
+		events[i] = &model.WatchEvent{Type: model.WatchEventInsert, Kind: kind}
+		if keyed, ok := any(item).(model.HasUniqueKey); ok {
+			events[i].Name = keyed.UniqueKey()
+		}
+		if resource, err := json.Marshal(item); err == nil {
+			events[i].Resource = resource
+		}
+		printables[i] = item
+	}
+	p.PrintWatchEvents(events, printables)
+}
+
+// listOne returns a list function for watchImpl that gets the resource with the specified name. Nothing is listed if
+// the resource doesn't exist.
+func listOne[T model.Printable](get func(ctx context.Context, name string) (T, error), name string) func(ctx context.Context) ([]T, error) {
+	return func(ctx context.Context) ([]T, error) {
+		item, err := get(ctx, name)
+		if err != nil || reflect.ValueOf(item).IsNil() {
+			return nil, err
+		}
+		return []T{item}, nil
+	}
+}
+
+// listAll returns a list function for watchImpl that lists the resources with the specified options
+func listAll[T model.Printable](list func(ctx context.Context, options ...client.QueryOption) ([]T, error), options ...client.QueryOption) func(ctx context.Context) ([]T, error) {
+	return func(ctx context.Context) ([]T, error) {
+		return list(ctx, options...)
+	}
+}
+
+// decodeWatchEvent decodes the resource of the event. T must be a pointer to a struct.
+func decodeWatchEvent[T model.Printable](event *model.WatchEvent) (T, error) {
+	var item T
+	item = reflect.New(reflect.TypeOf(item).Elem()).Interface().(T)
+	if err := json.Unmarshal(event.Resource, item); err != nil {
+		return item, fmt.Errorf("unable to decode %s %s: %w", event.Kind, event.Name, err)
+	}
+	return item, nil
+}
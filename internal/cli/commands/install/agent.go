@@ -25,11 +25,12 @@ import (
 )
 
 var (
-	platformFlag  string
-	versionFlag   string
-	labelsFlag    string
-	secretKeyFlag string
-	remoteURLFlag string
+	platformFlag        string
+	versionFlag         string
+	labelsFlag          string
+	secretKeyFlag       string
+	remoteURLFlag       string
+	enrollmentTokenFlag string
 )
 
 // AgentCommand returns the BindPlane install agent cobra command
@@ -45,11 +46,12 @@ func AgentCommand(bindplane *cli.BindPlane) *cobra.Command {
 			}
 
 			command, err := c.AgentInstallCommand(cmd.Context(), client.AgentInstallOptions{
-				Version:   versionFlag,
-				Labels:    labelsFlag,
-				Platform:  platformFlag,
-				SecretKey: secretKeyFlag,
-				RemoteURL: remoteURLFlag,
+				Version:         versionFlag,
+				Labels:          labelsFlag,
+				Platform:        platformFlag,
+				SecretKey:       secretKeyFlag,
+				RemoteURL:       remoteURLFlag,
+				EnrollmentToken: enrollmentTokenFlag,
 			})
 			if err != nil {
 				return err
@@ -76,6 +78,7 @@ func AgentCommand(bindplane *cli.BindPlane) *cobra.Command {
 	cmd.Flags().StringVar(&versionFlag, "version", "latest", "version of the agent to install")
 	cmd.Flags().StringVar(&labelsFlag, "labels", "", "labels to apply to the new agent")
 	cmd.Flags().StringVar(&secretKeyFlag, "secret-key", "", "secret-key to assign to the agent")
+	cmd.Flags().StringVar(&enrollmentTokenFlag, "enrollment-token", "", "enrollment-token used by the agent instead of the secret-key, see bindplanectl create enrollment-token")
 	cmd.Flags().StringVar(&remoteURLFlag, "remote-url", "", "websocket address of the BindPlane agent management platform")
 
 	return cmd
@@ -0,0 +1,187 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package login
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/observiq/bindplane-op/internal/cli"
+	"github.com/observiq/bindplane-op/internal/cli/commands/profile"
+	"github.com/observiq/bindplane-op/model"
+)
+
+const deviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+// Command returns the BindPlane login cobra command
+func Command(bindplane *cli.BindPlane, h profile.Helper) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "login",
+		Short: "Log in with single sign-on",
+		Long: `Logs in with the OpenID Connect provider configured on the server using the device authorization flow. The
+ID token is saved to the current profile and used instead of the username and password until it expires.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if bindplane.ProfileName == "" {
+				return errors.New("login requires a current profile, use bindplanectl profile use <name>")
+			}
+
+			c, err := bindplane.Client()
+			if err != nil {
+				return fmt.Errorf("error creating client: %w", err)
+			}
+
+			config, err := c.OIDCConfig(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			token, err := deviceLogin(cmd.Context(), http.DefaultClient, config, func(verificationURI, userCode string) {
+				fmt.Fprintf(out, "To log in, open %s and enter the code %s\n", verificationURI, userCode)
+			})
+			if err != nil {
+				return err
+			}
+
+			err = h.Folder().UpsertProfile(bindplane.ProfileName, func(p *model.Profile) error {
+				p.Spec.Token = token
+				return nil
+			})
+			if err != nil {
+				return fmt.Errorf("failed to save the token to profile %s: %w", bindplane.ProfileName, err)
+			}
+
+			fmt.Fprintf(out, "Logged in, token saved to profile %s\n", bindplane.ProfileName)
+			return nil
+		},
+	}
+	return cmd
+}
+
+// ----------------------------------------------------------------------
+// device authorization flow, RFC 8628
+
+type deviceAuthorizationResponse struct {
+	DeviceCode      string `json:"device_code"`
+	UserCode        string `json:"user_code"`
+	VerificationURI string `json:"verification_uri"`
+	ExpiresIn       int    `json:"expires_in"`
+	Interval        int    `json:"interval"`
+}
+
+type tokenResponse struct {
+	IDToken string `json:"id_token"`
+	Error   string `json:"error"`
+}
+
+// deviceLogin starts a device authorization, calls prompt with the URI and code the user enters, and polls the token
+// endpoint until the user completes login. It returns the ID token.
+func deviceLogin(ctx context.Context, client *http.Client, config *model.OIDCConfigResponse, prompt func(verificationURI, userCode string)) (string, error) {
+	if config.DeviceAuthorizationEndpoint == "" {
+		return "", errors.New("the single sign-on provider does not support the device authorization flow")
+	}
+
+	var device deviceAuthorizationResponse
+	status, err := postForm(ctx, client, config.DeviceAuthorizationEndpoint, url.Values{
+		"client_id": {config.ClientID},
+		"scope":     {strings.Join(config.Scopes, " ")},
+	}, &device)
+	if err != nil {
+		return "", fmt.Errorf("failed to start device authorization: %w", err)
+	}
+	if status != http.StatusOK || device.DeviceCode == "" {
+		return "", fmt.Errorf("failed to start device authorization, got status %d", status)
+	}
+
+	prompt(device.VerificationURI, device.UserCode)
+
+	interval := time.Duration(device.Interval) * time.Second
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	expiresIn := time.Duration(device.ExpiresIn) * time.Second
+	if expiresIn <= 0 {
+		expiresIn = 10 * time.Minute
+	}
+	ctx, cancel := context.WithTimeout(ctx, expiresIn)
+	defer cancel()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return "", errors.New("login expired before it was completed")
+			}
+			return "", ctx.Err()
+		case <-time.After(interval):
+		}
+
+		var token tokenResponse
+		_, err := postForm(ctx, client, config.TokenEndpoint, url.Values{
+			"grant_type":  {deviceCodeGrantType},
+			"device_code": {device.DeviceCode},
+			"client_id":   {config.ClientID},
+		}, &token)
+		if err != nil {
+			return "", fmt.Errorf("failed to request token: %w", err)
+		}
+
+		switch token.Error {
+		case "":
+			if token.IDToken == "" {
+				return "", errors.New("token response did not contain an id_token")
+			}
+			return token.IDToken, nil
+		case "authorization_pending":
+		case "slow_down":
+			interval += 5 * time.Second
+		default:
+			return "", fmt.Errorf("login failed: %s", token.Error)
+		}
+	}
+}
+
+func postForm(ctx context.Context, client *http.Client, endpoint string, values url.Values, result any) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(values.Encode()))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, err
+	}
+	if err := json.Unmarshal(body, result); err != nil {
+		return resp.StatusCode, fmt.Errorf("failed to parse response, got status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
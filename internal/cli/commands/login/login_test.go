@@ -0,0 +1,72 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package login
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/observiq/bindplane-op/common"
+	"github.com/observiq/bindplane-op/internal/server/oidc"
+	"github.com/observiq/bindplane-op/internal/server/oidc/oidctest"
+	"github.com/observiq/bindplane-op/model"
+)
+
+func TestDeviceLogin(t *testing.T) {
+	idp := oidctest.New(t, oidctest.Claims{Subject: "user-1", Groups: []string{"ops"}})
+	ctx := context.Background()
+
+	provider, err := oidc.NewProvider(ctx, &common.OIDC{
+		Issuer:      idp.Issuer(),
+		ClientID:    "bindplane-ui",
+		CLIClientID: "bindplane-cli",
+		GroupRoles:  map[string]string{"ops": "admin"},
+	}, "")
+	require.NoError(t, err)
+
+	t.Run("logs in", func(t *testing.T) {
+		var prompted bool
+		token, err := deviceLogin(ctx, http.DefaultClient, provider.ClientConfig(), func(verificationURI, userCode string) {
+			require.Equal(t, idp.Issuer()+"/activate", verificationURI)
+			prompted = true
+			idp.ApproveDevice(userCode)
+		})
+		require.NoError(t, err)
+		require.True(t, prompted)
+
+		// the token is accepted by the server
+		identity, err := provider.Verify(ctx, token)
+		require.NoError(t, err)
+		require.Equal(t, "user-1", identity.Subject)
+		require.Equal(t, model.RoleAdmin, identity.Role)
+	})
+
+	t.Run("device flow not supported", func(t *testing.T) {
+		config := provider.ClientConfig()
+		config.DeviceAuthorizationEndpoint = ""
+
+		_, err := deviceLogin(ctx, http.DefaultClient, config, func(string, string) {})
+		require.ErrorContains(t, err, "does not support the device authorization flow")
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		_, err := deviceLogin(ctx, http.DefaultClient, provider.ClientConfig(), func(string, string) { cancel() })
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 
+	"github.com/observiq/bindplane-op/common"
 	"github.com/observiq/bindplane-op/internal/agent"
 	"github.com/observiq/bindplane-op/internal/cli/flags"
 	"github.com/observiq/bindplane-op/model"
@@ -165,6 +166,59 @@ func registerModifiers() *profileSettingModifiers {
 		return nil
 	})
 
+	p.register("disable-secret-key-after-enrollment", func(name string, f *pflag.Flag, profile *model.Profile) error {
+		profile.Spec.Server.DisableSecretKeyAfterEnrollment = f.Value.String() == "true"
+		return nil
+	})
+
+	p.register("require-enrollment-token", func(name string, f *pflag.Flag, profile *model.Profile) error {
+		profile.Spec.Server.RequireEnrollmentToken = f.Value.String() == "true"
+		return nil
+	})
+
+	p.register("require-approval", func(name string, f *pflag.Flag, profile *model.Profile) error {
+		profile.Spec.Server.RequireApproval = f.Value.String() == "true"
+		return nil
+	})
+
+	p.register("disconnected-agent-ttl", func(name string, f *pflag.Flag, profile *model.Profile) error {
+		duration, err := time.ParseDuration(f.Value.String())
+		if err != nil {
+			return fmt.Errorf("failed to set disconnected-agent-ttl, must be a valid duration: %s", err.Error())
+		}
+		profile.Spec.Server.DisconnectedAgentTTL = duration
+		return nil
+	})
+
+	p.register("agent-ca", func(name string, f *pflag.Flag, profile *model.Profile) error {
+		profile.Spec.Server.AgentCAConfig().Enabled = f.Value.String() == "true"
+		return nil
+	})
+
+	p.register("agent-ca-cert", func(name string, f *pflag.Flag, profile *model.Profile) error {
+		profile.Spec.Server.AgentCAConfig().Certificate = f.Value.String()
+		return nil
+	})
+
+	p.register("agent-ca-key", func(name string, f *pflag.Flag, profile *model.Profile) error {
+		profile.Spec.Server.AgentCAConfig().PrivateKey = f.Value.String()
+		return nil
+	})
+
+	p.register("agent-certificate-ttl", func(name string, f *pflag.Flag, profile *model.Profile) error {
+		duration, err := time.ParseDuration(f.Value.String())
+		if err != nil {
+			return fmt.Errorf("failed to set agent-certificate-ttl, must be a valid duration: %s", err.Error())
+		}
+		profile.Spec.Server.AgentCAConfig().CertificateTTL = duration
+		return nil
+	})
+
+	p.register("require-agent-certificate", func(name string, f *pflag.Flag, profile *model.Profile) error {
+		profile.Spec.Server.AgentCAConfig().RequireCertificate = f.Value.String() == "true"
+		return nil
+	})
+
 	p.register("sync-agent-versions-interval", func(name string, f *pflag.Flag, profile *model.Profile) error {
 		duration, err := time.ParseDuration(f.Value.String())
 		if err != nil {
@@ -177,6 +231,54 @@ func registerModifiers() *profileSettingModifiers {
 		return nil
 	})
 
+	p.register("resource-type-dirs", func(name string, f *pflag.Flag, profile *model.Profile) error {
+		stringValue := f.Value.String() // StringSlice looks like `"[one,two]"
+		profile.Spec.Server.ResourceTypeDirs = strings.Split(stringValue[1:len(stringValue)-1], ",")
+		return nil
+	})
+
+	p.register("resource-type-catalog-url", func(name string, f *pflag.Flag, profile *model.Profile) error {
+		profile.Spec.Server.ResourceTypeCatalogURL = f.Value.String()
+		return nil
+	})
+
+	p.register("resource-type-sync-interval", func(name string, f *pflag.Flag, profile *model.Profile) error {
+		duration, err := time.ParseDuration(f.Value.String())
+		if err != nil {
+			return fmt.Errorf("failed to set resource-type-sync-interval, must be a valid duration: %s", err.Error())
+		}
+		profile.Spec.Server.ResourceTypeSyncInterval = duration
+		return nil
+	})
+
+	p.register("resource-type-conflicts", func(name string, f *pflag.Flag, profile *model.Profile) error {
+		profile.Spec.Server.ResourceTypeConflicts = f.Value.String()
+		return nil
+	})
+
+	p.register("event-bus-type", func(name string, f *pflag.Flag, profile *model.Profile) error {
+		profile.Spec.Server.EventBusConfig().Type = f.Value.String()
+		return nil
+	})
+
+	p.register("nats-url", func(name string, f *pflag.Flag, profile *model.Profile) error {
+		eventBus := profile.Spec.Server.EventBusConfig()
+		if eventBus.NATS == nil {
+			eventBus.NATS = &common.NATS{}
+		}
+		eventBus.NATS.URL = f.Value.String()
+		return nil
+	})
+
+	p.register("redis-address", func(name string, f *pflag.Flag, profile *model.Profile) error {
+		eventBus := profile.Spec.Server.EventBusConfig()
+		if eventBus.Redis == nil {
+			eventBus.Redis = &common.Redis{}
+		}
+		eventBus.Redis.Address = f.Value.String()
+		return nil
+	})
+
 	p.register("sessions-secret", func(name string, f *pflag.Flag, profile *model.Profile) error {
 		// Try to enforce it as a UUID
 		_, err := uuid.Parse(f.Value.String())
@@ -187,6 +289,78 @@ func registerModifiers() *profileSettingModifiers {
 		return nil
 	})
 
+	p.register("oidc-issuer", func(name string, f *pflag.Flag, profile *model.Profile) error {
+		profile.Spec.Server.OIDCConfig().Issuer = f.Value.String()
+		return nil
+	})
+
+	p.register("oidc-client-id", func(name string, f *pflag.Flag, profile *model.Profile) error {
+		profile.Spec.Server.OIDCConfig().ClientID = f.Value.String()
+		return nil
+	})
+
+	p.register("oidc-client-secret", func(name string, f *pflag.Flag, profile *model.Profile) error {
+		profile.Spec.Server.OIDCConfig().ClientSecret = f.Value.String()
+		return nil
+	})
+
+	p.register("oidc-cli-client-id", func(name string, f *pflag.Flag, profile *model.Profile) error {
+		profile.Spec.Server.OIDCConfig().CLIClientID = f.Value.String()
+		return nil
+	})
+
+	p.register("session-timeout", func(name string, f *pflag.Flag, profile *model.Profile) error {
+		duration, err := time.ParseDuration(f.Value.String())
+		if err != nil {
+			return fmt.Errorf("failed to set session-timeout, must be a valid duration: %s", err.Error())
+		}
+		profile.Spec.Server.SessionTimeout = duration
+		return nil
+	})
+
+	p.register("session-idle-timeout", func(name string, f *pflag.Flag, profile *model.Profile) error {
+		duration, err := time.ParseDuration(f.Value.String())
+		if err != nil {
+			return fmt.Errorf("failed to set session-idle-timeout, must be a valid duration: %s", err.Error())
+		}
+		profile.Spec.Server.SessionIdleTimeout = duration
+		return nil
+	})
+
+	p.register("oidc-default-role", func(name string, f *pflag.Flag, profile *model.Profile) error {
+		profile.Spec.Server.OIDCConfig().DefaultRole = f.Value.String()
+		return nil
+	})
+
+	p.register("gitops-repository", func(name string, f *pflag.Flag, profile *model.Profile) error {
+		profile.Spec.Server.GitOpsConfig().Repository = f.Value.String()
+		return nil
+	})
+
+	p.register("gitops-branch", func(name string, f *pflag.Flag, profile *model.Profile) error {
+		profile.Spec.Server.GitOpsConfig().Branch = f.Value.String()
+		return nil
+	})
+
+	p.register("gitops-path", func(name string, f *pflag.Flag, profile *model.Profile) error {
+		profile.Spec.Server.GitOpsConfig().Path = f.Value.String()
+		return nil
+	})
+
+	p.register("gitops-interval", func(name string, f *pflag.Flag, profile *model.Profile) error {
+		duration, err := time.ParseDuration(f.Value.String())
+		if err != nil {
+			return fmt.Errorf("failed to set gitops-interval, must be a valid duration: %s", err.Error())
+		}
+		profile.Spec.Server.GitOpsConfig().Interval = duration
+		return nil
+	})
+
+	p.register("gitops-prune", func(name string, f *pflag.Flag, profile *model.Profile) error {
+		profile.Spec.Server.GitOpsConfig().Prune = f.Value.String() == "true"
+		return nil
+	})
+
 	return p
 }
 
@@ -0,0 +1,55 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resolve
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/observiq/bindplane-op/internal/cli"
+)
+
+// ConflictCommand returns the BindPlane resolve conflict cobra command
+func ConflictCommand(bindplane *cli.BindPlane) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "conflict <agent-id>...",
+		Aliases: []string{"conflicts", "agent-conflict"},
+		Short:   "Assigns new IDs to hosts conflicting with agents",
+		Long: `Hosts cloned from an image that includes the agent configuration connect with the ID of the original agent and
+are given the Conflict status. Resolving the conflict assigns a new ID to each conflicting host so that it is managed
+as a separate agent.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return fmt.Errorf("id of the agent must be specified")
+			}
+
+			c, err := bindplane.Client()
+			if err != nil {
+				return fmt.Errorf("error creating client: %w", err)
+			}
+
+			for _, id := range args {
+				if err := c.ResolveAgentConflict(cmd.Context(), id); err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Assigning new IDs to hosts conflicting with agent '%s'\n", id)
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,54 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package revoke
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/observiq/bindplane-op/internal/cli"
+)
+
+// CertificateCommand returns the BindPlane revoke certificate cobra command
+func CertificateCommand(bindplane *cli.BindPlane) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "certificate <agent-id>...",
+		Aliases: []string{"certificates", "cert"},
+		Short:   "Revokes the client certificates issued to agents",
+		Long: `Revokes the client certificates issued to agents by the agent CA. Agents connecting with a revoked certificate
+are rejected. Connected agents are sent a new credential and certificate.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return fmt.Errorf("id of the agent must be specified")
+			}
+
+			c, err := bindplane.Client()
+			if err != nil {
+				return fmt.Errorf("error creating client: %w", err)
+			}
+
+			for _, id := range args {
+				if err := c.RevokeAgentCertificate(cmd.Context(), id); err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Revoked the certificate of agent '%s'\n", id)
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,71 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package revoke
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/observiq/bindplane-op/internal/cli"
+)
+
+// SessionCommand returns the BindPlane revoke session cobra command
+func SessionCommand(bindplane *cli.BindPlane) *cobra.Command {
+	var user string
+
+	cmd := &cobra.Command{
+		Use:     "session <session-id>...",
+		Aliases: []string{"sessions"},
+		Short:   "Revokes the sessions of users logged in to the UI",
+		Long: `Revokes the sessions of users logged in to the UI. The user must log in again. Specify the IDs of the sessions
+to revoke or use --user to revoke every session of a user. Requires the admin role.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 && user == "" {
+				return errors.New("id of the session or --user must be specified")
+			}
+			if len(args) > 0 && user != "" {
+				return errors.New("session ids and --user cannot both be specified")
+			}
+
+			c, err := bindplane.Client()
+			if err != nil {
+				return fmt.Errorf("error creating client: %w", err)
+			}
+
+			if user != "" {
+				revoked, err := c.RevokeUserSessions(cmd.Context(), user)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Revoked %d sessions of user '%s'\n", len(revoked), user)
+				return nil
+			}
+
+			for _, id := range args {
+				if err := c.RevokeUserSession(cmd.Context(), id); err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Revoked session '%s'\n", id)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&user, "user", "", "revoke every session of the user")
+
+	return cmd
+}
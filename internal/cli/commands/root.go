@@ -17,6 +17,7 @@ package commands
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"runtime"
 	"time"
@@ -33,6 +34,7 @@ import (
 	"github.com/observiq/bindplane-op/internal/cli"
 	"github.com/observiq/bindplane-op/internal/cli/commands/profile"
 	"github.com/observiq/bindplane-op/internal/cli/flags"
+	"github.com/observiq/bindplane-op/internal/cli/printer"
 	bptrace "github.com/observiq/bindplane-op/internal/trace"
 	v "github.com/observiq/bindplane-op/internal/version"
 	oteltrace "go.opentelemetry.io/otel/sdk/trace"
@@ -74,6 +76,11 @@ func Command(bindplane *cli.BindPlane, name string) *cobra.Command {
 				return fmt.Errorf("error while trying to unmarshal configuration, %w", err)
 			}
 
+			// validate the output options before running the command
+			if _, err := printer.NewPrinter(io.Discard, zap.NewNop(), bindplane.PrinterOptions()); err != nil {
+				return err
+			}
+
 			err = initTracing(bindplane)
 			if err != nil {
 				bindplane.Logger().Sugar().Warnf("continuing without tracing: %v", err)
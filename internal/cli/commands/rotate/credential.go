@@ -0,0 +1,55 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rotate
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/observiq/bindplane-op/internal/cli"
+)
+
+// CredentialCommand returns the BindPlane rotate credential cobra command
+func CredentialCommand(bindplane *cli.BindPlane) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "credential <agent-id>...",
+		Aliases: []string{"credentials", "agent-credential"},
+		Short:   "Issues a new credential to connected agents",
+		Long: `Agents that accept OpAMP connection settings are issued a credential when they first connect. Rotating the
+credential issues a new one without reinstalling the agent. The previous credential is accepted until the agent
+reconnects with the new credential.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return fmt.Errorf("id of the agent must be specified")
+			}
+
+			c, err := bindplane.Client()
+			if err != nil {
+				return fmt.Errorf("error creating client: %w", err)
+			}
+
+			for _, id := range args {
+				if err := c.RotateAgentCredential(cmd.Context(), id); err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Rotating credential of agent '%s'\n", id)
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -21,9 +21,12 @@ import (
 	"io/ioutil"
 
 	"github.com/observiq/bindplane-op/common"
+	"github.com/observiq/bindplane-op/internal/agentca"
 )
 
-func configureTLS(config *common.Server) (*tls.Config, error) {
+// configureTLS returns the TLS configuration of the server. If agentCA is not nil, client certificates issued by the
+// agent CA are also accepted.
+func configureTLS(config *common.Server, agentCA *agentca.Authority) (*tls.Config, error) {
 	keyPair, err := tls.LoadX509KeyPair(config.Certificate, config.PrivateKey)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load tls certificate: %w", err)
@@ -41,9 +44,23 @@ func configureTLS(config *common.Server) (*tls.Config, error) {
 		}
 	}
 
+	if agentCA != nil {
+		configureAgentCA(&tlsConfig, agentCA)
+	}
+
 	return &tlsConfig, nil
 }
 
+// configureAgentCA trusts client certificates issued by the agent CA. Unless mTLS is required by tlsCa, clients are not
+// required to present a certificate. The certificate presented by an agent is bound to its ID when it connects.
+func configureAgentCA(config *tls.Config, agentCA *agentca.Authority) {
+	if config.ClientCAs == nil {
+		config.ClientCAs = x509.NewCertPool()
+		config.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+	config.ClientCAs.AppendCertsFromPEM(agentCA.CertificatePEM())
+}
+
 func configureMutualTLS(config *tls.Config, caFile []string) error {
 	var caPool = x509.NewCertPool()
 
@@ -18,9 +18,12 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"io/ioutil"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/observiq/bindplane-op/common"
+	"github.com/observiq/bindplane-op/internal/agentca"
 	"github.com/stretchr/testify/require"
 )
 
@@ -173,7 +176,7 @@ func Test_configureTLS(t *testing.T) {
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			output, err := configureTLS(tc.serverConf)
+			output, err := configureTLS(tc.serverConf, nil)
 
 			if tc.errSubStr != "" {
 				require.Error(t, err)
@@ -195,3 +198,27 @@ func Test_configureTLS(t *testing.T) {
 		})
 	}
 }
+
+func Test_configureTLSAgentCA(t *testing.T) {
+	dir := t.TempDir()
+	authority, err := agentca.Load(filepath.Join(dir, "agent-ca.crt"), filepath.Join(dir, "agent-ca.key"), time.Hour)
+	require.NoError(t, err)
+
+	tlsConfig := common.TLSConfig{
+		Certificate: "testdata/bindplane.crt",
+		PrivateKey:  "testdata/bindplane.key",
+	}
+
+	// client certificates are optional
+	output, err := configureTLS(&common.Server{Common: common.Common{TLSConfig: tlsConfig}}, authority)
+	require.NoError(t, err)
+	require.Equal(t, tls.VerifyClientCertIfGiven, output.ClientAuth)
+	require.Len(t, output.ClientCAs.Subjects(), 1)
+
+	// client certificates are still required with mTLS
+	tlsConfig.CertificateAuthority = []string{"testdata/bindplane-ca.crt"}
+	output, err = configureTLS(&common.Server{Common: common.Common{TLSConfig: tlsConfig}}, authority)
+	require.NoError(t, err)
+	require.Equal(t, tls.RequireAndVerifyClientCert, output.ClientAuth)
+	require.Len(t, output.ClientCAs.Subjects(), 2)
+}
@@ -26,9 +26,11 @@ import (
 
 	ginzap "github.com/gin-contrib/zap"
 	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
 	"github.com/google/uuid"
 	"github.com/hashicorp/go-multierror"
 	cors "github.com/itsjamie/gin-cors"
+	"github.com/nats-io/nats.go"
 	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 	"go.uber.org/zap"
 
@@ -37,11 +39,14 @@ import (
 	"github.com/observiq/bindplane-op/internal/agent"
 	"github.com/observiq/bindplane-op/internal/cli"
 	"github.com/observiq/bindplane-op/internal/cli/commands/profile"
+	"github.com/observiq/bindplane-op/internal/cluster"
+	"github.com/observiq/bindplane-op/internal/gitops"
 	"github.com/observiq/bindplane-op/internal/graphql"
 	"github.com/observiq/bindplane-op/internal/opamp"
 	"github.com/observiq/bindplane-op/internal/rest"
 	"github.com/observiq/bindplane-op/internal/server"
 	"github.com/observiq/bindplane-op/internal/server/auth"
+	"github.com/observiq/bindplane-op/internal/server/oidc"
 	"github.com/observiq/bindplane-op/internal/server/sessions"
 	"github.com/observiq/bindplane-op/internal/store"
 	"github.com/observiq/bindplane-op/internal/store/search"
@@ -52,6 +57,10 @@ import (
 type Server struct {
 	logger *zap.Logger
 	http   *http.Server
+
+	// leader is stopped with stopLeader to release the leader lease when the server stops
+	leader     *cluster.Leader
+	stopLeader context.CancelFunc
 }
 
 // Start starts the BindPlane using the specified Config.
@@ -75,7 +84,14 @@ func (s *Server) Start(bindplane *cli.BindPlane, h profile.Helper, forceConsoleC
 		return err
 	}
 
-	// seed the store with the resourceTypes in /resources
+	// elect a leader to run singleton background jobs like agent version sync and agent cleanup
+	leader := cluster.NewLeader(cluster.LeaderLeaseName, cluster.NewNodeID(), st, cluster.DefaultLeaseTTL, s.logger.Named("leader"))
+	leaderCtx, stopLeader := context.WithCancel(context.Background())
+	s.leader, s.stopLeader = leader, stopLeader
+	leader.Start(leaderCtx)
+
+	// seed the store with the resourceTypes in /resources. seeding is idempotent, so every node seeds to ensure that the
+	// store has the resourceTypes of its version even if it isn't the leader at startup.
 	if !skipSeed {
 		err := store.Seed(st, s.logger)
 		if err != nil {
@@ -83,14 +99,24 @@ func (s *Server) Start(bindplane *cli.BindPlane, h profile.Helper, forceConsoleC
 		}
 	}
 
+	// load resourceTypes from the configured directories and catalog
+	if config.LoadResourceTypes() {
+		s.startResourceTypeLoader(config, st, leader)
+	}
+
+	// sync resources from the gitops repository after resourceTypes are loaded so that resources can use them
+	if config.GitOpsEnabled() {
+		s.startGitOpsController(config, st, leader)
+	}
+
 	// seed the search index
 	s.seedSearchIndexes(st)
 
 	// initialize the versions which provides agent versions for updates
-	versions := s.createVersions(config, st)
+	versions := s.createVersions(config, st, leader)
 
 	// initialize the server which provides access to everything
-	server, err := server.NewBindPlane(config, s.logger, st, versions)
+	server, err := server.NewBindPlane(config, s.logger, st, versions, leader)
 	if err != nil {
 		return err
 	}
@@ -113,16 +139,26 @@ func (s *Server) Start(bindplane *cli.BindPlane, h profile.Helper, forceConsoleC
 	}))
 
 	router.GET("/health", func(c *gin.Context) {
-		c.Status(http.StatusOK)
+		c.JSON(http.StatusOK, newHealthResponse(leader))
 	})
 
 	sessions.AddRoutes(router, server)
 
+	// single sign-on with an OpenID Connect provider
+	var provider *oidc.Provider
+	if config.OIDCEnabled() {
+		provider, err = oidc.NewProvider(context.Background(), config.OIDCConfig(), config.OIDCRedirectURL())
+		if err != nil {
+			return err
+		}
+		sessions.AddOIDCRoutes(router, server, provider)
+	}
+
 	v1 := router.Group("/v1")
 	v1.Use(otelgin.Middleware("bindplane"))
 
-	authv1 := v1.Group("/", auth.Chain(server)...)
-	rest.AddRestRoutes(authv1, server)
+	authv1 := v1.Group("/", auth.Chain(server, provider)...)
+	rest.AddRestRoutes(authv1.Group("/", auth.RequireWrite(), auth.RequireCSRF()), server)
 
 	graphql.AddRoutes(authv1, server)
 
@@ -150,7 +186,7 @@ func (s *Server) Start(bindplane *cli.BindPlane, h profile.Helper, forceConsoleC
 	}
 
 	if config.EnableTLS() {
-		c, err := configureTLS(config)
+		c, err := configureTLS(config, server.Manager().AgentCA())
 		if err != nil {
 			return fmt.Errorf("failed to configure tls: %w", err)
 		}
@@ -206,7 +242,17 @@ func (s *Server) stop() error {
 	timeout := 20 * time.Second
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
-	return s.http.Shutdown(ctx)
+	err := s.http.Shutdown(ctx)
+
+	// release the leader lease so that another node can become leader without waiting for it to expire
+	if s.stopLeader != nil {
+		s.stopLeader()
+		select {
+		case <-s.leader.Done():
+		case <-ctx.Done():
+		}
+	}
+	return err
 }
 
 func (s *Server) createStore(config *common.Server) (store.Store, error) {
@@ -214,16 +260,24 @@ func (s *Server) createStore(config *common.Server) (store.Store, error) {
 		return nil, errors.New("cannot create store with unset value for sessions-secret, run bindplane init server to set value")
 	}
 
+	// the bus used to send events between nodes, nil to use the default for the store
+	bus, err := s.createBus(config)
+	if err != nil {
+		return nil, err
+	}
+	options := store.Options{
+		SessionsSecret:   config.SessionsSecret,
+		MaxEventsToMerge: 100,
+		Bus:              bus,
+	}
+
 	switch config.StoreType {
 	case common.StoreTypeMap:
-		return store.NewMapStore(context.Background(), store.Options{
-			SessionsSecret:   config.SessionsSecret,
-			MaxEventsToMerge: 100,
-		}, s.logger), nil
+		return store.NewMapStore(context.Background(), options, s.logger), nil
 
 	case common.StoreTypeGoogleCloud:
 		s.logger.Info("Using Google Cloud Datastore and Pub/Sub")
-		return store.NewGoogleCloudStore(context.Background(), config, s.logger)
+		return store.NewGoogleCloudStore(context.Background(), config, options, s.logger)
 
 	default:
 		// case common.StoreTypeBbolt:
@@ -236,14 +290,54 @@ func (s *Server) createStore(config *common.Server) (store.Store, error) {
 		}
 
 		s.logger.Info("Using BBolt Storage", zap.String("storageFilePath", storageFilePath))
-		return store.NewBoltStore(context.Background(), db, store.Options{
-			SessionsSecret:   config.SessionsSecret,
-			MaxEventsToMerge: 100,
-		}, s.logger), nil
+		return store.NewBoltStore(context.Background(), db, options, s.logger), nil
+	}
+}
+
+func (s *Server) createBus(config *common.Server) (cluster.Bus, error) {
+	if config.EventBus == nil {
+		return nil, nil
+	}
+
+	switch config.EventBus.Type {
+	case "":
+		return nil, nil
+
+	case common.EventBusTypeNATS:
+		natsConfig := config.EventBus.NATS
+		if natsConfig == nil || natsConfig.URL == "" {
+			return nil, errors.New("cannot create nats event bus without a url, set eventBus.nats.url")
+		}
+		options := []nats.Option{}
+		if natsConfig.CredentialsFile != "" {
+			options = append(options, nats.UserCredentials(natsConfig.CredentialsFile))
+		}
+		s.logger.Info("Using NATS event bus", zap.String("url", natsConfig.URL))
+		return cluster.NewNATSBus(natsConfig.URL, s.logger.Named("nats"), options...)
+
+	case common.EventBusTypeRedis:
+		redisConfig := config.EventBus.Redis
+		if redisConfig == nil || redisConfig.Address == "" {
+			return nil, errors.New("cannot create redis event bus without an address, set eventBus.redis.address")
+		}
+		client := redis.NewClient(&redis.Options{
+			Addr:     redisConfig.Address,
+			Username: redisConfig.Username,
+			Password: redisConfig.Password,
+			DB:       redisConfig.DB,
+		})
+		if err := client.Ping(context.Background()).Err(); err != nil {
+			return nil, fmt.Errorf("connect to redis: %w", err)
+		}
+		s.logger.Info("Using Redis event bus", zap.String("address", redisConfig.Address))
+		return cluster.NewRedisBus(client, redisConfig.MaxLen, s.logger.Named("redis")), nil
+
+	default:
+		return nil, fmt.Errorf("unknown event bus type %s, must be one of: %s|%s", config.EventBus.Type, common.EventBusTypeNATS, common.EventBusTypeRedis)
 	}
 }
 
-func (s *Server) createVersions(config *common.Server, st store.Store) agent.Versions {
+func (s *Server) createVersions(config *common.Server, st store.Store, leadership cluster.Leadership) agent.Versions {
 	var client agent.Client
 	if !config.Offline {
 		client = agent.NewClient()
@@ -252,7 +346,57 @@ func (s *Server) createVersions(config *common.Server, st store.Store) agent.Ver
 		Logger:                    s.logger.Named("versions"),
 		SyncAgentVersionsInterval: config.SyncAgentVersionsInterval,
 		Offline:                   config.Offline,
+		Leadership:                leadership,
+	})
+}
+
+func (s *Server) startResourceTypeLoader(config *common.Server, st store.Store, leadership cluster.Leadership) {
+	// builtin resourceTypes are needed to restore them when an external resourceType is removed, even if seeding is
+	// skipped
+	builtin := store.BuiltinResourceTypes(s.logger)
+	loader := store.NewResourceTypeLoader(st, store.ResourceTypeLoaderSettings{
+		Dirs:       config.ResourceTypeDirs,
+		CatalogURL: config.ResourceTypeCatalogURL,
+		Interval:   config.ResourceTypeSyncInterval,
+		Conflicts:  store.ResourceTypeConflicts(config.ResourceTypeConflicts),
+		Builtin:    builtin,
+		Leadership: leadership,
+		Logger:     s.logger.Named("resource-types"),
 	})
+	if leadership.IsLeader() {
+		if err := loader.Sync(context.Background()); err != nil {
+			s.logger.Error("failed to load resourceTypes", zap.Error(err))
+		}
+	}
+	go loader.Start(context.Background())
+}
+
+func (s *Server) startGitOpsController(config *common.Server, st store.Store, leadership cluster.Leadership) {
+	gitOps := config.GitOpsConfig()
+	controller := gitops.NewController(st, gitops.Settings{
+		Repository: gitOps.Repository,
+		Branch:     config.GitOpsBranch(),
+		Path:       gitOps.Path,
+		Directory:  config.GitOpsDirectory(),
+		Interval:   config.GitOpsInterval(),
+		Prune:      gitOps.Prune,
+		Leadership: leadership,
+		Logger:     s.logger.Named("gitops"),
+	})
+	go controller.Start(context.Background())
+}
+
+// healthResponse is returned by /health and identifies the node and whether it is the leader of the cluster
+type healthResponse struct {
+	NodeID string `json:"nodeID"`
+	Leader bool   `json:"leader"`
+}
+
+func newHealthResponse(leadership cluster.Leadership) *healthResponse {
+	return &healthResponse{
+		NodeID: leadership.NodeID(),
+		Leader: leadership.IsLeader(),
+	}
 }
 
 func (s *Server) ensureSecretKey(config *common.Server, h profile.Helper) error {
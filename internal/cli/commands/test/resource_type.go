@@ -0,0 +1,252 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/observiq/bindplane-op/internal/cli"
+	"github.com/observiq/bindplane-op/model"
+	"github.com/observiq/bindplane-op/model/otel"
+)
+
+// ResourceTypeCases is the contents of the file specified with --cases
+type ResourceTypeCases struct {
+	Cases []ResourceTypeCase `yaml:"cases"`
+}
+
+// ResourceTypeCase is a set of parameters used to render a ResourceType and the expected result
+type ResourceTypeCase struct {
+	// Name identifies the case and is used for the default golden file name
+	Name string `yaml:"name"`
+
+	// Parameters are the parameters used to render the ResourceType. Default values are used for any parameters not
+	// specified.
+	Parameters []model.Parameter `yaml:"parameters,omitempty"`
+
+	// Golden is the path of the file containing the expected output, relative to the cases file. It defaults to
+	// golden/<name>.yaml.
+	Golden string `yaml:"golden,omitempty"`
+
+	// ExpectError is part of the error message expected when rendering fails. If specified, the case passes only if
+	// rendering fails with an error containing this message.
+	ExpectError string `yaml:"expectError,omitempty"`
+}
+
+// ErrCasesFailed is returned when one or more cases fail
+var ErrCasesFailed = errors.New("resource-type test cases failed")
+
+// ResourceTypeCommand returns the BindPlane test resource-type cobra command
+func ResourceTypeCommand(_ *cli.BindPlane) *cobra.Command {
+	var casesFile string
+	var update bool
+
+	cmd := &cobra.Command{
+		Use:   "resource-type <file.yaml>",
+		Short: "Render a ResourceType for each test case and compare with golden files",
+		Long: `Renders the SourceType, ProcessorType, or DestinationType in the file with the parameters of each case in the
+cases file. The rendered configuration for each type of telemetry is checked to ensure that it is well-formed
+OpenTelemetry configuration and compared with the golden file for the case. Use --update to write the golden files.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if casesFile == "" {
+				return errors.New("--cases must be specified")
+			}
+			resourceType, err := readResourceType(args[0])
+			if err != nil {
+				return err
+			}
+			cases, err := readResourceTypeCases(casesFile)
+			if err != nil {
+				return err
+			}
+			return runResourceTypeCases(cmd.OutOrStdout(), resourceType, cases, filepath.Dir(casesFile), update)
+		},
+	}
+
+	cmd.Flags().StringVar(&casesFile, "cases", "", "yaml file containing the test cases")
+	cmd.Flags().BoolVar(&update, "update", false, "write the rendered output to the golden files instead of comparing")
+
+	return cmd
+}
+
+func readResourceType(filename string) (model.Resource, error) {
+	resources, err := model.ResourcesFromFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", filename, err)
+	}
+	parsed, err := model.ParseResources(resources)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filename, err)
+	}
+	if len(parsed) != 1 {
+		return nil, fmt.Errorf("%s must contain exactly one ResourceType, found %d resources", filename, len(parsed))
+	}
+	resourceType := parsed[0]
+	switch resourceType.GetKind() {
+	case model.KindSourceType, model.KindProcessorType, model.KindDestinationType:
+	default:
+		return nil, fmt.Errorf("%s must contain a SourceType, ProcessorType, or DestinationType, found %s", filename, resourceType.GetKind())
+	}
+	if _, err := resourceType.Validate(); err != nil {
+		return nil, fmt.Errorf("%s %s is invalid: %w", resourceType.GetKind(), resourceType.Name(), err)
+	}
+	return resourceType, nil
+}
+
+func readResourceTypeCases(filename string) (*ResourceTypeCases, error) {
+	bytes, err := os.ReadFile(filepath.Clean(filename))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", filename, err)
+	}
+	cases := &ResourceTypeCases{}
+	if err := yaml.Unmarshal(bytes, cases); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filename, err)
+	}
+	names := map[string]struct{}{}
+	for _, c := range cases.Cases {
+		if c.Name == "" {
+			return nil, fmt.Errorf("all cases in %s must have a name", filename)
+		}
+		if _, ok := names[c.Name]; ok {
+			return nil, fmt.Errorf("%s contains more than one case named %s", filename, c.Name)
+		}
+		names[c.Name] = struct{}{}
+	}
+	return cases, nil
+}
+
+// runResourceTypeCases runs each case, printing PASS or FAIL for each, and returns ErrCasesFailed if any fail
+func runResourceTypeCases(out io.Writer, resourceType model.Resource, cases *ResourceTypeCases, dir string, update bool) error {
+	failed := 0
+	for _, c := range cases.Cases {
+		if err := runResourceTypeCase(resourceType, c, dir, update); err != nil {
+			failed++
+			fmt.Fprintf(out, "FAIL %s\n%s\n", c.Name, indent(err.Error()))
+			continue
+		}
+		fmt.Fprintf(out, "PASS %s\n", c.Name)
+	}
+	if failed > 0 {
+		fmt.Fprintf(out, "%d of %d cases failed\n", failed, len(cases.Cases))
+		return ErrCasesFailed
+	}
+	return nil
+}
+
+func runResourceTypeCase(resourceType model.Resource, c ResourceTypeCase, dir string, update bool) error {
+	partials, err := model.RenderResourceType(resourceType, resourceType.Name(), c.Parameters)
+	if c.ExpectError != "" {
+		switch {
+		case err == nil:
+			return fmt.Errorf("expected error containing %q", c.ExpectError)
+		case !strings.Contains(err.Error(), c.ExpectError):
+			return fmt.Errorf("expected error containing %q, got: %w", c.ExpectError, err)
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	actual, err := renderedYaml(partials)
+	if err != nil {
+		return err
+	}
+
+	golden := c.Golden
+	if golden == "" {
+		golden = filepath.Join("golden", c.Name+".yaml")
+	}
+	golden = filepath.Join(dir, golden)
+
+	if update {
+		if err := os.MkdirAll(filepath.Dir(golden), 0750); err != nil {
+			return err
+		}
+		return os.WriteFile(golden, []byte(actual), 0600)
+	}
+
+	expected, err := os.ReadFile(filepath.Clean(golden))
+	if err != nil {
+		return fmt.Errorf("failed to read golden file: %w", err)
+	}
+	if string(expected) == actual {
+		return nil
+	}
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(expected)),
+		B:        difflib.SplitLines(actual),
+		FromFile: golden,
+		ToFile:   "rendered",
+		Context:  3,
+	})
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("rendered output does not match golden file:\n%s", diff)
+}
+
+// renderedPartial is the yaml representation of an otel.Partial
+type renderedPartial struct {
+	Receivers  otel.ComponentList `yaml:"receivers,omitempty"`
+	Processors otel.ComponentList `yaml:"processors,omitempty"`
+	Exporters  otel.ComponentList `yaml:"exporters,omitempty"`
+	Extensions otel.ComponentList `yaml:"extensions,omitempty"`
+}
+
+// renderedYaml marshals the components rendered for each type of telemetry, omitting telemetry types without
+// components
+func renderedYaml(partials otel.Partials) (string, error) {
+	rendered := map[otel.PipelineType]renderedPartial{}
+	for pipelineType, partial := range partials {
+		if partial.Size() == 0 {
+			continue
+		}
+		rendered[pipelineType] = renderedPartial{
+			Receivers:  partial.Receivers,
+			Processors: partial.Processors,
+			Exporters:  partial.Exporters,
+			Extensions: partial.Extensions,
+		}
+	}
+	var sb strings.Builder
+	enc := yaml.NewEncoder(&sb)
+	enc.SetIndent(2)
+	if err := enc.Encode(rendered); err != nil {
+		return "", err
+	}
+	if err := enc.Close(); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+func indent(s string) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = "    " + line
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,107 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/observiq/bindplane-op/common"
+	"github.com/observiq/bindplane-op/internal/cli"
+)
+
+func executeResourceTypeCommand(t *testing.T, args ...string) (string, error) {
+	out := bytes.NewBufferString("")
+	bindplane := cli.NewBindPlane(common.InitConfig(""), out)
+	cmd := ResourceTypeCommand(bindplane)
+	cmd.SetOut(out)
+	cmd.SetErr(out)
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+// copyTestdata copies the testdata directory so that golden files can be modified
+func copyTestdata(t *testing.T) string {
+	dir := t.TempDir()
+	for _, name := range []string{"sourcetype.yaml", "cases.yaml", "golden/defaults.yaml", "golden/metrics.yaml"} {
+		contents, err := os.ReadFile(filepath.Join("testdata", name))
+		require.NoError(t, err)
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0750))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), contents, 0600))
+	}
+	return dir
+}
+
+func TestResourceTypeCommand(t *testing.T) {
+	t.Run("passes when rendered output matches the golden files", func(t *testing.T) {
+		out, err := executeResourceTypeCommand(t, "testdata/sourcetype.yaml", "--cases", "testdata/cases.yaml")
+		require.NoError(t, err)
+		require.Equal(t, "PASS defaults\nPASS metrics\nPASS interval-too-small\n", out)
+	})
+
+	t.Run("fails with a diff when rendered output does not match", func(t *testing.T) {
+		dir := copyTestdata(t)
+		golden := filepath.Join(dir, "golden", "defaults.yaml")
+		require.NoError(t, os.WriteFile(golden, []byte("logs:\n  receivers: []\n"), 0600))
+
+		out, err := executeResourceTypeCommand(t, filepath.Join(dir, "sourcetype.yaml"), "--cases", filepath.Join(dir, "cases.yaml"))
+		require.ErrorIs(t, err, ErrCasesFailed)
+		require.Contains(t, out, "FAIL defaults")
+		require.Contains(t, out, "+          - /var/log/app.log")
+		require.Contains(t, out, "PASS metrics")
+		require.Contains(t, out, "1 of 3 cases failed")
+	})
+
+	t.Run("update writes the golden files", func(t *testing.T) {
+		dir := copyTestdata(t)
+		require.NoError(t, os.RemoveAll(filepath.Join(dir, "golden")))
+
+		_, err := executeResourceTypeCommand(t, filepath.Join(dir, "sourcetype.yaml"), "--cases", filepath.Join(dir, "cases.yaml"), "--update")
+		require.NoError(t, err)
+
+		for _, name := range []string{"defaults.yaml", "metrics.yaml"} {
+			expected, err := os.ReadFile(filepath.Join("testdata", "golden", name))
+			require.NoError(t, err)
+			actual, err := os.ReadFile(filepath.Join(dir, "golden", name))
+			require.NoError(t, err)
+			require.Equal(t, string(expected), string(actual))
+		}
+	})
+
+	t.Run("fails when an expected error does not occur", func(t *testing.T) {
+		dir := copyTestdata(t)
+		cases := "cases:\n  - name: defaults\n    expectError: must be at least\n"
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "cases.yaml"), []byte(cases), 0600))
+
+		out, err := executeResourceTypeCommand(t, filepath.Join(dir, "sourcetype.yaml"), "--cases", filepath.Join(dir, "cases.yaml"))
+		require.ErrorIs(t, err, ErrCasesFailed)
+		require.Contains(t, out, `expected error containing "must be at least"`)
+	})
+
+	t.Run("requires cases", func(t *testing.T) {
+		_, err := executeResourceTypeCommand(t, "testdata/sourcetype.yaml")
+		require.EqualError(t, err, "--cases must be specified")
+	})
+
+	t.Run("requires a resource type", func(t *testing.T) {
+		_, err := executeResourceTypeCommand(t, "testdata/source.yaml", "--cases", "testdata/cases.yaml")
+		require.EqualError(t, err, "testdata/source.yaml must contain a SourceType, ProcessorType, or DestinationType, found Source")
+	})
+}
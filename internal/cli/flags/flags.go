@@ -24,7 +24,7 @@ import (
 func Global(cmd *cobra.Command) {
 	pf := newflags(cmd.PersistentFlags())
 	pf.String("env", "production", "BindPlane environment. One of test|development|production")
-	pf.StringP("output", "o", "table", "output format. One of: json|table|yaml|raw")
+	pf.StringP("output", "o", "table", "output format. One of: json|table|yaml|raw|csv|jsonpath=TEMPLATE|go-template=TEMPLATE|custom-columns=HEADER:jsonpath,...")
 	pf.String("host", "localhost", "domain on which the BindPlane server will run")
 	pf.String("port", "3001", "port on which the rest server is listening")
 	pf.String("server-url", "", "http url that clients use to connect to the server")
@@ -38,16 +38,51 @@ func Global(cmd *cobra.Command) {
 	pf.String("log-output", "", "output of the log. One of: file|stdout")
 }
 
+// Printer adds flags for commands that print lists of resources
+func Printer(cmd *cobra.Command) {
+	pf := newflags(cmd.PersistentFlags())
+	pf.String("sort-by", "", "jsonpath used to sort the resources, e.g. .metadata.name")
+	pf.Bool("no-headers", false, "omit the header of table, csv, and custom-columns output")
+}
+
 // Serve adds flags for the serve command
 func Serve(cmd *cobra.Command) {
 	f := newflags(cmd.Flags())
 	f.Bool("offline", false, "BindPlane server should be run in offline mode")
 	f.String("store-type", "", "type of store to use for storing agent status and configuration resources")
+	f.String("event-bus-type", "", "type of bus used to send events between BindPlane nodes. One of: nats|redis", withConfigFileName("eventBus.type"))
+	f.String("nats-url", "", "url of the NATS server used if event-bus-type is nats", withConfigFileName("eventBus.nats.url"))
+	f.String("redis-address", "", "address (host:port) of the Redis server used if event-bus-type is redis", withConfigFileName("eventBus.redis.address"))
 	f.String("remote-url", "", "websocket url that agents use to connect to the server")
 	f.String("secret-key", "", "secret key used by agents when connecting to the server")
+	f.Bool("disable-secret-key-after-enrollment", false, "reject agents enrolled with an enrollment token that connect with the secret key", withConfigFileName("disableSecretKeyAfterEnrollment"))
+	f.Bool("require-enrollment-token", false, "reject new agents that connect with the secret key instead of an enrollment token")
+	f.Bool("require-approval", false, "new agents must be approved before they receive configuration")
+	f.Duration("disconnected-agent-ttl", 0, "time after which disconnected agents are removed, 0 to keep disconnected agents", withConfigFileName("disconnectedAgentTTL"))
+	f.Bool("agent-ca", false, "issue client certificates to agents during enrollment and bind them to agent IDs, requires TLS", withConfigFileName("agentCA.enabled"))
+	f.String("agent-ca-cert", "", "agent CA certificate file, generated if missing, defaults to $HOME/.bindplane/agent-ca.crt", withConfigFileName("agentCA.certificate"))
+	f.String("agent-ca-key", "", "agent CA private key file, generated if missing, defaults to $HOME/.bindplane/agent-ca.key", withConfigFileName("agentCA.privateKey"))
+	f.Duration("agent-certificate-ttl", 0, "lifetime of agent certificates issued by the agent CA, defaults to 90 days", withConfigFileName("agentCA.certificateTTL"))
+	f.Bool("require-agent-certificate", false, "reject agents that have been issued a certificate but connect without one", withConfigFileName("agentCA.requireCertificate"))
 	f.String("sessions-secret", "", "secret key used to sign cookies for session authentication, must be a UUID")
+	f.Duration("session-timeout", 0, "maximum lifetime of a UI session, defaults to 12h")
+	f.Duration("session-idle-timeout", 0, "UI sessions expire after this long without activity, defaults to 1h")
+	f.String("oidc-issuer", "", "issuer URL of the OpenID Connect provider used for single sign-on", withConfigFileName("oidc.issuer"))
+	f.String("oidc-client-id", "", "ID of the OpenID Connect client used by the UI", withConfigFileName("oidc.clientID"))
+	f.String("oidc-client-secret", "", "secret of the OpenID Connect client used by the UI", withConfigFileName("oidc.clientSecret"))
+	f.String("oidc-cli-client-id", "", "ID of the OpenID Connect client used by bindplanectl login, defaults to oidc-client-id", withConfigFileName("oidc.cliClientID"))
+	f.String("oidc-default-role", "", "role of single sign-on users that are not in a mapped group. One of: admin|viewer", withConfigFileName("oidc.defaultRole"))
+	f.String("gitops-repository", "", "URL or path of a git repository to sync resources from", withConfigFileName("gitOps.repository"))
+	f.String("gitops-branch", "", "branch of the gitops repository to sync, defaults to main", withConfigFileName("gitOps.branch"))
+	f.String("gitops-path", "", "directory within the gitops repository containing resources, defaults to the root of the repository", withConfigFileName("gitOps.path"))
+	f.Duration("gitops-interval", 0, "time interval to sync the gitops repository, defaults to 1m", withConfigFileName("gitOps.interval"))
+	f.Bool("gitops-prune", false, "delete resources that were synced from the gitops repository and have been removed from it", withConfigFileName("gitOps.prune"))
 	f.String("storage-file-path", "", "full path to the desired storage file, defaults to the $HOME/.bindplane/storage")
 	f.String("downloads-folder-path", "", "full path to the downloads folder where agents are cached, defaults to $HOME/.bindplane/downloads")
 	f.Bool("disable-downloads-cache", false, "true if agent distributions should be cached")
 	f.Duration("sync-agent-versions-interval", 1*time.Hour, "time interval to sync agent-version resources from GitHub releases, 0 to disable or minimum 1h")
+	f.StringSlice("resource-type-dirs", make([]string, 0), "directories containing additional ResourceTypes to load")
+	f.String("resource-type-catalog-url", "", "url of a catalog index of additional ResourceTypes to load")
+	f.Duration("resource-type-sync-interval", 1*time.Minute, "time interval to check resource-type-dirs and resource-type-catalog-url for changes, 0 to disable")
+	f.String("resource-type-conflicts", "external", "ResourceType to use when a loaded ResourceType has the same name as a bundled ResourceType. One of: external|builtin")
 }
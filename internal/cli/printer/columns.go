@@ -0,0 +1,141 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package printer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/observiq/bindplane-op/model"
+)
+
+// customColumn is a column of CustomColumnsPrinter with the path of its values
+type customColumn struct {
+	header string
+	path   jsonPathExpr
+}
+
+// CustomColumnsPrinter prints a table with the columns specified by the user
+type CustomColumnsPrinter struct {
+	writer    io.Writer
+	logger    *zap.Logger
+	columns   []customColumn
+	noHeaders bool
+
+	// printedWatchHeader is true after the header has been printed for watch events
+	printedWatchHeader bool
+}
+
+var _ Printer = (*CustomColumnsPrinter)(nil)
+
+// NewCustomColumnsPrinter returns a new *CustomColumnsPrinter for a comma separated list of HEADER:jsonpath columns,
+// e.g. NAME:.name,VERSION:.version
+func NewCustomColumnsPrinter(writer io.Writer, logger *zap.Logger, spec string, noHeaders bool) (*CustomColumnsPrinter, error) {
+	var columns []customColumn
+	for _, column := range strings.Split(spec, ",") {
+		parts := strings.SplitN(column, ":", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid custom-columns %s, expected HEADER:jsonpath", column)
+		}
+		path, err := parseJSONPathExpr(trimBraces(parts[1]))
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, customColumn{header: parts[0], path: path})
+	}
+	return &CustomColumnsPrinter{
+		writer:    writer,
+		logger:    logger,
+		columns:   columns,
+		noHeaders: noHeaders,
+	}, nil
+}
+
+// PrintResource prints a generic model that implements the printable interface
+func (cp *CustomColumnsPrinter) PrintResource(item model.Printable) {
+	cp.PrintResources([]model.Printable{item})
+}
+
+// PrintResources prints a list of generic models that implements the printable interface
+func (cp *CustomColumnsPrinter) PrintResources(list []model.Printable) {
+	if len(list) == 0 {
+		fmt.Fprintln(cp.writer, "No matching resources found.")
+		return
+	}
+	cp.print(list, !cp.noHeaders)
+}
+
+// PrintWatchEvents prints a row for each resource. The header is only printed with the first events.
+func (cp *CustomColumnsPrinter) PrintWatchEvents(_ []*model.WatchEvent, items []model.Printable) {
+	if len(items) == 0 {
+		return
+	}
+	cp.print(items, !cp.noHeaders && !cp.printedWatchHeader)
+	cp.printedWatchHeader = true
+}
+
+func (cp *CustomColumnsPrinter) print(list []model.Printable, header bool) {
+	table := newTable(cp.writer)
+	if header {
+		headers := make([]string, len(cp.columns))
+		for i, column := range cp.columns {
+			headers[i] = column.header
+		}
+		table.SetHeader(headers)
+		// keep the headers as specified instead of converting them to uppercase
+		table.SetAutoFormatHeaders(false)
+	}
+	for _, item := range list {
+		data, err := asJSONValue(item)
+		if err != nil {
+			cp.logger.Error("could not marshal resource as json", zap.String("resource", item.PrintableKindSingular()), zap.Error(err))
+			continue
+		}
+		row := make([]string, len(cp.columns))
+		for i, column := range cp.columns {
+			row[i] = columnValue(column.path.evaluate(data, data))
+		}
+		table.Append(row)
+	}
+	table.Render()
+}
+
+// columnValue joins multiple values with commas and prints <none> if there are no values
+func columnValue(values []any) string {
+	var texts []string
+	for _, value := range values {
+		text, err := jsonPathValueString(value)
+		if err != nil || text == "" {
+			continue
+		}
+		texts = append(texts, text)
+	}
+	if len(texts) == 0 {
+		return "<none>"
+	}
+	return strings.Join(texts, ",")
+}
+
+// trimBraces allows paths to be specified as {.name} or .name
+func trimBraces(path string) string {
+	path = strings.TrimSpace(path)
+	if strings.HasPrefix(path, "{") && strings.HasSuffix(path, "}") {
+		return path[1 : len(path)-1]
+	}
+	return path
+}
@@ -0,0 +1,89 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package printer
+
+import (
+	"encoding/csv"
+	"io"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/observiq/bindplane-op/model"
+)
+
+// CSVPrinter prints the columns of the table output as comma separated values
+type CSVPrinter struct {
+	writer    io.Writer
+	logger    *zap.Logger
+	noHeaders bool
+
+	// printedWatchHeader is true after the header has been printed for watch events
+	printedWatchHeader bool
+}
+
+var _ Printer = (*CSVPrinter)(nil)
+
+// NewCSVPrinter returns a new *CSVPrinter
+func NewCSVPrinter(writer io.Writer, logger *zap.Logger, noHeaders bool) *CSVPrinter {
+	return &CSVPrinter{
+		writer:    writer,
+		logger:    logger,
+		noHeaders: noHeaders,
+	}
+}
+
+// PrintResource prints a generic model that implements the printable interface
+func (cp *CSVPrinter) PrintResource(item model.Printable) {
+	cp.PrintResources([]model.Printable{item})
+}
+
+// PrintResources prints a list of generic models that implements the printable interface. Nothing is printed if the
+// list is empty.
+func (cp *CSVPrinter) PrintResources(list []model.Printable) {
+	cp.print(list, !cp.noHeaders)
+}
+
+// PrintWatchEvents prints a row for each resource. The header is only printed with the first events.
+func (cp *CSVPrinter) PrintWatchEvents(_ []*model.WatchEvent, items []model.Printable) {
+	if len(items) == 0 {
+		return
+	}
+	cp.print(items, !cp.noHeaders && !cp.printedWatchHeader)
+	cp.printedWatchHeader = true
+}
+
+func (cp *CSVPrinter) print(list []model.Printable, header bool) {
+	if len(list) == 0 {
+		return
+	}
+	titles := list[0].PrintableFieldTitles()
+	writer := csv.NewWriter(cp.writer)
+	if header {
+		// match the uppercase header of the table output
+		headers := make([]string, len(titles))
+		for i, title := range titles {
+			headers[i] = strings.ToUpper(title)
+		}
+		_ = writer.Write(headers)
+	}
+	for _, item := range list {
+		_ = writer.Write(model.PrintableFieldValuesForTitles(item, titles))
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		cp.logger.Error("could not write resources as csv", zap.String("resource", list[0].PrintableKindPlural()), zap.Error(err))
+	}
+}
@@ -16,6 +16,7 @@ package printer
 
 import (
 	"encoding/json"
+	"fmt"
 	"io"
 
 	"go.uber.org/zap"
@@ -56,6 +57,20 @@ func (jp *JSONPrinter) PrintResources(list []model.Printable) {
 	}
 }
 
+// PrintWatchEvents prints each event as JSON on a separate line
+func (jp *JSONPrinter) PrintWatchEvents(events []*model.WatchEvent, _ []model.Printable) {
+	for _, event := range events {
+		val, err := json.Marshal(event)
+		if err != nil {
+			jp.logger.Error("could not marshal watch event as json", zap.String("resource", event.Name))
+			continue
+		}
+		if _, err := fmt.Fprintln(jp.writer, string(val)); err != nil {
+			jp.logger.Error("could not write watch event as json", zap.String("resource", event.Name))
+		}
+	}
+}
+
 func (jp *JSONPrinter) printIndentedJSONLine(resource interface{}, resourceName string) {
 	val, err := json.MarshalIndent(resource, "", "  ")
 	if err != nil {
@@ -0,0 +1,335 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package printer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// jsonPath is a template using the subset of the kubectl JSONPath syntax supported by bindplanectl. Expressions in
+// braces are evaluated against the JSON of the resources and other text is printed as is, e.g.
+//
+//	{range .items[*]}{.id}{"\t"}{.labels['bindplane/agent-os']}{"\n"}{end}
+//
+// Paths support .field, ['field'], [index], [*], and .* and are relative to the current range item, or the root if
+// they start with $. Fields that don't exist produce no output.
+type jsonPath struct {
+	nodes []jsonPathNode
+}
+
+type jsonPathNode interface{}
+
+// jsonPathText is printed as is
+type jsonPathText string
+
+// jsonPathRange executes the body for each value of the path
+type jsonPathRange struct {
+	path jsonPathExpr
+	body []jsonPathNode
+}
+
+// jsonPathExpr is a path that selects values from the JSON
+type jsonPathExpr struct {
+	fromRoot bool
+	steps    []jsonPathStep
+}
+
+type jsonPathStepType int
+
+const (
+	jsonPathStepField jsonPathStepType = iota
+	jsonPathStepIndex
+	jsonPathStepWildcard
+)
+
+type jsonPathStep struct {
+	stepType jsonPathStepType
+	field    string
+	index    int
+}
+
+// parseJSONPath parses the template. A template without braces is treated as a single expression, e.g. .name is the
+// same as {.name}.
+func parseJSONPath(template string) (*jsonPath, error) {
+	if !strings.Contains(template, "{") {
+		template = "{" + template + "}"
+	}
+
+	root := &jsonPathRange{}
+	stack := []*jsonPathRange{root}
+	for len(template) > 0 {
+		current := stack[len(stack)-1]
+
+		start := strings.IndexByte(template, '{')
+		if start < 0 {
+			current.body = append(current.body, jsonPathText(template))
+			break
+		}
+		if start > 0 {
+			current.body = append(current.body, jsonPathText(template[:start]))
+		}
+
+		end, err := actionEnd(template, start)
+		if err != nil {
+			return nil, err
+		}
+		action := strings.TrimSpace(template[start+1 : end])
+		template = template[end+1:]
+
+		switch {
+		case action == "end":
+			if len(stack) == 1 {
+				return nil, errors.New("jsonpath {end} without {range}")
+			}
+			stack = stack[:len(stack)-1]
+
+		case strings.HasPrefix(action, "range "):
+			path, err := parseJSONPathExpr(strings.TrimPrefix(action, "range "))
+			if err != nil {
+				return nil, err
+			}
+			rangeNode := &jsonPathRange{path: path}
+			current.body = append(current.body, rangeNode)
+			stack = append(stack, rangeNode)
+
+		case strings.HasPrefix(action, `"`):
+			text, err := strconv.Unquote(action)
+			if err != nil {
+				return nil, fmt.Errorf("invalid jsonpath string %s: %w", action, err)
+			}
+			current.body = append(current.body, jsonPathText(text))
+
+		default:
+			path, err := parseJSONPathExpr(action)
+			if err != nil {
+				return nil, err
+			}
+			current.body = append(current.body, path)
+		}
+	}
+	if len(stack) > 1 {
+		return nil, errors.New("jsonpath {range} without {end}")
+	}
+	return &jsonPath{nodes: root.body}, nil
+}
+
+// actionEnd returns the index of the brace that closes the action starting at start, skipping braces in strings
+func actionEnd(template string, start int) (int, error) {
+	var quote byte
+	for i := start + 1; i < len(template); i++ {
+		c := template[i]
+		switch {
+		case quote != 0 && c == '\\':
+			i++
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote != 0:
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '}':
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("unclosed jsonpath action %s", template[start:])
+}
+
+// parseJSONPathExpr parses a path like .labels['app'].items[*]
+func parseJSONPathExpr(expr string) (jsonPathExpr, error) {
+	path := jsonPathExpr{}
+	rest := strings.TrimSpace(expr)
+	switch {
+	case strings.HasPrefix(rest, "$"):
+		path.fromRoot = true
+		rest = rest[1:]
+	case strings.HasPrefix(rest, "@"):
+		rest = rest[1:]
+	}
+
+	for len(rest) > 0 {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			if strings.HasPrefix(rest, "*") {
+				path.steps = append(path.steps, jsonPathStep{stepType: jsonPathStepWildcard})
+				rest = rest[1:]
+				continue
+			}
+			n := strings.IndexAny(rest, ".[")
+			if n < 0 {
+				n = len(rest)
+			}
+			if n > 0 {
+				path.steps = append(path.steps, jsonPathStep{stepType: jsonPathStepField, field: rest[:n]})
+			}
+			rest = rest[n:]
+
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return path, fmt.Errorf("invalid jsonpath %s: missing ]", expr)
+			}
+			subscript := strings.TrimSpace(rest[1:end])
+			rest = rest[end+1:]
+			switch {
+			case subscript == "*":
+				path.steps = append(path.steps, jsonPathStep{stepType: jsonPathStepWildcard})
+			case len(subscript) >= 2 && (subscript[0] == '\'' || subscript[0] == '"') && subscript[len(subscript)-1] == subscript[0]:
+				path.steps = append(path.steps, jsonPathStep{stepType: jsonPathStepField, field: subscript[1 : len(subscript)-1]})
+			default:
+				index, err := strconv.Atoi(subscript)
+				if err != nil {
+					return path, fmt.Errorf("invalid jsonpath %s: unsupported subscript [%s]", expr, subscript)
+				}
+				path.steps = append(path.steps, jsonPathStep{stepType: jsonPathStepIndex, index: index})
+			}
+
+		default:
+			return path, fmt.Errorf("invalid jsonpath %s: unexpected %q", expr, rest[0])
+		}
+	}
+	return path, nil
+}
+
+// Execute writes the template evaluated against the data, which must be the result of unmarshaling JSON
+func (j *jsonPath) Execute(writer io.Writer, data any) error {
+	var buffer bytes.Buffer
+	if err := executeJSONPathNodes(&buffer, j.nodes, data, data); err != nil {
+		return err
+	}
+	_, err := writer.Write(buffer.Bytes())
+	return err
+}
+
+func executeJSONPathNodes(buffer *bytes.Buffer, nodes []jsonPathNode, root, current any) error {
+	for _, node := range nodes {
+		switch n := node.(type) {
+		case jsonPathText:
+			buffer.WriteString(string(n))
+
+		case jsonPathExpr:
+			for i, value := range n.evaluate(root, current) {
+				if i > 0 {
+					buffer.WriteByte(' ')
+				}
+				text, err := jsonPathValueString(value)
+				if err != nil {
+					return err
+				}
+				buffer.WriteString(text)
+			}
+
+		case *jsonPathRange:
+			values := n.path.evaluate(root, current)
+			if len(values) == 1 {
+				if list, ok := values[0].([]any); ok {
+					values = list
+				}
+			}
+			for _, value := range values {
+				if err := executeJSONPathNodes(buffer, n.body, root, value); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// evaluate returns the values selected by the path
+func (p jsonPathExpr) evaluate(root, current any) []any {
+	values := []any{current}
+	if p.fromRoot {
+		values = []any{root}
+	}
+	for _, step := range p.steps {
+		var next []any
+		for _, value := range values {
+			switch step.stepType {
+			case jsonPathStepField:
+				if m, ok := value.(map[string]any); ok {
+					if v, ok := m[step.field]; ok {
+						next = append(next, v)
+					}
+				}
+			case jsonPathStepIndex:
+				if list, ok := value.([]any); ok {
+					index := step.index
+					if index < 0 {
+						index += len(list)
+					}
+					if index >= 0 && index < len(list) {
+						next = append(next, list[index])
+					}
+				}
+			case jsonPathStepWildcard:
+				switch v := value.(type) {
+				case []any:
+					next = append(next, v...)
+				case map[string]any:
+					keys := make([]string, 0, len(v))
+					for key := range v {
+						keys = append(keys, key)
+					}
+					sort.Strings(keys)
+					for _, key := range keys {
+						next = append(next, v[key])
+					}
+				}
+			}
+		}
+		values = next
+	}
+	return values
+}
+
+// jsonPathValueString prints strings without quotes and other values as JSON
+func jsonPathValueString(value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("unable to print jsonpath value: %w", err)
+	}
+	return string(raw), nil
+}
+
+// asJSONValue converts a value to the maps, slices, and primitives produced by unmarshaling its JSON so that it can be
+// used with a jsonPath or go-template
+func asJSONValue(value any) (any, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var result any
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
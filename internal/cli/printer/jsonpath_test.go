@@ -0,0 +1,121 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package printer
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONPath(t *testing.T) {
+	data := map[string]any{}
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"items": [
+			{"id": "1", "name": "web-1", "labels": {"bindplane/agent-os": "linux", "env": "prod"}, "status": 1},
+			{"id": "2", "name": "web-2", "labels": {"bindplane/agent-os": "windows"}, "status": 0}
+		]
+	}`), &data))
+
+	tests := []struct {
+		name      string
+		template  string
+		expect    string
+		expectErr string
+	}{
+		{
+			name:     "wildcard",
+			template: "{.items[*].name}",
+			expect:   "web-1 web-2",
+		},
+		{
+			name:     "without braces",
+			template: ".items[0].id",
+			expect:   "1",
+		},
+		{
+			name:     "negative index",
+			template: "{.items[-1].id}",
+			expect:   "2",
+		},
+		{
+			name:     "range with text and quoted field",
+			template: `{range .items[*]}{.id}{"\t"}{.labels['bindplane/agent-os']}{"\n"}{end}`,
+			expect:   "1\tlinux\n2\twindows\n",
+		},
+		{
+			name:     "range over an array",
+			template: `{range .items}[{.labels.env}]{end}`,
+			expect:   "[prod][]",
+		},
+		{
+			name:     "root inside range",
+			template: `{range .items[*]}{$.items[0].name},{end}`,
+			expect:   "web-1,web-1,",
+		},
+		{
+			name:     "numbers and objects",
+			template: `{.items[0].status} {.items[1].labels}`,
+			expect:   `1 {"bindplane/agent-os":"windows"}`,
+		},
+		{
+			name:     "map wildcard is sorted by key",
+			template: `{.items[0].labels.*}`,
+			expect:   "linux prod",
+		},
+		{
+			name:     "missing fields print nothing",
+			template: `{.items[*].version}`,
+			expect:   "",
+		},
+		{
+			name:      "range without end",
+			template:  `{range .items[*]}{.id}`,
+			expectErr: "jsonpath {range} without {end}",
+		},
+		{
+			name:      "end without range",
+			template:  `{.id}{end}`,
+			expectErr: "jsonpath {end} without {range}",
+		},
+		{
+			name:      "unclosed action",
+			template:  `{.items`,
+			expectErr: "unclosed jsonpath action {.items",
+		},
+		{
+			name:      "invalid subscript",
+			template:  `{.items[a]}`,
+			expectErr: "invalid jsonpath .items[a]: unsupported subscript [a]",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path, err := parseJSONPath(test.template)
+			if test.expectErr != "" {
+				require.EqualError(t, err, test.expectErr)
+				return
+			}
+			require.NoError(t, err)
+
+			var buffer bytes.Buffer
+			require.NoError(t, path.Execute(&buffer, data))
+			require.Equal(t, test.expect, buffer.String())
+		})
+	}
+}
@@ -15,6 +15,12 @@
 package printer
 
 import (
+	"fmt"
+	"io"
+	"strings"
+
+	"go.uber.org/zap"
+
 	"github.com/observiq/bindplane-op/model"
 )
 
@@ -24,6 +30,59 @@ type Printer interface {
 	PrintResource(model.Printable)
 	// PrintResources prints a list of generic models that implements the printable interface
 	PrintResources([]model.Printable)
+	// PrintWatchEvents prints changes to resources received while watching. items contains the resource of each event
+	// in the same order as events.
+	PrintWatchEvents(events []*model.WatchEvent, items []model.Printable)
+}
+
+// Options configure the Printer returned by NewPrinter
+type Options struct {
+	// Output is the output format. One of: table|json|yaml|raw|csv|jsonpath=TEMPLATE|go-template=TEMPLATE|
+	// custom-columns=SPEC. raw is printed as a table by commands that don't support it.
+	Output string
+	// SortBy is a jsonpath used to sort lists of resources, e.g. .metadata.name
+	SortBy string
+	// NoHeaders omits the header of table, csv, and custom-columns output
+	NoHeaders bool
+}
+
+// NewPrinter returns the Printer for the options or an error if the output format or one of its templates is invalid
+func NewPrinter(writer io.Writer, logger *zap.Logger, options Options) (Printer, error) {
+	printer, err := newOutputPrinter(writer, logger, options)
+	if err != nil {
+		return nil, err
+	}
+	if options.SortBy == "" {
+		return printer, nil
+	}
+	sortBy, err := parseJSONPathExpr(trimBraces(options.SortBy))
+	if err != nil {
+		return nil, fmt.Errorf("invalid sort-by: %w", err)
+	}
+	return &sortingPrinter{Printer: printer, sortBy: sortBy}, nil
+}
+
+func newOutputPrinter(writer io.Writer, logger *zap.Logger, options Options) (Printer, error) {
+	format, arg, _ := strings.Cut(options.Output, "=")
+	switch format {
+	case "json":
+		return NewJSONPrinter(writer, logger), nil
+	case "yaml":
+		return NewYamlPrinter(writer, logger), nil
+	case "csv":
+		return NewCSVPrinter(writer, logger, options.NoHeaders), nil
+	case "jsonpath":
+		return NewJSONPathPrinter(writer, logger, arg)
+	case "go-template":
+		return NewGoTemplatePrinter(writer, logger, arg)
+	case "custom-columns":
+		return NewCustomColumnsPrinter(writer, logger, arg, options.NoHeaders)
+	case "table", "raw", "":
+		printer := NewTablePrinter(writer)
+		printer.noHeaders = options.NoHeaders
+		return printer, nil
+	}
+	return nil, fmt.Errorf("unknown output format %s, expected one of: json|table|yaml|raw|csv|jsonpath=TEMPLATE|go-template=TEMPLATE|custom-columns=SPEC", options.Output)
 }
 
 // PrintResource prints a single resource. It only exists to match the syntax of PrintResource.
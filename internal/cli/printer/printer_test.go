@@ -0,0 +1,105 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package printer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/observiq/bindplane-op/model"
+)
+
+func TestNewPrinter(t *testing.T) {
+	agents := []model.Printable{
+		&model.Agent{ID: "2", Name: "web-2", Version: "v1.3.0", Labels: model.LabelsFromValidatedMap(map[string]string{"env": "dev"})},
+		&model.Agent{ID: "1", Name: "web-1", Version: "v1.2.0", Status: model.Connected, Labels: model.LabelsFromValidatedMap(map[string]string{"env": "prod"})},
+	}
+
+	tests := []struct {
+		name      string
+		options   Options
+		expect    string
+		expectErr string
+	}{
+		{
+			name:    "table without headers",
+			options: Options{Output: "table", NoHeaders: true},
+			expect:  "2\tweb-2\tv1.3.0\tDisconnected\t-\t-\tenv=dev \t\n1\tweb-1\tv1.2.0\tConnected   \t-\t-\tenv=prod\t\n",
+		},
+		{
+			name:    "csv",
+			options: Options{Output: "csv"},
+			expect:  "ID,NAME,VERSION,STATUS,CONNECTED,DISCONNECTED,LABELS\n2,web-2,v1.3.0,Disconnected,-,-,env=dev\n1,web-1,v1.2.0,Connected,-,-,env=prod\n",
+		},
+		{
+			name:    "csv sorted without headers",
+			options: Options{Output: "csv", SortBy: ".name", NoHeaders: true},
+			expect:  "1,web-1,v1.2.0,Connected,-,-,env=prod\n2,web-2,v1.3.0,Disconnected,-,-,env=dev\n",
+		},
+		{
+			name:    "jsonpath",
+			options: Options{Output: `jsonpath={range .items[*]}{.id}={.labels.env}{"\n"}{end}`},
+			expect:  "2=dev\n1=prod\n",
+		},
+		{
+			name:    "go-template sorted by status",
+			options: Options{Output: `go-template={{range .items}}{{.name}} {{.version}}{{"\n"}}{{end}}`, SortBy: "{.status}"},
+			expect:  "web-2 v1.3.0\nweb-1 v1.2.0\n",
+		},
+		{
+			name:    "custom-columns",
+			options: Options{Output: "custom-columns=ID:.id,Env:{.labels.env},HOST:.hostname", SortBy: ".id"},
+			expect:  "ID\tEnv \tHOST   \n1 \tprod\t<none>\t\n2 \tdev \t<none>\t\n",
+		},
+		{
+			name:      "unknown output",
+			options:   Options{Output: "xml"},
+			expectErr: "unknown output format xml, expected one of: json|table|yaml|raw|csv|jsonpath=TEMPLATE|go-template=TEMPLATE|custom-columns=SPEC",
+		},
+		{
+			name:      "invalid custom-columns",
+			options:   Options{Output: "custom-columns=ID"},
+			expectErr: "invalid custom-columns ID, expected HEADER:jsonpath",
+		},
+		{
+			name:      "invalid go-template",
+			options:   Options{Output: "go-template={{.name"},
+			expectErr: "invalid go-template: template: output:1: unclosed action",
+		},
+		{
+			name:      "invalid sort-by",
+			options:   Options{Output: "table", SortBy: ".items[x]"},
+			expectErr: "invalid sort-by: invalid jsonpath .items[x]: unsupported subscript [x]",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buffer bytes.Buffer
+			printer, err := NewPrinter(&buffer, zap.NewNop(), test.options)
+			if test.expectErr != "" {
+				require.EqualError(t, err, test.expectErr)
+				return
+			}
+			require.NoError(t, err)
+
+			printer.PrintResources(agents)
+			require.Equal(t, test.expect, buffer.String())
+		})
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package printer
+
+import (
+	"sort"
+
+	"github.com/observiq/bindplane-op/model"
+)
+
+// sortingPrinter sorts lists of resources by the value of a jsonpath before printing them
+type sortingPrinter struct {
+	Printer
+	sortBy jsonPathExpr
+}
+
+var _ Printer = (*sortingPrinter)(nil)
+
+// PrintResources sorts the list and prints it with the underlying Printer
+func (sp *sortingPrinter) PrintResources(list []model.Printable) {
+	type sortable struct {
+		item model.Printable
+		key  any
+	}
+	sortables := make([]sortable, len(list))
+	for i, item := range list {
+		sortables[i].item = item
+		data, err := asJSONValue(item)
+		if err != nil {
+			continue
+		}
+		if values := sp.sortBy.evaluate(data, data); len(values) > 0 {
+			sortables[i].key = values[0]
+		}
+	}
+	sort.SliceStable(sortables, func(i, j int) bool {
+		return lessJSONValue(sortables[i].key, sortables[j].key)
+	})
+
+	sorted := make([]model.Printable, len(sortables))
+	for i, s := range sortables {
+		sorted[i] = s.item
+	}
+	sp.Printer.PrintResources(sorted)
+}
+
+// lessJSONValue compares numbers numerically and other values by their string value. Missing values are sorted first.
+func lessJSONValue(a, b any) bool {
+	if a == nil || b == nil {
+		return a == nil && b != nil
+	}
+	if x, ok := a.(float64); ok {
+		if y, ok := b.(float64); ok {
+			return x < y
+		}
+	}
+	x, _ := jsonPathValueString(a)
+	y, _ := jsonPathValueString(b)
+	return x < y
+}
@@ -25,14 +25,22 @@ import (
 
 // TablePrinter TODO(doc)
 type TablePrinter struct {
-	writer io.Writer
-	table  *tablewriter.Table
+	writer    io.Writer
+	table     *tablewriter.Table
+	noHeaders bool
+
+	// printedWatchHeader is true after the header has been printed for watch events
+	printedWatchHeader bool
 }
 
 var _ Printer = (*TablePrinter)(nil)
 
 // NewTablePrinter takes an io.Writer and returns a new *TablePrinter.
 func NewTablePrinter(writer io.Writer) *TablePrinter {
+	return &TablePrinter{writer: writer, table: newTable(writer)}
+}
+
+func newTable(writer io.Writer) *tablewriter.Table {
 	table := tablewriter.NewWriter(writer)
 
 	table.SetAutoWrapText(false)
@@ -47,7 +55,7 @@ func NewTablePrinter(writer io.Writer) *TablePrinter {
 	table.SetTablePadding("\t") // pad with tabs
 	table.SetNoWhiteSpace(true)
 
-	return &TablePrinter{writer: writer, table: table}
+	return table
 }
 
 // PrintResource prints a generic model that implements the printable interface
@@ -63,13 +71,34 @@ func (tp *TablePrinter) PrintResources(list []model.Printable) {
 	}
 	titles := list[0].PrintableFieldTitles()
 	tp.Reset()
-	tp.table.SetHeader(titles)
+	if !tp.noHeaders {
+		tp.table.SetHeader(titles)
+	}
 	for _, item := range list {
 		tp.table.Append(model.PrintableFieldValuesForTitles(item, titles))
 	}
 	tp.table.Render()
 }
 
+// PrintWatchEvents prints a row for each event with the type of the event in the first column. The header is only
+// printed with the first events.
+func (tp *TablePrinter) PrintWatchEvents(events []*model.WatchEvent, items []model.Printable) {
+	if len(items) == 0 {
+		return
+	}
+	titles := items[0].PrintableFieldTitles()
+	// use a new table for each batch because the header of a table can't be removed
+	table := newTable(tp.writer)
+	if !tp.noHeaders && !tp.printedWatchHeader {
+		table.SetHeader(append([]string{"Event"}, titles...))
+		tp.printedWatchHeader = true
+	}
+	for i, item := range items {
+		table.Append(append([]string{string(events[i].Type)}, model.PrintableFieldValuesForTitles(item, titles)...))
+	}
+	table.Render()
+}
+
 // Reset TODO(docs)
 func (tp *TablePrinter) Reset() {
 	tp.table.ClearRows()
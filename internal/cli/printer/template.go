@@ -0,0 +1,96 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package printer
+
+import (
+	"fmt"
+	"io"
+	"text/template"
+
+	"go.uber.org/zap"
+
+	"github.com/observiq/bindplane-op/model"
+)
+
+// TemplatePrinter prints resources using a jsonpath or go-template. A single resource is the root of the template and
+// a list of resources is available as .items.
+type TemplatePrinter struct {
+	writer  io.Writer
+	logger  *zap.Logger
+	execute func(writer io.Writer, data any) error
+}
+
+var _ Printer = (*TemplatePrinter)(nil)
+
+// NewJSONPathPrinter returns a new *TemplatePrinter that uses the jsonpath template, e.g. {.items[*].name}
+func NewJSONPathPrinter(writer io.Writer, logger *zap.Logger, jsonPathTemplate string) (*TemplatePrinter, error) {
+	path, err := parseJSONPath(jsonPathTemplate)
+	if err != nil {
+		return nil, err
+	}
+	return &TemplatePrinter{
+		writer:  writer,
+		logger:  logger,
+		execute: path.Execute,
+	}, nil
+}
+
+// NewGoTemplatePrinter returns a new *TemplatePrinter that uses the go template, e.g. {{range .items}}{{.name}}{{end}}
+func NewGoTemplatePrinter(writer io.Writer, logger *zap.Logger, goTemplate string) (*TemplatePrinter, error) {
+	tmpl, err := template.New("output").Parse(goTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid go-template: %w", err)
+	}
+	return &TemplatePrinter{
+		writer:  writer,
+		logger:  logger,
+		execute: tmpl.Execute,
+	}, nil
+}
+
+// PrintResource prints a generic model that implements the printable interface
+func (tp *TemplatePrinter) PrintResource(item model.Printable) {
+	if item == nil {
+		return
+	}
+	tp.print(item, item.PrintableKindSingular())
+}
+
+// PrintResources prints a list of generic models that implements the printable interface
+func (tp *TemplatePrinter) PrintResources(list []model.Printable) {
+	resourceName := "?"
+	if len(list) > 0 {
+		resourceName = list[0].PrintableKindPlural()
+	}
+	tp.print(map[string]any{"items": list}, resourceName)
+}
+
+// PrintWatchEvents prints each resource using the template
+func (tp *TemplatePrinter) PrintWatchEvents(_ []*model.WatchEvent, items []model.Printable) {
+	for _, item := range items {
+		tp.PrintResource(item)
+	}
+}
+
+func (tp *TemplatePrinter) print(resource any, resourceName string) {
+	data, err := asJSONValue(resource)
+	if err != nil {
+		tp.logger.Error("could not marshal resource as json", zap.String("resource", resourceName), zap.Error(err))
+		return
+	}
+	if err := tp.execute(tp.writer, data); err != nil {
+		tp.logger.Error("could not print resource using template", zap.String("resource", resourceName), zap.Error(err))
+	}
+}
@@ -60,6 +60,27 @@ func (yp *YamlPrinter) PrintResources(list []model.Printable) {
 	}
 }
 
+// yamlWatchEvent is a model.WatchEvent with the resource as yaml instead of raw json
+type yamlWatchEvent struct {
+	Type     model.WatchEventType `yaml:"type"`
+	Kind     model.Kind           `yaml:"kind"`
+	Name     string               `yaml:"name"`
+	Resource model.Printable      `yaml:"resource"`
+}
+
+// PrintWatchEvents prints each event as a separate yaml document
+func (yp *YamlPrinter) PrintWatchEvents(events []*model.WatchEvent, items []model.Printable) {
+	for i, event := range events {
+		fmt.Fprintln(yp.writer, "---")
+		yp.printYamlLine(yamlWatchEvent{
+			Type:     event.Type,
+			Kind:     event.Kind,
+			Name:     event.Name,
+			Resource: items[i],
+		}, event.Name)
+	}
+}
+
 func (yp *YamlPrinter) printYamlLine(resource interface{}, resourceName string) {
 	val, err := yaml.Marshal(resource)
 	if err != nil {
@@ -0,0 +1,112 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cluster provides communication between the nodes of a BindPlane deployment
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Handler receives messages published to a subject
+type Handler func(ctx context.Context, data []byte)
+
+// Bus sends messages to every node in a cluster. Messages published to a subject are received by the handlers
+// subscribed to that subject on every node, including the node that published them.
+type Bus interface {
+	// Publish sends the data to the handlers subscribed to the subject on every node
+	Publish(ctx context.Context, subject string, data []byte) error
+
+	// Subscribe adds a handler for messages published to the subject and returns a function to unsubscribe
+	Subscribe(subject string, handler Handler) (unsubscribe func())
+}
+
+// NewNodeID returns an ID that identifies this node in a cluster. It uses the hostname and a random suffix so that
+// multiple nodes on the same host have different IDs.
+func NewNodeID() string {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "bindplane"
+	}
+	return fmt.Sprintf("%s-%s", hostname, uuid.NewString()[:8])
+}
+
+// ----------------------------------------------------------------------
+
+type subscription struct {
+	handler Handler
+}
+
+// LocalBus is a Bus for a single node that delivers messages to its subscribers synchronously
+type LocalBus struct {
+	subscriptions map[string]map[*subscription]struct{}
+	mtx           sync.RWMutex
+}
+
+var _ Bus = (*LocalBus)(nil)
+
+// NewLocalBus returns a new Bus for a single node. It can also be shared by multiple nodes in the same process, which
+// is useful for tests.
+func NewLocalBus() *LocalBus {
+	return &LocalBus{
+		subscriptions: map[string]map[*subscription]struct{}{},
+	}
+}
+
+// Publish delivers the data to every handler subscribed to the subject
+func (b *LocalBus) Publish(ctx context.Context, subject string, data []byte) error {
+	for _, handler := range b.handlers(subject) {
+		handler(ctx, data)
+	}
+	return nil
+}
+
+// Subscribe adds a handler for messages published to the subject and returns a function to unsubscribe
+func (b *LocalBus) Subscribe(subject string, handler Handler) func() {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	s := &subscription{handler: handler}
+	subscriptions, ok := b.subscriptions[subject]
+	if !ok {
+		subscriptions = map[*subscription]struct{}{}
+		b.subscriptions[subject] = subscriptions
+	}
+	subscriptions[s] = struct{}{}
+
+	return func() {
+		b.mtx.Lock()
+		defer b.mtx.Unlock()
+		delete(b.subscriptions[subject], s)
+		if len(b.subscriptions[subject]) == 0 {
+			delete(b.subscriptions, subject)
+		}
+	}
+}
+
+// handlers returns a copy of the handlers for the subject so that handlers can subscribe and publish without deadlock
+func (b *LocalBus) handlers(subject string) []Handler {
+	b.mtx.RLock()
+	defer b.mtx.RUnlock()
+	handlers := make([]Handler, 0, len(b.subscriptions[subject]))
+	for s := range b.subscriptions[subject] {
+		handlers = append(handlers, s.handler)
+	}
+	return handlers
+}
@@ -0,0 +1,130 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// received collects the messages received by a handler
+type received struct {
+	messages []string
+	mtx      sync.Mutex
+}
+
+func (r *received) handler(ctx context.Context, data []byte) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.messages = append(r.messages, string(data))
+}
+
+func (r *received) get() []string {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	return append([]string{}, r.messages...)
+}
+
+// testNodes verifies that messages published by one node are received by the subscribers on every node
+func testNodes(t *testing.T, nodeA, nodeB Bus) {
+	ctx := context.Background()
+
+	var a, b, other received
+	unsubscribeA := nodeA.Subscribe("agents.updates", a.handler)
+	unsubscribeB := nodeB.Subscribe("agents.updates", b.handler)
+	defer unsubscribeB()
+	unsubscribeOther := nodeB.Subscribe("other", other.handler)
+	defer unsubscribeOther()
+
+	require.NoError(t, nodeA.Publish(ctx, "agents.updates", []byte("1")))
+	require.NoError(t, nodeB.Publish(ctx, "agents.updates", []byte("2")))
+
+	expect := []string{"1", "2"}
+	require.Eventually(t, func() bool { return len(a.get()) == 2 && len(b.get()) == 2 }, 5*time.Second, 10*time.Millisecond)
+	require.ElementsMatch(t, expect, a.get())
+	require.ElementsMatch(t, expect, b.get())
+
+	unsubscribeA()
+	require.NoError(t, nodeB.Publish(ctx, "agents.updates", []byte("3")))
+	require.Eventually(t, func() bool { return len(b.get()) == 3 }, 5*time.Second, 10*time.Millisecond)
+	require.ElementsMatch(t, expect, a.get())
+	require.Empty(t, other.get())
+}
+
+func TestNATSBus(t *testing.T) {
+	server, err := natsserver.NewServer(&natsserver.Options{Host: "127.0.0.1", Port: -1, NoLog: true, NoSigs: true})
+	require.NoError(t, err)
+	go server.Start()
+	defer server.Shutdown()
+	require.True(t, server.ReadyForConnections(5*time.Second))
+
+	nodeA, err := NewNATSBus(server.ClientURL(), zap.NewNop())
+	require.NoError(t, err)
+	defer nodeA.Close()
+	nodeB, err := NewNATSBus(server.ClientURL(), zap.NewNop())
+	require.NoError(t, err)
+	defer nodeB.Close()
+
+	testNodes(t, nodeA, nodeB)
+}
+
+func TestNATSBusConnectError(t *testing.T) {
+	_, err := NewNATSBus("nats://127.0.0.1:1", zap.NewNop())
+	require.Error(t, err)
+}
+
+func TestRedisBus(t *testing.T) {
+	server := miniredis.RunT(t)
+
+	nodeA := NewRedisBus(redis.NewClient(&redis.Options{Addr: server.Addr()}), 0, zap.NewNop())
+	defer nodeA.Close()
+	nodeB := NewRedisBus(redis.NewClient(&redis.Options{Addr: server.Addr()}), 100, zap.NewNop())
+	defer nodeB.Close()
+
+	// entries added before subscribing are not received
+	require.NoError(t, nodeA.Publish(context.Background(), "agents.updates", []byte("0")))
+
+	testNodes(t, nodeA, nodeB)
+}
+
+func TestRedisBusLastIDError(t *testing.T) {
+	server := miniredis.RunT(t)
+	ctx := context.Background()
+
+	bus := NewRedisBus(redis.NewClient(&redis.Options{Addr: server.Addr()}), 0, zap.NewNop())
+	defer bus.Close()
+	require.NoError(t, bus.Publish(ctx, "agents.updates", []byte("old")))
+
+	// the stream is not replayed if the last entry can't be read
+	var r received
+	server.SetError("ERR unavailable")
+	unsubscribe := bus.Subscribe("agents.updates", r.handler)
+	defer unsubscribe()
+	server.SetError("")
+
+	require.Eventually(t, func() bool {
+		require.NoError(t, bus.Publish(ctx, "agents.updates", []byte("new")))
+		return len(r.get()) > 0
+	}, 10*time.Second, 100*time.Millisecond)
+	require.NotContains(t, r.get(), "old")
+}
@@ -0,0 +1,44 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalBus(t *testing.T) {
+	bus := NewLocalBus()
+	ctx := context.Background()
+
+	var a, b []string
+	unsubscribeA := bus.Subscribe("subject", func(ctx context.Context, data []byte) { a = append(a, string(data)) })
+	bus.Subscribe("subject", func(ctx context.Context, data []byte) { b = append(b, string(data)) })
+	bus.Subscribe("other", func(ctx context.Context, data []byte) { t.Fatal("should not receive messages for subject") })
+
+	require.NoError(t, bus.Publish(ctx, "subject", []byte("1")))
+	unsubscribeA()
+	require.NoError(t, bus.Publish(ctx, "subject", []byte("2")))
+	require.NoError(t, bus.Publish(ctx, "none", []byte("3")))
+
+	require.Equal(t, []string{"1"}, a)
+	require.Equal(t, []string{"1", "2"}, b)
+}
+
+func TestNewNodeID(t *testing.T) {
+	require.NotEqual(t, NewNodeID(), NewNodeID())
+}
@@ -0,0 +1,179 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// LeaderLeaseName is the name of the lease held by the leader of a BindPlane cluster
+	LeaderLeaseName = "leader"
+
+	// DefaultLeaseTTL is the default duration of the leader lease. The leader renews the lease at a third of this
+	// interval and another node can become leader once it expires.
+	DefaultLeaseTTL = 15 * time.Second
+)
+
+// Leases grants exclusive, expiring leases. It is implemented by the store shared by the nodes in a cluster.
+type Leases interface {
+	// AcquireLease acquires or renews the named lease for the holder. It returns false if the lease is held by another
+	// holder and has not expired.
+	AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error)
+
+	// ReleaseLease releases the named lease if it is held by the holder
+	ReleaseLease(ctx context.Context, name, holder string) error
+}
+
+// Leadership reports whether this node is the leader of the cluster. Singleton background jobs should only run on the
+// leader.
+type Leadership interface {
+	// NodeID is the ID of this node
+	NodeID() string
+
+	// IsLeader returns true if this node is currently the leader
+	IsLeader() bool
+}
+
+// ----------------------------------------------------------------------
+
+type standalone string
+
+// Standalone returns the Leadership of a node that isn't part of a cluster and is always the leader
+func Standalone(nodeID string) Leadership {
+	return standalone(nodeID)
+}
+
+func (s standalone) NodeID() string {
+	return string(s)
+}
+
+func (s standalone) IsLeader() bool {
+	return true
+}
+
+// ----------------------------------------------------------------------
+
+// Leader elects a single leader from the nodes in a cluster by holding a lease
+type Leader struct {
+	name   string
+	nodeID string
+	leases Leases
+	ttl    time.Duration
+	logger *zap.Logger
+
+	leader bool
+	mtx    sync.RWMutex
+
+	// done is closed after the lease is released
+	done chan struct{}
+}
+
+var _ Leadership = (*Leader)(nil)
+
+// NewLeader creates a Leader that campaigns for the named lease on behalf of the node. If ttl is 0, DefaultLeaseTTL is
+// used.
+func NewLeader(name, nodeID string, leases Leases, ttl time.Duration, logger *zap.Logger) *Leader {
+	if ttl <= 0 {
+		ttl = DefaultLeaseTTL
+	}
+	return &Leader{
+		name:   name,
+		nodeID: nodeID,
+		leases: leases,
+		ttl:    ttl,
+		logger: logger.With(zap.String("nodeID", nodeID), zap.String("lease", name)),
+		done:   make(chan struct{}),
+	}
+}
+
+// NodeID is the ID of this node
+func (l *Leader) NodeID() string {
+	return l.nodeID
+}
+
+// IsLeader returns true if this node holds the lease
+func (l *Leader) IsLeader() bool {
+	l.mtx.RLock()
+	defer l.mtx.RUnlock()
+	return l.leader
+}
+
+// Start campaigns for the lease once so that IsLeader is accurate when it returns and then continues to campaign in
+// the background, renewing the lease while this node is the leader. The lease is released when the context is done.
+func (l *Leader) Start(ctx context.Context) {
+	l.campaign(ctx)
+	go l.run(ctx)
+}
+
+// Done returns a channel that is closed after the context passed to Start is done and the lease is released
+func (l *Leader) Done() <-chan struct{} {
+	return l.done
+}
+
+func (l *Leader) run(ctx context.Context) {
+	defer close(l.done)
+	ticker := time.NewTicker(l.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			l.resign()
+			return
+		case <-ticker.C:
+			l.campaign(ctx)
+		}
+	}
+}
+
+// campaign acquires or renews the lease and updates the leader status
+func (l *Leader) campaign(ctx context.Context) {
+	acquired, err := l.leases.AcquireLease(ctx, l.name, l.nodeID, l.ttl)
+	if err != nil {
+		// without the lease we can't be sure that another node hasn't become leader
+		l.logger.Error("failed to acquire leader lease", zap.Error(err))
+		acquired = false
+	}
+	l.setLeader(acquired)
+}
+
+// resign releases the lease so that another node can become leader without waiting for it to expire
+func (l *Leader) resign() {
+	if !l.IsLeader() {
+		return
+	}
+	l.setLeader(false)
+
+	// the campaign context is done, use a new context to release the lease
+	ctx, cancel := context.WithTimeout(context.Background(), l.ttl)
+	defer cancel()
+	if err := l.leases.ReleaseLease(ctx, l.name, l.nodeID); err != nil {
+		l.logger.Error("failed to release leader lease", zap.Error(err))
+	}
+}
+
+func (l *Leader) setLeader(leader bool) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	if l.leader != leader {
+		l.logger.Info("leadership changed", zap.Bool("leader", leader))
+	}
+	l.leader = leader
+}
@@ -0,0 +1,128 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// testLeases holds leases in memory like the leases of a single node store
+type testLeases struct {
+	holders map[string]string
+	expires map[string]time.Time
+	err     error
+	mtx     sync.Mutex
+}
+
+func newTestLeases() *testLeases {
+	return &testLeases{
+		holders: map[string]string{},
+		expires: map[string]time.Time{},
+	}
+}
+
+func (l *testLeases) AcquireLease(_ context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	if l.err != nil {
+		return false, l.err
+	}
+	if current, ok := l.holders[name]; ok && current != holder && time.Now().Before(l.expires[name]) {
+		return false, nil
+	}
+	l.holders[name] = holder
+	l.expires[name] = time.Now().Add(ttl)
+	return true, nil
+}
+
+func (l *testLeases) ReleaseLease(_ context.Context, name, holder string) error {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	if l.holders[name] == holder {
+		delete(l.holders, name)
+		delete(l.expires, name)
+	}
+	return nil
+}
+
+func (l *testLeases) setErr(err error) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	l.err = err
+}
+
+func TestLeader(t *testing.T) {
+	leases := newTestLeases()
+	ttl := 150 * time.Millisecond
+
+	ctxA, cancelA := context.WithCancel(context.Background())
+	defer cancelA()
+	ctxB, cancelB := context.WithCancel(context.Background())
+	defer cancelB()
+
+	a := NewLeader(LeaderLeaseName, "a", leases, ttl, zap.NewNop())
+	b := NewLeader(LeaderLeaseName, "b", leases, ttl, zap.NewNop())
+	require.Equal(t, "a", a.NodeID())
+
+	a.Start(ctxA)
+	b.Start(ctxB)
+	require.True(t, a.IsLeader())
+	require.False(t, b.IsLeader())
+
+	// a renews the lease and remains the leader
+	time.Sleep(ttl * 2)
+	require.True(t, a.IsLeader())
+	require.False(t, b.IsLeader())
+
+	// a resigns and b becomes the leader
+	cancelA()
+	<-a.Done()
+	leases.mtx.Lock()
+	require.NotEqual(t, "a", leases.holders[LeaderLeaseName])
+	leases.mtx.Unlock()
+	require.Eventually(t, func() bool { return !a.IsLeader() && b.IsLeader() }, ttl*4, 10*time.Millisecond)
+}
+
+func TestLeaderLeaseError(t *testing.T) {
+	leases := newTestLeases()
+	ttl := 150 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	l := NewLeader(LeaderLeaseName, "a", leases, ttl, zap.NewNop())
+	l.Start(ctx)
+	require.True(t, l.IsLeader())
+
+	// the leader steps down if it can't renew the lease
+	leases.setErr(errors.New("store unavailable"))
+	require.Eventually(t, func() bool { return !l.IsLeader() }, ttl*4, 10*time.Millisecond)
+
+	leases.setErr(nil)
+	require.Eventually(t, l.IsLeader, ttl*4, 10*time.Millisecond)
+}
+
+func TestStandalone(t *testing.T) {
+	s := Standalone("node")
+	require.Equal(t, "node", s.NodeID())
+	require.True(t, s.IsLeader())
+}
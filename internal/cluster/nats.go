@@ -0,0 +1,82 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+	"go.uber.org/zap"
+)
+
+// natsSubjectPrefix is added to all subjects to avoid conflicts with other users of the NATS server
+const natsSubjectPrefix = "bindplane."
+
+// NATSBus is a Bus that sends messages to every node with a NATS server
+type NATSBus struct {
+	conn   *nats.Conn
+	logger *zap.Logger
+}
+
+var _ Bus = (*NATSBus)(nil)
+
+// NewNATSBus connects to the NATS server at the specified url and returns a new NATSBus. Additional options can be used
+// to configure authentication or TLS.
+func NewNATSBus(url string, logger *zap.Logger, options ...nats.Option) (*NATSBus, error) {
+	options = append([]nats.Option{
+		nats.Name("bindplane"),
+		nats.MaxReconnects(-1),
+	}, options...)
+
+	conn, err := nats.Connect(url, options...)
+	if err != nil {
+		return nil, fmt.Errorf("connect to nats: %w", err)
+	}
+	return &NATSBus{
+		conn:   conn,
+		logger: logger,
+	}, nil
+}
+
+// Publish sends the data to the handlers subscribed to the subject on every node
+func (b *NATSBus) Publish(ctx context.Context, subject string, data []byte) error {
+	return b.conn.Publish(natsSubjectPrefix+subject, data)
+}
+
+// Subscribe adds a handler for messages published to the subject and returns a function to unsubscribe
+func (b *NATSBus) Subscribe(subject string, handler Handler) func() {
+	subscription, err := b.conn.Subscribe(natsSubjectPrefix+subject, func(msg *nats.Msg) {
+		handler(context.Background(), msg.Data)
+	})
+	if err != nil {
+		b.logger.Error("unable to subscribe to nats subject", zap.String("subject", subject), zap.Error(err))
+		return func() {}
+	}
+	// wait for the server to process the subscription so that messages published after Subscribe returns are received
+	if err := b.conn.Flush(); err != nil {
+		b.logger.Error("unable to flush nats subscription", zap.String("subject", subject), zap.Error(err))
+	}
+	return func() {
+		if err := subscription.Unsubscribe(); err != nil {
+			b.logger.Error("unable to unsubscribe from nats subject", zap.String("subject", subject), zap.Error(err))
+		}
+	}
+}
+
+// Close drains the subscriptions and closes the connection to the NATS server
+func (b *NATSBus) Close() error {
+	return b.conn.Drain()
+}
@@ -0,0 +1,168 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+)
+
+const (
+	// redisStreamPrefix is added to all subjects to name the stream used for each subject
+	redisStreamPrefix = "bindplane:"
+	// redisDataField is the field of each stream entry that contains the data
+	redisDataField = "data"
+	// redisBlock is the maximum time to wait for new entries before checking if the subscription has ended
+	redisBlock = time.Second
+	// redisRetryInterval is the time to wait after an error reading a stream
+	redisRetryInterval = 5 * time.Second
+	// DefaultRedisMaxLen is the default approximate maximum number of entries kept in each stream
+	DefaultRedisMaxLen = 10_000
+)
+
+// RedisBus is a Bus that sends messages to every node with Redis Streams. Each subject is a stream and every subscriber
+// reads the entries added to the stream after it subscribed.
+type RedisBus struct {
+	client *redis.Client
+	maxLen int64
+	logger *zap.Logger
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+var _ Bus = (*RedisBus)(nil)
+
+// NewRedisBus returns a new RedisBus using the specified client. Streams are trimmed to approximately maxLen entries
+// or DefaultRedisMaxLen if maxLen is 0.
+func NewRedisBus(client *redis.Client, maxLen int64, logger *zap.Logger) *RedisBus {
+	if maxLen <= 0 {
+		maxLen = DefaultRedisMaxLen
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &RedisBus{
+		client: client,
+		maxLen: maxLen,
+		logger: logger,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Publish sends the data to the handlers subscribed to the subject on every node
+func (b *RedisBus) Publish(ctx context.Context, subject string, data []byte) error {
+	return b.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: redisStreamPrefix + subject,
+		MaxLen: b.maxLen,
+		Approx: true,
+		Values: map[string]any{redisDataField: data},
+	}).Err()
+}
+
+// Subscribe adds a handler for messages published to the subject and returns a function to unsubscribe
+func (b *RedisBus) Subscribe(subject string, handler Handler) func() {
+	ctx, cancel := context.WithCancel(b.ctx)
+	stream := redisStreamPrefix + subject
+
+	// read the last ID before returning so that entries added after Subscribe returns are received. if it can't be read,
+	// it is read again before reading the stream and only entries added after that are received.
+	lastID, err := b.lastID(ctx, stream)
+	if err != nil {
+		b.logger.Error("unable to read the last entry of redis stream", zap.String("stream", stream), zap.Error(err))
+	}
+
+	go func() {
+		for {
+			if lastID == "" {
+				// retry instead of reading from the start of the stream, which would replay its whole history
+				id, err := b.lastID(ctx, stream)
+				switch {
+				case ctx.Err() != nil:
+					return
+				case err != nil:
+					b.logger.Error("unable to read the last entry of redis stream", zap.String("stream", stream), zap.Error(err))
+					if !b.wait(ctx) {
+						return
+					}
+					continue
+				}
+				lastID = id
+			}
+
+			streams, err := b.client.XRead(ctx, &redis.XReadArgs{
+				Streams: []string{stream, lastID},
+				Block:   redisBlock,
+			}).Result()
+
+			switch {
+			case ctx.Err() != nil:
+				return
+
+			case errors.Is(err, redis.Nil):
+				continue
+
+			case err != nil:
+				b.logger.Error("unable to read redis stream", zap.String("stream", stream), zap.Error(err))
+				if !b.wait(ctx) {
+					return
+				}
+				continue
+			}
+
+			for _, s := range streams {
+				for _, message := range s.Messages {
+					lastID = message.ID
+					if data, ok := message.Values[redisDataField].(string); ok {
+						handler(ctx, []byte(data))
+					}
+				}
+			}
+		}
+	}()
+
+	return cancel
+}
+
+// Close stops all subscriptions and closes the client
+func (b *RedisBus) Close() error {
+	b.cancel()
+	return b.client.Close()
+}
+
+// wait waits for the retry interval after an error and returns false if the subscription ended
+func (b *RedisBus) wait(ctx context.Context) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(redisRetryInterval):
+		return true
+	}
+}
+
+// lastID returns the ID of the last entry in the stream or 0-0 if the stream is empty or doesn't exist
+func (b *RedisBus) lastID(ctx context.Context, stream string) (string, error) {
+	messages, err := b.client.XRevRangeN(ctx, stream, "+", "-", 1).Result()
+	if err != nil {
+		return "", err
+	}
+	if len(messages) == 0 {
+		return "0-0", nil
+	}
+	return messages[0].ID, nil
+}
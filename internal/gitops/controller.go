@@ -0,0 +1,374 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gitops syncs resources from a git repository to the store
+package gitops
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/observiq/bindplane-op/internal/cluster"
+	"github.com/observiq/bindplane-op/internal/store"
+	"github.com/observiq/bindplane-op/model"
+)
+
+// Settings configures the Controller
+type Settings struct {
+	// Repository is the URL or path of the git repository
+	Repository string
+
+	// Branch is the branch of the repository to sync
+	Branch string
+
+	// Path is the directory of the repository containing resource yaml files
+	Path string
+
+	// Directory is where the repository is cloned
+	Directory string
+
+	// Interval is the interval at which the repository is pulled
+	Interval time.Duration
+
+	// Prune removes resources that were synced from the repository and have been removed from it
+	Prune bool
+
+	// Leadership determines if this node syncs at the configured interval. In a cluster, only the leader syncs the
+	// repository. If nil, this node always syncs.
+	Leadership cluster.Leadership
+
+	Logger *zap.Logger
+}
+
+// Controller periodically pulls resources from a git repository and applies the resources that changed to the store.
+// Each applied resource is labeled with the commit it was synced from, which is also used to find the resources to
+// prune. The status of each sync is saved to the store.
+type Controller struct {
+	store      store.Store
+	settings   Settings
+	repository *repository
+	logger     *zap.Logger
+
+	// mtx prevents concurrent syncs from using the clone at the same time
+	mtx sync.Mutex
+}
+
+// NewController creates a new Controller that will apply resources to the specified store
+func NewController(store store.Store, settings Settings) *Controller {
+	if settings.Logger == nil {
+		settings.Logger = zap.NewNop()
+	}
+	return &Controller{
+		store:    store,
+		settings: settings,
+		repository: &repository{
+			url:    settings.Repository,
+			branch: settings.Branch,
+			dir:    settings.Directory,
+		},
+		logger: settings.Logger,
+	}
+}
+
+// Start syncs the repository immediately and then at the configured interval until the context is done. If Leadership
+// is configured, it only syncs while this node is the leader.
+func (c *Controller) Start(ctx context.Context) {
+	if c.settings.Interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(c.settings.Interval)
+	defer ticker.Stop()
+
+	for {
+		if c.settings.Leadership == nil || c.settings.Leadership.IsLeader() {
+			if _, err := c.Sync(ctx); err != nil {
+				c.logger.Error("failed to sync gitops repository", zap.Error(err))
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sync pulls the repository and applies the resources that changed since they were last synced. If Prune is set,
+// resources that were synced from the repository and are no longer in it are deleted. The status of the sync is saved
+// to the store and returned along with an error if the sync was not completely successful.
+func (c *Controller) Sync(ctx context.Context) (*model.GitOpsSyncStatus, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	status := &model.GitOpsSyncStatus{
+		Repository: redactURL(c.settings.Repository),
+		Branch:     c.settings.Branch,
+		Path:       c.settings.Path,
+		Prune:      c.settings.Prune,
+		SyncedAt:   time.Now().UTC(),
+	}
+
+	// keep the previous commit if the repository can't be pulled
+	if previous, err := c.store.GitOpsSyncStatus(ctx); err == nil && previous != nil {
+		status.Commit = previous.Commit
+	}
+
+	commit, err := c.repository.pull(ctx)
+	if err != nil {
+		// git includes the URL in some errors
+		message := strings.ReplaceAll(err.Error(), c.settings.Repository, status.Repository)
+		status.Errors = append(status.Errors, fmt.Sprintf("failed to pull repository: %s", message))
+		return c.finish(ctx, status)
+	}
+	status.Commit = commit
+
+	desired, readErrors := c.read()
+	status.Errors = append(status.Errors, readErrors...)
+
+	existing, err := c.existing()
+	if err != nil {
+		status.Errors = append(status.Errors, fmt.Sprintf("failed to list resources: %s", err.Error()))
+		return c.finish(ctx, status)
+	}
+
+	c.apply(status, desired, existing)
+
+	if c.settings.Prune {
+		// a file that can't be read would cause all of its resources to be pruned
+		if len(readErrors) > 0 {
+			status.Errors = append(status.Errors, "resources were not pruned because the repository contains errors")
+		} else {
+			c.prune(status, desired, existing)
+		}
+	}
+
+	return c.finish(ctx, status)
+}
+
+// finish saves the status and returns an error if the sync had errors
+func (c *Controller) finish(ctx context.Context, status *model.GitOpsSyncStatus) (*model.GitOpsSyncStatus, error) {
+	if err := c.store.SetGitOpsSyncStatus(ctx, status); err != nil {
+		return status, fmt.Errorf("failed to save gitops sync status: %w", err)
+	}
+	c.logger.Info("synced gitops repository",
+		zap.String("commit", status.Commit),
+		zap.Int("changes", len(status.Changes)),
+		zap.Int("errors", len(status.Errors)),
+	)
+	if !status.Succeeded() {
+		return status, fmt.Errorf("gitops sync failed: %s", strings.Join(status.Errors, "; "))
+	}
+	return status, nil
+}
+
+// read returns the resources in the yaml files of the repository path, sorted in the order they should be applied,
+// and the errors of files that could not be read
+func (c *Controller) read() ([]*model.AnyResource, []string) {
+	root := filepath.Join(c.settings.Directory, c.settings.Path)
+
+	var resources []*model.AnyResource
+	var errs []string
+	files := map[string]string{}
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != root && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if ext := strings.ToLower(filepath.Ext(path)); ext != ".yaml" && ext != ".yml" {
+			return nil
+		}
+
+		rel, _ := filepath.Rel(c.settings.Directory, path)
+		parsed, err := model.ResourcesFromFile(path)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("failed to read %s: %s", rel, err.Error()))
+			return nil
+		}
+		for _, r := range parsed {
+			key := resourceKey(r.Kind, r.Name())
+			if other, ok := files[key]; ok {
+				errs = append(errs, fmt.Sprintf("%s %s in %s is also defined in %s", r.Kind, r.Name(), rel, other))
+				continue
+			}
+			files[key] = rel
+			resources = append(resources, r)
+		}
+		return nil
+	})
+	if err != nil {
+		errs = append(errs, fmt.Sprintf("failed to read %s: %s", c.settings.Path, err.Error()))
+	}
+
+	sort.SliceStable(resources, func(i, j int) bool {
+		return resources[i].Kind.DependencyOrder() < resources[j].Kind.DependencyOrder()
+	})
+	return resources, errs
+}
+
+// existing returns the resources in the store by kind and name
+func (c *Controller) existing() (map[string]model.Resource, error) {
+	existing := map[string]model.Resource{}
+	for _, add := range []func() error{
+		func() error { return addExisting(existing, c.store.SourceTypes) },
+		func() error { return addExisting(existing, c.store.ProcessorTypes) },
+		func() error { return addExisting(existing, c.store.DestinationTypes) },
+		func() error { return addExisting(existing, c.store.Sources) },
+		func() error { return addExisting(existing, c.store.Processors) },
+		func() error { return addExisting(existing, c.store.Destinations) },
+		func() error { return addExisting(existing, c.store.Configurations) },
+		func() error { return addExisting(existing, c.store.AgentVersions) },
+	} {
+		if err := add(); err != nil {
+			return nil, err
+		}
+	}
+	return existing, nil
+}
+
+// apply applies the desired resources that are new or have changed since they were synced
+func (c *Controller) apply(status *model.GitOpsSyncStatus, desired []*model.AnyResource, existing map[string]model.Resource) {
+	var resources []model.Resource
+	for _, r := range desired {
+		if current, ok := existing[resourceKey(r.Kind, r.Name())]; ok && unchanged(current, r) {
+			continue
+		}
+
+		setCommitLabel(r, status.Commit)
+		parsed, err := model.ParseResource(r)
+		if err != nil {
+			addChange(status, r.Kind, r.Name(), model.StatusInvalid, err.Error())
+			continue
+		}
+		resources = append(resources, parsed)
+	}
+	if len(resources) == 0 {
+		return
+	}
+
+	statuses, err := c.store.ApplyResources(resources)
+	if err != nil {
+		status.Errors = append(status.Errors, fmt.Sprintf("failed to apply resources: %s", err.Error()))
+	}
+	for _, s := range statuses {
+		if s.Status == model.StatusUnchanged {
+			continue
+		}
+		addChange(status, s.Resource.GetKind(), s.Resource.Name(), s.Status, s.Reason)
+	}
+}
+
+// prune deletes the resources synced from the repository that are no longer in it
+func (c *Controller) prune(status *model.GitOpsSyncStatus, desired []*model.AnyResource, existing map[string]model.Resource) {
+	keep := map[string]bool{}
+	for _, r := range desired {
+		keep[resourceKey(r.Kind, r.Name())] = true
+	}
+
+	var resources []model.Resource
+	for key, r := range existing {
+		if keep[key] || r.GetLabels().Get(model.LabelBindPlaneGitOpsCommit) == "" {
+			continue
+		}
+		resources = append(resources, r)
+	}
+	if len(resources) == 0 {
+		return
+	}
+
+	// delete resources before the resources they depend on
+	sort.Slice(resources, func(i, j int) bool {
+		if resources[i].GetKind().DependencyOrder() != resources[j].GetKind().DependencyOrder() {
+			return resources[i].GetKind().DependencyOrder() > resources[j].GetKind().DependencyOrder()
+		}
+		return resources[i].Name() < resources[j].Name()
+	})
+
+	statuses, err := c.store.DeleteResources(resources)
+	if err != nil {
+		status.Errors = append(status.Errors, fmt.Sprintf("failed to prune resources: %s", err.Error()))
+	}
+	for _, s := range statuses {
+		addChange(status, s.Resource.GetKind(), s.Resource.Name(), s.Status, s.Reason)
+	}
+}
+
+// ----------------------------------------------------------------------
+
+// addChange adds the change to the status. Resources that were not created, configured, or deleted are also added to
+// the errors.
+func addChange(status *model.GitOpsSyncStatus, kind model.Kind, name string, updateStatus model.UpdateStatus, reason string) {
+	status.Changes = append(status.Changes, model.GitOpsChange{
+		Kind:   kind,
+		Name:   name,
+		Status: updateStatus,
+		Reason: reason,
+	})
+	switch updateStatus {
+	case model.StatusCreated, model.StatusConfigured, model.StatusDeleted:
+	default:
+		status.Errors = append(status.Errors, fmt.Sprintf("%s %s %s: %s", kind, name, updateStatus, reason))
+	}
+}
+
+// unchanged returns true if the resource was synced and is the same as the desired resource apart from the commit
+func unchanged(current model.Resource, desired *model.AnyResource) bool {
+	commit := current.GetLabels().Get(model.LabelBindPlaneGitOpsCommit)
+	if commit == "" {
+		return false
+	}
+	setCommitLabel(desired, commit)
+	parsed, err := model.ParseResource(desired)
+	if err != nil {
+		return false
+	}
+	return store.ResourcesEqual(current, parsed)
+}
+
+// setCommitLabel sets the bindplane/gitops-commit label on the resource
+func setCommitLabel(r *model.AnyResource, commit string) {
+	if r.Metadata.Labels.Set == nil {
+		r.Metadata.Labels = model.MakeLabels()
+	}
+	r.Metadata.Labels.Set[model.LabelBindPlaneGitOpsCommit] = commit
+}
+
+func addExisting[T model.Resource](existing map[string]model.Resource, list func(...store.QueryOption) ([]T, error)) error {
+	resources, err := list()
+	if err != nil {
+		return err
+	}
+	for _, r := range resources {
+		existing[resourceKey(r.GetKind(), r.Name())] = r
+	}
+	return nil
+}
+
+func resourceKey(kind model.Kind, name string) string {
+	return fmt.Sprintf("%s|%s", kind, name)
+}
@@ -0,0 +1,276 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitops
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/observiq/bindplane-op/internal/store"
+	"github.com/observiq/bindplane-op/model"
+)
+
+const testSource = `apiVersion: bindplane.observiq.com/v1
+kind: Source
+metadata:
+  name: macos-1
+spec:
+  type: macos
+`
+
+const testConfiguration = `apiVersion: bindplane.observiq.com/v1
+kind: Configuration
+metadata:
+  name: prod
+  labels:
+    env: %s
+spec:
+  sources:
+    - name: macos-1
+  selector:
+    matchLabels:
+      configuration: prod
+`
+
+const testConfigurationWithoutSources = `apiVersion: bindplane.observiq.com/v1
+kind: Configuration
+metadata:
+  name: prod
+spec:
+  selector:
+    matchLabels:
+      configuration: prod
+`
+
+// testRepository is a bare repository with a working tree used to push commits to it
+type testRepository struct {
+	t      *testing.T
+	origin string
+	work   string
+}
+
+func newTestRepository(t *testing.T) *testRepository {
+	dir := t.TempDir()
+	r := &testRepository{
+		t:      t,
+		origin: filepath.Join(dir, "origin.git"),
+		work:   filepath.Join(dir, "work"),
+	}
+	r.git("", "init", "--quiet", "--bare", r.origin)
+	r.git("", "init", "--quiet", r.work)
+	r.git(r.work, "checkout", "--quiet", "-b", "main")
+	return r
+}
+
+func (r *testRepository) git(dir string, args ...string) string {
+	args = append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)
+	out, err := git(context.Background(), dir, args...)
+	require.NoError(r.t, err)
+	return out
+}
+
+// commit writes the files, removing files with empty contents, and pushes the commit to the bare repository
+func (r *testRepository) commit(files map[string]string) string {
+	for name, contents := range files {
+		path := filepath.Join(r.work, name)
+		if contents == "" {
+			require.NoError(r.t, os.Remove(path))
+			continue
+		}
+		require.NoError(r.t, os.MkdirAll(filepath.Dir(path), 0750))
+		require.NoError(r.t, os.WriteFile(path, []byte(contents), 0600))
+	}
+	r.git(r.work, "add", "--all")
+	r.git(r.work, "commit", "--quiet", "--allow-empty", "-m", "update")
+	r.git(r.work, "push", "--quiet", r.origin, "HEAD:refs/heads/main")
+	return r.git(r.work, "rev-parse", "HEAD")
+}
+
+func newTestStore(t *testing.T) store.Store {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	s := store.NewMapStore(ctx, store.Options{
+		SessionsSecret:   "super-secret-key",
+		MaxEventsToMerge: 1,
+	}, zap.NewNop())
+	_, err := s.ApplyResources([]model.Resource{model.NewSourceType("macos", []model.ParameterDefinition{})})
+	require.NoError(t, err)
+	return s
+}
+
+func changes(status *model.GitOpsSyncStatus) map[string]model.UpdateStatus {
+	result := map[string]model.UpdateStatus{}
+	for _, change := range status.Changes {
+		result[resourceKey(change.Kind, change.Name)] = change.Status
+	}
+	return result
+}
+
+func sourceCommit(t *testing.T, s store.Store, name string) string {
+	source, err := s.Source(name)
+	require.NoError(t, err)
+	require.NotNil(t, source)
+	return source.GetLabels().Get(model.LabelBindPlaneGitOpsCommit)
+}
+
+func configurationCommit(t *testing.T, s store.Store, name string) string {
+	configuration, err := s.Configuration(name)
+	require.NoError(t, err)
+	require.NotNil(t, configuration)
+	return configuration.GetLabels().Get(model.LabelBindPlaneGitOpsCommit)
+}
+
+func TestSync(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	s := newTestStore(t)
+
+	controller := NewController(s, Settings{
+		Repository: repo.origin,
+		Branch:     "main",
+		Path:       "prod",
+		Directory:  filepath.Join(t.TempDir(), "gitops"),
+		Prune:      true,
+	})
+
+	// files outside of the path and files that aren't yaml are ignored
+	first := repo.commit(map[string]string{
+		"prod/source.yaml":        testSource,
+		"prod/configuration.yaml": fmt.Sprintf(testConfiguration, "blue"),
+		"prod/README.md":          "not a resource",
+		"staging/source.yaml":     strings.Replace(testSource, "macos-1", "macos-2", 1),
+	})
+
+	t.Run("applies new resources", func(t *testing.T) {
+		status, err := controller.Sync(ctx)
+		require.NoError(t, err)
+		require.Equal(t, first, status.Commit)
+		require.Equal(t, map[string]model.UpdateStatus{
+			resourceKey(model.KindSource, "macos-1"):     model.StatusCreated,
+			resourceKey(model.KindConfiguration, "prod"): model.StatusCreated,
+		}, changes(status))
+
+		require.Equal(t, first, sourceCommit(t, s, "macos-1"))
+		require.Equal(t, first, configurationCommit(t, s, "prod"))
+
+		saved, err := s.GitOpsSyncStatus(ctx)
+		require.NoError(t, err)
+		require.Equal(t, status, saved)
+	})
+
+	t.Run("skips unchanged resources", func(t *testing.T) {
+		second := repo.commit(map[string]string{"prod/README.md": "still not a resource"})
+
+		status, err := controller.Sync(ctx)
+		require.NoError(t, err)
+		require.Equal(t, second, status.Commit)
+		require.Empty(t, status.Changes)
+		require.Equal(t, first, sourceCommit(t, s, "macos-1"), "unchanged resources keep their commit")
+	})
+
+	t.Run("applies changed resources", func(t *testing.T) {
+		third := repo.commit(map[string]string{"prod/configuration.yaml": fmt.Sprintf(testConfiguration, "green")})
+
+		status, err := controller.Sync(ctx)
+		require.NoError(t, err)
+		require.Equal(t, map[string]model.UpdateStatus{
+			resourceKey(model.KindConfiguration, "prod"): model.StatusConfigured,
+		}, changes(status))
+		require.Equal(t, first, sourceCommit(t, s, "macos-1"))
+		require.Equal(t, third, configurationCommit(t, s, "prod"))
+	})
+
+	t.Run("does not prune when the repository contains errors", func(t *testing.T) {
+		repo.commit(map[string]string{
+			"prod/source.yaml": "",
+			"prod/broken.yaml": "kind: [",
+		})
+
+		status, err := controller.Sync(ctx)
+		require.Error(t, err)
+		require.Len(t, status.Errors, 2)
+		require.Empty(t, status.Changes)
+
+		source, err := s.Source("macos-1")
+		require.NoError(t, err)
+		require.NotNil(t, source)
+	})
+
+	t.Run("prunes removed resources", func(t *testing.T) {
+		// unmanaged resources are never pruned
+		_, err := s.ApplyResources([]model.Resource{model.NewSource("manual", "macos", []model.Parameter{})})
+		require.NoError(t, err)
+
+		repo.commit(map[string]string{
+			"prod/broken.yaml":        "",
+			"prod/configuration.yaml": testConfigurationWithoutSources,
+		})
+
+		status, err := controller.Sync(ctx)
+		require.NoError(t, err)
+		require.Equal(t, map[string]model.UpdateStatus{
+			resourceKey(model.KindConfiguration, "prod"): model.StatusConfigured,
+			resourceKey(model.KindSource, "macos-1"):     model.StatusDeleted,
+		}, changes(status))
+
+		source, err := s.Source("macos-1")
+		require.NoError(t, err)
+		require.Nil(t, source)
+		manual, err := s.Source("manual")
+		require.NoError(t, err)
+		require.NotNil(t, manual)
+	})
+
+	t.Run("keeps the previous commit when the repository can't be pulled", func(t *testing.T) {
+		previous, err := s.GitOpsSyncStatus(ctx)
+		require.NoError(t, err)
+
+		broken := NewController(s, Settings{
+			Repository: filepath.Join(t.TempDir(), "missing.git"),
+			Branch:     "main",
+			Directory:  filepath.Join(t.TempDir(), "gitops"),
+		})
+		status, err := broken.Sync(ctx)
+		require.ErrorContains(t, err, "failed to pull repository")
+		require.Equal(t, previous.Commit, status.Commit)
+		require.WithinDuration(t, time.Now(), status.SyncedAt, time.Minute)
+	})
+}
+
+func TestPullChangedRepository(t *testing.T) {
+	ctx := context.Background()
+	first := newTestRepository(t)
+	second := newTestRepository(t)
+	firstCommit := first.commit(map[string]string{"source.yaml": testSource})
+	secondCommit := second.commit(map[string]string{"source.yaml": testSource})
+
+	dir := filepath.Join(t.TempDir(), "gitops")
+	commit, err := (&repository{url: first.origin, branch: "main", dir: dir}).pull(ctx)
+	require.NoError(t, err)
+	require.Equal(t, firstCommit, commit)
+
+	// a clone of a different repository is replaced
+	commit, err = (&repository{url: second.origin, branch: "main", dir: dir}).pull(ctx)
+	require.NoError(t, err)
+	require.Equal(t, secondCommit, commit)
+}
@@ -0,0 +1,122 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitops
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// repository is a clone of the GitOps repository that is updated with the git command
+type repository struct {
+	url    string
+	branch string
+	dir    string
+}
+
+// pull clones the repository if it hasn't been cloned, or fetches the branch and resets the working tree to it. It
+// returns the commit of the working tree.
+func (r *repository) pull(ctx context.Context) (string, error) {
+	cloned, err := r.cloned(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if !cloned {
+		if err := os.RemoveAll(r.dir); err != nil {
+			return "", fmt.Errorf("failed to remove previous clone: %w", err)
+		}
+		if err := os.MkdirAll(filepath.Dir(r.dir), 0750); err != nil {
+			return "", fmt.Errorf("failed to create gitops directory: %w", err)
+		}
+		if _, err := git(ctx, "", "clone", "--quiet", "--single-branch", "--branch", r.branch, "--", r.url, r.dir); err != nil {
+			return "", err
+		}
+	} else {
+		refspec := fmt.Sprintf("+refs/heads/%s:refs/remotes/origin/%s", r.branch, r.branch)
+		if _, err := git(ctx, r.dir, "fetch", "--quiet", "origin", refspec); err != nil {
+			return "", err
+		}
+		if _, err := git(ctx, r.dir, "reset", "--quiet", "--hard", "refs/remotes/origin/"+r.branch); err != nil {
+			return "", err
+		}
+	}
+
+	return git(ctx, r.dir, "rev-parse", "HEAD")
+}
+
+// cloned returns true if the directory contains a clone of the repository. A clone of a different repository, e.g.
+// after the configured repository changes, must be cloned again.
+func (r *repository) cloned(ctx context.Context) (bool, error) {
+	_, err := os.Stat(filepath.Join(r.dir, ".git"))
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("failed to read gitops directory: %w", err)
+	}
+
+	origin, err := git(ctx, r.dir, "config", "--get", "remote.origin.url")
+	if err != nil {
+		return false, nil
+	}
+	return origin == r.url, nil
+}
+
+// git runs the git command in the directory and returns its trimmed output
+func git(ctx context.Context, dir string, args ...string) (string, error) {
+	// #nosec G204 - git is run with arguments from the server configuration
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	// fail instead of waiting for credentials to be entered
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return "", fmt.Errorf("git %s: %w: %s", args[0], err, message)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// redactURL removes credentials from a repository URL so that it can be displayed. Tokens are often used as the
+// username of https URLs, so the username of an http or https URL is redacted as well as the password.
+func redactURL(repository string) string {
+	u, err := url.Parse(repository)
+	if err != nil || u.User == nil {
+		return repository
+	}
+	_, hasPassword := u.User.Password()
+	switch {
+	case hasPassword:
+		return u.Redacted()
+	case u.Scheme == "http" || u.Scheme == "https":
+		u.User = url.User("xxxxx")
+		return u.String()
+	default:
+		return repository
+	}
+}
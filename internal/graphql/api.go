@@ -15,19 +15,23 @@
 package graphql
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"time"
 
+	"github.com/99designs/gqlgen/graphql"
 	"github.com/99designs/gqlgen/graphql/handler"
 	"github.com/99designs/gqlgen/graphql/handler/extension"
 	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/99designs/gqlgen/graphql/playground"
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
+	"github.com/vektah/gqlparser/v2/ast"
 
 	"github.com/observiq/bindplane-op/internal/graphql/generated"
 	"github.com/observiq/bindplane-op/internal/server"
+	"github.com/observiq/bindplane-op/internal/server/auth"
 )
 
 // AddRoutes TODO(doc)
@@ -61,5 +65,26 @@ func newHandler(bindplane server.BindPlane) *handler.Server {
 		},
 	})
 	srv.Use(extension.Introspection{})
+	srv.AroundOperations(requireCSRF)
+	srv.AroundOperations(requireWrite)
 	return srv
 }
+
+// requireCSRF rejects mutations from requests authenticated with a session cookie that don't have the CSRF token of
+// the session
+func requireCSRF(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	operation := graphql.GetOperationContext(ctx).Operation
+	if operation != nil && operation.Operation == ast.Mutation && !auth.CSRFVerified(ctx) {
+		return graphql.OneShot(graphql.ErrorResponse(ctx, "missing or invalid csrf token"))
+	}
+	return next(ctx)
+}
+
+// requireWrite rejects mutations from users with a role that can't modify resources
+func requireWrite(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	operation := graphql.GetOperationContext(ctx).Operation
+	if operation != nil && operation.Operation == ast.Mutation && !auth.CanWrite(ctx) {
+		return graphql.OneShot(graphql.ErrorResponse(ctx, "forbidden"))
+	}
+	return next(ctx)
+}
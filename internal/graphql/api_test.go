@@ -0,0 +1,123 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/ast"
+
+	"github.com/observiq/bindplane-op/internal/server/auth"
+	"github.com/observiq/bindplane-op/model"
+)
+
+func TestRequireCSRF(t *testing.T) {
+	tests := []struct {
+		name      string
+		operation ast.Operation
+		verified  bool
+		expectRun bool
+	}{
+		{
+			name:      "query without token",
+			operation: ast.Query,
+			expectRun: true,
+		},
+		{
+			name:      "mutation with token",
+			operation: ast.Mutation,
+			verified:  true,
+			expectRun: true,
+		},
+		{
+			name:      "mutation without token",
+			operation: ast.Mutation,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := auth.WithCSRFVerified(context.Background(), test.verified)
+			ctx = graphql.WithOperationContext(ctx, &graphql.OperationContext{
+				Operation: &ast.OperationDefinition{Operation: test.operation},
+			})
+			ctx = graphql.WithResponseContext(ctx, graphql.DefaultErrorPresenter, graphql.DefaultRecover)
+
+			var ran bool
+			response := requireCSRF(ctx, func(ctx context.Context) graphql.ResponseHandler {
+				ran = true
+				return graphql.OneShot(&graphql.Response{})
+			})(ctx)
+
+			require.Equal(t, test.expectRun, ran)
+			if !test.expectRun {
+				require.Len(t, response.Errors, 1)
+				require.Equal(t, "missing or invalid csrf token", response.Errors[0].Message)
+			}
+		})
+	}
+}
+
+func TestRequireWrite(t *testing.T) {
+	tests := []struct {
+		name      string
+		operation ast.Operation
+		role      model.Role
+		expectRun bool
+	}{
+		{
+			name:      "viewer query",
+			operation: ast.Query,
+			role:      model.RoleViewer,
+			expectRun: true,
+		},
+		{
+			name:      "admin mutation",
+			operation: ast.Mutation,
+			role:      model.RoleAdmin,
+			expectRun: true,
+		},
+		{
+			name:      "viewer mutation",
+			operation: ast.Mutation,
+			role:      model.RoleViewer,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := auth.WithRole(context.Background(), test.role)
+			ctx = graphql.WithOperationContext(ctx, &graphql.OperationContext{
+				Operation: &ast.OperationDefinition{Operation: test.operation},
+			})
+			ctx = graphql.WithResponseContext(ctx, graphql.DefaultErrorPresenter, graphql.DefaultRecover)
+
+			var ran bool
+			response := requireWrite(ctx, func(ctx context.Context) graphql.ResponseHandler {
+				ran = true
+				return graphql.OneShot(&graphql.Response{})
+			})(ctx)
+
+			require.Equal(t, test.expectRun, ran)
+			if !test.expectRun {
+				require.Len(t, response.Errors, 1)
+				require.Equal(t, "forbidden", response.Errors[0].Message)
+			}
+		})
+	}
+}
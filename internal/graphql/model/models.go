@@ -64,6 +64,15 @@ func ToConfigurationChange(event store.Event[*model.Configuration]) *Configurati
 	}
 }
 
+// ToChanges converts store.Events to an array of changes for use with graphql, e.g. []*SourceChange
+func ToChanges[T model.Resource, C any](events store.Events[T], change func(item T, eventType EventType) C) []C {
+	result := []C{}
+	for _, event := range events {
+		result = append(result, change(event.Item, ToEventType(event.Type)))
+	}
+	return result
+}
+
 // ToEventType converts a store.EventType to a graphql EventType
 func ToEventType(eventType store.EventType) EventType {
 	switch eventType {
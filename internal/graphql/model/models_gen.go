@@ -22,11 +22,23 @@ type AgentConfiguration struct {
 	Manager   map[string]interface{} `json:"Manager"`
 }
 
+type AgentResult struct {
+	ID     string        `json:"id"`
+	Agent  *model.Agent  `json:"agent"`
+	Errors []*FieldError `json:"errors"`
+}
+
+type AgentVersionChange struct {
+	AgentVersion *model.AgentVersion `json:"agentVersion"`
+	EventType    EventType           `json:"eventType"`
+}
+
 type Agents struct {
 	Query         *string              `json:"query"`
 	Agents        []*model.Agent       `json:"agents"`
 	Suggestions   []*search.Suggestion `json:"suggestions"`
 	LatestVersion string               `json:"latestVersion"`
+	Continue      *string              `json:"continue"`
 }
 
 type Components struct {
@@ -43,6 +55,22 @@ type Configurations struct {
 	Query          *string                `json:"query"`
 	Configurations []*model.Configuration `json:"configurations"`
 	Suggestions    []*search.Suggestion   `json:"suggestions"`
+	Continue       *string                `json:"continue"`
+}
+
+type DestinationChange struct {
+	Destination *model.Destination `json:"destination"`
+	EventType   EventType          `json:"eventType"`
+}
+
+type DestinationTypeChange struct {
+	DestinationType *model.DestinationType `json:"destinationType"`
+	EventType       EventType              `json:"eventType"`
+}
+
+type DestinationTypesPage struct {
+	DestinationTypes []*model.DestinationType `json:"destinationTypes"`
+	Continue         *string                  `json:"continue"`
 }
 
 type DestinationWithType struct {
@@ -50,6 +78,90 @@ type DestinationWithType struct {
 	DestinationType *model.DestinationType `json:"destinationType"`
 }
 
+type DestinationsPage struct {
+	Destinations []*model.Destination `json:"destinations"`
+	Continue     *string              `json:"continue"`
+}
+
+type FieldError struct {
+	Field   *string `json:"field"`
+	Message string  `json:"message"`
+}
+
+type MetadataInput struct {
+	Name        string                 `json:"name"`
+	DisplayName *string                `json:"displayName"`
+	Description *string                `json:"description"`
+	Icon        *string                `json:"icon"`
+	Labels      map[string]interface{} `json:"labels"`
+}
+
+type ProcessorChange struct {
+	Processor *model.Processor `json:"processor"`
+	EventType EventType        `json:"eventType"`
+}
+
+type ProcessorTypeChange struct {
+	ProcessorType *model.ProcessorType `json:"processorType"`
+	EventType     EventType            `json:"eventType"`
+}
+
+type ProcessorTypesPage struct {
+	ProcessorTypes []*model.ProcessorType `json:"processorTypes"`
+	Continue       *string                `json:"continue"`
+}
+
+type ProcessorsPage struct {
+	Processors []*model.Processor `json:"processors"`
+	Continue   *string            `json:"continue"`
+}
+
+type ResourceChange struct {
+	Kind      string         `json:"kind"`
+	Name      string         `json:"name"`
+	Resource  model.Resource `json:"resource"`
+	EventType EventType      `json:"eventType"`
+}
+
+type ResourceInput struct {
+	Kind     string                 `json:"kind"`
+	Metadata *MetadataInput         `json:"metadata"`
+	Spec     map[string]interface{} `json:"spec"`
+}
+
+type ResourceKeyInput struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+}
+
+type ResourceResult struct {
+	Kind   string        `json:"kind"`
+	Name   string        `json:"name"`
+	Status string        `json:"status"`
+	Reason *string       `json:"reason"`
+	Errors []*FieldError `json:"errors"`
+}
+
+type SourceChange struct {
+	Source    *model.Source `json:"source"`
+	EventType EventType     `json:"eventType"`
+}
+
+type SourceTypeChange struct {
+	SourceType *model.SourceType `json:"sourceType"`
+	EventType  EventType         `json:"eventType"`
+}
+
+type SourceTypesPage struct {
+	SourceTypes []*model.SourceType `json:"sourceTypes"`
+	Continue    *string             `json:"continue"`
+}
+
+type SourcesPage struct {
+	Sources  []*model.Source `json:"sources"`
+	Continue *string         `json:"continue"`
+}
+
 type AgentChangeType string
 
 const (
@@ -136,6 +248,49 @@ func (e EventType) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
+type ParameterRuleType string
+
+const (
+	ParameterRuleTypeMutuallyExclusive ParameterRuleType = "mutuallyExclusive"
+	ParameterRuleTypeRequires          ParameterRuleType = "requires"
+	ParameterRuleTypeAtLeastOneOf      ParameterRuleType = "atLeastOneOf"
+)
+
+var AllParameterRuleType = []ParameterRuleType{
+	ParameterRuleTypeMutuallyExclusive,
+	ParameterRuleTypeRequires,
+	ParameterRuleTypeAtLeastOneOf,
+}
+
+func (e ParameterRuleType) IsValid() bool {
+	switch e {
+	case ParameterRuleTypeMutuallyExclusive, ParameterRuleTypeRequires, ParameterRuleTypeAtLeastOneOf:
+		return true
+	}
+	return false
+}
+
+func (e ParameterRuleType) String() string {
+	return string(e)
+}
+
+func (e *ParameterRuleType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ParameterRuleType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ParameterRuleType", str)
+	}
+	return nil
+}
+
+func (e ParameterRuleType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
 type ParameterType string
 
 const (
@@ -148,6 +303,12 @@ const (
 	ParameterTypeMap      ParameterType = "map"
 	ParameterTypeYaml     ParameterType = "yaml"
 	ParameterTypeTimezone ParameterType = "timezone"
+	ParameterTypeFloat    ParameterType = "float"
+	ParameterTypeDuration ParameterType = "duration"
+	ParameterTypeHostport ParameterType = "hostport"
+	ParameterTypeURL      ParameterType = "url"
+	ParameterTypeRegex    ParameterType = "regex"
+	ParameterTypeObjects  ParameterType = "objects"
 )
 
 var AllParameterType = []ParameterType{
@@ -160,11 +321,17 @@ var AllParameterType = []ParameterType{
 	ParameterTypeMap,
 	ParameterTypeYaml,
 	ParameterTypeTimezone,
+	ParameterTypeFloat,
+	ParameterTypeDuration,
+	ParameterTypeHostport,
+	ParameterTypeURL,
+	ParameterTypeRegex,
+	ParameterTypeObjects,
 }
 
 func (e ParameterType) IsValid() bool {
 	switch e {
-	case ParameterTypeString, ParameterTypeStrings, ParameterTypeInt, ParameterTypeBool, ParameterTypeEnum, ParameterTypeEnums, ParameterTypeMap, ParameterTypeYaml, ParameterTypeTimezone:
+	case ParameterTypeString, ParameterTypeStrings, ParameterTypeInt, ParameterTypeBool, ParameterTypeEnum, ParameterTypeEnums, ParameterTypeMap, ParameterTypeYaml, ParameterTypeTimezone, ParameterTypeFloat, ParameterTypeDuration, ParameterTypeHostport, ParameterTypeURL, ParameterTypeRegex, ParameterTypeObjects:
 		return true
 	}
 	return false
@@ -194,16 +361,28 @@ func (e ParameterType) MarshalGQL(w io.Writer) {
 type RelevantIfOperatorType string
 
 const (
-	RelevantIfOperatorTypeEquals RelevantIfOperatorType = "equals"
+	RelevantIfOperatorTypeEquals      RelevantIfOperatorType = "equals"
+	RelevantIfOperatorTypeNotEquals   RelevantIfOperatorType = "notEquals"
+	RelevantIfOperatorTypeIn          RelevantIfOperatorType = "in"
+	RelevantIfOperatorTypeContainsAny RelevantIfOperatorType = "containsAny"
+	RelevantIfOperatorTypeExists      RelevantIfOperatorType = "exists"
+	RelevantIfOperatorTypeAnd         RelevantIfOperatorType = "and"
+	RelevantIfOperatorTypeOr          RelevantIfOperatorType = "or"
 )
 
 var AllRelevantIfOperatorType = []RelevantIfOperatorType{
 	RelevantIfOperatorTypeEquals,
+	RelevantIfOperatorTypeNotEquals,
+	RelevantIfOperatorTypeIn,
+	RelevantIfOperatorTypeContainsAny,
+	RelevantIfOperatorTypeExists,
+	RelevantIfOperatorTypeAnd,
+	RelevantIfOperatorTypeOr,
 }
 
 func (e RelevantIfOperatorType) IsValid() bool {
 	switch e {
-	case RelevantIfOperatorTypeEquals:
+	case RelevantIfOperatorTypeEquals, RelevantIfOperatorTypeNotEquals, RelevantIfOperatorTypeIn, RelevantIfOperatorTypeContainsAny, RelevantIfOperatorTypeExists, RelevantIfOperatorTypeAnd, RelevantIfOperatorTypeOr:
 		return true
 	}
 	return false
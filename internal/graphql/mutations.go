@@ -0,0 +1,371 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	model1 "github.com/observiq/bindplane-op/internal/graphql/model"
+	"github.com/observiq/bindplane-op/internal/server"
+	"github.com/observiq/bindplane-op/model"
+	"github.com/observiq/bindplane-op/model/validation"
+)
+
+// resourceKey identifies a resource in the results of a mutation. Names are only unique within a kind.
+type resourceKey struct {
+	kind model.Kind
+	name string
+}
+
+func keyOf(resource model.Resource) resourceKey {
+	return resourceKey{kind: resource.GetKind(), name: resource.Name()}
+}
+
+// parseResourceInput converts the input to a resource. Errors are FieldErrors that identify the invalid input.
+func parseResourceInput(input *model1.ResourceInput) (model.Resource, error) {
+	kind := model.ParseKind(input.Kind)
+	if kind == model.KindUnknown {
+		return nil, validation.NewFieldError("kind", fmt.Errorf("%s is not a valid resource kind", input.Kind))
+	}
+
+	metadata := model.Metadata{Name: input.Metadata.Name}
+	if input.Metadata.DisplayName != nil {
+		metadata.DisplayName = *input.Metadata.DisplayName
+	}
+	if input.Metadata.Description != nil {
+		metadata.Description = *input.Metadata.Description
+	}
+	if input.Metadata.Icon != nil {
+		metadata.Icon = *input.Metadata.Icon
+	}
+	labels, err := labelsInput(input.Metadata.Labels)
+	if err != nil {
+		return nil, validation.NewFieldError("metadata.labels", err)
+	}
+	// labels are validated with the rest of the resource when it is applied
+	metadata.Labels = model.LabelsFromValidatedMap(labels)
+
+	spec, err := specInput(input.Spec)
+	if err != nil {
+		return nil, validation.NewFieldError("spec", err)
+	}
+
+	resource, err := model.ParseResource(&model.AnyResource{
+		ResourceMeta: model.ResourceMeta{
+			APIVersion: model.V1,
+			Kind:       kind,
+			Metadata:   metadata,
+		},
+		Spec: spec,
+	})
+	if err != nil {
+		return nil, validation.NewFieldError("spec", err)
+	}
+	return resource, nil
+}
+
+// specInput decodes the spec of a Map input the same way as the spec of a resource applied with REST. Numbers are
+// json.Number in a Map input and float64 in parsed JSON.
+func specInput(input map[string]any) (map[string]any, error) {
+	if input == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+	var spec map[string]any
+	err = json.Unmarshal(data, &spec)
+	return spec, err
+}
+
+// labelsInput converts the labels of a Map input to label names and values, which must be strings. A null value is
+// the same as an empty value.
+func labelsInput(input map[string]any) (map[string]string, error) {
+	labels := make(map[string]string, len(input))
+	for name, value := range input {
+		switch value := value.(type) {
+		case string:
+			labels[name] = value
+		case nil:
+			labels[name] = ""
+		default:
+			return nil, fmt.Errorf("value of label %s must be a string", name)
+		}
+	}
+	return labels, nil
+}
+
+// fieldErrors converts an error returned by validation to a FieldError for each error
+func fieldErrors(err error) []*model1.FieldError {
+	result := []*model1.FieldError{}
+	for _, fieldErr := range validation.FieldErrors(err) {
+		result = append(result, newFieldError(fieldErr.Field, fieldErr))
+	}
+	return result
+}
+
+// newFieldError returns a FieldError with a null field if field is empty
+func newFieldError(field string, err error) *model1.FieldError {
+	fieldErr := &model1.FieldError{Message: err.Error()}
+	if field != "" {
+		fieldErr.Field = &field
+	}
+	return fieldErr
+}
+
+// invalidResult returns the result for input that could not be parsed or applied
+func invalidResult(kind string, name string, err error) *model1.ResourceResult {
+	return &model1.ResourceResult{
+		Kind:   kind,
+		Name:   name,
+		Status: string(model.StatusInvalid),
+		Errors: fieldErrors(err),
+	}
+}
+
+// resourceResult converts the status of an applied or deleted resource to a ResourceResult. The store only reports
+// the reason an invalid resource was rejected, so the resource is validated again to report an error for each field.
+func (r *Resolver) resourceResult(status model.ResourceStatus) *model1.ResourceResult {
+	result := &model1.ResourceResult{
+		Kind:   string(status.Resource.GetKind()),
+		Name:   status.Resource.Name(),
+		Status: string(status.Status),
+		Errors: []*model1.FieldError{},
+	}
+	if status.Reason != "" {
+		result.Reason = &status.Reason
+	}
+
+	switch status.Status {
+	case model.StatusInvalid:
+		if _, err := status.Resource.ValidateWithStore(r.bindplane.Store()); err != nil {
+			result.Errors = fieldErrors(err)
+		}
+		if len(result.Errors) == 0 {
+			result.Errors = []*model1.FieldError{{Message: status.Reason}}
+		}
+	case model.StatusError, model.StatusInUse:
+		result.Errors = []*model1.FieldError{{Message: status.Reason}}
+	}
+	return result
+}
+
+// applyResources applies the resources that can be parsed and returns a result for each input in the same order
+func (r *Resolver) applyResources(inputs []*model1.ResourceInput) ([]*model1.ResourceResult, error) {
+	results := make([]*model1.ResourceResult, len(inputs))
+	resources := make([]model.Resource, 0, len(inputs))
+	positions := map[resourceKey][]int{}
+	for i, input := range inputs {
+		resource, err := parseResourceInput(input)
+		if err != nil {
+			results[i] = invalidResult(input.Kind, input.Metadata.Name, err)
+			continue
+		}
+		resources = append(resources, resource)
+		positions[keyOf(resource)] = append(positions[keyOf(resource)], i)
+	}
+
+	r.bindplane.Logger().Info("graphql applyResources", zap.Int("count", len(resources)))
+
+	statuses, err := r.bindplane.Store().ApplyResources(resources)
+	if err != nil {
+		return nil, err
+	}
+	for _, status := range statuses {
+		for _, i := range positions[keyOf(status.Resource)] {
+			results[i] = r.resourceResult(status)
+		}
+	}
+
+	// resources without a status were skipped by the store
+	for _, resource := range resources {
+		for _, i := range positions[keyOf(resource)] {
+			if results[i] == nil {
+				results[i] = r.resourceResult(*model.NewResourceStatus(resource, model.StatusUnchanged))
+			}
+		}
+	}
+	return results, nil
+}
+
+// deleteResources deletes the resources and returns a result for each input in the same order
+func (r *Resolver) deleteResources(inputs []*model1.ResourceKeyInput) ([]*model1.ResourceResult, error) {
+	results := make([]*model1.ResourceResult, len(inputs))
+	resources := make([]model.Resource, 0, len(inputs))
+	positions := map[resourceKey][]int{}
+	for i, input := range inputs {
+		resource, err := parseResourceInput(&model1.ResourceInput{
+			Kind:     input.Kind,
+			Metadata: &model1.MetadataInput{Name: input.Name},
+		})
+		if err != nil {
+			results[i] = invalidResult(input.Kind, input.Name, err)
+			continue
+		}
+		resources = append(resources, resource)
+		positions[keyOf(resource)] = append(positions[keyOf(resource)], i)
+	}
+
+	r.bindplane.Logger().Info("graphql deleteResources", zap.Int("count", len(resources)))
+
+	statuses, err := r.bindplane.Store().DeleteResources(resources)
+	if err != nil {
+		return nil, err
+	}
+	for _, status := range statuses {
+		for _, i := range positions[keyOf(status.Resource)] {
+			results[i] = r.resourceResult(status)
+		}
+	}
+
+	// the store doesn't report resources that don't exist
+	for _, resource := range resources {
+		for _, i := range positions[keyOf(resource)] {
+			if results[i] == nil {
+				reason := fmt.Sprintf("%s %s not found", resource.GetKind(), resource.Name())
+				results[i] = r.resourceResult(*model.NewResourceStatusWithReason(resource, model.StatusUnchanged, reason))
+			}
+		}
+	}
+	return results, nil
+}
+
+// updateAgents calls update for each agent that exists and returns a result for each id in the same order. update
+// returns the updated agent or a FieldError if the agent can't be updated.
+func (r *Resolver) updateAgents(ids []string, update func(agent *model.Agent) (*model.Agent, *model1.FieldError, error)) ([]*model1.AgentResult, error) {
+	results := make([]*model1.AgentResult, 0, len(ids))
+	for _, id := range ids {
+		result := &model1.AgentResult{ID: id, Errors: []*model1.FieldError{}}
+		results = append(results, result)
+
+		agent, err := r.bindplane.Store().Agent(id)
+		if err != nil {
+			return nil, err
+		}
+		if agent == nil {
+			result.Errors = append(result.Errors, newFieldError("ids", fmt.Errorf("agent %s not found", id)))
+			continue
+		}
+
+		updated, fieldErr, err := update(agent)
+		if err != nil {
+			return nil, err
+		}
+		result.Agent = updated
+		if fieldErr != nil {
+			result.Errors = append(result.Errors, fieldErr)
+		}
+	}
+	return results, nil
+}
+
+// labelAgents merges the labels with the labels of each agent
+func (r *Resolver) labelAgents(ctx context.Context, ids []string, input map[string]any, overwrite bool) ([]*model1.AgentResult, error) {
+	labels, labelsErr := labelsInput(input)
+	var newLabels model.Labels
+	if labelsErr == nil {
+		newLabels, labelsErr = model.LabelsFromMap(labels)
+	}
+
+	r.bindplane.Logger().Info("graphql labelAgents", zap.String("labels", newLabels.String()), zap.Strings("ids", ids))
+
+	return r.updateAgents(ids, func(agent *model.Agent) (*model.Agent, *model1.FieldError, error) {
+		switch {
+		case labelsErr != nil:
+			return agent, newFieldError("labels", labelsErr), nil
+		case !overwrite && agent.Labels.Conflicts(newLabels):
+			return agent, newFieldError("labels", errors.New("new labels conflict with existing labels, set overwrite to replace labels")), nil
+		}
+		updated, err := r.bindplane.Store().UpsertAgent(ctx, agent.ID, func(current *model.Agent) {
+			current.Labels = model.LabelsFromMerge(current.Labels, newLabels)
+		})
+		return updated, nil, err
+	})
+}
+
+// upgradeAgents starts an upgrade of each agent to the version or the latest version if version is empty
+func (r *Resolver) upgradeAgents(ctx context.Context, ids []string, version string) ([]*model1.AgentResult, error) {
+	if version == "" {
+		version = r.bindplane.Versions().LatestVersionString()
+	}
+
+	r.bindplane.Logger().Info("graphql upgradeAgents", zap.String("version", version), zap.Strings("ids", ids))
+
+	return r.updateAgents(ids, func(agent *model.Agent) (*model.Agent, *model1.FieldError, error) {
+		if !agent.SupportsUpgrade() {
+			return agent, newFieldError("", fmt.Errorf("agent %s with version %s does not support upgrade", agent.ID, agent.Version)), nil
+		}
+		updated, err := r.bindplane.Store().UpsertAgent(ctx, agent.ID, func(current *model.Agent) {
+			current.UpgradeTo(version)
+		})
+		return updated, nil, err
+	})
+}
+
+// restartAgents sends a restart to each agent. The agent may be connected to another node, which will send the
+// restart command.
+func (r *Resolver) restartAgents(ctx context.Context, ids []string) ([]*model1.AgentResult, error) {
+	r.bindplane.Logger().Info("graphql restartAgents", zap.Strings("ids", ids))
+
+	return r.updateAgents(ids, func(agent *model.Agent) (*model.Agent, *model1.FieldError, error) {
+		err := r.bindplane.Manager().SendAgentUpdates(ctx, agent, &server.AgentUpdates{Restart: true})
+		switch {
+		case errors.Is(err, server.ErrAgentNotConnected):
+			return agent, newFieldError("", fmt.Errorf("agent %s is not connected", agent.ID)), nil
+		case err != nil:
+			return nil, nil, err
+		}
+		return agent, nil, nil
+	})
+}
+
+// copyConfiguration applies a duplicate of the configuration with the new name
+func (r *Resolver) copyConfiguration(name string, newName string) (*model1.ResourceResult, error) {
+	kind := string(model.KindConfiguration)
+
+	config, err := r.bindplane.Store().Configuration(name)
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		return invalidResult(kind, newName, validation.NewFieldError("name", fmt.Errorf("no configuration with name %s found", name))), nil
+	}
+
+	existing, err := r.bindplane.Store().Configuration(newName)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return invalidResult(kind, newName, validation.NewFieldError("newName", errors.New("a configuration with that name already exists"))), nil
+	}
+
+	duplicate := config.Duplicate(newName)
+	statuses, err := r.bindplane.Store().ApplyResources([]model.Resource{duplicate})
+	if err != nil {
+		return nil, err
+	}
+	for _, status := range statuses {
+		if keyOf(status.Resource) == keyOf(duplicate) {
+			return r.resourceResult(status), nil
+		}
+	}
+	return r.resourceResult(*model.NewResourceStatus(duplicate, model.StatusUnchanged)), nil
+}
@@ -16,13 +16,16 @@ package graphql
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/observiq/bindplane-op/internal/eventbus"
+	model1 "github.com/observiq/bindplane-op/internal/graphql/model"
 	"github.com/observiq/bindplane-op/internal/server"
 	"github.com/observiq/bindplane-op/internal/store"
 	"github.com/observiq/bindplane-op/internal/store/search"
 	"github.com/observiq/bindplane-op/model"
 	"go.opentelemetry.io/otel"
+	"golang.org/x/exp/slices"
 )
 
 var tracer = otel.Tracer("graphql")
@@ -98,13 +101,21 @@ func applySelectorToEvents[T model.Resource](selector *model.Selector, events st
 	return result
 }
 
+// applyQueryToEvents changes events for resources that don't match the query to Remove. Resources without an index are
+// matched with search.Matches.
 func applyQueryToEvents[T model.Resource](query *search.Query, index search.Index, events store.Events[T]) store.Events[T] {
-	if query == nil || index == nil {
+	if query == nil {
 		return events
 	}
+	matches := func(item T) bool {
+		if index == nil {
+			return search.Matches(query, item)
+		}
+		return index.Matches(query, item.Name())
+	}
 	result := store.NewEvents[T]()
 	for _, event := range events {
-		if event.Type != store.EventTypeRemove && !index.Matches(query, event.Item.Name()) {
+		if event.Type != store.EventTypeRemove && !matches(event.Item) {
 			result.Include(event.Item, store.EventTypeRemove)
 		} else {
 			result.Include(event.Item, event.Type)
@@ -113,6 +124,107 @@ func applyQueryToEvents[T model.Resource](query *search.Query, index search.Inde
 	return result
 }
 
+// applyNameToEvents removes events for resources that don't have the name, if specified
+func applyNameToEvents[T model.Resource](name *string, events store.Events[T]) store.Events[T] {
+	if name == nil {
+		return events
+	}
+	result := store.NewEvents[T]()
+	for _, event := range events {
+		if event.Item.Name() == *name {
+			result.Include(event.Item, event.Type)
+		}
+	}
+	return result
+}
+
+// resourceFilter filters the events of a resource subscription by the name, selector, and query arguments
+type resourceFilter struct {
+	name     *string
+	selector *model.Selector
+	query    *search.Query
+}
+
+func (r *Resolver) parseResourceFilter(name *string, selector *string, query *string) (*resourceFilter, error) {
+	parsedSelector, parsedQuery, err := r.parseSelectorAndQuery(selector, query)
+	if err != nil {
+		return nil, err
+	}
+	return &resourceFilter{name: name, selector: parsedSelector, query: parsedQuery}, nil
+}
+
+func applyResourceFilter[T model.Resource](filter *resourceFilter, index search.Index, events store.Events[T]) store.Events[T] {
+	events = applyNameToEvents(filter.name, events)
+	events = applySelectorToEvents(filter.selector, events)
+	return applyQueryToEvents(filter.query, index, events)
+}
+
+// subscribeResourceChanges subscribes to the events of one kind of resource, selected from the updates by events and
+// converted to the change type of the subscription by change
+func subscribeResourceChanges[T model.Resource, C any](
+	ctx context.Context,
+	r *Resolver,
+	name, selector, query *string,
+	events func(updates *store.Updates) store.Events[T],
+	change func(item T, eventType model1.EventType) C,
+) (<-chan []C, error) {
+	filter, err := r.parseResourceFilter(name, selector, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// we can ignore the unsubscribe function because this will automatically unsubscribe when the context is done.
+	channel, _ := eventbus.SubscribeWithFilterUntilDone(ctx, r.updates, func(updates *store.Updates) (result []C, accept bool) {
+		filtered := applyResourceFilter(filter, nil, events(updates))
+		return model1.ToChanges(filtered, change), len(filtered) > 0
+	})
+	return channel, nil
+}
+
+// resourceChangeKinds are the kinds of resources with changes sent to resourceChanges subscriptions
+var resourceChangeKinds = []model.Kind{
+	model.KindConfiguration,
+	model.KindSource,
+	model.KindSourceType,
+	model.KindProcessor,
+	model.KindProcessorType,
+	model.KindDestination,
+	model.KindDestinationType,
+	model.KindAgentVersion,
+}
+
+// parseResourceChangeKinds returns the set of kinds or nil if no kinds are specified, which includes all kinds
+func parseResourceChangeKinds(kinds []string) (map[model.Kind]bool, error) {
+	if len(kinds) == 0 {
+		return nil, nil
+	}
+	result := map[model.Kind]bool{}
+	for _, kind := range kinds {
+		parsed := model.ParseKind(kind)
+		if !slices.Contains(resourceChangeKinds, parsed) {
+			return nil, fmt.Errorf("%s is not a kind of resource with changes", kind)
+		}
+		result[parsed] = true
+	}
+	return result, nil
+}
+
+// appendResourceChanges appends a ResourceChange for each event for a resource of one of the kinds
+func appendResourceChanges[T model.Resource](result []*model1.ResourceChange, kinds map[model.Kind]bool, filter *resourceFilter, index search.Index, events store.Events[T]) []*model1.ResourceChange {
+	for _, event := range applyResourceFilter(filter, index, events) {
+		if kinds != nil && !kinds[event.Item.GetKind()] {
+			continue
+		}
+		result = append(result, &model1.ResourceChange{
+			Kind:      string(event.Item.GetKind()),
+			Name:      event.Item.Name(),
+			Resource:  event.Item,
+			EventType: model1.ToEventType(event.Type),
+		})
+	}
+	return result
+}
+
 func (r *Resolver) parseSelectorAndQuery(selector *string, query *string) (*model.Selector, *search.Query, error) {
 	var parsedSelector *model.Selector
 	if selector != nil {
@@ -159,6 +271,51 @@ func (r *Resolver) queryOptionsAndSuggestions(selector *string, query *string, i
 	return options, suggestions, nil
 }
 
+// queryOptions returns the store query options for the selector and query arguments of a list query
+func (r *Resolver) queryOptions(selector *string, query *string) ([]store.QueryOption, error) {
+	parsedSelector, parsedQuery, err := r.parseSelectorAndQuery(selector, query)
+	if err != nil {
+		return nil, err
+	}
+
+	options := []store.QueryOption{}
+	if parsedSelector != nil {
+		options = append(options, store.WithSelector(*parsedSelector))
+	}
+	if parsedQuery != nil {
+		options = append(options, store.WithQuery(parsedQuery))
+	}
+	return options, nil
+}
+
+// pageOptions returns the store query options for the sort, limit, and continue arguments of a list query
+func pageOptions(sort *string, limit *int, continueArg *string) []store.QueryOption {
+	options := []store.QueryOption{}
+	if sort != nil {
+		options = append(options, store.WithSort(*sort))
+	}
+	if limit != nil {
+		options = append(options, store.WithLimit(*limit))
+	}
+	if continueArg != nil {
+		options = append(options, store.WithContinue(*continueArg))
+	}
+	return options
+}
+
+// nextContinue returns the token used to request the next page of results or nil if the page is not full
+func nextContinue[T model.HasUniqueKey](items []T, sort *string, limit *int) *string {
+	if limit == nil || *limit <= 0 || len(items) < *limit {
+		return nil
+	}
+	var sortValue string
+	if sort != nil {
+		sortValue = *sort
+	}
+	token := store.ContinueToken(items[len(items)-1], sortValue)
+	return &token
+}
+
 // hasAgentConfigurationChanges determines if there is an agent update
 // in updates that would affect the list of configurations
 func (r *Resolver) hasAgentConfigurationChanges(updates *store.Updates) bool {
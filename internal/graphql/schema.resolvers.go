@@ -85,6 +85,11 @@ func (r *agentUpgradeResolver) Status(ctx context.Context, obj *model.AgentUpgra
 	return int(obj.Status), nil
 }
 
+// Kind is the resolver for the kind field.
+func (r *agentVersionResolver) Kind(ctx context.Context, obj *model.AgentVersion) (string, error) {
+	return string(obj.GetKind()), nil
+}
+
 // Kind is the resolver for the kind field.
 func (r *configurationResolver) Kind(ctx context.Context, obj *model.Configuration) (string, error) {
 	return string(obj.GetKind()), nil
@@ -100,16 +105,41 @@ func (r *configurationResolver) AgentCount(ctx context.Context, obj *model.Confi
 	return &count, nil
 }
 
+// Usage is the resolver for the usage field.
+func (r *configurationResolver) Usage(ctx context.Context, obj *model.Configuration) (*model.ResourceUsage, error) {
+	return store.FindResourceUsage(r.bindplane.Store(), obj)
+}
+
 // Kind is the resolver for the kind field.
 func (r *destinationResolver) Kind(ctx context.Context, obj *model.Destination) (string, error) {
 	return string(obj.GetKind()), nil
 }
 
+// Usage is the resolver for the usage field.
+func (r *destinationResolver) Usage(ctx context.Context, obj *model.Destination) (*model.ResourceUsage, error) {
+	return store.FindResourceUsage(r.bindplane.Store(), obj)
+}
+
 // Kind is the resolver for the kind field.
 func (r *destinationTypeResolver) Kind(ctx context.Context, obj *model.DestinationType) (string, error) {
 	return string(obj.GetKind()), nil
 }
 
+// Usage is the resolver for the usage field.
+func (r *destinationTypeResolver) Usage(ctx context.Context, obj *model.DestinationType) (*model.ResourceUsage, error) {
+	return store.FindResourceUsage(r.bindplane.Store(), obj)
+}
+
+// Kind is the resolver for the kind field.
+func (r *gitOpsChangeResolver) Kind(ctx context.Context, obj *model.GitOpsChange) (string, error) {
+	return string(obj.Kind), nil
+}
+
+// Status is the resolver for the status field.
+func (r *gitOpsChangeResolver) Status(ctx context.Context, obj *model.GitOpsChange) (string, error) {
+	return string(obj.Status), nil
+}
+
 // Labels is the resolver for the labels field.
 func (r *metadataResolver) Labels(ctx context.Context, obj *model.Metadata) (map[string]interface{}, error) {
 	labels := map[string]interface{}{}
@@ -119,6 +149,40 @@ func (r *metadataResolver) Labels(ctx context.Context, obj *model.Metadata) (map
 	return labels, nil
 }
 
+// ApplyResources is the resolver for the applyResources field.
+func (r *mutationResolver) ApplyResources(ctx context.Context, resources []*model1.ResourceInput) ([]*model1.ResourceResult, error) {
+	return r.applyResources(resources)
+}
+
+// DeleteResources is the resolver for the deleteResources field.
+func (r *mutationResolver) DeleteResources(ctx context.Context, resources []*model1.ResourceKeyInput) ([]*model1.ResourceResult, error) {
+	return r.deleteResources(resources)
+}
+
+// LabelAgents is the resolver for the labelAgents field.
+func (r *mutationResolver) LabelAgents(ctx context.Context, ids []string, labels map[string]interface{}, overwrite *bool) ([]*model1.AgentResult, error) {
+	return r.labelAgents(ctx, ids, labels, overwrite != nil && *overwrite)
+}
+
+// UpgradeAgents is the resolver for the upgradeAgents field.
+func (r *mutationResolver) UpgradeAgents(ctx context.Context, ids []string, version *string) ([]*model1.AgentResult, error) {
+	var v string
+	if version != nil {
+		v = *version
+	}
+	return r.upgradeAgents(ctx, ids, v)
+}
+
+// RestartAgents is the resolver for the restartAgents field.
+func (r *mutationResolver) RestartAgents(ctx context.Context, ids []string) ([]*model1.AgentResult, error) {
+	return r.restartAgents(ctx, ids)
+}
+
+// CopyConfiguration is the resolver for the copyConfiguration field.
+func (r *mutationResolver) CopyConfiguration(ctx context.Context, name string, newName string) (*model1.ResourceResult, error) {
+	return r.copyConfiguration(name, newName)
+}
+
 // Type is the resolver for the type field.
 func (r *parameterDefinitionResolver) Type(ctx context.Context, obj *model.ParameterDefinition) (model1.ParameterType, error) {
 	switch obj.Type {
@@ -149,27 +213,65 @@ func (r *parameterDefinitionResolver) Type(ctx context.Context, obj *model.Param
 	case "timezone":
 		return model1.ParameterTypeTimezone, nil
 
+	case "float":
+		return model1.ParameterTypeFloat, nil
+
+	case "duration":
+		return model1.ParameterTypeDuration, nil
+
+	case "hostport":
+		return model1.ParameterTypeHostport, nil
+
+	case "url":
+		return model1.ParameterTypeURL, nil
+
+	case "regex":
+		return model1.ParameterTypeRegex, nil
+
+	case "objects":
+		return model1.ParameterTypeObjects, nil
+
 	default:
 		return "", errors.New("unknown parameter type")
 	}
 }
 
+// Type is the resolver for the type field.
+func (r *parameterRuleResolver) Type(ctx context.Context, obj *model.ParameterRule) (model1.ParameterRuleType, error) {
+	return model1.ParameterRuleType(obj.Type), nil
+}
+
 // Kind is the resolver for the kind field.
 func (r *processorResolver) Kind(ctx context.Context, obj *model.Processor) (string, error) {
 	return string(obj.GetKind()), nil
 }
 
+// Usage is the resolver for the usage field.
+func (r *processorResolver) Usage(ctx context.Context, obj *model.Processor) (*model.ResourceUsage, error) {
+	return store.FindResourceUsage(r.bindplane.Store(), obj)
+}
+
 // Kind is the resolver for the kind field.
 func (r *processorTypeResolver) Kind(ctx context.Context, obj *model.ProcessorType) (string, error) {
 	return string(obj.GetKind()), nil
 }
 
+// Usage is the resolver for the usage field.
+func (r *processorTypeResolver) Usage(ctx context.Context, obj *model.ProcessorType) (*model.ResourceUsage, error) {
+	return store.FindResourceUsage(r.bindplane.Store(), obj)
+}
+
 // Agents is the resolver for the agents field.
-func (r *queryResolver) Agents(ctx context.Context, selector *string, query *string) (*model1.Agents, error) {
+func (r *queryResolver) Agents(ctx context.Context, selector *string, query *string, sort *string, limit *int, continueArg *string) (*model1.Agents, error) {
 	ctx, span := tracer.Start(ctx, "graphql/Agents")
 	defer span.End()
 
 	options, suggestions, err := r.queryOptionsAndSuggestions(selector, query, r.bindplane.Store().AgentIndex())
+	if err != nil {
+		return nil, err
+	}
+	options = append(options, pageOptions(sort, limit, continueArg)...)
+
 	agents, err := r.bindplane.Store().Agents(ctx, options...)
 	if err != nil {
 		r.bindplane.Logger().Error("error in graphql Agents", zap.Error(err))
@@ -181,6 +283,7 @@ func (r *queryResolver) Agents(ctx context.Context, selector *string, query *str
 		Query:         query,
 		Suggestions:   suggestions,
 		LatestVersion: r.bindplane.Versions().LatestVersionString(),
+		Continue:      nextContinue(agents, sort, limit),
 	}, nil
 }
 
@@ -190,8 +293,13 @@ func (r *queryResolver) Agent(ctx context.Context, id string) (*model.Agent, err
 }
 
 // Configurations is the resolver for the configurations field.
-func (r *queryResolver) Configurations(ctx context.Context, selector *string, query *string) (*model1.Configurations, error) {
+func (r *queryResolver) Configurations(ctx context.Context, selector *string, query *string, sort *string, limit *int, continueArg *string) (*model1.Configurations, error) {
 	options, suggestions, err := r.queryOptionsAndSuggestions(selector, query, r.bindplane.Store().ConfigurationIndex())
+	if err != nil {
+		return nil, err
+	}
+	options = append(options, pageOptions(sort, limit, continueArg)...)
+
 	configurations, err := r.bindplane.Store().Configurations(options...)
 	if err != nil {
 		return nil, err
@@ -200,6 +308,7 @@ func (r *queryResolver) Configurations(ctx context.Context, selector *string, qu
 		Configurations: configurations,
 		Query:          query,
 		Suggestions:    suggestions,
+		Continue:       nextContinue(configurations, sort, limit),
 	}, nil
 }
 
@@ -209,8 +318,28 @@ func (r *queryResolver) Configuration(ctx context.Context, name string) (*model.
 }
 
 // Sources is the resolver for the sources field.
-func (r *queryResolver) Sources(ctx context.Context) ([]*model.Source, error) {
-	return r.bindplane.Store().Sources()
+func (r *queryResolver) Sources(ctx context.Context, selector *string, query *string, sort *string) ([]*model.Source, error) {
+	options, err := r.queryOptions(selector, query)
+	if err != nil {
+		return nil, err
+	}
+	return r.bindplane.Store().Sources(append(options, pageOptions(sort, nil, nil)...)...)
+}
+
+// SourcesPage is the resolver for the sourcesPage field.
+func (r *queryResolver) SourcesPage(ctx context.Context, selector *string, query *string, sort *string, limit *int, continueArg *string) (*model1.SourcesPage, error) {
+	options, err := r.queryOptions(selector, query)
+	if err != nil {
+		return nil, err
+	}
+	sources, err := r.bindplane.Store().Sources(append(options, pageOptions(sort, limit, continueArg)...)...)
+	if err != nil {
+		return nil, err
+	}
+	return &model1.SourcesPage{
+		Sources:  sources,
+		Continue: nextContinue(sources, sort, limit),
+	}, nil
 }
 
 // Source is the resolver for the source field.
@@ -219,8 +348,28 @@ func (r *queryResolver) Source(ctx context.Context, name string) (*model.Source,
 }
 
 // SourceTypes is the resolver for the sourceTypes field.
-func (r *queryResolver) SourceTypes(ctx context.Context) ([]*model.SourceType, error) {
-	return r.bindplane.Store().SourceTypes()
+func (r *queryResolver) SourceTypes(ctx context.Context, selector *string, query *string, sort *string) ([]*model.SourceType, error) {
+	options, err := r.queryOptions(selector, query)
+	if err != nil {
+		return nil, err
+	}
+	return r.bindplane.Store().SourceTypes(append(options, pageOptions(sort, nil, nil)...)...)
+}
+
+// SourceTypesPage is the resolver for the sourceTypesPage field.
+func (r *queryResolver) SourceTypesPage(ctx context.Context, selector *string, query *string, sort *string, limit *int, continueArg *string) (*model1.SourceTypesPage, error) {
+	options, err := r.queryOptions(selector, query)
+	if err != nil {
+		return nil, err
+	}
+	sourceTypes, err := r.bindplane.Store().SourceTypes(append(options, pageOptions(sort, limit, continueArg)...)...)
+	if err != nil {
+		return nil, err
+	}
+	return &model1.SourceTypesPage{
+		SourceTypes: sourceTypes,
+		Continue:    nextContinue(sourceTypes, sort, limit),
+	}, nil
 }
 
 // SourceType is the resolver for the sourceType field.
@@ -229,8 +378,28 @@ func (r *queryResolver) SourceType(ctx context.Context, name string) (*model.Sou
 }
 
 // Processors is the resolver for the processors field.
-func (r *queryResolver) Processors(ctx context.Context) ([]*model.Processor, error) {
-	return r.bindplane.Store().Processors()
+func (r *queryResolver) Processors(ctx context.Context, selector *string, query *string, sort *string) ([]*model.Processor, error) {
+	options, err := r.queryOptions(selector, query)
+	if err != nil {
+		return nil, err
+	}
+	return r.bindplane.Store().Processors(append(options, pageOptions(sort, nil, nil)...)...)
+}
+
+// ProcessorsPage is the resolver for the processorsPage field.
+func (r *queryResolver) ProcessorsPage(ctx context.Context, selector *string, query *string, sort *string, limit *int, continueArg *string) (*model1.ProcessorsPage, error) {
+	options, err := r.queryOptions(selector, query)
+	if err != nil {
+		return nil, err
+	}
+	processors, err := r.bindplane.Store().Processors(append(options, pageOptions(sort, limit, continueArg)...)...)
+	if err != nil {
+		return nil, err
+	}
+	return &model1.ProcessorsPage{
+		Processors: processors,
+		Continue:   nextContinue(processors, sort, limit),
+	}, nil
 }
 
 // Processor is the resolver for the processor field.
@@ -239,8 +408,28 @@ func (r *queryResolver) Processor(ctx context.Context, name string) (*model.Proc
 }
 
 // ProcessorTypes is the resolver for the processorTypes field.
-func (r *queryResolver) ProcessorTypes(ctx context.Context) ([]*model.ProcessorType, error) {
-	return r.bindplane.Store().ProcessorTypes()
+func (r *queryResolver) ProcessorTypes(ctx context.Context, selector *string, query *string, sort *string) ([]*model.ProcessorType, error) {
+	options, err := r.queryOptions(selector, query)
+	if err != nil {
+		return nil, err
+	}
+	return r.bindplane.Store().ProcessorTypes(append(options, pageOptions(sort, nil, nil)...)...)
+}
+
+// ProcessorTypesPage is the resolver for the processorTypesPage field.
+func (r *queryResolver) ProcessorTypesPage(ctx context.Context, selector *string, query *string, sort *string, limit *int, continueArg *string) (*model1.ProcessorTypesPage, error) {
+	options, err := r.queryOptions(selector, query)
+	if err != nil {
+		return nil, err
+	}
+	processorTypes, err := r.bindplane.Store().ProcessorTypes(append(options, pageOptions(sort, limit, continueArg)...)...)
+	if err != nil {
+		return nil, err
+	}
+	return &model1.ProcessorTypesPage{
+		ProcessorTypes: processorTypes,
+		Continue:       nextContinue(processorTypes, sort, limit),
+	}, nil
 }
 
 // ProcessorType is the resolver for the processorType field.
@@ -249,8 +438,28 @@ func (r *queryResolver) ProcessorType(ctx context.Context, name string) (*model.
 }
 
 // Destinations is the resolver for the destinations field.
-func (r *queryResolver) Destinations(ctx context.Context) ([]*model.Destination, error) {
-	return r.bindplane.Store().Destinations()
+func (r *queryResolver) Destinations(ctx context.Context, selector *string, query *string, sort *string) ([]*model.Destination, error) {
+	options, err := r.queryOptions(selector, query)
+	if err != nil {
+		return nil, err
+	}
+	return r.bindplane.Store().Destinations(append(options, pageOptions(sort, nil, nil)...)...)
+}
+
+// DestinationsPage is the resolver for the destinationsPage field.
+func (r *queryResolver) DestinationsPage(ctx context.Context, selector *string, query *string, sort *string, limit *int, continueArg *string) (*model1.DestinationsPage, error) {
+	options, err := r.queryOptions(selector, query)
+	if err != nil {
+		return nil, err
+	}
+	destinations, err := r.bindplane.Store().Destinations(append(options, pageOptions(sort, limit, continueArg)...)...)
+	if err != nil {
+		return nil, err
+	}
+	return &model1.DestinationsPage{
+		Destinations: destinations,
+		Continue:     nextContinue(destinations, sort, limit),
+	}, nil
 }
 
 // Destination is the resolver for the destination field.
@@ -283,8 +492,28 @@ func (r *queryResolver) DestinationWithType(ctx context.Context, name string) (*
 }
 
 // DestinationTypes is the resolver for the destinationTypes field.
-func (r *queryResolver) DestinationTypes(ctx context.Context) ([]*model.DestinationType, error) {
-	return r.bindplane.Store().DestinationTypes()
+func (r *queryResolver) DestinationTypes(ctx context.Context, selector *string, query *string, sort *string) ([]*model.DestinationType, error) {
+	options, err := r.queryOptions(selector, query)
+	if err != nil {
+		return nil, err
+	}
+	return r.bindplane.Store().DestinationTypes(append(options, pageOptions(sort, nil, nil)...)...)
+}
+
+// DestinationTypesPage is the resolver for the destinationTypesPage field.
+func (r *queryResolver) DestinationTypesPage(ctx context.Context, selector *string, query *string, sort *string, limit *int, continueArg *string) (*model1.DestinationTypesPage, error) {
+	options, err := r.queryOptions(selector, query)
+	if err != nil {
+		return nil, err
+	}
+	destinationTypes, err := r.bindplane.Store().DestinationTypes(append(options, pageOptions(sort, limit, continueArg)...)...)
+	if err != nil {
+		return nil, err
+	}
+	return &model1.DestinationTypesPage{
+		DestinationTypes: destinationTypes,
+		Continue:         nextContinue(destinationTypes, sort, limit),
+	}, nil
 }
 
 // DestinationType is the resolver for the destinationType field.
@@ -320,21 +549,41 @@ func (r *queryResolver) Components(ctx context.Context) (*model1.Components, err
 	}, nil
 }
 
+// GitOpsSyncStatus is the resolver for the gitOpsSyncStatus field.
+func (r *queryResolver) GitOpsSyncStatus(ctx context.Context) (*model.GitOpsSyncStatus, error) {
+	return r.bindplane.Store().GitOpsSyncStatus(ctx)
+}
+
 // Operator is the resolver for the operator field.
 func (r *relevantIfConditionResolver) Operator(ctx context.Context, obj *model.RelevantIfCondition) (model1.RelevantIfOperatorType, error) {
 	return model1.RelevantIfOperatorType(obj.Operator), nil
 }
 
+// Kind is the resolver for the kind field.
+func (r *resourceUsageResolver) Kind(ctx context.Context, obj *model.ResourceUsage) (string, error) {
+	return string(obj.Kind), nil
+}
+
 // Kind is the resolver for the kind field.
 func (r *sourceResolver) Kind(ctx context.Context, obj *model.Source) (string, error) {
 	return string(obj.GetKind()), nil
 }
 
+// Usage is the resolver for the usage field.
+func (r *sourceResolver) Usage(ctx context.Context, obj *model.Source) (*model.ResourceUsage, error) {
+	return store.FindResourceUsage(r.bindplane.Store(), obj)
+}
+
 // Kind is the resolver for the kind field.
 func (r *sourceTypeResolver) Kind(ctx context.Context, obj *model.SourceType) (string, error) {
 	return string(obj.GetKind()), nil
 }
 
+// Usage is the resolver for the usage field.
+func (r *sourceTypeResolver) Usage(ctx context.Context, obj *model.SourceType) (*model.ResourceUsage, error) {
+	return store.FindResourceUsage(r.bindplane.Store(), obj)
+}
+
 // AgentChanges is the resolver for the agentChanges field.
 func (r *subscriptionResolver) AgentChanges(ctx context.Context, selector *string, query *string) (<-chan []*model1.AgentChange, error) {
 	parsedSelector, parsedQuery, err := r.parseSelectorAndQuery(selector, query)
@@ -358,7 +607,7 @@ func (r *subscriptionResolver) AgentChanges(ctx context.Context, selector *strin
 }
 
 // ConfigurationChanges is the resolver for the configurationChanges field.
-func (r *subscriptionResolver) ConfigurationChanges(ctx context.Context, selector *string, query *string) (<-chan []*model1.ConfigurationChange, error) {
+func (r *subscriptionResolver) ConfigurationChanges(ctx context.Context, name *string, selector *string, query *string) (<-chan []*model1.ConfigurationChange, error) {
 	parsedSelector, parsedQuery, err := r.parseSelectorAndQuery(selector, query)
 	if err != nil {
 		return nil, err
@@ -385,7 +634,8 @@ func (r *subscriptionResolver) ConfigurationChanges(ctx context.Context, selecto
 			}
 		}
 
-		events := applySelectorToEvents(parsedSelector, configUpdates)
+		events := applyNameToEvents(name, configUpdates)
+		events = applySelectorToEvents(parsedSelector, events)
 		events = applyQueryToEvents(parsedQuery, r.bindplane.Store().ConfigurationIndex(), events)
 
 		return model1.ToConfigurationChanges(events), len(events) > 0
@@ -394,6 +644,97 @@ func (r *subscriptionResolver) ConfigurationChanges(ctx context.Context, selecto
 	return channel, nil
 }
 
+// SourceChanges is the resolver for the sourceChanges field.
+func (r *subscriptionResolver) SourceChanges(ctx context.Context, name *string, selector *string, query *string) (<-chan []*model1.SourceChange, error) {
+	return subscribeResourceChanges(ctx, r.Resolver, name, selector, query,
+		func(updates *store.Updates) store.Events[*model.Source] { return updates.Sources },
+		func(item *model.Source, eventType model1.EventType) *model1.SourceChange {
+			return &model1.SourceChange{Source: item, EventType: eventType}
+		})
+}
+
+// SourceTypeChanges is the resolver for the sourceTypeChanges field.
+func (r *subscriptionResolver) SourceTypeChanges(ctx context.Context, name *string, selector *string, query *string) (<-chan []*model1.SourceTypeChange, error) {
+	return subscribeResourceChanges(ctx, r.Resolver, name, selector, query,
+		func(updates *store.Updates) store.Events[*model.SourceType] { return updates.SourceTypes },
+		func(item *model.SourceType, eventType model1.EventType) *model1.SourceTypeChange {
+			return &model1.SourceTypeChange{SourceType: item, EventType: eventType}
+		})
+}
+
+// ProcessorChanges is the resolver for the processorChanges field.
+func (r *subscriptionResolver) ProcessorChanges(ctx context.Context, name *string, selector *string, query *string) (<-chan []*model1.ProcessorChange, error) {
+	return subscribeResourceChanges(ctx, r.Resolver, name, selector, query,
+		func(updates *store.Updates) store.Events[*model.Processor] { return updates.Processors },
+		func(item *model.Processor, eventType model1.EventType) *model1.ProcessorChange {
+			return &model1.ProcessorChange{Processor: item, EventType: eventType}
+		})
+}
+
+// ProcessorTypeChanges is the resolver for the processorTypeChanges field.
+func (r *subscriptionResolver) ProcessorTypeChanges(ctx context.Context, name *string, selector *string, query *string) (<-chan []*model1.ProcessorTypeChange, error) {
+	return subscribeResourceChanges(ctx, r.Resolver, name, selector, query,
+		func(updates *store.Updates) store.Events[*model.ProcessorType] { return updates.ProcessorTypes },
+		func(item *model.ProcessorType, eventType model1.EventType) *model1.ProcessorTypeChange {
+			return &model1.ProcessorTypeChange{ProcessorType: item, EventType: eventType}
+		})
+}
+
+// DestinationChanges is the resolver for the destinationChanges field.
+func (r *subscriptionResolver) DestinationChanges(ctx context.Context, name *string, selector *string, query *string) (<-chan []*model1.DestinationChange, error) {
+	return subscribeResourceChanges(ctx, r.Resolver, name, selector, query,
+		func(updates *store.Updates) store.Events[*model.Destination] { return updates.Destinations },
+		func(item *model.Destination, eventType model1.EventType) *model1.DestinationChange {
+			return &model1.DestinationChange{Destination: item, EventType: eventType}
+		})
+}
+
+// DestinationTypeChanges is the resolver for the destinationTypeChanges field.
+func (r *subscriptionResolver) DestinationTypeChanges(ctx context.Context, name *string, selector *string, query *string) (<-chan []*model1.DestinationTypeChange, error) {
+	return subscribeResourceChanges(ctx, r.Resolver, name, selector, query,
+		func(updates *store.Updates) store.Events[*model.DestinationType] { return updates.DestinationTypes },
+		func(item *model.DestinationType, eventType model1.EventType) *model1.DestinationTypeChange {
+			return &model1.DestinationTypeChange{DestinationType: item, EventType: eventType}
+		})
+}
+
+// AgentVersionChanges is the resolver for the agentVersionChanges field.
+func (r *subscriptionResolver) AgentVersionChanges(ctx context.Context, name *string, selector *string, query *string) (<-chan []*model1.AgentVersionChange, error) {
+	return subscribeResourceChanges(ctx, r.Resolver, name, selector, query,
+		func(updates *store.Updates) store.Events[*model.AgentVersion] { return updates.AgentVersions },
+		func(item *model.AgentVersion, eventType model1.EventType) *model1.AgentVersionChange {
+			return &model1.AgentVersionChange{AgentVersion: item, EventType: eventType}
+		})
+}
+
+// ResourceChanges is the resolver for the resourceChanges field.
+func (r *subscriptionResolver) ResourceChanges(ctx context.Context, kinds []string, name *string, selector *string, query *string) (<-chan []*model1.ResourceChange, error) {
+	parsedKinds, err := parseResourceChangeKinds(kinds)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := r.parseResourceFilter(name, selector, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// we can ignore the unsubscribe function because this will automatically unsubscribe when the context is done.
+	channel, _ := eventbus.SubscribeWithFilterUntilDone(ctx, r.updates, func(updates *store.Updates) (result []*model1.ResourceChange, accept bool) {
+		result = []*model1.ResourceChange{}
+		result = appendResourceChanges(result, parsedKinds, filter, r.bindplane.Store().ConfigurationIndex(), updates.Configurations)
+		result = appendResourceChanges(result, parsedKinds, filter, nil, updates.Sources)
+		result = appendResourceChanges(result, parsedKinds, filter, nil, updates.SourceTypes)
+		result = appendResourceChanges(result, parsedKinds, filter, nil, updates.Processors)
+		result = appendResourceChanges(result, parsedKinds, filter, nil, updates.ProcessorTypes)
+		result = appendResourceChanges(result, parsedKinds, filter, nil, updates.Destinations)
+		result = appendResourceChanges(result, parsedKinds, filter, nil, updates.DestinationTypes)
+		result = appendResourceChanges(result, parsedKinds, filter, nil, updates.AgentVersions)
+		return result, len(result) > 0
+	})
+
+	return channel, nil
+}
+
 // Agent returns generated.AgentResolver implementation.
 func (r *Resolver) Agent() generated.AgentResolver { return &agentResolver{r} }
 
@@ -403,6 +744,9 @@ func (r *Resolver) AgentSelector() generated.AgentSelectorResolver { return &age
 // AgentUpgrade returns generated.AgentUpgradeResolver implementation.
 func (r *Resolver) AgentUpgrade() generated.AgentUpgradeResolver { return &agentUpgradeResolver{r} }
 
+// AgentVersion returns generated.AgentVersionResolver implementation.
+func (r *Resolver) AgentVersion() generated.AgentVersionResolver { return &agentVersionResolver{r} }
+
 // Configuration returns generated.ConfigurationResolver implementation.
 func (r *Resolver) Configuration() generated.ConfigurationResolver { return &configurationResolver{r} }
 
@@ -414,14 +758,23 @@ func (r *Resolver) DestinationType() generated.DestinationTypeResolver {
 	return &destinationTypeResolver{r}
 }
 
+// GitOpsChange returns generated.GitOpsChangeResolver implementation.
+func (r *Resolver) GitOpsChange() generated.GitOpsChangeResolver { return &gitOpsChangeResolver{r} }
+
 // Metadata returns generated.MetadataResolver implementation.
 func (r *Resolver) Metadata() generated.MetadataResolver { return &metadataResolver{r} }
 
+// Mutation returns generated.MutationResolver implementation.
+func (r *Resolver) Mutation() generated.MutationResolver { return &mutationResolver{r} }
+
 // ParameterDefinition returns generated.ParameterDefinitionResolver implementation.
 func (r *Resolver) ParameterDefinition() generated.ParameterDefinitionResolver {
 	return &parameterDefinitionResolver{r}
 }
 
+// ParameterRule returns generated.ParameterRuleResolver implementation.
+func (r *Resolver) ParameterRule() generated.ParameterRuleResolver { return &parameterRuleResolver{r} }
+
 // Processor returns generated.ProcessorResolver implementation.
 func (r *Resolver) Processor() generated.ProcessorResolver { return &processorResolver{r} }
 
@@ -436,6 +789,9 @@ func (r *Resolver) RelevantIfCondition() generated.RelevantIfConditionResolver {
 	return &relevantIfConditionResolver{r}
 }
 
+// ResourceUsage returns generated.ResourceUsageResolver implementation.
+func (r *Resolver) ResourceUsage() generated.ResourceUsageResolver { return &resourceUsageResolver{r} }
+
 // Source returns generated.SourceResolver implementation.
 func (r *Resolver) Source() generated.SourceResolver { return &sourceResolver{r} }
 
@@ -448,15 +804,20 @@ func (r *Resolver) Subscription() generated.SubscriptionResolver { return &subsc
 type agentResolver struct{ *Resolver }
 type agentSelectorResolver struct{ *Resolver }
 type agentUpgradeResolver struct{ *Resolver }
+type agentVersionResolver struct{ *Resolver }
 type configurationResolver struct{ *Resolver }
 type destinationResolver struct{ *Resolver }
 type destinationTypeResolver struct{ *Resolver }
+type gitOpsChangeResolver struct{ *Resolver }
 type metadataResolver struct{ *Resolver }
+type mutationResolver struct{ *Resolver }
 type parameterDefinitionResolver struct{ *Resolver }
+type parameterRuleResolver struct{ *Resolver }
 type processorResolver struct{ *Resolver }
 type processorTypeResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
 type relevantIfConditionResolver struct{ *Resolver }
+type resourceUsageResolver struct{ *Resolver }
 type sourceResolver struct{ *Resolver }
 type sourceTypeResolver struct{ *Resolver }
 type subscriptionResolver struct{ *Resolver }
@@ -16,7 +16,9 @@ package graphql
 
 import (
 	"context"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/99designs/gqlgen/client"
 	"github.com/stretchr/testify/require"
@@ -28,6 +30,7 @@ import (
 	"github.com/observiq/bindplane-op/internal/agent/mocks"
 	model1 "github.com/observiq/bindplane-op/internal/graphql/model"
 	"github.com/observiq/bindplane-op/internal/server"
+	"github.com/observiq/bindplane-op/internal/server/auth"
 	"github.com/observiq/bindplane-op/internal/store"
 	"github.com/observiq/bindplane-op/model"
 )
@@ -55,7 +58,7 @@ func TestQueryResolvers(t *testing.T) {
 		MaxEventsToMerge: 1,
 	}, zap.NewNop())
 
-	bindplane, err := server.NewBindPlane(&common.Server{}, zaptest.NewLogger(t), mapstore, mockVersions())
+	bindplane, err := server.NewBindPlane(&common.Server{}, zaptest.NewLogger(t), mapstore, mockVersions(), nil)
 	require.NoError(t, err)
 
 	srv := newHandler(bindplane)
@@ -105,6 +108,437 @@ func TestQueryResolvers(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, resp["agent"].ID, agent.ID)
 	})
+
+	t.Run("agents supports sort, limit, and continue", func(t *testing.T) {
+		s.Clear()
+
+		for _, id := range []string{"1", "2", "3"} {
+			_, err := addAgent(s, &model.Agent{ID: id, Name: "Fake Agent " + id})
+			require.NoError(t, err)
+		}
+
+		var resp map[string]model1.Agents
+		err := c.Post(`query TestQuery { agents(sort: "-name", limit: 2) { agents { id } continue } }`, &resp)
+		require.NoError(t, err)
+		require.Len(t, resp["agents"].Agents, 2)
+		require.Equal(t, "3", resp["agents"].Agents[0].ID)
+		require.NotNil(t, resp["agents"].Continue)
+
+		err = c.Post(`query TestQuery($continue: String) { agents(sort: "-name", limit: 2, continue: $continue) { agents { id } continue } }`,
+			&resp, client.Var("continue", *resp["agents"].Continue))
+		require.NoError(t, err)
+		require.Len(t, resp["agents"].Agents, 1)
+		require.Equal(t, "1", resp["agents"].Agents[0].ID)
+		require.Nil(t, resp["agents"].Continue)
+
+		err = c.Post(`query TestQuery { agents(sort: "unknown") { agents { id } } }`, &resp)
+		require.ErrorContains(t, err, "invalid sort: unsupported field unknown")
+	})
+
+	t.Run("sourceTypesPage returns pages of source types", func(t *testing.T) {
+		s.Clear()
+
+		_, err := s.ApplyResources([]model.Resource{
+			model.NewSourceType("macos", nil),
+			model.NewSourceType("nginx", nil),
+			model.NewSourceType("postgresql", nil),
+		})
+		require.NoError(t, err)
+
+		type page struct {
+			SourceTypes []struct {
+				Metadata struct {
+					Name string
+				}
+			}
+			Continue *string
+		}
+
+		var resp map[string]page
+		err = c.Post(`query TestQuery { sourceTypesPage(limit: 2) { sourceTypes { metadata { name } } continue } }`, &resp)
+		require.NoError(t, err)
+		require.Len(t, resp["sourceTypesPage"].SourceTypes, 2)
+		require.NotNil(t, resp["sourceTypesPage"].Continue)
+
+		err = c.Post(`query TestQuery($continue: String) { sourceTypesPage(limit: 2, continue: $continue) { sourceTypes { metadata { name } } continue } }`,
+			&resp, client.Var("continue", *resp["sourceTypesPage"].Continue))
+		require.NoError(t, err)
+		require.Len(t, resp["sourceTypesPage"].SourceTypes, 1)
+		require.Equal(t, "postgresql", resp["sourceTypesPage"].SourceTypes[0].Metadata.Name)
+		require.Nil(t, resp["sourceTypesPage"].Continue)
+
+		var list map[string][]struct {
+			Metadata struct {
+				Name string
+			}
+		}
+		err = c.Post(`query TestQuery { sourceTypes(query: "ngi") { metadata { name } } }`, &list)
+		require.NoError(t, err)
+		require.Len(t, list["sourceTypes"], 1)
+		require.Equal(t, "nginx", list["sourceTypes"][0].Metadata.Name)
+	})
+}
+
+func TestMutationResolvers(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	mapstore := store.NewMapStore(ctx, store.Options{
+		SessionsSecret:   "super-secret-key",
+		MaxEventsToMerge: 1,
+	}, zap.NewNop())
+
+	bindplane, err := server.NewBindPlane(&common.Server{}, zaptest.NewLogger(t), mapstore, mockVersions(), nil)
+	require.NoError(t, err)
+
+	srv := newHandler(bindplane)
+	c := client.New(srv)
+
+	s := bindplane.Store()
+
+	type fieldError struct {
+		Field   *string
+		Message string
+	}
+	type resourceResult struct {
+		Kind   string
+		Name   string
+		Status string
+		Reason *string
+		Errors []fieldError
+	}
+	type agentResult struct {
+		ID    string
+		Agent *struct {
+			Labels map[string]any
+		}
+		Errors []fieldError
+	}
+	fields := func(errs []fieldError) []string {
+		result := []string{}
+		for _, err := range errs {
+			if err.Field == nil {
+				result = append(result, "")
+				continue
+			}
+			result = append(result, *err.Field)
+		}
+		return result
+	}
+
+	t.Run("applyResources returns an error for each invalid field", func(t *testing.T) {
+		s.Clear()
+
+		var resp map[string][]resourceResult
+		err := c.Post(`mutation TestMutation($resources: [ResourceInput!]!) {
+			applyResources(resources: $resources) { kind name status reason errors { field message } }
+		}`, &resp, client.Var("resources", []map[string]any{
+			{
+				"kind":     "SourceType",
+				"metadata": map[string]any{"name": "tcp"},
+				"spec": map[string]any{
+					"parameters": []map[string]any{{"name": "port", "label": "Port", "type": "int", "default": 514}},
+				},
+			},
+			{
+				"kind":     "Source",
+				"metadata": map[string]any{"name": "bad source", "labels": map[string]any{"env": "prod"}},
+				"spec": map[string]any{
+					"type":       "tcp",
+					"parameters": []map[string]any{{"name": "port", "value": "not a port"}},
+				},
+			},
+			{
+				"kind":     "Widget",
+				"metadata": map[string]any{"name": "widget"},
+			},
+		}))
+		require.NoError(t, err)
+
+		results := resp["applyResources"]
+		require.Len(t, results, 3)
+
+		require.Equal(t, "SourceType", results[0].Kind)
+		require.Equal(t, "created", results[0].Status)
+		require.Empty(t, results[0].Errors)
+
+		require.Equal(t, "Source", results[1].Kind)
+		require.Equal(t, "invalid", results[1].Status)
+		require.NotNil(t, results[1].Reason)
+		require.Equal(t, []string{"metadata.name", "spec.parameters.port"}, fields(results[1].Errors))
+
+		require.Equal(t, "Widget", results[2].Kind)
+		require.Equal(t, "invalid", results[2].Status)
+		require.Equal(t, []string{"kind"}, fields(results[2].Errors))
+	})
+
+	t.Run("deleteResources deletes resources", func(t *testing.T) {
+		s.Clear()
+
+		_, err := s.ApplyResources([]model.Resource{model.NewSourceType("macos", nil)})
+		require.NoError(t, err)
+
+		var resp map[string][]resourceResult
+		err = c.Post(`mutation TestMutation($resources: [ResourceKeyInput!]!) {
+			deleteResources(resources: $resources) { kind name status reason errors { field message } }
+		}`, &resp, client.Var("resources", []map[string]any{
+			{"kind": "SourceType", "name": "macos"},
+			{"kind": "SourceType", "name": "missing"},
+		}))
+		require.NoError(t, err)
+
+		results := resp["deleteResources"]
+		require.Len(t, results, 2)
+		require.Equal(t, "deleted", results[0].Status)
+		require.Equal(t, "unchanged", results[1].Status)
+		require.Equal(t, "SourceType missing not found", *results[1].Reason)
+
+		sourceType, err := s.SourceType("macos")
+		require.NoError(t, err)
+		require.Nil(t, sourceType)
+	})
+
+	t.Run("labelAgents merges labels", func(t *testing.T) {
+		s.Clear()
+
+		labels, err := model.LabelsFromSelector("env=dev")
+		require.NoError(t, err)
+		_, err = addAgent(s, &model.Agent{ID: "1", Name: "Fake Agent 1", Labels: labels})
+		require.NoError(t, err)
+
+		query := `mutation TestMutation($ids: [ID!]!, $labels: Map!, $overwrite: Boolean) {
+			labelAgents(ids: $ids, labels: $labels, overwrite: $overwrite) { id agent { labels } errors { field message } }
+		}`
+
+		var resp map[string][]agentResult
+		err = c.Post(query, &resp,
+			client.Var("ids", []string{"1", "2"}),
+			client.Var("labels", map[string]any{"env": "prod"}))
+		require.NoError(t, err)
+		results := resp["labelAgents"]
+		require.Len(t, results, 2)
+		require.Equal(t, []string{"labels"}, fields(results[0].Errors))
+		require.Equal(t, "dev", results[0].Agent.Labels["env"])
+		require.Equal(t, []string{"ids"}, fields(results[1].Errors))
+		require.Nil(t, results[1].Agent)
+
+		err = c.Post(query, &resp,
+			client.Var("ids", []string{"1"}),
+			client.Var("labels", map[string]any{"env": "prod", "app": "web"}),
+			client.Var("overwrite", true))
+		require.NoError(t, err)
+		results = resp["labelAgents"]
+		require.Len(t, results, 1)
+		require.Empty(t, results[0].Errors)
+		require.Equal(t, map[string]any{"env": "prod", "app": "web"}, results[0].Agent.Labels)
+
+		err = c.Post(query, &resp,
+			client.Var("ids", []string{"1"}),
+			client.Var("labels", map[string]any{"bad label": "x"}))
+		require.NoError(t, err)
+		require.Equal(t, []string{"labels"}, fields(resp["labelAgents"][0].Errors))
+	})
+
+	t.Run("upgradeAgents upgrades agents that support upgrade", func(t *testing.T) {
+		s.Clear()
+
+		_, err := addAgent(s, &model.Agent{ID: "1", Name: "Fake Agent 1", Version: "v1.6.0"})
+		require.NoError(t, err)
+		_, err = addAgent(s, &model.Agent{ID: "2", Name: "Fake Agent 2", Version: "v1.5.0"})
+		require.NoError(t, err)
+
+		var resp map[string][]agentResult
+		err = c.Post(`mutation TestMutation($ids: [ID!]!) { upgradeAgents(ids: $ids) { id errors { field message } } }`,
+			&resp, client.Var("ids", []string{"1", "2"}))
+		require.NoError(t, err)
+		results := resp["upgradeAgents"]
+		require.Len(t, results, 2)
+		require.Empty(t, results[0].Errors)
+		require.Equal(t, []fieldError{{Message: "agent 2 with version v1.5.0 does not support upgrade"}}, results[1].Errors)
+
+		agent, err := s.Agent("1")
+		require.NoError(t, err)
+		require.NotNil(t, agent.Upgrade)
+		require.Equal(t, mockLatestVersion, agent.Upgrade.Version)
+	})
+
+	t.Run("restartAgents reports agents that are not connected", func(t *testing.T) {
+		s.Clear()
+
+		_, err := addAgent(s, &model.Agent{ID: "1", Name: "Fake Agent 1"})
+		require.NoError(t, err)
+
+		var resp map[string][]agentResult
+		err = c.Post(`mutation TestMutation($ids: [ID!]!) { restartAgents(ids: $ids) { id errors { field message } } }`,
+			&resp, client.Var("ids", []string{"1"}))
+		require.NoError(t, err)
+		require.Equal(t, []fieldError{{Message: "agent 1 is not connected"}}, resp["restartAgents"][0].Errors)
+	})
+
+	t.Run("copyConfiguration copies a configuration", func(t *testing.T) {
+		s.Clear()
+
+		_, err := s.ApplyResources([]model.Resource{
+			model.NewConfiguration("config"),
+			model.NewConfiguration("existing"),
+		})
+		require.NoError(t, err)
+
+		query := `mutation TestMutation($name: String!, $newName: String!) {
+			copyConfiguration(name: $name, newName: $newName) { kind name status errors { field message } }
+		}`
+
+		var resp map[string]resourceResult
+		err = c.Post(query, &resp, client.Var("name", "config"), client.Var("newName", "copy"))
+		require.NoError(t, err)
+		require.Equal(t, "created", resp["copyConfiguration"].Status)
+		require.Equal(t, "copy", resp["copyConfiguration"].Name)
+
+		err = c.Post(query, &resp, client.Var("name", "config"), client.Var("newName", "existing"))
+		require.NoError(t, err)
+		require.Equal(t, "invalid", resp["copyConfiguration"].Status)
+		require.Equal(t, []string{"newName"}, fields(resp["copyConfiguration"].Errors))
+
+		err = c.Post(query, &resp, client.Var("name", "missing"), client.Var("newName", "other"))
+		require.NoError(t, err)
+		require.Equal(t, []string{"name"}, fields(resp["copyConfiguration"].Errors))
+	})
+
+	t.Run("mutations require a role that can write", func(t *testing.T) {
+		s.Clear()
+
+		var resp map[string][]agentResult
+		err := c.Post(`mutation TestMutation { restartAgents(ids: ["1"]) { id } }`, &resp, func(bd *client.Request) {
+			bd.HTTP = bd.HTTP.WithContext(auth.WithRole(bd.HTTP.Context(), model.RoleViewer))
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "forbidden")
+	})
+}
+
+func TestSubscriptionResolvers(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	mapstore := store.NewMapStore(ctx, store.Options{
+		SessionsSecret:   "super-secret-key",
+		MaxEventsToMerge: 1,
+	}, zap.NewNop())
+
+	bindplane, err := server.NewBindPlane(&common.Server{}, zaptest.NewLogger(t), mapstore, mockVersions(), nil)
+	require.NoError(t, err)
+
+	resolver := NewResolver(bindplane)
+	s := bindplane.Store()
+
+	_, err = s.ApplyResources([]model.Resource{
+		model.NewSourceType("macos", nil),
+		model.NewDestinationType("otlp", nil),
+	})
+	require.NoError(t, err)
+
+	labeled := func(source *model.Source, selector string) *model.Source {
+		labels, err := model.LabelsFromSelector(selector)
+		require.NoError(t, err)
+		source.Metadata.Labels = labels
+		return source
+	}
+
+	// receive returns the next result sent to the channel
+	receive := func(t *testing.T, channel any) any {
+		value := reflect.ValueOf(channel)
+		chosen, result, ok := reflect.Select([]reflect.SelectCase{
+			{Dir: reflect.SelectRecv, Chan: value},
+			{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(time.After(5 * time.Second))},
+		})
+		require.Equal(t, 0, chosen, "timed out waiting for changes")
+		require.True(t, ok)
+		return result.Interface()
+	}
+
+	ptr := func(s string) *string { return &s }
+
+	t.Run("sourceChanges filters by name", func(t *testing.T) {
+		subCtx, subCancel := context.WithCancel(ctx)
+		defer subCancel()
+
+		channel, err := resolver.Subscription().SourceChanges(subCtx, ptr("syslog"), nil, nil)
+		require.NoError(t, err)
+
+		_, err = s.ApplyResources([]model.Resource{model.NewSource("other", "macos", nil)})
+		require.NoError(t, err)
+		_, err = s.ApplyResources([]model.Resource{model.NewSource("syslog", "macos", nil)})
+		require.NoError(t, err)
+
+		changes := receive(t, channel).([]*model1.SourceChange)
+		require.Len(t, changes, 1)
+		require.Equal(t, "syslog", changes[0].Source.Name())
+		require.Equal(t, model1.EventTypeInsert, changes[0].EventType)
+	})
+
+	t.Run("sourceChanges sends remove for sources that no longer match the selector", func(t *testing.T) {
+		subCtx, subCancel := context.WithCancel(ctx)
+		defer subCancel()
+
+		channel, err := resolver.Subscription().SourceChanges(subCtx, ptr("labeled"), ptr("env=prod"), nil)
+		require.NoError(t, err)
+
+		_, err = s.ApplyResources([]model.Resource{labeled(model.NewSource("labeled", "macos", nil), "env=prod")})
+		require.NoError(t, err)
+		changes := receive(t, channel).([]*model1.SourceChange)
+		require.Len(t, changes, 1)
+		require.Equal(t, model1.EventTypeInsert, changes[0].EventType)
+
+		_, err = s.ApplyResources([]model.Resource{labeled(model.NewSource("labeled", "macos", nil), "env=dev")})
+		require.NoError(t, err)
+		changes = receive(t, channel).([]*model1.SourceChange)
+		require.Len(t, changes, 1)
+		require.Equal(t, model1.EventTypeRemove, changes[0].EventType)
+	})
+
+	t.Run("destinationChanges filters by query", func(t *testing.T) {
+		subCtx, subCancel := context.WithCancel(ctx)
+		defer subCancel()
+
+		channel, err := resolver.Subscription().DestinationChanges(subCtx, nil, nil, ptr("prod"))
+		require.NoError(t, err)
+
+		_, err = s.ApplyResources([]model.Resource{model.NewDestination("prod-otlp", "otlp", nil)})
+		require.NoError(t, err)
+		changes := receive(t, channel).([]*model1.DestinationChange)
+		require.Len(t, changes, 1)
+		require.Equal(t, "prod-otlp", changes[0].Destination.Name())
+		require.Equal(t, model1.EventTypeInsert, changes[0].EventType)
+
+		_, err = s.ApplyResources([]model.Resource{model.NewDestination("dev-otlp", "otlp", nil)})
+		require.NoError(t, err)
+		changes = receive(t, channel).([]*model1.DestinationChange)
+		require.Len(t, changes, 1)
+		require.Equal(t, "dev-otlp", changes[0].Destination.Name())
+		require.Equal(t, model1.EventTypeRemove, changes[0].EventType)
+	})
+
+	t.Run("resourceChanges filters by kind", func(t *testing.T) {
+		subCtx, subCancel := context.WithCancel(ctx)
+		defer subCancel()
+
+		channel, err := resolver.Subscription().ResourceChanges(subCtx, []string{"destinations"}, nil, nil, nil)
+		require.NoError(t, err)
+
+		_, err = s.ApplyResources([]model.Resource{model.NewSource("kinds", "macos", nil)})
+		require.NoError(t, err)
+		_, err = s.ApplyResources([]model.Resource{model.NewDestination("kinds", "otlp", nil)})
+		require.NoError(t, err)
+
+		changes := receive(t, channel).([]*model1.ResourceChange)
+		require.Len(t, changes, 1)
+		require.Equal(t, "Destination", changes[0].Kind)
+		require.Equal(t, "kinds", changes[0].Name)
+		require.IsType(t, &model.Destination{}, changes[0].Resource)
+	})
+
+	t.Run("resourceChanges rejects unknown kinds", func(t *testing.T) {
+		_, err := resolver.Subscription().ResourceChanges(ctx, []string{"Agent"}, nil, nil, nil)
+		require.EqualError(t, err, "Agent is not a kind of resource with changes")
+	})
 }
 
 func TestConfigForAgent(t *testing.T) {
@@ -115,7 +549,7 @@ func TestConfigForAgent(t *testing.T) {
 		MaxEventsToMerge: 1,
 	}, zap.NewNop())
 
-	bindplane, err := server.NewBindPlane(&common.Server{}, zaptest.NewLogger(t), mapstore, mockVersions())
+	bindplane, err := server.NewBindPlane(&common.Server{}, zaptest.NewLogger(t), mapstore, mockVersions(), nil)
 	require.NoError(t, err)
 
 	srv := newHandler(bindplane)
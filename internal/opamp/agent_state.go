@@ -36,6 +36,16 @@ var (
 )
 
 func (s *opampServer) updateAgentState(ctx context.Context, agentID string, conn opamp.Connection, msg *protobufs.AgentToServer, response *protobufs.ServerToAgent) (agent *model.Agent, state *agentState, err error) {
+	// the credential is read before the agent is updated because some stores don't allow nested transactions
+	credential, err := s.manager.AgentCredential(ctx, agentID)
+	if err != nil {
+		s.logger.Error("unable to get the agent credential, enrollment labels will not be applied", zap.String("agentID", agentID), zap.Error(err))
+	}
+	pending, err := s.manager.PendingApproval(ctx, agentID)
+	if err != nil {
+		s.logger.Error("unable to determine if the agent is pending approval", zap.String("agentID", agentID), zap.Error(err))
+	}
+
 	agent, err = s.manager.UpsertAgent(ctx, agentID, func(agent *model.Agent) {
 		// we're using opamp
 		agent.Protocol = ProtocolName
@@ -54,6 +64,16 @@ func (s *opampServer) updateAgentState(ctx context.Context, agentID string, conn
 		// after sync, update sequence number
 		state.SequenceNum = msg.GetSequenceNum()
 
+		// remember the capabilities to know what can be sent to the agent outside of a response, e.g. connection settings
+		if capabilities := msg.GetCapabilities(); capabilities != 0 {
+			state.Status.Capabilities = capabilities
+		}
+
+		// agents enrolled with an enrollment token always have the labels of the token
+		if credential != nil && len(credential.Labels.Set) > 0 {
+			agent.Labels = model.LabelsFromMerge(agent.Labels, credential.Labels)
+		}
+
 		// always update the agent status, regardless of RemoteConfigStatus message being present
 		updateAgentStatus(s.logger, agent, state.Status.GetRemoteConfigStatus())
 
@@ -62,6 +82,16 @@ func (s *opampServer) updateAgentState(ctx context.Context, agentID string, conn
 			agent.Disconnect()
 		} else {
 			agent.Connect(agent.Version)
+
+			// pending agents keep the Pending status until they are approved
+			if pending {
+				agent.Status = model.Pending
+			}
+
+			// agents with a conflict keep the Conflict status until the conflict is resolved
+			if agent.Conflict != nil {
+				agent.Status = model.Conflict
+			}
 		}
 
 		// the state could be new
@@ -17,12 +17,16 @@ package opamp
 import (
 	"bytes"
 	"context"
+	"crypto/x509"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/open-telemetry/opamp-go/protobufs"
 	opampSvr "github.com/open-telemetry/opamp-go/server"
 	opamp "github.com/open-telemetry/opamp-go/server/types"
@@ -61,6 +65,11 @@ func AddRoutes(router gin.IRouter, bindplane server.BindPlane) error {
 	server := opampSvr.New(bindplane.Logger().Sugar())
 
 	callbacks := newServer(bindplane.Manager(), bindplane.Logger())
+	if websocketURL := bindplane.Config().WebsocketURL(); websocketURL != "" {
+		// agents are given credentials to use with the same endpoint used by the install command
+		callbacks.endpoint = fmt.Sprintf("%s/v1/opamp", websocketURL)
+	}
+	callbacks.agentCertificates = bindplane.Manager().AgentCA() != nil
 	settings := opampSvr.Settings{
 		Callbacks: callbacks,
 	}
@@ -70,10 +79,14 @@ func AddRoutes(router gin.IRouter, bindplane server.BindPlane) error {
 		return fmt.Errorf("error attempting to attach the OpAMP server: %w", err)
 	}
 
-	router.Any("/opamp", gin.WrapF(http.HandlerFunc(handler)))
+	// agents using the HTTP transport poll the same path used by websocket agents
+	router.Any("/opamp", gin.WrapF(callbacks.handler(http.HandlerFunc(handler))))
 
 	bindplane.Manager().EnableProtocol(callbacks)
 
+	// disconnect agents using the HTTP transport when they stop polling
+	go callbacks.expirePollers(context.Background())
+
 	return nil
 }
 
@@ -84,8 +97,16 @@ const (
 type opampServer struct {
 	manager                 server.Manager
 	connections             *connections
+	pollers                 *pollers
+	sockets                 *sockets
+	pollingTimeout          time.Duration
+	endpoint                string
 	compatibleOpAMPVersions []string
 	logger                  *zap.Logger
+
+	// agentCertificates is true if the agent CA is enabled and agents are issued client certificates with their
+	// credentials
+	agentCertificates bool
 }
 
 var _ server.Protocol = (*opampServer)(nil)
@@ -95,6 +116,9 @@ func newServer(manager server.Manager, logger *zap.Logger) *opampServer {
 	return &opampServer{
 		manager:                 manager,
 		connections:             newConnections(),
+		pollers:                 newPollers(),
+		sockets:                 newSockets(),
+		pollingTimeout:          PollingTimeout,
 		compatibleOpAMPVersions: compatibleOpAMPVersions,
 		logger:                  logger,
 	}
@@ -138,7 +162,7 @@ func (s *opampServer) OnConnecting(request *http.Request) opamp.ConnectionRespon
 		}
 	}
 
-	accept := s.manager.VerifySecretKey(ctx, headers.secretKey)
+	accept := s.manager.VerifySecretKey(ctx, headers.id, headers.secretKey)
 	if !accept {
 		return opamp.ConnectionResponse{
 			Accept:         false,
@@ -146,6 +170,28 @@ func (s *opampServer) OnConnecting(request *http.Request) opamp.ConnectionRespon
 		}
 	}
 
+	if s.agentCertificates {
+		if err := s.manager.VerifyAgentCertificate(ctx, headers.id, peerCertificate(request)); err != nil {
+			s.logger.Info("rejecting agent certificate", zap.String("agentID", headers.id), zap.Error(err))
+			return opamp.ConnectionResponse{
+				Accept:         false,
+				HTTPStatusCode: http.StatusUnauthorized,
+			}
+		}
+	}
+
+	rule, err := s.manager.DeniedAgent(ctx, &model.Agent{ID: headers.id, HostName: headers.hostname})
+	if err != nil {
+		s.logger.Error("unable to check the agent deny rules", zap.String("agentID", headers.id), zap.Error(err))
+	}
+	if rule != nil {
+		s.logger.Info("rejecting denied agent", zap.String("agentID", headers.id), zap.String("rule", rule.ID()))
+		return opamp.ConnectionResponse{
+			Accept:         false,
+			HTTPStatusCode: http.StatusForbidden,
+		}
+	}
+
 	return opamp.ConnectionResponse{
 		Accept:         true,
 		HTTPStatusCode: http.StatusOK,
@@ -162,7 +208,7 @@ type agentHeaders struct {
 
 func parseAgentHeaders(request *http.Request) *agentHeaders {
 	authHeader := request.Header.Get(headerAuthorization)
-	secretKey := strings.Replace(authHeader, "Secret-Key ", "", 1)
+	secretKey := strings.Replace(authHeader, agentCredentialPrefix, "", 1)
 	if secretKey == authHeader {
 		// check for missing Secret-Key identifier
 		secretKey = ""
@@ -176,6 +222,14 @@ func parseAgentHeaders(request *http.Request) *agentHeaders {
 	}
 }
 
+// peerCertificate returns the client certificate presented by the agent or nil if it didn't present one
+func peerCertificate(request *http.Request) *x509.Certificate {
+	if request.TLS == nil || len(request.TLS.PeerCertificates) == 0 {
+		return nil
+	}
+	return request.TLS.PeerCertificates[0]
+}
+
 // OnConnected is called when the WebSocket connection is successfully established after OnConnecting() returns and the
 // HTTP connection is upgraded to WebSocket.
 //
@@ -201,15 +255,41 @@ func (s *opampServer) OnMessage(conn opamp.Connection, message *protobufs.AgentT
 	)
 
 	s.logger.Info("OpAMP agent message", zap.String("agentID", agentID), zap.Strings("submessages", messageComponents(message)))
-	s.connections.connect(conn, agentID)
 
 	response := &protobufs.ServerToAgent{
 		InstanceUid:  agentID,
 		Capabilities: capabilities,
 	}
 
+	// messages from other hosts using the ID of a connected agent are not applied to the agent until the conflict is
+	// resolved by assigning them new IDs
+	if s.connections.duplicateAgentID(conn) == agentID {
+		return response
+	}
+	if conflict := s.agentConflict(ctx, conn, agentID, message); conflict != nil {
+		s.connections.addDuplicate(conn, agentID)
+		if err := s.manager.ReportAgentConflict(ctx, agentID, conflict); err != nil {
+			s.logger.Error("unable to report the agent conflict", zap.String("agentID", agentID), zap.Error(err))
+		}
+		return response
+	}
+
+	if s.connections.connect(conn, agentID) {
+		// let the other nodes know that the agent is connected to this node
+		s.manager.AgentConnected(ctx, agentID)
+	}
+
 	// verify the configuration and modify the response message
 	err := s.verifyAgentConfig(ctx, conn, agentID, message, response)
+	if errors.Is(err, errAgentDenied) {
+		s.logger.Info("disconnecting denied agent", zap.String("agentID", agentID), zap.Error(err))
+		response.ErrorResponse = &protobufs.ServerErrorResponse{
+			Type:         protobufs.ServerErrorResponse_BadRequest,
+			ErrorMessage: err.Error(),
+		}
+		s.disconnectDeniedAgent(conn)
+		return response
+	}
 	if err != nil {
 		s.logger.Error("error verifying the agent configuration", zap.Error(err))
 		// send an error response
@@ -219,6 +299,10 @@ func (s *opampServer) OnMessage(conn opamp.Connection, message *protobufs.AgentT
 			ErrorMessage: err.Error(),
 		}
 	}
+
+	if err := s.offerAgentCredential(ctx, agentID, message, response); err != nil {
+		s.logger.Error("error issuing the agent credential", zap.Error(err))
+	}
 	s.logger.Info("sending response to the agent", zap.Any("agentID", agentID), zap.Any("response", response))
 
 	return response
@@ -234,16 +318,30 @@ func (s *opampServer) OnConnectionClose(conn opamp.Connection) {
 	agentID := s.connections.agentID(conn)
 	s.logger.Info("OpAMP agent disconnected", zap.String("AgentID", agentID))
 	s.connections.disconnect(conn)
+	s.sockets.remove(conn.RemoteAddr().String())
+	if poller, ok := conn.(*pollingConnection); ok {
+		s.pollers.remove(poller)
+	}
+	if duplicateID, ok := s.connections.removeDuplicate(conn); ok {
+		s.closeDuplicate(ctx, duplicateID)
+		return
+	}
 	if agentID == "" {
 		return
 	}
+	s.manager.AgentDisconnected(ctx, agentID)
+	duplicates := s.connections.duplicateConnections(agentID)
 	_, err := s.manager.UpsertAgent(ctx, agentID, func(agent *model.Agent) {
+		if len(duplicates) > 0 {
+			agent.ClearConflict()
+		}
 		agent.Disconnect()
 	})
 	if err != nil {
 		s.logger.Error("error trying to save disconnected state of agent", zap.String("agentID", agentID), zap.Error(err))
 		return
 	}
+	s.promoteDuplicates(agentID, duplicates)
 }
 
 // ----------------------------------------------------------------------
@@ -261,23 +359,37 @@ func (s *opampServer) ConnectedAgentIDs(ctx context.Context) ([]string, error) {
 	return s.connections.agentIDs(), nil
 }
 
+// Disconnect closes the connection to the specified agent ID. Agents using the HTTP transport are forgotten and
+// reconnect on the next poll.
 func (s *opampServer) Disconnect(agentID string) bool {
 	conn := s.connections.connection(agentID)
 	if conn != nil {
 		s.connections.disconnect(conn)
+		if poller, ok := conn.(*pollingConnection); ok {
+			s.pollers.remove(poller)
+		} else {
+			s.sockets.close(conn.RemoteAddr().String())
+		}
 		return true
 	}
 	return false
 }
 
-// Connected returns true if the specified agent ID is connected
+// Connected returns true if the specified agent ID is connected. Agents using the HTTP transport are connected until
+// they stop polling for longer than the polling timeout.
 func (s *opampServer) Connected(agentID string) bool {
 	return s.connections.connected(agentID)
 }
 
 // UpdateAgent should send a message to the specified agent to update the configuration to match the
-// specified configuration.
+// specified configuration. Messages to agents using the HTTP transport are queued until the next poll.
 func (s *opampServer) UpdateAgent(ctx context.Context, agent *model.Agent, updates *server.AgentUpdates) error {
+	if updates.AssignNewIDs {
+		if err := s.assignNewIDs(ctx, agent.ID); err != nil {
+			return fmt.Errorf("unable to assign new IDs to the hosts conflicting with agent [%s]: %w", agent.ID, err)
+		}
+	}
+
 	conn := s.connections.connection(agent.ID)
 	if conn == nil {
 		// agent not connected, nothing to do
@@ -350,8 +462,20 @@ func (s *opampServer) UpdateAgent(ctx context.Context, agent *model.Agent, updat
 		}
 	}
 
-	// if the message doesn't have a new configuration or a new package available, do nothing
-	if serverToAgent.RemoteConfig == nil && serverToAgent.PackagesAvailable == nil {
+	if updates.RotateCredential {
+		s.rotateAgentCredential(ctx, agent, serverToAgent)
+	}
+
+	if updates.Restart {
+		s.logger.Info("sending restart command to agent", zap.String("agentID", agent.ID))
+		serverToAgent.Command = &protobufs.ServerToAgentCommand{
+			Type: protobufs.ServerToAgentCommand_Restart,
+		}
+	}
+
+	// if the message doesn't have a new configuration, a new package available, a command, or new connection settings,
+	// do nothing
+	if serverToAgent.RemoteConfig == nil && serverToAgent.PackagesAvailable == nil && serverToAgent.Command == nil && serverToAgent.ConnectionSettings == nil {
 		return nil
 	}
 
@@ -407,6 +531,102 @@ func (s *opampServer) send(ctx context.Context, conn opamp.Connection, msg *prot
 }
 
 // ----------------------------------------------------------------------
+// duplicate agent IDs
+
+// agentConflict returns an AgentConflict if the message describes a different host than the agent with the same ID
+// that is already connected, e.g. a virtual machine cloned from an image that includes the agent configuration
+func (s *opampServer) agentConflict(ctx context.Context, conn opamp.Connection, agentID string, message *protobufs.AgentToServer) *model.AgentConflict {
+	description := message.GetAgentDescription()
+	if description == nil || s.connections.agentID(conn) == agentID {
+		return nil
+	}
+	if !s.connections.connected(agentID) && !s.manager.Connected(agentID) {
+		return nil
+	}
+	agent, err := s.manager.Agent(ctx, agentID)
+	if err != nil || agent == nil {
+		return nil
+	}
+
+	desc := parseAgentDescription(description)
+	conflict := &model.AgentConflict{
+		HostName:   desc.Hostname,
+		MacAddress: desc.MacAddress,
+	}
+	if addr := conn.RemoteAddr(); addr != nil {
+		conflict.RemoteAddress = addr.String()
+	}
+	if !conflict.ConflictsWith(agent) {
+		return nil
+	}
+	return conflict
+}
+
+// assignNewIDs sends a new ID to each host connected to this node with the ID of the agent while it was already
+// connected. The hosts are asked to report their full state so that they connect using the new ID.
+func (s *opampServer) assignNewIDs(ctx context.Context, agentID string) error {
+	var lastErr error
+	for _, conn := range s.connections.duplicateConnections(agentID) {
+		newID := uuid.NewString()
+		s.logger.Info("assigning a new ID to the host conflicting with the agent", zap.String("agentID", agentID), zap.String("newAgentID", newID))
+		s.connections.removeDuplicate(conn)
+		err := s.send(ctx, conn, &protobufs.ServerToAgent{
+			InstanceUid:  agentID,
+			Capabilities: capabilities,
+			Flags:        protobufs.ServerToAgent_ReportFullState,
+			AgentIdentification: &protobufs.AgentIdentification{
+				NewInstanceUid: newID,
+			},
+		})
+		if err != nil {
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+// closeDuplicate clears the conflict of the agent after the last host conflicting with it disconnects from this node
+func (s *opampServer) closeDuplicate(ctx context.Context, agentID string) {
+	s.logger.Info("OpAMP host conflicting with the agent disconnected", zap.String("agentID", agentID))
+	if len(s.connections.duplicateConnections(agentID)) > 0 {
+		return
+	}
+	_, err := s.manager.UpsertAgent(ctx, agentID, func(agent *model.Agent) {
+		agent.ClearConflict()
+	})
+	if err != nil {
+		s.logger.Error("unable to clear the agent conflict", zap.String("agentID", agentID), zap.Error(err))
+	}
+}
+
+// promoteDuplicates asks the hosts that conflicted with a disconnected agent to report their full state. The agent may
+// have reconnected from another address before its previous connection was closed, and the first host to report its
+// state becomes the agent.
+func (s *opampServer) promoteDuplicates(agentID string, duplicates []opamp.Connection) {
+	for _, conn := range duplicates {
+		s.connections.removeDuplicate(conn)
+		err := s.send(context.Background(), conn, &protobufs.ServerToAgent{
+			InstanceUid:  agentID,
+			Capabilities: capabilities,
+			Flags:        protobufs.ServerToAgent_ReportFullState,
+		})
+		if err != nil {
+			s.logger.Error("unable to request the state of the host conflicting with the agent", zap.String("agentID", agentID), zap.Error(err))
+		}
+	}
+}
+
+// ----------------------------------------------------------------------
+
+// errAgentDenied is returned when an agent matches an AgentDenyRule after describing itself
+var errAgentDenied = errors.New("agent denied")
+
+// disconnectDeniedAgent closes the connection and marks the agent disconnected. The agent remains in the store so that
+// it is rejected by OnConnecting if it reconnects.
+func (s *opampServer) disconnectDeniedAgent(conn opamp.Connection) {
+	s.sockets.close(conn.RemoteAddr().String())
+	s.OnConnectionClose(conn)
+}
 
 func (s *opampServer) verifyAgentConfig(ctx context.Context, conn opamp.Connection, agentID string, message *protobufs.AgentToServer, response *protobufs.ServerToAgent) error {
 	ctx, span := tracer.Start(ctx, "opamp/verifyAgentConfig")
@@ -418,6 +638,17 @@ func (s *opampServer) verifyAgentConfig(ctx context.Context, conn opamp.Connecti
 		return fmt.Errorf("unable to update agent [%s]: %w", agentID, err)
 	}
 
+	// the host name and MAC address are only known after the agent describes itself
+	if message.GetAgentDescription() != nil {
+		rule, err := s.manager.DeniedAgent(ctx, agent)
+		if err != nil {
+			s.logger.Error("unable to check the agent deny rules", zap.String("agentID", agentID), zap.Error(err))
+		}
+		if rule != nil {
+			return fmt.Errorf("%w by rule %s", errAgentDenied, rule.ID())
+		}
+	}
+
 	return s.updateAgentConfig(ctx, agent, state, response)
 }
 
@@ -430,6 +661,11 @@ func (s *opampServer) updateAgentConfig(ctx context.Context, agent *model.Agent,
 		return nil
 	}
 
+	if agent.Status == model.Pending {
+		s.logger.Info("agent is pending approval, configuration will not be sent", zap.String("agentID", agent.ID))
+		return nil
+	}
+
 	agentConfiguration, err := agentRawConfiguration.Parse()
 	if err != nil {
 		// TODO(andy): ignore the current unparsable configuration and force new configuration?
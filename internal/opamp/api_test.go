@@ -15,15 +15,23 @@
 package opamp
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/gorilla/websocket"
 	"github.com/observiq/bindplane-op/common"
 	"github.com/observiq/bindplane-op/internal/server"
 	"github.com/observiq/bindplane-op/internal/server/mocks"
@@ -31,6 +39,7 @@ import (
 	"github.com/observiq/bindplane-op/model"
 	"github.com/observiq/bindplane-op/model/observiq"
 	"github.com/open-telemetry/opamp-go/protobufs"
+	opampSvr "github.com/open-telemetry/opamp-go/server"
 	opamp "github.com/open-telemetry/opamp-go/server/types"
 
 	"github.com/stretchr/testify/mock"
@@ -247,6 +256,7 @@ func TestServerOnConnecting(t *testing.T) {
 	tests := []struct {
 		name          string
 		authorization string
+		hostname      string
 		expect        opamp.ConnectionResponse
 	}{
 		{
@@ -289,13 +299,26 @@ func TestServerOnConnecting(t *testing.T) {
 				HTTPStatusCode: http.StatusUnauthorized,
 			},
 		},
+		{
+			name:          "denied host name",
+			authorization: fmt.Sprintf("Secret-Key %s", goodKey),
+			hostname:      "denied-host",
+			expect: opamp.ConnectionResponse{
+				Accept:         false,
+				HTTPStatusCode: http.StatusForbidden,
+			},
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			manager := &mocks.Manager{}
-			manager.On("VerifySecretKey", mock.Anything, goodKey).Return(true)
-			manager.On("VerifySecretKey", mock.Anything, badKey).Return(false)
-			manager.On("VerifySecretKey", mock.Anything, noKey).Return(false)
+			manager.On("VerifySecretKey", mock.Anything, mock.Anything, goodKey).Return(true)
+			manager.On("VerifySecretKey", mock.Anything, mock.Anything, badKey).Return(false)
+			manager.On("VerifySecretKey", mock.Anything, mock.Anything, noKey).Return(false)
+			manager.On("DeniedAgent", mock.Anything, mock.MatchedBy(func(agent *model.Agent) bool {
+				return agent.HostName == "denied-host"
+			})).Return(&model.AgentDenyRule{Field: model.AgentDenyFieldHostName, Value: "denied-host"}, nil)
+			manager.On("DeniedAgent", mock.Anything, mock.Anything).Return(nil, nil)
 			server := testServer(manager)
 			server.compatibleOpAMPVersions = []string{"v0.2.0"}
 			request := &http.Request{
@@ -306,6 +329,9 @@ func TestServerOnConnecting(t *testing.T) {
 			if test.authorization != "" {
 				request.Header["Authorization"] = []string{test.authorization}
 			}
+			if test.hostname != "" {
+				request.Header["Agent-Hostname"] = []string{test.hostname}
+			}
 			response := server.OnConnecting(request)
 			require.Equal(t, test.expect.Accept, response.Accept)
 			require.Equal(t, test.expect.HTTPStatusCode, response.HTTPStatusCode)
@@ -313,6 +339,78 @@ func TestServerOnConnecting(t *testing.T) {
 	}
 }
 
+func TestServerOnConnectingCertificate(t *testing.T) {
+	certificate := &x509.Certificate{Subject: pkix.Name{CommonName: "agent"}}
+	tests := []struct {
+		name              string
+		agentCertificates bool
+		tls               *tls.ConnectionState
+		verifyErr         error
+		expectAccept      bool
+	}{
+		{
+			name:         "agent ca disabled",
+			tls:          &tls.ConnectionState{PeerCertificates: []*x509.Certificate{certificate}},
+			verifyErr:    server.ErrAgentCertificateMismatch,
+			expectAccept: true,
+		},
+		{
+			name:              "valid certificate",
+			agentCertificates: true,
+			tls:               &tls.ConnectionState{PeerCertificates: []*x509.Certificate{certificate}},
+			expectAccept:      true,
+		},
+		{
+			name:              "no certificate",
+			agentCertificates: true,
+			expectAccept:      true,
+		},
+		{
+			name:              "mismatched certificate",
+			agentCertificates: true,
+			tls:               &tls.ConnectionState{PeerCertificates: []*x509.Certificate{certificate}},
+			verifyErr:         server.ErrAgentCertificateMismatch,
+		},
+		{
+			name:              "certificate required",
+			agentCertificates: true,
+			verifyErr:         server.ErrAgentCertificateRequired,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var expectCertificate *x509.Certificate
+			if test.tls != nil {
+				expectCertificate = certificate
+			}
+			manager := &mocks.Manager{}
+			manager.On("VerifySecretKey", mock.Anything, "agent", "key").Return(true)
+			manager.On("VerifyAgentCertificate", mock.Anything, "agent", expectCertificate).Return(test.verifyErr)
+			manager.On("DeniedAgent", mock.Anything, mock.Anything).Return(nil, nil)
+
+			s := testServer(manager)
+			s.compatibleOpAMPVersions = []string{"v0.2.0"}
+			s.agentCertificates = test.agentCertificates
+			request := &http.Request{
+				Header: http.Header{
+					"Opamp-Version": []string{"v0.2.0"},
+					"Agent-Id":      []string{"agent"},
+					"Authorization": []string{"Secret-Key key"},
+				},
+				TLS: test.tls,
+			}
+			response := s.OnConnecting(request)
+			require.Equal(t, test.expectAccept, response.Accept)
+			if !test.expectAccept {
+				require.Equal(t, http.StatusUnauthorized, response.HTTPStatusCode)
+			}
+			if !test.agentCertificates {
+				manager.AssertNotCalled(t, "VerifyAgentCertificate", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
 func makeAgentDescription(version string) *protobufs.AgentDescription {
 	return &protobufs.AgentDescription{
 		IdentifyingAttributes: []*protobufs.KeyValue{
@@ -373,6 +471,7 @@ service:
 		testMapStore,
 		nil,
 		logger,
+		nil,
 	)
 	require.NoError(t, err)
 
@@ -843,7 +942,7 @@ func TestOnConnectingOpAMPCompatibility(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		testManager, err := server.NewManager(&common.Server{SecretKey: "a0f1db77-818a-4f1a-81a3-7b6a9613ef41"}, nil, nil, zap.NewNop())
+		testManager, err := server.NewManager(&common.Server{SecretKey: "a0f1db77-818a-4f1a-81a3-7b6a9613ef41"}, nil, nil, zap.NewNop(), nil)
 		require.NoError(t, err)
 		testServer := newServer(testManager, zap.NewNop())
 		testServer.compatibleOpAMPVersions = []string{"v0.2.0"}
@@ -855,3 +954,360 @@ func TestOnConnectingOpAMPCompatibility(t *testing.T) {
 		})
 	}
 }
+
+// newTransportTestServer starts an httptest.Server that serves OpAMP over both the websocket and HTTP transports
+func newTransportTestServer(t *testing.T, config *common.Server) (*opampServer, server.Manager, *httptest.Server) {
+	testMapStore := store.NewMapStore(context.TODO(), store.Options{
+		SessionsSecret:   "supersecret-key",
+		MaxEventsToMerge: 1000,
+	}, zap.NewNop())
+	testManager, err := server.NewManager(config, testMapStore, nil, zap.NewNop(), nil)
+	require.NoError(t, err)
+
+	opampServer := testServer(testManager)
+	testManager.EnableProtocol(opampServer)
+
+	websocketHandler, err := opampSvr.New(zap.NewNop().Sugar()).Attach(opampSvr.Settings{Callbacks: opampServer})
+	require.NoError(t, err)
+
+	httpServer := httptest.NewServer(opampServer.handler(http.HandlerFunc(websocketHandler)))
+	t.Cleanup(httpServer.Close)
+
+	return opampServer, testManager, httpServer
+}
+
+func transportTestHeaders(secretKey string) http.Header {
+	return http.Header{
+		"Authorization": []string{"Secret-Key " + secretKey},
+		"Opamp-Version": []string{"v0.2.0"},
+		"Agent-Id":      []string{"4ec02b0f-3cb7-498d-9172-bfaa28718ee8"},
+		"Agent-Version": []string{"v1.6.0"},
+	}
+}
+
+func TestServerHTTPTransport(t *testing.T) {
+	agentID := "4ec02b0f-3cb7-498d-9172-bfaa28718ee8"
+	opampServer, testManager, httpServer := newTransportTestServer(t, &common.Server{SecretKey: "secret"})
+	ctx := context.Background()
+
+	poll := func(t *testing.T, secretKey string, body []byte) (*http.Response, *protobufs.ServerToAgent) {
+		request, err := http.NewRequest(http.MethodPost, httpServer.URL, bytes.NewReader(body))
+		require.NoError(t, err)
+		request.Header = transportTestHeaders(secretKey)
+		request.Header.Set(headerContentType, contentTypeProtobuf)
+
+		response, err := http.DefaultClient.Do(request)
+		require.NoError(t, err)
+		defer response.Body.Close()
+
+		if response.StatusCode != http.StatusOK {
+			return response, nil
+		}
+		require.Equal(t, contentTypeProtobuf, response.Header.Get(headerContentType))
+		data, err := io.ReadAll(response.Body)
+		require.NoError(t, err)
+		var serverToAgent protobufs.ServerToAgent
+		require.NoError(t, proto.Unmarshal(data, &serverToAgent))
+		return response, &serverToAgent
+	}
+
+	message := func(t *testing.T, sequenceNum uint64) []byte {
+		data, err := proto.Marshal(&protobufs.AgentToServer{
+			InstanceUid: agentID,
+			SequenceNum: sequenceNum,
+		})
+		require.NoError(t, err)
+		return data
+	}
+
+	t.Run("rejects bad secret key", func(t *testing.T) {
+		response, _ := poll(t, "wrong", message(t, 1))
+		require.Equal(t, http.StatusUnauthorized, response.StatusCode)
+		require.False(t, opampServer.Connected(agentID))
+	})
+
+	t.Run("rejects malformed message", func(t *testing.T) {
+		response, _ := poll(t, "secret", []byte("not protobuf"))
+		require.Equal(t, http.StatusBadRequest, response.StatusCode)
+		require.False(t, opampServer.Connected(agentID))
+	})
+
+	t.Run("first poll connects the agent", func(t *testing.T) {
+		response, serverToAgent := poll(t, "secret", message(t, 1))
+		require.Equal(t, http.StatusOK, response.StatusCode)
+		require.Equal(t, agentID, serverToAgent.InstanceUid)
+		require.Equal(t, protobufs.ServerToAgent_ReportFullState, serverToAgent.Flags)
+		require.True(t, opampServer.Connected(agentID))
+
+		agent, err := testManager.Agent(ctx, agentID)
+		require.NoError(t, err)
+		require.Equal(t, "Connected", agent.StatusDisplayText())
+	})
+
+	t.Run("updates are queued until the next poll", func(t *testing.T) {
+		agent, err := testManager.Agent(ctx, agentID)
+		require.NoError(t, err)
+		require.NoError(t, opampServer.UpdateAgent(ctx, agent, &server.AgentUpdates{Restart: true}))
+
+		_, serverToAgent := poll(t, "secret", message(t, 2))
+		require.Equal(t, protobufs.ServerToAgentCommand_Restart, serverToAgent.GetCommand().GetType())
+
+		// the queue is cleared by the poll
+		_, serverToAgent = poll(t, "secret", message(t, 3))
+		require.Nil(t, serverToAgent.Command)
+	})
+
+	t.Run("agent disconnects when it stops polling", func(t *testing.T) {
+		opampServer.disconnectExpiredPollers(time.Now())
+		require.True(t, opampServer.Connected(agentID))
+
+		opampServer.disconnectExpiredPollers(time.Now().Add(2 * PollingTimeout))
+		require.False(t, opampServer.Connected(agentID))
+
+		agent, err := testManager.Agent(ctx, agentID)
+		require.NoError(t, err)
+		require.Equal(t, "Disconnected", agent.StatusDisplayText())
+	})
+}
+
+func TestServerWebsocketTransport(t *testing.T) {
+	agentID := "4ec02b0f-3cb7-498d-9172-bfaa28718ee8"
+	opampServer, testManager, httpServer := newTransportTestServer(t, &common.Server{SecretKey: "secret"})
+	ctx := context.Background()
+
+	url := "ws" + strings.TrimPrefix(httpServer.URL, "http")
+
+	_, response, err := websocket.DefaultDialer.Dial(url, transportTestHeaders("wrong"))
+	require.Error(t, err)
+	require.Equal(t, http.StatusUnauthorized, response.StatusCode)
+
+	conn, _, err := websocket.DefaultDialer.Dial(url, transportTestHeaders("secret"))
+	require.NoError(t, err)
+
+	receive := func(t *testing.T) *protobufs.ServerToAgent {
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+		_, data, err := conn.ReadMessage()
+		require.NoError(t, err)
+		var serverToAgent protobufs.ServerToAgent
+		require.NoError(t, proto.Unmarshal(data, &serverToAgent))
+		return &serverToAgent
+	}
+
+	data, err := proto.Marshal(&protobufs.AgentToServer{InstanceUid: agentID, SequenceNum: 1})
+	require.NoError(t, err)
+	require.NoError(t, conn.WriteMessage(websocket.BinaryMessage, data))
+
+	serverToAgent := receive(t)
+	require.Equal(t, agentID, serverToAgent.InstanceUid)
+	require.Equal(t, protobufs.ServerToAgent_ReportFullState, serverToAgent.Flags)
+	require.True(t, opampServer.Connected(agentID))
+
+	// the websocket is the connection until it is closed
+	require.NoError(t, conn.WriteMessage(websocket.BinaryMessage, data))
+	serverToAgent = receive(t)
+	require.Equal(t, agentID, serverToAgent.InstanceUid)
+	require.Empty(t, opampServer.pollers.expired(time.Now().Add(2*PollingTimeout), PollingTimeout))
+
+	require.NoError(t, conn.Close())
+	require.Eventually(t, func() bool {
+		agent, err := testManager.Agent(ctx, agentID)
+		return err == nil && !opampServer.Connected(agentID) && agent.StatusDisplayText() == "Disconnected"
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestServerRequireApproval(t *testing.T) {
+	agentID := "4ec02b0f-3cb7-498d-9172-bfaa28718ee8"
+	opampServer, testManager, _ := newTransportTestServer(t, &common.Server{SecretKey: "secret", RequireApproval: true})
+	ctx := context.Background()
+
+	conn := &mocks.Connection{}
+	conn.On("RemoteAddr").Return(&TestAddr{network: "tcp", address: "0.0.0.0:0"})
+
+	message := func(sequenceNum uint64) *protobufs.AgentToServer {
+		return &protobufs.AgentToServer{
+			InstanceUid: agentID,
+			SequenceNum: sequenceNum,
+			EffectiveConfig: &protobufs.EffectiveConfig{
+				ConfigMap: &protobufs.AgentConfigMap{ConfigMap: map[string]*protobufs.AgentConfigFile{}},
+			},
+		}
+	}
+
+	response := opampServer.OnMessage(conn, message(1))
+	require.Nil(t, response.RemoteConfig)
+
+	agent, err := testManager.Agent(ctx, agentID)
+	require.NoError(t, err)
+	require.Equal(t, model.Pending, agent.Status)
+
+	// the agent remains pending after reconnecting
+	opampServer.OnConnectionClose(conn)
+	opampServer.OnMessage(conn, message(2))
+	agent, err = testManager.Agent(ctx, agentID)
+	require.NoError(t, err)
+	require.Equal(t, model.Pending, agent.Status)
+
+	approved, err := testManager.ApproveAgents(ctx, []string{agentID, "unknown"})
+	require.NoError(t, err)
+	require.Equal(t, []string{agentID}, approved)
+
+	agent, err = testManager.Agent(ctx, agentID)
+	require.NoError(t, err)
+	require.Equal(t, model.Connected, agent.Status)
+
+	opampServer.OnMessage(conn, message(3))
+	agent, err = testManager.Agent(ctx, agentID)
+	require.NoError(t, err)
+	require.NotEqual(t, model.Pending, agent.Status)
+
+	// approved agents are not approved again
+	approved, err = testManager.ApproveAgents(ctx, []string{agentID})
+	require.NoError(t, err)
+	require.Empty(t, approved)
+}
+
+func TestServerDeniedAgent(t *testing.T) {
+	agentID := "4ec02b0f-3cb7-498d-9172-bfaa28718ee8"
+	opampServer, testManager, httpServer := newTransportTestServer(t, &common.Server{SecretKey: "secret"})
+	ctx := context.Background()
+
+	rule, err := model.NewAgentDenyRule(model.AgentDenyFieldMacAddress, "00:11:22:33:44:55", "cloned")
+	require.NoError(t, err)
+	removed, err := testManager.DenyAgents(ctx, rule)
+	require.NoError(t, err)
+	require.Empty(t, removed)
+
+	url := "ws" + strings.TrimPrefix(httpServer.URL, "http")
+	conn, _, err := websocket.DefaultDialer.Dial(url, transportTestHeaders("secret"))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	data, err := proto.Marshal(&protobufs.AgentToServer{
+		InstanceUid: agentID,
+		SequenceNum: 1,
+		AgentDescription: &protobufs.AgentDescription{
+			NonIdentifyingAttributes: []*protobufs.KeyValue{
+				{
+					Key:   "host.mac_address",
+					Value: &protobufs.AnyValue{Value: &protobufs.AnyValue_StringValue{StringValue: "00-11-22-33-44-55"}},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, conn.WriteMessage(websocket.BinaryMessage, data))
+
+	// the server closes the websocket
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	_, _, err = conn.ReadMessage()
+	require.Error(t, err)
+	require.False(t, opampServer.Connected(agentID))
+
+	// the stored agent has the denied MAC address so it can't reconnect
+	_, response, err := websocket.DefaultDialer.Dial(url, transportTestHeaders("secret"))
+	require.Error(t, err)
+	require.Equal(t, http.StatusForbidden, response.StatusCode)
+}
+
+func TestServerAgentConflict(t *testing.T) {
+	agentID := "4ec02b0f-3cb7-498d-9172-bfaa28718ee8"
+	opampServer, testManager, _ := newTransportTestServer(t, &common.Server{SecretKey: "secret"})
+	ctx := context.Background()
+
+	connection := func(address string) *mocks.Connection {
+		conn := &mocks.Connection{}
+		conn.On("RemoteAddr").Return(&TestAddr{network: "tcp", address: address})
+		conn.On("Send", mock.Anything, mock.Anything).Return(nil)
+		return conn
+	}
+	message := func(hostname string, sequenceNum uint64) *protobufs.AgentToServer {
+		message := &protobufs.AgentToServer{
+			InstanceUid:  agentID,
+			SequenceNum:  sequenceNum,
+			Capabilities: protobufs.AgentCapabilities_ReportsStatus,
+		}
+		if hostname != "" {
+			message.AgentDescription = &protobufs.AgentDescription{
+				NonIdentifyingAttributes: []*protobufs.KeyValue{
+					{
+						Key:   "host.name",
+						Value: &protobufs.AnyValue{Value: &protobufs.AnyValue_StringValue{StringValue: hostname}},
+					},
+				},
+			}
+		}
+		return message
+	}
+	sent := func(conn *mocks.Connection) []*protobufs.ServerToAgent {
+		messages := []*protobufs.ServerToAgent{}
+		for _, call := range conn.Calls {
+			if call.Method == "Send" {
+				messages = append(messages, call.Arguments.Get(1).(*protobufs.ServerToAgent))
+			}
+		}
+		return messages
+	}
+
+	original := connection("10.0.0.1:51000")
+	opampServer.OnMessage(original, message("web-1", 1))
+
+	// the same host reconnecting is not a conflict
+	reconnected := connection("10.0.0.1:52000")
+	opampServer.OnMessage(reconnected, message("web-1", 2))
+	require.Equal(t, reconnected, opampServer.connections.connection(agentID))
+	opampServer.OnConnectionClose(original)
+	require.True(t, opampServer.Connected(agentID), "closing the previous connection should not disconnect the agent")
+
+	// a cloned host is a conflict and does not replace the agent connection
+	clone := connection("10.0.0.2:51000")
+	opampServer.OnMessage(clone, message("web-2", 1))
+	opampServer.OnMessage(clone, message("", 2))
+	require.Equal(t, reconnected, opampServer.connections.connection(agentID))
+
+	agent, err := testManager.Agent(ctx, agentID)
+	require.NoError(t, err)
+	require.Equal(t, model.Conflict, agent.Status)
+	require.Equal(t, "web-1", agent.HostName)
+	require.Equal(t, "web-2", agent.Conflict.HostName)
+	require.Equal(t, "10.0.0.2:51000", agent.Conflict.RemoteAddress)
+
+	// the conflicting host is assigned a new ID
+	require.NoError(t, testManager.ResolveAgentConflict(ctx, agentID))
+	messages := sent(clone)
+	require.Len(t, messages, 1)
+	newID := messages[0].GetAgentIdentification().GetNewInstanceUid()
+	require.NotEmpty(t, newID)
+	require.NotEqual(t, agentID, newID)
+
+	agent, err = testManager.Agent(ctx, agentID)
+	require.NoError(t, err)
+	require.Equal(t, model.Connected, agent.Status)
+	require.Nil(t, agent.Conflict)
+	require.ErrorIs(t, testManager.ResolveAgentConflict(ctx, agentID), server.ErrNoAgentConflict)
+
+	// the host connects with the new ID
+	renamed := message("web-2", 1)
+	renamed.InstanceUid = newID
+	opampServer.OnMessage(clone, renamed)
+	require.Equal(t, clone, opampServer.connections.connection(newID))
+	require.Equal(t, reconnected, opampServer.connections.connection(agentID))
+
+	// a host that conflicts with an agent that disconnects is asked to report its state
+	another := connection("10.0.0.3:51000")
+	opampServer.OnMessage(another, message("web-3", 1))
+	opampServer.OnConnectionClose(reconnected)
+
+	agent, err = testManager.Agent(ctx, agentID)
+	require.NoError(t, err)
+	require.Equal(t, model.Disconnected, agent.Status)
+	require.Nil(t, agent.Conflict)
+	messages = sent(another)
+	require.Len(t, messages, 1)
+	require.Equal(t, protobufs.ServerToAgent_ReportFullState, messages[0].Flags)
+
+	opampServer.OnMessage(another, message("web-3", 3))
+	require.Equal(t, another, opampServer.connections.connection(agentID))
+	agent, err = testManager.Agent(ctx, agentID)
+	require.NoError(t, err)
+	require.Equal(t, "web-3", agent.HostName)
+}
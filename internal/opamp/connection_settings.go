@@ -0,0 +1,121 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opamp
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"time"
+
+	"github.com/open-telemetry/opamp-go/protobufs"
+	"go.uber.org/zap"
+
+	"github.com/observiq/bindplane-op/internal/agentca"
+	"github.com/observiq/bindplane-op/model"
+)
+
+// agentCredentialPrefix is the prefix of the Authorization header used by agents, e.g. Authorization: Secret-Key 1234
+const agentCredentialPrefix = "Secret-Key "
+
+// offerAgentCredential issues a credential to agents that accept connection settings and haven't been issued a
+// credential. The agent uses the credential instead of the enrollment token or secret key used to install it. When
+// the agent CA is enabled, a new credential is also issued if the agent doesn't have a certificate or its certificate
+// is due for renewal. Agents with a revoked certificate are not issued a new one.
+func (s *opampServer) offerAgentCredential(ctx context.Context, agentID string, message *protobufs.AgentToServer, response *protobufs.ServerToAgent) error {
+	if s.endpoint == "" || !hasCapability(message, protobufs.AgentCapabilities_AcceptsOpAMPConnectionSettings) {
+		return nil
+	}
+	credential, err := s.manager.AgentCredential(ctx, agentID)
+	if err != nil {
+		return fmt.Errorf("unable to get the credential for agent [%s]: %w", agentID, err)
+	}
+	if credential != nil && credential.Issued() && !s.certificateDue(credential) {
+		return nil
+	}
+	return s.issueAgentCredential(ctx, agentID, response)
+}
+
+// certificateDue returns true if the agent CA is enabled and the agent needs a new certificate
+func (s *opampServer) certificateDue(credential *model.AgentCredential) bool {
+	if !s.agentCertificates {
+		return false
+	}
+	if credential.CertificateRevoked() {
+		return false
+	}
+	return !credential.CertificateIssued() || credential.CertificateRenewalDue(time.Now())
+}
+
+// issueAgentCredential issues a new credential to the agent and adds it to the connection settings of the response.
+// When the agent CA is enabled, a new client certificate is issued with the credential.
+func (s *opampServer) issueAgentCredential(ctx context.Context, agentID string, response *protobufs.ServerToAgent) error {
+	secret, err := s.manager.IssueAgentCredential(ctx, agentID)
+	if err != nil {
+		return fmt.Errorf("unable to issue a credential for agent [%s]: %w", agentID, err)
+	}
+	var certificate *agentca.Certificate
+	if s.agentCertificates {
+		certificate, err = s.manager.IssueAgentCertificate(ctx, agentID)
+		if err != nil {
+			return fmt.Errorf("unable to issue a certificate for agent [%s]: %w", agentID, err)
+		}
+	}
+	s.logger.Info("issuing credential to agent", zap.String("agentID", agentID), zap.Bool("certificate", certificate != nil))
+	response.ConnectionSettings = connectionSettings(s.endpoint, secret, certificate)
+	response.Capabilities |= protobufs.ServerCapabilities_OffersConnectionSettings
+	return nil
+}
+
+// rotateAgentCredential issues a new credential to the agent if it accepts connection settings. The previous credential
+// is accepted until the agent reconnects with the new credential.
+func (s *opampServer) rotateAgentCredential(ctx context.Context, agent *model.Agent, response *protobufs.ServerToAgent) {
+	state, err := decodeState(agent.State)
+	if err != nil || !hasCapability(&state.Status, protobufs.AgentCapabilities_AcceptsOpAMPConnectionSettings) || s.endpoint == "" {
+		s.logger.Warn("unable to rotate the credential of an agent that doesn't accept connection settings", zap.String("agentID", agent.ID))
+		return
+	}
+	if err := s.issueAgentCredential(ctx, agent.ID, response); err != nil {
+		s.logger.Error("unable to rotate the agent credential", zap.String("agentID", agent.ID), zap.Error(err))
+	}
+}
+
+// connectionSettings returns the OpAMP connection settings that authenticate with the specified secret and the client
+// certificate, if not nil
+func connectionSettings(endpoint, secret string, certificate *agentca.Certificate) *protobufs.ConnectionSettingsOffers {
+	authorization := agentCredentialPrefix + secret
+	hash := sha256.New()
+	hash.Write([]byte(endpoint + "\n" + authorization))
+	settings := &protobufs.OpAMPConnectionSettings{
+		DestinationEndpoint: endpoint,
+		Headers: &protobufs.Headers{
+			Headers: []*protobufs.Header{
+				{Key: headerAuthorization, Value: authorization},
+			},
+		},
+	}
+	if certificate != nil {
+		hash.Write(certificate.CertificatePEM)
+		settings.Certificate = &protobufs.TLSCertificate{
+			PublicKey:   certificate.CertificatePEM,
+			PrivateKey:  certificate.PrivateKeyPEM,
+			CaPublicKey: certificate.CAPEM,
+		}
+	}
+	return &protobufs.ConnectionSettingsOffers{
+		Hash:  hash.Sum(nil),
+		Opamp: settings,
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opamp
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/open-telemetry/opamp-go/protobufs"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/observiq/bindplane-op/internal/agentca"
+	"github.com/observiq/bindplane-op/internal/server/mocks"
+	"github.com/observiq/bindplane-op/model"
+)
+
+func TestOfferAgentCredential(t *testing.T) {
+	issued := &model.AgentCredential{AgentID: "issued", Hash: "hash"}
+	tests := []struct {
+		name         string
+		endpoint     string
+		capabilities protobufs.AgentCapabilities
+		credential   *model.AgentCredential
+		expectOffer  bool
+	}{
+		{
+			name:         "accepts connection settings",
+			endpoint:     "ws://localhost:3001/v1/opamp",
+			capabilities: protobufs.AgentCapabilities_AcceptsOpAMPConnectionSettings,
+			expectOffer:  true,
+		},
+		{
+			name:         "enrolled with a token",
+			endpoint:     "ws://localhost:3001/v1/opamp",
+			capabilities: protobufs.AgentCapabilities_AcceptsOpAMPConnectionSettings,
+			credential:   &model.AgentCredential{AgentID: "agent", TokenID: "token"},
+			expectOffer:  true,
+		},
+		{
+			name:         "already issued",
+			endpoint:     "ws://localhost:3001/v1/opamp",
+			capabilities: protobufs.AgentCapabilities_AcceptsOpAMPConnectionSettings,
+			credential:   issued,
+		},
+		{
+			name:         "doesn't accept connection settings",
+			endpoint:     "ws://localhost:3001/v1/opamp",
+			capabilities: protobufs.AgentCapabilities_ReportsStatus,
+		},
+		{
+			name:         "no endpoint",
+			capabilities: protobufs.AgentCapabilities_AcceptsOpAMPConnectionSettings,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			manager := &mocks.Manager{}
+			manager.On("AgentCredential", mock.Anything, "agent").Return(test.credential, nil)
+			manager.On("IssueAgentCredential", mock.Anything, "agent").Return("secret", nil)
+
+			s := testServer(manager)
+			s.endpoint = test.endpoint
+
+			response := &protobufs.ServerToAgent{Capabilities: capabilities}
+			message := &protobufs.AgentToServer{InstanceUid: "agent", Capabilities: test.capabilities}
+			require.NoError(t, s.offerAgentCredential(context.Background(), "agent", message, response))
+
+			if !test.expectOffer {
+				require.Nil(t, response.ConnectionSettings)
+				require.Equal(t, capabilities, response.Capabilities)
+				manager.AssertNotCalled(t, "IssueAgentCredential", mock.Anything, mock.Anything)
+				return
+			}
+			require.Equal(t, capabilities|protobufs.ServerCapabilities_OffersConnectionSettings, response.Capabilities)
+			require.Equal(t, connectionSettings(test.endpoint, "secret", nil), response.ConnectionSettings)
+		})
+	}
+}
+
+func TestOfferAgentCertificate(t *testing.T) {
+	now := time.Now()
+	issuedAt := now.Add(-time.Hour)
+	fresh := now.Add(2 * time.Hour)
+	due := now.Add(10 * time.Minute)
+	certificate := &agentca.Certificate{
+		CertificatePEM: []byte("cert"),
+		PrivateKeyPEM:  []byte("key"),
+		CAPEM:          []byte("ca"),
+		Serial:         "b2",
+	}
+
+	tests := []struct {
+		name        string
+		credential  *model.AgentCredential
+		expectOffer bool
+	}{
+		{
+			name:        "not issued",
+			expectOffer: true,
+		},
+		{
+			name:        "credential without certificate",
+			credential:  &model.AgentCredential{AgentID: "agent", Hash: "hash"},
+			expectOffer: true,
+		},
+		{
+			name: "certificate issued",
+			credential: &model.AgentCredential{AgentID: "agent", Hash: "hash", CertificateSerial: "a1",
+				CertificateIssuedAt: &issuedAt, CertificateExpiresAt: &fresh},
+		},
+		{
+			name: "certificate due for renewal",
+			credential: &model.AgentCredential{AgentID: "agent", Hash: "hash", CertificateSerial: "a1",
+				CertificateIssuedAt: &issuedAt, CertificateExpiresAt: &due},
+			expectOffer: true,
+		},
+		{
+			name:       "certificate revoked",
+			credential: &model.AgentCredential{AgentID: "agent", Hash: "hash", CertificateRequired: true},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			manager := &mocks.Manager{}
+			manager.On("AgentCredential", mock.Anything, "agent").Return(test.credential, nil)
+			manager.On("IssueAgentCredential", mock.Anything, "agent").Return("secret", nil)
+			manager.On("IssueAgentCertificate", mock.Anything, "agent").Return(certificate, nil)
+
+			s := testServer(manager)
+			s.endpoint = "wss://localhost:3001/v1/opamp"
+			s.agentCertificates = true
+
+			response := &protobufs.ServerToAgent{Capabilities: capabilities}
+			message := &protobufs.AgentToServer{InstanceUid: "agent", Capabilities: protobufs.AgentCapabilities_AcceptsOpAMPConnectionSettings}
+			require.NoError(t, s.offerAgentCredential(context.Background(), "agent", message, response))
+
+			if !test.expectOffer {
+				require.Nil(t, response.ConnectionSettings)
+				manager.AssertNotCalled(t, "IssueAgentCertificate", mock.Anything, mock.Anything)
+				return
+			}
+			require.Equal(t, connectionSettings(s.endpoint, "secret", certificate), response.ConnectionSettings)
+		})
+	}
+}
+
+func TestConnectionSettings(t *testing.T) {
+	settings := connectionSettings("ws://localhost:3001/v1/opamp", "secret", nil)
+	require.Equal(t, "ws://localhost:3001/v1/opamp", settings.Opamp.DestinationEndpoint)
+	require.Equal(t, []*protobufs.Header{{Key: "Authorization", Value: "Secret-Key secret"}}, settings.Opamp.Headers.Headers)
+	require.NotEmpty(t, settings.Hash)
+
+	// the hash changes with the credential
+	require.NotEqual(t, settings.Hash, connectionSettings("ws://localhost:3001/v1/opamp", "other", nil).Hash)
+
+	// the secret parses as the secret key of the agent
+	headers := parseAgentHeaders(&http.Request{Header: http.Header{"Authorization": []string{settings.Opamp.Headers.Headers[0].Value}}})
+	require.Equal(t, "secret", headers.secretKey)
+
+	// the certificate is included and changes the hash
+	certificate := &agentca.Certificate{CertificatePEM: []byte("cert"), PrivateKeyPEM: []byte("key"), CAPEM: []byte("ca")}
+	withCertificate := connectionSettings("ws://localhost:3001/v1/opamp", "secret", certificate)
+	require.Equal(t, &protobufs.TLSCertificate{PublicKey: []byte("cert"), PrivateKey: []byte("key"), CaPublicKey: []byte("ca")}, withCertificate.Opamp.Certificate)
+	require.NotEqual(t, settings.Hash, withCertificate.Hash)
+}
+
+func TestRotateAgentCredential(t *testing.T) {
+	manager := &mocks.Manager{}
+	manager.On("IssueAgentCredential", mock.Anything, "accepts").Return("secret", nil)
+
+	s := testServer(manager)
+	s.endpoint = "ws://localhost:3001/v1/opamp"
+
+	accepts := &model.Agent{ID: "accepts", State: encodeState(&agentState{
+		Status: protobufs.AgentToServer{Capabilities: protobufs.AgentCapabilities_AcceptsOpAMPConnectionSettings},
+	})}
+	response := &protobufs.ServerToAgent{}
+	s.rotateAgentCredential(context.Background(), accepts, response)
+	require.Equal(t, connectionSettings(s.endpoint, "secret", nil), response.ConnectionSettings)
+
+	unsupported := &model.Agent{ID: "unsupported", State: encodeState(&agentState{})}
+	response = &protobufs.ServerToAgent{}
+	s.rotateAgentCredential(context.Background(), unsupported, response)
+	require.Nil(t, response.ConnectionSettings)
+	manager.AssertNotCalled(t, "IssueAgentCredential", mock.Anything, "unsupported")
+}
@@ -25,7 +25,9 @@ type connections struct {
 	locks       map[opamp.Connection]*sync.Mutex
 	connections map[opamp.Connection]string
 	agents      map[string]opamp.Connection
-	mtx         sync.RWMutex
+	// maps connection => agentID for connections from other hosts using the ID of a connected agent
+	duplicates map[opamp.Connection]string
+	mtx        sync.RWMutex
 }
 
 func newConnections() *connections {
@@ -33,23 +35,36 @@ func newConnections() *connections {
 		locks:       make(map[opamp.Connection]*sync.Mutex),
 		connections: make(map[opamp.Connection]string),
 		agents:      make(map[string]opamp.Connection),
+		duplicates:  make(map[opamp.Connection]string),
 	}
 }
 
-func (c *connections) connect(conn opamp.Connection, agentID string) {
+// connect associates the connection with the agentID and returns true if it wasn't already associated
+func (c *connections) connect(conn opamp.Connection, agentID string) bool {
 	c.mtx.Lock()
 	defer c.mtx.Unlock()
-	c.locks[conn] = &sync.Mutex{}
+	if c.agents[agentID] == conn && c.connections[conn] == agentID {
+		return false
+	}
+	if previous, ok := c.agents[agentID]; ok && previous != conn {
+		// forget the previous connection so that closing it doesn't disconnect the agent
+		delete(c.locks, previous)
+		delete(c.connections, previous)
+	}
+	if _, ok := c.locks[conn]; !ok {
+		c.locks[conn] = &sync.Mutex{}
+	}
 	c.connections[conn] = agentID
 	c.agents[agentID] = conn
+	return true
 }
 
 func (c *connections) disconnect(conn opamp.Connection) {
 	c.mtx.Lock()
 	defer c.mtx.Unlock()
+	delete(c.locks, conn)
 	agentID, ok := c.connections[conn]
 	if ok {
-		delete(c.locks, conn)
 		delete(c.connections, conn)
 		delete(c.agents, agentID)
 	}
@@ -88,3 +103,44 @@ func (c *connections) agentIDs() []string {
 	}
 	return ids
 }
+
+// addDuplicate associates the connection with the agentID of an agent connected from another host without replacing
+// the connection of the agent
+func (c *connections) addDuplicate(conn opamp.Connection, agentID string) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if _, ok := c.locks[conn]; !ok {
+		c.locks[conn] = &sync.Mutex{}
+	}
+	c.duplicates[conn] = agentID
+}
+
+// removeDuplicate removes the duplicate connection and returns the agentID it was using and true if it was a duplicate.
+// The connection can still be used to send messages until it is disconnected.
+func (c *connections) removeDuplicate(conn opamp.Connection) (string, bool) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	agentID, ok := c.duplicates[conn]
+	delete(c.duplicates, conn)
+	return agentID, ok
+}
+
+// duplicateAgentID returns the agentID used by the duplicate connection or "" if the connection is not a duplicate
+func (c *connections) duplicateAgentID(conn opamp.Connection) string {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+	return c.duplicates[conn]
+}
+
+// duplicateConnections returns the duplicate connections using the specified agentID
+func (c *connections) duplicateConnections(agentID string) []opamp.Connection {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+	conns := []opamp.Connection{}
+	for conn, id := range c.duplicates {
+		if id == agentID {
+			conns = append(conns, conn)
+		}
+	}
+	return conns
+}
@@ -81,3 +81,41 @@ func TestConnected(t *testing.T) {
 	require.Equal(t, []string{"1"}, c.agentIDs(), "should have agentID 1 connected")
 	require.True(t, c.connected("1"), "should have agentID 1 connected")
 }
+
+func TestReconnect(t *testing.T) {
+	c := newConnections()
+	previous := &testConnection{agentID: "1", addr: testAddr{address: "10.0.0.1:51000"}}
+	conn := &testConnection{agentID: "1", addr: testAddr{address: "10.0.0.1:52000"}}
+	c.connect(previous, "1")
+	c.connect(conn, "1")
+	require.Equal(t, "", c.agentID(previous), "should forget the previous connection")
+
+	c.disconnect(previous)
+	require.Equal(t, conn, c.connection("1"), "closing the previous connection should not disconnect the agent")
+}
+
+func TestDuplicates(t *testing.T) {
+	c := newConnections()
+	conn := &testConnection{agentID: "1", addr: testAddr{address: "10.0.0.1:51000"}}
+	duplicate := &testConnection{agentID: "1", addr: testAddr{address: "10.0.0.2:51000"}}
+	c.connect(conn, "1")
+	c.addDuplicate(duplicate, "1")
+
+	require.Equal(t, conn, c.connection("1"), "duplicate should not replace the connection")
+	require.Equal(t, "1", c.duplicateAgentID(duplicate))
+	require.Equal(t, []opamp.Connection{duplicate}, c.duplicateConnections("1"))
+	require.NotNil(t, c.sendLock(duplicate), "should be able to send to the duplicate")
+
+	agentID, ok := c.removeDuplicate(duplicate)
+	require.True(t, ok)
+	require.Equal(t, "1", agentID)
+	require.Equal(t, "", c.duplicateAgentID(duplicate))
+	require.Empty(t, c.duplicateConnections("1"))
+	require.NotNil(t, c.sendLock(duplicate), "should be able to send to the removed duplicate until it is disconnected")
+
+	c.disconnect(duplicate)
+	require.Nil(t, c.sendLock(duplicate))
+
+	_, ok = c.removeDuplicate(duplicate)
+	require.False(t, ok)
+}
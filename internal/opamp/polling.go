@@ -0,0 +1,299 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opamp
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/open-telemetry/opamp-go/protobufs"
+	opamp "github.com/open-telemetry/opamp-go/server/types"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	headerContentType   = "Content-Type"
+	contentTypeProtobuf = "application/x-protobuf"
+
+	// PollingTimeout is the time after the last poll that an agent using the HTTP transport is considered disconnected.
+	// It is three times the default polling interval of OpAMP HTTP clients.
+	PollingTimeout = 90 * time.Second
+)
+
+// pollingAddr is the remote address of the last poll from an agent using the HTTP transport
+type pollingAddr string
+
+var _ net.Addr = (*pollingAddr)(nil)
+
+func (a pollingAddr) Network() string {
+	return "tcp"
+}
+
+func (a pollingAddr) String() string {
+	return string(a)
+}
+
+// pollingConnection is the opamp.Connection of an agent using the HTTP transport. It lasts across polls so that the
+// agent remains connected between polls. Messages sent to the agent are queued until the next poll.
+type pollingConnection struct {
+	agentID    string
+	key        string
+	remoteAddr pollingAddr
+	lastPoll   time.Time
+	pending    *protobufs.ServerToAgent
+	mtx        sync.Mutex
+}
+
+var _ opamp.Connection = (*pollingConnection)(nil)
+
+// RemoteAddr returns the remote address of the last poll
+func (c *pollingConnection) RemoteAddr() net.Addr {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.remoteAddr
+}
+
+// Send queues the message to be sent in the response to the next poll. Messages sent before the next poll are merged
+// with the most recent message taking precedence.
+func (c *pollingConnection) Send(_ context.Context, message *protobufs.ServerToAgent) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.pending == nil {
+		c.pending = &protobufs.ServerToAgent{}
+	}
+	mergeServerToAgent(c.pending, message)
+	return nil
+}
+
+// poll records a poll from the remote address
+func (c *pollingConnection) poll(remoteAddr string, now time.Time) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.remoteAddr = pollingAddr(remoteAddr)
+	c.lastPoll = now
+}
+
+// takePending returns the queued message, if any, and clears the queue
+func (c *pollingConnection) takePending() *protobufs.ServerToAgent {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	pending := c.pending
+	c.pending = nil
+	return pending
+}
+
+func (c *pollingConnection) expired(now time.Time, timeout time.Duration) bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return now.Sub(c.lastPoll) > timeout
+}
+
+// mergeServerToAgent copies the fields that are set in src to dst
+func mergeServerToAgent(dst, src *protobufs.ServerToAgent) {
+	if src.InstanceUid != "" {
+		dst.InstanceUid = src.InstanceUid
+	}
+	if src.ErrorResponse != nil {
+		dst.ErrorResponse = src.ErrorResponse
+	}
+	if src.RemoteConfig != nil {
+		dst.RemoteConfig = src.RemoteConfig
+	}
+	if src.ConnectionSettings != nil {
+		dst.ConnectionSettings = src.ConnectionSettings
+	}
+	if src.PackagesAvailable != nil {
+		dst.PackagesAvailable = src.PackagesAvailable
+	}
+	if src.AgentIdentification != nil {
+		dst.AgentIdentification = src.AgentIdentification
+	}
+	if src.Command != nil {
+		dst.Command = src.Command
+	}
+	dst.Flags |= src.Flags
+	dst.Capabilities |= src.Capabilities
+}
+
+// addPending adds the fields of the pending message that are not already set in the response
+func addPending(response, pending *protobufs.ServerToAgent) {
+	if pending == nil {
+		return
+	}
+	if response.ErrorResponse == nil {
+		response.ErrorResponse = pending.ErrorResponse
+	}
+	if response.RemoteConfig == nil {
+		response.RemoteConfig = pending.RemoteConfig
+	}
+	if response.ConnectionSettings == nil {
+		response.ConnectionSettings = pending.ConnectionSettings
+	}
+	if response.PackagesAvailable == nil {
+		response.PackagesAvailable = pending.PackagesAvailable
+	}
+	if response.AgentIdentification == nil {
+		response.AgentIdentification = pending.AgentIdentification
+	}
+	if response.Command == nil {
+		response.Command = pending.Command
+	}
+	response.Flags |= pending.Flags
+}
+
+// ----------------------------------------------------------------------
+
+// pollers are the connections of agents using the HTTP transport. Each connection is identified by the agent ID and
+// the host of the remote address so that hosts polling with the same agent ID have separate connections.
+type pollers struct {
+	connections map[string]*pollingConnection
+	mtx         sync.Mutex
+}
+
+func newPollers() *pollers {
+	return &pollers{
+		connections: map[string]*pollingConnection{},
+	}
+}
+
+// poll returns the connection for the agent, creating it if this is the first poll since the agent connected
+func (p *pollers) poll(agentID, remoteAddr string, now time.Time) *pollingConnection {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	key := pollerKey(agentID, remoteAddr)
+	conn, ok := p.connections[key]
+	if !ok {
+		conn = &pollingConnection{agentID: agentID, key: key}
+		p.connections[key] = conn
+	}
+	conn.poll(remoteAddr, now)
+	return conn
+}
+
+// remove removes the connection if it is still the connection for its agent
+func (p *pollers) remove(conn *pollingConnection) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if p.connections[conn.key] == conn {
+		delete(p.connections, conn.key)
+	}
+}
+
+// pollerKey returns the key of the connection of the agent polling from the remote address. The port is ignored
+// because each poll may use a new TCP connection.
+func pollerKey(agentID, remoteAddr string) string {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	return agentID + "@" + host
+}
+
+// expired returns the connections of agents that have not polled within the timeout
+func (p *pollers) expired(now time.Time, timeout time.Duration) []*pollingConnection {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	var expired []*pollingConnection
+	for _, conn := range p.connections {
+		if conn.expired(now, timeout) {
+			expired = append(expired, conn)
+		}
+	}
+	return expired
+}
+
+// ----------------------------------------------------------------------
+
+// handler returns an http.HandlerFunc that handles plain HTTP requests from agents using the HTTP transport and passes
+// all other requests to the websocket handler
+func (s *opampServer) handler(websocket http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, request *http.Request) {
+		if request.Header.Get(headerContentType) == contentTypeProtobuf {
+			s.handlePoll(w, request)
+			return
+		}
+		websocket(s.sockets.track(w), request)
+	}
+}
+
+// handlePoll handles a single AgentToServer message from an agent using the HTTP transport and responds with the
+// ServerToAgent message, including any messages queued since the last poll
+func (s *opampServer) handlePoll(w http.ResponseWriter, request *http.Request) {
+	connectionResponse := s.OnConnecting(request)
+	if !connectionResponse.Accept {
+		for k, v := range connectionResponse.HTTPResponseHeader {
+			w.Header().Set(k, v)
+		}
+		w.WriteHeader(connectionResponse.HTTPStatusCode)
+		return
+	}
+
+	body, err := io.ReadAll(request.Body)
+	if err != nil {
+		s.logger.Error("unable to read OpAMP HTTP request", zap.Error(err))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var message protobufs.AgentToServer
+	if err := proto.Unmarshal(body, &message); err != nil || message.InstanceUid == "" {
+		s.logger.Error("unable to decode OpAMP HTTP request", zap.Error(err))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	conn := s.pollers.poll(message.InstanceUid, request.RemoteAddr, time.Now())
+	response := s.OnMessage(conn, &message)
+	addPending(response, conn.takePending())
+
+	data, err := proto.Marshal(response)
+	if err != nil {
+		s.logger.Error("unable to encode OpAMP HTTP response", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set(headerContentType, contentTypeProtobuf)
+	if _, err := w.Write(data); err != nil {
+		s.logger.Error("unable to write OpAMP HTTP response", zap.Error(err))
+	}
+}
+
+// expirePollers disconnects agents using the HTTP transport that have not polled within the polling timeout
+func (s *opampServer) expirePollers(ctx context.Context) {
+	ticker := time.NewTicker(s.pollingTimeout / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.disconnectExpiredPollers(time.Now())
+		}
+	}
+}
+
+func (s *opampServer) disconnectExpiredPollers(now time.Time) {
+	for _, conn := range s.pollers.expired(now, s.pollingTimeout) {
+		s.logger.Info("OpAMP agent stopped polling", zap.String("agentID", conn.agentID))
+		s.OnConnectionClose(conn)
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opamp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/open-telemetry/opamp-go/protobufs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPollingConnectionSend(t *testing.T) {
+	conn := &pollingConnection{agentID: "agent"}
+	conn.poll("127.0.0.1:1234", time.Now())
+	require.Equal(t, "127.0.0.1:1234", conn.RemoteAddr().String())
+	require.Nil(t, conn.takePending())
+
+	restart := &protobufs.ServerToAgentCommand{Type: protobufs.ServerToAgentCommand_Restart}
+	first := &protobufs.AgentRemoteConfig{ConfigHash: []byte("first")}
+	second := &protobufs.AgentRemoteConfig{ConfigHash: []byte("second")}
+
+	require.NoError(t, conn.Send(context.Background(), &protobufs.ServerToAgent{RemoteConfig: first, Command: restart}))
+	require.NoError(t, conn.Send(context.Background(), &protobufs.ServerToAgent{RemoteConfig: second, Flags: protobufs.ServerToAgent_ReportFullState}))
+
+	pending := conn.takePending()
+	require.Equal(t, second, pending.RemoteConfig)
+	require.Equal(t, restart, pending.Command)
+	require.Equal(t, protobufs.ServerToAgent_ReportFullState, pending.Flags)
+	require.Nil(t, conn.takePending())
+}
+
+func TestAddPending(t *testing.T) {
+	restart := &protobufs.ServerToAgentCommand{Type: protobufs.ServerToAgentCommand_Restart}
+	current := &protobufs.AgentRemoteConfig{ConfigHash: []byte("current")}
+	queued := &protobufs.AgentRemoteConfig{ConfigHash: []byte("queued")}
+	packages := &protobufs.PackagesAvailable{AllPackagesHash: []byte("v1.6.0")}
+
+	tests := []struct {
+		name     string
+		response *protobufs.ServerToAgent
+		pending  *protobufs.ServerToAgent
+		expect   *protobufs.ServerToAgent
+	}{
+		{
+			name:     "nothing pending",
+			response: &protobufs.ServerToAgent{InstanceUid: "agent", RemoteConfig: current},
+			expect:   &protobufs.ServerToAgent{InstanceUid: "agent", RemoteConfig: current},
+		},
+		{
+			name:     "response takes precedence",
+			response: &protobufs.ServerToAgent{InstanceUid: "agent", RemoteConfig: current},
+			pending:  &protobufs.ServerToAgent{RemoteConfig: queued, PackagesAvailable: packages, Command: restart},
+			expect:   &protobufs.ServerToAgent{InstanceUid: "agent", RemoteConfig: current, PackagesAvailable: packages, Command: restart},
+		},
+		{
+			name:     "pending fills response",
+			response: &protobufs.ServerToAgent{InstanceUid: "agent"},
+			pending:  &protobufs.ServerToAgent{RemoteConfig: queued, Flags: protobufs.ServerToAgent_ReportFullState},
+			expect:   &protobufs.ServerToAgent{InstanceUid: "agent", RemoteConfig: queued, Flags: protobufs.ServerToAgent_ReportFullState},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			addPending(test.response, test.pending)
+			require.Equal(t, test.expect.InstanceUid, test.response.InstanceUid)
+			require.Equal(t, test.expect.RemoteConfig, test.response.RemoteConfig)
+			require.Equal(t, test.expect.PackagesAvailable, test.response.PackagesAvailable)
+			require.Equal(t, test.expect.Command, test.response.Command)
+			require.Equal(t, test.expect.Flags, test.response.Flags)
+		})
+	}
+}
+
+func TestPollers(t *testing.T) {
+	p := newPollers()
+	now := time.Now()
+
+	a := p.poll("a", "127.0.0.1:1", now)
+	b := p.poll("b", "127.0.0.1:2", now.Add(-time.Minute))
+	require.Same(t, a, p.poll("a", "127.0.0.1:3", now))
+	require.Equal(t, "127.0.0.1:3", a.RemoteAddr().String())
+	require.NotSame(t, a, p.poll("a", "127.0.0.2:1", now), "hosts polling with the same agent ID should have separate connections")
+
+	require.Equal(t, []*pollingConnection{b}, p.expired(now, 30*time.Second))
+
+	p.remove(b)
+	require.Empty(t, p.expired(now, 30*time.Second))
+	require.NotSame(t, b, p.poll("b", "127.0.0.1:2", now))
+}
@@ -0,0 +1,84 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opamp
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"sync"
+)
+
+// sockets records the network connections of agents using the websocket transport by remote address so that the
+// server can close them. opamp.Connection does not provide a way to close the connection.
+type sockets struct {
+	conns map[string]net.Conn
+	mtx   sync.Mutex
+}
+
+func newSockets() *sockets {
+	return &sockets{
+		conns: make(map[string]net.Conn),
+	}
+}
+
+// track returns an http.ResponseWriter that records the network connection when the websocket upgrade hijacks it
+func (s *sockets) track(w http.ResponseWriter) http.ResponseWriter {
+	if _, ok := w.(http.Hijacker); !ok {
+		return w
+	}
+	return &trackingResponseWriter{ResponseWriter: w, sockets: s}
+}
+
+func (s *sockets) add(conn net.Conn) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.conns[conn.RemoteAddr().String()] = conn
+}
+
+// remove forgets the network connection with the remote address without closing it
+func (s *sockets) remove(remoteAddr string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	delete(s.conns, remoteAddr)
+}
+
+// close closes the network connection with the remote address and returns true if it was found
+func (s *sockets) close(remoteAddr string) bool {
+	s.mtx.Lock()
+	conn, ok := s.conns[remoteAddr]
+	delete(s.conns, remoteAddr)
+	s.mtx.Unlock()
+
+	if !ok {
+		return false
+	}
+	_ = conn.Close()
+	return true
+}
+
+type trackingResponseWriter struct {
+	http.ResponseWriter
+	sockets *sockets
+}
+
+// Hijack implements http.Hijacker and records the hijacked connection
+func (w *trackingResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := w.ResponseWriter.(http.Hijacker).Hijack()
+	if err == nil {
+		w.sockets.add(conn)
+	}
+	return conn, rw, err
+}
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/hashicorp/go-multierror"
@@ -26,6 +27,8 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/observiq/bindplane-op/internal/server"
+	"github.com/observiq/bindplane-op/internal/server/auth"
+	"github.com/observiq/bindplane-op/internal/server/sessions"
 	"github.com/observiq/bindplane-op/internal/store"
 	"github.com/observiq/bindplane-op/internal/store/search"
 	"github.com/observiq/bindplane-op/internal/version"
@@ -46,6 +49,26 @@ func AddRestRoutes(router gin.IRouter, bindplane server.BindPlane) {
 	router.POST("/agents/:id/version", func(c *gin.Context) { upgradeAgent(c, bindplane) })
 	router.PATCH("/agents/version", func(c *gin.Context) { upgradeAgents(c, bindplane) })
 	router.GET("/agents/:id/configuration", func(c *gin.Context) { getAgentConfiguration(c, bindplane) })
+	router.PUT("/agents/:id/credentials/rotate", func(c *gin.Context) { rotateAgentCredential(c, bindplane) })
+	router.PUT("/agents/:id/conflict/resolve", func(c *gin.Context) { resolveAgentConflict(c, bindplane) })
+	router.PUT("/agents/:id/certificate/revoke", func(c *gin.Context) { revokeAgentCertificate(c, bindplane) })
+	router.POST("/agents/approve", func(c *gin.Context) { approveAgents(c, bindplane) })
+
+	router.GET("/agent-deny-rules", func(c *gin.Context) { agentDenyRules(c, bindplane) })
+	router.POST("/agent-deny-rules", func(c *gin.Context) { createAgentDenyRule(c, bindplane) })
+	router.DELETE("/agent-deny-rules/:id", func(c *gin.Context) { deleteAgentDenyRule(c, bindplane) })
+
+	// sessions of other users are only visible to admins
+	sessionRoutes := router.Group("/sessions", auth.RequireAdmin())
+	sessionRoutes.GET("", func(c *gin.Context) { userSessions(c, bindplane) })
+	sessionRoutes.DELETE("/:id", func(c *gin.Context) { deleteUserSession(c, bindplane) })
+	sessionRoutes.POST("/revoke", func(c *gin.Context) { revokeUserSessions(c, bindplane) })
+
+	router.GET("/gitops/sync", func(c *gin.Context) { gitOpsSync(c, bindplane) })
+
+	router.GET("/enrollment-tokens", func(c *gin.Context) { enrollmentTokens(c, bindplane) })
+	router.POST("/enrollment-tokens", func(c *gin.Context) { createEnrollmentToken(c, bindplane) })
+	router.DELETE("/enrollment-tokens/:id", func(c *gin.Context) { deleteEnrollmentToken(c, bindplane) })
 
 	router.GET("/agent-versions", func(c *gin.Context) { agentVersions(c, bindplane) })
 	router.GET("/agent-versions/:name", func(c *gin.Context) { agentVersion(c, bindplane) })
@@ -55,95 +78,76 @@ func AddRestRoutes(router gin.IRouter, bindplane server.BindPlane) {
 
 	router.GET("/configurations", func(c *gin.Context) { configurations(c, bindplane) })
 	router.GET("/configurations/:name", func(c *gin.Context) { configuration(c, bindplane) })
+	router.GET("/configurations/:name/usage", func(c *gin.Context) { configurationUsage(c, bindplane) })
 	router.DELETE("/configurations/:name", func(c *gin.Context) { deleteConfiguration(c, bindplane) })
 	router.POST("/configurations/:name/copy", func(c *gin.Context) { copyConfig(c, bindplane) })
 
 	router.GET("/sources", func(c *gin.Context) { sources(c, bindplane) })
 	router.GET("/sources/:name", func(c *gin.Context) { source(c, bindplane) })
+	router.GET("/sources/:name/usage", func(c *gin.Context) { sourceUsage(c, bindplane) })
 	router.DELETE("/sources/:name", func(c *gin.Context) { deleteSource(c, bindplane) })
 
 	router.GET("/source-types", func(c *gin.Context) { sourceTypes(c, bindplane) })
 	router.GET("/source-types/:name", func(c *gin.Context) { sourceType(c, bindplane) })
+	router.GET("/source-types/:name/usage", func(c *gin.Context) { sourceTypeUsage(c, bindplane) })
 	router.DELETE("/source-types/:name", func(c *gin.Context) { deleteSourceType(c, bindplane) })
 
 	router.GET("/processors", func(c *gin.Context) { processors(c, bindplane) })
 	router.GET("/processors/:name", func(c *gin.Context) { processor(c, bindplane) })
+	router.GET("/processors/:name/usage", func(c *gin.Context) { processorUsage(c, bindplane) })
 	router.DELETE("/processors/:name", func(c *gin.Context) { deleteProcessor(c, bindplane) })
 
 	router.GET("/processor-types", func(c *gin.Context) { processorTypes(c, bindplane) })
 	router.GET("/processor-types/:name", func(c *gin.Context) { processorType(c, bindplane) })
+	router.GET("/processor-types/:name/usage", func(c *gin.Context) { processorTypeUsage(c, bindplane) })
 	router.DELETE("/processor-types/:name", func(c *gin.Context) { deleteProcessorType(c, bindplane) })
 
 	router.GET("/destinations", func(c *gin.Context) { destinations(c, bindplane) })
 	router.GET("/destinations/:name", func(c *gin.Context) { destination(c, bindplane) })
+	router.GET("/destinations/:name/usage", func(c *gin.Context) { destinationUsage(c, bindplane) })
 	router.DELETE("/destinations/:name", func(c *gin.Context) { deleteDestination(c, bindplane) })
 
 	router.GET("/destination-types", func(c *gin.Context) { destinationTypes(c, bindplane) })
 	router.GET("/destination-types/:name", func(c *gin.Context) { destinationType(c, bindplane) })
+	router.GET("/destination-types/:name/usage", func(c *gin.Context) { destinationTypeUsage(c, bindplane) })
 	router.DELETE("/destination-types/:name", func(c *gin.Context) { deleteDestinationType(c, bindplane) })
 
 	router.POST("/apply", func(c *gin.Context) { applyResources(c, bindplane) })
 	router.POST("/delete", func(c *gin.Context) { deleteResources(c, bindplane) })
 
+	router.GET("/watch", func(c *gin.Context) { watch(c, bindplane) })
+
 	router.GET("/version", func(c *gin.Context) { bindplaneVersion(c) })
 }
 
 // @Summary List agents
 // @Produce json
 // @Router /agents [get]
+// @Param	selector	query	string	false "label selector used to filter the results, e.g. env=prod"
+// @Param	query	query	string	false "search query used to filter the results"
+// @Param	sort	query	string	false "field used to sort the results, prefixed with - to sort descending"
+// @Param	limit	query	int	false "maximum number of results to return"
+// @Param	offset	query	int	false "number of results to skip"
+// @Param	continue	query	string	false "continue token returned with the previous page of results"
 // @Success 200 {object} model.AgentsResponse
+// @Failure 400 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 func agents(c *gin.Context, bindplane server.BindPlane) {
 	ctx, span := tracer.Start(c.Request.Context(), "rest/agents")
 	defer span.End()
 
-	options := []store.QueryOption{}
-
-	selectorString := c.DefaultQuery("selector", "")
-	selector, err := model.SelectorFromString(selectorString)
-	if err != nil {
-		handleErrorResponse(c, http.StatusBadRequest, err)
-		return
-	}
-	options = append(options, store.WithSelector(selector))
-
-	query := c.DefaultQuery("query", "")
-	if query != "" {
-		q := search.ParseQuery(query)
-		q.ReplaceVersionLatest(bindplane.Versions())
-		options = append(options, store.WithQuery(q))
-	}
-
-	offset := c.DefaultQuery("offset", "0")
-	offsetValue, err := strconv.Atoi(offset)
-	if err != nil {
-		handleErrorResponse(c, http.StatusBadRequest, fmt.Errorf("offset must be a number: %v", err))
-		return
-	}
-	options = append(options, store.WithOffset(offsetValue))
-
-	limit := c.DefaultQuery("limit", "0")
-	limitValue, err := strconv.Atoi(limit)
-	if err != nil {
-		handleErrorResponse(c, http.StatusBadRequest, fmt.Errorf("limit must be a number: %v", err))
+	options, ok := listOptions(c, bindplane)
+	if !ok {
 		return
 	}
-	options = append(options, store.WithLimit(limitValue))
-
-	sort := c.DefaultQuery("sort", "")
-	if sort != "" {
-		options = append(options, store.WithSort(sort))
-	}
 
 	agents, err := bindplane.Store().Agents(ctx, options...)
-	if err != nil {
-		handleErrorResponse(c, http.StatusInternalServerError, err)
-		return
+	if okResponse(c, err) {
+		c.JSON(http.StatusOK, model.AgentsResponse{
+			Agents:   agents,
+			Continue: continueToken(c, agents),
+		})
 	}
-
-	c.JSON(http.StatusOK, model.AgentsResponse{
-		Agents: agents,
-	})
 }
 
 // @Summary delete agents by ids
@@ -391,19 +395,220 @@ func patchAgentLabels(c *gin.Context, bindplane server.BindPlane) {
 	})
 }
 
-// @Summary TODO restart agent
+// @Summary Restart agent
 // @Produce json
 // @Router /agents/{id}/restart [put]
 // @Param 	id	path	string	true "the id of the agent"
+// @Success 202 "Restart requested"
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
 func restartAgent(c *gin.Context, bindplane server.BindPlane) {
+	ctx, span := tracer.Start(c.Request.Context(), "rest/restartAgent")
+	defer span.End()
+
+	id := c.Param("id")
+
+	agent, err := bindplane.Store().Agent(id)
+	switch {
+	case err != nil:
+		handleErrorResponse(c, http.StatusInternalServerError, err)
+		return
+
+	case agent == nil:
+		handleErrorResponse(c, http.StatusNotFound, store.ErrResourceMissing)
+		return
+	}
+
+	// the agent may be connected to another node, which will send the restart command
+	err = bindplane.Manager().SendAgentUpdates(ctx, agent, &server.AgentUpdates{Restart: true})
+	switch {
+	case errors.Is(err, server.ErrAgentNotConnected):
+		handleErrorResponse(c, http.StatusConflict, fmt.Errorf("agent %s is not connected", agent.ID))
+		return
+
+	case err != nil:
+		handleErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
+
+// @Summary Rotate agent credential
+// @Description Issues a new credential to an agent that accepts OpAMP connection settings. The previous credential is accepted until the agent reconnects with the new credential.
+// @Produce json
+// @Router /agents/{id}/credentials/rotate [put]
+// @Param 	id	path	string	true "the id of the agent"
+// @Success 202 "Rotation requested"
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+func rotateAgentCredential(c *gin.Context, bindplane server.BindPlane) {
+	ctx, span := tracer.Start(c.Request.Context(), "rest/rotateAgentCredential")
+	defer span.End()
+
+	id := c.Param("id")
+
+	agent, err := bindplane.Store().Agent(id)
+	switch {
+	case err != nil:
+		handleErrorResponse(c, http.StatusInternalServerError, err)
+		return
+
+	case agent == nil:
+		handleErrorResponse(c, http.StatusNotFound, store.ErrResourceMissing)
+		return
+	}
+
+	// the agent may be connected to another node, which will issue the new credential
+	err = bindplane.Manager().SendAgentUpdates(ctx, agent, &server.AgentUpdates{RotateCredential: true})
+	switch {
+	case errors.Is(err, server.ErrAgentNotConnected):
+		handleErrorResponse(c, http.StatusConflict, fmt.Errorf("agent %s is not connected", agent.ID))
+		return
+
+	case err != nil:
+		handleErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
+
+// @Summary Resolve agent conflict
+// @Description Assigns a new ID to each host that connected with the ID of the agent while it was already connected, e.g. virtual machines cloned from an image that includes the agent configuration.
+// @Produce json
+// @Router /agents/{id}/conflict/resolve [put]
+// @Param 	id	path	string	true "the id of the agent"
+// @Success 202 "New IDs assigned"
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+func resolveAgentConflict(c *gin.Context, bindplane server.BindPlane) {
+	ctx, span := tracer.Start(c.Request.Context(), "rest/resolveAgentConflict")
+	defer span.End()
+
+	id := c.Param("id")
+
+	agent, err := bindplane.Store().Agent(id)
+	switch {
+	case err != nil:
+		handleErrorResponse(c, http.StatusInternalServerError, err)
+		return
+
+	case agent == nil:
+		handleErrorResponse(c, http.StatusNotFound, store.ErrResourceMissing)
+		return
+	}
+
+	err = bindplane.Manager().ResolveAgentConflict(ctx, agent.ID)
+	switch {
+	case errors.Is(err, server.ErrNoAgentConflict):
+		handleErrorResponse(c, http.StatusConflict, fmt.Errorf("agent %s does not have a conflict", agent.ID))
+		return
+
+	case err != nil:
+		handleErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
+
+// @Summary Revoke agent certificate
+// @Description Revokes the client certificates issued to the agent by the agent CA. If the agent is connected, it is sent a new credential and certificate.
+// @Produce json
+// @Router /agents/{id}/certificate/revoke [put]
+// @Param 	id	path	string	true "the id of the agent"
+// @Success 202 "Certificate revoked"
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+func revokeAgentCertificate(c *gin.Context, bindplane server.BindPlane) {
+	ctx, span := tracer.Start(c.Request.Context(), "rest/revokeAgentCertificate")
+	defer span.End()
+
 	id := c.Param("id")
 
-	// TODO(andy): Do a restart
-	bindplane.Logger().Info("TODO Restart agent", zap.String("id", id))
+	agent, err := bindplane.Store().Agent(id)
+	switch {
+	case err != nil:
+		handleErrorResponse(c, http.StatusInternalServerError, err)
+		return
+
+	case agent == nil:
+		handleErrorResponse(c, http.StatusNotFound, store.ErrResourceMissing)
+		return
+	}
+
+	err = bindplane.Manager().RevokeAgentCertificate(ctx, agent.ID)
+	switch {
+	case errors.Is(err, server.ErrAgentCADisabled):
+		handleErrorResponse(c, http.StatusConflict, err)
+		return
+
+	case err != nil:
+		handleErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
 
 	c.Status(http.StatusAccepted)
 }
 
+// @Summary Approve pending agents
+// @Description Approves pending agents with the specified ids and pending agents matching the selector and query. Approved agents are sent their configuration.
+// @Produce json
+// @Router /agents/approve [post]
+// @Param body body model.PostApproveAgentsRequest true "ids, selector, and query of the agents to approve"
+// @Success 200 {object} model.ApproveAgentsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+func approveAgents(c *gin.Context, bindplane server.BindPlane) {
+	ctx, span := tracer.Start(c.Request.Context(), "rest/approveAgents")
+	defer span.End()
+
+	var req model.PostApproveAgentsRequest
+	if err := c.BindJSON(&req); err != nil {
+		handleErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+	if len(req.IDs) == 0 && req.Selector == "" && req.Query == "" {
+		handleErrorResponse(c, http.StatusBadRequest, errors.New("ids, selector, or query must be specified"))
+		return
+	}
+
+	ids := req.IDs
+	if req.Selector != "" || req.Query != "" {
+		selector, err := model.SelectorFromString(req.Selector)
+		if err != nil {
+			handleErrorResponse(c, http.StatusBadRequest, err)
+			return
+		}
+		options := []store.QueryOption{store.WithSelector(selector)}
+		if req.Query != "" {
+			q := search.ParseQuery(req.Query)
+			q.ReplaceVersionLatest(bindplane.Versions())
+			options = append(options, store.WithQuery(q))
+		}
+		agents, err := bindplane.Store().Agents(ctx, options...)
+		if err != nil {
+			handleErrorResponse(c, http.StatusInternalServerError, err)
+			return
+		}
+		for _, agent := range agents {
+			ids = append(ids, agent.ID)
+		}
+	}
+
+	approved, err := bindplane.Manager().ApproveAgents(ctx, ids)
+	if okResponse(c, err) {
+		c.JSON(http.StatusOK, model.ApproveAgentsResponse{
+			Approved: approved,
+		})
+	}
+}
+
 // @Summary Update multiple agents
 // @Router /agents/version [patch]
 // @Param body body model.PatchAgentVersionsRequest true "request body containing ids and version"
@@ -498,13 +703,26 @@ func upgradeAgent(c *gin.Context, bindplane server.BindPlane) {
 // @Summary List agent versions
 // @Produce json
 // @Router /agent-versions [get]
+// @Param	selector	query	string	false "label selector used to filter the results, e.g. env=prod"
+// @Param	query	query	string	false "search query used to filter the results"
+// @Param	sort	query	string	false "field used to sort the results, prefixed with - to sort descending"
+// @Param	limit	query	int	false "maximum number of results to return"
+// @Param	offset	query	int	false "number of results to skip"
+// @Param	continue	query	string	false "continue token returned with the previous page of results"
 // @Success 200 {object} model.AgentVersionsResponse
+// @Failure 400 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 func agentVersions(c *gin.Context, bindplane server.BindPlane) {
-	agentVersions, err := bindplane.Store().AgentVersions()
+	options, ok := listOptions(c, bindplane)
+	if !ok {
+		return
+	}
+
+	agentVersions, err := bindplane.Store().AgentVersions(options...)
 	if okResponse(c, err) {
 		c.JSON(http.StatusOK, model.AgentVersionsResponse{
 			AgentVersions: agentVersions,
+			Continue:      continueToken(c, agentVersions),
 		})
 	}
 }
@@ -543,21 +761,267 @@ func deleteAgentVersion(c *gin.Context, bindplane server.BindPlane) {
 
 // ----------------------------------------------------------------------
 
+// @Summary List enrollment tokens
+// @Produce json
+// @Router /enrollment-tokens [get]
+// @Success 200 {object} model.EnrollmentTokensResponse
+// @Failure 500 {object} ErrorResponse
+func enrollmentTokens(c *gin.Context, bindplane server.BindPlane) {
+	tokens, err := bindplane.Store().EnrollmentTokens(c.Request.Context())
+	if okResponse(c, err) {
+		redacted := make([]*model.EnrollmentToken, 0, len(tokens))
+		for _, token := range tokens {
+			redacted = append(redacted, token.Redacted())
+		}
+		c.JSON(http.StatusOK, model.EnrollmentTokensResponse{
+			EnrollmentTokens: redacted,
+		})
+	}
+}
+
+// @Summary Create enrollment token
+// @Description Creates a token used by agents to enroll. The token value is only returned when the token is created.
+// @Produce json
+// @Router /enrollment-tokens [post]
+// @Param body body model.PostEnrollmentTokenRequest true "labels, maximum uses, and expiration of the token"
+// @Success 201 {object} model.EnrollmentTokenResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+func createEnrollmentToken(c *gin.Context, bindplane server.BindPlane) {
+	var req model.PostEnrollmentTokenRequest
+	if err := c.BindJSON(&req); err != nil {
+		handleErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+
+	labels, err := model.LabelsFromMap(req.Labels)
+	if err != nil {
+		handleErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+	if req.MaxUses < 0 {
+		handleErrorResponse(c, http.StatusBadRequest, errors.New("maxUses must not be negative"))
+		return
+	}
+	var ttl time.Duration
+	if req.ExpiresIn != "" {
+		ttl, err = time.ParseDuration(req.ExpiresIn)
+		if err != nil || ttl <= 0 {
+			handleErrorResponse(c, http.StatusBadRequest, fmt.Errorf("invalid expiresIn %q, must be a positive duration like 24h", req.ExpiresIn))
+			return
+		}
+	}
+
+	token, value, err := model.NewEnrollmentToken(labels, req.MaxUses, ttl)
+	if err != nil {
+		handleErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+	if err := bindplane.Store().CreateEnrollmentToken(c.Request.Context(), token); err != nil {
+		handleErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, model.EnrollmentTokenResponse{
+		EnrollmentToken: token.Redacted(),
+		Token:           value,
+	})
+}
+
+// @Summary Delete enrollment token
+// @Description Deletes the token. Agents that have not been issued a credential can no longer connect with the token.
+// @Produce json
+// @Router /enrollment-tokens/{id} [delete]
+// @Param 	id	path	string	true "the id of the enrollment token to delete"
+// @Success 204	"Successful Delete, no content"
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+func deleteEnrollmentToken(c *gin.Context, bindplane server.BindPlane) {
+	token, err := bindplane.Store().DeleteEnrollmentToken(c.Request.Context(), c.Param("id"))
+	if okResource(c, token == nil, err) {
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// ----------------------------------------------------------------------
+
+// @Summary List agent deny rules
+// @Produce json
+// @Router /agent-deny-rules [get]
+// @Success 200 {object} model.AgentDenyRulesResponse
+// @Failure 500 {object} ErrorResponse
+func agentDenyRules(c *gin.Context, bindplane server.BindPlane) {
+	rules, err := bindplane.Store().AgentDenyRules(c.Request.Context())
+	if okResponse(c, err) {
+		c.JSON(http.StatusOK, model.AgentDenyRulesResponse{
+			Rules: rules,
+		})
+	}
+}
+
+// @Summary Create agent deny rule
+// @Description Denies agents with the specified id, hostname, or macAddress. Matching agents are removed and disconnected and cannot connect until the rule is deleted.
+// @Produce json
+// @Router /agent-deny-rules [post]
+// @Param body body model.PostAgentDenyRuleRequest true "field and value to deny"
+// @Success 201 {object} model.AgentDenyRuleResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+func createAgentDenyRule(c *gin.Context, bindplane server.BindPlane) {
+	ctx, span := tracer.Start(c.Request.Context(), "rest/createAgentDenyRule")
+	defer span.End()
+
+	var req model.PostAgentDenyRuleRequest
+	if err := c.BindJSON(&req); err != nil {
+		handleErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+
+	rule, err := model.NewAgentDenyRule(req.Field, req.Value, req.Reason)
+	if err != nil {
+		handleErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+
+	disconnected, err := bindplane.Manager().DenyAgents(ctx, rule)
+	if err != nil {
+		handleErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, model.AgentDenyRuleResponse{
+		Rule:         rule,
+		Disconnected: disconnected,
+	})
+}
+
+// @Summary Delete agent deny rule
+// @Description Deletes the rule. Agents matching the rule can connect again.
+// @Produce json
+// @Router /agent-deny-rules/{id} [delete]
+// @Param 	id	path	string	true "the id of the rule to delete, e.g. hostname:web-1"
+// @Success 204	"Successful Delete, no content"
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+func deleteAgentDenyRule(c *gin.Context, bindplane server.BindPlane) {
+	rule, err := bindplane.Store().DeleteAgentDenyRule(c.Request.Context(), c.Param("id"))
+	if okResource(c, rule == nil, err) {
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// ----------------------------------------------------------------------
+
+// @Summary Get GitOps sync status
+// @Description Returns the status of the most recent sync of resources from the GitOps repository.
+// @Produce json
+// @Router /gitops/sync [get]
+// @Success 200 {object} model.GitOpsSyncResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+func gitOpsSync(c *gin.Context, bindplane server.BindPlane) {
+	status, err := bindplane.Store().GitOpsSyncStatus(c.Request.Context())
+	if okResource(c, status == nil, err) {
+		c.JSON(http.StatusOK, model.GitOpsSyncResponse{
+			Status: status,
+		})
+	}
+}
+
+// ----------------------------------------------------------------------
+
+// @Summary List user sessions
+// @Description Lists the sessions of users logged in to the UI. Requires the admin role.
+// @Produce json
+// @Router /sessions [get]
+// @Success 200 {object} model.UserSessionsResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+func userSessions(c *gin.Context, bindplane server.BindPlane) {
+	records, err := sessions.Records(c.Request.Context(), bindplane)
+	if !okResponse(c, err) {
+		return
+	}
+	redacted := make([]*model.UserSession, 0, len(records))
+	for _, record := range records {
+		redacted = append(redacted, record.Redacted())
+	}
+	c.JSON(http.StatusOK, model.UserSessionsResponse{
+		Sessions: redacted,
+	})
+}
+
+// @Summary Revoke user session
+// @Description Revokes the session. The user must log in again. Requires the admin role.
+// @Produce json
+// @Router /sessions/{id} [delete]
+// @Param 	id	path	string	true "the id of the session to revoke"
+// @Success 204	"Successful Delete, no content"
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+func deleteUserSession(c *gin.Context, bindplane server.BindPlane) {
+	record, err := bindplane.Store().DeleteUserSessionRecord(c.Request.Context(), c.Param("id"))
+	if okResource(c, record == nil, err) {
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// @Summary Revoke the sessions of a user
+// @Description Revokes every session of the user. Requires the admin role.
+// @Produce json
+// @Router /sessions/revoke [post]
+// @Param body body model.PostRevokeUserSessionsRequest true "user whose sessions are revoked"
+// @Success 200 {object} model.RevokeUserSessionsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+func revokeUserSessions(c *gin.Context, bindplane server.BindPlane) {
+	var req model.PostRevokeUserSessionsRequest
+	if err := c.BindJSON(&req); err != nil {
+		handleErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+	if req.User == "" {
+		handleErrorResponse(c, http.StatusBadRequest, errors.New("user must be specified"))
+		return
+	}
+
+	revoked, err := sessions.RevokeUser(c.Request.Context(), bindplane, req.User)
+	if okResponse(c, err) {
+		c.JSON(http.StatusOK, model.RevokeUserSessionsResponse{
+			Revoked: revoked,
+		})
+	}
+}
+
+// ----------------------------------------------------------------------
+
 // @Summary List Configurations
 // @Produce json
 // @Router /configurations [get]
+// @Param	selector	query	string	false "label selector used to filter the results, e.g. env=prod"
+// @Param	query	query	string	false "search query used to filter the results"
+// @Param	sort	query	string	false "field used to sort the results, prefixed with - to sort descending"
+// @Param	limit	query	int	false "maximum number of results to return"
+// @Param	offset	query	int	false "number of results to skip"
+// @Param	continue	query	string	false "continue token returned with the previous page of results"
 // @Success 200 {object} model.ConfigurationsResponse
+// @Failure 400 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 func configurations(c *gin.Context, bindplane server.BindPlane) {
-	configs, err := bindplane.Store().Configurations()
-	if err != nil {
-		handleErrorResponse(c, http.StatusInternalServerError, err)
+	options, ok := listOptions(c, bindplane)
+	if !ok {
 		return
 	}
 
-	c.JSON(http.StatusOK, model.ConfigurationsResponse{
-		Configurations: configs,
-	})
+	configs, err := bindplane.Store().Configurations(options...)
+	if okResponse(c, err) {
+		c.JSON(http.StatusOK, model.ConfigurationsResponse{
+			Configurations: configs,
+			Continue:       continueToken(c, configs),
+		})
+	}
 }
 
 // @Summary Get configuration by name
@@ -588,6 +1052,21 @@ func configuration(c *gin.Context, bindplane server.BindPlane) {
 	})
 }
 
+// @Summary Get configuration usage
+// @Produce json
+// @Router /configurations/{name}/usage [get]
+// @Param 	name	path	string	true "the name of the configuration"
+// @Success 200 {object} model.ResourceUsageResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+func configurationUsage(c *gin.Context, bindplane server.BindPlane) {
+	name := c.Param("name")
+	config, err := bindplane.Store().Configuration(name)
+	if okResource(c, config == nil, err) {
+		resourceUsage(c, bindplane, config)
+	}
+}
+
 // @Summary Delete configuration by name
 // @Produce json
 // @Router /configurations/{name} [delete]
@@ -684,13 +1163,26 @@ func copyConfig(c *gin.Context, bindplane server.BindPlane) {
 // @Summary List sources
 // @Produce json
 // @Router /sources [get]
+// @Param	selector	query	string	false "label selector used to filter the results, e.g. env=prod"
+// @Param	query	query	string	false "search query used to filter the results"
+// @Param	sort	query	string	false "field used to sort the results, prefixed with - to sort descending"
+// @Param	limit	query	int	false "maximum number of results to return"
+// @Param	offset	query	int	false "number of results to skip"
+// @Param	continue	query	string	false "continue token returned with the previous page of results"
 // @Success 200 {object} model.SourcesResponse
+// @Failure 400 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 func sources(c *gin.Context, bindplane server.BindPlane) {
-	sources, err := bindplane.Store().Sources()
+	options, ok := listOptions(c, bindplane)
+	if !ok {
+		return
+	}
+
+	sources, err := bindplane.Store().Sources(options...)
 	if okResponse(c, err) {
 		c.JSON(http.StatusOK, model.SourcesResponse{
-			Sources: sources,
+			Sources:  sources,
+			Continue: continueToken(c, sources),
 		})
 	}
 }
@@ -712,6 +1204,21 @@ func source(c *gin.Context, bindplane server.BindPlane) {
 	}
 }
 
+// @Summary Get source usage
+// @Produce json
+// @Router /sources/{name}/usage [get]
+// @Param 	name	path	string	true "the name of the source"
+// @Success 200 {object} model.ResourceUsageResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+func sourceUsage(c *gin.Context, bindplane server.BindPlane) {
+	name := c.Param("name")
+	source, err := bindplane.Store().Source(name)
+	if okResource(c, source == nil, err) {
+		resourceUsage(c, bindplane, source)
+	}
+}
+
 // @Summary Delete source by name
 // @Produce json
 // @Router /sources/{name} [delete]
@@ -733,13 +1240,26 @@ func deleteSource(c *gin.Context, bindplane server.BindPlane) {
 // @Summary List source types
 // @Produce json
 // @Router /source-types [get]
+// @Param	selector	query	string	false "label selector used to filter the results, e.g. env=prod"
+// @Param	query	query	string	false "search query used to filter the results"
+// @Param	sort	query	string	false "field used to sort the results, prefixed with - to sort descending"
+// @Param	limit	query	int	false "maximum number of results to return"
+// @Param	offset	query	int	false "number of results to skip"
+// @Param	continue	query	string	false "continue token returned with the previous page of results"
 // @Success 200 {object} model.SourceTypesResponse
+// @Failure 400 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 func sourceTypes(c *gin.Context, bindplane server.BindPlane) {
-	sourceTypes, err := bindplane.Store().SourceTypes()
+	options, ok := listOptions(c, bindplane)
+	if !ok {
+		return
+	}
+
+	sourceTypes, err := bindplane.Store().SourceTypes(options...)
 	if okResponse(c, err) {
 		c.JSON(http.StatusOK, model.SourceTypesResponse{
 			SourceTypes: sourceTypes,
+			Continue:    continueToken(c, sourceTypes),
 		})
 	}
 }
@@ -761,6 +1281,21 @@ func sourceType(c *gin.Context, bindplane server.BindPlane) {
 	}
 }
 
+// @Summary Get source type usage
+// @Produce json
+// @Router /source-types/{name}/usage [get]
+// @Param 	name	path	string	true "the name of the source type"
+// @Success 200 {object} model.ResourceUsageResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+func sourceTypeUsage(c *gin.Context, bindplane server.BindPlane) {
+	name := c.Param("name")
+	sourceType, err := bindplane.Store().SourceType(name)
+	if okResource(c, sourceType == nil, err) {
+		resourceUsage(c, bindplane, sourceType)
+	}
+}
+
 // @Summary Delete source type by name
 // @Produce json
 // @Router /source-types/{name} [delete]
@@ -781,13 +1316,26 @@ func deleteSourceType(c *gin.Context, bindplane server.BindPlane) {
 // @Summary List processors
 // @Produce json
 // @Router /processors [get]
+// @Param	selector	query	string	false "label selector used to filter the results, e.g. env=prod"
+// @Param	query	query	string	false "search query used to filter the results"
+// @Param	sort	query	string	false "field used to sort the results, prefixed with - to sort descending"
+// @Param	limit	query	int	false "maximum number of results to return"
+// @Param	offset	query	int	false "number of results to skip"
+// @Param	continue	query	string	false "continue token returned with the previous page of results"
 // @Success 200 {object} model.ProcessorsResponse
+// @Failure 400 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 func processors(c *gin.Context, bindplane server.BindPlane) {
-	processors, err := bindplane.Store().Processors()
+	options, ok := listOptions(c, bindplane)
+	if !ok {
+		return
+	}
+
+	processors, err := bindplane.Store().Processors(options...)
 	if okResponse(c, err) {
 		c.JSON(http.StatusOK, model.ProcessorsResponse{
 			Processors: processors,
+			Continue:   continueToken(c, processors),
 		})
 	}
 }
@@ -809,6 +1357,21 @@ func processor(c *gin.Context, bindplane server.BindPlane) {
 	}
 }
 
+// @Summary Get processor usage
+// @Produce json
+// @Router /processors/{name}/usage [get]
+// @Param 	name	path	string	true "the name of the processor"
+// @Success 200 {object} model.ResourceUsageResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+func processorUsage(c *gin.Context, bindplane server.BindPlane) {
+	name := c.Param("name")
+	processor, err := bindplane.Store().Processor(name)
+	if okResource(c, processor == nil, err) {
+		resourceUsage(c, bindplane, processor)
+	}
+}
+
 // @Summary Delete processor by name
 // @Produce json
 // @Router /processors/{name} [delete]
@@ -829,13 +1392,26 @@ func deleteProcessor(c *gin.Context, bindplane server.BindPlane) {
 // @Summary List processor types
 // @Produce json
 // @Router /processor-types [get]
+// @Param	selector	query	string	false "label selector used to filter the results, e.g. env=prod"
+// @Param	query	query	string	false "search query used to filter the results"
+// @Param	sort	query	string	false "field used to sort the results, prefixed with - to sort descending"
+// @Param	limit	query	int	false "maximum number of results to return"
+// @Param	offset	query	int	false "number of results to skip"
+// @Param	continue	query	string	false "continue token returned with the previous page of results"
 // @Success 200 {object} model.ProcessorTypesResponse
+// @Failure 400 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 func processorTypes(c *gin.Context, bindplane server.BindPlane) {
-	processorTypes, err := bindplane.Store().ProcessorTypes()
+	options, ok := listOptions(c, bindplane)
+	if !ok {
+		return
+	}
+
+	processorTypes, err := bindplane.Store().ProcessorTypes(options...)
 	if okResponse(c, err) {
 		c.JSON(http.StatusOK, model.ProcessorTypesResponse{
 			ProcessorTypes: processorTypes,
+			Continue:       continueToken(c, processorTypes),
 		})
 	}
 }
@@ -857,6 +1433,21 @@ func processorType(c *gin.Context, bindplane server.BindPlane) {
 	}
 }
 
+// @Summary Get processor type usage
+// @Produce json
+// @Router /processor-types/{name}/usage [get]
+// @Param 	name	path	string	true "the name of the processor type"
+// @Success 200 {object} model.ResourceUsageResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+func processorTypeUsage(c *gin.Context, bindplane server.BindPlane) {
+	name := c.Param("name")
+	processorType, err := bindplane.Store().ProcessorType(name)
+	if okResource(c, processorType == nil, err) {
+		resourceUsage(c, bindplane, processorType)
+	}
+}
+
 // @Summary Delete processor type by name
 // @Produce json
 // @Router /processor-types/{name} [delete]
@@ -877,13 +1468,26 @@ func deleteProcessorType(c *gin.Context, bindplane server.BindPlane) {
 // @Summary List destinations
 // @Produce json
 // @Router /destinations [get]
+// @Param	selector	query	string	false "label selector used to filter the results, e.g. env=prod"
+// @Param	query	query	string	false "search query used to filter the results"
+// @Param	sort	query	string	false "field used to sort the results, prefixed with - to sort descending"
+// @Param	limit	query	int	false "maximum number of results to return"
+// @Param	offset	query	int	false "number of results to skip"
+// @Param	continue	query	string	false "continue token returned with the previous page of results"
 // @Success 200 {object} model.DestinationsResponse
+// @Failure 400 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 func destinations(c *gin.Context, bindplane server.BindPlane) {
-	destinations, err := bindplane.Store().Destinations()
+	options, ok := listOptions(c, bindplane)
+	if !ok {
+		return
+	}
+
+	destinations, err := bindplane.Store().Destinations(options...)
 	if okResponse(c, err) {
 		c.JSON(http.StatusOK, model.DestinationsResponse{
 			Destinations: destinations,
+			Continue:     continueToken(c, destinations),
 		})
 	}
 }
@@ -905,6 +1509,21 @@ func destination(c *gin.Context, bindplane server.BindPlane) {
 	}
 }
 
+// @Summary Get destination usage
+// @Produce json
+// @Router /destinations/{name}/usage [get]
+// @Param 	name	path	string	true "the name of the destination"
+// @Success 200 {object} model.ResourceUsageResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+func destinationUsage(c *gin.Context, bindplane server.BindPlane) {
+	name := c.Param("name")
+	destination, err := bindplane.Store().Destination(name)
+	if okResource(c, destination == nil, err) {
+		resourceUsage(c, bindplane, destination)
+	}
+}
+
 // @Summary Delete destination by name
 // @Produce json
 // @Router /destinations/{name} [delete]
@@ -925,13 +1544,26 @@ func deleteDestination(c *gin.Context, bindplane server.BindPlane) {
 // @Summary List destination types
 // @Produce json
 // @Router /destination-types [get]
+// @Param	selector	query	string	false "label selector used to filter the results, e.g. env=prod"
+// @Param	query	query	string	false "search query used to filter the results"
+// @Param	sort	query	string	false "field used to sort the results, prefixed with - to sort descending"
+// @Param	limit	query	int	false "maximum number of results to return"
+// @Param	offset	query	int	false "number of results to skip"
+// @Param	continue	query	string	false "continue token returned with the previous page of results"
 // @Success 200 {object} model.DestinationTypesResponse
+// @Failure 400 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 func destinationTypes(c *gin.Context, bindplane server.BindPlane) {
-	destinationTypes, err := bindplane.Store().DestinationTypes()
+	options, ok := listOptions(c, bindplane)
+	if !ok {
+		return
+	}
+
+	destinationTypes, err := bindplane.Store().DestinationTypes(options...)
 	if okResponse(c, err) {
 		c.JSON(http.StatusOK, model.DestinationTypesResponse{
 			DestinationTypes: destinationTypes,
+			Continue:         continueToken(c, destinationTypes),
 		})
 	}
 }
@@ -953,6 +1585,21 @@ func destinationType(c *gin.Context, bindplane server.BindPlane) {
 	}
 }
 
+// @Summary Get destination type usage
+// @Produce json
+// @Router /destination-types/{name}/usage [get]
+// @Param 	name	path	string	true "the name of the destination type"
+// @Success 200 {object} model.ResourceUsageResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+func destinationTypeUsage(c *gin.Context, bindplane server.BindPlane) {
+	name := c.Param("name")
+	destinationType, err := bindplane.Store().DestinationType(name)
+	if okResource(c, destinationType == nil, err) {
+		resourceUsage(c, bindplane, destinationType)
+	}
+}
+
 // @Summary Delete destination type by name
 // @Produce json
 // @Router /destination-types/{name} [delete]
@@ -979,6 +1626,7 @@ func deleteDestinationType(c *gin.Context, bindplane server.BindPlane) {
 // @Param resources 	body	[]model.AnyResource	true "Resources"
 // @Success 200 {object} model.ApplyResponse
 // @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 func applyResources(c *gin.Context, bindplane server.BindPlane) {
 	p := &model.ApplyPayload{}
@@ -1002,8 +1650,18 @@ func applyResources(c *gin.Context, bindplane server.BindPlane) {
 
 	bindplane.Logger().Info("/apply", zap.Int("count", len(resources)))
 
-	resourceStatuses, err := bindplane.Store().ApplyResources(resources)
-	if err != nil {
+	var resourceStatuses []model.ResourceStatus
+	var err error
+	if len(p.Preconditions) > 0 {
+		resourceStatuses, err = bindplane.Store().ApplyResourcesWithPreconditions(resources, p.Preconditions)
+	} else {
+		resourceStatuses, err = bindplane.Store().ApplyResources(resources)
+	}
+	switch {
+	case errors.Is(err, store.ErrPreconditionFailed):
+		handleErrorResponse(c, http.StatusConflict, err)
+		return
+	case err != nil:
 		handleErrorResponse(c, http.StatusInternalServerError, err)
 		return
 	}
@@ -1069,6 +1727,7 @@ func bindplaneVersion(c *gin.Context) {
 // @Router /agent-versions/{version}/install-command [get]
 // @Param version 	path	string	true "2.1.1"
 // @Param secret-key query string false "uuid"
+// @Param enrollment-token query string false "enrollment token value, used instead of the secret key"
 // @Param remote-url query string false "http%3A%2F%2Flocalhost%3A3001"
 // @Param platform query string false "windows-amd64"
 // @Param labels query string false "env=stage,app=bindplane"
@@ -1083,6 +1742,20 @@ func getInstallCommand(c *gin.Context, bindplane server.BindPlane) {
 		secretKey = config.SecretKey
 	}
 
+	// agents installed with an enrollment token use it instead of the secret key
+	if enrollmentToken := c.Query("enrollment-token"); enrollmentToken != "" {
+		id, _, ok := model.ParseEnrollmentToken(enrollmentToken)
+		if !ok {
+			handleErrorResponse(c, http.StatusBadRequest, errors.New("invalid enrollment token"))
+			return
+		}
+		token, err := bindplane.Store().EnrollmentToken(c.Request.Context(), id)
+		if !okResource(c, token == nil, err) {
+			return
+		}
+		secretKey = enrollmentToken
+	}
+
 	remoteURL := c.Query("remote-url")
 	if remoteURL == "" {
 		remoteURL = fmt.Sprintf("%s/v1/opamp", config.WebsocketURL())
@@ -1171,6 +1844,16 @@ func syncAgentVersion(c *gin.Context, bindplane server.BindPlane) {
 
 // ----------------------------------------------------------------------
 
+// resourceUsage responds with the usage of the resource
+func resourceUsage(c *gin.Context, bindplane server.BindPlane, resource model.Resource) {
+	usage, err := store.FindResourceUsage(bindplane.Store(), resource)
+	if okResponse(c, err) {
+		c.JSON(http.StatusOK, model.ResourceUsageResponse{
+			Usage: usage,
+		})
+	}
+}
+
 // okResponse returns true if there should be an OK response based on the error provided. It will set an error response on the
 // gin.Context if appropriate.
 func okResponse(c *gin.Context, err error) bool {
@@ -1179,6 +1862,8 @@ func okResponse(c *gin.Context, err error) bool {
 		return true
 	case errors.Is(err, store.ErrResourceMissing):
 		handleErrorResponse(c, http.StatusNotFound, err)
+	case errors.Is(err, store.ErrInvalidContinueToken), errors.Is(err, store.ErrInvalidSort):
+		handleErrorResponse(c, http.StatusBadRequest, err)
 	case isDependencyError(err):
 		handleErrorResponse(c, http.StatusConflict, err)
 	default:
@@ -1187,6 +1872,65 @@ func okResponse(c *gin.Context, err error) bool {
 	return false
 }
 
+// listOptions returns the store query options for the selector, query, sort, offset, limit, and continue parameters
+// of a list request. It will set an error response on the gin.Context and return false if a parameter is invalid.
+func listOptions(c *gin.Context, bindplane server.BindPlane) ([]store.QueryOption, bool) {
+	options := []store.QueryOption{}
+
+	selectorString := c.DefaultQuery("selector", "")
+	selector, err := model.SelectorFromString(selectorString)
+	if err != nil {
+		handleErrorResponse(c, http.StatusBadRequest, err)
+		return nil, false
+	}
+	options = append(options, store.WithSelector(selector))
+
+	query := c.DefaultQuery("query", "")
+	if query != "" {
+		q := search.ParseQuery(query)
+		q.ReplaceVersionLatest(bindplane.Versions())
+		options = append(options, store.WithQuery(q))
+	}
+
+	offset := c.DefaultQuery("offset", "0")
+	offsetValue, err := strconv.Atoi(offset)
+	if err != nil {
+		handleErrorResponse(c, http.StatusBadRequest, fmt.Errorf("offset must be a number: %v", err))
+		return nil, false
+	}
+	options = append(options, store.WithOffset(offsetValue))
+
+	limit := c.DefaultQuery("limit", "0")
+	limitValue, err := strconv.Atoi(limit)
+	if err != nil {
+		handleErrorResponse(c, http.StatusBadRequest, fmt.Errorf("limit must be a number: %v", err))
+		return nil, false
+	}
+	options = append(options, store.WithLimit(limitValue))
+
+	sort := c.DefaultQuery("sort", "")
+	if sort != "" {
+		options = append(options, store.WithSort(sort))
+	}
+
+	token := c.DefaultQuery("continue", "")
+	if token != "" {
+		options = append(options, store.WithContinue(token))
+	}
+
+	return options, true
+}
+
+// continueToken returns the token used to request the next page of results if the list request has a limit and the
+// page is full
+func continueToken[T model.HasUniqueKey](c *gin.Context, items []T) string {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "0"))
+	if limit <= 0 || len(items) < limit {
+		return ""
+	}
+	return store.ContinueToken(items[len(items)-1], c.DefaultQuery("sort", ""))
+}
+
 // okResource returns true if there should be an OK response based on the resource and error provided. It will set an
 // error response on the gin.Context if appropriate.
 func okResource(c *gin.Context, resourceIsNil bool, err error) bool {
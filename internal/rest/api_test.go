@@ -24,10 +24,12 @@ import (
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-resty/resty/v2"
 	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
 	"github.com/mitchellh/mapstructure"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -36,7 +38,9 @@ import (
 	"go.uber.org/zap/zaptest"
 
 	"github.com/observiq/bindplane-op/common"
+	"github.com/observiq/bindplane-op/internal/cluster"
 	"github.com/observiq/bindplane-op/internal/server"
+	"github.com/observiq/bindplane-op/internal/server/auth"
 	"github.com/observiq/bindplane-op/internal/store"
 	"github.com/observiq/bindplane-op/model"
 )
@@ -187,7 +191,7 @@ func TestREST(t *testing.T) {
 		MaxEventsToMerge: 1,
 	}, zap.NewNop())
 
-	bindplane, err := server.NewBindPlane(&common.Server{}, zaptest.NewLogger(t), store, nil)
+	bindplane, err := server.NewBindPlane(&common.Server{}, zaptest.NewLogger(t), store, nil, nil)
 	require.NoError(t, err)
 	AddRestRoutes(router, bindplane)
 
@@ -256,6 +260,49 @@ func TestREST(t *testing.T) {
 		require.ElementsMatch(t, rr.Destinations, []*model.Destination{destination1, destination2})
 	})
 
+	t.Run("GET /destinations supports selector, sort, limit, and continue", func(t *testing.T) {
+		resetStore(t, s)
+
+		resources := []model.Resource{}
+		for i := 1; i <= 5; i++ {
+			destination := testDestination(fmt.Sprintf("destination-%d", i), "cabin")
+			destination.Metadata.Labels = model.LabelsFromValidatedMap(map[string]string{"odd": fmt.Sprintf("%t", i%2 == 1)})
+			resources = append(resources, destination)
+		}
+		_, err := s.ApplyResources(resources)
+		require.NoError(t, err)
+
+		names := func(destinations []*model.Destination) []string {
+			result := []string{}
+			for _, destination := range destinations {
+				result = append(result, destination.Name())
+			}
+			return result
+		}
+
+		rr := &model.DestinationsResponse{}
+		getRequest(t, client, "/destinations?sort=-name&limit=2", rr)
+		require.Equal(t, []string{"destination-5", "destination-4"}, names(rr.Destinations))
+		require.NotEmpty(t, rr.Continue)
+
+		next := &model.DestinationsResponse{}
+		getRequest(t, client, "/destinations?sort=-name&limit=2&continue="+rr.Continue, next)
+		require.Equal(t, []string{"destination-3", "destination-2"}, names(next.Destinations))
+
+		rr = &model.DestinationsResponse{}
+		getRequest(t, client, "/destinations?selector=odd%3Dtrue&query=destination-&limit=5", rr)
+		require.Equal(t, []string{"destination-1", "destination-3", "destination-5"}, names(rr.Destinations))
+		require.Empty(t, rr.Continue)
+
+		resp, err := client.R().Get("/destinations?sort=name&continue=" + next.Continue)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode())
+
+		resp, err = client.R().Get("/destinations?sort=unknown")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode())
+	})
+
 	t.Run("GET /destinations/:name returns a specific Destination by name", func(t *testing.T) {
 		resetStore(t, s)
 
@@ -274,6 +321,48 @@ func TestREST(t *testing.T) {
 		require.Equal(t, rr.Destination, destination2)
 	})
 
+	t.Run("GET /destinations/:name/usage returns the resources and agents that use a Destination", func(t *testing.T) {
+		resetStore(t, s)
+
+		destination := testDestination("usage-destination", "cabin")
+		configuration := model.NewConfigurationWithSpec("usage-configuration", model.ConfigurationSpec{
+			Sources:      []model.ResourceConfiguration{{Type: "macos"}},
+			Destinations: []model.ResourceConfiguration{{Name: "usage-destination"}},
+			Selector: model.AgentSelector{
+				MatchLabels: map[string]string{"env": "production"},
+			},
+		})
+		_, err := s.ApplyResources([]model.Resource{destination, configuration})
+		require.NoError(t, err)
+
+		_, err = addAgent(s, &model.Agent{ID: "1", Labels: model.LabelsFromValidatedMap(map[string]string{"env": "production"})})
+		require.NoError(t, err)
+		_, err = addAgent(s, &model.Agent{ID: "2", Labels: model.LabelsFromValidatedMap(map[string]string{"env": "test"})})
+		require.NoError(t, err)
+
+		rr := &model.ResourceUsageResponse{}
+
+		getRequest(t, client, "/destinations/usage-destination/usage", rr)
+
+		require.Equal(t, &model.ResourceUsage{
+			Kind:           model.KindDestination,
+			Name:           "usage-destination",
+			Sources:        []string{},
+			Processors:     []string{},
+			Destinations:   []string{},
+			Configurations: []string{"usage-configuration"},
+			Agents:         []string{"1"},
+		}, rr.Usage)
+	})
+
+	t.Run("GET /destinations/:name/usage 404 Not Found", func(t *testing.T) {
+		resetStore(t, s)
+
+		resp, err := client.R().Get("/destinations/does-not-exist/usage")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNotFound, resp.StatusCode())
+	})
+
 	t.Run("DELETE /agents 200", func(t *testing.T) {
 		resetStore(t, s)
 		addAgent(s, &model.Agent{ID: "1"})
@@ -564,6 +653,63 @@ func TestREST(t *testing.T) {
 		}
 	})
 
+	t.Run("POST /apply preconditions", func(t *testing.T) {
+		resetStore(t, bindplane.Store())
+		sourceAsResource := testSource("source", "macos")
+		_, err := bindplane.Store().ApplyResources([]model.Resource{sourceAsResource})
+		require.NoError(t, err)
+
+		// hash the source as the client would read it from the server
+		stored := &model.SourceResponse{}
+		getRequest(t, client, "/sources/source", stored)
+		hash, err := model.ResourceHash(stored.Source)
+		require.NoError(t, err)
+
+		sourceAsAny := testSourceAsAny(t, "source", "macos")
+
+		tests := []struct {
+			description    string
+			setupResources []model.Resource
+			preconditions  []model.ResourcePrecondition
+			wantStatus     int
+		}{
+			{
+				description:    "matching hash",
+				setupResources: []model.Resource{sourceAsResource},
+				preconditions:  []model.ResourcePrecondition{{Kind: model.KindSource, Name: "source", Hash: hash}},
+				wantStatus:     http.StatusAccepted,
+			},
+			{
+				description:    "stale hash",
+				setupResources: []model.Resource{sourceAsResource},
+				preconditions:  []model.ResourcePrecondition{{Kind: model.KindSource, Name: "source", Hash: "stale"}},
+				wantStatus:     http.StatusConflict,
+			},
+			{
+				description:    "deleted resource",
+				setupResources: []model.Resource{},
+				preconditions:  []model.ResourcePrecondition{{Kind: model.KindSource, Name: "source", Hash: hash}},
+				wantStatus:     http.StatusConflict,
+			},
+		}
+
+		for _, test := range tests {
+			t.Run(test.description, func(t *testing.T) {
+				resetStore(t, bindplane.Store())
+				_, err := bindplane.Store().ApplyResources(test.setupResources)
+				require.NoError(t, err, "expect no error in setup")
+
+				payload := &model.ApplyPayload{
+					Resources:     []*model.AnyResource{sourceAsAny},
+					Preconditions: test.preconditions,
+				}
+				resp, err := client.R().SetBody(payload).Post("/apply")
+				require.NoError(t, err, "expect no error in rest call")
+				require.Equal(t, test.wantStatus, resp.StatusCode())
+			})
+		}
+	})
+
 	t.Run("GET /configurations", func(t *testing.T) {
 		resetStore(t, bindplane.Store())
 
@@ -822,6 +968,216 @@ func TestREST(t *testing.T) {
 
 	})
 
+	t.Run("PUT /agents/:id/restart", func(t *testing.T) {
+		resetStore(t, bindplane.Store())
+		_, err := addAgent(s, &model.Agent{ID: "1", Labels: model.MakeLabels()})
+		require.NoError(t, err)
+
+		resp, err := client.R().Put("/agents/1/restart")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusConflict, resp.StatusCode(), "agent is not connected to any node")
+
+		resp, err = client.R().Put("/agents/missing/restart")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNotFound, resp.StatusCode())
+	})
+
+	t.Run("PUT /agents/:id/credentials/rotate", func(t *testing.T) {
+		resetStore(t, bindplane.Store())
+		_, err := addAgent(s, &model.Agent{ID: "1", Labels: model.MakeLabels()})
+		require.NoError(t, err)
+
+		resp, err := client.R().Put("/agents/1/credentials/rotate")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusConflict, resp.StatusCode(), "agent is not connected to any node")
+
+		resp, err = client.R().Put("/agents/missing/credentials/rotate")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNotFound, resp.StatusCode())
+	})
+
+	t.Run("PUT /agents/:id/conflict/resolve", func(t *testing.T) {
+		resetStore(t, bindplane.Store())
+		_, err := addAgent(s, &model.Agent{ID: "1", Labels: model.MakeLabels()})
+		require.NoError(t, err)
+
+		resp, err := client.R().Put("/agents/1/conflict/resolve")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusConflict, resp.StatusCode(), "agent does not have a conflict")
+
+		_, err = s.UpsertAgent(context.Background(), "1", func(agent *model.Agent) {
+			agent.Status = model.Connected
+			agent.SetConflict(&model.AgentConflict{HostName: "web-2"})
+		})
+		require.NoError(t, err)
+
+		resp, err = client.R().Put("/agents/1/conflict/resolve")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusAccepted, resp.StatusCode())
+
+		agent, err := s.Agent("1")
+		require.NoError(t, err)
+		require.Nil(t, agent.Conflict)
+		require.Equal(t, model.Connected, agent.Status)
+
+		resp, err = client.R().Put("/agents/missing/conflict/resolve")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNotFound, resp.StatusCode())
+	})
+
+	t.Run("PUT /agents/:id/certificate/revoke", func(t *testing.T) {
+		resetStore(t, bindplane.Store())
+		_, err := addAgent(s, &model.Agent{ID: "1", Labels: model.MakeLabels()})
+		require.NoError(t, err)
+
+		resp, err := client.R().Put("/agents/1/certificate/revoke")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusConflict, resp.StatusCode(), "agent ca is not enabled")
+
+		resp, err = client.R().Put("/agents/missing/certificate/revoke")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNotFound, resp.StatusCode())
+	})
+
+	t.Run("/enrollment-tokens", func(t *testing.T) {
+		resetStore(t, bindplane.Store())
+
+		created := &model.EnrollmentTokenResponse{}
+		resp, err := client.R().
+			SetBody(&model.PostEnrollmentTokenRequest{Labels: map[string]string{"env": "prod"}, MaxUses: 2, ExpiresIn: "24h"}).
+			SetResult(created).
+			Post("/enrollment-tokens")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusCreated, resp.StatusCode())
+		require.NotEmpty(t, created.Token)
+		require.Empty(t, created.EnrollmentToken.Hash, "hash is never returned")
+		require.Equal(t, 2, created.EnrollmentToken.MaxUses)
+		require.NotNil(t, created.EnrollmentToken.ExpiresAt)
+		require.Equal(t, "prod", created.EnrollmentToken.Labels.Set["env"])
+
+		stored, err := s.EnrollmentToken(context.Background(), created.EnrollmentToken.ID)
+		require.NoError(t, err)
+		id, secret, ok := model.ParseEnrollmentToken(created.Token)
+		require.True(t, ok)
+		require.Equal(t, stored.ID, id)
+		require.True(t, stored.Verify(secret))
+
+		list := &model.EnrollmentTokensResponse{}
+		getRequest(t, client, "/enrollment-tokens", list)
+		require.Len(t, list.EnrollmentTokens, 1)
+		require.Equal(t, created.EnrollmentToken.ID, list.EnrollmentTokens[0].ID)
+		require.Empty(t, list.EnrollmentTokens[0].Hash)
+
+		for _, body := range []*model.PostEnrollmentTokenRequest{
+			{ExpiresIn: "tomorrow"},
+			{ExpiresIn: "-1h"},
+			{MaxUses: -1},
+			{Labels: map[string]string{"bad label": "value"}},
+		} {
+			resp, err = client.R().SetBody(body).Post("/enrollment-tokens")
+			require.NoError(t, err)
+			require.Equal(t, http.StatusBadRequest, resp.StatusCode())
+		}
+
+		resp, err = client.R().Delete("/enrollment-tokens/" + created.EnrollmentToken.ID)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNoContent, resp.StatusCode())
+
+		resp, err = client.R().Delete("/enrollment-tokens/" + created.EnrollmentToken.ID)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNotFound, resp.StatusCode())
+	})
+
+	t.Run("POST /agents/approve", func(t *testing.T) {
+		resetStore(t, bindplane.Store())
+		ctx := context.Background()
+		for _, agent := range []*model.Agent{
+			{ID: "1", Status: model.Pending, Labels: model.LabelsFromValidatedMap(map[string]string{"app": "web"})},
+			{ID: "2", Status: model.Pending, Labels: model.LabelsFromValidatedMap(map[string]string{"app": "web"})},
+			{ID: "3", Status: model.Pending, Labels: model.LabelsFromValidatedMap(map[string]string{"app": "db"})},
+		} {
+			_, err := addAgent(s, agent)
+			require.NoError(t, err)
+			_, err = s.UpsertAgentCredential(ctx, agent.ID, func(current *model.AgentCredential) error {
+				current.Pending = true
+				return nil
+			})
+			require.NoError(t, err)
+		}
+
+		result := &model.ApproveAgentsResponse{}
+		resp, err := client.R().SetBody(&model.PostApproveAgentsRequest{IDs: []string{"1"}}).SetResult(result).Post("/agents/approve")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode())
+		require.Equal(t, []string{"1"}, result.Approved)
+
+		agent, err := s.Agent("1")
+		require.NoError(t, err)
+		require.Equal(t, model.Connected, agent.Status)
+
+		resp, err = client.R().SetBody(&model.PostApproveAgentsRequest{Selector: "app=web"}).SetResult(result).Post("/agents/approve")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode())
+		require.Equal(t, []string{"2"}, result.Approved, "agent 1 is already approved")
+
+		credential, err := s.AgentCredential(ctx, "3")
+		require.NoError(t, err)
+		require.True(t, credential.Pending)
+
+		for _, body := range []*model.PostApproveAgentsRequest{
+			{},
+			{Selector: "app=web,bad"},
+		} {
+			resp, err = client.R().SetBody(body).Post("/agents/approve")
+			require.NoError(t, err)
+			require.Equal(t, http.StatusBadRequest, resp.StatusCode())
+		}
+	})
+
+	t.Run("/agent-deny-rules", func(t *testing.T) {
+		resetStore(t, bindplane.Store())
+		_, err := addAgent(s, &model.Agent{ID: "1", HostName: "web-1", Labels: model.MakeLabels()})
+		require.NoError(t, err)
+		_, err = addAgent(s, &model.Agent{ID: "2", HostName: "web-2", Labels: model.MakeLabels()})
+		require.NoError(t, err)
+
+		created := &model.AgentDenyRuleResponse{}
+		resp, err := client.R().
+			SetBody(&model.PostAgentDenyRuleRequest{Field: model.AgentDenyFieldHostName, Value: "web-1", Reason: "cloned"}).
+			SetResult(created).
+			Post("/agent-deny-rules")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusCreated, resp.StatusCode())
+		require.Equal(t, "hostname:web-1", created.Rule.ID())
+		require.Equal(t, []string{"1"}, created.Disconnected)
+
+		agent, err := s.Agent("1")
+		require.NoError(t, err)
+		require.Nil(t, agent, "denied agents are removed")
+
+		list := &model.AgentDenyRulesResponse{}
+		getRequest(t, client, "/agent-deny-rules", list)
+		require.Len(t, list.Rules, 1)
+		require.Equal(t, "cloned", list.Rules[0].Reason)
+
+		for _, body := range []*model.PostAgentDenyRuleRequest{
+			{Field: model.AgentDenyFieldID},
+			{Field: "name", Value: "web-1"},
+		} {
+			resp, err = client.R().SetBody(body).Post("/agent-deny-rules")
+			require.NoError(t, err)
+			require.Equal(t, http.StatusBadRequest, resp.StatusCode())
+		}
+
+		resp, err = client.R().Delete("/agent-deny-rules/hostname:web-1")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNoContent, resp.StatusCode())
+
+		resp, err = client.R().Delete("/agent-deny-rules/hostname:web-1")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNotFound, resp.StatusCode())
+	})
+
 	t.Run("PATCH /agents/labels status 200", func(t *testing.T) {
 		resetStore(t, bindplane.Store())
 
@@ -895,6 +1251,208 @@ func TestREST(t *testing.T) {
 	})
 }
 
+func TestRESTUserSessions(t *testing.T) {
+	router := gin.Default()
+	// the role of the test user is specified by a header in place of the authentication middleware
+	router.Use(func(c *gin.Context) {
+		c.Set(auth.RoleKey, model.Role(c.GetHeader("X-Test-Role")))
+	})
+	svr := httptest.NewServer(router)
+	defer svr.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	store := store.NewMapStore(ctx, store.Options{
+		SessionsSecret:   "super-secret-key",
+		MaxEventsToMerge: 1,
+	}, zap.NewNop())
+
+	bindplane, err := server.NewBindPlane(&common.Server{}, zaptest.NewLogger(t), store, nil, nil)
+	require.NoError(t, err)
+	AddRestRoutes(router, bindplane)
+
+	client := resty.New()
+	client.SetBaseURL(svr.URL)
+	client.SetHeader("X-Test-Role", string(model.RoleAdmin))
+
+	addSession := func(user string, timeout time.Duration) *model.UserSession {
+		session, _, err := model.NewUserSession(user, model.RoleAdmin, timeout, time.Hour)
+		require.NoError(t, err)
+		require.NoError(t, store.CreateUserSessionRecord(ctx, session))
+		return session
+	}
+	alice1 := addSession("alice", time.Hour)
+	alice2 := addSession("alice", time.Hour)
+	bob := addSession("bob", time.Hour)
+	expired := addSession("carol", -time.Minute)
+
+	t.Run("viewers are forbidden", func(t *testing.T) {
+		resp, err := client.R().SetHeader("X-Test-Role", string(model.RoleViewer)).Get("/sessions")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusForbidden, resp.StatusCode())
+	})
+
+	t.Run("GET /sessions returns active sessions without csrf hashes", func(t *testing.T) {
+		result := &model.UserSessionsResponse{}
+		getRequest(t, client, "/sessions", result)
+		ids := []string{}
+		for _, session := range result.Sessions {
+			require.Empty(t, session.CSRFHash)
+			ids = append(ids, session.ID)
+		}
+		require.ElementsMatch(t, []string{alice1.ID, alice2.ID, bob.ID}, ids)
+
+		record, err := store.UserSessionRecord(ctx, expired.ID)
+		require.NoError(t, err)
+		require.Nil(t, record, "expired sessions are removed")
+	})
+
+	t.Run("DELETE /sessions/:id revokes the session", func(t *testing.T) {
+		resp, err := client.R().Delete("/sessions/" + bob.ID)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNoContent, resp.StatusCode())
+
+		resp, err = client.R().Delete("/sessions/" + bob.ID)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNotFound, resp.StatusCode())
+	})
+
+	t.Run("POST /sessions/revoke revokes the sessions of the user", func(t *testing.T) {
+		resp, err := client.R().SetBody(&model.PostRevokeUserSessionsRequest{}).Post("/sessions/revoke")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode())
+
+		result := &model.RevokeUserSessionsResponse{}
+		resp, err = client.R().
+			SetBody(&model.PostRevokeUserSessionsRequest{User: "alice"}).
+			SetResult(result).
+			Post("/sessions/revoke")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode())
+		require.ElementsMatch(t, []string{alice1.ID, alice2.ID}, result.Revoked)
+
+		records, err := store.UserSessionRecords(ctx)
+		require.NoError(t, err)
+		require.Empty(t, records)
+	})
+}
+
+func TestRESTGitOpsSync(t *testing.T) {
+	router := gin.Default()
+	svr := httptest.NewServer(router)
+	defer svr.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	store := store.NewMapStore(ctx, store.Options{
+		SessionsSecret:   "super-secret-key",
+		MaxEventsToMerge: 1,
+	}, zap.NewNop())
+
+	bindplane, err := server.NewBindPlane(&common.Server{}, zaptest.NewLogger(t), store, nil, nil)
+	require.NoError(t, err)
+	AddRestRoutes(router, bindplane)
+
+	client := resty.New()
+	client.SetBaseURL(svr.URL)
+
+	t.Run("GET /gitops/sync returns 404 before the first sync", func(t *testing.T) {
+		resp, err := client.R().Get("/gitops/sync")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNotFound, resp.StatusCode())
+	})
+
+	t.Run("GET /gitops/sync returns the status", func(t *testing.T) {
+		status := &model.GitOpsSyncStatus{
+			Repository: "https://github.com/observiq/resources.git",
+			Branch:     "main",
+			Commit:     "8d2f0f5c4c1a6e2d8b9f3e7a1c5d4b6a2e9f8c7d",
+			SyncedAt:   time.Date(2022, 9, 1, 0, 0, 0, 0, time.UTC),
+			Changes: []model.GitOpsChange{
+				{Kind: model.KindSource, Name: "source-1", Status: model.StatusCreated},
+			},
+			Errors: []string{"failed to parse bindplane/bad.yaml"},
+		}
+		require.NoError(t, store.SetGitOpsSyncStatus(ctx, status))
+
+		result := &model.GitOpsSyncResponse{}
+		resp, err := client.R().SetResult(result).Get("/gitops/sync")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode())
+		require.Equal(t, status, result.Status)
+	})
+}
+
+func TestRESTWatch(t *testing.T) {
+	router := gin.Default()
+	svr := httptest.NewServer(router)
+	defer svr.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	store := store.NewMapStore(ctx, store.Options{
+		SessionsSecret:   "super-secret-key",
+		MaxEventsToMerge: 1,
+	}, zap.NewNop())
+
+	bindplane, err := server.NewBindPlane(&common.Server{}, zaptest.NewLogger(t), store, nil, nil)
+	require.NoError(t, err)
+	AddRestRoutes(router, bindplane)
+	resetStore(t, store)
+
+	watchURL := "ws" + strings.TrimPrefix(svr.URL, "http") + "/watch"
+
+	t.Run("GET /watch rejects unsupported kinds", func(t *testing.T) {
+		_, resp, err := websocket.DefaultDialer.Dial(watchURL+"?kind=Unknown", nil)
+		require.Error(t, err)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("GET /watch rejects invalid selectors", func(t *testing.T) {
+		_, resp, err := websocket.DefaultDialer.Dial(watchURL+"?kind=Source&selector="+url.QueryEscape("a=b=c"), nil)
+		require.Error(t, err)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("GET /watch sends changes that match the filter", func(t *testing.T) {
+		conn, _, err := websocket.DefaultDialer.Dial(watchURL+"?kind=Source&selector="+url.QueryEscape("env=prod"), nil)
+		require.NoError(t, err)
+		defer conn.Close()
+
+		// an empty batch is sent once the watch has started
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+		events := []*model.WatchEvent{}
+		require.NoError(t, conn.ReadJSON(&events))
+		require.Empty(t, events)
+
+		nginx := testSource("nginx", "nginx")
+		nginx.Metadata.Labels = model.LabelsFromValidatedMap(map[string]string{"env": "prod"})
+		_, err = store.ApplyResources([]model.Resource{nginx, testDestination("cabin", "cabin")})
+		require.NoError(t, err)
+
+		require.NoError(t, conn.ReadJSON(&events))
+		require.Len(t, events, 1)
+		require.Equal(t, model.WatchEventInsert, events[0].Type)
+		require.Equal(t, model.KindSource, events[0].Kind)
+		require.Equal(t, "nginx", events[0].Name)
+
+		source := &model.Source{}
+		require.NoError(t, json.Unmarshal(events[0].Resource, source))
+		require.Equal(t, "nginx", source.Name())
+
+		// the source no longer matches the selector
+		nginx = testSource("nginx", "nginx")
+		nginx.Metadata.Labels = model.LabelsFromValidatedMap(map[string]string{"env": "dev"})
+		_, err = store.ApplyResources([]model.Resource{nginx})
+		require.NoError(t, err)
+
+		require.NoError(t, conn.ReadJSON(&events))
+		require.Len(t, events, 1)
+		require.Equal(t, model.WatchEventRemove, events[0].Type)
+		require.Equal(t, "nginx", events[0].Name)
+	})
+}
+
 func getRequest(t *testing.T, client *resty.Client, endpoint string, result interface{}) {
 	_, err := client.R().SetResult(result).Get(endpoint)
 	if err != nil {
@@ -1643,7 +2201,7 @@ func TestRESTMock(t *testing.T) {
 			defer svr.Close()
 
 			store := &mockStore{}
-			bindplane, err := server.NewBindPlane(&common.Server{}, zaptest.NewLogger(t), store, nil)
+			bindplane, err := server.NewBindPlane(&common.Server{}, zaptest.NewLogger(t), store, nil, nil)
 			require.NoError(t, err)
 			AddRestRoutes(router, bindplane)
 
@@ -1696,7 +2254,7 @@ func (m *mockStore) DeleteResources(resources []model.Resource) ([]model.Resourc
 	return args.Get(0).([]model.ResourceStatus), args.Error(1)
 }
 
-func (m *mockStore) Sources() ([]*model.Source, error) {
+func (m *mockStore) Sources(options ...store.QueryOption) ([]*model.Source, error) {
 	args := m.Called()
 	return args.Get(0).([]*model.Source), args.Error(1)
 }
@@ -1736,7 +2294,7 @@ func (m *mockStore) Agent(id string) (*model.Agent, error) {
 	}
 }
 
-func (m *mockStore) Destinations() ([]*model.Destination, error) {
+func (m *mockStore) Destinations(options ...store.QueryOption) ([]*model.Destination, error) {
 	args := m.Called()
 	return args.Get(0).([]*model.Destination), args.Error(1)
 }
@@ -1785,3 +2343,7 @@ func (m *mockStore) DeleteConfiguration(name string) (*model.Configuration, erro
 		return args.Get(0).(*model.Configuration), args.Error(1)
 	}
 }
+
+func (m *mockStore) ClusterBus() cluster.Bus {
+	return cluster.NewLocalBus()
+}
@@ -0,0 +1,200 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+
+	"github.com/observiq/bindplane-op/internal/eventbus"
+	"github.com/observiq/bindplane-op/internal/server"
+	"github.com/observiq/bindplane-op/internal/store"
+	"github.com/observiq/bindplane-op/model"
+)
+
+// watchPingInterval keeps the websocket open through proxies when there are no changes
+const watchPingInterval = 10 * time.Second
+
+// the default CheckOrigin rejects cross-origin requests from browsers so that another site can't use the session
+// cookie of a user to watch resources
+var watchUpgrader = websocket.Upgrader{}
+
+// watchFilter limits the events sent by watch
+type watchFilter struct {
+	kind     model.Kind
+	name     string
+	selector *model.Selector
+}
+
+// @Summary Watch resources
+// @Description Upgrades to a websocket that sends a JSON array of events each time resources of the kind change.
+// @Description Updates to resources that no longer match the selector are sent as removes.
+// @Description An empty array is sent once the watch has started so that clients can list resources without missing changes.
+// @Produce json
+// @Router /watch [get]
+// @Param 	kind		query	string	true	"the kind of resources to watch, e.g. Agent or Configuration"
+// @Param 	name		query	string	false	"the name of the resource to watch, or the ID of an agent"
+// @Param 	selector	query	string	false	"label selector to filter resources, e.g. app=foo"
+// @Success 101 {array} model.WatchEvent
+// @Failure 400 {object} ErrorResponse
+func watch(c *gin.Context, bindplane server.BindPlane) {
+	filter := watchFilter{
+		kind: model.ParseKind(c.Query("kind")),
+		name: c.Query("name"),
+	}
+	if _, ok := watchEvents(filter, store.NewUpdates()); !ok {
+		handleErrorResponse(c, http.StatusBadRequest, fmt.Errorf("unable to watch kind %s", c.Query("kind")))
+		return
+	}
+	if selectorString := c.Query("selector"); selectorString != "" {
+		selector, err := model.SelectorFromString(selectorString)
+		if err != nil {
+			handleErrorResponse(c, http.StatusBadRequest, err)
+			return
+		}
+		filter.selector = &selector
+	}
+
+	conn, err := watchUpgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// the upgrader has already responded with an error
+		bindplane.Logger().Debug("failed to upgrade watch request", zap.Error(err))
+		return
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	// read until the client closes the connection. messages from the client are ignored.
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	changes, _ := eventbus.SubscribeWithFilterUntilDone(ctx, bindplane.Store().Updates(), func(updates *store.Updates) ([]*model.WatchEvent, bool) {
+		events, _ := watchEvents(filter, updates)
+		return events, len(events) > 0
+	})
+
+	// let the client know that changes from now on will be sent
+	if err := conn.WriteJSON([]*model.WatchEvent{}); err != nil {
+		bindplane.Logger().Debug("failed to send watch events", zap.Error(err))
+		return
+	}
+
+	ping := time.NewTicker(watchPingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case events, ok := <-changes:
+			if !ok {
+				return
+			}
+			if err := conn.WriteJSON(events); err != nil {
+				bindplane.Logger().Debug("failed to send watch events", zap.Error(err))
+				return
+			}
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(watchPingInterval)); err != nil && !errors.Is(err, websocket.ErrCloseSent) {
+				return
+			}
+		}
+	}
+}
+
+// watchEvents returns the events in the updates that match the filter. It returns false if the kind can't be watched.
+func watchEvents(filter watchFilter, updates *store.Updates) ([]*model.WatchEvent, bool) {
+	switch filter.kind {
+	case model.KindAgent:
+		return appendWatchEvents(nil, filter, updates.Agents), true
+	case model.KindAgentVersion:
+		return appendWatchEvents(nil, filter, updates.AgentVersions), true
+	case model.KindConfiguration:
+		return appendWatchEvents(nil, filter, updates.Configurations), true
+	case model.KindSource:
+		return appendWatchEvents(nil, filter, updates.Sources), true
+	case model.KindSourceType:
+		return appendWatchEvents(nil, filter, updates.SourceTypes), true
+	case model.KindProcessor:
+		return appendWatchEvents(nil, filter, updates.Processors), true
+	case model.KindProcessorType:
+		return appendWatchEvents(nil, filter, updates.ProcessorTypes), true
+	case model.KindDestination:
+		return appendWatchEvents(nil, filter, updates.Destinations), true
+	case model.KindDestinationType:
+		return appendWatchEvents(nil, filter, updates.DestinationTypes), true
+	}
+	return nil, false
+}
+
+type watchable interface {
+	model.HasUniqueKey
+	model.Labeled
+}
+
+func appendWatchEvents[T watchable](watchEvents []*model.WatchEvent, filter watchFilter, events store.Events[T]) []*model.WatchEvent {
+	for key, event := range events {
+		if filter.name != "" && key != filter.name {
+			continue
+		}
+		eventType := watchEventType(event.Type)
+		if eventType != model.WatchEventRemove && filter.selector != nil && !filter.selector.Matches(event.Item.GetLabels()) {
+			eventType = model.WatchEventRemove
+		}
+		resource, err := json.Marshal(event.Item)
+		if err != nil {
+			continue
+		}
+		watchEvents = append(watchEvents, &model.WatchEvent{
+			Type:     eventType,
+			Kind:     filter.kind,
+			Name:     key,
+			Resource: resource,
+		})
+	}
+	sort.Slice(watchEvents, func(i, j int) bool {
+		return watchEvents[i].Name < watchEvents[j].Name
+	})
+	return watchEvents
+}
+
+func watchEventType(eventType store.EventType) model.WatchEventType {
+	switch eventType {
+	case store.EventTypeInsert:
+		return model.WatchEventInsert
+	case store.EventTypeRemove:
+		return model.WatchEventRemove
+	default:
+		// labels changes are updates
+		return model.WatchEventUpdate
+	}
+}
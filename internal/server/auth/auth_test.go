@@ -0,0 +1,221 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/observiq/bindplane-op/common"
+	"github.com/observiq/bindplane-op/internal/server"
+	"github.com/observiq/bindplane-op/internal/server/oidc"
+	"github.com/observiq/bindplane-op/internal/server/oidc/oidctest"
+	"github.com/observiq/bindplane-op/internal/server/sessions"
+	"github.com/observiq/bindplane-op/internal/store"
+)
+
+func TestChain(t *testing.T) {
+	idp := oidctest.New(t, oidctest.Claims{Subject: "user-1"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s := store.NewMapStore(ctx, store.Options{
+		SessionsSecret:   "super-secret-key",
+		MaxEventsToMerge: 1,
+	}, zap.NewNop())
+
+	cfg := &common.Server{}
+	cfg.Username = "user"
+	cfg.Password = "secret"
+	bindplane, err := server.NewBindPlane(cfg, zap.NewNop(), s, nil, nil)
+	require.NoError(t, err)
+
+	provider, err := oidc.NewProvider(ctx, &common.OIDC{
+		Issuer:     idp.Issuer(),
+		ClientID:   "bindplane-cli",
+		GroupRoles: map[string]string{"ops": "admin", "engineers": "viewer"},
+	}, "")
+	require.NoError(t, err)
+
+	router := gin.New()
+	sessions.AddRoutes(router, bindplane)
+	group := router.Group("/v1", Chain(bindplane, provider)...)
+	group.Use(RequireWrite(), RequireCSRF())
+	handler := func(c *gin.Context) { c.Status(http.StatusOK) }
+	group.GET("/agents", handler)
+	group.DELETE("/agents", handler)
+
+	// graphql checks the role of the request context instead of the method
+	graphqlGroup := router.Group("/v1", Chain(bindplane, provider)...)
+	graphqlGroup.POST("/graphql", func(c *gin.Context) {
+		if !CanWrite(c.Request.Context()) {
+			c.Status(http.StatusForbidden)
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+
+	// log in to get a session cookie and its csrf token
+	loginReq := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(url.Values{
+		"username": []string{"user"},
+		"password": []string{"secret"},
+	}.Encode()))
+	loginReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	loginW := httptest.NewRecorder()
+	router.ServeHTTP(loginW, loginReq)
+	require.Equal(t, http.StatusOK, loginW.Code)
+
+	var sessionCookie *http.Cookie
+	var csrfToken string
+	for _, cookie := range loginW.Result().Cookies() {
+		switch cookie.Name {
+		case sessions.CookieName:
+			sessionCookie = cookie
+		case sessions.CSRFCookieName:
+			csrfToken = cookie.Value
+		}
+	}
+	require.NotNil(t, sessionCookie)
+	require.NotEmpty(t, csrfToken)
+
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		groups       []string
+		bearer       bool
+		basic        bool
+		session      bool
+		csrfToken    string
+		expectStatus int
+	}{
+		{
+			name:         "unauthenticated",
+			method:       http.MethodGet,
+			expectStatus: http.StatusUnauthorized,
+		},
+		{
+			name:         "basic can write",
+			method:       http.MethodDelete,
+			basic:        true,
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "admin bearer can write",
+			method:       http.MethodDelete,
+			groups:       []string{"ops"},
+			bearer:       true,
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "viewer bearer can read",
+			method:       http.MethodGet,
+			groups:       []string{"engineers"},
+			bearer:       true,
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "viewer bearer can't write",
+			method:       http.MethodDelete,
+			groups:       []string{"engineers"},
+			bearer:       true,
+			expectStatus: http.StatusForbidden,
+		},
+		{
+			name:         "session can read without csrf token",
+			method:       http.MethodGet,
+			session:      true,
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "session can't write without csrf token",
+			method:       http.MethodDelete,
+			session:      true,
+			expectStatus: http.StatusForbidden,
+		},
+		{
+			name:         "session can't write with wrong csrf token",
+			method:       http.MethodDelete,
+			session:      true,
+			csrfToken:    "wrong",
+			expectStatus: http.StatusForbidden,
+		},
+		{
+			name:         "session can write with csrf token",
+			method:       http.MethodDelete,
+			session:      true,
+			csrfToken:    csrfToken,
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "admin bearer can write with graphql",
+			method:       http.MethodPost,
+			path:         "/v1/graphql",
+			groups:       []string{"ops"},
+			bearer:       true,
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "viewer bearer can't write with graphql",
+			method:       http.MethodPost,
+			path:         "/v1/graphql",
+			groups:       []string{"engineers"},
+			bearer:       true,
+			expectStatus: http.StatusForbidden,
+		},
+		{
+			name:         "bearer without a role",
+			method:       http.MethodGet,
+			groups:       []string{"sales"},
+			bearer:       true,
+			expectStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := test.path
+			if path == "" {
+				path = "/v1/agents"
+			}
+			req := httptest.NewRequest(test.method, path, nil)
+			if test.basic {
+				req.SetBasicAuth("user", "secret")
+			}
+			if test.bearer {
+				idp.SetClaims(oidctest.Claims{Subject: "user-1", Groups: test.groups})
+				req.Header.Set("Authorization", "Bearer "+idp.IDToken("bindplane-cli", ""))
+			}
+			if test.session {
+				req.AddCookie(sessionCookie)
+			}
+			if test.csrfToken != "" {
+				req.Header.Set(sessions.CSRFHeader, test.csrfToken)
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			require.Equal(t, test.expectStatus, w.Code)
+		})
+	}
+}
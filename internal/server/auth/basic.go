@@ -18,6 +18,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"github.com/observiq/bindplane-op/internal/server"
+	"github.com/observiq/bindplane-op/model"
 )
 
 // CheckBasic checks the basic authentication for a request and sets
@@ -36,5 +37,6 @@ func CheckBasic(server server.BindPlane) gin.HandlerFunc {
 		}
 
 		c.Set("authenticated", true)
+		c.Set(RoleKey, model.RoleAdmin)
 	}
 }
@@ -0,0 +1,50 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/observiq/bindplane-op/internal/server"
+	"github.com/observiq/bindplane-op/internal/server/oidc"
+)
+
+// CheckBearer checks for an ID token issued by the OpenID Connect provider in
+// the Authorization header and sets authenticated to true and the role of the
+// user if it is valid. If the header is not set or the token is invalid it
+// goes to the next handler.
+func CheckBearer(server server.BindPlane, provider *oidc.Provider) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		token := strings.TrimPrefix(header, "Bearer ")
+		if token == header || token == "" {
+			c.Next()
+			return
+		}
+
+		identity, err := provider.Verify(c.Request.Context(), token)
+		if err != nil {
+			server.Logger().Debug("invalid bearer token", zap.Error(err))
+			c.Next()
+			return
+		}
+
+		c.Set("authenticated", true)
+		c.Set(RoleKey, identity.Role)
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/observiq/bindplane-op/internal/server/sessions"
+	"github.com/observiq/bindplane-op/model"
+)
+
+// UserSessionKey is the context key of the *model.UserSession of requests
+// authenticated with a session cookie
+const UserSessionKey = "userSession"
+
+type csrfContextKey struct{}
+
+// CheckCSRF verifies the CSRF token of requests authenticated with a session
+// cookie. Requests authenticated with basic auth or a bearer token can't be
+// forged by another site and don't need a token. The result is saved in the
+// request context for RequireCSRF and CSRFVerified.
+func CheckCSRF() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		verified := true
+		if value, ok := c.Get(UserSessionKey); ok {
+			verified = value.(*model.UserSession).VerifyCSRF(c.GetHeader(sessions.CSRFHeader))
+		}
+		c.Request = c.Request.WithContext(WithCSRFVerified(c.Request.Context(), verified))
+	}
+}
+
+// WithCSRFVerified returns a context with the result of CheckCSRF
+func WithCSRFVerified(ctx context.Context, verified bool) context.Context {
+	return context.WithValue(ctx, csrfContextKey{}, verified)
+}
+
+// RequireCSRF aborts requests that modify resources if they did not pass
+// CheckCSRF.
+func RequireCSRF() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if readOnlyMethod(c.Request.Method) {
+			return
+		}
+		if !CSRFVerified(c.Request.Context()) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"errors": []string{"missing or invalid csrf token"}})
+			return
+		}
+	}
+}
+
+// CSRFVerified returns false if the request of the context is authenticated
+// with a session cookie and does not have the CSRF token of the session. It is
+// used by handlers like GraphQL that can't tell from the method whether a
+// request modifies resources.
+func CSRFVerified(ctx context.Context) bool {
+	verified, ok := ctx.Value(csrfContextKey{}).(bool)
+	return !ok || verified
+}
+
+func readOnlyMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
@@ -19,6 +19,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/observiq/bindplane-op/internal/server"
+	"github.com/observiq/bindplane-op/internal/server/oidc"
 )
 
 // RequireLogin should be the last middleware in the middleware chain.
@@ -32,11 +33,13 @@ func RequireLogin() gin.HandlerFunc {
 	}
 }
 
-// Chain returns the ordered slice of authentication middleware.
-func Chain(server server.BindPlane) []gin.HandlerFunc {
-	return []gin.HandlerFunc{
-		CheckBasic(server),
-		CheckSession(server),
-		RequireLogin(),
+// Chain returns the ordered slice of authentication middleware. If provider is not nil, ID tokens issued by the
+// OpenID Connect provider are accepted as bearer tokens. Requests authenticated with a session cookie have their CSRF
+// token checked, see RequireCSRF, and the role of the user is saved in the request context, see CanWrite.
+func Chain(server server.BindPlane, provider *oidc.Provider) []gin.HandlerFunc {
+	chain := []gin.HandlerFunc{CheckBasic(server)}
+	if provider != nil {
+		chain = append(chain, CheckBearer(server, provider))
 	}
+	return append(chain, CheckSession(server), RequireLogin(), SaveRole(), CheckCSRF())
 }
@@ -0,0 +1,79 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/observiq/bindplane-op/model"
+)
+
+// RoleKey is the context key of the model.Role of the authenticated user
+const RoleKey = "role"
+
+type roleContextKey struct{}
+
+// SaveRole saves the role of the authenticated user in the request context for
+// CanWrite. It must follow the authentication middleware.
+func SaveRole() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if role, ok := c.Get(RoleKey); ok {
+			c.Request = c.Request.WithContext(WithRole(c.Request.Context(), role.(model.Role)))
+		}
+	}
+}
+
+// WithRole returns a context with the role of the authenticated user
+func WithRole(ctx context.Context, role model.Role) context.Context {
+	return context.WithValue(ctx, roleContextKey{}, role)
+}
+
+// CanWrite returns false if the request of the context is authenticated with a
+// role that can't modify resources. It is used by handlers like GraphQL that
+// can't tell from the method whether a request modifies resources.
+func CanWrite(ctx context.Context) bool {
+	role, ok := ctx.Value(roleContextKey{}).(model.Role)
+	return !ok || role.CanWrite()
+}
+
+// RequireWrite aborts requests that modify resources if the authenticated
+// user has a role that can't modify resources. It must follow the
+// authentication middleware.
+func RequireWrite() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if readOnlyMethod(c.Request.Method) {
+			return
+		}
+
+		if role, ok := c.Get(RoleKey); !ok || !role.(model.Role).CanWrite() {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+	}
+}
+
+// RequireAdmin aborts requests if the authenticated user is not an admin. It
+// must follow the authentication middleware.
+func RequireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if role, ok := c.Get(RoleKey); !ok || role.(model.Role) != model.RoleAdmin {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+	}
+}
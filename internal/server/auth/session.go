@@ -23,8 +23,9 @@ import (
 )
 
 // CheckSession checks to see if the attached cookie session is authenticated
-// and if so sets authenticated to true on the context.  If not authenticated it
-// goes to the next handler.
+// and if so sets authenticated to true and the UserSession on the context.
+// Each request extends the idle timeout of the session. If not authenticated
+// it goes to the next handler.
 func CheckSession(server server.BindPlane) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		session, err := server.Store().UserSessions().Get(c.Request, sessions.CookieName)
@@ -49,6 +50,21 @@ func CheckSession(server server.BindPlane) gin.HandlerFunc {
 			return
 		}
 
-		c.Keys["authenticated"] = true
+		// The session must have a record that has not expired or been revoked. Sessions created before records were
+		// added don't have one and must log in again.
+		id, _ := session.Values["id"].(string)
+		record, err := sessions.Lookup(c.Request.Context(), server, id, true)
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+		if record == nil {
+			c.Next()
+			return
+		}
+
+		c.Set("authenticated", true)
+		c.Set(RoleKey, record.Role)
+		c.Set(UserSessionKey, record)
 	}
 }
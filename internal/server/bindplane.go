@@ -19,6 +19,7 @@ import (
 
 	"github.com/observiq/bindplane-op/common"
 	"github.com/observiq/bindplane-op/internal/agent"
+	"github.com/observiq/bindplane-op/internal/cluster"
 	"github.com/observiq/bindplane-op/internal/store"
 )
 
@@ -36,9 +37,10 @@ type BindPlane interface {
 	Logger() *zap.Logger
 }
 
-// NewBindPlane TODO(doc)
-func NewBindPlane(config *common.Server, logger *zap.Logger, s store.Store, versions agent.Versions) (BindPlane, error) {
-	manager, err := NewManager(config, s, versions, logger)
+// NewBindPlane creates a BindPlane using the specified store and versions. The leadership identifies this node in a
+// cluster and may be nil if this is the only node.
+func NewBindPlane(config *common.Server, logger *zap.Logger, s store.Store, versions agent.Versions, leadership cluster.Leadership) (BindPlane, error) {
+	manager, err := NewManager(config, s, versions, logger, leadership)
 	if err != nil {
 		return nil, err
 	}
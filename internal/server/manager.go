@@ -16,6 +16,9 @@ package server
 
 import (
 	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
 	"math"
 	"sync"
 	"time"
@@ -26,6 +29,8 @@ import (
 
 	"github.com/observiq/bindplane-op/common"
 	"github.com/observiq/bindplane-op/internal/agent"
+	"github.com/observiq/bindplane-op/internal/agentca"
+	"github.com/observiq/bindplane-op/internal/cluster"
 	"github.com/observiq/bindplane-op/internal/eventbus"
 	"github.com/observiq/bindplane-op/internal/store"
 	"github.com/observiq/bindplane-op/model"
@@ -36,10 +41,29 @@ var tracer = otel.Tracer("bindplane/manager")
 const (
 	// AgentCleanupInterval is the default agent cleanup interval.
 	AgentCleanupInterval = time.Minute
-	// AgentCleanupTTL is the default agent cleanup time to live.
-	AgentCleanupTTL = 15 * time.Minute
 	// AgentHeartbeatInterval is the default interval for the heartbeat sent to the agent to keep the websocket live.
 	AgentHeartbeatInterval = 30 * time.Second
+	// AgentCertificateRenewalInterval is the interval at which the certificates of connected agents are checked for
+	// renewal.
+	AgentCertificateRenewalInterval = time.Hour
+)
+
+var (
+	// ErrNoAgentConflict is returned when resolving the conflict of an agent that does not have a conflict
+	ErrNoAgentConflict = errors.New("agent does not have a conflict")
+
+	// ErrAgentCertificateRequired is returned when an agent that was issued a certificate connects without it and
+	// certificates are required
+	ErrAgentCertificateRequired = errors.New("agent certificate required")
+
+	// ErrAgentCertificateMismatch is returned when an agent connects with a certificate issued to another agent
+	ErrAgentCertificateMismatch = errors.New("agent certificate was issued to another agent")
+
+	// ErrAgentCertificateRevoked is returned when an agent connects with a certificate that was revoked or replaced
+	ErrAgentCertificateRevoked = errors.New("agent certificate was revoked")
+
+	// ErrAgentCADisabled is returned when revoking an agent certificate while the agent CA is disabled
+	ErrAgentCADisabled = errors.New("agent ca is not enabled")
 )
 
 // Manager manages agent connects and communications with them
@@ -54,39 +78,130 @@ type Manager interface {
 	UpsertAgent(ctx context.Context, agentID string, updater store.AgentUpdater) (*model.Agent, error)
 	// AgentUpdates returns the updates that should be applied to an agent based on the current bindplane configuration
 	AgentUpdates(ctx context.Context, agent *model.Agent) (*AgentUpdates, error)
-	// VerifySecretKey checks to see if the specified secretKey matches configured secretKey
-	VerifySecretKey(ctx context.Context, secretKey string) bool
+	// VerifySecretKey checks to see if the specified secretKey is the credential issued to the agent, a valid enrollment
+	// token, or the configured secretKey
+	VerifySecretKey(ctx context.Context, agentID string, secretKey string) bool
+	// AgentCredential returns the credential issued to the agent or nil if there is no credential
+	AgentCredential(ctx context.Context, agentID string) (*model.AgentCredential, error)
+	// IssueAgentCredential issues a new credential secret to the agent and returns it. The previous secret is accepted
+	// until the agent authenticates with the new secret.
+	IssueAgentCredential(ctx context.Context, agentID string) (string, error)
+	// PendingApproval returns true if the agent must be approved before it receives configuration. When approval is
+	// required, new agents that have not been approved are recorded as pending.
+	PendingApproval(ctx context.Context, agentID string) (bool, error)
+	// ApproveAgents approves the pending agents with the specified IDs, sends them their configuration, and returns the
+	// IDs of the agents that were approved
+	ApproveAgents(ctx context.Context, agentIDs []string) ([]string, error)
+	// DeniedAgent returns the AgentDenyRule that matches the agent or the stored agent with the same ID or nil if the
+	// agent is not denied
+	DeniedAgent(ctx context.Context, agent *model.Agent) (*model.AgentDenyRule, error)
+	// DenyAgents adds the AgentDenyRule, removes the agents matching the rule, and returns the IDs of the removed
+	// agents. Removing the agents disconnects them from every node.
+	DenyAgents(ctx context.Context, rule *model.AgentDenyRule) ([]string, error)
+	// Connected returns true if the agent is connected to this node or another node
+	Connected(agentID string) bool
+	// ReportAgentConflict is called by a Protocol when another host connects to this node with the ID of an agent that
+	// is already connected. The agent is given the Conflict status until the conflict is resolved.
+	ReportAgentConflict(ctx context.Context, agentID string, conflict *model.AgentConflict) error
+	// ResolveAgentConflict assigns a new ID to each host that connected with the ID of the agent while it was already
+	// connected and clears the conflict. It returns ErrNoAgentConflict if the agent does not have a conflict.
+	ResolveAgentConflict(ctx context.Context, agentID string) error
+	// AgentCA returns the built-in certificate authority that issues agent certificates or nil if it is disabled
+	AgentCA() *agentca.Authority
+	// VerifyAgentCertificate returns an error if the client certificate presented by the agent was issued by the agent
+	// CA but does not belong to the agent or was revoked. certificate is nil if the agent did not present a certificate.
+	VerifyAgentCertificate(ctx context.Context, agentID string, certificate *x509.Certificate) error
+	// IssueAgentCertificate issues a new client certificate to the agent. The previous certificate is accepted until the
+	// agent connects with the new certificate. It returns nil if the agent CA is disabled.
+	IssueAgentCertificate(ctx context.Context, agentID string) (*agentca.Certificate, error)
+	// RevokeAgentCertificate revokes the client certificates issued to the agent. If the agent is connected, it is sent
+	// a new credential and certificate. It returns ErrAgentCADisabled if the agent CA is disabled.
+	RevokeAgentCertificate(ctx context.Context, agentID string) error
 	// ResourceStore provides access to the store to render configurations
 	ResourceStore() model.ResourceStore
 	// AgentVersion returns information about a version of an agent
 	AgentVersion(ctx context.Context, version string) (*model.AgentVersion, error)
+	// AgentConnected is called by a Protocol when an agent connects to this node
+	AgentConnected(ctx context.Context, agentID string)
+	// AgentDisconnected is called by a Protocol when an agent disconnects from this node
+	AgentDisconnected(ctx context.Context, agentID string)
+	// SendAgentUpdates sends the updates to the agent, forwarding them to the node connected to the agent if it isn't
+	// connected to this node. It returns ErrAgentNotConnected if the agent isn't connected to any node.
+	SendAgentUpdates(ctx context.Context, agent *model.Agent, updates *AgentUpdates) error
 }
 
 // ----------------------------------------------------------------------
 
 type manager struct {
-	// agentCleanupTicker   *time.Ticker
 	// agentHeartbeatTicker *time.Ticker
-	store     store.Store
-	versions  agent.Versions
-	logger    *zap.Logger
-	protocols []Protocol
-	secretKey string
+	store      store.Store
+	versions   agent.Versions
+	logger     *zap.Logger
+	protocols  []Protocol
+	secretKey  string
+	router     *agentRouter
+	leadership cluster.Leadership
+
+	// requireApproval is true if new agents must be approved before they receive configuration
+	requireApproval bool
+	// disableSecretKeyAfterEnrollment is true if agents enrolled with a token can't use the secret key
+	disableSecretKeyAfterEnrollment bool
+	// requireEnrollmentToken is true if new agents can't use the secret key
+	requireEnrollmentToken bool
+	// agentCleanupTTL is the time after which disconnected agents are removed, 0 to keep disconnected agents
+	agentCleanupTTL time.Duration
+
+	// agentCA issues agent certificates and is nil if the agent CA is disabled
+	agentCA *agentca.Authority
+	// requireCertificate is true if agents that were issued a certificate must connect with it
+	requireCertificate bool
 }
 
 var _ Manager = (*manager)(nil)
 
-// NewManager returns a new implementation of the Manager interface
-func NewManager(config *common.Server, store store.Store, versions agent.Versions, logger *zap.Logger) (Manager, error) {
-	return &manager{
-		// agentCleanupTicker:   time.NewTicker(AgentCleanupInterval),
+// NewManager returns a new implementation of the Manager interface. The leadership identifies this node and determines
+// if it runs singleton jobs like disconnected agent cleanup. If nil, this node is considered the only node.
+func NewManager(config *common.Server, store store.Store, versions agent.Versions, logger *zap.Logger, leadership cluster.Leadership) (Manager, error) {
+	var bus cluster.Bus
+	if store != nil {
+		bus = store.ClusterBus()
+	}
+	if bus == nil {
+		bus = cluster.NewLocalBus()
+	}
+	if leadership == nil {
+		leadership = cluster.Standalone(cluster.NewNodeID())
+	}
+	m := newManager(config, store, versions, logger, leadership, bus)
+	if config.AgentCAEnabled() {
+		authority, err := agentca.Load(config.AgentCACertificatePath(), config.AgentCAPrivateKeyPath(), config.AgentCertificateTTL())
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the agent ca: %w", err)
+		}
+		m.agentCA = authority
+		m.requireCertificate = config.AgentCA.RequireCertificate
+	}
+	return m, nil
+}
+
+func newManager(config *common.Server, store store.Store, versions agent.Versions, logger *zap.Logger, leadership cluster.Leadership, bus cluster.Bus) *manager {
+	m := &manager{
 		// agentHeartbeatTicker: time.NewTicker(AgentHeartbeatInterval),
-		store:     store,
-		versions:  versions,
-		logger:    logger,
-		protocols: []Protocol{},
-		secretKey: config.SecretKey,
-	}, nil
+		store:      store,
+		versions:   versions,
+		logger:     logger,
+		protocols:  []Protocol{},
+		secretKey:  config.SecretKey,
+		leadership: leadership,
+
+		requireApproval: config.RequireApproval,
+		agentCleanupTTL: config.DisconnectedAgentTTL,
+
+		disableSecretKeyAfterEnrollment: config.DisableSecretKeyAfterEnrollment,
+		requireEnrollmentToken:          config.RequireEnrollmentToken,
+	}
+	m.router = newAgentRouter(leadership.NodeID(), bus, m, logger)
+	return m
 }
 
 func (m *manager) EnableProtocol(protocol Protocol) {
@@ -98,10 +213,27 @@ func (m *manager) Start(ctx context.Context) {
 	updatesChannel, unsubscribe := eventbus.Subscribe(m.store.Updates(), eventbus.WithChannel(make(chan *store.Updates, 10_000)))
 	defer unsubscribe()
 
+	m.router.start(ctx)
+
+	// disconnected agents are only removed if a TTL is configured
+	var cleanupAgents <-chan time.Time
+	if m.agentCleanupTTL > 0 {
+		cleanupTicker := time.NewTicker(AgentCleanupInterval)
+		defer cleanupTicker.Stop()
+		cleanupAgents = cleanupTicker.C
+	}
+
+	// each node renews the certificates of the agents connected to it
+	var renewAgentCertificates <-chan time.Time
+	if m.agentCA != nil {
+		renewalTicker := time.NewTicker(AgentCertificateRenewalInterval)
+		defer renewalTicker.Stop()
+		renewAgentCertificates = renewalTicker.C
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
-			// m.agentCleanupTicker.Stop()
 			// m.agentHeartbeatTicker.Stop()
 			return
 
@@ -113,10 +245,16 @@ func (m *manager) Start(ctx context.Context) {
 			)
 			m.handleUpdates(updates)
 
-			// TODO: determine if these need to be replaced and if so, replace them
-			// case <-m.agentCleanupTicker.C:
-			// 	m.handleAgentCleanup()
+		case <-cleanupAgents:
+			// cleanup is a singleton job that only runs on the leader
+			if m.leadership.IsLeader() {
+				m.handleAgentCleanup()
+			}
+
+		case <-renewAgentCertificates:
+			m.handleAgentCertificateRenewal(ctx)
 
+			// TODO: determine if this needs to be replaced and if so, replace it
 			// case <-m.agentHeartbeatTicker.C:
 			// 	m.handleAgentHeartbeat()
 		}
@@ -221,8 +359,8 @@ func (m *manager) handleUpdates(updates *store.Updates) {
 			continue
 		}
 
-		// only consider connected agents
-		if !m.connected(agent.ID) {
+		// only consider connected agents that are not pending approval
+		if !m.connected(agent.ID) || agent.Status == model.Pending {
 			continue
 		}
 
@@ -265,6 +403,9 @@ func (m *manager) handleUpdates(updates *store.Updates) {
 				m.logger.Error("unable to apply configuration to agent", zap.String("agentID", agentID), zap.String("configuration.name", configuration.Name()), zap.Error(err))
 				continue
 			}
+			if agent == nil || agent.Status == model.Pending {
+				continue
+			}
 
 			// TODO(andy): support multiple matches with precedence
 			if event.Type == store.EventTypeRemove {
@@ -305,12 +446,445 @@ func (m *manager) AgentUpdates(ctx context.Context, agent *model.Agent) (*AgentU
 	}, nil
 }
 
-// VerifySecretKey checks to see if the specified secretKey matches configured secretKey. If the BindPlane server does not
-// have a configured secretKey, this returns true.
-func (m *manager) VerifySecretKey(ctx context.Context, secretKey string) bool {
+// VerifySecretKey checks to see if the specified secretKey is the credential issued to the agent, a valid enrollment
+// token, or the configured secretKey. If the BindPlane server does not have a configured secretKey, this returns true.
+//
+// The first time an agent uses an enrollment token, a use of the token is recorded and the agent is associated with
+// the token so that it can reconnect with the same token until a credential is issued.
+func (m *manager) VerifySecretKey(ctx context.Context, agentID string, secretKey string) bool {
+	ctx, span := tracer.Start(ctx, "manager/VerifySecretKey")
+	defer span.End()
+
+	// credentials and enrollment tokens are only available for agents that identify themselves
+	identified := agentID != "" && m.store != nil
+
+	var credential *model.AgentCredential
+	if identified {
+		var err error
+		credential, err = m.store.AgentCredential(ctx, agentID)
+		if err != nil {
+			m.logger.Error("unable to get the agent credential", zap.String("agentID", agentID), zap.Error(err))
+		}
+	}
+
+	// agents that were issued a credential must use it
+	if credential != nil && credential.Issued() {
+		ok, current := credential.Verify(secretKey)
+		if ok && current && credential.PreviousHash != "" {
+			// the agent is using the new secret, stop accepting the previous secret
+			_, err := m.store.UpsertAgentCredential(ctx, agentID, func(current *model.AgentCredential) error {
+				current.PreviousHash = ""
+				return nil
+			})
+			if err != nil {
+				m.logger.Error("unable to remove the previous agent credential", zap.String("agentID", agentID), zap.Error(err))
+			}
+		}
+		return ok
+	}
+
+	if tokenID, secret, ok := model.ParseEnrollmentToken(secretKey); ok && identified {
+		return m.verifyEnrollmentToken(ctx, agentID, credential, tokenID, secret)
+	}
+
+	if m.disableSecretKeyAfterEnrollment && credential != nil && credential.TokenID != "" {
+		// agents enrolled with a token must continue to use it
+		return false
+	}
+	if m.requireEnrollmentToken && !m.knownAgent(agentID) {
+		// new agents must enroll with a token
+		return false
+	}
 	return m.secretKey == "" || m.secretKey == secretKey
 }
 
+// knownAgent returns true if the agent has connected before
+func (m *manager) knownAgent(agentID string) bool {
+	if agentID == "" || m.store == nil {
+		return false
+	}
+	agent, err := m.store.Agent(agentID)
+	if err != nil {
+		m.logger.Error("unable to get the agent", zap.String("agentID", agentID), zap.Error(err))
+		return false
+	}
+	return agent != nil
+}
+
+func (m *manager) verifyEnrollmentToken(ctx context.Context, agentID string, credential *model.AgentCredential, tokenID, secret string) bool {
+	token, err := m.store.EnrollmentToken(ctx, tokenID)
+	if err != nil {
+		m.logger.Error("unable to get the enrollment token", zap.String("tokenID", tokenID), zap.Error(err))
+		return false
+	}
+	if token == nil || !token.Verify(secret) {
+		return false
+	}
+
+	// agents reconnecting with the token they enrolled with don't use the token again. expiration and uses only limit new
+	// enrollments, deleting the token revokes it.
+	if credential != nil && credential.TokenID == token.ID {
+		return true
+	}
+
+	token, err = m.store.UpdateEnrollmentToken(ctx, tokenID, func(current *model.EnrollmentToken) error {
+		if err := current.Valid(time.Now()); err != nil {
+			return err
+		}
+		current.Uses++
+		return nil
+	})
+	if err != nil {
+		m.logger.Info("agent rejected enrollment token", zap.String("agentID", agentID), zap.String("tokenID", tokenID), zap.Error(err))
+		return false
+	}
+
+	_, err = m.store.UpsertAgentCredential(ctx, agentID, func(current *model.AgentCredential) error {
+		current.TokenID = token.ID
+		current.Labels = token.Labels
+		return nil
+	})
+	if err != nil {
+		m.logger.Error("unable to save the agent credential", zap.String("agentID", agentID), zap.Error(err))
+		return false
+	}
+	return true
+}
+
+// AgentCredential returns the credential issued to the agent or nil if there is no credential
+func (m *manager) AgentCredential(ctx context.Context, agentID string) (*model.AgentCredential, error) {
+	return m.store.AgentCredential(ctx, agentID)
+}
+
+// IssueAgentCredential issues a new credential secret to the agent and returns it. The previous secret is accepted
+// until the agent authenticates with the new secret.
+func (m *manager) IssueAgentCredential(ctx context.Context, agentID string) (string, error) {
+	var secret string
+	_, err := m.store.UpsertAgentCredential(ctx, agentID, func(current *model.AgentCredential) (err error) {
+		secret, err = current.Issue()
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return secret, nil
+}
+
+// PendingApproval returns true if the agent must be approved before it receives configuration. When approval is
+// required, new agents that have not been approved are recorded as pending. Agents that were already known to BindPlane
+// when approval was first required are not pending.
+func (m *manager) PendingApproval(ctx context.Context, agentID string) (bool, error) {
+	if !m.requireApproval {
+		return false, nil
+	}
+
+	credential, err := m.store.AgentCredential(ctx, agentID)
+	if err != nil {
+		return false, err
+	}
+	if credential != nil && (credential.Pending || credential.Approved()) {
+		return credential.Pending, nil
+	}
+
+	agent, err := m.store.Agent(agentID)
+	if err != nil {
+		return false, err
+	}
+	if agent != nil && agent.Status != model.Pending {
+		return false, nil
+	}
+
+	m.logger.Info("new agent is pending approval", zap.String("agentID", agentID))
+	_, err = m.store.UpsertAgentCredential(ctx, agentID, func(current *model.AgentCredential) error {
+		current.Pending = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ApproveAgents approves the pending agents with the specified IDs, sends them their configuration, and returns the
+// IDs of the agents that were approved
+func (m *manager) ApproveAgents(ctx context.Context, agentIDs []string) ([]string, error) {
+	ctx, span := tracer.Start(ctx, "manager/ApproveAgents")
+	defer span.End()
+
+	approved := []string{}
+	for _, agentID := range agentIDs {
+		credential, err := m.store.AgentCredential(ctx, agentID)
+		if err != nil {
+			return approved, err
+		}
+		if credential == nil || !credential.Pending {
+			continue
+		}
+		_, err = m.store.UpsertAgentCredential(ctx, agentID, func(current *model.AgentCredential) error {
+			current.Approve()
+			return nil
+		})
+		if err != nil {
+			return approved, err
+		}
+		approved = append(approved, agentID)
+		m.logger.Info("agent approved", zap.String("agentID", agentID))
+
+		// pending agents that were cleaned up receive their configuration when they reconnect
+		agent, err := m.store.Agent(agentID)
+		if err != nil || agent == nil || agent.Status != model.Pending {
+			continue
+		}
+		agent, err = m.store.UpsertAgent(ctx, agentID, func(current *model.Agent) {
+			if current.Status == model.Pending {
+				current.Status = model.Connected
+			}
+		})
+		if err != nil {
+			m.logger.Error("unable to update the status of the approved agent", zap.String("agentID", agentID), zap.Error(err))
+			continue
+		}
+		updates, err := m.AgentUpdates(ctx, agent)
+		if err != nil {
+			m.logger.Error("unable to get updates for the approved agent", zap.String("agentID", agentID), zap.Error(err))
+			continue
+		}
+		if err := m.SendAgentUpdates(ctx, agent, updates); err != nil && !errors.Is(err, ErrAgentNotConnected) {
+			m.logger.Error("unable to send configuration to the approved agent", zap.String("agentID", agentID), zap.Error(err))
+		}
+	}
+	return approved, nil
+}
+
+// DeniedAgent returns the AgentDenyRule that matches the agent or the stored agent with the same ID or nil if the
+// agent is not denied. The stored agent is checked because agents only report their MAC address after connecting.
+func (m *manager) DeniedAgent(ctx context.Context, agent *model.Agent) (*model.AgentDenyRule, error) {
+	if m.store == nil {
+		return nil, nil
+	}
+	rules, err := m.store.AgentDenyRules(ctx)
+	if err != nil || len(rules) == 0 {
+		return nil, err
+	}
+	if rule := model.MatchingAgentDenyRule(rules, agent); rule != nil {
+		return rule, nil
+	}
+	if agent.ID == "" {
+		return nil, nil
+	}
+	stored, err := m.store.Agent(agent.ID)
+	if err != nil {
+		return nil, err
+	}
+	return model.MatchingAgentDenyRule(rules, stored), nil
+}
+
+// DenyAgents adds the AgentDenyRule, removes the agents matching the rule, and returns the IDs of the removed agents.
+// Removing the agents disconnects them from every node.
+func (m *manager) DenyAgents(ctx context.Context, rule *model.AgentDenyRule) ([]string, error) {
+	ctx, span := tracer.Start(ctx, "manager/DenyAgents")
+	defer span.End()
+
+	if err := m.store.AddAgentDenyRule(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	agents, err := m.store.Agents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ids := []string{}
+	for _, agent := range agents {
+		if rule.Matches(agent) {
+			ids = append(ids, agent.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return ids, nil
+	}
+
+	m.logger.Info("removing denied agents", zap.String("rule", rule.ID()), zap.Strings("agentIDs", ids))
+	if _, err := m.store.DeleteAgents(ctx, ids); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// Connected returns true if the agent is connected to this node or another node
+func (m *manager) Connected(agentID string) bool {
+	if m.connected(agentID) {
+		return true
+	}
+	_, ok := m.router.node(agentID)
+	return ok
+}
+
+// ReportAgentConflict is called by a Protocol when another host connects to this node with the ID of an agent that is
+// already connected. The agent is given the Conflict status until the conflict is resolved.
+func (m *manager) ReportAgentConflict(ctx context.Context, agentID string, conflict *model.AgentConflict) error {
+	ctx, span := tracer.Start(ctx, "manager/ReportAgentConflict")
+	defer span.End()
+
+	conflict.NodeID = m.leadership.NodeID()
+	if conflict.DetectedAt.IsZero() {
+		conflict.DetectedAt = time.Now()
+	}
+	m.logger.Warn("another host connected with the ID of a connected agent",
+		zap.String("agentID", agentID),
+		zap.String("hostname", conflict.HostName),
+		zap.String("macAddress", conflict.MacAddress),
+		zap.String("remoteAddress", conflict.RemoteAddress),
+	)
+	_, err := m.store.UpsertAgent(ctx, agentID, func(current *model.Agent) {
+		current.SetConflict(conflict)
+	})
+	return err
+}
+
+// ResolveAgentConflict assigns a new ID to each host that connected with the ID of the agent while it was already
+// connected and clears the conflict. It returns ErrNoAgentConflict if the agent does not have a conflict.
+func (m *manager) ResolveAgentConflict(ctx context.Context, agentID string) error {
+	ctx, span := tracer.Start(ctx, "manager/ResolveAgentConflict")
+	defer span.End()
+
+	agent, err := m.store.Agent(agentID)
+	if err != nil {
+		return err
+	}
+	if agent == nil || agent.Conflict == nil {
+		return ErrNoAgentConflict
+	}
+
+	// the conflicting hosts are connected to the node that detected the conflict, which may not be the node connected
+	// to the agent
+	updates := &AgentUpdates{AssignNewIDs: true}
+	if nodeID := agent.Conflict.NodeID; nodeID == "" || nodeID == m.leadership.NodeID() {
+		err = m.sendAgentUpdates(ctx, agent, updates)
+	} else {
+		err = m.router.forwardToNode(ctx, nodeID, agent, updates)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to assign new IDs to the hosts conflicting with agent %s: %w", agentID, err)
+	}
+
+	_, err = m.store.UpsertAgent(ctx, agentID, func(current *model.Agent) {
+		current.ClearConflict()
+	})
+	return err
+}
+
+// AgentCA returns the built-in certificate authority that issues agent certificates or nil if it is disabled
+func (m *manager) AgentCA() *agentca.Authority {
+	return m.agentCA
+}
+
+// VerifyAgentCertificate returns an error if the client certificate presented by the agent was issued by the agent CA
+// but does not belong to the agent or was revoked. Certificates from other authorities configured for mTLS are verified
+// by the TLS handshake and are not bound to agents.
+func (m *manager) VerifyAgentCertificate(ctx context.Context, agentID string, certificate *x509.Certificate) error {
+	if m.agentCA == nil {
+		return nil
+	}
+	ctx, span := tracer.Start(ctx, "manager/VerifyAgentCertificate")
+	defer span.End()
+
+	if certificate != nil && !m.agentCA.Issued(certificate) {
+		certificate = nil
+	}
+
+	var credential *model.AgentCredential
+	if agentID != "" {
+		var err error
+		credential, err = m.store.AgentCredential(ctx, agentID)
+		if err != nil {
+			return fmt.Errorf("unable to get the agent credential: %w", err)
+		}
+	}
+
+	if certificate == nil {
+		// agents with a revoked certificate still require one
+		if m.requireCertificate && credential != nil && credential.CertificateRequired {
+			return ErrAgentCertificateRequired
+		}
+		return nil
+	}
+
+	if err := m.agentCA.Verify(certificate); err != nil {
+		return err
+	}
+	if certificate.Subject.CommonName != agentID {
+		return fmt.Errorf("%w: %s", ErrAgentCertificateMismatch, certificate.Subject.CommonName)
+	}
+	if credential == nil {
+		return ErrAgentCertificateRevoked
+	}
+
+	ok, current := credential.VerifyCertificate(agentca.Serial(certificate))
+	if !ok {
+		return ErrAgentCertificateRevoked
+	}
+	if current && credential.PreviousCertificateSerial != "" {
+		// the agent is using the new certificate, stop accepting the previous certificate
+		_, err := m.store.UpsertAgentCredential(ctx, agentID, func(current *model.AgentCredential) error {
+			current.PreviousCertificateSerial = ""
+			return nil
+		})
+		if err != nil {
+			m.logger.Error("unable to remove the previous agent certificate", zap.String("agentID", agentID), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// IssueAgentCertificate issues a new client certificate to the agent. The previous certificate is accepted until the
+// agent connects with the new certificate. It returns nil if the agent CA is disabled.
+func (m *manager) IssueAgentCertificate(ctx context.Context, agentID string) (*agentca.Certificate, error) {
+	if m.agentCA == nil {
+		return nil, nil
+	}
+	certificate, err := m.agentCA.Issue(agentID)
+	if err != nil {
+		return nil, err
+	}
+	_, err = m.store.UpsertAgentCredential(ctx, agentID, func(current *model.AgentCredential) error {
+		current.IssueCertificate(certificate.Serial, certificate.ExpiresAt)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return certificate, nil
+}
+
+// RevokeAgentCertificate revokes the client certificates issued to the agent. If the agent is connected, it is sent a
+// new credential and certificate. Otherwise it must be reinstalled to connect with a certificate again. It returns
+// ErrAgentCADisabled if the agent CA is disabled.
+func (m *manager) RevokeAgentCertificate(ctx context.Context, agentID string) error {
+	if m.agentCA == nil {
+		return ErrAgentCADisabled
+	}
+	ctx, span := tracer.Start(ctx, "manager/RevokeAgentCertificate")
+	defer span.End()
+
+	_, err := m.store.UpsertAgentCredential(ctx, agentID, func(current *model.AgentCredential) error {
+		current.RevokeCertificate()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	m.logger.Info("revoked agent certificate", zap.String("agentID", agentID))
+
+	if !m.Connected(agentID) {
+		return nil
+	}
+	agent, err := m.store.Agent(agentID)
+	if err != nil || agent == nil {
+		return err
+	}
+	return m.SendAgentUpdates(ctx, agent, &AgentUpdates{RotateCredential: true})
+}
+
 // ResourceStore provides access to the store to render configurations
 func (m *manager) ResourceStore() model.ResourceStore {
 	return m.store
@@ -326,6 +900,28 @@ func (m *manager) AgentVersion(ctx context.Context, version string) (*model.Agen
 	return m.versions.Version(version)
 }
 
+// AgentConnected is called by a Protocol when an agent connects to this node
+func (m *manager) AgentConnected(ctx context.Context, agentID string) {
+	m.router.announce(ctx, []string{agentID}, true)
+}
+
+// AgentDisconnected is called by a Protocol when an agent disconnects from this node
+func (m *manager) AgentDisconnected(ctx context.Context, agentID string) {
+	m.router.announce(ctx, []string{agentID}, false)
+}
+
+// SendAgentUpdates sends the updates to the agent, forwarding them to the node connected to the agent if it isn't
+// connected to this node. It returns ErrAgentNotConnected if the agent isn't connected to any node.
+func (m *manager) SendAgentUpdates(ctx context.Context, agent *model.Agent, updates *AgentUpdates) error {
+	ctx, span := tracer.Start(ctx, "manager/SendAgentUpdates")
+	defer span.End()
+
+	if m.connected(agent.ID) {
+		return m.sendAgentUpdates(ctx, agent, updates)
+	}
+	return m.router.forward(ctx, agent, updates)
+}
+
 // ----------------------------------------------------------------------
 
 // handleAgentCleanup removes disconnected agents from the store.
@@ -334,13 +930,37 @@ func (m *manager) handleAgentCleanup() {
 	defer span.End()
 
 	now := time.Now()
-	// TODO: in a cluster, move this to a job
-	err := m.store.CleanupDisconnectedAgents(now.Add(-AgentCleanupTTL))
+	err := m.store.CleanupDisconnectedAgents(now.Add(-m.agentCleanupTTL))
 	if err != nil {
 		m.logger.Error("error cleaning up disconnected agents", zap.Error(err))
 	}
 }
 
+// handleAgentCertificateRenewal sends a new credential and certificate to connected agents with certificates that are
+// due for renewal
+func (m *manager) handleAgentCertificateRenewal(ctx context.Context) {
+	ctx, span := tracer.Start(ctx, "manager/handleAgentCertificateRenewal")
+	defer span.End()
+
+	now := time.Now()
+	for _, agentID := range m.connectedAgentIDs(ctx) {
+		credential, err := m.store.AgentCredential(ctx, agentID)
+		if err != nil {
+			m.logger.Error("unable to get the agent credential", zap.String("agentID", agentID), zap.Error(err))
+			continue
+		}
+		if credential == nil || !credential.CertificateRenewalDue(now) {
+			continue
+		}
+		agent, err := m.store.Agent(agentID)
+		if err != nil || agent == nil {
+			continue
+		}
+		m.logger.Info("renewing agent certificate", zap.String("agentID", agentID))
+		m.updateAgent(ctx, agent, &AgentUpdates{RotateCredential: true})
+	}
+}
+
 func (m *manager) handleAgentHeartbeat() {
 	ctx, span := tracer.Start(context.TODO(), "manager/handleAgentHeartbeat")
 	defer span.End()
@@ -367,6 +987,7 @@ func (m *manager) handleAgentHeartbeat() {
 func (m *manager) disconnect(agentID string) bool {
 	for _, p := range m.protocols {
 		if p.Disconnect(agentID) {
+			m.router.announce(context.TODO(), []string{agentID}, false)
 			return true
 		}
 	}
@@ -397,10 +1018,18 @@ func (m *manager) connectedAgentIDs(ctx context.Context) []string {
 }
 
 func (m *manager) updateAgent(ctx context.Context, agent *model.Agent, updates *AgentUpdates) {
+	if err := m.sendAgentUpdates(ctx, agent, updates); err != nil {
+		m.logger.Error("unable to update agent", zap.String("agentID", agent.ID))
+	}
+}
+
+// sendAgentUpdates sends the updates to the agent using every protocol and returns the last error
+func (m *manager) sendAgentUpdates(ctx context.Context, agent *model.Agent, updates *AgentUpdates) error {
+	var lastErr error
 	for _, p := range m.protocols {
-		err := p.UpdateAgent(ctx, agent, updates)
-		if err != nil {
-			m.logger.Error("unable to update agent", zap.String("agentID", agent.ID))
+		if err := p.UpdateAgent(ctx, agent, updates); err != nil {
+			lastErr = err
 		}
 	}
+	return lastErr
 }
@@ -16,8 +16,15 @@ package server
 
 import (
 	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/observiq/bindplane-op/common"
+	"github.com/observiq/bindplane-op/internal/agentca"
+	"github.com/observiq/bindplane-op/internal/cluster"
 	"github.com/observiq/bindplane-op/internal/store"
 	"github.com/observiq/bindplane-op/model"
 	"github.com/stretchr/testify/mock"
@@ -40,13 +47,15 @@ var (
 		MaxEventsToMerge: 1,
 	}, logger)
 	testProtocol = &mockProtocol{}
-	testManager  = &manager{
-		store:     testMapstore,
-		logger:    logger,
-		protocols: []Protocol{testProtocol},
-	}
+	testManager  = newTestManager(testMapstore, "test", cluster.NewLocalBus(), testProtocol)
 )
 
+func newTestManager(s store.Store, nodeID string, bus cluster.Bus, protocols ...Protocol) *manager {
+	m := newManager(&common.Server{}, s, nil, logger, cluster.Standalone(nodeID), bus)
+	m.protocols = protocols
+	return m
+}
+
 func makeTestAgent(agentID string) *model.Agent {
 	agent, err := testMapstore.UpsertAgent(context.TODO(), agentID, func(agent *model.Agent) {})
 	if err != nil {
@@ -280,11 +289,285 @@ func TestManagerVerifySecretKey(t *testing.T) {
 			testManager := &manager{
 				secretKey: test.managerSecretKey,
 			}
-			require.Equal(t, test.expect, testManager.VerifySecretKey(context.TODO(), test.agentSecretKey))
+			require.Equal(t, test.expect, testManager.VerifySecretKey(context.TODO(), "", test.agentSecretKey))
 		})
 	}
 }
 
+func TestManagerVerifyEnrollmentToken(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewMapStore(ctx, store.Options{SessionsSecret: "super-secret-key", MaxEventsToMerge: 1}, logger)
+	m := newManager(&common.Server{SecretKey: "server-secret"}, s, nil, logger, cluster.Standalone("test"), cluster.NewLocalBus())
+
+	labels, err := model.LabelsFromSelector("env=prod")
+	require.NoError(t, err)
+	token, value, err := model.NewEnrollmentToken(labels, 1, 0)
+	require.NoError(t, err)
+	require.NoError(t, s.CreateEnrollmentToken(ctx, token))
+
+	expired, expiredValue, err := model.NewEnrollmentToken(model.Labels{}, 0, time.Hour)
+	require.NoError(t, err)
+	past := time.Now().Add(-time.Minute)
+	expired.ExpiresAt = &past
+	require.NoError(t, s.CreateEnrollmentToken(ctx, expired))
+
+	// wrong secret
+	require.False(t, m.VerifySecretKey(ctx, "agent-1", token.ID+".wrong"))
+	// token values require an agent ID
+	require.False(t, m.VerifySecretKey(ctx, "", value))
+	// expired
+	require.False(t, m.VerifySecretKey(ctx, "agent-1", expiredValue))
+
+	// first use enrolls the agent with the labels of the token
+	require.True(t, m.VerifySecretKey(ctx, "agent-1", value))
+	credential, err := m.AgentCredential(ctx, "agent-1")
+	require.NoError(t, err)
+	require.Equal(t, token.ID, credential.TokenID)
+	require.Equal(t, "prod", credential.Labels.Set["env"])
+	require.False(t, credential.Issued())
+
+	// the agent can reconnect with the same token
+	require.True(t, m.VerifySecretKey(ctx, "agent-1", value))
+
+	// no uses remaining for another agent
+	require.False(t, m.VerifySecretKey(ctx, "agent-2", value))
+	saved, err := s.EnrollmentToken(ctx, token.ID)
+	require.NoError(t, err)
+	require.Equal(t, 1, saved.Uses)
+
+	// the server secret key is still accepted
+	require.True(t, m.VerifySecretKey(ctx, "agent-2", "server-secret"))
+}
+
+func TestManagerIssueAgentCredential(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewMapStore(ctx, store.Options{SessionsSecret: "super-secret-key", MaxEventsToMerge: 1}, logger)
+	m := newManager(&common.Server{SecretKey: "server-secret"}, s, nil, logger, cluster.Standalone("test"), cluster.NewLocalBus())
+
+	first, err := m.IssueAgentCredential(ctx, "agent-1")
+	require.NoError(t, err)
+	require.True(t, m.VerifySecretKey(ctx, "agent-1", first))
+	require.False(t, m.VerifySecretKey(ctx, "agent-2", first))
+
+	// rotate, both secrets are accepted until the agent uses the new secret
+	second, err := m.IssueAgentCredential(ctx, "agent-1")
+	require.NoError(t, err)
+	require.NotEqual(t, first, second)
+	require.True(t, m.VerifySecretKey(ctx, "agent-1", first))
+	require.True(t, m.VerifySecretKey(ctx, "agent-1", second))
+	require.False(t, m.VerifySecretKey(ctx, "agent-1", first))
+}
+
+func TestManagerVerifyIssuedAgentCredential(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewMapStore(ctx, store.Options{SessionsSecret: "super-secret-key", MaxEventsToMerge: 1}, logger)
+	m := newManager(&common.Server{SecretKey: "server-secret"}, s, nil, logger, cluster.Standalone("test"), cluster.NewLocalBus())
+
+	labels, err := model.LabelsFromSelector("env=prod")
+	require.NoError(t, err)
+	token, value, err := model.NewEnrollmentToken(labels, 0, 0)
+	require.NoError(t, err)
+	require.NoError(t, s.CreateEnrollmentToken(ctx, token))
+
+	other, otherValue, err := model.NewEnrollmentToken(model.Labels{}, 0, 0)
+	require.NoError(t, err)
+	require.NoError(t, s.CreateEnrollmentToken(ctx, other))
+
+	require.True(t, m.VerifySecretKey(ctx, "agent-1", value))
+	issued, err := m.IssueAgentCredential(ctx, "agent-1")
+	require.NoError(t, err)
+	require.True(t, m.VerifySecretKey(ctx, "agent-1", issued))
+
+	// an agent with an issued credential can't use the secret key or an enrollment token
+	require.False(t, m.VerifySecretKey(ctx, "agent-1", "server-secret"))
+	require.False(t, m.VerifySecretKey(ctx, "agent-1", value))
+	require.False(t, m.VerifySecretKey(ctx, "agent-1", otherValue))
+
+	// the enrollment is unchanged
+	credential, err := m.AgentCredential(ctx, "agent-1")
+	require.NoError(t, err)
+	require.Equal(t, token.ID, credential.TokenID)
+	require.Equal(t, "prod", credential.Labels.Set["env"])
+}
+
+func TestManagerDisableSecretKeyAfterEnrollment(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewMapStore(ctx, store.Options{SessionsSecret: "super-secret-key", MaxEventsToMerge: 1}, logger)
+	config := &common.Server{SecretKey: "server-secret", DisableSecretKeyAfterEnrollment: true}
+	m := newManager(config, s, nil, logger, cluster.Standalone("test"), cluster.NewLocalBus())
+
+	token, value, err := model.NewEnrollmentToken(model.Labels{}, 0, 0)
+	require.NoError(t, err)
+	require.NoError(t, s.CreateEnrollmentToken(ctx, token))
+
+	require.True(t, m.VerifySecretKey(ctx, "agent-1", value))
+	require.False(t, m.VerifySecretKey(ctx, "agent-1", "server-secret"))
+	require.True(t, m.VerifySecretKey(ctx, "agent-1", value))
+
+	// agents that didn't enroll with a token can use the secret key
+	require.True(t, m.VerifySecretKey(ctx, "agent-2", "server-secret"))
+}
+
+func TestManagerRequireEnrollmentToken(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewMapStore(ctx, store.Options{SessionsSecret: "super-secret-key", MaxEventsToMerge: 1}, logger)
+	config := &common.Server{SecretKey: "server-secret", RequireEnrollmentToken: true}
+	m := newManager(config, s, nil, logger, cluster.Standalone("test"), cluster.NewLocalBus())
+
+	token, value, err := model.NewEnrollmentToken(model.Labels{}, 0, 0)
+	require.NoError(t, err)
+	require.NoError(t, s.CreateEnrollmentToken(ctx, token))
+
+	// new agents can't use the secret key
+	require.False(t, m.VerifySecretKey(ctx, "agent-1", "server-secret"))
+	require.False(t, m.VerifySecretKey(ctx, "", "server-secret"))
+
+	// new agents can enroll with a token
+	require.True(t, m.VerifySecretKey(ctx, "agent-1", value))
+
+	// agents that have connected before can use the secret key
+	_, err = s.UpsertAgent(ctx, "agent-2", func(current *model.Agent) {})
+	require.NoError(t, err)
+	require.True(t, m.VerifySecretKey(ctx, "agent-2", "server-secret"))
+	require.False(t, m.VerifySecretKey(ctx, "agent-2", "wrong-secret"))
+}
+
+func TestManagerAgentCertificates(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	s := store.NewMapStore(ctx, store.Options{SessionsSecret: "super-secret-key", MaxEventsToMerge: 1}, logger)
+	config := &common.Server{
+		AgentCA: &common.AgentCA{
+			Enabled:            true,
+			Certificate:        filepath.Join(dir, "agent-ca.crt"),
+			PrivateKey:         filepath.Join(dir, "agent-ca.key"),
+			RequireCertificate: true,
+		},
+	}
+	bindplane, err := NewManager(config, s, nil, logger, cluster.Standalone("test"))
+	require.NoError(t, err)
+	m := bindplane.(*manager)
+	require.NotNil(t, m.AgentCA())
+
+	parse := func(issued *agentca.Certificate) *x509.Certificate {
+		block, _ := pem.Decode(issued.CertificatePEM)
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		require.NoError(t, err)
+		return certificate
+	}
+
+	// agents without certificates are accepted until a certificate is issued
+	require.NoError(t, m.VerifyAgentCertificate(ctx, "agent-1", nil))
+
+	first, err := m.IssueAgentCertificate(ctx, "agent-1")
+	require.NoError(t, err)
+	require.NoError(t, m.VerifyAgentCertificate(ctx, "agent-1", parse(first)))
+	require.ErrorIs(t, m.VerifyAgentCertificate(ctx, "agent-1", nil), ErrAgentCertificateRequired)
+	require.ErrorIs(t, m.VerifyAgentCertificate(ctx, "agent-2", parse(first)), ErrAgentCertificateMismatch)
+
+	// certificates from other authorities are not bound to agents and don't replace an issued certificate
+	other, err := agentca.Load(filepath.Join(dir, "other.crt"), filepath.Join(dir, "other.key"), time.Hour)
+	require.NoError(t, err)
+	otherCertificate, err := other.Issue("agent-2")
+	require.NoError(t, err)
+	require.NoError(t, m.VerifyAgentCertificate(ctx, "agent-3", parse(otherCertificate)))
+	require.ErrorIs(t, m.VerifyAgentCertificate(ctx, "agent-1", parse(otherCertificate)), ErrAgentCertificateRequired)
+
+	// renew, both certificates are accepted until the agent uses the new certificate
+	second, err := m.IssueAgentCertificate(ctx, "agent-1")
+	require.NoError(t, err)
+	require.NoError(t, m.VerifyAgentCertificate(ctx, "agent-1", parse(first)))
+	require.NoError(t, m.VerifyAgentCertificate(ctx, "agent-1", parse(second)))
+	require.ErrorIs(t, m.VerifyAgentCertificate(ctx, "agent-1", parse(first)), ErrAgentCertificateRevoked)
+
+	// revoke, the agent isn't connected so no new certificate is sent and it can't connect without a certificate
+	require.NoError(t, m.RevokeAgentCertificate(ctx, "agent-1"))
+	require.ErrorIs(t, m.VerifyAgentCertificate(ctx, "agent-1", parse(second)), ErrAgentCertificateRevoked)
+	require.ErrorIs(t, m.VerifyAgentCertificate(ctx, "agent-1", nil), ErrAgentCertificateRequired)
+
+	// disabled
+	disabled := newManager(&common.Server{}, s, nil, logger, cluster.Standalone("test"), cluster.NewLocalBus())
+	require.Nil(t, disabled.AgentCA())
+	require.NoError(t, disabled.VerifyAgentCertificate(ctx, "agent-2", parse(first)))
+	issued, err := disabled.IssueAgentCertificate(ctx, "agent-2")
+	require.NoError(t, err)
+	require.Nil(t, issued)
+	require.ErrorIs(t, disabled.RevokeAgentCertificate(ctx, "agent-2"), ErrAgentCADisabled)
+}
+
+func TestManagerPendingApproval(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewMapStore(ctx, store.Options{SessionsSecret: "super-secret-key", MaxEventsToMerge: 1}, logger)
+
+	// agents known before approval was required are not pending
+	_, err := s.UpsertAgent(ctx, "existing", func(current *model.Agent) { current.Status = model.Connected })
+	require.NoError(t, err)
+
+	m := newManager(&common.Server{RequireApproval: true}, s, nil, logger, cluster.Standalone("test"), cluster.NewLocalBus())
+
+	pending, err := m.PendingApproval(ctx, "existing")
+	require.NoError(t, err)
+	require.False(t, pending)
+
+	pending, err = m.PendingApproval(ctx, "new")
+	require.NoError(t, err)
+	require.True(t, pending)
+
+	approved, err := m.ApproveAgents(ctx, []string{"existing", "new"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"new"}, approved)
+
+	pending, err = m.PendingApproval(ctx, "new")
+	require.NoError(t, err)
+	require.False(t, pending)
+
+	// without approval, no agents are pending
+	m = newManager(&common.Server{}, s, nil, logger, cluster.Standalone("test"), cluster.NewLocalBus())
+	pending, err = m.PendingApproval(ctx, "other")
+	require.NoError(t, err)
+	require.False(t, pending)
+}
+
+func TestManagerDenyAgents(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewMapStore(ctx, store.Options{SessionsSecret: "super-secret-key", MaxEventsToMerge: 1}, logger)
+	m := newManager(&common.Server{}, s, nil, logger, cluster.Standalone("test"), cluster.NewLocalBus())
+
+	_, err := s.UpsertAgent(ctx, "agent-1", func(current *model.Agent) { current.HostName = "host-1" })
+	require.NoError(t, err)
+	_, err = s.UpsertAgent(ctx, "agent-2", func(current *model.Agent) { current.MacAddress = "00:11:22:33:44:55" })
+	require.NoError(t, err)
+
+	rule, err := model.NewAgentDenyRule(model.AgentDenyFieldMacAddress, "00-11-22-33-44-55", "")
+	require.NoError(t, err)
+
+	denied, err := m.DeniedAgent(ctx, &model.Agent{ID: "agent-2"})
+	require.NoError(t, err)
+	require.Nil(t, denied)
+
+	// the MAC address of the stored agent is checked
+	require.NoError(t, s.AddAgentDenyRule(ctx, rule))
+	denied, err = m.DeniedAgent(ctx, &model.Agent{ID: "agent-2"})
+	require.NoError(t, err)
+	require.Equal(t, rule.ID(), denied.ID())
+
+	removed, err := m.DenyAgents(ctx, rule)
+	require.NoError(t, err)
+	require.Equal(t, []string{"agent-2"}, removed)
+
+	agent, err := s.Agent("agent-2")
+	require.NoError(t, err)
+	require.Nil(t, agent)
+
+	denied, err = m.DeniedAgent(ctx, &model.Agent{ID: "agent-2", MacAddress: "00:11:22:33:44:55"})
+	require.NoError(t, err)
+	require.Equal(t, rule.ID(), denied.ID())
+
+	denied, err = m.DeniedAgent(ctx, &model.Agent{ID: "agent-1", HostName: "host-1"})
+	require.NoError(t, err)
+	require.Nil(t, denied)
+}
+
 // -------------------------
 // Protocol is an autogenerated mock type for the Protocol type
 type mockProtocol struct {
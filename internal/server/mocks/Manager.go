@@ -5,6 +5,8 @@ package mocks
 import (
 	context "context"
 
+	agentca "github.com/observiq/bindplane-op/internal/agentca"
+
 	mock "github.com/stretchr/testify/mock"
 
 	model "github.com/observiq/bindplane-op/model"
@@ -14,6 +16,8 @@ import (
 	store "github.com/observiq/bindplane-op/internal/store"
 
 	testing "testing"
+
+	x509 "crypto/x509"
 )
 
 // Manager is an autogenerated mock type for the Manager type
@@ -44,6 +48,55 @@ func (_m *Manager) Agent(ctx context.Context, agentID string) (*model.Agent, err
 	return r0, r1
 }
 
+// AgentCA provides a mock function with given fields:
+func (_m *Manager) AgentCA() *agentca.Authority {
+	ret := _m.Called()
+
+	var r0 *agentca.Authority
+	if rf, ok := ret.Get(0).(func() *agentca.Authority); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*agentca.Authority)
+		}
+	}
+
+	return r0
+}
+
+// AgentConnected provides a mock function with given fields: ctx, agentID
+func (_m *Manager) AgentConnected(ctx context.Context, agentID string) {
+	_m.Called(ctx, agentID)
+}
+
+// AgentCredential provides a mock function with given fields: ctx, agentID
+func (_m *Manager) AgentCredential(ctx context.Context, agentID string) (*model.AgentCredential, error) {
+	ret := _m.Called(ctx, agentID)
+
+	var r0 *model.AgentCredential
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.AgentCredential); ok {
+		r0 = rf(ctx, agentID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.AgentCredential)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, agentID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AgentDisconnected provides a mock function with given fields: ctx, agentID
+func (_m *Manager) AgentDisconnected(ctx context.Context, agentID string) {
+	_m.Called(ctx, agentID)
+}
+
 // AgentUpdates provides a mock function with given fields: ctx, _a1
 func (_m *Manager) AgentUpdates(ctx context.Context, _a1 *model.Agent) (*server.AgentUpdates, error) {
 	ret := _m.Called(ctx, _a1)
@@ -90,11 +143,187 @@ func (_m *Manager) AgentVersion(ctx context.Context, version string) (*model.Age
 	return r0, r1
 }
 
+// ApproveAgents provides a mock function with given fields: ctx, agentIDs
+func (_m *Manager) ApproveAgents(ctx context.Context, agentIDs []string) ([]string, error) {
+	ret := _m.Called(ctx, agentIDs)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []string); ok {
+		r0 = rf(ctx, agentIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, agentIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Connected provides a mock function with given fields: agentID
+func (_m *Manager) Connected(agentID string) bool {
+	ret := _m.Called(agentID)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(string) bool); ok {
+		r0 = rf(agentID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// DeniedAgent provides a mock function with given fields: ctx, agent
+func (_m *Manager) DeniedAgent(ctx context.Context, agent *model.Agent) (*model.AgentDenyRule, error) {
+	ret := _m.Called(ctx, agent)
+
+	var r0 *model.AgentDenyRule
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Agent) *model.AgentDenyRule); ok {
+		r0 = rf(ctx, agent)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.AgentDenyRule)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *model.Agent) error); ok {
+		r1 = rf(ctx, agent)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DenyAgents provides a mock function with given fields: ctx, rule
+func (_m *Manager) DenyAgents(ctx context.Context, rule *model.AgentDenyRule) ([]string, error) {
+	ret := _m.Called(ctx, rule)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(context.Context, *model.AgentDenyRule) []string); ok {
+		r0 = rf(ctx, rule)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *model.AgentDenyRule) error); ok {
+		r1 = rf(ctx, rule)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // EnableProtocol provides a mock function with given fields: _a0
 func (_m *Manager) EnableProtocol(_a0 server.Protocol) {
 	_m.Called(_a0)
 }
 
+// IssueAgentCertificate provides a mock function with given fields: ctx, agentID
+func (_m *Manager) IssueAgentCertificate(ctx context.Context, agentID string) (*agentca.Certificate, error) {
+	ret := _m.Called(ctx, agentID)
+
+	var r0 *agentca.Certificate
+	if rf, ok := ret.Get(0).(func(context.Context, string) *agentca.Certificate); ok {
+		r0 = rf(ctx, agentID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*agentca.Certificate)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, agentID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IssueAgentCredential provides a mock function with given fields: ctx, agentID
+func (_m *Manager) IssueAgentCredential(ctx context.Context, agentID string) (string, error) {
+	ret := _m.Called(ctx, agentID)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = rf(ctx, agentID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, agentID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PendingApproval provides a mock function with given fields: ctx, agentID
+func (_m *Manager) PendingApproval(ctx context.Context, agentID string) (bool, error) {
+	ret := _m.Called(ctx, agentID)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, agentID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, agentID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReportAgentConflict provides a mock function with given fields: ctx, agentID, conflict
+func (_m *Manager) ReportAgentConflict(ctx context.Context, agentID string, conflict *model.AgentConflict) error {
+	ret := _m.Called(ctx, agentID, conflict)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *model.AgentConflict) error); ok {
+		r0 = rf(ctx, agentID, conflict)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ResolveAgentConflict provides a mock function with given fields: ctx, agentID
+func (_m *Manager) ResolveAgentConflict(ctx context.Context, agentID string) error {
+	ret := _m.Called(ctx, agentID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, agentID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ResourceStore provides a mock function with given fields:
 func (_m *Manager) ResourceStore() model.ResourceStore {
 	ret := _m.Called()
@@ -111,6 +340,34 @@ func (_m *Manager) ResourceStore() model.ResourceStore {
 	return r0
 }
 
+// RevokeAgentCertificate provides a mock function with given fields: ctx, agentID
+func (_m *Manager) RevokeAgentCertificate(ctx context.Context, agentID string) error {
+	ret := _m.Called(ctx, agentID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, agentID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SendAgentUpdates provides a mock function with given fields: ctx, agent, updates
+func (_m *Manager) SendAgentUpdates(ctx context.Context, agent *model.Agent, updates *server.AgentUpdates) error {
+	ret := _m.Called(ctx, agent, updates)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Agent, *server.AgentUpdates) error); ok {
+		r0 = rf(ctx, agent, updates)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Start provides a mock function with given fields: ctx
 func (_m *Manager) Start(ctx context.Context) {
 	_m.Called(ctx)
@@ -139,13 +396,27 @@ func (_m *Manager) UpsertAgent(ctx context.Context, agentID string, updater stor
 	return r0, r1
 }
 
-// VerifySecretKey provides a mock function with given fields: ctx, secretKey
-func (_m *Manager) VerifySecretKey(ctx context.Context, secretKey string) bool {
-	ret := _m.Called(ctx, secretKey)
+// VerifyAgentCertificate provides a mock function with given fields: ctx, agentID, certificate
+func (_m *Manager) VerifyAgentCertificate(ctx context.Context, agentID string, certificate *x509.Certificate) error {
+	ret := _m.Called(ctx, agentID, certificate)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *x509.Certificate) error); ok {
+		r0 = rf(ctx, agentID, certificate)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// VerifySecretKey provides a mock function with given fields: ctx, agentID, secretKey
+func (_m *Manager) VerifySecretKey(ctx context.Context, agentID string, secretKey string) bool {
+	ret := _m.Called(ctx, agentID, secretKey)
 
 	var r0 bool
-	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
-		r0 = rf(ctx, secretKey)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) bool); ok {
+		r0 = rf(ctx, agentID, secretKey)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
@@ -0,0 +1,268 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package oidctest provides a stand-in OpenID Connect provider for tests
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+const keyID = "oidctest"
+
+// Claims are the claims of the user that logs in
+type Claims struct {
+	Subject string
+	Email   string
+	Groups  []string
+}
+
+// Provider is an OpenID Connect provider that logs in a single user without prompting. It supports the authorization
+// code flow with PKCE and the device authorization flow.
+type Provider struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	signer jose.Signer
+
+	mtx     sync.Mutex
+	claims  Claims
+	codes   map[string]*authorization
+	devices map[string]*authorization
+}
+
+type authorization struct {
+	clientID      string
+	nonce         string
+	codeChallenge string
+	approved      bool
+}
+
+// New starts a Provider that is closed when the test completes
+func New(t *testing.T, claims Claims) *Provider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %s", err)
+	}
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: jose.RS256, Key: key},
+		(&jose.SignerOptions{}).WithType("JWT").WithHeader("kid", keyID),
+	)
+	if err != nil {
+		t.Fatalf("failed to create signer: %s", err)
+	}
+
+	p := &Provider{
+		key:     key,
+		signer:  signer,
+		claims:  claims,
+		codes:   map[string]*authorization{},
+		devices: map[string]*authorization{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", p.discovery)
+	mux.HandleFunc("/keys", p.keys)
+	mux.HandleFunc("/authorize", p.authorize)
+	mux.HandleFunc("/device", p.device)
+	mux.HandleFunc("/token", p.token)
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+	return p
+}
+
+// Issuer returns the issuer URL of the Provider
+func (p *Provider) Issuer() string {
+	return p.server.URL
+}
+
+// SetClaims changes the claims of ID tokens issued after the call
+func (p *Provider) SetClaims(claims Claims) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.claims = claims
+}
+
+// ApproveDevice approves the pending device authorization with the user code
+func (p *Provider) ApproveDevice(userCode string) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if auth, ok := p.devices[userCode]; ok {
+		auth.approved = true
+	}
+}
+
+// IDToken returns a signed ID token for the client using the current claims
+func (p *Provider) IDToken(clientID, nonce string) string {
+	p.mtx.Lock()
+	claims := p.claims
+	p.mtx.Unlock()
+
+	now := time.Now()
+	token, err := jwt.Signed(p.signer).
+		Claims(jwt.Claims{
+			Issuer:   p.Issuer(),
+			Subject:  claims.Subject,
+			Audience: jwt.Audience{clientID},
+			IssuedAt: jwt.NewNumericDate(now),
+			Expiry:   jwt.NewNumericDate(now.Add(time.Hour)),
+		}).
+		Claims(map[string]any{
+			"nonce":  nonce,
+			"email":  claims.Email,
+			"groups": claims.Groups,
+		}).
+		CompactSerialize()
+	if err != nil {
+		panic(err)
+	}
+	return token
+}
+
+// ----------------------------------------------------------------------
+
+func (p *Provider) discovery(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"issuer":                                p.Issuer(),
+		"authorization_endpoint":                p.Issuer() + "/authorize",
+		"token_endpoint":                        p.Issuer() + "/token",
+		"device_authorization_endpoint":         p.Issuer() + "/device",
+		"jwks_uri":                              p.Issuer() + "/keys",
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+	})
+}
+
+func (p *Provider) keys(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, jose.JSONWebKeySet{
+		Keys: []jose.JSONWebKey{{Key: &p.key.PublicKey, KeyID: keyID, Algorithm: "RS256", Use: "sig"}},
+	})
+}
+
+// authorize immediately redirects back to the client with an authorization code
+func (p *Provider) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "" {
+		http.Error(w, "pkce is required", http.StatusBadRequest)
+		return
+	}
+
+	code := randomString()
+	p.mtx.Lock()
+	p.codes[code] = &authorization{
+		clientID:      query.Get("client_id"),
+		nonce:         query.Get("nonce"),
+		codeChallenge: query.Get("code_challenge"),
+	}
+	p.mtx.Unlock()
+
+	redirect, err := url.Parse(query.Get("redirect_uri"))
+	if err != nil {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+	values := redirect.Query()
+	values.Set("code", code)
+	values.Set("state", query.Get("state"))
+	redirect.RawQuery = values.Encode()
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+// device starts a device authorization. The user code is also the device code.
+func (p *Provider) device(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	code := randomString()
+	p.mtx.Lock()
+	p.devices[code] = &authorization{clientID: r.PostForm.Get("client_id")}
+	p.mtx.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"device_code":      code,
+		"user_code":        code,
+		"verification_uri": p.Issuer() + "/activate",
+		"expires_in":       600,
+		"interval":         1,
+	})
+}
+
+func (p *Provider) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var auth *authorization
+	p.mtx.Lock()
+	switch r.PostForm.Get("grant_type") {
+	case "authorization_code":
+		auth = p.codes[r.PostForm.Get("code")]
+		delete(p.codes, r.PostForm.Get("code"))
+		if auth != nil && codeChallenge(r.PostForm.Get("code_verifier")) != auth.codeChallenge {
+			auth = nil
+		}
+	case "urn:ietf:params:oauth:grant-type:device_code":
+		auth = p.devices[r.PostForm.Get("device_code")]
+		if auth != nil && !auth.approved {
+			p.mtx.Unlock()
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "authorization_pending"})
+			return
+		}
+		delete(p.devices, r.PostForm.Get("device_code"))
+	}
+	p.mtx.Unlock()
+
+	if auth == nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": randomString(),
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     p.IDToken(auth.clientID, auth.nonce),
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func randomString() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func codeChallenge(codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,237 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package oidc provides OpenID Connect single sign-on for the UI and bindplanectl
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+
+	"github.com/observiq/bindplane-op/common"
+	"github.com/observiq/bindplane-op/model"
+)
+
+const defaultGroupsClaim = "groups"
+
+var defaultScopes = []string{"profile", "email", "groups"}
+
+// ErrNoRole is returned when the user is not a member of any group mapped to a role and there is no default role
+var ErrNoRole = errors.New("user is not assigned a role")
+
+// Identity is the user identified by an ID token
+type Identity struct {
+	Subject string
+	Email   string
+	Name    string
+	Groups  []string
+	Role    model.Role
+}
+
+// User returns the name used to identify the user in logs and the session, the email if present and the subject
+// otherwise
+func (i *Identity) User() string {
+	if i.Email != "" {
+		return i.Email
+	}
+	return i.Subject
+}
+
+// Provider authenticates users with an OpenID Connect provider
+type Provider struct {
+	config   *common.OIDC
+	provider *oidc.Provider
+	oauth2   *oauth2.Config
+	verifier *oidc.IDTokenVerifier
+
+	deviceAuthorizationEndpoint string
+}
+
+// NewProvider discovers the configuration of the provider at config.Issuer. redirectURL is the URL of the
+// /oidc/callback route.
+func NewProvider(ctx context.Context, config *common.OIDC, redirectURL string) (*Provider, error) {
+	provider, err := oidc.NewProvider(ctx, config.Issuer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover oidc provider: %w", err)
+	}
+
+	var claims struct {
+		DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint"`
+	}
+	if err := provider.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("failed to parse oidc provider metadata: %w", err)
+	}
+
+	scopes := config.Scopes
+	if len(scopes) == 0 {
+		scopes = defaultScopes
+	}
+
+	return &Provider{
+		config:   config,
+		provider: provider,
+		oauth2: &oauth2.Config{
+			ClientID:     config.ClientID,
+			ClientSecret: config.ClientSecret,
+			RedirectURL:  redirectURL,
+			Endpoint:     provider.Endpoint(),
+			Scopes:       append([]string{oidc.ScopeOpenID}, scopes...),
+		},
+		// the audience is checked in Verify because tokens are issued to either the UI or the CLI client
+		verifier: provider.Verifier(&oidc.Config{SkipClientIDCheck: true}),
+
+		deviceAuthorizationEndpoint: claims.DeviceAuthorizationEndpoint,
+	}, nil
+}
+
+// AuthCodeURL returns the URL of the provider login page. The codeVerifier is sent as an S256 PKCE challenge and must
+// be passed to Exchange with the code returned to the callback.
+func (p *Provider) AuthCodeURL(state, nonce, codeVerifier string) string {
+	return p.oauth2.AuthCodeURL(state,
+		oidc.Nonce(nonce),
+		oauth2.SetAuthURLParam("code_challenge", CodeChallenge(codeVerifier)),
+		oauth2.SetAuthURLParam("code_challenge_method", "S256"),
+	)
+}
+
+// Exchange exchanges the authorization code for an ID token and returns the identity of the user
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*Identity, error) {
+	token, err := p.oauth2.Exchange(ctx, code, oauth2.SetAuthURLParam("code_verifier", codeVerifier))
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange authorization code: %w", err)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, errors.New("token response did not contain an id_token")
+	}
+
+	idToken, err := p.verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, err
+	}
+	if idToken.Nonce != nonce {
+		return nil, errors.New("id token nonce does not match")
+	}
+	return p.identity(idToken)
+}
+
+// Verify verifies an ID token issued to the UI or CLI client and returns the identity of the user
+func (p *Provider) Verify(ctx context.Context, rawIDToken string) (*Identity, error) {
+	idToken, err := p.verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, err
+	}
+	return p.identity(idToken)
+}
+
+// ClientConfig returns the configuration used by bindplanectl login
+func (p *Provider) ClientConfig() *model.OIDCConfigResponse {
+	return &model.OIDCConfigResponse{
+		Issuer:                      p.config.Issuer,
+		ClientID:                    p.cliClientID(),
+		DeviceAuthorizationEndpoint: p.deviceAuthorizationEndpoint,
+		TokenEndpoint:               p.oauth2.Endpoint.TokenURL,
+		Scopes:                      p.oauth2.Scopes,
+	}
+}
+
+// ----------------------------------------------------------------------
+
+func (p *Provider) cliClientID() string {
+	if p.config.CLIClientID != "" {
+		return p.config.CLIClientID
+	}
+	return p.config.ClientID
+}
+
+func (p *Provider) verify(ctx context.Context, rawIDToken string) (*oidc.IDToken, error) {
+	idToken, err := p.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify id token: %w", err)
+	}
+	for _, audience := range idToken.Audience {
+		if audience == p.config.ClientID || audience == p.cliClientID() {
+			return idToken, nil
+		}
+	}
+	return nil, errors.New("id token was not issued to a bindplane client")
+}
+
+func (p *Provider) identity(idToken *oidc.IDToken) (*Identity, error) {
+	var claims map[string]any
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("failed to parse id token claims: %w", err)
+	}
+
+	identity := &Identity{
+		Subject: idToken.Subject,
+		Email:   stringClaim(claims, "email"),
+		Name:    stringClaim(claims, "name"),
+	}
+
+	groupsClaim := p.config.GroupsClaim
+	if groupsClaim == "" {
+		groupsClaim = defaultGroupsClaim
+	}
+	if groups, ok := claims[groupsClaim].([]any); ok {
+		for _, group := range groups {
+			if group, ok := group.(string); ok {
+				identity.Groups = append(identity.Groups, group)
+			}
+		}
+	}
+
+	roles := []model.Role{}
+	for _, group := range identity.Groups {
+		if role, ok := p.config.GroupRoles[group]; ok {
+			roles = append(roles, model.Role(role))
+		}
+	}
+	identity.Role = model.HighestRole(roles...)
+	if identity.Role == "" {
+		identity.Role = model.Role(p.config.DefaultRole)
+	}
+	if !identity.Role.Valid() {
+		return nil, fmt.Errorf("%w: %s", ErrNoRole, identity.User())
+	}
+	return identity, nil
+}
+
+func stringClaim(claims map[string]any, name string) string {
+	value, _ := claims[name].(string)
+	return value
+}
+
+// RandomString returns a random URL safe string used for the state, nonce, and PKCE code verifier
+func RandomString() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate random string: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// CodeChallenge returns the S256 PKCE code challenge for the code verifier
+func CodeChallenge(codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,190 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/observiq/bindplane-op/common"
+	"github.com/observiq/bindplane-op/internal/server/oidc/oidctest"
+	"github.com/observiq/bindplane-op/model"
+)
+
+func testConfig(issuer string) *common.OIDC {
+	return &common.OIDC{
+		Issuer:      issuer,
+		ClientID:    "bindplane-ui",
+		CLIClientID: "bindplane-cli",
+		GroupRoles: map[string]string{
+			"ops":       "admin",
+			"engineers": "viewer",
+		},
+	}
+}
+
+func TestProviderAuthorizationCode(t *testing.T) {
+	idp := oidctest.New(t, oidctest.Claims{Subject: "user-1", Email: "user@example.com", Groups: []string{"ops"}})
+	ctx := context.Background()
+
+	provider, err := NewProvider(ctx, testConfig(idp.Issuer()), "http://bindplane/oidc/callback")
+	require.NoError(t, err)
+
+	verifier, err := RandomString()
+	require.NoError(t, err)
+
+	// follow the redirect of the stand-in provider to get the code
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := client.Get(provider.AuthCodeURL("state-1", "nonce-1", verifier))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	require.NoError(t, err)
+	require.Equal(t, "state-1", location.Query().Get("state"))
+	code := location.Query().Get("code")
+
+	t.Run("wrong nonce", func(t *testing.T) {
+		resp, err := client.Get(provider.AuthCodeURL("state-2", "nonce-2", verifier))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		location, err := url.Parse(resp.Header.Get("Location"))
+		require.NoError(t, err)
+
+		_, err = provider.Exchange(ctx, location.Query().Get("code"), verifier, "other")
+		require.ErrorContains(t, err, "nonce does not match")
+	})
+
+	t.Run("wrong code verifier", func(t *testing.T) {
+		resp, err := client.Get(provider.AuthCodeURL("state-3", "nonce-3", verifier))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		location, err := url.Parse(resp.Header.Get("Location"))
+		require.NoError(t, err)
+
+		_, err = provider.Exchange(ctx, location.Query().Get("code"), "other", "nonce-3")
+		require.ErrorContains(t, err, "failed to exchange authorization code")
+	})
+
+	identity, err := provider.Exchange(ctx, code, verifier, "nonce-1")
+	require.NoError(t, err)
+	require.Equal(t, "user-1", identity.Subject)
+	require.Equal(t, "user@example.com", identity.User())
+	require.Equal(t, model.RoleAdmin, identity.Role)
+}
+
+func TestProviderVerify(t *testing.T) {
+	idp := oidctest.New(t, oidctest.Claims{Subject: "user-1"})
+	other := oidctest.New(t, oidctest.Claims{Subject: "user-1", Groups: []string{"ops"}})
+	ctx := context.Background()
+
+	tests := []struct {
+		name         string
+		defaultRole  string
+		claims       oidctest.Claims
+		token        func() string
+		expectRole   model.Role
+		expectGroups []string
+		expectError  string
+	}{
+		{
+			name:         "highest role",
+			claims:       oidctest.Claims{Subject: "user-1", Groups: []string{"engineers", "ops", "sales"}},
+			token:        func() string { return idp.IDToken("bindplane-cli", "") },
+			expectRole:   model.RoleAdmin,
+			expectGroups: []string{"engineers", "ops", "sales"},
+		},
+		{
+			name:         "viewer",
+			claims:       oidctest.Claims{Subject: "user-1", Groups: []string{"engineers"}},
+			token:        func() string { return idp.IDToken("bindplane-ui", "") },
+			expectRole:   model.RoleViewer,
+			expectGroups: []string{"engineers"},
+		},
+		{
+			name:         "default role",
+			defaultRole:  "viewer",
+			claims:       oidctest.Claims{Subject: "user-1", Groups: []string{"sales"}},
+			token:        func() string { return idp.IDToken("bindplane-cli", "") },
+			expectRole:   model.RoleViewer,
+			expectGroups: []string{"sales"},
+		},
+		{
+			name:        "no role",
+			claims:      oidctest.Claims{Subject: "user-1", Groups: []string{"sales"}},
+			token:       func() string { return idp.IDToken("bindplane-cli", "") },
+			expectError: "user is not assigned a role",
+		},
+		{
+			name:        "other audience",
+			claims:      oidctest.Claims{Subject: "user-1", Groups: []string{"ops"}},
+			token:       func() string { return idp.IDToken("another-app", "") },
+			expectError: "id token was not issued to a bindplane client",
+		},
+		{
+			name:        "other issuer",
+			claims:      oidctest.Claims{Subject: "user-1", Groups: []string{"ops"}},
+			token:       func() string { return other.IDToken("bindplane-cli", "") },
+			expectError: "failed to verify id token",
+		},
+		{
+			name:        "not a token",
+			token:       func() string { return "not-a-token" },
+			expectError: "failed to verify id token",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := testConfig(idp.Issuer())
+			config.DefaultRole = test.defaultRole
+			provider, err := NewProvider(ctx, config, "")
+			require.NoError(t, err)
+
+			idp.SetClaims(test.claims)
+			identity, err := provider.Verify(ctx, test.token())
+			if test.expectError != "" {
+				require.ErrorContains(t, err, test.expectError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.expectRole, identity.Role)
+			require.Equal(t, test.expectGroups, identity.Groups)
+		})
+	}
+}
+
+func TestProviderClientConfig(t *testing.T) {
+	idp := oidctest.New(t, oidctest.Claims{})
+
+	provider, err := NewProvider(context.Background(), testConfig(idp.Issuer()), "")
+	require.NoError(t, err)
+
+	require.Equal(t, &model.OIDCConfigResponse{
+		Issuer:                      idp.Issuer(),
+		ClientID:                    "bindplane-cli",
+		DeviceAuthorizationEndpoint: idp.Issuer() + "/device",
+		TokenEndpoint:               idp.Issuer() + "/token",
+		Scopes:                      []string{"openid", "profile", "email", "groups"},
+	}, provider.ClientConfig())
+
+	_, err = NewProvider(context.Background(), testConfig("http://127.0.0.1:1"), "")
+	require.ErrorContains(t, err, "failed to discover oidc provider")
+}
@@ -31,6 +31,17 @@ type AgentUpdates struct {
 
 	// Version instructs the agent to install a specific version
 	Version string
+
+	// Restart instructs the agent to restart
+	Restart bool
+
+	// RotateCredential issues a new credential to the agent. It is only supported by OpAMP agents that accept connection
+	// settings.
+	RotateCredential bool
+
+	// AssignNewIDs assigns a new ID to each host that connected with the ID of the agent while it was already connected.
+	// It is only supported by OpAMP.
+	AssignNewIDs bool
 }
 
 // Protocol represents a communication protocol for managing agents
@@ -56,5 +67,5 @@ type Protocol interface {
 
 // Empty returns true if the updates are empty because no changes need to be made to the agent
 func (u *AgentUpdates) Empty() bool {
-	return u.Labels == nil && u.Configuration == nil && u.Version == ""
+	return u.Labels == nil && u.Configuration == nil && u.Version == "" && !u.Restart && !u.AssignNewIDs
 }
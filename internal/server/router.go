@@ -0,0 +1,291 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/observiq/bindplane-op/internal/cluster"
+	"github.com/observiq/bindplane-op/model"
+)
+
+// ErrAgentNotConnected is returned when updates cannot be sent to an agent because it isn't connected to any node
+var ErrAgentNotConnected = errors.New("agent not connected")
+
+const (
+	// subjectAgentConnections receives agentConnectionsMessage when agents connect to or disconnect from a node
+	subjectAgentConnections = "agents.connections"
+	// subjectAgentConnectionsSync is used by a node that just started to request the agents connected to other nodes
+	subjectAgentConnectionsSync = "agents.connections.sync"
+	// subjectAgentUpdates receives agentUpdatesMessage for agents connected to the node in the message. All nodes share
+	// the subject so that a Bus backed by a persistent stream doesn't keep a stream for each node that ever started.
+	subjectAgentUpdates = "agents.updates"
+	// subjectNodeHeartbeat receives the ID of each node every NodeHeartbeatInterval so that other nodes can expire the
+	// agents of nodes that stopped without announcing their disconnects
+	subjectNodeHeartbeat = "nodes.heartbeat"
+)
+
+const (
+	// NodeHeartbeatInterval is how often each node announces that it is running
+	NodeHeartbeatInterval = 10 * time.Second
+	// NodeTTL is how long a node is considered running after its last heartbeat or announcement. The agents of a node
+	// that expires are removed from the registry and marked disconnected.
+	NodeTTL = 3 * NodeHeartbeatInterval
+)
+
+// agentConnectionsMessage announces that agents connected to or disconnected from a node
+type agentConnectionsMessage struct {
+	NodeID    string   `json:"nodeID"`
+	AgentIDs  []string `json:"agentIDs"`
+	Connected bool     `json:"connected"`
+}
+
+// agentUpdatesMessage forwards AgentUpdates to the node connected to the agent
+type agentUpdatesMessage struct {
+	NodeID  string        `json:"nodeID"`
+	AgentID string        `json:"agentID"`
+	Updates *AgentUpdates `json:"updates"`
+}
+
+// agentRouter keeps a registry of the nodes connected to each agent and forwards AgentUpdates for agents connected to
+// other nodes. Each node announces its connections and heartbeats on the cluster.Bus and every node keeps its own copy
+// of the registry.
+type agentRouter struct {
+	nodeID  string
+	bus     cluster.Bus
+	manager *manager
+	logger  *zap.Logger
+
+	// nodes maps agentID => nodeID for agents connected to other nodes
+	nodes map[string]string
+	// lastSeen maps nodeID => the time of the last heartbeat or announcement from another node
+	lastSeen map[string]time.Time
+	mtx      sync.RWMutex
+}
+
+func newAgentRouter(nodeID string, bus cluster.Bus, manager *manager, logger *zap.Logger) *agentRouter {
+	return &agentRouter{
+		nodeID:   nodeID,
+		bus:      bus,
+		manager:  manager,
+		logger:   logger.With(zap.String("nodeID", nodeID)),
+		nodes:    map[string]string{},
+		lastSeen: map[string]time.Time{},
+	}
+}
+
+// start subscribes to the messages from other nodes until the context is done and requests the agents connected to
+// other nodes
+func (r *agentRouter) start(ctx context.Context) {
+	unsubscribes := []func(){
+		r.bus.Subscribe(subjectAgentConnections, r.handleConnections),
+		r.bus.Subscribe(subjectAgentConnectionsSync, r.handleConnectionsSync),
+		r.bus.Subscribe(subjectAgentUpdates, r.handleUpdates),
+		r.bus.Subscribe(subjectNodeHeartbeat, r.handleHeartbeat),
+	}
+	go func() {
+		<-ctx.Done()
+		for _, unsubscribe := range unsubscribes {
+			unsubscribe()
+		}
+	}()
+	go r.heartbeat(ctx)
+
+	if err := r.bus.Publish(ctx, subjectAgentConnectionsSync, []byte(r.nodeID)); err != nil {
+		r.logger.Error("unable to request the agents connected to other nodes", zap.Error(err))
+	}
+}
+
+// node returns the ID of the other node connected to the agent
+func (r *agentRouter) node(agentID string) (nodeID string, ok bool) {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	nodeID, ok = r.nodes[agentID]
+	return nodeID, ok
+}
+
+// heartbeat publishes the ID of this node and expires other nodes every NodeHeartbeatInterval until the context is
+// done
+func (r *agentRouter) heartbeat(ctx context.Context) {
+	ticker := time.NewTicker(NodeHeartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.bus.Publish(ctx, subjectNodeHeartbeat, []byte(r.nodeID)); err != nil {
+				r.logger.Error("unable to publish node heartbeat", zap.Error(err))
+			}
+			r.expire(ctx, time.Now())
+		}
+	}
+}
+
+// expire removes the agents of nodes that haven't been seen for NodeTTL from the registry and marks them disconnected
+// because the node stopped without announcing their disconnects
+func (r *agentRouter) expire(ctx context.Context, now time.Time) {
+	var agentIDs []string
+	r.mtx.Lock()
+	for nodeID, lastSeen := range r.lastSeen {
+		if now.Sub(lastSeen) < NodeTTL {
+			continue
+		}
+		r.logger.Warn("node expired, its agents are no longer considered connected", zap.String("expiredNodeID", nodeID))
+		delete(r.lastSeen, nodeID)
+		for agentID, agentNodeID := range r.nodes {
+			if agentNodeID == nodeID {
+				delete(r.nodes, agentID)
+				agentIDs = append(agentIDs, agentID)
+			}
+		}
+	}
+	r.mtx.Unlock()
+
+	for _, agentID := range agentIDs {
+		if r.manager.connected(agentID) {
+			continue
+		}
+		// don't recreate agents that were deleted
+		if agent, err := r.manager.store.Agent(agentID); err != nil || agent == nil {
+			continue
+		}
+		_, err := r.manager.store.UpsertAgent(ctx, agentID, func(current *model.Agent) {
+			// every node expires the agents, only the first marks them disconnected
+			if current.Status != model.Disconnected {
+				current.Disconnect()
+			}
+		})
+		if err != nil {
+			r.logger.Error("unable to mark the agent of an expired node disconnected", zap.String("agentID", agentID), zap.Error(err))
+		}
+	}
+}
+
+// announce publishes the agents that connected to or disconnected from this node
+func (r *agentRouter) announce(ctx context.Context, agentIDs []string, connected bool) {
+	err := r.publish(ctx, subjectAgentConnections, &agentConnectionsMessage{
+		NodeID:    r.nodeID,
+		AgentIDs:  agentIDs,
+		Connected: connected,
+	})
+	if err != nil {
+		r.logger.Error("unable to announce agent connections", zap.Strings("agentIDs", agentIDs), zap.Bool("connected", connected), zap.Error(err))
+	}
+}
+
+// forward sends the updates to the node connected to the agent
+func (r *agentRouter) forward(ctx context.Context, agent *model.Agent, updates *AgentUpdates) error {
+	nodeID, ok := r.node(agent.ID)
+	if !ok {
+		return ErrAgentNotConnected
+	}
+	return r.forwardToNode(ctx, nodeID, agent, updates)
+}
+
+// forwardToNode sends the updates for the agent to the specified node
+func (r *agentRouter) forwardToNode(ctx context.Context, nodeID string, agent *model.Agent, updates *AgentUpdates) error {
+	r.logger.Info("forwarding updates to agent connected to another node", zap.String("agentID", agent.ID), zap.String("agentNodeID", nodeID))
+	return r.publish(ctx, subjectAgentUpdates, &agentUpdatesMessage{
+		NodeID:  nodeID,
+		AgentID: agent.ID,
+		Updates: updates,
+	})
+}
+
+func (r *agentRouter) publish(ctx context.Context, subject string, message any) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	return r.bus.Publish(ctx, subject, data)
+}
+
+// ----------------------------------------------------------------------
+// handlers
+
+func (r *agentRouter) handleConnections(ctx context.Context, data []byte) {
+	var message agentConnectionsMessage
+	if err := json.Unmarshal(data, &message); err != nil {
+		r.logger.Error("unable to unmarshal agent connections", zap.Error(err))
+		return
+	}
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if message.NodeID != r.nodeID {
+		r.lastSeen[message.NodeID] = time.Now()
+	}
+	for _, agentID := range message.AgentIDs {
+		switch {
+		case message.NodeID == r.nodeID:
+			if message.Connected {
+				// the agent moved to this node
+				delete(r.nodes, agentID)
+			}
+		case message.Connected:
+			r.nodes[agentID] = message.NodeID
+		case r.nodes[agentID] == message.NodeID:
+			// ignore disconnects from a previous node after the agent reconnected to another node
+			delete(r.nodes, agentID)
+		}
+	}
+}
+
+func (r *agentRouter) handleHeartbeat(ctx context.Context, data []byte) {
+	nodeID := string(data)
+	if nodeID == r.nodeID {
+		return
+	}
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.lastSeen[nodeID] = time.Now()
+}
+
+func (r *agentRouter) handleConnectionsSync(ctx context.Context, data []byte) {
+	if string(data) == r.nodeID {
+		return
+	}
+	agentIDs := r.manager.connectedAgentIDs(ctx)
+	if len(agentIDs) > 0 {
+		r.announce(ctx, agentIDs, true)
+	}
+}
+
+func (r *agentRouter) handleUpdates(ctx context.Context, data []byte) {
+	var message agentUpdatesMessage
+	if err := json.Unmarshal(data, &message); err != nil || message.Updates == nil {
+		r.logger.Error("unable to unmarshal agent updates", zap.Error(err))
+		return
+	}
+	if message.NodeID != r.nodeID {
+		// the agent is connected to another node
+		return
+	}
+
+	agent, err := r.manager.store.Agent(message.AgentID)
+	if err != nil || agent == nil {
+		r.logger.Error("unable to find agent for forwarded updates", zap.String("agentID", message.AgentID), zap.Error(err))
+		return
+	}
+	if err := r.manager.sendAgentUpdates(ctx, agent, message.Updates); err != nil {
+		r.logger.Error("unable to send forwarded updates to agent", zap.String("agentID", agent.ID), zap.Error(err))
+	}
+}
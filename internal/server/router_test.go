@@ -0,0 +1,190 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/observiq/bindplane-op/internal/cluster"
+	"github.com/observiq/bindplane-op/model"
+)
+
+func matchAgentID(agentID string) any {
+	return mock.MatchedBy(func(agent *model.Agent) bool { return agent.ID == agentID })
+}
+
+func TestAgentRouter(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	managerTestReset()
+	agent := makeTestAgent("1")
+	bus := cluster.NewLocalBus()
+
+	protocolA := &mockProtocol{}
+	protocolB := &mockProtocol{}
+	nodeA := newTestManager(testMapstore, "a", bus, protocolA)
+	nodeB := newTestManager(testMapstore, "b", bus, protocolB)
+	// nodeA reports its connections when nodeB starts
+	protocolA.On("ConnectedAgentIDs", mock.Anything).Return([]string{}, nil).Once()
+	nodeA.router.start(ctx)
+	nodeB.router.start(ctx)
+
+	restart := &AgentUpdates{Restart: true}
+
+	t.Run("agent not connected to any node", func(t *testing.T) {
+		protocolA.On("Connected", "1").Return(false).Once()
+		err := nodeA.SendAgentUpdates(ctx, agent, restart)
+		require.ErrorIs(t, err, ErrAgentNotConnected)
+	})
+
+	t.Run("forwards updates to the node connected to the agent", func(t *testing.T) {
+		nodeB.AgentConnected(ctx, "1")
+		nodeID, ok := nodeA.router.node("1")
+		require.True(t, ok)
+		require.Equal(t, "b", nodeID)
+
+		protocolA.On("Connected", "1").Return(false).Once()
+		protocolB.On("UpdateAgent", mock.Anything, matchAgentID("1"), restart).Return(nil).Once()
+		require.NoError(t, nodeA.SendAgentUpdates(ctx, agent, restart))
+		protocolB.AssertExpectations(t)
+		// nodes share the updates subject and ignore updates for agents connected to other nodes
+		protocolA.AssertNotCalled(t, "UpdateAgent", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("sends updates to the agent connected to this node", func(t *testing.T) {
+		protocolB.On("Connected", "1").Return(true).Once()
+		protocolB.On("UpdateAgent", mock.Anything, agent, restart).Return(nil).Once()
+		require.NoError(t, nodeB.SendAgentUpdates(ctx, agent, restart))
+		protocolB.AssertExpectations(t)
+	})
+
+	t.Run("new node requests the agents connected to other nodes", func(t *testing.T) {
+		protocolA.On("ConnectedAgentIDs", mock.Anything).Return([]string{}, nil).Once()
+		protocolB.On("ConnectedAgentIDs", mock.Anything).Return([]string{"1"}, nil).Once()
+		nodeC := newTestManager(testMapstore, "c", bus)
+		nodeC.router.start(ctx)
+
+		nodeID, ok := nodeC.router.node("1")
+		require.True(t, ok)
+		require.Equal(t, "b", nodeID)
+	})
+
+	t.Run("ignores disconnect from a previous node", func(t *testing.T) {
+		nodeA.AgentConnected(ctx, "1")
+		nodeB.AgentDisconnected(ctx, "1")
+
+		_, ok := nodeA.router.node("1")
+		require.False(t, ok)
+		nodeID, ok := nodeB.router.node("1")
+		require.True(t, ok)
+		require.Equal(t, "a", nodeID)
+	})
+
+	t.Run("removes agents that disconnect", func(t *testing.T) {
+		nodeA.AgentDisconnected(ctx, "1")
+		_, ok := nodeB.router.node("1")
+		require.False(t, ok)
+	})
+
+	protocolA.AssertExpectations(t)
+	protocolB.AssertExpectations(t)
+}
+
+func TestAgentRouterResolveAgentConflict(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	managerTestReset()
+	makeTestAgent("1")
+	bus := cluster.NewLocalBus()
+
+	protocolA := &mockProtocol{}
+	protocolB := &mockProtocol{}
+	nodeA := newTestManager(testMapstore, "a", bus, protocolA)
+	nodeB := newTestManager(testMapstore, "b", bus, protocolB)
+	protocolA.On("ConnectedAgentIDs", mock.Anything).Return([]string{}, nil).Once()
+	nodeA.router.start(ctx)
+	nodeB.router.start(ctx)
+
+	require.ErrorIs(t, nodeA.ResolveAgentConflict(ctx, "1"), ErrNoAgentConflict)
+	require.ErrorIs(t, nodeA.ResolveAgentConflict(ctx, "missing"), ErrNoAgentConflict)
+
+	// the conflicting host connected to nodeB
+	require.NoError(t, nodeB.ReportAgentConflict(ctx, "1", &model.AgentConflict{HostName: "web-2"}))
+	agent, err := testMapstore.Agent("1")
+	require.NoError(t, err)
+	require.Equal(t, "b", agent.Conflict.NodeID)
+	require.False(t, agent.Conflict.DetectedAt.IsZero())
+
+	assign := &AgentUpdates{AssignNewIDs: true}
+	protocolB.On("UpdateAgent", mock.Anything, matchAgentID("1"), assign).Return(nil).Once()
+	require.NoError(t, nodeA.ResolveAgentConflict(ctx, "1"))
+
+	agent, err = testMapstore.Agent("1")
+	require.NoError(t, err)
+	require.Nil(t, agent.Conflict)
+
+	protocolA.AssertExpectations(t)
+	protocolB.AssertExpectations(t)
+}
+
+func TestAgentRouterExpiresNodes(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	managerTestReset()
+	agent := makeTestAgent("1")
+	_, err := testMapstore.UpsertAgent(ctx, agent.ID, func(current *model.Agent) { current.Status = model.Connected })
+	require.NoError(t, err)
+	bus := cluster.NewLocalBus()
+
+	protocolA := &mockProtocol{}
+	nodeA := newTestManager(testMapstore, "a", bus, protocolA)
+	nodeB := newTestManager(testMapstore, "b", bus)
+	nodeA.router.start(ctx)
+
+	// nodeB stops without announcing that the agent disconnected
+	nodeB.router.announce(ctx, []string{"1"}, true)
+	require.NoError(t, bus.Publish(ctx, subjectNodeHeartbeat, []byte("b")))
+	protocolA.On("Connected", "1").Return(false)
+
+	t.Run("keeps agents of nodes that are running", func(t *testing.T) {
+		nodeA.router.expire(ctx, time.Now())
+		require.True(t, nodeA.Connected("1"))
+	})
+
+	t.Run("removes and disconnects agents of expired nodes", func(t *testing.T) {
+		nodeA.router.expire(ctx, time.Now().Add(NodeTTL))
+		require.False(t, nodeA.Connected("1"))
+		require.ErrorIs(t, nodeA.SendAgentUpdates(ctx, agent, &AgentUpdates{Restart: true}), ErrAgentNotConnected)
+
+		agent, err := testMapstore.Agent("1")
+		require.NoError(t, err)
+		require.Equal(t, model.Disconnected, agent.Status)
+		require.NotNil(t, agent.DisconnectedAt)
+	})
+}
+
+func TestAgentUpdatesEmpty(t *testing.T) {
+	require.True(t, (&AgentUpdates{}).Empty())
+	require.False(t, (&AgentUpdates{Restart: true}).Empty())
+	require.False(t, (&AgentUpdates{AssignNewIDs: true}).Empty())
+}
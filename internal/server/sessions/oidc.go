@@ -0,0 +1,134 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sessions
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	gsessions "github.com/gorilla/sessions"
+	"go.uber.org/zap"
+
+	"github.com/observiq/bindplane-op/internal/server"
+	"github.com/observiq/bindplane-op/internal/server/oidc"
+)
+
+const (
+	// OIDCCookieName is the name of the cookie that holds the state of an OpenID Connect login in progress
+	OIDCCookieName = "BP_OP_OIDC"
+
+	// oidcLoginTimeout is the number of seconds a user has to complete login with the provider
+	oidcLoginTimeout = 600
+)
+
+// oidcSession returns the session holding the state of an OpenID Connect login. Unlike the session cookie, it is sent
+// with SameSite=Lax so that it is included when the provider redirects back to the callback.
+func oidcSession(ctx *gin.Context, bindplane server.BindPlane) (*gsessions.Session, error) {
+	session, err := bindplane.Store().UserSessions().Get(ctx.Request, OIDCCookieName)
+	session.Options = &gsessions.Options{
+		Path:     "/oidc",
+		MaxAge:   oidcLoginTimeout,
+		HttpOnly: true,
+		Secure:   session.Options != nil && session.Options.Secure,
+		SameSite: http.SameSiteLaxMode,
+	}
+	return session, err
+}
+
+func oidcLogin(ctx *gin.Context, bindplane server.BindPlane, provider *oidc.Provider) {
+	// errors are ignored because a stale or invalid cookie is replaced
+	session, _ := oidcSession(ctx, bindplane)
+
+	values := map[string]string{}
+	for _, key := range []string{"state", "nonce", "verifier"} {
+		value, err := oidc.RandomString()
+		if err != nil {
+			ctx.AbortWithError(http.StatusInternalServerError, errors.New("failed to start login"))
+			bindplane.Logger().Error("failed to start oidc login", zap.Error(err))
+			return
+		}
+		values[key] = value
+		session.Values[key] = value
+	}
+
+	if err := session.Save(ctx.Request, ctx.Writer); err != nil {
+		ctx.AbortWithError(http.StatusInternalServerError, errors.New("failed to save session"))
+		bindplane.Logger().Error("failed to save session at oidc login", zap.Error(err))
+		return
+	}
+
+	ctx.Redirect(http.StatusFound, provider.AuthCodeURL(values["state"], values["nonce"], values["verifier"]))
+}
+
+func oidcCallback(ctx *gin.Context, bindplane server.BindPlane, provider *oidc.Provider) {
+	loginSession, err := oidcSession(ctx, bindplane)
+	if err != nil || loginSession.IsNew {
+		ctx.AbortWithError(http.StatusBadRequest, errors.New("login expired or was not started"))
+		return
+	}
+
+	// the login state can only be used once
+	state, _ := loginSession.Values["state"].(string)
+	nonce, _ := loginSession.Values["nonce"].(string)
+	verifier, _ := loginSession.Values["verifier"].(string)
+	loginSession.Options.MaxAge = -1
+	if err := loginSession.Save(ctx.Request, ctx.Writer); err != nil {
+		ctx.AbortWithError(http.StatusInternalServerError, errors.New("failed to save session"))
+		bindplane.Logger().Error("failed to clear oidc login session", zap.Error(err))
+		return
+	}
+
+	if errorCode := ctx.Query("error"); errorCode != "" {
+		ctx.AbortWithError(http.StatusUnauthorized, errors.New("login failed: "+errorCode))
+		return
+	}
+	if state == "" || ctx.Query("state") != state {
+		ctx.AbortWithError(http.StatusBadRequest, errors.New("login state does not match"))
+		return
+	}
+
+	identity, err := provider.Exchange(ctx.Request.Context(), ctx.Query("code"), verifier, nonce)
+	if err != nil {
+		ctx.AbortWithError(http.StatusUnauthorized, errors.New("login failed"))
+		bindplane.Logger().Info("oidc login failed", zap.Error(err))
+		return
+	}
+
+	session, err := bindplane.Store().UserSessions().Get(ctx.Request, CookieName)
+	if err != nil && session == nil {
+		ctx.AbortWithError(http.StatusInternalServerError, errors.New("failed to retrieve session"))
+		bindplane.Logger().Error("failed to retrieve session at oidc login", zap.Error(err))
+		return
+	}
+
+	bindplane.Logger().Info("logging in user.", zap.String("user", identity.User()), zap.String("role", string(identity.Role)))
+
+	if err := start(ctx, bindplane, session, identity.User(), identity.Role); err != nil {
+		ctx.AbortWithError(http.StatusInternalServerError, errors.New("failed to save session"))
+		bindplane.Logger().Error("failed to save session after oidc login", zap.Error(err))
+		return
+	}
+
+	ctx.Redirect(http.StatusFound, "/")
+}
+
+// AddOIDCRoutes adds the routes used to log in with an OpenID Connect provider. /oidc/login redirects the UI to the
+// provider, which redirects back to /oidc/callback. /oidc/config returns the configuration used by bindplanectl login.
+func AddOIDCRoutes(router gin.IRouter, bindplane server.BindPlane, provider *oidc.Provider) {
+	router.GET("/oidc/login", func(ctx *gin.Context) { oidcLogin(ctx, bindplane, provider) })
+	router.GET("/oidc/callback", func(ctx *gin.Context) { oidcCallback(ctx, bindplane, provider) })
+	router.GET("/oidc/config", func(ctx *gin.Context) { ctx.JSON(http.StatusOK, provider.ClientConfig()) })
+}
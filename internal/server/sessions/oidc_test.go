@@ -0,0 +1,144 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sessions
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/observiq/bindplane-op/common"
+	"github.com/observiq/bindplane-op/internal/server"
+	"github.com/observiq/bindplane-op/internal/server/oidc"
+	"github.com/observiq/bindplane-op/internal/server/oidc/oidctest"
+	"github.com/observiq/bindplane-op/internal/store"
+	"github.com/observiq/bindplane-op/model"
+)
+
+func TestOIDCLogin(t *testing.T) {
+	idp := oidctest.New(t, oidctest.Claims{Subject: "user-1", Email: "user@example.com", Groups: []string{"engineers"}})
+
+	router := gin.New()
+	svr := httptest.NewServer(router)
+	defer svr.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s := store.NewMapStore(ctx, store.Options{
+		SessionsSecret:   "super-secret-key",
+		MaxEventsToMerge: 1,
+	}, zap.NewNop())
+
+	config := &common.OIDC{
+		Issuer:     idp.Issuer(),
+		ClientID:   "bindplane-ui",
+		GroupRoles: map[string]string{"engineers": "viewer"},
+	}
+	provider, err := oidc.NewProvider(ctx, config, svr.URL+"/oidc/callback")
+	require.NoError(t, err)
+
+	bindplane, err := server.NewBindPlane(&common.Server{}, zap.NewNop(), s, nil, nil)
+	require.NoError(t, err)
+
+	AddRoutes(router, bindplane)
+	AddOIDCRoutes(router, bindplane, provider)
+	router.GET("/", func(c *gin.Context) {
+		session, err := bindplane.Store().UserSessions().Get(c.Request, CookieName)
+		require.NoError(t, err)
+		c.JSON(http.StatusOK, session.Values["role"])
+	})
+
+	newClient := func(t *testing.T) *http.Client {
+		jar, err := cookiejar.New(nil)
+		require.NoError(t, err)
+		return &http.Client{Jar: jar}
+	}
+
+	t.Run("logs in with the provider", func(t *testing.T) {
+		client := newClient(t)
+
+		resp, err := client.Get(svr.URL + "/oidc/login")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, svr.URL+"/", resp.Request.URL.String())
+
+		var role string
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&role))
+		require.Equal(t, string(model.RoleViewer), role)
+
+		verifyResp, err := client.Get(svr.URL + "/verify")
+		require.NoError(t, err)
+		defer verifyResp.Body.Close()
+		require.Equal(t, http.StatusOK, verifyResp.StatusCode)
+	})
+
+	t.Run("callback without login", func(t *testing.T) {
+		resp, err := newClient(t).Get(svr.URL + "/oidc/callback?code=abc&state=def")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("callback with wrong state", func(t *testing.T) {
+		client := newClient(t)
+		client.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+
+		resp, err := client.Get(svr.URL + "/oidc/login")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusFound, resp.StatusCode)
+
+		callback, err := client.Get(svr.URL + "/oidc/callback?code=abc&state=wrong")
+		require.NoError(t, err)
+		defer callback.Body.Close()
+		require.Equal(t, http.StatusBadRequest, callback.StatusCode)
+	})
+
+	t.Run("user without a role", func(t *testing.T) {
+		idp.SetClaims(oidctest.Claims{Subject: "user-2", Groups: []string{"sales"}})
+		defer idp.SetClaims(oidctest.Claims{Subject: "user-1", Groups: []string{"engineers"}})
+
+		client := newClient(t)
+		resp, err := client.Get(svr.URL + "/oidc/login")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+		verifyResp, err := client.Get(svr.URL + "/verify")
+		require.NoError(t, err)
+		defer verifyResp.Body.Close()
+		require.Equal(t, http.StatusUnauthorized, verifyResp.StatusCode)
+	})
+
+	t.Run("config", func(t *testing.T) {
+		resp, err := newClient(t).Get(svr.URL + "/oidc/config")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var config model.OIDCConfigResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&config))
+		require.Equal(t, "bindplane-ui", config.ClientID)
+		require.Equal(t, idp.Issuer()+"/device", config.DeviceAuthorizationEndpoint)
+	})
+}
@@ -0,0 +1,179 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sessions
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	gsessions "github.com/gorilla/sessions"
+	"go.uber.org/zap"
+
+	"github.com/observiq/bindplane-op/internal/server"
+	"github.com/observiq/bindplane-op/internal/store"
+	"github.com/observiq/bindplane-op/model"
+)
+
+const (
+	// CSRFCookieName is the name of the cookie containing the CSRF token of the session. Unlike the session cookie, it
+	// can be read by the UI, which sends the token in the CSRFHeader of requests that modify resources.
+	CSRFCookieName = "BP_OP_CSRF"
+
+	// CSRFHeader is the header containing the CSRF token of the session
+	CSRFHeader = "X-CSRF-Token"
+
+	// lastSeenInterval limits how often the LastSeenAt of a session is saved
+	lastSeenInterval = time.Minute
+)
+
+// start creates a UserSession for the user and saves its ID in the session cookie. The CSRF token of the session is
+// written to the CSRF cookie. Any UserSession previously saved in the cookie is deleted.
+func start(ctx *gin.Context, bindplane server.BindPlane, session *gsessions.Session, user string, role model.Role) error {
+	config := bindplane.Config()
+	timeout := config.SessionTimeoutOrDefault()
+
+	if id, ok := session.Values["id"].(string); ok {
+		if _, err := bindplane.Store().DeleteUserSessionRecord(ctx.Request.Context(), id); err != nil {
+			return fmt.Errorf("failed to delete previous session: %w", err)
+		}
+	}
+
+	record, csrfToken, err := model.NewUserSession(user, role, timeout, config.SessionIdleTimeoutOrDefault())
+	if err != nil {
+		return err
+	}
+	record.RemoteAddress = ctx.ClientIP()
+	record.UserAgent = ctx.Request.UserAgent()
+	if err := bindplane.Store().CreateUserSessionRecord(ctx.Request.Context(), record); err != nil {
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+
+	session.Values["authenticated"] = true
+	session.Values["id"] = record.ID
+	session.Values["user"] = user
+	session.Values["role"] = string(role)
+	session.Options.MaxAge = int(timeout.Seconds())
+
+	setCSRFCookie(ctx, csrfToken, session.Options.MaxAge)
+	return session.Save(ctx.Request, ctx.Writer)
+}
+
+// end deletes the UserSession saved in the session cookie and expires the session and CSRF cookies
+func end(ctx *gin.Context, bindplane server.BindPlane, session *gsessions.Session) error {
+	if id, ok := session.Values["id"].(string); ok {
+		if _, err := bindplane.Store().DeleteUserSessionRecord(ctx.Request.Context(), id); err != nil {
+			return fmt.Errorf("failed to delete session: %w", err)
+		}
+	}
+
+	session.Values["authenticated"] = false
+	delete(session.Values, "id")
+	session.Options.MaxAge = -1
+
+	setCSRFCookie(ctx, "", -1)
+	return session.Save(ctx.Request, ctx.Writer)
+}
+
+func setCSRFCookie(ctx *gin.Context, token string, maxAge int) {
+	http.SetCookie(ctx.Writer, &http.Cookie{
+		Name:     CSRFCookieName,
+		Value:    token,
+		Path:     "/",
+		MaxAge:   maxAge,
+		Secure:   ctx.Request.TLS != nil,
+		SameSite: http.SameSiteStrictMode,
+	})
+}
+
+// Lookup returns the UserSession with the specified ID or nil if it does not exist or has expired. Expired sessions
+// are deleted. If touch is true, the LastSeenAt of the session is updated, extending its idle timeout.
+func Lookup(ctx context.Context, bindplane server.BindPlane, id string, touch bool) (*model.UserSession, error) {
+	if id == "" {
+		return nil, nil
+	}
+
+	s := bindplane.Store()
+	record, err := s.UserSessionRecord(ctx, id)
+	if err != nil || record == nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if record.Expired(now) {
+		if _, err := s.DeleteUserSessionRecord(ctx, id); err != nil {
+			bindplane.Logger().Error("failed to delete expired session", zap.Error(err))
+		}
+		return nil, nil
+	}
+
+	if touch && now.Sub(record.LastSeenAt) >= lastSeenInterval {
+		record, err = s.UpdateUserSessionRecord(ctx, id, func(current *model.UserSession) error {
+			current.LastSeenAt = now.UTC()
+			return nil
+		})
+		switch {
+		case err == store.ErrResourceMissing:
+			// revoked since it was read
+			return nil, nil
+		case err != nil:
+			return nil, err
+		}
+	}
+	return record, nil
+}
+
+// Records returns the UserSessions that have not expired and deletes those that have
+func Records(ctx context.Context, bindplane server.BindPlane) ([]*model.UserSession, error) {
+	records, err := bindplane.Store().UserSessionRecords(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	active := make([]*model.UserSession, 0, len(records))
+	for _, record := range records {
+		if !record.Expired(now) {
+			active = append(active, record)
+			continue
+		}
+		if _, err := bindplane.Store().DeleteUserSessionRecord(ctx, record.ID); err != nil {
+			return nil, err
+		}
+	}
+	return active, nil
+}
+
+// RevokeUser deletes all of the UserSessions of the user and returns their IDs
+func RevokeUser(ctx context.Context, bindplane server.BindPlane, user string) ([]string, error) {
+	records, err := bindplane.Store().UserSessionRecords(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	revoked := []string{}
+	for _, record := range records {
+		if record.User != user {
+			continue
+		}
+		if _, err := bindplane.Store().DeleteUserSessionRecord(ctx, record.ID); err != nil {
+			return revoked, err
+		}
+		revoked = append(revoked, record.ID)
+	}
+	return revoked, nil
+}
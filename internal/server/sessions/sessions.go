@@ -19,7 +19,9 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	gsessions "github.com/gorilla/sessions"
 	"github.com/observiq/bindplane-op/internal/server"
+	"github.com/observiq/bindplane-op/model"
 	"go.uber.org/zap"
 )
 
@@ -44,13 +46,9 @@ func login(ctx *gin.Context, bindplane server.BindPlane) {
 		return
 	}
 
-	// Set user as authenticated
-	session.Values["authenticated"] = true
-
 	bindplane.Logger().Info("logging in user.", zap.String("user", username))
 
-	// Save and write the session
-	if err := session.Save(ctx.Request, ctx.Writer); err != nil {
+	if err := start(ctx, bindplane, session, username, model.RoleAdmin); err != nil {
 		ctx.AbortWithError(http.StatusInternalServerError, errors.New("failed to save session"))
 		bindplane.Logger().Error("failed to save session after login", zap.Error(err))
 	}
@@ -64,14 +62,29 @@ func logout(ctx *gin.Context, bindplane server.BindPlane) {
 		return
 	}
 
-	// Revoke users authentication
-	session.Values["authenticated"] = false
-	// Delete the cookie
-	session.Options.MaxAge = -1
-
 	bindplane.Logger().Info("logging out user.", zap.Any("user", session.Values["user"]))
-	// Save and write the session
-	if err := session.Save(ctx.Request, ctx.Writer); err != nil {
+
+	// logout everywhere revokes every session of the user, e.g. sessions left open on other computers
+	if ctx.Query("everywhere") == "true" {
+		id, _ := session.Values["id"].(string)
+		record, err := Lookup(ctx.Request.Context(), bindplane, id, false)
+		if err != nil {
+			ctx.AbortWithError(http.StatusInternalServerError, errors.New("failed to retrieve session"))
+			bindplane.Logger().Error("failed to lookup session for logout", zap.Error(err))
+			return
+		}
+		if record == nil || !record.VerifyCSRF(ctx.GetHeader(CSRFHeader)) {
+			ctx.AbortWithError(http.StatusForbidden, errors.New("logout everywhere requires an authenticated session and csrf token"))
+			return
+		}
+		if _, err := RevokeUser(ctx.Request.Context(), bindplane, record.User); err != nil {
+			ctx.AbortWithError(http.StatusInternalServerError, errors.New("failed to revoke sessions"))
+			bindplane.Logger().Error("failed to revoke sessions at logout", zap.Error(err))
+			return
+		}
+	}
+
+	if err := end(ctx, bindplane, session); err != nil {
 		ctx.AbortWithError(http.StatusInternalServerError, errors.New("failed to save session"))
 		bindplane.Logger().Error("failed to save session after logout", zap.Error(err))
 	}
@@ -85,13 +98,28 @@ func verify(c *gin.Context, bindplane server.BindPlane) {
 		return
 	}
 
-	if session.Values["authenticated"] == true {
+	if isAuthenticated(c, bindplane, session) {
 		return
 	}
 
 	c.AbortWithError(http.StatusUnauthorized, errors.New("unauthorized"))
 }
 
+// isAuthenticated returns true if the session is authenticated and its UserSession has not expired or been revoked.
+// It does not extend the idle timeout of the session so that the UI can poll /verify.
+func isAuthenticated(c *gin.Context, bindplane server.BindPlane, session *gsessions.Session) bool {
+	if session.Values["authenticated"] != true {
+		return false
+	}
+	id, _ := session.Values["id"].(string)
+	record, err := Lookup(c.Request.Context(), bindplane, id, false)
+	if err != nil {
+		bindplane.Logger().Error("failed to lookup session", zap.Error(err))
+		return false
+	}
+	return record != nil
+}
+
 // AddRoutes adds the login, logout, and verify route used for session authentication.
 func AddRoutes(router gin.IRouter, bindplane server.BindPlane) {
 	router.POST("/login", func(ctx *gin.Context) { login(ctx, bindplane) })
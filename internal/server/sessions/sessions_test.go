@@ -47,7 +47,7 @@ func TestAddRoutes(t *testing.T) {
 		MaxEventsToMerge: 1,
 	}, logger)
 
-	bindplane, err := server.NewBindPlane(&common.Server{}, zap.NewNop(), s, nil)
+	bindplane, err := server.NewBindPlane(&common.Server{}, zap.NewNop(), s, nil, nil)
 	require.NoError(t, err)
 
 	t.Run("adds /login /logout and /verify", func(t *testing.T) {
@@ -93,7 +93,7 @@ func TestHandleLogin(t *testing.T) {
 		SessionsSecret:   "super-secret-key",
 		MaxEventsToMerge: 1,
 	}, logger)
-	bindplane, err := server.NewBindPlane(cfg, zap.NewNop(), s, nil)
+	bindplane, err := server.NewBindPlane(cfg, zap.NewNop(), s, nil, nil)
 	require.NoError(t, err)
 
 	AddRoutes(router, bindplane)
@@ -154,7 +154,7 @@ func TestLogin(t *testing.T) {
 		MaxEventsToMerge: 1,
 	}, logger)
 
-	bindplane, err := server.NewBindPlane(cfg, zap.NewNop(), s, nil)
+	bindplane, err := server.NewBindPlane(cfg, zap.NewNop(), s, nil, nil)
 	require.NoError(t, err)
 
 	t.Run("will not set authenticated to true for invalid creds", func(t *testing.T) {
@@ -197,6 +197,7 @@ func TestLogin(t *testing.T) {
 		session, err := bindplane.Store().UserSessions().Get(ctx.Request, CookieName)
 		require.NoError(t, err)
 		require.Equal(t, session.Values["authenticated"], true)
+		require.Equal(t, "admin", session.Values["role"])
 	})
 
 }
@@ -216,7 +217,7 @@ func TestLogout(t *testing.T) {
 		MaxEventsToMerge: 1,
 	}, logger)
 
-	bindplane, err := server.NewBindPlane(cfg, zap.NewNop(), s, nil)
+	bindplane, err := server.NewBindPlane(cfg, zap.NewNop(), s, nil, nil)
 	require.NoError(t, err)
 
 	t.Run("will set authenticated to false for a logged in context", func(t *testing.T) {
@@ -236,6 +237,35 @@ func TestLogout(t *testing.T) {
 		session, _ := bindplane.Store().UserSessions().Get(logoutCtx.Request, CookieName)
 		require.False(t, session.Values["authenticated"].(bool))
 	})
+
+	t.Run("everywhere revokes every session of the user", func(t *testing.T) {
+		getLoggedInCookie(t, bindplane)
+
+		w := httptest.NewRecorder()
+		getLoggedInContext(t, bindplane, w)
+		cookie := getCookie(t, w, CookieName)
+		csrf := getCookie(t, w, CSRFCookieName)
+
+		// the csrf token is required
+		logoutReq := httptest.NewRequest("PUT", "/logout?everywhere=true", nil)
+		logoutReq.AddCookie(cookie)
+		logoutW := httptest.NewRecorder()
+		logoutCtx, _ := gin.CreateTestContext(logoutW)
+		logoutCtx.Request = logoutReq
+		logout(logoutCtx, bindplane)
+		require.Equal(t, http.StatusForbidden, logoutW.Code)
+
+		logoutReq = httptest.NewRequest("PUT", "/logout?everywhere=true", nil)
+		logoutReq.AddCookie(cookie)
+		logoutReq.Header.Set(CSRFHeader, csrf.Value)
+		logoutCtx, _ = gin.CreateTestContext(httptest.NewRecorder())
+		logoutCtx.Request = logoutReq
+		logout(logoutCtx, bindplane)
+
+		records, err := Records(ctx, bindplane)
+		require.NoError(t, err)
+		require.Empty(t, records)
+	})
 }
 
 func TestVerify(t *testing.T) {
@@ -253,7 +283,7 @@ func TestVerify(t *testing.T) {
 		MaxEventsToMerge: 1,
 	}, logger)
 
-	bindplane, err := server.NewBindPlane(cfg, zap.NewNop(), s, nil)
+	bindplane, err := server.NewBindPlane(cfg, zap.NewNop(), s, nil, nil)
 	require.NoError(t, err)
 
 	t.Run("aborts with status 401 when authenticated is unset", func(t *testing.T) {
@@ -281,6 +311,28 @@ func TestVerify(t *testing.T) {
 		require.Equal(t, http.StatusUnauthorized, w.Result().StatusCode)
 	})
 
+	t.Run("accepts a logged in session until it is revoked", func(t *testing.T) {
+		cookie := getLoggedInCookie(t, bindplane)
+
+		verifyCookie := func() int {
+			req := httptest.NewRequest("GET", "/verify", nil)
+			req.AddCookie(cookie)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request = req
+
+			verify(ctx, bindplane)
+			return w.Result().StatusCode
+		}
+		require.Equal(t, http.StatusOK, verifyCookie())
+
+		revoked, err := RevokeUser(ctx, bindplane, "user")
+		require.NoError(t, err)
+		require.NotEmpty(t, revoked)
+		require.Equal(t, http.StatusUnauthorized, verifyCookie())
+	})
+
 }
 
 func getLoggedInContext(t *testing.T, bindplane server.BindPlane, w http.ResponseWriter) *gin.Context {
@@ -302,7 +354,17 @@ func getLoggedInCookie(t *testing.T, bindplane server.BindPlane) *http.Cookie {
 	w := httptest.NewRecorder()
 	getLoggedInContext(t, bindplane, w)
 
-	return w.Result().Cookies()[0]
+	return getCookie(t, w, CookieName)
+}
+
+func getCookie(t *testing.T, w *httptest.ResponseRecorder, name string) *http.Cookie {
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == name {
+			return cookie
+		}
+	}
+	require.FailNow(t, "missing cookie", name)
+	return nil
 }
 
 func getLoggedOutCookie(t *testing.T, bindplane server.BindPlane) *http.Cookie {
@@ -317,5 +379,5 @@ func getLoggedOutCookie(t *testing.T, bindplane server.BindPlane) *http.Cookie {
 	session.Values["authenticated"] = false
 	session.Save(logoutReq, w)
 
-	return w.Result().Cookies()[0]
+	return getCookie(t, w, CookieName)
 }
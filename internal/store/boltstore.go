@@ -28,6 +28,7 @@ import (
 	"go.etcd.io/bbolt"
 	"go.uber.org/zap"
 
+	"github.com/observiq/bindplane-op/internal/cluster"
 	"github.com/observiq/bindplane-op/internal/eventbus"
 	"github.com/observiq/bindplane-op/internal/store/search"
 	"github.com/observiq/bindplane-op/model"
@@ -38,6 +39,12 @@ const (
 	bucketResources = "Resources"
 	bucketTasks     = "Tasks"
 	bucketAgents    = "Agents"
+
+	bucketEnrollmentTokens = "EnrollmentTokens"
+	bucketAgentCredentials = "AgentCredentials"
+	bucketAgentDenyRules   = "AgentDenyRules"
+	bucketUserSessions     = "UserSessions"
+	bucketGitOps           = "GitOps"
 )
 
 type boltstore struct {
@@ -48,6 +55,9 @@ type boltstore struct {
 	logger             *zap.Logger
 	sync.RWMutex
 	sessionStorage sessions.Store
+
+	// leases are local to this node because the bbolt file isn't shared with other nodes
+	localLeases
 }
 
 var _ Store = (*boltstore)(nil)
@@ -56,13 +66,13 @@ var _ Store = (*boltstore)(nil)
 func NewBoltStore(ctx context.Context, db *bbolt.DB, options Options, logger *zap.Logger) Store {
 	store := &boltstore{
 		db:                 db,
-		updates:            newStoreUpdates(ctx, options.MaxEventsToMerge),
 		agentIndex:         search.NewInMemoryIndex("agent"),
 		configurationIndex: search.NewInMemoryIndex("configuration"),
 		logger:             logger,
 
 		sessionStorage: newBPCookieStore(options.SessionsSecret),
 	}
+	store.updates = newStoreUpdates(ctx, options, store.agentIndex, store.configurationIndex, logger)
 
 	// boltstore is not used for clusters, disconnect all agents
 	store.disconnectAllAgents(context.Background())
@@ -82,6 +92,11 @@ func InitDB(storageFilePath string) (*bbolt.DB, error) {
 		bucketResources,
 		bucketTasks,
 		bucketAgents,
+		bucketEnrollmentTokens,
+		bucketAgentCredentials,
+		bucketAgentDenyRules,
+		bucketUserSessions,
+		bucketGitOps,
 	}
 
 	// make sure buckets exists, errors are ignored here because bucket names are
@@ -151,6 +166,115 @@ func (s *boltstore) Updates() eventbus.Source[*Updates] {
 	return s.updates.Updates()
 }
 
+// ClusterBus is used to send messages between the BindPlane nodes sharing the store
+func (s *boltstore) ClusterBus() cluster.Bus {
+	return s.updates.bus
+}
+
+// EnrollmentTokens returns all of the EnrollmentTokens
+func (s *boltstore) EnrollmentTokens(_ context.Context) ([]*model.EnrollmentToken, error) {
+	return boltRecords[model.EnrollmentToken](s.db, bucketEnrollmentTokens)
+}
+
+// EnrollmentToken returns the EnrollmentToken with the specified ID or nil if it does not exist
+func (s *boltstore) EnrollmentToken(_ context.Context, id string) (*model.EnrollmentToken, error) {
+	return boltRecord[model.EnrollmentToken](s.db, bucketEnrollmentTokens, id)
+}
+
+// CreateEnrollmentToken adds a new EnrollmentToken
+func (s *boltstore) CreateEnrollmentToken(_ context.Context, token *model.EnrollmentToken) error {
+	_, err := boltUpdateRecord(s.db, bucketEnrollmentTokens, token.ID, true, func(current *model.EnrollmentToken) error {
+		*current = *token
+		return nil
+	})
+	return err
+}
+
+// UpdateEnrollmentToken atomically updates an existing EnrollmentToken
+func (s *boltstore) UpdateEnrollmentToken(_ context.Context, id string, updater EnrollmentTokenUpdater) (*model.EnrollmentToken, error) {
+	return boltUpdateRecord(s.db, bucketEnrollmentTokens, id, false, updater)
+}
+
+// DeleteEnrollmentToken removes the EnrollmentToken and returns it or nil if it does not exist
+func (s *boltstore) DeleteEnrollmentToken(_ context.Context, id string) (*model.EnrollmentToken, error) {
+	return boltDeleteRecord[model.EnrollmentToken](s.db, bucketEnrollmentTokens, id)
+}
+
+// AgentCredential returns the credential issued to the agent or nil if there is no credential
+func (s *boltstore) AgentCredential(_ context.Context, agentID string) (*model.AgentCredential, error) {
+	return boltRecord[model.AgentCredential](s.db, bucketAgentCredentials, agentID)
+}
+
+// UpsertAgentCredential atomically adds or updates the credential of the agent
+func (s *boltstore) UpsertAgentCredential(_ context.Context, agentID string, updater AgentCredentialUpdater) (*model.AgentCredential, error) {
+	return boltUpdateRecord(s.db, bucketAgentCredentials, agentID, true, func(current *model.AgentCredential) error {
+		current.AgentID = agentID
+		return updater(current)
+	})
+}
+
+// AgentDenyRules returns all of the AgentDenyRules
+func (s *boltstore) AgentDenyRules(_ context.Context) ([]*model.AgentDenyRule, error) {
+	return boltRecords[model.AgentDenyRule](s.db, bucketAgentDenyRules)
+}
+
+// AddAgentDenyRule adds the AgentDenyRule, replacing any rule with the same ID
+func (s *boltstore) AddAgentDenyRule(_ context.Context, rule *model.AgentDenyRule) error {
+	_, err := boltUpdateRecord(s.db, bucketAgentDenyRules, rule.ID(), true, func(current *model.AgentDenyRule) error {
+		*current = *rule
+		return nil
+	})
+	return err
+}
+
+// DeleteAgentDenyRule removes the AgentDenyRule and returns it or nil if it does not exist
+func (s *boltstore) DeleteAgentDenyRule(_ context.Context, id string) (*model.AgentDenyRule, error) {
+	return boltDeleteRecord[model.AgentDenyRule](s.db, bucketAgentDenyRules, id)
+}
+
+// UserSessionRecords returns the server side records of all of the user sessions
+func (s *boltstore) UserSessionRecords(_ context.Context) ([]*model.UserSession, error) {
+	return boltRecords[model.UserSession](s.db, bucketUserSessions)
+}
+
+// UserSessionRecord returns the UserSession with the specified ID or nil if it does not exist
+func (s *boltstore) UserSessionRecord(_ context.Context, id string) (*model.UserSession, error) {
+	return boltRecord[model.UserSession](s.db, bucketUserSessions, id)
+}
+
+// CreateUserSessionRecord adds a new UserSession
+func (s *boltstore) CreateUserSessionRecord(_ context.Context, session *model.UserSession) error {
+	_, err := boltUpdateRecord(s.db, bucketUserSessions, session.ID, true, func(current *model.UserSession) error {
+		*current = *session
+		return nil
+	})
+	return err
+}
+
+// UpdateUserSessionRecord atomically updates an existing UserSession
+func (s *boltstore) UpdateUserSessionRecord(_ context.Context, id string, updater UserSessionUpdater) (*model.UserSession, error) {
+	return boltUpdateRecord(s.db, bucketUserSessions, id, false, updater)
+}
+
+// DeleteUserSessionRecord removes the UserSession and returns it or nil if it does not exist
+func (s *boltstore) DeleteUserSessionRecord(_ context.Context, id string) (*model.UserSession, error) {
+	return boltDeleteRecord[model.UserSession](s.db, bucketUserSessions, id)
+}
+
+// GitOpsSyncStatus returns the status of the most recent GitOps sync or nil if resources have not been synced
+func (s *boltstore) GitOpsSyncStatus(_ context.Context) (*model.GitOpsSyncStatus, error) {
+	return boltRecord[model.GitOpsSyncStatus](s.db, bucketGitOps, gitOpsSyncStatusKey)
+}
+
+// SetGitOpsSyncStatus saves the status of the most recent GitOps sync
+func (s *boltstore) SetGitOpsSyncStatus(_ context.Context, status *model.GitOpsSyncStatus) error {
+	_, err := boltUpdateRecord(s.db, bucketGitOps, gitOpsSyncStatusKey, true, func(current *model.GitOpsSyncStatus) error {
+		*current = *status
+		return nil
+	})
+	return err
+}
+
 // DeleteResources iterates threw a slice of resources, and removes them from storage by name.
 // Sends any successful pipeline deletes to the pipelineDeletes channel, to be handled by the manager.
 // Exporter and receiver deletes are sent to the manager via notifyUpdates.
@@ -253,6 +377,64 @@ func (s *boltstore) ApplyResources(resources []model.Resource) ([]model.Resource
 	return resourceStatuses, errs
 }
 
+// ApplyResourcesWithPreconditions applies the resources in a single transaction that first checks the preconditions
+func (s *boltstore) ApplyResourcesWithPreconditions(resources []model.Resource, preconditions []model.ResourcePrecondition) ([]model.ResourceStatus, error) {
+	var invalidStatuses []model.ResourceStatus
+	var valid []model.Resource
+	warnings := map[model.Resource]string{}
+	for _, resource := range resources {
+		resource.EnsureID()
+
+		warn, err := resource.ValidateWithStore(s)
+		if err != nil {
+			invalidStatuses = append(invalidStatuses, *model.NewResourceStatusWithReason(resource, model.StatusInvalid, err.Error()))
+			continue
+		}
+		valid = append(valid, resource)
+		warnings[resource] = warn
+	}
+
+	updates := NewUpdates()
+	resourceStatuses := invalidStatuses
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		for _, precondition := range preconditions {
+			data := resourcesBucket(tx).Get(resourceKey(precondition.Kind, precondition.Name))
+			if err := checkStoredPrecondition(precondition, data); err != nil {
+				return err
+			}
+		}
+		for _, resource := range valid {
+			status, err := upsertResource(tx, resource, resource.GetKind())
+			if err != nil {
+				return err
+			}
+			resourceStatuses = append(resourceStatuses, *model.NewResourceStatusWithReason(resource, status, warnings[resource]))
+
+			switch status {
+			case model.StatusCreated:
+				updates.IncludeResource(resource, EventTypeInsert)
+			case model.StatusConfigured:
+				updates.IncludeResource(resource, EventTypeUpdate)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, resource := range valid {
+		if r, ok := resource.(*model.Configuration); ok {
+			if err := s.configurationIndex.Upsert(r); err != nil {
+				s.logger.Error("failed to update the search index", zap.String("configuration", r.Name()))
+			}
+		}
+	}
+	s.notify(updates)
+
+	return resourceStatuses, nil
+}
+
 // ----------------------------------------------------------------------
 
 func (s *boltstore) notify(updates *Updates) {
@@ -278,12 +460,22 @@ func (s *boltstore) Clear() {
 		_ = tx.DeleteBucket([]byte(bucketResources))
 		_ = tx.DeleteBucket([]byte(bucketTasks))
 		_ = tx.DeleteBucket([]byte(bucketAgents))
+		_ = tx.DeleteBucket([]byte(bucketEnrollmentTokens))
+		_ = tx.DeleteBucket([]byte(bucketAgentCredentials))
+		_ = tx.DeleteBucket([]byte(bucketAgentDenyRules))
+		_ = tx.DeleteBucket([]byte(bucketUserSessions))
+		_ = tx.DeleteBucket([]byte(bucketGitOps))
 
 		// create them again
 		// Disregarding errors because bucket names are valid.
 		_, _ = tx.CreateBucketIfNotExists([]byte(bucketResources))
 		_, _ = tx.CreateBucketIfNotExists([]byte(bucketTasks))
 		_, _ = tx.CreateBucketIfNotExists([]byte(bucketAgents))
+		_, _ = tx.CreateBucketIfNotExists([]byte(bucketEnrollmentTokens))
+		_, _ = tx.CreateBucketIfNotExists([]byte(bucketAgentCredentials))
+		_, _ = tx.CreateBucketIfNotExists([]byte(bucketAgentDenyRules))
+		_, _ = tx.CreateBucketIfNotExists([]byte(bucketUserSessions))
+		_, _ = tx.CreateBucketIfNotExists([]byte(bucketGitOps))
 		return nil
 	})
 }
@@ -357,6 +549,10 @@ func (s *boltstore) UpsertAgent(ctx context.Context, id string, updater AgentUpd
 
 func (s *boltstore) Agents(ctx context.Context, options ...QueryOption) ([]*model.Agent, error) {
 	opts := makeQueryOptions(options)
+	collector, err := newPageCollector[*model.Agent](opts, s.agentIndex)
+	if err != nil {
+		return nil, err
+	}
 
 	// search is implemented using the search index
 	if opts.query != nil {
@@ -364,23 +560,31 @@ func (s *boltstore) Agents(ctx context.Context, options ...QueryOption) ([]*mode
 		if err != nil {
 			return nil, err
 		}
-		return s.agentsByID(ids, opts)
+		if err := s.agentsByID(ids, collector); err != nil {
+			return nil, err
+		}
+		return collector.page(), nil
 	}
 
-	agents := []*model.Agent{}
-
-	err := s.db.View(func(tx *bbolt.Tx) error {
+	err = s.db.View(func(tx *bbolt.Tx) error {
 		cursor := agentBucket(tx).Cursor()
 		prefix := []byte("Agent")
 
-		for k, v := cursor.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = cursor.Next() {
+		// agents are stored by id so we can start after the previous page and stop when the page is complete
+		start := prefix
+		if id := collector.startKey("id"); id != "" {
+			start = agentKey(id)
+		}
+
+		for k, v := cursor.Seek(start); k != nil && bytes.HasPrefix(k, prefix); k, v = cursor.Next() {
 			agent := &model.Agent{}
 			if err := json.Unmarshal(v, agent); err != nil {
 				return fmt.Errorf("agents: %w", err)
 			}
 
-			if opts.selector.Matches(agent.Labels) {
-				agents = append(agents, agent)
+			collector.add(agent)
+			if collector.complete("id") {
+				break
 			}
 		}
 
@@ -391,17 +595,7 @@ func (s *boltstore) Agents(ctx context.Context, options ...QueryOption) ([]*mode
 		return nil, err
 	}
 
-	if opts.sort == "" {
-		opts.sort = "name"
-	}
-	return applySortOffsetAndLimit(agents, opts, func(field string, item *model.Agent) string {
-		switch field {
-		case "id":
-			return item.ID
-		default:
-			return item.Name
-		}
-	}), nil
+	return collector.page(), nil
 }
 
 func (s *boltstore) DeleteAgents(ctx context.Context, agentIDs []string) ([]*model.Agent, error) {
@@ -458,28 +652,22 @@ func (s *boltstore) DeleteAgents(ctx context.Context, agentIDs []string) ([]*mod
 	return deleted, nil
 }
 
-func (s *boltstore) agentsByID(ids []string, opts queryOptions) ([]*model.Agent, error) {
-	var agents []*model.Agent
-
-	err := s.db.View(func(tx *bbolt.Tx) error {
+func (s *boltstore) agentsByID(ids []string, collector *pageCollector[*model.Agent]) error {
+	return s.db.View(func(tx *bbolt.Tx) error {
 		for _, id := range ids {
 			data := agentBucket(tx).Get(agentKey(id))
 			if data == nil {
-				return nil
+				continue
 			}
 			agent := &model.Agent{}
 			if err := json.Unmarshal(data, agent); err != nil {
 				return fmt.Errorf("agents: %w", err)
 			}
 
-			if opts.selector.Matches(agent.Labels) {
-				agents = append(agents, agent)
-			}
+			collector.add(agent)
 		}
 		return nil
 	})
-
-	return agents, err
 }
 
 func (s *boltstore) AgentsCount(ctx context.Context, options ...QueryOption) (int, error) {
@@ -512,12 +700,8 @@ func (s *boltstore) AgentVersion(name string) (*model.AgentVersion, error) {
 	}
 	return item, err
 }
-func (s *boltstore) AgentVersions() ([]*model.AgentVersion, error) {
-	result, err := resources[*model.AgentVersion](s, model.KindAgentVersion)
-	if err == nil {
-		model.SortAgentVersionsLatestFirst(result)
-	}
-	return result, err
+func (s *boltstore) AgentVersions(options ...QueryOption) ([]*model.AgentVersion, error) {
+	return queryResources[*model.AgentVersion](s, model.KindAgentVersion, makeQueryOptions(options), nil)
 }
 func (s *boltstore) DeleteAgentVersion(name string) (*model.AgentVersion, error) {
 	item, exists, err := deleteResourceAndNotify(s, model.KindAgentVersion, name, &model.AgentVersion{})
@@ -528,19 +712,7 @@ func (s *boltstore) DeleteAgentVersion(name string) (*model.AgentVersion, error)
 }
 
 func (s *boltstore) Configurations(options ...QueryOption) ([]*model.Configuration, error) {
-	opts := makeQueryOptions(options)
-	// search is implemented using the search index
-	if opts.query != nil {
-		names, err := s.configurationIndex.Search(context.TODO(), opts.query)
-		if err != nil {
-			return nil, err
-		}
-		return resourcesByName[*model.Configuration](s, model.KindConfiguration, names, opts)
-	}
-
-	return resourcesWithFilter(s, model.KindConfiguration, func(c *model.Configuration) bool {
-		return opts.selector.Matches(c.GetLabels())
-	})
+	return queryResources[*model.Configuration](s, model.KindConfiguration, makeQueryOptions(options), s.configurationIndex)
 }
 func (s *boltstore) Configuration(name string) (*model.Configuration, error) {
 	item, exists, err := resource[*model.Configuration](s, model.KindConfiguration, name)
@@ -564,8 +736,8 @@ func (s *boltstore) Source(name string) (*model.Source, error) {
 	}
 	return item, err
 }
-func (s *boltstore) Sources() ([]*model.Source, error) {
-	return resources[*model.Source](s, model.KindSource)
+func (s *boltstore) Sources(options ...QueryOption) ([]*model.Source, error) {
+	return queryResources[*model.Source](s, model.KindSource, makeQueryOptions(options), nil)
 }
 func (s *boltstore) DeleteSource(name string) (*model.Source, error) {
 	item, exists, err := deleteResourceAndNotify(s, model.KindSource, name, &model.Source{})
@@ -582,8 +754,8 @@ func (s *boltstore) SourceType(name string) (*model.SourceType, error) {
 	}
 	return item, err
 }
-func (s *boltstore) SourceTypes() ([]*model.SourceType, error) {
-	return resources[*model.SourceType](s, model.KindSourceType)
+func (s *boltstore) SourceTypes(options ...QueryOption) ([]*model.SourceType, error) {
+	return queryResources[*model.SourceType](s, model.KindSourceType, makeQueryOptions(options), nil)
 }
 func (s *boltstore) DeleteSourceType(name string) (*model.SourceType, error) {
 	item, exists, err := deleteResourceAndNotify(s, model.KindSourceType, name, &model.SourceType{})
@@ -600,8 +772,8 @@ func (s *boltstore) Processor(name string) (*model.Processor, error) {
 	}
 	return item, err
 }
-func (s *boltstore) Processors() ([]*model.Processor, error) {
-	return resources[*model.Processor](s, model.KindProcessor)
+func (s *boltstore) Processors(options ...QueryOption) ([]*model.Processor, error) {
+	return queryResources[*model.Processor](s, model.KindProcessor, makeQueryOptions(options), nil)
 }
 func (s *boltstore) DeleteProcessor(name string) (*model.Processor, error) {
 	item, exists, err := deleteResourceAndNotify(s, model.KindProcessor, name, &model.Processor{})
@@ -618,8 +790,8 @@ func (s *boltstore) ProcessorType(name string) (*model.ProcessorType, error) {
 	}
 	return item, err
 }
-func (s *boltstore) ProcessorTypes() ([]*model.ProcessorType, error) {
-	return resources[*model.ProcessorType](s, model.KindProcessorType)
+func (s *boltstore) ProcessorTypes(options ...QueryOption) ([]*model.ProcessorType, error) {
+	return queryResources[*model.ProcessorType](s, model.KindProcessorType, makeQueryOptions(options), nil)
 }
 func (s *boltstore) DeleteProcessorType(name string) (*model.ProcessorType, error) {
 	item, exists, err := deleteResourceAndNotify(s, model.KindProcessorType, name, &model.ProcessorType{})
@@ -636,8 +808,8 @@ func (s *boltstore) Destination(name string) (*model.Destination, error) {
 	}
 	return item, err
 }
-func (s *boltstore) Destinations() ([]*model.Destination, error) {
-	return resources[*model.Destination](s, model.KindDestination)
+func (s *boltstore) Destinations(options ...QueryOption) ([]*model.Destination, error) {
+	return queryResources[*model.Destination](s, model.KindDestination, makeQueryOptions(options), nil)
 }
 func (s *boltstore) DeleteDestination(name string) (*model.Destination, error) {
 	item, exists, err := deleteResourceAndNotify(s, model.KindDestination, name, &model.Destination{})
@@ -654,8 +826,8 @@ func (s *boltstore) DestinationType(name string) (*model.DestinationType, error)
 	}
 	return item, err
 }
-func (s *boltstore) DestinationTypes() ([]*model.DestinationType, error) {
-	return resources[*model.DestinationType](s, model.KindDestinationType)
+func (s *boltstore) DestinationTypes(options ...QueryOption) ([]*model.DestinationType, error) {
+	return queryResources[*model.DestinationType](s, model.KindDestinationType, makeQueryOptions(options), nil)
 }
 func (s *boltstore) DeleteDestinationType(name string) (*model.DestinationType, error) {
 	item, exists, err := deleteResourceAndNotify(s, model.KindDestinationType, name, &model.DestinationType{})
@@ -853,50 +1025,71 @@ func resource[R model.Resource](s *boltstore, kind model.Kind, name string) (res
 	return resource, exists, err
 }
 
-func resources[R model.Resource](s *boltstore, kind model.Kind) ([]R, error) {
-	return resourcesWithFilter[R](s, kind, nil)
-}
-func resourcesWithFilter[R model.Resource](s *boltstore, kind model.Kind, include func(R) bool) ([]R, error) {
-	var resources []R
+// queryResources returns the page of resources of the specified kind matching the query options. If an index is
+// specified, it is used to find the resources matching the query.
+func queryResources[R model.Resource](s *boltstore, kind model.Kind, opts queryOptions, index search.Index) ([]R, error) {
+	collector, err := newPageCollector[R](opts, index)
+	if err != nil {
+		return nil, err
+	}
 
-	err := s.db.View(func(tx *bbolt.Tx) error {
+	// search is implemented using the search index
+	if opts.query != nil && index != nil {
+		names, err := index.Search(context.TODO(), opts.query)
+		if err != nil {
+			return nil, err
+		}
+		if err := resourcesByName(s, kind, names, collector); err != nil {
+			return nil, err
+		}
+		return collector.page(), nil
+	}
+
+	err = s.db.View(func(tx *bbolt.Tx) error {
 		prefix := resourcesPrefix(kind)
 		cursor := resourcesBucket(tx).Cursor()
 
-		for k, v := cursor.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = cursor.Next() {
+		// resources are stored by name so we can start after the previous page and stop when the page is complete
+		start := prefix
+		if name := collector.startKey("name"); name != "" {
+			start = resourceKey(kind, name)
+		}
+
+		for k, v := cursor.Seek(start); k != nil && bytes.HasPrefix(k, prefix); k, v = cursor.Next() {
 			var resource R
 			if err := json.Unmarshal(v, &resource); err != nil {
 				// TODO(andy): if it can't be unmarshaled, it should probably be removed from the store. ignore it for now.
 				s.logger.Error("failed to unmarshal resource", zap.String("key", string(k)), zap.String("kind", string(kind)), zap.Error(err))
 				continue
 			}
-			if include == nil || include(resource) {
-				resources = append(resources, resource)
+			collector.add(resource)
+			if collector.complete("name") {
+				break
 			}
 		}
 
 		return nil
 	})
+	if err != nil {
+		return nil, err
+	}
 
-	return resources, err
+	return collector.page(), nil
 }
 
-// resourcesByName returns the resources of the specified name with the specified names. If requesting some resources
-// results in an error, the errors will be accumulated and return with the list of resources successfully retrieved.
-func resourcesByName[R model.Resource](s *boltstore, kind model.Kind, names []string, opts queryOptions) ([]R, error) {
+// resourcesByName adds the resources of the specified kind with the specified names to the collector. If requesting
+// some resources results in an error, the errors will be accumulated and returned.
+func resourcesByName[R model.Resource](s *boltstore, kind model.Kind, names []string, collector *pageCollector[R]) error {
 	var errs error
-	var results []R
 
 	for _, name := range names {
 		if result, exists, err := resource[R](s, kind, name); err != nil {
 			errs = multierror.Append(errs, err)
-		} else {
-			if exists && opts.selector.Matches(result.GetLabels()) {
-				results = append(results, result)
-			}
+		} else if exists {
+			collector.add(result)
 		}
 	}
-	return results, errs
+	return errs
 }
 
 func deleteResourceAndNotify[R model.Resource](s *boltstore, kind model.Kind, name string, emptyResource R) (resource R, exists bool, err error) {
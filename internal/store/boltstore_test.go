@@ -316,6 +316,17 @@ func TestBoltstoreApplyResourceReturn(t *testing.T) {
 	runApplyResourceReturnTests(t, store)
 }
 
+func TestBoltstoreApplyResourcesWithPreconditions(t *testing.T) {
+	db, err := initTestDB(t)
+	require.NoError(t, err)
+	defer cleanupTestDB(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	store := NewBoltStore(ctx, db, testOptions, zap.NewNop())
+	runApplyResourcesWithPreconditionsTests(t, store)
+}
+
 func TestBoltstoreDeleteResourcesReturn(t *testing.T) {
 	db, err := initTestDB(t)
 	require.NoError(t, err)
@@ -383,13 +394,14 @@ func TestInitDB(t *testing.T) {
 			require.NoError(t, db.Close())
 
 			// cursor count increases by 2 for every empty bucket created
-			// a count of 6 means we have three buckets.
-			bucketCount := 3
+			// a count of 16 means we have eight buckets.
+			bucketCount := 8
 			require.Equal(t, bucketCount*2, db.Stats().TxStats.CursorCount)
 
-			// InitDB creates three buckets: Resources, Tasks, Agents
+			// InitDB creates eight buckets: Resources, Tasks, Agents, EnrollmentTokens, AgentCredentials, AgentDenyRules,
+			// UserSessions, GitOps
 			_ = db.Update(func(tx *bbolt.Tx) error {
-				for _, bucket := range []string{bucketResources, bucketTasks, bucketAgents} {
+				for _, bucket := range []string{bucketResources, bucketTasks, bucketAgents, bucketEnrollmentTokens, bucketAgentCredentials, bucketAgentDenyRules, bucketUserSessions, bucketGitOps} {
 					// Deleting the bucket
 					err := tx.DeleteBucket([]byte(bucket))
 					require.NoError(t, err, "expected bucket %s to exist", bucket)
@@ -434,6 +446,11 @@ func TestBucketNames(t *testing.T) {
 	require.Equal(t, "Resources", bucketResources)
 	require.Equal(t, "Tasks", bucketTasks)
 	require.Equal(t, "Agents", bucketAgents)
+	require.Equal(t, "EnrollmentTokens", bucketEnrollmentTokens)
+	require.Equal(t, "AgentCredentials", bucketAgentCredentials)
+	require.Equal(t, "AgentDenyRules", bucketAgentDenyRules)
+	require.Equal(t, "UserSessions", bucketUserSessions)
+	require.Equal(t, "GitOps", bucketGitOps)
 }
 
 func TestBoltstoreDependentResources(t *testing.T) {
@@ -467,6 +484,7 @@ func TestBoltstorePaging(t *testing.T) {
 	defer cancel()
 	store := NewBoltStore(ctx, db, testOptions, zap.NewNop())
 	runPagingTests(t, store)
+	runResourcePagingTests(t, store)
 }
 
 func TestBoltStoreDeleteAgents(t *testing.T) {
@@ -491,6 +509,17 @@ func TestBoltstoreUpsertAgents(t *testing.T) {
 	runTestUpsertAgents(t, store)
 }
 
+func TestBoltstoreEnrollment(t *testing.T) {
+	db, err := initTestDB(t)
+	require.NoError(t, err)
+	defer cleanupTestDB(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	store := NewBoltStore(ctx, db, testOptions, zap.NewNop())
+	runEnrollmentTests(t, store)
+}
+
 /* ------------------------ SETUP + HELPER FUNCTIONS ------------------------ */
 
 func initTestDB(t *testing.T) (*bbolt.DB, error) {
@@ -28,12 +28,14 @@ import (
 	"github.com/gorilla/sessions"
 	"github.com/hashicorp/go-multierror"
 	"github.com/observiq/bindplane-op/common"
+	"github.com/observiq/bindplane-op/internal/cluster"
 	"github.com/observiq/bindplane-op/internal/eventbus"
 	"github.com/observiq/bindplane-op/internal/store/search"
 	"github.com/observiq/bindplane-op/model"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
+	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
 	"google.golang.org/grpc"
 )
@@ -43,46 +45,55 @@ var tracer = otel.Tracer("googlecloudstore")
 type googleCloudStore struct {
 	client             *datastore.Client
 	pubsub             *pubsubClient
-	updates            eventbus.Source[*Updates]
+	updates            *storeUpdates
 	agentIndex         search.Index
 	configurationIndex search.Index
 	logger             *zap.Logger
 
 	sessionStore sessions.Store
+
+	// subscribers receives messages from the Pub/Sub subscription if the Pub/Sub topic is used as the cluster.Bus
+	subscribers *cluster.LocalBus
 }
 
 var _ Store = (*googleCloudStore)(nil)
 
-// NewGoogleCloudStore creates a new Google Cloud store that uses Cloud Datastore for storage and Pub/sub for events.
-func NewGoogleCloudStore(ctx context.Context, cfg *common.Server, logger *zap.Logger) (Store, error) {
+// NewGoogleCloudStore creates a new Google Cloud store that uses Cloud Datastore for storage and Pub/sub for events. If
+// options.Bus is specified, it is used for events instead of Pub/sub.
+func NewGoogleCloudStore(ctx context.Context, cfg *common.Server, options Options, logger *zap.Logger) (Store, error) {
 	datastoreClient, err := createDatastore(ctx, cfg.GoogleCloudDatastore)
 	if err != nil {
 		return nil, err
 	}
 
-	pubsubClient, err := createPubSub(ctx, cfg.GoogleCloudPubSub)
-	if err != nil {
-		return nil, err
-	}
-
 	s := &googleCloudStore{
 		client:             datastoreClient,
-		pubsub:             pubsubClient,
-		updates:            eventbus.NewSource[*Updates](),
 		agentIndex:         search.NewInMemoryIndex("agent"),
 		configurationIndex: search.NewInMemoryIndex("configuration"),
 		logger:             logger,
 
 		sessionStore: newBPCookieStore(cfg.SessionsSecret),
+		subscribers:  cluster.NewLocalBus(),
 	}
 
-	// start listening for events
-	go func() {
-		err = pubsubClient.subscriber.Receive(ctx, s.receivePubsubMessage)
+	if options.Bus == nil {
+		pubsubClient, err := createPubSub(ctx, cfg.GoogleCloudPubSub)
 		if err != nil {
-			logger.Fatal("subscriber failed", zap.Error(err))
+			return nil, err
 		}
-	}()
+		s.pubsub = pubsubClient
+		options.Bus = (*pubsubBus)(s)
+
+		// start listening for events
+		go func() {
+			err := pubsubClient.subscriber.Receive(ctx, s.receivePubsubMessage)
+			if err != nil {
+				logger.Fatal("subscriber failed", zap.Error(err))
+			}
+		}()
+	}
+
+	s.updates = newStoreUpdates(ctx, options, s.agentIndex, s.configurationIndex, logger)
 
 	return s, nil
 }
@@ -114,7 +125,7 @@ func (s *googleCloudStore) Agents(ctx context.Context, options ...QueryOption) (
 		span.SetAttributes(attribute.String("bindplane.list.query", opts.query.Original))
 	}
 
-	return getDatastoreResourcesWithQuery[*model.Agent](ctx, s, s.agentIndex, model.KindAgent, &opts)
+	return queryDatastoreResources[*model.Agent](ctx, s, s.agentIndex, model.KindAgent, opts)
 }
 
 func (s *googleCloudStore) AgentsCount(ctx context.Context, options ...QueryOption) (int, error) {
@@ -244,8 +255,8 @@ func (s *googleCloudStore) AgentVersion(name string) (*model.AgentVersion, error
 	}
 	return item, err
 }
-func (s *googleCloudStore) AgentVersions() ([]*model.AgentVersion, error) {
-	return getDatastoreResources[*model.AgentVersion](s, model.KindAgentVersion, nil)
+func (s *googleCloudStore) AgentVersions(options ...QueryOption) ([]*model.AgentVersion, error) {
+	return queryDatastoreResources[*model.AgentVersion](context.TODO(), s, nil, model.KindAgentVersion, makeQueryOptions(options))
 }
 func (s *googleCloudStore) DeleteAgentVersion(name string) (*model.AgentVersion, error) {
 	item, exists, err := deleteDatastoreResourceAndNotify[*model.AgentVersion](s, model.KindAgentVersion, name)
@@ -256,8 +267,7 @@ func (s *googleCloudStore) DeleteAgentVersion(name string) (*model.AgentVersion,
 }
 
 func (s *googleCloudStore) Configurations(options ...QueryOption) ([]*model.Configuration, error) {
-	opts := makeQueryOptions(options)
-	return getDatastoreResourcesWithQuery[*model.Configuration](context.TODO(), s, s.configurationIndex, model.KindConfiguration, &opts)
+	return queryDatastoreResources[*model.Configuration](context.TODO(), s, s.configurationIndex, model.KindConfiguration, makeQueryOptions(options))
 }
 
 func (s *googleCloudStore) Configuration(name string) (*model.Configuration, error) {
@@ -282,8 +292,8 @@ func (s *googleCloudStore) Source(name string) (*model.Source, error) {
 	}
 	return item, err
 }
-func (s *googleCloudStore) Sources() ([]*model.Source, error) {
-	return getDatastoreResources[*model.Source](s, model.KindSource, nil)
+func (s *googleCloudStore) Sources(options ...QueryOption) ([]*model.Source, error) {
+	return queryDatastoreResources[*model.Source](context.TODO(), s, nil, model.KindSource, makeQueryOptions(options))
 }
 func (s *googleCloudStore) DeleteSource(name string) (*model.Source, error) {
 	item, exists, err := deleteDatastoreResourceAndNotify[*model.Source](s, model.KindSource, name)
@@ -300,8 +310,8 @@ func (s *googleCloudStore) SourceType(name string) (*model.SourceType, error) {
 	}
 	return item, err
 }
-func (s *googleCloudStore) SourceTypes() ([]*model.SourceType, error) {
-	return getDatastoreResources[*model.SourceType](s, model.KindSourceType, nil)
+func (s *googleCloudStore) SourceTypes(options ...QueryOption) ([]*model.SourceType, error) {
+	return queryDatastoreResources[*model.SourceType](context.TODO(), s, nil, model.KindSourceType, makeQueryOptions(options))
 }
 func (s *googleCloudStore) DeleteSourceType(name string) (*model.SourceType, error) {
 	item, exists, err := deleteDatastoreResourceAndNotify[*model.SourceType](s, model.KindSourceType, name)
@@ -318,8 +328,8 @@ func (s *googleCloudStore) Processor(name string) (*model.Processor, error) {
 	}
 	return item, err
 }
-func (s *googleCloudStore) Processors() ([]*model.Processor, error) {
-	return getDatastoreResources[*model.Processor](s, model.KindProcessor, nil)
+func (s *googleCloudStore) Processors(options ...QueryOption) ([]*model.Processor, error) {
+	return queryDatastoreResources[*model.Processor](context.TODO(), s, nil, model.KindProcessor, makeQueryOptions(options))
 }
 func (s *googleCloudStore) DeleteProcessor(name string) (*model.Processor, error) {
 	item, exists, err := deleteDatastoreResourceAndNotify[*model.Processor](s, model.KindProcessor, name)
@@ -336,8 +346,8 @@ func (s *googleCloudStore) ProcessorType(name string) (*model.ProcessorType, err
 	}
 	return item, err
 }
-func (s *googleCloudStore) ProcessorTypes() ([]*model.ProcessorType, error) {
-	return getDatastoreResources[*model.ProcessorType](s, model.KindProcessorType, nil)
+func (s *googleCloudStore) ProcessorTypes(options ...QueryOption) ([]*model.ProcessorType, error) {
+	return queryDatastoreResources[*model.ProcessorType](context.TODO(), s, nil, model.KindProcessorType, makeQueryOptions(options))
 }
 func (s *googleCloudStore) DeleteProcessorType(name string) (*model.ProcessorType, error) {
 	item, exists, err := deleteDatastoreResourceAndNotify[*model.ProcessorType](s, model.KindProcessorType, name)
@@ -354,8 +364,8 @@ func (s *googleCloudStore) Destination(name string) (*model.Destination, error)
 	}
 	return item, err
 }
-func (s *googleCloudStore) Destinations() ([]*model.Destination, error) {
-	return getDatastoreResources[*model.Destination](s, model.KindDestination, nil)
+func (s *googleCloudStore) Destinations(options ...QueryOption) ([]*model.Destination, error) {
+	return queryDatastoreResources[*model.Destination](context.TODO(), s, nil, model.KindDestination, makeQueryOptions(options))
 }
 func (s *googleCloudStore) DeleteDestination(name string) (*model.Destination, error) {
 	item, exists, err := deleteDatastoreResourceAndNotify[*model.Destination](s, model.KindDestination, name)
@@ -372,8 +382,8 @@ func (s *googleCloudStore) DestinationType(name string) (*model.DestinationType,
 	}
 	return item, err
 }
-func (s *googleCloudStore) DestinationTypes() ([]*model.DestinationType, error) {
-	return getDatastoreResources[*model.DestinationType](s, model.KindDestinationType, nil)
+func (s *googleCloudStore) DestinationTypes(options ...QueryOption) ([]*model.DestinationType, error) {
+	return queryDatastoreResources[*model.DestinationType](context.TODO(), s, nil, model.KindDestinationType, makeQueryOptions(options))
 }
 func (s *googleCloudStore) DeleteDestinationType(name string) (*model.DestinationType, error) {
 	item, exists, err := deleteDatastoreResourceAndNotify[*model.DestinationType](s, model.KindDestinationType, name)
@@ -426,6 +436,68 @@ func (s *googleCloudStore) ApplyResources(resources []model.Resource) ([]model.R
 	return resourceStatuses, errs
 }
 
+// ApplyResourcesWithPreconditions applies the resources in a single transaction that first checks the preconditions
+func (s *googleCloudStore) ApplyResourcesWithPreconditions(resources []model.Resource, preconditions []model.ResourcePrecondition) ([]model.ResourceStatus, error) {
+	var invalidStatuses []model.ResourceStatus
+	var valid []model.Resource
+	warnings := map[model.Resource]string{}
+	for _, resource := range resources {
+		resource.EnsureID()
+
+		warn, err := resource.ValidateWithStore(s)
+		if err != nil {
+			invalidStatuses = append(invalidStatuses, *model.NewResourceStatusWithReason(resource, model.StatusInvalid, err.Error()))
+			continue
+		}
+		valid = append(valid, resource)
+		warnings[resource] = warn
+	}
+
+	var resourceStatuses []model.ResourceStatus
+	var updates *Updates
+	_, err := s.client.RunInTransaction(context.TODO(), func(tx *datastore.Transaction) error {
+		// the transaction may be retried
+		resourceStatuses = invalidStatuses
+		updates = NewUpdates()
+
+		for _, precondition := range preconditions {
+			var current datastoreResource
+			var data []byte
+			err := tx.Get(datastoreKey(precondition.Kind, precondition.Name), &current)
+			switch {
+			case err == nil:
+				data = current.Body
+			case !errors.Is(err, datastore.ErrNoSuchEntity):
+				return fmt.Errorf("failed to get the resource: %w", err)
+			}
+			if err := checkStoredPrecondition(precondition, data); err != nil {
+				return err
+			}
+		}
+		for _, resource := range valid {
+			status, err := upsertDatastoreResourceTx(tx, resource)
+			if err != nil {
+				return err
+			}
+			resourceStatuses = append(resourceStatuses, *model.NewResourceStatusWithReason(resource, status, warnings[resource]))
+
+			switch status {
+			case model.StatusCreated:
+				updates.IncludeResource(resource, EventTypeInsert)
+			case model.StatusConfigured:
+				updates.IncludeResource(resource, EventTypeUpdate)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.notify(updates)
+
+	return resourceStatuses, nil
+}
+
 // Batch delete of a slice of resources, returns the successfully deleted resources or an error.
 func (s *googleCloudStore) DeleteResources(resources []model.Resource) ([]model.ResourceStatus, error) {
 	updates := NewUpdates()
@@ -512,7 +584,7 @@ func (s *googleCloudStore) CleanupDisconnectedAgents(since time.Time) error {
 // configuration changed or a component in them was updated. Agents with labels that change are also sent with
 // Updates.
 func (s *googleCloudStore) Updates() eventbus.Source[*Updates] {
-	return s.updates
+	return s.updates.Updates()
 }
 
 // Index provides access to the search Index implementation managed by the Store
@@ -525,6 +597,185 @@ func (s *googleCloudStore) ConfigurationIndex() search.Index {
 	return s.configurationIndex
 }
 
+// ClusterBus is used to send messages between the BindPlane nodes sharing the store. Unless another Bus is configured,
+// it uses the Pub/Sub topic.
+func (s *googleCloudStore) ClusterBus() cluster.Bus {
+	return s.updates.bus
+}
+
+// datastoreLeaseKind is the Datastore kind used to store leases
+const datastoreLeaseKind = "Lease"
+
+type datastoreLease struct {
+	Holder  string
+	Expires time.Time
+}
+
+// AcquireLease acquires or renews the named lease for the holder using a Datastore transaction so that only one node
+// holds the lease at a time. It returns false if the lease is held by another holder and has not expired.
+func (s *googleCloudStore) AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	key := datastore.NameKey(datastoreLeaseKind, name, nil)
+	acquired := false
+	_, err := s.client.RunInTransaction(ctx, func(tx *datastore.Transaction) error {
+		now := time.Now()
+		var current datastoreLease
+		err := tx.Get(key, &current)
+		switch {
+		case errors.Is(err, datastore.ErrNoSuchEntity):
+		case err != nil:
+			return err
+		case current.Holder != holder && now.Before(current.Expires):
+			acquired = false
+			return nil
+		}
+		_, err = tx.Put(key, &datastoreLease{
+			Holder:  holder,
+			Expires: now.Add(ttl),
+		})
+		acquired = err == nil
+		return err
+	})
+	if err != nil {
+		return false, fmt.Errorf("acquire lease %s: %w", name, err)
+	}
+	return acquired, nil
+}
+
+// ReleaseLease releases the named lease if it is held by the holder
+func (s *googleCloudStore) ReleaseLease(ctx context.Context, name, holder string) error {
+	key := datastore.NameKey(datastoreLeaseKind, name, nil)
+	_, err := s.client.RunInTransaction(ctx, func(tx *datastore.Transaction) error {
+		var current datastoreLease
+		err := tx.Get(key, &current)
+		switch {
+		case errors.Is(err, datastore.ErrNoSuchEntity):
+			return nil
+		case err != nil:
+			return err
+		case current.Holder != holder:
+			return nil
+		}
+		return tx.Delete(key)
+	})
+	if err != nil {
+		return fmt.Errorf("release lease %s: %w", name, err)
+	}
+	return nil
+}
+
+// Datastore kinds used to store records that aren't resources
+const (
+	datastoreEnrollmentTokenKind = "EnrollmentToken"
+	datastoreAgentCredentialKind = "AgentCredential"
+	datastoreAgentDenyRuleKind   = "AgentDenyRule"
+	datastoreUserSessionKind     = "UserSession"
+	datastoreGitOpsKind          = "GitOpsSyncStatus"
+)
+
+// EnrollmentTokens returns all of the EnrollmentTokens
+func (s *googleCloudStore) EnrollmentTokens(ctx context.Context) ([]*model.EnrollmentToken, error) {
+	return datastoreRecords[model.EnrollmentToken](ctx, s.client, datastoreEnrollmentTokenKind)
+}
+
+// EnrollmentToken returns the EnrollmentToken with the specified ID or nil if it does not exist
+func (s *googleCloudStore) EnrollmentToken(ctx context.Context, id string) (*model.EnrollmentToken, error) {
+	return datastoreGetRecord[model.EnrollmentToken](ctx, s.client, datastoreEnrollmentTokenKind, id)
+}
+
+// CreateEnrollmentToken adds a new EnrollmentToken
+func (s *googleCloudStore) CreateEnrollmentToken(ctx context.Context, token *model.EnrollmentToken) error {
+	_, err := datastoreUpdateRecord(ctx, s.client, datastoreEnrollmentTokenKind, token.ID, true, func(current *model.EnrollmentToken) error {
+		*current = *token
+		return nil
+	})
+	return err
+}
+
+// UpdateEnrollmentToken atomically updates an existing EnrollmentToken
+func (s *googleCloudStore) UpdateEnrollmentToken(ctx context.Context, id string, updater EnrollmentTokenUpdater) (*model.EnrollmentToken, error) {
+	return datastoreUpdateRecord(ctx, s.client, datastoreEnrollmentTokenKind, id, false, updater)
+}
+
+// DeleteEnrollmentToken removes the EnrollmentToken and returns it or nil if it does not exist
+func (s *googleCloudStore) DeleteEnrollmentToken(ctx context.Context, id string) (*model.EnrollmentToken, error) {
+	return datastoreDeleteRecord[model.EnrollmentToken](ctx, s.client, datastoreEnrollmentTokenKind, id)
+}
+
+// AgentCredential returns the credential issued to the agent or nil if there is no credential
+func (s *googleCloudStore) AgentCredential(ctx context.Context, agentID string) (*model.AgentCredential, error) {
+	return datastoreGetRecord[model.AgentCredential](ctx, s.client, datastoreAgentCredentialKind, agentID)
+}
+
+// UpsertAgentCredential atomically adds or updates the credential of the agent
+func (s *googleCloudStore) UpsertAgentCredential(ctx context.Context, agentID string, updater AgentCredentialUpdater) (*model.AgentCredential, error) {
+	return datastoreUpdateRecord(ctx, s.client, datastoreAgentCredentialKind, agentID, true, func(current *model.AgentCredential) error {
+		current.AgentID = agentID
+		return updater(current)
+	})
+}
+
+// AgentDenyRules returns all of the AgentDenyRules
+func (s *googleCloudStore) AgentDenyRules(ctx context.Context) ([]*model.AgentDenyRule, error) {
+	return datastoreRecords[model.AgentDenyRule](ctx, s.client, datastoreAgentDenyRuleKind)
+}
+
+// AddAgentDenyRule adds the AgentDenyRule, replacing any rule with the same ID
+func (s *googleCloudStore) AddAgentDenyRule(ctx context.Context, rule *model.AgentDenyRule) error {
+	_, err := datastoreUpdateRecord(ctx, s.client, datastoreAgentDenyRuleKind, rule.ID(), true, func(current *model.AgentDenyRule) error {
+		*current = *rule
+		return nil
+	})
+	return err
+}
+
+// DeleteAgentDenyRule removes the AgentDenyRule and returns it or nil if it does not exist
+func (s *googleCloudStore) DeleteAgentDenyRule(ctx context.Context, id string) (*model.AgentDenyRule, error) {
+	return datastoreDeleteRecord[model.AgentDenyRule](ctx, s.client, datastoreAgentDenyRuleKind, id)
+}
+
+// UserSessionRecords returns the server side records of all of the user sessions
+func (s *googleCloudStore) UserSessionRecords(ctx context.Context) ([]*model.UserSession, error) {
+	return datastoreRecords[model.UserSession](ctx, s.client, datastoreUserSessionKind)
+}
+
+// UserSessionRecord returns the UserSession with the specified ID or nil if it does not exist
+func (s *googleCloudStore) UserSessionRecord(ctx context.Context, id string) (*model.UserSession, error) {
+	return datastoreGetRecord[model.UserSession](ctx, s.client, datastoreUserSessionKind, id)
+}
+
+// CreateUserSessionRecord adds a new UserSession
+func (s *googleCloudStore) CreateUserSessionRecord(ctx context.Context, session *model.UserSession) error {
+	_, err := datastoreUpdateRecord(ctx, s.client, datastoreUserSessionKind, session.ID, true, func(current *model.UserSession) error {
+		*current = *session
+		return nil
+	})
+	return err
+}
+
+// UpdateUserSessionRecord atomically updates an existing UserSession
+func (s *googleCloudStore) UpdateUserSessionRecord(ctx context.Context, id string, updater UserSessionUpdater) (*model.UserSession, error) {
+	return datastoreUpdateRecord(ctx, s.client, datastoreUserSessionKind, id, false, updater)
+}
+
+// DeleteUserSessionRecord removes the UserSession and returns it or nil if it does not exist
+func (s *googleCloudStore) DeleteUserSessionRecord(ctx context.Context, id string) (*model.UserSession, error) {
+	return datastoreDeleteRecord[model.UserSession](ctx, s.client, datastoreUserSessionKind, id)
+}
+
+// GitOpsSyncStatus returns the status of the most recent GitOps sync or nil if resources have not been synced
+func (s *googleCloudStore) GitOpsSyncStatus(ctx context.Context) (*model.GitOpsSyncStatus, error) {
+	return datastoreGetRecord[model.GitOpsSyncStatus](ctx, s.client, datastoreGitOpsKind, gitOpsSyncStatusKey)
+}
+
+// SetGitOpsSyncStatus saves the status of the most recent GitOps sync
+func (s *googleCloudStore) SetGitOpsSyncStatus(ctx context.Context, status *model.GitOpsSyncStatus) error {
+	_, err := datastoreUpdateRecord(ctx, s.client, datastoreGitOpsKind, gitOpsSyncStatusKey, true, func(current *model.GitOpsSyncStatus) error {
+		*current = *status
+		return nil
+	})
+	return err
+}
+
 // TODO (auth) we need to implement this interface in google cloudstore to allow a
 // multi-node running of BindPlane
 func (s *googleCloudStore) UserSessions() sessions.Store {
@@ -535,7 +786,7 @@ func (s *googleCloudStore) UserSessions() sessions.Store {
 // events
 
 func (s *googleCloudStore) notify(updates *Updates) {
-	ctx, span := tracer.Start(context.TODO(), "store/notify")
+	_, span := tracer.Start(context.TODO(), "store/notify")
 	defer span.End()
 
 	err := updates.addTransitiveUpdates(s)
@@ -544,18 +795,9 @@ func (s *googleCloudStore) notify(updates *Updates) {
 		s.logger.Error("unable to add transitive updates", zap.Any("updates", updates), zap.Error(err))
 	}
 	if !updates.Empty() {
-		// send to pub/sub. eventually the messages will return to this node as events.
-		s.sendPubsubMessage(ctx, updates)
-	}
-}
-
-func (s *googleCloudStore) sendPubsubMessage(ctx context.Context, updates *Updates) {
-	bytes, err := json.Marshal(updates)
-	if err != nil {
-		s.logger.Warn(fmt.Sprintf("failed to marshal Updates message: %s", err))
-		return
+		// send to all nodes. eventually the messages will return to this node as events.
+		s.updates.Send(updates)
 	}
-	s.pubsub.publisher.Publish(ctx, &pubsub.Message{Data: bytes})
 }
 
 // receivePubsubMessage receives messages from this node or other nodes and forwards them to subscribers in this node
@@ -565,44 +807,38 @@ func (s *googleCloudStore) receivePubsubMessage(ctx context.Context, msg *pubsub
 
 	defer msg.Ack()
 
-	var updates Updates
-	if err := json.Unmarshal(msg.Data, &updates); err != nil {
-		s.logger.Warn(fmt.Sprintf("failed to unmarshal Updates message: %s", err))
-		return
+	// messages without a subject are Updates sent by nodes that don't use the subject attribute
+	subject := msg.Attributes[pubsubSubjectAttribute]
+	if subject == "" {
+		subject = subjectStoreUpdates
 	}
+	span.SetAttributes(attribute.String("subject", subject))
 
-	span.SetAttributes(
-		attribute.Int("configurations", len(updates.Configurations)),
-		attribute.Int("agents", len(updates.Agents)),
-	)
+	_ = s.subscribers.Publish(ctx, subject, msg.Data)
+}
 
-	// update indexes which must be in sync across servers
-	for _, event := range updates.Configurations {
-		updateIndex(s, s.configurationIndex, event)
-	}
-	for _, event := range updates.Agents {
-		updateIndex(s, s.agentIndex, event)
-	}
+// pubsubSubjectAttribute is the Pub/Sub message attribute with the subject of messages sent with the pubsubBus
+const pubsubSubjectAttribute = "subject"
 
-	s.updates.Send(&updates)
-}
+// pubsubBus implements cluster.Bus with the Pub/Sub topic. Every node has its own subscription to the topic, so messages
+// are received by every node and delivered to the handlers subscribed on that node.
+type pubsubBus googleCloudStore
+
+var _ cluster.Bus = (*pubsubBus)(nil)
 
-type indexed interface {
-	model.HasUniqueKey
-	search.Indexed
+// Publish sends the data to the handlers subscribed to the subject on every node
+func (b *pubsubBus) Publish(ctx context.Context, subject string, data []byte) error {
+	result := b.pubsub.publisher.Publish(ctx, &pubsub.Message{
+		Data:       data,
+		Attributes: map[string]string{pubsubSubjectAttribute: subject},
+	})
+	_, err := result.Get(ctx)
+	return err
 }
 
-func updateIndex[T indexed](s *googleCloudStore, index search.Index, event Event[T]) {
-	var err error
-	switch event.Type {
-	case EventTypeRemove:
-		err = index.Remove(event.Item)
-	default:
-		err = index.Upsert(event.Item)
-	}
-	if err != nil {
-		s.logger.Error("failed to update the search index", zap.String("ID", event.Item.IndexID()), zap.Error(err))
-	}
+// Subscribe adds a handler for messages published to the subject and returns a function to unsubscribe
+func (b *pubsubBus) Subscribe(subject string, handler cluster.Handler) func() {
+	return b.subscribers.Subscribe(subject, handler)
 }
 
 // ----------------------------------------------------------------------
@@ -614,29 +850,11 @@ func datastoreKey(kind model.Kind, uniqueKey string) *datastore.Key {
 
 func datastoreQuery(kind model.Kind, opts *queryOptions) *datastore.Query {
 	query := datastore.NewQuery(string(kind))
-	if opts != nil {
-		if opts.offset > 0 {
-			query = query.Offset(opts.offset)
-		}
-		if opts.limit > 0 {
-			query = query.Limit(opts.limit)
-		}
-		if opts.sort != "" && kind == model.KindAgent {
-			// sort currently only supported for agents for id, name, or status and id is key order
-			switch opts.sort {
-			case "name":
-				query = query.Order("name")
-			case "status":
-				query = query.Order("status")
-			}
-		}
-
-		if !opts.selector.Empty() {
-			// translate requirements to a query
-			labels, _ := opts.selector.MatchLabels()
-			for k, v := range labels {
-				query = query.Filter("labels=", fmt.Sprintf("%s=%s", k, v))
-			}
+	if opts != nil && !opts.selector.Empty() {
+		// translate requirements to a query
+		labels, _ := opts.selector.MatchLabels()
+		for k, v := range labels {
+			query = query.Filter("labels=", fmt.Sprintf("%s=%s", k, v))
 		}
 	}
 
@@ -759,6 +977,32 @@ func upsertDatastoreResource[R model.Resource](s *googleCloudStore, r R) (model.
 	return successStatus, err
 }
 
+// upsertDatastoreResourceTx puts the resource in the transaction, preserving the ID of an existing resource
+func upsertDatastoreResourceTx(tx *datastore.Transaction, r model.Resource) (model.UpdateStatus, error) {
+	successStatus := model.StatusCreated
+	var existing datastoreResource
+	err := tx.Get(datastoreKey(r.GetKind(), r.UniqueKey()), &existing)
+	switch {
+	case err == nil:
+		successStatus = model.StatusConfigured
+		var current model.AnyResource
+		if err := json.Unmarshal(existing.Body, &current); err == nil {
+			r.SetID(current.ID())
+		}
+	case !errors.Is(err, datastore.ErrNoSuchEntity):
+		return model.StatusError, fmt.Errorf("failed to get the resource: %w", err)
+	}
+
+	dsr, err := newDatastoreResource(r)
+	if err != nil {
+		return model.StatusUnchanged, fmt.Errorf("failed to marshal the resource: %w", err)
+	}
+	if _, err := tx.Put(dsr.Key, dsr); err != nil {
+		return model.StatusUnchanged, fmt.Errorf("failed to put the resource: %w", err)
+	}
+	return successStatus, nil
+}
+
 func getDatastoreResource[R any](s *googleCloudStore, kind model.Kind, name string) (resource R, exists bool, err error) {
 	var dsr datastoreResource
 
@@ -837,38 +1081,61 @@ func deleteDatastoreResource[R model.Resource](s *googleCloudStore, kind model.K
 	return resource, true, nil
 }
 
-func getDatastoreResources[R any](s *googleCloudStore, kind model.Kind, opts *queryOptions) ([]R, error) {
-	query := datastoreQuery(kind, opts)
-	var list []datastoreResource
-	if _, err := s.client.GetAll(context.TODO(), query, &list); err != nil {
+// queryDatastoreResources returns the page of resources of the specified kind matching the query options. Resources
+// are read from the datastore one at a time so that only the resources on the page are kept in memory. If an index is
+// specified, it is used to find the resources matching the query.
+func queryDatastoreResources[R listItem](ctx context.Context, s *googleCloudStore, index search.Index, kind model.Kind, opts queryOptions) ([]R, error) {
+	collector, err := newPageCollector[R](opts, index)
+	if err != nil {
 		return nil, err
 	}
 
-	results := make([]R, 0, len(list))
-	for _, dsr := range list {
-		dsr := dsr // copy to local variable to securely pass a reference to a loop variable
-		var result R
-		if err := decodeDatastoreResource(&dsr, &result); err != nil {
-			s.logger.Error("unable to decode datastore resource", zap.String("name", dsr.Name), zap.String("kind", string(kind)))
-			continue
+	// search is implemented using the search index
+	if opts.query != nil && index != nil {
+		ids, err := index.Search(ctx, opts.query)
+		if err != nil {
+			return nil, err
 		}
-		results = append(results, result)
+		results, err := getDatastoreResourcesByID[R](ctx, s, kind, ids)
+		if err != nil {
+			return nil, err
+		}
+		for _, result := range results {
+			collector.add(result)
+		}
+		return collector.page(), nil
 	}
 
-	return results, nil
-}
+	// datastore keys are agent ids and resource names and results are returned in key order
+	scanField := "name"
+	if kind == model.KindAgent {
+		scanField = "id"
+	}
 
-func getDatastoreResourcesWithQuery[R any](ctx context.Context, s *googleCloudStore, index search.Index, kind model.Kind, opts *queryOptions) ([]R, error) {
-	// search is implemented using the search index
-	if opts.query != nil {
-		ids, err := index.Search(ctx, opts.query)
+	query := datastoreQuery(kind, &opts)
+	if key := collector.startKey(scanField); key != "" {
+		query = query.Filter("__key__ >", datastoreKey(kind, key))
+	}
+
+	it := s.client.Run(ctx, query)
+	for !collector.complete(scanField) {
+		var dsr datastoreResource
+		_, err := it.Next(&dsr)
+		if err == iterator.Done {
+			break
+		}
 		if err != nil {
 			return nil, err
 		}
-		return getDatastoreResourcesByID[R](ctx, s, kind, ids)
+		var result R
+		if err := decodeDatastoreResource(&dsr, &result); err != nil {
+			s.logger.Error("unable to decode datastore resource", zap.String("name", dsr.Name), zap.String("kind", string(kind)))
+			continue
+		}
+		collector.add(result)
 	}
 
-	return getDatastoreResources[R](s, kind, opts)
+	return collector.page(), nil
 }
 
 func getDatastoreResourcesByID[R any](ctx context.Context, s *googleCloudStore, kind model.Kind, ids []string) ([]R, error) {
@@ -0,0 +1,63 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type lease struct {
+	holder  string
+	expires time.Time
+}
+
+// localLeases are leases held in memory for stores that are only used by a single node
+type localLeases struct {
+	leases map[string]lease
+	mtx    sync.Mutex
+}
+
+// AcquireLease acquires or renews the named lease for the holder. It returns false if the lease is held by another
+// holder and has not expired.
+func (l *localLeases) AcquireLease(_ context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	now := time.Now()
+	if current, ok := l.leases[name]; ok && current.holder != holder && now.Before(current.expires) {
+		return false, nil
+	}
+	if l.leases == nil {
+		l.leases = map[string]lease{}
+	}
+	l.leases[name] = lease{
+		holder:  holder,
+		expires: now.Add(ttl),
+	}
+	return true, nil
+}
+
+// ReleaseLease releases the named lease if it is held by the holder
+func (l *localLeases) ReleaseLease(_ context.Context, name, holder string) error {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if current, ok := l.leases[name]; ok && current.holder == holder {
+		delete(l.leases, name)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestLocalLeases(t *testing.T) {
+	ctx := context.Background()
+	s := NewMapStore(ctx, Options{}, zap.NewNop())
+
+	acquired, err := s.AcquireLease(ctx, "lease", "a", time.Minute)
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	// held by a
+	acquired, err = s.AcquireLease(ctx, "lease", "b", time.Minute)
+	require.NoError(t, err)
+	require.False(t, acquired)
+
+	// renewed by a
+	acquired, err = s.AcquireLease(ctx, "lease", "a", time.Minute)
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	// other leases are independent
+	acquired, err = s.AcquireLease(ctx, "other", "b", time.Minute)
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	// only the holder can release
+	require.NoError(t, s.ReleaseLease(ctx, "lease", "b"))
+	acquired, err = s.AcquireLease(ctx, "lease", "b", time.Minute)
+	require.NoError(t, err)
+	require.False(t, acquired)
+
+	require.NoError(t, s.ReleaseLease(ctx, "lease", "a"))
+	acquired, err = s.AcquireLease(ctx, "lease", "b", time.Millisecond)
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	// expired leases can be acquired by another holder
+	time.Sleep(5 * time.Millisecond)
+	acquired, err = s.AcquireLease(ctx, "lease", "a", time.Minute)
+	require.NoError(t, err)
+	require.True(t, acquired)
+}
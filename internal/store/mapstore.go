@@ -27,6 +27,7 @@ import (
 	"go.uber.org/zap"
 	"golang.org/x/exp/maps"
 
+	"github.com/observiq/bindplane-op/internal/cluster"
 	"github.com/observiq/bindplane-op/internal/eventbus"
 	"github.com/observiq/bindplane-op/internal/store/search"
 	"github.com/observiq/bindplane-op/model"
@@ -51,14 +52,24 @@ type mapStore struct {
 	sync.RWMutex
 
 	sessionStore sessions.Store
+
+	enrollmentTokens records[model.EnrollmentToken]
+	agentCredentials records[model.AgentCredential]
+	agentDenyRules   records[model.AgentDenyRule]
+	userSessions     records[model.UserSession]
+	gitOpsStatus     records[model.GitOpsSyncStatus]
+
+	// leases are local to this node because the store isn't shared with other nodes
+	localLeases
 }
 
 var _ Store = (*mapStore)(nil)
 
 // NewMapStore returns an in memory Store
 func NewMapStore(ctx context.Context, options Options, logger *zap.Logger) Store {
-	return &mapStore{
+	store := &mapStore{
 		agents:             make(map[string]*model.Agent),
+		agentVersions:      newResourceStore[*model.AgentVersion](),
 		configurations:     newResourceStore[*model.Configuration](),
 		sources:            newResourceStore[*model.Source](),
 		sourceTypes:        newResourceStore[*model.SourceType](),
@@ -66,12 +77,18 @@ func NewMapStore(ctx context.Context, options Options, logger *zap.Logger) Store
 		processorTypes:     newResourceStore[*model.ProcessorType](),
 		destinations:       newResourceStore[*model.Destination](),
 		destinationTypes:   newResourceStore[*model.DestinationType](),
-		updates:            newStoreUpdates(ctx, options.MaxEventsToMerge),
 		agentIndex:         search.NewInMemoryIndex("agent"),
 		configurationIndex: search.NewInMemoryIndex("configuration"),
 		logger:             logger,
 		sessionStore:       newBPCookieStore(options.SessionsSecret),
+		enrollmentTokens:   newRecords[model.EnrollmentToken](),
+		agentCredentials:   newRecords[model.AgentCredential](),
+		agentDenyRules:     newRecords[model.AgentDenyRule](),
+		userSessions:       newRecords[model.UserSession](),
+		gitOpsStatus:       newRecords[model.GitOpsSyncStatus](),
 	}
+	store.updates = newStoreUpdates(ctx, options, store.agentIndex, store.configurationIndex, logger)
+	return store
 }
 
 // ----------------------------------------------------------------------
@@ -113,7 +130,7 @@ func (r *resourceStore[T]) add(resource T) *model.ResourceStatus {
 	switch {
 	case !ok:
 		status = model.StatusCreated
-	case !resourcesEqual(existing, resource):
+	case !ResourcesEqual(existing, resource):
 		status = model.StatusConfigured
 	default:
 		status = model.StatusUnchanged
@@ -166,6 +183,21 @@ func (r *resourceStore[T]) list() []T {
 	return maps.Values(r.store)
 }
 
+// query returns the page of resources matching the query options. The index is used to match the query and may be nil.
+func (r *resourceStore[T]) query(opts queryOptions, index search.Index) ([]T, error) {
+	collector, err := newPageCollector[T](opts, index)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	for _, item := range r.store {
+		collector.add(item)
+	}
+	return collector.page(), nil
+}
+
 func (r *resourceStore[T]) clear() {
 	r.mtx.Lock()
 	defer r.mtx.Unlock()
@@ -185,6 +217,12 @@ func (mapstore *mapStore) Clear() {
 	mapstore.sourceTypes.clear()
 	mapstore.destinations.clear()
 	mapstore.destinationTypes.clear()
+
+	mapstore.enrollmentTokens.clear()
+	mapstore.agentCredentials.clear()
+	mapstore.agentDenyRules.clear()
+	mapstore.userSessions.clear()
+	mapstore.gitOpsStatus.clear()
 }
 
 func (mapstore *mapStore) UpsertAgents(ctx context.Context, agentIDs []string, updater AgentUpdater) ([]*model.Agent, error) {
@@ -219,26 +257,14 @@ func (mapstore *mapStore) Agents(ctx context.Context, options ...QueryOption) ([
 	mapstore.RLock()
 	defer mapstore.RUnlock()
 
-	opts := makeQueryOptions(options)
-	result := make([]*model.Agent, 0, len(mapstore.agents))
-
-	for _, value := range mapstore.agents {
-		if opts.selector.Matches(value.Labels) {
-			result = append(result, value)
-		}
+	collector, err := newPageCollector[*model.Agent](makeQueryOptions(options), mapstore.agentIndex)
+	if err != nil {
+		return nil, err
 	}
-
-	if opts.sort == "" {
-		opts.sort = "name"
+	for _, value := range mapstore.agents {
+		collector.add(value)
 	}
-	return applySortOffsetAndLimit(result, opts, func(field string, item *model.Agent) string {
-		switch field {
-		case "id":
-			return item.ID
-		default:
-			return item.Name
-		}
-	}), nil
+	return collector.page(), nil
 }
 
 func (mapstore *mapStore) AgentsCount(ctx context.Context, options ...QueryOption) (int, error) {
@@ -291,8 +317,8 @@ func (mapstore *mapStore) DeleteAgents(ctx context.Context, agentIDs []string) (
 func (mapstore *mapStore) AgentVersion(name string) (*model.AgentVersion, error) {
 	return mapstore.agentVersions.get(name), nil
 }
-func (mapstore *mapStore) AgentVersions() ([]*model.AgentVersion, error) {
-	return mapstore.agentVersions.list(), nil
+func (mapstore *mapStore) AgentVersions(options ...QueryOption) ([]*model.AgentVersion, error) {
+	return mapstore.agentVersions.query(makeQueryOptions(options), nil)
 }
 func (mapstore *mapStore) DeleteAgentVersion(name string) (*model.AgentVersion, error) {
 	item, exists, err := mapstore.agentVersions.removeAndNotify(name, mapstore)
@@ -306,15 +332,7 @@ func (mapstore *mapStore) DeleteAgentVersion(name string) (*model.AgentVersion,
 }
 
 func (mapstore *mapStore) Configurations(options ...QueryOption) ([]*model.Configuration, error) {
-	opts := makeQueryOptions(options)
-	config := mapstore.configurations.list()
-	if opts.sort == "" {
-		opts.sort = "name"
-	}
-	return applySortOffsetAndLimit(config, opts, func(field string, item *model.Configuration) string {
-		// we currently only support sorting by name
-		return item.Name()
-	}), nil
+	return mapstore.configurations.query(makeQueryOptions(options), mapstore.configurationIndex)
 }
 func (mapstore *mapStore) Configuration(name string) (*model.Configuration, error) {
 	return mapstore.configurations.get(name), nil
@@ -334,8 +352,8 @@ func (mapstore *mapStore) DeleteConfiguration(name string) (*model.Configuration
 func (mapstore *mapStore) Source(name string) (*model.Source, error) {
 	return mapstore.sources.get(name), nil
 }
-func (mapstore *mapStore) Sources() ([]*model.Source, error) {
-	return mapstore.sources.list(), nil
+func (mapstore *mapStore) Sources(options ...QueryOption) ([]*model.Source, error) {
+	return mapstore.sources.query(makeQueryOptions(options), nil)
 }
 func (mapstore *mapStore) DeleteSource(name string) (*model.Source, error) {
 	item, exists, err := mapstore.sources.removeAndNotify(name, mapstore)
@@ -352,8 +370,8 @@ func (mapstore *mapStore) DeleteSource(name string) (*model.Source, error) {
 func (mapstore *mapStore) SourceType(name string) (*model.SourceType, error) {
 	return mapstore.sourceTypes.get(name), nil
 }
-func (mapstore *mapStore) SourceTypes() ([]*model.SourceType, error) {
-	return mapstore.sourceTypes.list(), nil
+func (mapstore *mapStore) SourceTypes(options ...QueryOption) ([]*model.SourceType, error) {
+	return mapstore.sourceTypes.query(makeQueryOptions(options), nil)
 }
 func (mapstore *mapStore) DeleteSourceType(name string) (*model.SourceType, error) {
 	item, exists, err := mapstore.sourceTypes.removeAndNotify(name, mapstore)
@@ -370,8 +388,8 @@ func (mapstore *mapStore) DeleteSourceType(name string) (*model.SourceType, erro
 func (mapstore *mapStore) Processor(name string) (*model.Processor, error) {
 	return mapstore.processors.get(name), nil
 }
-func (mapstore *mapStore) Processors() ([]*model.Processor, error) {
-	return mapstore.processors.list(), nil
+func (mapstore *mapStore) Processors(options ...QueryOption) ([]*model.Processor, error) {
+	return mapstore.processors.query(makeQueryOptions(options), nil)
 }
 func (mapstore *mapStore) DeleteProcessor(name string) (*model.Processor, error) {
 	item, exists, err := mapstore.processors.removeAndNotify(name, mapstore)
@@ -388,8 +406,8 @@ func (mapstore *mapStore) DeleteProcessor(name string) (*model.Processor, error)
 func (mapstore *mapStore) ProcessorType(name string) (*model.ProcessorType, error) {
 	return mapstore.processorTypes.get(name), nil
 }
-func (mapstore *mapStore) ProcessorTypes() ([]*model.ProcessorType, error) {
-	return mapstore.processorTypes.list(), nil
+func (mapstore *mapStore) ProcessorTypes(options ...QueryOption) ([]*model.ProcessorType, error) {
+	return mapstore.processorTypes.query(makeQueryOptions(options), nil)
 }
 func (mapstore *mapStore) DeleteProcessorType(name string) (*model.ProcessorType, error) {
 	item, exists, err := mapstore.processorTypes.removeAndNotify(name, mapstore)
@@ -406,8 +424,8 @@ func (mapstore *mapStore) DeleteProcessorType(name string) (*model.ProcessorType
 func (mapstore *mapStore) Destination(name string) (*model.Destination, error) {
 	return mapstore.destinations.get(name), nil
 }
-func (mapstore *mapStore) Destinations() ([]*model.Destination, error) {
-	return mapstore.destinations.list(), nil
+func (mapstore *mapStore) Destinations(options ...QueryOption) ([]*model.Destination, error) {
+	return mapstore.destinations.query(makeQueryOptions(options), nil)
 }
 func (mapstore *mapStore) DeleteDestination(name string) (*model.Destination, error) {
 	item, exists, err := mapstore.destinations.removeAndNotify(name, mapstore)
@@ -424,8 +442,8 @@ func (mapstore *mapStore) DeleteDestination(name string) (*model.Destination, er
 func (mapstore *mapStore) DestinationType(name string) (*model.DestinationType, error) {
 	return mapstore.destinationTypes.get(name), nil
 }
-func (mapstore *mapStore) DestinationTypes() ([]*model.DestinationType, error) {
-	return mapstore.destinationTypes.list(), nil
+func (mapstore *mapStore) DestinationTypes(options ...QueryOption) ([]*model.DestinationType, error) {
+	return mapstore.destinationTypes.query(makeQueryOptions(options), nil)
 }
 func (mapstore *mapStore) DeleteDestinationType(name string) (*model.DestinationType, error) {
 	item, exists, err := mapstore.destinationTypes.removeAndNotify(name, mapstore)
@@ -442,6 +460,59 @@ func (mapstore *mapStore) DeleteDestinationType(name string) (*model.Destination
 func (mapstore *mapStore) ApplyResources(resources []model.Resource) ([]model.ResourceStatus, error) {
 	mapstore.Lock()
 	defer mapstore.Unlock()
+	return mapstore.applyResources(resources)
+}
+
+func (mapstore *mapStore) ApplyResourcesWithPreconditions(resources []model.Resource, preconditions []model.ResourcePrecondition) ([]model.ResourceStatus, error) {
+	mapstore.Lock()
+	defer mapstore.Unlock()
+	for _, precondition := range preconditions {
+		current, err := mapstore.resource(precondition.Kind, precondition.Name)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkPrecondition(precondition, current); err != nil {
+			return nil, err
+		}
+	}
+	return mapstore.applyResources(resources)
+}
+
+// resource returns the resource of the kind with the specified name or nil if it doesn't exist
+func (mapstore *mapStore) resource(kind model.Kind, name string) (model.Resource, error) {
+	switch kind {
+	case model.KindAgentVersion:
+		return getResource(&mapstore.agentVersions, name), nil
+	case model.KindConfiguration:
+		return getResource(&mapstore.configurations, name), nil
+	case model.KindSource:
+		return getResource(&mapstore.sources, name), nil
+	case model.KindSourceType:
+		return getResource(&mapstore.sourceTypes, name), nil
+	case model.KindProcessor:
+		return getResource(&mapstore.processors, name), nil
+	case model.KindProcessorType:
+		return getResource(&mapstore.processorTypes, name), nil
+	case model.KindDestination:
+		return getResource(&mapstore.destinations, name), nil
+	case model.KindDestinationType:
+		return getResource(&mapstore.destinationTypes, name), nil
+	}
+	return nil, fmt.Errorf("preconditions are not supported for %s", kind)
+}
+
+// getResource returns the resource with the specified name as a model.Resource that is nil if it doesn't exist
+func getResource[T model.Resource](store *resourceStore[T], name string) model.Resource {
+	store.mtx.RLock()
+	defer store.mtx.RUnlock()
+	if resource, ok := store.store[name]; ok {
+		return resource
+	}
+	return nil
+}
+
+// applyResources applies the resources while the mapstore is locked
+func (mapstore *mapStore) applyResources(resources []model.Resource) ([]model.ResourceStatus, error) {
 	var result error
 
 	updates := NewUpdates()
@@ -598,6 +669,115 @@ func (mapstore *mapStore) Updates() eventbus.Source[*Updates] {
 	return mapstore.updates.Updates()
 }
 
+// ClusterBus is used to send messages between the BindPlane nodes sharing the store
+func (mapstore *mapStore) ClusterBus() cluster.Bus {
+	return mapstore.updates.bus
+}
+
+// EnrollmentTokens returns all of the EnrollmentTokens
+func (mapstore *mapStore) EnrollmentTokens(_ context.Context) ([]*model.EnrollmentToken, error) {
+	return mapstore.enrollmentTokens.list(), nil
+}
+
+// EnrollmentToken returns the EnrollmentToken with the specified ID or nil if it does not exist
+func (mapstore *mapStore) EnrollmentToken(_ context.Context, id string) (*model.EnrollmentToken, error) {
+	return mapstore.enrollmentTokens.get(id), nil
+}
+
+// CreateEnrollmentToken adds a new EnrollmentToken
+func (mapstore *mapStore) CreateEnrollmentToken(_ context.Context, token *model.EnrollmentToken) error {
+	_, err := mapstore.enrollmentTokens.update(token.ID, true, func(current *model.EnrollmentToken) error {
+		*current = *token
+		return nil
+	})
+	return err
+}
+
+// UpdateEnrollmentToken atomically updates an existing EnrollmentToken
+func (mapstore *mapStore) UpdateEnrollmentToken(_ context.Context, id string, updater EnrollmentTokenUpdater) (*model.EnrollmentToken, error) {
+	return mapstore.enrollmentTokens.update(id, false, updater)
+}
+
+// DeleteEnrollmentToken removes the EnrollmentToken and returns it or nil if it does not exist
+func (mapstore *mapStore) DeleteEnrollmentToken(_ context.Context, id string) (*model.EnrollmentToken, error) {
+	return mapstore.enrollmentTokens.delete(id), nil
+}
+
+// AgentCredential returns the credential issued to the agent or nil if there is no credential
+func (mapstore *mapStore) AgentCredential(_ context.Context, agentID string) (*model.AgentCredential, error) {
+	return mapstore.agentCredentials.get(agentID), nil
+}
+
+// UpsertAgentCredential atomically adds or updates the credential of the agent
+func (mapstore *mapStore) UpsertAgentCredential(_ context.Context, agentID string, updater AgentCredentialUpdater) (*model.AgentCredential, error) {
+	return mapstore.agentCredentials.update(agentID, true, func(current *model.AgentCredential) error {
+		current.AgentID = agentID
+		return updater(current)
+	})
+}
+
+// AgentDenyRules returns all of the AgentDenyRules
+func (mapstore *mapStore) AgentDenyRules(_ context.Context) ([]*model.AgentDenyRule, error) {
+	return mapstore.agentDenyRules.list(), nil
+}
+
+// AddAgentDenyRule adds the AgentDenyRule, replacing any rule with the same ID
+func (mapstore *mapStore) AddAgentDenyRule(_ context.Context, rule *model.AgentDenyRule) error {
+	_, err := mapstore.agentDenyRules.update(rule.ID(), true, func(current *model.AgentDenyRule) error {
+		*current = *rule
+		return nil
+	})
+	return err
+}
+
+// DeleteAgentDenyRule removes the AgentDenyRule and returns it or nil if it does not exist
+func (mapstore *mapStore) DeleteAgentDenyRule(_ context.Context, id string) (*model.AgentDenyRule, error) {
+	return mapstore.agentDenyRules.delete(id), nil
+}
+
+// UserSessionRecords returns the server side records of all of the user sessions
+func (mapstore *mapStore) UserSessionRecords(_ context.Context) ([]*model.UserSession, error) {
+	return mapstore.userSessions.list(), nil
+}
+
+// UserSessionRecord returns the UserSession with the specified ID or nil if it does not exist
+func (mapstore *mapStore) UserSessionRecord(_ context.Context, id string) (*model.UserSession, error) {
+	return mapstore.userSessions.get(id), nil
+}
+
+// CreateUserSessionRecord adds a new UserSession
+func (mapstore *mapStore) CreateUserSessionRecord(_ context.Context, session *model.UserSession) error {
+	_, err := mapstore.userSessions.update(session.ID, true, func(current *model.UserSession) error {
+		*current = *session
+		return nil
+	})
+	return err
+}
+
+// UpdateUserSessionRecord atomically updates an existing UserSession
+func (mapstore *mapStore) UpdateUserSessionRecord(_ context.Context, id string, updater UserSessionUpdater) (*model.UserSession, error) {
+	return mapstore.userSessions.update(id, false, updater)
+}
+
+// DeleteUserSessionRecord removes the UserSession and returns it or nil if it does not exist
+func (mapstore *mapStore) DeleteUserSessionRecord(_ context.Context, id string) (*model.UserSession, error) {
+	return mapstore.userSessions.delete(id), nil
+}
+
+// GitOpsSyncStatus returns the status of the most recent GitOps sync or nil if resources have not been synced
+func (mapstore *mapStore) GitOpsSyncStatus(_ context.Context) (*model.GitOpsSyncStatus, error) {
+	return mapstore.gitOpsStatus.get(gitOpsSyncStatusKey), nil
+}
+
+// SetGitOpsSyncStatus saves the status of the most recent GitOps sync
+func (mapstore *mapStore) SetGitOpsSyncStatus(_ context.Context, status *model.GitOpsSyncStatus) error {
+	_, err := mapstore.gitOpsStatus.update(gitOpsSyncStatusKey, true, func(current *model.GitOpsSyncStatus) error {
+		*current = *status
+		return nil
+	})
+	return err
+}
+
 // CleanupDisconnectedAgents removes agents that have disconnected before the specified time
 func (mapstore *mapStore) CleanupDisconnectedAgents(since time.Time) error {
 	mapstore.Lock()
@@ -679,7 +859,8 @@ func (mapstore *mapStore) notify(updates *Updates) {
 	}
 }
 
-func resourcesEqual(r1 model.Resource, r2 model.Resource) bool {
+// ResourcesEqual returns true if the resources have the same kind, metadata, and spec. IDs are ignored.
+func ResourcesEqual(r1 model.Resource, r2 model.Resource) bool {
 	r1Any := &model.AnyResource{}
 	r2Any := &model.AnyResource{}
 	err := mapstructure.Decode(r1, r1Any)
@@ -56,6 +56,14 @@ func TestMapstoreApplyResourcesReturn(t *testing.T) {
 	runApplyResourceReturnTests(t, store)
 }
 
+func TestMapstoreApplyResourcesWithPreconditions(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	store := NewMapStore(ctx, testOptions, zap.NewNop())
+
+	runApplyResourcesWithPreconditionsTests(t, store)
+}
+
 func TestMapstoreDeleteResourcesReturn(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -109,7 +117,7 @@ func TestMapstoreResourcesEqual(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.description, func(t *testing.T) {
-			got := resourcesEqual(test.r1, test.r2)
+			got := ResourcesEqual(test.r1, test.r2)
 			assert.Equal(t, test.expect, got)
 		})
 	}
@@ -157,6 +165,7 @@ func TestMapstorePaging(t *testing.T) {
 	defer cancel()
 	store := NewMapStore(ctx, testOptions, zap.NewNop())
 	runPagingTests(t, store)
+	runResourcePagingTests(t, store)
 }
 
 func TestMapstoreDeleteAgents(t *testing.T) {
@@ -172,3 +181,10 @@ func TestMapstoreUpsertAgents(t *testing.T) {
 	store := NewMapStore(ctx, testOptions, zap.NewNop())
 	runTestUpsertAgents(t, store)
 }
+
+func TestMapstoreEnrollment(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	store := NewMapStore(ctx, testOptions, zap.NewNop())
+	runEnrollmentTests(t, store)
+}
@@ -0,0 +1,306 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/observiq/bindplane-op/internal/store/search"
+	"github.com/observiq/bindplane-op/internal/util/semver"
+	"github.com/observiq/bindplane-op/model"
+)
+
+// listItem is an item that can be returned by a Store query
+type listItem interface {
+	model.HasUniqueKey
+	model.Labeled
+	search.Indexed
+}
+
+// continueToken identifies the last item of a page of results. It is encoded as base64 JSON so that it can be passed
+// to clients as an opaque string.
+type continueToken struct {
+	Sort  string `json:"s"`
+	Value string `json:"v"`
+	Key   string `json:"k"`
+}
+
+// ContinueToken returns the token that can be used with WithContinue to request the items after the specified item. The
+// sort must be the same sort used for the query that returned the item.
+func ContinueToken(item model.HasUniqueKey, sort string) string {
+	if sort == "" {
+		sort = defaultSort(item)
+	}
+	field, _ := parseSort(sort)
+	data, err := json.Marshal(continueToken{
+		Sort:  sort,
+		Value: sortValue(item, field),
+		Key:   item.UniqueKey(),
+	})
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func parseContinueToken(token, sort string) (*continueToken, error) {
+	if token == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidContinueToken
+	}
+	result := &continueToken{}
+	if err := json.Unmarshal(data, result); err != nil {
+		return nil, ErrInvalidContinueToken
+	}
+	if result.Sort != sort {
+		return nil, fmt.Errorf("%w: token was created with sort %s", ErrInvalidContinueToken, result.Sort)
+	}
+	return result, nil
+}
+
+// defaultSort returns the sort used when none is specified. Agent versions are listed latest first and everything else
+// is listed by name. The item may be a nil pointer of the item type.
+func defaultSort(item any) string {
+	if _, ok := item.(*model.AgentVersion); ok {
+		return "-version"
+	}
+	return "name"
+}
+
+// sortFields returns the fields that can be used to sort items of the same type as item. The item may be a nil pointer
+// of the item type.
+func sortFields(item any) []string {
+	switch item.(type) {
+	case *model.Agent:
+		return []string{"id", "name", "status", "version"}
+	case *model.AgentVersion:
+		return []string{"name", "displayName", "version"}
+	}
+	fields := []string{"name", "displayName"}
+	if _, ok := item.(interface{ ResourceTypeName() string }); ok {
+		fields = append(fields, "type")
+	}
+	return fields
+}
+
+// validateSort returns an error if the field of the sort is not supported for items of the same type as item
+func validateSort(item any, sort string) error {
+	field, _ := parseSort(sort)
+	fields := sortFields(item)
+	for _, f := range fields {
+		if f == field {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: unsupported field %s, must be one of %s", ErrInvalidSort, field, strings.Join(fields, ", "))
+}
+
+func parseSort(sort string) (field string, descending bool) {
+	if strings.HasPrefix(sort, "-") {
+		return sort[1:], true
+	}
+	return sort, false
+}
+
+// sortValue returns the value of the field used to sort the item. Values are formatted so that they can be compared as
+// strings. The field must be one of the sortFields of the item.
+func sortValue(item any, field string) string {
+	switch item := item.(type) {
+	case *model.Agent:
+		switch field {
+		case "id":
+			return item.ID
+		case "status":
+			return fmt.Sprintf("%03d", item.Status)
+		case "version":
+			return versionSortValue(item.Version)
+		}
+		return item.Name
+
+	case *model.AgentVersion:
+		if field == "version" {
+			return versionSortValue(item.Version())
+		}
+	}
+
+	if resource, ok := item.(model.Resource); ok {
+		switch field {
+		case "displayName":
+			if resource, ok := resource.(model.Printable); ok {
+				return resource.PrintableFieldValue("Display")
+			}
+		case "type":
+			if resource, ok := resource.(interface{ ResourceTypeName() string }); ok {
+				return resource.ResourceTypeName()
+			}
+		}
+		return resource.Name()
+	}
+	return ""
+}
+
+func versionSortValue(version string) string {
+	v := semver.Parse(version)
+	return fmt.Sprintf("%010d.%010d.%010d", v.Major, v.Minor, v.Patch)
+}
+
+// ----------------------------------------------------------------------
+
+// pageCollector collects the items of a single page of query results. Items are added in any order and only the items
+// needed for the page are kept so that a store can scan all of its records without loading them into memory.
+type pageCollector[T listItem] struct {
+	opts       queryOptions
+	index      search.Index
+	sort       string
+	field      string
+	descending bool
+	after      *continueToken
+	items      []T
+}
+
+// newPageCollector returns a pageCollector for the query options. The index is used to match the query and may be nil
+// if the items are not indexed.
+func newPageCollector[T listItem](opts queryOptions, index search.Index) (*pageCollector[T], error) {
+	var item T
+	sort := opts.sort
+	if sort == "" {
+		sort = defaultSort(item)
+	}
+	if err := validateSort(item, sort); err != nil {
+		return nil, err
+	}
+	after, err := parseContinueToken(opts.continueToken, sort)
+	if err != nil {
+		return nil, err
+	}
+	field, descending := parseSort(sort)
+	return &pageCollector[T]{
+		opts:       opts,
+		index:      index,
+		sort:       sort,
+		field:      field,
+		descending: descending,
+		after:      after,
+	}, nil
+}
+
+// max returns the number of items needed for the page or 0 if all items are needed
+func (c *pageCollector[T]) max() int {
+	if c.opts.limit == 0 {
+		return 0
+	}
+	return c.opts.offset + c.opts.limit
+}
+
+// matches returns true if the item matches the selector and query
+func (c *pageCollector[T]) matches(item T) bool {
+	if !c.opts.selector.Matches(item.GetLabels()) {
+		return false
+	}
+	if c.opts.query == nil {
+		return true
+	}
+	if c.index != nil {
+		return c.index.Matches(c.opts.query, item.IndexID())
+	}
+	return search.Matches(c.opts.query, item)
+}
+
+// add adds the item to the page if it matches the query and comes after the continue token
+func (c *pageCollector[T]) add(item T) {
+	if !c.matches(item) {
+		return
+	}
+	if c.after != nil && !c.less(c.after.Value, c.after.Key, sortValue(item, c.field), item.UniqueKey()) {
+		return
+	}
+	c.items = append(c.items, item)
+
+	// periodically discard the items that cannot be part of the page
+	if max := c.max(); max > 0 && len(c.items) >= 2*max {
+		c.sortItems()
+		c.items = c.items[:max]
+	}
+}
+
+// sortedBy returns true if the results are sorted ascending by the specified field. Stores that scan items ordered by
+// that field can stop scanning when the collector is complete.
+func (c *pageCollector[T]) sortedBy(field string) bool {
+	return c.field == field && !c.descending
+}
+
+// complete returns true if items are added in the order of the results and enough items have been collected for the
+// page. scanField is the field used to order the items scanned by the store.
+func (c *pageCollector[T]) complete(scanField string) bool {
+	max := c.max()
+	return max > 0 && c.sortedBy(scanField) && len(c.items) >= max
+}
+
+// startKey returns the unique key of the last item of the previous page if the store scans items in the order of the
+// results. The store can start scanning from this key.
+func (c *pageCollector[T]) startKey(scanField string) string {
+	if c.after == nil || !c.sortedBy(scanField) {
+		return ""
+	}
+	return c.after.Key
+}
+
+// page returns the sorted page of results. It returns an empty slice if there are no results.
+func (c *pageCollector[T]) page() []T {
+	c.sortItems()
+	items := c.items
+	if items == nil {
+		items = []T{}
+	}
+	if c.opts.offset != 0 {
+		offset := c.opts.offset
+		if offset > len(items) {
+			offset = len(items)
+		}
+		items = items[offset:]
+	}
+	if c.opts.limit != 0 && c.opts.limit < len(items) {
+		items = items[:c.opts.limit]
+	}
+	return items
+}
+
+func (c *pageCollector[T]) sortItems() {
+	sort.SliceStable(c.items, func(i, j int) bool {
+		a, b := c.items[i], c.items[j]
+		return c.less(sortValue(a, c.field), a.UniqueKey(), sortValue(b, c.field), b.UniqueKey())
+	})
+}
+
+// less compares items by sort value and then by unique key, reversing the order for a descending sort
+func (c *pageCollector[T]) less(value1, key1, value2, key2 string) bool {
+	if value1 == value2 {
+		if c.descending {
+			return key1 > key2
+		}
+		return key1 < key2
+	}
+	if c.descending {
+		return value1 > value2
+	}
+	return value1 < value2
+}
@@ -0,0 +1,289 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"cloud.google.com/go/datastore"
+	"go.etcd.io/bbolt"
+)
+
+// Records are values stored by key that aren't resources, e.g. EnrollmentTokens and AgentCredentials. Each store
+// implementation has helpers to get, list, update, and delete them. Updates are atomic and are not saved if the updater
+// returns an error.
+
+// records stores records in memory for the mapStore
+type records[T any] struct {
+	store map[string]T
+	mtx   sync.RWMutex
+}
+
+func newRecords[T any]() records[T] {
+	return records[T]{
+		store: map[string]T{},
+	}
+}
+
+// get returns a copy of the record or nil if it doesn't exist
+func (r *records[T]) get(key string) *T {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	if record, ok := r.store[key]; ok {
+		return &record
+	}
+	return nil
+}
+
+// list returns copies of the records sorted by key
+func (r *records[T]) list() []*T {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	keys := make([]string, 0, len(r.store))
+	for key := range r.store {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	result := make([]*T, 0, len(keys))
+	for _, key := range keys {
+		record := r.store[key]
+		result = append(result, &record)
+	}
+	return result
+}
+
+// update calls the updater with a copy of the current record. If the record doesn't exist, it returns
+// ErrResourceMissing unless create is true in which case the updater is called with an empty record.
+func (r *records[T]) update(key string, create bool, updater func(*T) error) (*T, error) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	record, ok := r.store[key]
+	if !ok && !create {
+		return nil, ErrResourceMissing
+	}
+	if err := updater(&record); err != nil {
+		return nil, err
+	}
+	r.store[key] = record
+	return &record, nil
+}
+
+func (r *records[T]) clear() {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.store = map[string]T{}
+}
+
+// delete removes the record and returns it or nil if it doesn't exist
+func (r *records[T]) delete(key string) *T {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	record, ok := r.store[key]
+	if !ok {
+		return nil
+	}
+	delete(r.store, key)
+	return &record
+}
+
+// ----------------------------------------------------------------------
+// bbolt
+
+func boltRecord[T any](db *bbolt.DB, bucket, key string) (*T, error) {
+	var record *T
+	err := db.View(func(tx *bbolt.Tx) error {
+		data := tx.Bucket([]byte(bucket)).Get([]byte(key))
+		if data == nil {
+			return nil
+		}
+		record = new(T)
+		return json.Unmarshal(data, record)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get %s: %w", key, err)
+	}
+	return record, nil
+}
+
+func boltRecords[T any](db *bbolt.DB, bucket string) ([]*T, error) {
+	result := []*T{}
+	err := db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket([]byte(bucket)).ForEach(func(_, data []byte) error {
+			record := new(T)
+			if err := json.Unmarshal(data, record); err != nil {
+				return err
+			}
+			result = append(result, record)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list %s: %w", bucket, err)
+	}
+	return result, nil
+}
+
+func boltUpdateRecord[T any](db *bbolt.DB, bucket, key string, create bool, updater func(*T) error) (*T, error) {
+	record := new(T)
+	err := db.Update(func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(bucket))
+		data := b.Get([]byte(key))
+		switch {
+		case data != nil:
+			if err := json.Unmarshal(data, record); err != nil {
+				return err
+			}
+		case !create:
+			return ErrResourceMissing
+		}
+		if err := updater(record); err != nil {
+			return err
+		}
+		data, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte(key), data)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+func boltDeleteRecord[T any](db *bbolt.DB, bucket, key string) (*T, error) {
+	var record *T
+	err := db.Update(func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(bucket))
+		data := b.Get([]byte(key))
+		if data == nil {
+			return nil
+		}
+		record = new(T)
+		if err := json.Unmarshal(data, record); err != nil {
+			return err
+		}
+		return b.Delete([]byte(key))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("delete %s: %w", key, err)
+	}
+	return record, nil
+}
+
+// ----------------------------------------------------------------------
+// datastore
+
+// datastoreRecord stores a record as json in a Datastore entity
+type datastoreRecord struct {
+	Data []byte `datastore:"data,noindex"`
+}
+
+func datastoreGetRecord[T any](ctx context.Context, client *datastore.Client, kind, key string) (*T, error) {
+	var dsr datastoreRecord
+	err := client.Get(ctx, datastore.NameKey(kind, key, nil), &dsr)
+	switch {
+	case errors.Is(err, datastore.ErrNoSuchEntity):
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("get %s: %w", key, err)
+	}
+	record := new(T)
+	if err := json.Unmarshal(dsr.Data, record); err != nil {
+		return nil, fmt.Errorf("get %s: %w", key, err)
+	}
+	return record, nil
+}
+
+func datastoreRecords[T any](ctx context.Context, client *datastore.Client, kind string) ([]*T, error) {
+	var dsrs []datastoreRecord
+	if _, err := client.GetAll(ctx, datastore.NewQuery(kind), &dsrs); err != nil {
+		return nil, fmt.Errorf("list %s: %w", kind, err)
+	}
+	result := make([]*T, 0, len(dsrs))
+	for _, dsr := range dsrs {
+		record := new(T)
+		if err := json.Unmarshal(dsr.Data, record); err != nil {
+			return nil, fmt.Errorf("list %s: %w", kind, err)
+		}
+		result = append(result, record)
+	}
+	return result, nil
+}
+
+func datastoreUpdateRecord[T any](ctx context.Context, client *datastore.Client, kind, key string, create bool, updater func(*T) error) (*T, error) {
+	dsKey := datastore.NameKey(kind, key, nil)
+	var record *T
+	_, err := client.RunInTransaction(ctx, func(tx *datastore.Transaction) error {
+		record = new(T)
+		var dsr datastoreRecord
+		err := tx.Get(dsKey, &dsr)
+		switch {
+		case errors.Is(err, datastore.ErrNoSuchEntity):
+			if !create {
+				return ErrResourceMissing
+			}
+		case err != nil:
+			return err
+		default:
+			if err := json.Unmarshal(dsr.Data, record); err != nil {
+				return err
+			}
+		}
+		if err := updater(record); err != nil {
+			return err
+		}
+		data, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Put(dsKey, &datastoreRecord{Data: data})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+func datastoreDeleteRecord[T any](ctx context.Context, client *datastore.Client, kind, key string) (*T, error) {
+	dsKey := datastore.NameKey(kind, key, nil)
+	var record *T
+	_, err := client.RunInTransaction(ctx, func(tx *datastore.Transaction) error {
+		record = nil
+		var dsr datastoreRecord
+		err := tx.Get(dsKey, &dsr)
+		switch {
+		case errors.Is(err, datastore.ErrNoSuchEntity):
+			return nil
+		case err != nil:
+			return err
+		}
+		record = new(T)
+		if err := json.Unmarshal(dsr.Data, record); err != nil {
+			return err
+		}
+		return tx.Delete(dsKey)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("delete %s: %w", key, err)
+	}
+	return record, nil
+}
@@ -0,0 +1,595 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v2"
+
+	"github.com/observiq/bindplane-op/internal/cluster"
+	"github.com/observiq/bindplane-op/model"
+)
+
+// ResourceTypeOrigin indicates where a ResourceType was loaded from and is stored in the bindplane/origin label
+type ResourceTypeOrigin string
+
+const (
+	// ResourceTypeOriginBuiltin is used for ResourceTypes bundled with BindPlane
+	ResourceTypeOriginBuiltin ResourceTypeOrigin = "builtin"
+	// ResourceTypeOriginDirectory is used for ResourceTypes loaded from a configured directory
+	ResourceTypeOriginDirectory ResourceTypeOrigin = "directory"
+	// ResourceTypeOriginCatalog is used for ResourceTypes loaded from a catalog
+	ResourceTypeOriginCatalog ResourceTypeOrigin = "catalog"
+)
+
+// ResourceTypeConflicts determines which ResourceType is used when an external ResourceType has the same kind and name
+// as a builtin ResourceType.
+type ResourceTypeConflicts string
+
+const (
+	// ResourceTypeConflictsExternal replaces the builtin ResourceType with the external ResourceType. This is the
+	// default.
+	ResourceTypeConflictsExternal ResourceTypeConflicts = "external"
+	// ResourceTypeConflictsBuiltin keeps the builtin ResourceType and ignores the external ResourceType
+	ResourceTypeConflictsBuiltin ResourceTypeConflicts = "builtin"
+)
+
+// ResourceTypeCatalog is the index served at a catalog URL. Each entry is the URL of a yaml file containing
+// ResourceTypes and may be relative to the URL of the index.
+type ResourceTypeCatalog struct {
+	ResourceTypes []string `yaml:"resourceTypes" json:"resourceTypes"`
+}
+
+// ResourceTypeLoaderSettings configures the ResourceTypeLoader
+type ResourceTypeLoaderSettings struct {
+	// Dirs are directories that will be searched for yaml files containing ResourceTypes
+	Dirs []string
+
+	// CatalogURL is the URL of a ResourceTypeCatalog index
+	CatalogURL string
+
+	// Interval is the interval at which Dirs and CatalogURL are checked for changes
+	Interval time.Duration
+
+	// Conflicts determines which ResourceType is used when an external ResourceType has the same kind and name as a
+	// builtin ResourceType
+	Conflicts ResourceTypeConflicts
+
+	// Builtin are the ResourceTypes bundled with BindPlane. They are restored when an external ResourceType that
+	// replaced them is removed.
+	Builtin []model.Resource
+
+	// Client is used to request the catalog. http.DefaultClient is used if not specified.
+	Client *http.Client
+
+	// Leadership determines if this node syncs at the configured interval. In a cluster, only the leader syncs
+	// ResourceTypes. If nil, this node always syncs.
+	Leadership cluster.Leadership
+
+	Logger *zap.Logger
+}
+
+// ResourceTypeLoader loads SourceTypes, ProcessorTypes, and DestinationTypes from directories and catalogs outside of
+// the BindPlane binary, applying them to the store as they are added, changed, or removed.
+type ResourceTypeLoader struct {
+	store    Store
+	settings ResourceTypeLoaderSettings
+	builtin  map[string]model.Resource
+	logger   *zap.Logger
+
+	mtx sync.Mutex
+	// previous contains the last ResourceTypes successfully read from each location so that a location that can't be
+	// read doesn't cause its ResourceTypes to be removed
+	previous map[string][]model.Resource
+}
+
+// loadedResourceTypes are the ResourceTypes read from a single file or url
+type loadedResourceTypes struct {
+	location  string
+	resources []model.Resource
+	err       error
+}
+
+// NewResourceTypeLoader creates a new ResourceTypeLoader that will apply ResourceTypes to the specified store
+func NewResourceTypeLoader(store Store, settings ResourceTypeLoaderSettings) *ResourceTypeLoader {
+	if settings.Client == nil {
+		settings.Client = http.DefaultClient
+	}
+	if settings.Logger == nil {
+		settings.Logger = zap.NewNop()
+	}
+	if settings.Conflicts == "" {
+		settings.Conflicts = ResourceTypeConflictsExternal
+	}
+	builtin := map[string]model.Resource{}
+	for _, r := range settings.Builtin {
+		builtin[resourceTypeKey(r)] = r
+	}
+	return &ResourceTypeLoader{
+		store:    store,
+		settings: settings,
+		builtin:  builtin,
+		logger:   settings.Logger,
+		previous: map[string][]model.Resource{},
+	}
+}
+
+// Start syncs ResourceTypes at the configured interval until the context is done. If Leadership is configured, it only
+// syncs while this node is the leader.
+func (l *ResourceTypeLoader) Start(ctx context.Context) {
+	if l.settings.Interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(l.settings.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if l.settings.Leadership != nil && !l.settings.Leadership.IsLeader() {
+				continue
+			}
+			if err := l.Sync(ctx); err != nil {
+				l.logger.Error("failed to sync ResourceTypes", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Sync reads ResourceTypes from the configured directories and catalog and applies any changes to the store.
+// ResourceTypes that are no longer present are removed from the store or, if they replaced a builtin ResourceType, the
+// builtin ResourceType is restored. External ResourceTypes are identified by the bindplane/origin label of the
+// ResourceTypes in the store so that they are removed after a restart or by a new leader.
+func (l *ResourceTypeLoader) Sync(ctx context.Context) error {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	var loaded []loadedResourceTypes
+	for _, dir := range l.settings.Dirs {
+		loaded = append(loaded, l.readDir(dir)...)
+	}
+	if l.settings.CatalogURL != "" {
+		loaded = append(loaded, l.readCatalog(ctx, l.settings.CatalogURL)...)
+	}
+
+	external := l.resolve(loaded)
+
+	applied, err := externalResourceTypes(l.store)
+	if err != nil {
+		return fmt.Errorf("failed to list ResourceTypes: %w", err)
+	}
+
+	var errs error
+	if err := l.apply(external); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	if err := l.remove(external, applied); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	return errs
+}
+
+// resolve combines the ResourceTypes from each location, resolving conflicts between locations and with builtin
+// ResourceTypes. Locations that could not be read use the ResourceTypes last read from that location.
+func (l *ResourceTypeLoader) resolve(loaded []loadedResourceTypes) map[string]model.Resource {
+	external := map[string]model.Resource{}
+	locations := map[string]string{}
+	previous := map[string][]model.Resource{}
+
+	for _, result := range loaded {
+		resources := result.resources
+		if result.err != nil {
+			l.logger.Error("failed to load ResourceTypes", zap.String("location", result.location), zap.Error(result.err))
+			resources = l.previous[result.location]
+		}
+		previous[result.location] = resources
+
+		for _, r := range resources {
+			key := resourceTypeKey(r)
+			if location, ok := locations[key]; ok {
+				l.logger.Warn("ignoring duplicate ResourceType",
+					zap.String("resourceType", key),
+					zap.String("location", result.location),
+					zap.String("loadedFrom", location))
+				continue
+			}
+			if _, ok := l.builtin[key]; ok && l.settings.Conflicts == ResourceTypeConflictsBuiltin {
+				l.logger.Warn("ignoring ResourceType that conflicts with a builtin ResourceType",
+					zap.String("resourceType", key),
+					zap.String("location", result.location))
+				continue
+			}
+			locations[key] = result.location
+			external[key] = r
+		}
+	}
+
+	l.previous = previous
+	return external
+}
+
+// apply applies the external ResourceTypes to the store
+func (l *ResourceTypeLoader) apply(external map[string]model.Resource) error {
+	if len(external) == 0 {
+		return nil
+	}
+	resources := make([]model.Resource, 0, len(external))
+	for _, r := range external {
+		resources = append(resources, r)
+	}
+
+	statuses, err := l.store.ApplyResources(resources)
+	if err != nil {
+		return err
+	}
+	for _, status := range statuses {
+		key := resourceTypeKey(status.Resource)
+		switch status.Status {
+		case model.StatusInvalid:
+			l.logger.Error("invalid ResourceType", zap.String("resourceType", key), zap.String("reason", status.Reason))
+		case model.StatusUnchanged:
+			// nothing to log
+		default:
+			l.logger.Info("loaded ResourceType", zap.String("resourceType", key), zap.String("status", string(status.Status)))
+		}
+	}
+	return nil
+}
+
+// externalResourceTypes returns the ResourceTypes in the store that were loaded from a directory or catalog by key
+func externalResourceTypes(store Store) (map[string]model.Resource, error) {
+	var resources []model.Resource
+	sourceTypes, err := store.SourceTypes()
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range sourceTypes {
+		resources = append(resources, r)
+	}
+	processorTypes, err := store.ProcessorTypes()
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range processorTypes {
+		resources = append(resources, r)
+	}
+	destinationTypes, err := store.DestinationTypes()
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range destinationTypes {
+		resources = append(resources, r)
+	}
+
+	applied := map[string]model.Resource{}
+	for _, r := range resources {
+		switch ResourceTypeOrigin(r.GetLabels().Get(model.LabelBindPlaneOrigin)) {
+		case ResourceTypeOriginDirectory, ResourceTypeOriginCatalog:
+			applied[resourceTypeKey(r)] = r
+		}
+	}
+	return applied, nil
+}
+
+// remove removes applied ResourceTypes that are no longer present, restoring builtin ResourceTypes they replaced
+func (l *ResourceTypeLoader) remove(external, applied map[string]model.Resource) error {
+	var restore, remove []model.Resource
+	for key, r := range applied {
+		if _, ok := external[key]; ok {
+			continue
+		}
+		if builtin, ok := l.builtin[key]; ok {
+			restore = append(restore, builtin)
+			continue
+		}
+		inUse, err := resourceTypeInUse(l.store, r)
+		if err != nil {
+			return err
+		}
+		if inUse {
+			l.logger.Warn("not removing ResourceType because it is in use", zap.String("resourceType", key))
+			continue
+		}
+		remove = append(remove, r)
+	}
+
+	var errs error
+	if len(restore) > 0 {
+		statuses, err := l.store.ApplyResources(restore)
+		if err != nil {
+			errs = multierror.Append(errs, err)
+		}
+		for _, status := range statuses {
+			l.logger.Info("restored builtin ResourceType", zap.String("resourceType", resourceTypeKey(status.Resource)))
+		}
+	}
+	if len(remove) > 0 {
+		statuses, err := l.store.DeleteResources(remove)
+		if err != nil {
+			errs = multierror.Append(errs, err)
+		}
+		for _, status := range statuses {
+			l.logger.Info("removed ResourceType", zap.String("resourceType", resourceTypeKey(status.Resource)))
+		}
+	}
+	return errs
+}
+
+// readDir reads all of the yaml files in the directory and its subdirectories
+func (l *ResourceTypeLoader) readDir(dir string) []loadedResourceTypes {
+	var loaded []loadedResourceTypes
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !isYamlFile(path) {
+			return nil
+		}
+		resources, err := readResourceTypesFile(path)
+		loaded = append(loaded, loadedResourceTypes{
+			location:  path,
+			resources: resources,
+			err:       err,
+		})
+		return nil
+	})
+	if err != nil {
+		// keep all of the ResourceTypes previously read from this directory
+		return l.previousLocations(dir, dir+string(filepath.Separator), err)
+	}
+	return loaded
+}
+
+// readCatalog reads the catalog index and each of the files it references
+func (l *ResourceTypeLoader) readCatalog(ctx context.Context, catalogURL string) []loadedResourceTypes {
+	catalog, err := l.fetchCatalog(ctx, catalogURL)
+	if err != nil {
+		// keep all of the ResourceTypes previously read from this catalog
+		return l.previousLocations(catalogURL, catalogLocationPrefix, err)
+	}
+
+	base, _ := url.Parse(catalogURL)
+	loaded := make([]loadedResourceTypes, 0, len(catalog.ResourceTypes))
+	for _, entry := range catalog.ResourceTypes {
+		ref, err := url.Parse(entry)
+		if err != nil {
+			loaded = append(loaded, loadedResourceTypes{location: catalogLocationPrefix + entry, err: err})
+			continue
+		}
+		resourceURL := base.ResolveReference(ref).String()
+		resources, err := l.fetchResourceTypes(ctx, resourceURL)
+		loaded = append(loaded, loadedResourceTypes{
+			location:  catalogLocationPrefix + resourceURL,
+			resources: resources,
+			err:       err,
+		})
+	}
+	return loaded
+}
+
+// previousLocations returns a failed result for each previously read location with the specified prefix so that
+// their ResourceTypes are kept. If there are none, a single failed result for location is returned.
+func (l *ResourceTypeLoader) previousLocations(location, prefix string, err error) []loadedResourceTypes {
+	var loaded []loadedResourceTypes
+	for previous := range l.previous {
+		if strings.HasPrefix(previous, prefix) {
+			loaded = append(loaded, loadedResourceTypes{location: previous, err: err})
+		}
+	}
+	if len(loaded) == 0 {
+		loaded = append(loaded, loadedResourceTypes{location: location, err: err})
+	}
+	return loaded
+}
+
+// catalogLocationPrefix distinguishes catalog locations from directory locations
+const catalogLocationPrefix = "catalog:"
+
+func (l *ResourceTypeLoader) fetchCatalog(ctx context.Context, catalogURL string) (*ResourceTypeCatalog, error) {
+	body, err := l.get(ctx, catalogURL)
+	if err != nil {
+		return nil, err
+	}
+	catalog := &ResourceTypeCatalog{}
+	if err := yaml.Unmarshal(body, catalog); err != nil {
+		return nil, fmt.Errorf("failed to parse catalog %s: %w", catalogURL, err)
+	}
+	return catalog, nil
+}
+
+func (l *ResourceTypeLoader) fetchResourceTypes(ctx context.Context, resourceURL string) ([]model.Resource, error) {
+	body, err := l.get(ctx, resourceURL)
+	if err != nil {
+		return nil, err
+	}
+	return readResourceTypes(strings.NewReader(string(body)), ResourceTypeOriginCatalog)
+}
+
+func (l *ResourceTypeLoader) get(ctx context.Context, resourceURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, resourceURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := l.settings.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to get %s: %s", resourceURL, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// ----------------------------------------------------------------------
+
+func readResourceTypesFile(path string) ([]model.Resource, error) {
+	file, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+	return readResourceTypes(file, ResourceTypeOriginDirectory)
+}
+
+// readResourceTypes parses and validates the ResourceTypes in the reader. An error is returned if any of the resources
+// are invalid or are not a SourceType, ProcessorType, or DestinationType.
+func readResourceTypes(reader io.Reader, origin ResourceTypeOrigin) ([]model.Resource, error) {
+	anyResources, err := model.ResourcesFromReader(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs error
+	for _, r := range anyResources {
+		switch r.Kind {
+		case model.KindSourceType, model.KindProcessorType, model.KindDestinationType:
+			setOriginLabel(r, origin)
+		default:
+			errs = multierror.Append(errs, fmt.Errorf("%s %s is not a ResourceType", r.Kind, r.Metadata.Name))
+		}
+	}
+	if errs != nil {
+		return nil, errs
+	}
+
+	resources, err := model.ParseResources(anyResources)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range resources {
+		if _, err := r.Validate(); err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("%s %s is invalid: %w", r.GetKind(), r.Name(), err))
+		}
+	}
+	if errs != nil {
+		return nil, errs
+	}
+	return resources, nil
+}
+
+// resourceTypeInUse returns true if a Source, Processor, Destination, or Configuration uses the ResourceType
+func resourceTypeInUse(s Store, r model.Resource) (bool, error) {
+	name := r.Name()
+	switch r.GetKind() {
+	case model.KindSourceType:
+		sources, err := s.Sources()
+		if err != nil {
+			return false, err
+		}
+		for _, source := range sources {
+			if source.Spec.Type == name {
+				return true, nil
+			}
+		}
+	case model.KindProcessorType:
+		processors, err := s.Processors()
+		if err != nil {
+			return false, err
+		}
+		for _, processor := range processors {
+			if processor.Spec.Type == name {
+				return true, nil
+			}
+		}
+	case model.KindDestinationType:
+		destinations, err := s.Destinations()
+		if err != nil {
+			return false, err
+		}
+		for _, destination := range destinations {
+			if destination.Spec.Type == name {
+				return true, nil
+			}
+		}
+	default:
+		return false, errors.New("not a ResourceType")
+	}
+
+	configurations, err := s.Configurations()
+	if err != nil {
+		return false, err
+	}
+	for _, configuration := range configurations {
+		if configurationUsesResourceType(configuration, r.GetKind(), name) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func configurationUsesResourceType(c *model.Configuration, kind model.Kind, name string) bool {
+	uses := func(resources []model.ResourceConfiguration) bool {
+		for _, rc := range resources {
+			if rc.Type == name {
+				return true
+			}
+		}
+		return false
+	}
+	switch kind {
+	case model.KindSourceType:
+		return uses(c.Spec.Sources)
+	case model.KindProcessorType:
+		for _, source := range c.Spec.Sources {
+			if uses(source.Processors) {
+				return true
+			}
+		}
+		for _, destination := range c.Spec.Destinations {
+			if uses(destination.Processors) {
+				return true
+			}
+		}
+	case model.KindDestinationType:
+		return uses(c.Spec.Destinations)
+	}
+	return false
+}
+
+// resourceTypeKey uniquely identifies a ResourceType by kind and name
+func resourceTypeKey(r model.Resource) string {
+	return fmt.Sprintf("%s/%s", r.GetKind(), r.Name())
+}
+
+// setOriginLabel sets the bindplane/origin label on the resource
+func setOriginLabel(r *model.AnyResource, origin ResourceTypeOrigin) {
+	if r.Metadata.Labels.Set == nil {
+		r.Metadata.Labels = model.MakeLabels()
+	}
+	r.Metadata.Labels.Set[model.LabelBindPlaneOrigin] = string(origin)
+}
+
+func isYamlFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yaml" || ext == ".yml"
+}
@@ -0,0 +1,347 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/observiq/bindplane-op/model"
+)
+
+func testSourceTypeYaml(name, displayName string) string {
+	return fmt.Sprintf(`apiVersion: bindplane.observiq.com/v1
+kind: SourceType
+metadata:
+  name: %s
+  displayName: %s
+spec:
+  version: 0.0.1
+  parameters:
+    - name: path
+      type: string
+      default: /var/log/app.log
+  logs:
+    receivers: |
+      - filelog:
+          include: [ {{ .path }} ]
+`, name, displayName)
+}
+
+func writeTestFile(t *testing.T, path, contents string) {
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0600))
+}
+
+func TestResourceTypeLoaderDirs(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	s := NewMapStore(ctx, testOptions, zap.NewNop())
+	loader := NewResourceTypeLoader(s, ResourceTypeLoaderSettings{Dirs: []string{dir}})
+
+	// added
+	file := filepath.Join(dir, "custom.yaml")
+	writeTestFile(t, file, testSourceTypeYaml("custom", "Custom"))
+	writeTestFile(t, filepath.Join(dir, "README.md"), "not a resource type")
+	require.NoError(t, loader.Sync(ctx))
+
+	sourceType, err := s.SourceType("custom")
+	require.NoError(t, err)
+	require.NotNil(t, sourceType)
+	require.Equal(t, "Custom", sourceType.Metadata.DisplayName)
+	require.Equal(t, string(ResourceTypeOriginDirectory), sourceType.GetLabels().Get(model.LabelBindPlaneOrigin))
+
+	// changed
+	writeTestFile(t, file, testSourceTypeYaml("custom", "Custom Changed"))
+	require.NoError(t, loader.Sync(ctx))
+
+	sourceType, err = s.SourceType("custom")
+	require.NoError(t, err)
+	require.Equal(t, "Custom Changed", sourceType.Metadata.DisplayName)
+
+	// invalid files keep the previous version
+	writeTestFile(t, file, "kind: SourceType\nmetadata:\n  name: custom\nspec:\n  logs:\n    receivers: '{{ .missing'\n")
+	require.NoError(t, loader.Sync(ctx))
+
+	sourceType, err = s.SourceType("custom")
+	require.NoError(t, err)
+	require.Equal(t, "Custom Changed", sourceType.Metadata.DisplayName)
+
+	// removed
+	require.NoError(t, os.Remove(file))
+	require.NoError(t, loader.Sync(ctx))
+
+	sourceType, err = s.SourceType("custom")
+	require.NoError(t, err)
+	require.Nil(t, sourceType)
+}
+
+func TestResourceTypeLoaderRejectsOtherKinds(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	s := NewMapStore(ctx, testOptions, zap.NewNop())
+	loader := NewResourceTypeLoader(s, ResourceTypeLoaderSettings{Dirs: []string{dir}})
+
+	contents := testSourceTypeYaml("custom", "Custom") + `---
+apiVersion: bindplane.observiq.com/v1
+kind: Source
+metadata:
+  name: custom
+spec:
+  type: custom
+`
+	writeTestFile(t, filepath.Join(dir, "custom.yaml"), contents)
+	require.NoError(t, loader.Sync(ctx))
+
+	sourceType, err := s.SourceType("custom")
+	require.NoError(t, err)
+	require.Nil(t, sourceType)
+	source, err := s.Source("custom")
+	require.NoError(t, err)
+	require.Nil(t, source)
+}
+
+func TestResourceTypeLoaderConflicts(t *testing.T) {
+	builtin, err := readResourceTypes(strings.NewReader(testSourceTypeYaml("custom", "Builtin")), ResourceTypeOriginBuiltin)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name              string
+		conflicts         ResourceTypeConflicts
+		expectDisplayName string
+		expectOrigin      ResourceTypeOrigin
+	}{
+		{
+			name:              "external",
+			conflicts:         ResourceTypeConflictsExternal,
+			expectDisplayName: "External",
+			expectOrigin:      ResourceTypeOriginDirectory,
+		},
+		{
+			name:              "builtin",
+			conflicts:         ResourceTypeConflictsBuiltin,
+			expectDisplayName: "Builtin",
+			expectOrigin:      ResourceTypeOriginBuiltin,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			dir := t.TempDir()
+			s := NewMapStore(ctx, testOptions, zap.NewNop())
+			_, err := s.ApplyResources(builtin)
+			require.NoError(t, err)
+
+			loader := NewResourceTypeLoader(s, ResourceTypeLoaderSettings{
+				Dirs:      []string{dir},
+				Conflicts: test.conflicts,
+				Builtin:   builtin,
+			})
+
+			file := filepath.Join(dir, "custom.yaml")
+			writeTestFile(t, file, testSourceTypeYaml("custom", "External"))
+			require.NoError(t, loader.Sync(ctx))
+
+			sourceType, err := s.SourceType("custom")
+			require.NoError(t, err)
+			require.Equal(t, test.expectDisplayName, sourceType.Metadata.DisplayName)
+			require.Equal(t, string(test.expectOrigin), sourceType.GetLabels().Get(model.LabelBindPlaneOrigin))
+
+			// removing the external ResourceType restores the builtin ResourceType
+			require.NoError(t, os.Remove(file))
+			require.NoError(t, loader.Sync(ctx))
+
+			sourceType, err = s.SourceType("custom")
+			require.NoError(t, err)
+			require.Equal(t, "Builtin", sourceType.Metadata.DisplayName)
+			require.Equal(t, string(ResourceTypeOriginBuiltin), sourceType.GetLabels().Get(model.LabelBindPlaneOrigin))
+		})
+	}
+}
+
+func TestResourceTypeLoaderRestart(t *testing.T) {
+	builtin, err := readResourceTypes(strings.NewReader(testSourceTypeYaml("custom", "Builtin")), ResourceTypeOriginBuiltin)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	s := NewMapStore(ctx, testOptions, zap.NewNop())
+	_, err = s.ApplyResources(builtin)
+	require.NoError(t, err)
+
+	settings := ResourceTypeLoaderSettings{Dirs: []string{dir}, Builtin: builtin}
+	writeTestFile(t, filepath.Join(dir, "custom.yaml"), testSourceTypeYaml("custom", "External"))
+	writeTestFile(t, filepath.Join(dir, "other.yaml"), testSourceTypeYaml("other", "Other"))
+	require.NoError(t, NewResourceTypeLoader(s, settings).Sync(ctx))
+
+	// a new loader, e.g. after a restart or on a new leader, removes the ResourceTypes applied by the previous loader
+	require.NoError(t, os.Remove(filepath.Join(dir, "custom.yaml")))
+	require.NoError(t, os.Remove(filepath.Join(dir, "other.yaml")))
+	require.NoError(t, NewResourceTypeLoader(s, settings).Sync(ctx))
+
+	sourceType, err := s.SourceType("custom")
+	require.NoError(t, err)
+	require.Equal(t, "Builtin", sourceType.Metadata.DisplayName)
+	require.Equal(t, string(ResourceTypeOriginBuiltin), sourceType.GetLabels().Get(model.LabelBindPlaneOrigin))
+
+	sourceType, err = s.SourceType("other")
+	require.NoError(t, err)
+	require.Nil(t, sourceType)
+}
+
+func TestResourceTypeLoaderDuplicates(t *testing.T) {
+	ctx := context.Background()
+	dir1 := t.TempDir()
+	dir2 := t.TempDir()
+	s := NewMapStore(ctx, testOptions, zap.NewNop())
+	loader := NewResourceTypeLoader(s, ResourceTypeLoaderSettings{Dirs: []string{dir1, dir2}})
+
+	writeTestFile(t, filepath.Join(dir1, "custom.yaml"), testSourceTypeYaml("custom", "First"))
+	writeTestFile(t, filepath.Join(dir2, "custom.yaml"), testSourceTypeYaml("custom", "Second"))
+	require.NoError(t, loader.Sync(ctx))
+
+	sourceType, err := s.SourceType("custom")
+	require.NoError(t, err)
+	require.Equal(t, "First", sourceType.Metadata.DisplayName)
+}
+
+func TestResourceTypeLoaderInUse(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	s := NewMapStore(ctx, testOptions, zap.NewNop())
+	loader := NewResourceTypeLoader(s, ResourceTypeLoaderSettings{Dirs: []string{dir}})
+
+	file := filepath.Join(dir, "custom.yaml")
+	writeTestFile(t, file, testSourceTypeYaml("custom", "Custom"))
+	require.NoError(t, loader.Sync(ctx))
+
+	_, err := s.ApplyResources([]model.Resource{model.NewSource("custom-source", "custom", nil)})
+	require.NoError(t, err)
+
+	require.NoError(t, os.Remove(file))
+	require.NoError(t, loader.Sync(ctx))
+
+	sourceType, err := s.SourceType("custom")
+	require.NoError(t, err)
+	require.NotNil(t, sourceType)
+}
+
+func TestResourceTypeLoaderCatalog(t *testing.T) {
+	var mtx sync.Mutex
+	files := map[string]string{
+		"/catalog/index.yaml":  "resourceTypes:\n  - custom.yaml\n  - types/other.yaml\n",
+		"/catalog/custom.yaml": testSourceTypeYaml("custom", "Custom"),
+		"/catalog/types/other.yaml": `apiVersion: bindplane.observiq.com/v1
+kind: DestinationType
+metadata:
+  name: other
+spec:
+  version: 0.0.1
+  parameters: []
+  logs:
+    exporters: |
+      - logging:
+`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		contents, ok := files[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(contents))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	s := NewMapStore(ctx, testOptions, zap.NewNop())
+	loader := NewResourceTypeLoader(s, ResourceTypeLoaderSettings{
+		CatalogURL: server.URL + "/catalog/index.yaml",
+		Client:     server.Client(),
+	})
+	require.NoError(t, loader.Sync(ctx))
+
+	sourceType, err := s.SourceType("custom")
+	require.NoError(t, err)
+	require.NotNil(t, sourceType)
+	require.Equal(t, string(ResourceTypeOriginCatalog), sourceType.GetLabels().Get(model.LabelBindPlaneOrigin))
+
+	destinationType, err := s.DestinationType("other")
+	require.NoError(t, err)
+	require.NotNil(t, destinationType)
+
+	// an unavailable catalog keeps the previous ResourceTypes
+	mtx.Lock()
+	delete(files, "/catalog/index.yaml")
+	mtx.Unlock()
+	require.NoError(t, loader.Sync(ctx))
+
+	sourceType, err = s.SourceType("custom")
+	require.NoError(t, err)
+	require.NotNil(t, sourceType)
+
+	// ResourceTypes removed from the catalog are removed from the store
+	mtx.Lock()
+	files["/catalog/index.yaml"] = "resourceTypes:\n  - custom.yaml\n"
+	mtx.Unlock()
+	require.NoError(t, loader.Sync(ctx))
+
+	destinationType, err = s.DestinationType("other")
+	require.NoError(t, err)
+	require.Nil(t, destinationType)
+	sourceType, err = s.SourceType("custom")
+	require.NoError(t, err)
+	require.NotNil(t, sourceType)
+}
+
+func TestSeedKeepsExternalResourceTypes(t *testing.T) {
+	ctx := context.Background()
+	s := NewMapStore(ctx, testOptions, zap.NewNop())
+	require.NoError(t, Seed(s, zap.NewNop()))
+
+	var builtin model.Resource
+	for _, r := range BuiltinResourceTypes(zap.NewNop()) {
+		if r.GetKind() == model.KindSourceType {
+			builtin = r
+			break
+		}
+	}
+	require.NotNil(t, builtin)
+
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "custom.yaml"), testSourceTypeYaml(builtin.Name(), "External"))
+	loader := NewResourceTypeLoader(s, ResourceTypeLoaderSettings{Dirs: []string{dir}, Builtin: BuiltinResourceTypes(zap.NewNop())})
+	require.NoError(t, loader.Sync(ctx))
+
+	// seeding again, e.g. when another node starts, doesn't revert the external ResourceType
+	require.NoError(t, Seed(s, zap.NewNop()))
+
+	sourceType, err := s.SourceType(builtin.Name())
+	require.NoError(t, err)
+	require.Equal(t, "External", sourceType.Metadata.DisplayName)
+	require.Equal(t, string(ResourceTypeOriginDirectory), sourceType.GetLabels().Get(model.LabelBindPlaneOrigin))
+}
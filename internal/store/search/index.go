@@ -156,6 +156,18 @@ func (i *index) Matches(query *Query, indexID string) bool {
 	return true
 }
 
+// Matches returns true if the indexed resource matches the query. It is used to search resources that are not in an
+// Index.
+func Matches(query *Query, indexed Indexed) bool {
+	doc := newDocument(indexed)
+	for _, token := range query.Tokens {
+		if !tokenMatchesDocument(token, doc) {
+			return false
+		}
+	}
+	return true
+}
+
 func (i *index) Suggestions(query *Query) ([]*Suggestion, error) {
 	i.mtx.RLock()
 	defer i.mtx.RUnlock()
@@ -605,3 +605,26 @@ func exactSuggestion(label, query string) *Suggestion {
 		Score: ScoreExact,
 	}
 }
+
+func TestMatches(t *testing.T) {
+	doc := emptyDocument("1")
+	doc.addField("name", "nginx")
+	doc.addField("kind", "source")
+	doc.labels["env"] = "prod"
+
+	tests := []struct {
+		query  string
+		expect bool
+	}{
+		{query: "ngi", expect: true},
+		{query: "name:nginx kind:source", expect: true},
+		{query: "env:prod", expect: true},
+		{query: "-env:prod", expect: false},
+		{query: "kind:destination", expect: false},
+	}
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			require.Equal(t, test.expect, Matches(ParseQuery(test.query), doc))
+		})
+	}
+}
@@ -16,15 +16,16 @@ package store
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/fs"
 	"net/http"
-	"sort"
 	"time"
 
 	"github.com/gorilla/sessions"
 	"github.com/hashicorp/go-multierror"
+	"github.com/observiq/bindplane-op/internal/cluster"
 	"github.com/observiq/bindplane-op/internal/eventbus"
 	"github.com/observiq/bindplane-op/internal/store/search"
 	"github.com/observiq/bindplane-op/model"
@@ -39,6 +40,9 @@ type Options struct {
 	// MaxEventsToMerge is the maximum number of update events (inserts, updates, deletes, etc) to merge into a single
 	// event.
 	MaxEventsToMerge int
+	// Bus is used to send Updates to every BindPlane node sharing the store. If nil, Updates are only sent to this node
+	// unless the store provides its own Bus.
+	Bus cluster.Bus
 }
 
 // Store handles interacting with a storage backend,
@@ -46,6 +50,8 @@ type Store interface {
 	Clear()
 
 	Agent(string) (*model.Agent, error)
+	// Agents returns the agents matching the query options. The list methods of the Store all support selector,
+	// query, sort, offset, limit, and continue options.
 	Agents(ctx context.Context, options ...QueryOption) ([]*model.Agent, error)
 	AgentsCount(context.Context, ...QueryOption) (int, error)
 	// UpsertAgent adds a new Agent to the Store or updates an existing one
@@ -54,7 +60,7 @@ type Store interface {
 	DeleteAgents(ctx context.Context, agentIDs []string) ([]*model.Agent, error)
 
 	AgentVersion(name string) (*model.AgentVersion, error)
-	AgentVersions() ([]*model.AgentVersion, error)
+	AgentVersions(options ...QueryOption) ([]*model.AgentVersion, error)
 	DeleteAgentVersion(string) (*model.AgentVersion, error)
 
 	Configurations(options ...QueryOption) ([]*model.Configuration, error)
@@ -62,30 +68,34 @@ type Store interface {
 	DeleteConfiguration(string) (*model.Configuration, error)
 
 	Source(name string) (*model.Source, error)
-	Sources() ([]*model.Source, error)
+	Sources(options ...QueryOption) ([]*model.Source, error)
 	DeleteSource(name string) (*model.Source, error)
 
 	SourceType(name string) (*model.SourceType, error)
-	SourceTypes() ([]*model.SourceType, error)
+	SourceTypes(options ...QueryOption) ([]*model.SourceType, error)
 	DeleteSourceType(name string) (*model.SourceType, error)
 
 	Processor(name string) (*model.Processor, error)
-	Processors() ([]*model.Processor, error)
+	Processors(options ...QueryOption) ([]*model.Processor, error)
 	DeleteProcessor(name string) (*model.Processor, error)
 
 	ProcessorType(name string) (*model.ProcessorType, error)
-	ProcessorTypes() ([]*model.ProcessorType, error)
+	ProcessorTypes(options ...QueryOption) ([]*model.ProcessorType, error)
 	DeleteProcessorType(name string) (*model.ProcessorType, error)
 
 	Destination(name string) (*model.Destination, error)
-	Destinations() ([]*model.Destination, error)
+	Destinations(options ...QueryOption) ([]*model.Destination, error)
 	DeleteDestination(name string) (*model.Destination, error)
 
 	DestinationType(name string) (*model.DestinationType, error)
-	DestinationTypes() ([]*model.DestinationType, error)
+	DestinationTypes(options ...QueryOption) ([]*model.DestinationType, error)
 	DeleteDestinationType(name string) (*model.DestinationType, error)
 
 	ApplyResources([]model.Resource) ([]model.ResourceStatus, error)
+	// ApplyResourcesWithPreconditions applies the resources if the resources in the preconditions have not changed. The
+	// preconditions are checked atomically with the apply. It returns an error wrapping ErrPreconditionFailed and applies
+	// none of the resources if a precondition is not met.
+	ApplyResourcesWithPreconditions(resources []model.Resource, preconditions []model.ResourcePrecondition) ([]model.ResourceStatus, error)
 	// Batch delete of a slice of resources, returns the successfully deleted resources or an error.
 	DeleteResources([]model.Resource) ([]model.ResourceStatus, error)
 
@@ -111,6 +121,60 @@ type Store interface {
 
 	// UserSessions must implement the gorilla sessions.Store interface
 	UserSessions() sessions.Store
+
+	// ClusterBus is used to send messages between the BindPlane nodes sharing the store, e.g. to forward updates to an
+	// agent connected to another node. It is the Bus used to send Updates.
+	ClusterBus() cluster.Bus
+
+	// AcquireLease acquires or renews the named lease for the holder. It returns false if the lease is held by another
+	// holder and has not expired. Leases are used to elect a leader to run singleton background jobs.
+	AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error)
+
+	// ReleaseLease releases the named lease if it is held by the holder
+	ReleaseLease(ctx context.Context, name, holder string) error
+
+	// EnrollmentTokens returns all of the EnrollmentTokens
+	EnrollmentTokens(ctx context.Context) ([]*model.EnrollmentToken, error)
+	// EnrollmentToken returns the EnrollmentToken with the specified ID or nil if it does not exist
+	EnrollmentToken(ctx context.Context, id string) (*model.EnrollmentToken, error)
+	// CreateEnrollmentToken adds a new EnrollmentToken
+	CreateEnrollmentToken(ctx context.Context, token *model.EnrollmentToken) error
+	// UpdateEnrollmentToken atomically updates an existing EnrollmentToken. The token is not saved if the updater
+	// returns an error. It returns ErrResourceMissing if the token does not exist.
+	UpdateEnrollmentToken(ctx context.Context, id string, updater EnrollmentTokenUpdater) (*model.EnrollmentToken, error)
+	// DeleteEnrollmentToken removes the EnrollmentToken and returns it or nil if it does not exist
+	DeleteEnrollmentToken(ctx context.Context, id string) (*model.EnrollmentToken, error)
+
+	// AgentCredential returns the credential issued to the agent or nil if there is no credential
+	AgentCredential(ctx context.Context, agentID string) (*model.AgentCredential, error)
+	// UpsertAgentCredential atomically adds or updates the credential of the agent. The credential is not saved if the
+	// updater returns an error.
+	UpsertAgentCredential(ctx context.Context, agentID string, updater AgentCredentialUpdater) (*model.AgentCredential, error)
+
+	// AgentDenyRules returns all of the AgentDenyRules
+	AgentDenyRules(ctx context.Context) ([]*model.AgentDenyRule, error)
+	// AddAgentDenyRule adds the AgentDenyRule, replacing any rule with the same ID
+	AddAgentDenyRule(ctx context.Context, rule *model.AgentDenyRule) error
+	// DeleteAgentDenyRule removes the AgentDenyRule and returns it or nil if it does not exist
+	DeleteAgentDenyRule(ctx context.Context, id string) (*model.AgentDenyRule, error)
+
+	// UserSessionRecords returns the server side records of all of the user sessions, including expired sessions that
+	// have not been deleted
+	UserSessionRecords(ctx context.Context) ([]*model.UserSession, error)
+	// UserSessionRecord returns the UserSession with the specified ID or nil if it does not exist
+	UserSessionRecord(ctx context.Context, id string) (*model.UserSession, error)
+	// CreateUserSessionRecord adds a new UserSession
+	CreateUserSessionRecord(ctx context.Context, session *model.UserSession) error
+	// UpdateUserSessionRecord atomically updates an existing UserSession. The session is not saved if the updater
+	// returns an error. It returns ErrResourceMissing if the session does not exist.
+	UpdateUserSessionRecord(ctx context.Context, id string, updater UserSessionUpdater) (*model.UserSession, error)
+	// DeleteUserSessionRecord removes the UserSession and returns it or nil if it does not exist
+	DeleteUserSessionRecord(ctx context.Context, id string) (*model.UserSession, error)
+
+	// GitOpsSyncStatus returns the status of the most recent GitOps sync or nil if resources have not been synced
+	GitOpsSyncStatus(ctx context.Context) (*model.GitOpsSyncStatus, error)
+	// SetGitOpsSyncStatus saves the status of the most recent GitOps sync
+	SetGitOpsSyncStatus(ctx context.Context, status *model.GitOpsSyncStatus) error
 }
 
 // AgentUpdater is given the current Agent model (possibly empty except for ID) and should update the Agent directly. We
@@ -118,6 +182,21 @@ type Store interface {
 // Store implementation.
 type AgentUpdater func(current *model.Agent)
 
+// EnrollmentTokenUpdater is given the current EnrollmentToken and should update it directly. If it returns an error,
+// the update is not saved.
+type EnrollmentTokenUpdater func(current *model.EnrollmentToken) error
+
+// AgentCredentialUpdater is given the current AgentCredential (possibly empty except for AgentID) and should update it
+// directly. If it returns an error, the update is not saved.
+type AgentCredentialUpdater func(current *model.AgentCredential) error
+
+// UserSessionUpdater is given the current UserSession and should update it directly. If it returns an error, the
+// update is not saved.
+type UserSessionUpdater func(current *model.UserSession) error
+
+// gitOpsSyncStatusKey is the key of the GitOpsSyncStatus record. There is only one GitOps repository per server.
+const gitOpsSyncStatusKey = "status"
+
 // ErrResourceMissing is used in delete functions to indicate the delete
 // could not be performed because no such resource exists
 var ErrResourceMissing = errors.New("resource not found")
@@ -127,6 +206,17 @@ var ErrResourceMissing = errors.New("resource not found")
 // i.e. the Source that is being deleted is being referenced in a Configuration.
 var ErrResourceInUse = errors.New("resource in use")
 
+// ErrPreconditionFailed is returned by ApplyResourcesWithPreconditions when a resource in the preconditions was
+// changed or deleted since it was read
+var ErrPreconditionFailed = errors.New("precondition failed")
+
+// ErrInvalidSort is returned by queries with a sort field that is not supported for the type of item
+var ErrInvalidSort = errors.New("invalid sort")
+
+// ErrInvalidContinueToken is returned by queries with a continue token that is malformed or was created with a
+// different sort
+var ErrInvalidContinueToken = errors.New("invalid continue token")
+
 // ----------------------------------------------------------------------
 
 // queryOptions represents the set of options available for a store query
@@ -136,6 +226,8 @@ type queryOptions struct {
 	offset   int
 	limit    int
 	sort     string
+	// continueToken is the token returned with the previous page of results
+	continueToken string
 }
 
 func makeQueryOptions(options []QueryOption) queryOptions {
@@ -182,14 +274,57 @@ func WithLimit(limit int) QueryOption {
 }
 
 // WithSort sets the sort order for the request. The sort value is the name of the field, sorted ascending. To sort
-// descending, prefix the field with a minus sign (-). Some Stores only allow sorting by certain fields. Sort values not
-// supported will be ignored.
+// descending, prefix the field with a minus sign (-). Agents can be sorted by id, name, status, or version. Resources
+// can be sorted by name or displayName, sources, processors, and destinations by type, and agent versions by version.
+// Other fields sort by name. Items with the same value are sorted by their unique key.
 func WithSort(field string) QueryOption {
 	return func(opts *queryOptions) {
 		opts.sort = field
 	}
 }
 
+// WithContinue sets the continue token returned by ContinueToken for the last item of the previous page. The results
+// will start after that item. The sort must be the same as the sort of the previous page.
+func WithContinue(token string) QueryOption {
+	return func(opts *queryOptions) {
+		opts.continueToken = token
+	}
+}
+
+// ----------------------------------------------------------------------
+// preconditions
+
+// checkPrecondition returns an error wrapping ErrPreconditionFailed if the current resource, nil if it doesn't exist,
+// doesn't match the hash of the precondition
+func checkPrecondition(precondition model.ResourcePrecondition, current model.Resource) error {
+	if current == nil {
+		return fmt.Errorf("%w: %s %s was deleted", ErrPreconditionFailed, precondition.Kind, precondition.Name)
+	}
+	hash, err := model.ResourceHash(current)
+	if err != nil {
+		return err
+	}
+	if hash != precondition.Hash {
+		return fmt.Errorf("%w: %s %s was changed", ErrPreconditionFailed, precondition.Kind, precondition.Name)
+	}
+	return nil
+}
+
+// checkStoredPrecondition checks the precondition against the json of a stored resource, nil if it doesn't exist
+func checkStoredPrecondition(precondition model.ResourcePrecondition, data []byte) error {
+	if data == nil {
+		return checkPrecondition(precondition, nil)
+	}
+	current, err := model.NewEmptyResource(precondition.Kind)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, current); err != nil {
+		return fmt.Errorf("failed to unmarshal %s %s: %w", precondition.Kind, precondition.Name, err)
+	}
+	return checkPrecondition(precondition, current)
+}
+
 // ----------------------------------------------------------------------
 // seeding resources
 
@@ -205,8 +340,49 @@ func Seed(store Store, logger *zap.Logger) error {
 	return errs
 }
 
-// seedDir adds bundled resources from the specified dir to the store
+// BuiltinResourceTypes returns the ResourceTypes bundled with BindPlane, labeled with their builtin origin
+func BuiltinResourceTypes(logger *zap.Logger) []model.Resource {
+	resourceTypes := make([]model.Resource, 0)
+	for _, dir := range embedded.SeedFolders {
+		resourceTypes = append(resourceTypes, readSeedDir(dir, logger)...)
+	}
+	return resourceTypes
+}
+
+// seedDir adds bundled resources from the specified dir to the store. Builtin ResourceTypes that were replaced by a
+// ResourceType loaded from a directory or catalog are skipped so that seeding doesn't revert them.
 func seedDir(dir string, store Store, logger *zap.Logger) error {
+	external, err := externalResourceTypes(store)
+	if err != nil {
+		return fmt.Errorf("failed to list ResourceTypes: %w", err)
+	}
+
+	var resourceTypes []model.Resource
+	for _, r := range readSeedDir(dir, logger) {
+		if _, ok := external[resourceTypeKey(r)]; ok {
+			logger.Debug("not seeding ResourceType replaced by the ResourceTypeLoader", zap.String("resourceType", resourceTypeKey(r)))
+			continue
+		}
+		resourceTypes = append(resourceTypes, r)
+	}
+
+	updates, err := store.ApplyResources(resourceTypes)
+	if err != nil {
+		return err
+	}
+
+	messages := make([]string, len(updates))
+	for i, update := range updates {
+		messages[i] = fmt.Sprintf("%s %s", update.Resource.Name(), update.Status)
+	}
+
+	logger.Info("Seeded ResourceTypes", zap.String("dir", dir), zap.Any("resourceTypes", messages))
+
+	return nil
+}
+
+// readSeedDir reads the bundled resources from the specified dir
+func readSeedDir(dir string, logger *zap.Logger) []model.Resource {
 	filesystem := embedded.Files
 	resourceTypes := make([]model.Resource, 0)
 
@@ -226,6 +402,9 @@ func seedDir(dir string, store Store, logger *zap.Logger) error {
 			logger.Error("failed to get resource from reader", zap.String("path", path), zap.Error(err))
 			return nil
 		}
+		for _, resource := range r {
+			setOriginLabel(resource, ResourceTypeOriginBuiltin)
+		}
 
 		parsed, err := model.ParseResources(r)
 		if err != nil {
@@ -237,19 +416,7 @@ func seedDir(dir string, store Store, logger *zap.Logger) error {
 		return nil
 	})
 
-	updates, err := store.ApplyResources(resourceTypes)
-	if err != nil {
-		return err
-	}
-
-	messages := make([]string, len(updates))
-	for i, update := range updates {
-		messages[i] = fmt.Sprintf("%s %s", update.Resource.Name(), update.Status)
-	}
-
-	logger.Info("Seeded ResourceTypes", zap.String("dir", dir), zap.Any("resourceTypes", messages))
-
-	return nil
+	return resourceTypes
 }
 
 type dependency struct {
@@ -324,39 +491,6 @@ func FindDependentResources(ctx context.Context, s Store, r model.Resource) (Dep
 }
 
 // ----------------------------------------------------------------------
-// generic helpers for sorting and paging
-
-func applySortOffsetAndLimit[T any](list []T, opts queryOptions, fieldAccessor fieldAccessor[T]) []T {
-	if opts.sort != "" {
-		sortField := opts.sort
-		ascending := true
-		if opts.sort[0] == '-' {
-			sortField = opts.sort[1:]
-			ascending = false
-		}
-		sort.Sort(byField[T]{
-			list:          list,
-			field:         sortField,
-			ascending:     ascending,
-			fieldAccessor: fieldAccessor,
-		})
-	}
-	if opts.offset != 0 {
-		offset := opts.offset
-		if offset > len(list) {
-			offset = len(list)
-		}
-		list = list[offset:]
-	}
-	if opts.limit != 0 {
-		limit := opts.limit
-		if limit > len(list) {
-			limit = len(list)
-		}
-		list = list[:limit]
-	}
-	return list
-}
 
 func newBPCookieStore(secret string) *sessions.CookieStore {
 	store := sessions.NewCookieStore([]byte(secret))
@@ -364,34 +498,3 @@ func newBPCookieStore(secret string) *sessions.CookieStore {
 	store.Options.SameSite = http.SameSiteStrictMode
 	return store
 }
-
-type fieldAccessor[T any] func(field string, item T) string
-
-type byField[T any] struct {
-	list          []T
-	field         string
-	ascending     bool
-	fieldAccessor fieldAccessor[T]
-}
-
-var _ sort.Interface = (*byField[any])(nil)
-
-// Len returns the length of the list
-func (r byField[T]) Len() int {
-	return len(r.list)
-}
-
-// Swap swaps to items in the list
-func (r byField[T]) Swap(i, j int) {
-	r.list[i], r.list[j] = r.list[j], r.list[i]
-}
-
-// Less returns true if the i'th item is less than the j'th
-func (r byField[T]) Less(i, j int) bool {
-	f1 := r.fieldAccessor(r.field, r.list[i])
-	f2 := r.fieldAccessor(r.field, r.list[j])
-	if r.ascending {
-		return f1 < f2
-	}
-	return f1 > f2
-}
@@ -19,6 +19,7 @@ package store
 import (
 	"context"
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -669,6 +670,89 @@ func runApplyResourceReturnTests(t *testing.T, store Store) {
 	}
 }
 
+// These tests that ApplyResourcesWithPreconditions only applies resources that have not changed since they were read
+func runApplyResourcesWithPreconditionsTests(t *testing.T, store Store) {
+	sourceWithValue := func(value string) *model.Source {
+		return model.NewSource("macos-1", "macos", []model.Parameter{{Name: "s", Value: value}})
+	}
+
+	// setup applies the source and returns a precondition for it as read from the store
+	setup := func(t *testing.T) model.ResourcePrecondition {
+		store.Clear()
+		applyTestTypes(t, store)
+		_, err := store.ApplyResources([]model.Resource{sourceWithValue("1")})
+		require.NoError(t, err)
+
+		current, err := store.Source("macos-1")
+		require.NoError(t, err)
+		hash, err := model.ResourceHash(current)
+		require.NoError(t, err)
+		return model.ResourcePrecondition{Kind: model.KindSource, Name: "macos-1", Hash: hash}
+	}
+
+	t.Run("applies if the resource has not changed", func(t *testing.T) {
+		precondition := setup(t)
+		statuses, err := store.ApplyResourcesWithPreconditions([]model.Resource{sourceWithValue("2")}, []model.ResourcePrecondition{precondition})
+		require.NoError(t, err)
+		require.Len(t, statuses, 1)
+		require.Equal(t, model.StatusConfigured, statuses[0].Status)
+	})
+
+	t.Run("does not apply if the resource changed", func(t *testing.T) {
+		precondition := setup(t)
+		_, err := store.ApplyResources([]model.Resource{sourceWithValue("2")})
+		require.NoError(t, err)
+
+		_, err = store.ApplyResourcesWithPreconditions([]model.Resource{sourceWithValue("3")}, []model.ResourcePrecondition{precondition})
+		require.ErrorIs(t, err, ErrPreconditionFailed)
+
+		current, err := store.Source("macos-1")
+		require.NoError(t, err)
+		require.Equal(t, "2", current.Spec.Parameters[0].Value)
+	})
+
+	t.Run("does not apply if the resource was deleted", func(t *testing.T) {
+		precondition := setup(t)
+		_, err := store.DeleteSource("macos-1")
+		require.NoError(t, err)
+
+		_, err = store.ApplyResourcesWithPreconditions([]model.Resource{sourceWithValue("3")}, []model.ResourcePrecondition{precondition})
+		require.ErrorIs(t, err, ErrPreconditionFailed)
+
+		current, err := store.Source("macos-1")
+		require.NoError(t, err)
+		require.Nil(t, current)
+	})
+
+	t.Run("only one of concurrent edits is applied", func(t *testing.T) {
+		precondition := setup(t)
+
+		const edits = 10
+		var wg sync.WaitGroup
+		errs := make(chan error, edits)
+		for i := 0; i < edits; i++ {
+			wg.Add(1)
+			go func(value string) {
+				defer wg.Done()
+				_, err := store.ApplyResourcesWithPreconditions([]model.Resource{sourceWithValue(value)}, []model.ResourcePrecondition{precondition})
+				errs <- err
+			}(fmt.Sprintf("edit-%d", i))
+		}
+		wg.Wait()
+		close(errs)
+
+		applied := 0
+		for err := range errs {
+			if err == nil {
+				applied++
+				continue
+			}
+			require.ErrorIs(t, err, ErrPreconditionFailed)
+		}
+		require.Equal(t, 1, applied)
+	})
+}
+
 func runValidateApplyResourcesTests(t *testing.T, store Store) {
 	tests := []struct {
 		name      string
@@ -1225,6 +1309,94 @@ func runPagingTests(t *testing.T, store Store) {
 	})
 }
 
+func runResourcePagingTests(t *testing.T, store Store) {
+	resources := []model.Resource{}
+	for i := 0; i < 25; i++ {
+		sourceType := model.NewSourceType(fmt.Sprintf("type-%02d", i), nil)
+		sourceType.Metadata.DisplayName = fmt.Sprintf("Type %02d", 24-i)
+		sourceType.Metadata.Labels = labels(map[string]string{"even": fmt.Sprintf("%t", i%2 == 0)})
+		resources = append(resources, sourceType)
+	}
+	statuses, err := store.ApplyResources(resources)
+	require.NoError(t, err)
+	requireOkStatuses(t, statuses)
+
+	names := func(items []*model.SourceType) []string {
+		result := []string{}
+		for _, item := range items {
+			result = append(result, item.Name())
+		}
+		return result
+	}
+
+	t.Run("selector, query, and limit", func(t *testing.T) {
+		selector, err := model.SelectorFromString("even=true")
+		require.NoError(t, err)
+		items, err := store.SourceTypes(WithSelector(selector), WithQuery(search.ParseQuery("type-1")), WithLimit(3))
+		require.NoError(t, err)
+		require.Equal(t, []string{"type-10", "type-12", "type-14"}, names(items))
+	})
+
+	for _, sort := range []string{"", "name", "-name", "displayName"} {
+		t.Run("continue with sort "+sort, func(t *testing.T) {
+			all, err := store.SourceTypes(WithSort(sort))
+			require.NoError(t, err)
+			require.Len(t, all, 25)
+
+			pages := []*model.SourceType{}
+			options := []QueryOption{WithSort(sort), WithLimit(10)}
+			for i := 0; i < 3; i++ {
+				page, err := store.SourceTypes(options...)
+				require.NoError(t, err)
+				pages = append(pages, page...)
+				if len(page) == 0 {
+					break
+				}
+				options = []QueryOption{WithSort(sort), WithLimit(10), WithContinue(ContinueToken(page[len(page)-1], sort))}
+			}
+			require.Equal(t, names(all), names(pages))
+		})
+	}
+
+	t.Run("sort descending", func(t *testing.T) {
+		items, err := store.SourceTypes(WithSort("-name"), WithLimit(2))
+		require.NoError(t, err)
+		require.Equal(t, []string{"type-24", "type-23"}, names(items))
+
+		items, err = store.SourceTypes(WithSort("displayName"), WithLimit(2))
+		require.NoError(t, err)
+		require.Equal(t, []string{"type-24", "type-23"}, names(items))
+	})
+
+	t.Run("continue token with a different sort", func(t *testing.T) {
+		items, err := store.SourceTypes(WithLimit(1))
+		require.NoError(t, err)
+		_, err = store.SourceTypes(WithSort("-name"), WithContinue(ContinueToken(items[0], "")))
+		require.ErrorIs(t, err, ErrInvalidContinueToken)
+		_, err = store.SourceTypes(WithContinue("not-a-token"))
+		require.ErrorIs(t, err, ErrInvalidContinueToken)
+	})
+
+	t.Run("unsupported sort field", func(t *testing.T) {
+		_, err := store.SourceTypes(WithSort("-status"))
+		require.ErrorIs(t, err, ErrInvalidSort)
+		_, err = store.SourceTypes(WithSort("type"))
+		require.ErrorIs(t, err, ErrInvalidSort)
+		_, err = store.Agents(context.TODO(), WithSort("displayName"))
+		require.ErrorIs(t, err, ErrInvalidSort)
+	})
+
+	t.Run("agents continue by id", func(t *testing.T) {
+		page, err := store.Agents(context.TODO(), WithSort("id"), WithLimit(10))
+		require.NoError(t, err)
+		require.Len(t, page, 10)
+		page, err = store.Agents(context.TODO(), WithSort("id"), WithLimit(10), WithContinue(ContinueToken(page[9], "id")))
+		require.NoError(t, err)
+		require.Len(t, page, 10)
+		require.Equal(t, "010", page[0].ID)
+	})
+}
+
 func runTestUpsertAgents(t *testing.T, store Store) {
 	t.Run("can insert new agents", func(t *testing.T) {
 		store.Clear()
@@ -1312,3 +1484,174 @@ func requireOkStatuses(t *testing.T, statuses []model.ResourceStatus) {
 		}, status.Status)
 	}
 }
+
+func runEnrollmentTests(t *testing.T, store Store) {
+	ctx := context.Background()
+
+	t.Run("enrollment tokens", func(t *testing.T) {
+		store.Clear()
+
+		tokenA := &model.EnrollmentToken{ID: "a", MaxUses: 1, Hash: "hash-a", Labels: labels(map[string]string{"env": "prod"})}
+		tokenB := &model.EnrollmentToken{ID: "b", Hash: "hash-b"}
+		require.NoError(t, store.CreateEnrollmentToken(ctx, tokenB))
+		require.NoError(t, store.CreateEnrollmentToken(ctx, tokenA))
+
+		tokens, err := store.EnrollmentTokens(ctx)
+		require.NoError(t, err)
+		require.Len(t, tokens, 2)
+		require.Equal(t, "a", tokens[0].ID, "sorted by id")
+
+		token, err := store.EnrollmentToken(ctx, "a")
+		require.NoError(t, err)
+		require.Equal(t, "prod", token.Labels.Set["env"])
+
+		missing, err := store.EnrollmentToken(ctx, "missing")
+		require.NoError(t, err)
+		require.Nil(t, missing)
+
+		updated, err := store.UpdateEnrollmentToken(ctx, "a", func(current *model.EnrollmentToken) error {
+			current.Uses++
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, 1, updated.Uses)
+
+		// the update is not saved if the updater fails
+		_, err = store.UpdateEnrollmentToken(ctx, "a", func(current *model.EnrollmentToken) error {
+			current.Uses++
+			return model.ErrEnrollmentTokenUsed
+		})
+		require.ErrorIs(t, err, model.ErrEnrollmentTokenUsed)
+		token, err = store.EnrollmentToken(ctx, "a")
+		require.NoError(t, err)
+		require.Equal(t, 1, token.Uses)
+
+		_, err = store.UpdateEnrollmentToken(ctx, "missing", func(current *model.EnrollmentToken) error { return nil })
+		require.ErrorIs(t, err, ErrResourceMissing)
+
+		deleted, err := store.DeleteEnrollmentToken(ctx, "a")
+		require.NoError(t, err)
+		require.Equal(t, "a", deleted.ID)
+		deleted, err = store.DeleteEnrollmentToken(ctx, "a")
+		require.NoError(t, err)
+		require.Nil(t, deleted)
+
+		store.Clear()
+		tokens, err = store.EnrollmentTokens(ctx)
+		require.NoError(t, err)
+		require.Empty(t, tokens)
+	})
+
+	t.Run("agent credentials", func(t *testing.T) {
+		store.Clear()
+
+		credential, err := store.AgentCredential(ctx, "1")
+		require.NoError(t, err)
+		require.Nil(t, credential)
+
+		credential, err = store.UpsertAgentCredential(ctx, "1", func(current *model.AgentCredential) error {
+			current.TokenID = "a"
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, "1", credential.AgentID)
+		require.Equal(t, "a", credential.TokenID)
+
+		credential, err = store.UpsertAgentCredential(ctx, "1", func(current *model.AgentCredential) error {
+			current.Hash = "hash"
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, "a", credential.TokenID, "existing fields are kept")
+		require.Equal(t, "hash", credential.Hash)
+
+		credential, err = store.AgentCredential(ctx, "1")
+		require.NoError(t, err)
+		require.Equal(t, "1", credential.AgentID)
+		require.Equal(t, "a", credential.TokenID)
+		require.Equal(t, "hash", credential.Hash)
+	})
+
+	t.Run("agent deny rules", func(t *testing.T) {
+		store.Clear()
+
+		hostRule := &model.AgentDenyRule{Field: model.AgentDenyFieldHostName, Value: "host-1", Reason: "decommissioned"}
+		idRule := &model.AgentDenyRule{Field: model.AgentDenyFieldID, Value: "1"}
+		require.NoError(t, store.AddAgentDenyRule(ctx, hostRule))
+		require.NoError(t, store.AddAgentDenyRule(ctx, idRule))
+		// adding the same rule replaces it
+		require.NoError(t, store.AddAgentDenyRule(ctx, idRule))
+
+		rules, err := store.AgentDenyRules(ctx)
+		require.NoError(t, err)
+		require.Len(t, rules, 2)
+		require.Equal(t, "hostname:host-1", rules[0].ID(), "sorted by id")
+		require.Equal(t, "decommissioned", rules[0].Reason)
+
+		deleted, err := store.DeleteAgentDenyRule(ctx, "id:1")
+		require.NoError(t, err)
+		require.Equal(t, "1", deleted.Value)
+		deleted, err = store.DeleteAgentDenyRule(ctx, "id:1")
+		require.NoError(t, err)
+		require.Nil(t, deleted)
+
+		store.Clear()
+		rules, err = store.AgentDenyRules(ctx)
+		require.NoError(t, err)
+		require.Empty(t, rules)
+	})
+
+	t.Run("user sessions", func(t *testing.T) {
+		store.Clear()
+
+		session, _, err := model.NewUserSession("user", model.RoleAdmin, time.Hour, time.Minute)
+		require.NoError(t, err)
+		require.NoError(t, store.CreateUserSessionRecord(ctx, session))
+
+		found, err := store.UserSessionRecord(ctx, session.ID)
+		require.NoError(t, err)
+		require.Equal(t, "user", found.User)
+		require.Equal(t, session.CSRFHash, found.CSRFHash)
+
+		missing, err := store.UserSessionRecord(ctx, "missing")
+		require.NoError(t, err)
+		require.Nil(t, missing)
+
+		lastSeen := session.LastSeenAt.Add(time.Minute)
+		updated, err := store.UpdateUserSessionRecord(ctx, session.ID, func(current *model.UserSession) error {
+			current.LastSeenAt = lastSeen
+			return nil
+		})
+		require.NoError(t, err)
+		require.True(t, lastSeen.Equal(updated.LastSeenAt))
+
+		_, err = store.UpdateUserSessionRecord(ctx, "missing", func(current *model.UserSession) error { return nil })
+		require.ErrorIs(t, err, ErrResourceMissing)
+
+		sessions, err := store.UserSessionRecords(ctx)
+		require.NoError(t, err)
+		require.Len(t, sessions, 1)
+
+		deleted, err := store.DeleteUserSessionRecord(ctx, session.ID)
+		require.NoError(t, err)
+		require.Equal(t, session.ID, deleted.ID)
+		deleted, err = store.DeleteUserSessionRecord(ctx, session.ID)
+		require.NoError(t, err)
+		require.Nil(t, deleted)
+	})
+
+	t.Run("gitops sync status", func(t *testing.T) {
+		store.Clear()
+
+		status, err := store.GitOpsSyncStatus(ctx)
+		require.NoError(t, err)
+		require.Nil(t, status)
+
+		for _, commit := range []string{"a1b2c3", "d4e5f6"} {
+			require.NoError(t, store.SetGitOpsSyncStatus(ctx, &model.GitOpsSyncStatus{Repository: "repo", Commit: commit}))
+			status, err = store.GitOpsSyncStatus(ctx)
+			require.NoError(t, err)
+			require.Equal(t, commit, status.Commit)
+		}
+	})
+}
@@ -16,10 +16,16 @@ package store
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/hashicorp/go-multierror"
+	"go.uber.org/zap"
+
+	"github.com/observiq/bindplane-op/internal/cluster"
 	"github.com/observiq/bindplane-op/internal/eventbus"
+	"github.com/observiq/bindplane-op/internal/store/search"
 	"github.com/observiq/bindplane-op/model"
 )
 
@@ -267,6 +273,10 @@ func (updates *Updates) addConfigurationUpdatesFromComponents(configuration *mod
 			updates.Configurations.Include(configuration, EventTypeUpdate)
 			return
 		}
+		if updates.includesProcessors(source.Processors) {
+			updates.Configurations.Include(configuration, EventTypeUpdate)
+			return
+		}
 	}
 	for _, destination := range configuration.Spec.Destinations {
 		if _, ok := updates.Destinations[destination.Name]; ok {
@@ -277,9 +287,26 @@ func (updates *Updates) addConfigurationUpdatesFromComponents(configuration *mod
 			updates.Configurations.Include(configuration, EventTypeUpdate)
 			return
 		}
+		if updates.includesProcessors(destination.Processors) {
+			updates.Configurations.Include(configuration, EventTypeUpdate)
+			return
+		}
 	}
 }
 
+// includesProcessors returns true if any of the processors or their types are included in the updates
+func (updates *Updates) includesProcessors(processors []model.ResourceConfiguration) bool {
+	for _, processor := range processors {
+		if _, ok := updates.Processors[processor.Name]; ok {
+			return true
+		}
+		if _, ok := updates.ProcessorTypes[processor.Type]; ok {
+			return true
+		}
+	}
+	return false
+}
+
 // ----------------------------------------------------------------------
 // merge for use with RelayWithMerge
 
@@ -315,17 +342,30 @@ func mergeUpdates(into, single *Updates) bool {
 
 // ----------------------------------------------------------------------
 
+// subjectStoreUpdates is the subject used to send Updates to every node with the cluster.Bus
+const subjectStoreUpdates = "store.updates"
+
 type storeUpdates struct {
 	updates eventbus.Source[*Updates]
 	// updatesInternal is an internal source used for notification. It will relay to the updates available to clients of
 	// the store.
 	updatesInternal eventbus.Source[*Updates]
+
+	// bus sends Updates to every node. If distributed is false, Updates are sent directly to updatesInternal.
+	bus         cluster.Bus
+	distributed bool
+
+	// indexes must be updated with agents and configurations from Updates received from other nodes
+	agentIndex         search.Index
+	configurationIndex search.Index
+	logger             *zap.Logger
 }
 
-func newStoreUpdates(ctx context.Context, maxEventsToMerge int) *storeUpdates {
+func newStoreUpdates(ctx context.Context, options Options, agentIndex, configurationIndex search.Index, logger *zap.Logger) *storeUpdates {
 	updates := eventbus.NewSource[*Updates]()
 	updatesInternal := eventbus.NewSource[*Updates]()
 
+	maxEventsToMerge := options.MaxEventsToMerge
 	if maxEventsToMerge == 0 {
 		maxEventsToMerge = 100
 	}
@@ -341,10 +381,28 @@ func newStoreUpdates(ctx context.Context, maxEventsToMerge int) *storeUpdates {
 		eventbus.WithUnboundedChannel[*Updates](100*time.Millisecond),
 	)
 
-	return &storeUpdates{
-		updates:         updates,
-		updatesInternal: updatesInternal,
+	s := &storeUpdates{
+		updates:            updates,
+		updatesInternal:    updatesInternal,
+		bus:                options.Bus,
+		distributed:        options.Bus != nil,
+		agentIndex:         agentIndex,
+		configurationIndex: configurationIndex,
+		logger:             logger,
 	}
+
+	if s.distributed {
+		unsubscribe := s.bus.Subscribe(subjectStoreUpdates, s.receive)
+		go func() {
+			<-ctx.Done()
+			unsubscribe()
+		}()
+	} else {
+		// the bus is still used by other components of this node
+		s.bus = cluster.NewLocalBus()
+	}
+
+	return s
 }
 
 // Updates returns the external channel that can be provided to external clients.
@@ -352,7 +410,60 @@ func (s *storeUpdates) Updates() eventbus.Source[*Updates] {
 	return s.updates
 }
 
-// Send adds an Updates event to the internal channel where it can be merged and relayed to the external channel.
+// Send adds an Updates event to the internal channel where it can be merged and relayed to the external channel. If the
+// store is shared by multiple nodes, the Updates are published to the bus and will return to this node with the
+// Updates from other nodes.
 func (s *storeUpdates) Send(updates *Updates) {
-	s.updatesInternal.Send(updates)
+	if !s.distributed {
+		s.updatesInternal.Send(updates)
+		return
+	}
+
+	data, err := json.Marshal(updates)
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("failed to marshal Updates message: %s", err))
+		return
+	}
+	if err := s.bus.Publish(context.TODO(), subjectStoreUpdates, data); err != nil {
+		// other nodes won't receive the updates but this node can still use them
+		s.logger.Error("failed to publish Updates message", zap.Error(err))
+		s.updatesInternal.Send(updates)
+	}
+}
+
+// receive receives Updates from this node or other nodes and forwards them to subscribers in this node
+func (s *storeUpdates) receive(ctx context.Context, data []byte) {
+	var updates Updates
+	if err := json.Unmarshal(data, &updates); err != nil {
+		s.logger.Warn(fmt.Sprintf("failed to unmarshal Updates message: %s", err))
+		return
+	}
+
+	// update indexes which must be in sync across servers
+	for _, event := range updates.Configurations {
+		updateIndex(s.configurationIndex, event, s.logger)
+	}
+	for _, event := range updates.Agents {
+		updateIndex(s.agentIndex, event, s.logger)
+	}
+
+	s.updatesInternal.Send(&updates)
+}
+
+type indexed interface {
+	model.HasUniqueKey
+	search.Indexed
+}
+
+func updateIndex[T indexed](index search.Index, event Event[T], logger *zap.Logger) {
+	var err error
+	switch event.Type {
+	case EventTypeRemove:
+		err = index.Remove(event.Item)
+	default:
+		err = index.Upsert(event.Item)
+	}
+	if err != nil {
+		logger.Error("failed to update the search index", zap.String("ID", event.Item.IndexID()), zap.Error(err))
+	}
 }
@@ -17,7 +17,10 @@ package store
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/observiq/bindplane-op/internal/cluster"
+	"github.com/observiq/bindplane-op/internal/eventbus"
 	"github.com/observiq/bindplane-op/model"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
@@ -42,6 +45,7 @@ func updatesTestSetup(t *testing.T) {
 		newTestProcessorType("pt2"),
 		newTestProcessorType("pt3"),
 		newTestProcessor("p1", "pt1"),
+		newTestProcessor("p2", "pt3"),
 		newTestSourceType("st1"),
 		newTestSourceType("st2"),
 		newTestSourceType("st3"),
@@ -65,6 +69,7 @@ func updatesTestSetup(t *testing.T) {
 		newTestConfiguration("c5", nil, nil, nil, nil),
 		newTestConfiguration("c6", []string{"s4"}, nil, []string{"d3"}, nil),
 		newTestConfiguration("c7", nil, []string{"st5"}, []string{"d3"}, nil),
+		newTestConfigurationWithProcessors("c8", "s3", []model.ResourceConfiguration{{Name: "p2"}}, "d3", []model.ResourceConfiguration{{Type: "pt3"}}),
 	}
 	for _, resource := range resources {
 		resourceMap[resource.Name()] = resource
@@ -129,6 +134,13 @@ func newTestConfiguration(name string, sources []string, sourceTypes []string, d
 	return c
 }
 
+func newTestConfigurationWithProcessors(name, source string, sourceProcessors []model.ResourceConfiguration, destination string, destinationProcessors []model.ResourceConfiguration) *model.Configuration {
+	c := newTestConfiguration(name, []string{source}, nil, []string{destination}, nil)
+	c.Spec.Sources[0].Processors = sourceProcessors
+	c.Spec.Destinations[0].Processors = destinationProcessors
+	return c
+}
+
 func addUpdates[T model.Resource](t *testing.T, names []string, events Events[T]) {
 	for _, name := range names {
 		resource, ok := resourceMap[name]
@@ -183,7 +195,7 @@ func TestTransitiveUpdates(t *testing.T) {
 			SourceTypes:          []string{"st1", "st2", "st3", "st4"},
 			ExpectSources:        []string{"s1", "s2", "s3"},
 			ExpectSourceTypes:    []string{"st1", "st2", "st3", "st4"},
-			ExpectConfigurations: []string{"c1", "c2", "c3", "c4"},
+			ExpectConfigurations: []string{"c1", "c2", "c3", "c4", "c8"},
 		},
 		{
 			Name:                   "dt2 destination type",
@@ -236,6 +248,19 @@ func TestTransitiveUpdates(t *testing.T) {
 			ExpectProcessorTypes: []string{"pt1"},
 			ExpectConfigurations: []string{"c6"},
 		},
+		{
+			Name:                 "p2 in configuration source",
+			Processors:           []string{"p2"},
+			ExpectProcessors:     []string{"p2"},
+			ExpectConfigurations: []string{"c8"},
+		},
+		{
+			Name:                 "pt3 in configuration destination",
+			ProcessorTypes:       []string{"pt3"},
+			ExpectProcessors:     []string{"p2"},
+			ExpectProcessorTypes: []string{"pt3"},
+			ExpectConfigurations: []string{"c8"},
+		},
 	}
 
 	for _, test := range tests {
@@ -266,3 +291,40 @@ func TestTransitiveUpdates(t *testing.T) {
 		})
 	}
 }
+
+func TestStoreUpdatesBus(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// two nodes with stores sharing a bus
+	options := testOptions
+	options.Bus = cluster.NewLocalBus()
+	nodeA := NewMapStore(ctx, options, zap.NewNop())
+	nodeB := NewMapStore(ctx, options, zap.NewNop())
+	require.Same(t, options.Bus, nodeB.ClusterBus())
+
+	updatesB, unsubscribe := eventbus.Subscribe(nodeB.Updates())
+	defer unsubscribe()
+
+	require.NoError(t, addAgent(nodeA, &model.Agent{ID: "a1", Labels: labels(map[string]string{"env": "test"})}))
+
+	select {
+	case updates := <-updatesB:
+		require.Contains(t, updates.Agents, "a1")
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "timed out waiting for updates from nodeA")
+	}
+
+	// the index of nodeB is updated with the agent from nodeA
+	require.Equal(t, []string{"a1"}, nodeB.AgentIndex().Select(map[string]string{"env": "test"}))
+}
+
+func TestStoreUpdatesLocal(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	nodeA := NewMapStore(ctx, testOptions, zap.NewNop())
+	nodeB := NewMapStore(ctx, testOptions, zap.NewNop())
+	require.NotNil(t, nodeA.ClusterBus())
+	require.NotSame(t, nodeA.ClusterBus(), nodeB.ClusterBus(), "stores without a bus are not shared")
+}
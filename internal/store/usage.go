@@ -0,0 +1,72 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"sort"
+
+	"github.com/observiq/bindplane-op/model"
+)
+
+// FindResourceUsage finds the resources that use the specified resource, directly or transitively, and the agents with
+// a configuration that uses it. The dependencies are the same ones used to send transitive updates when a resource is
+// modified: Type => Source, Processor, or Destination => Configuration => Agent.
+func FindResourceUsage(s Store, r model.Resource) (*model.ResourceUsage, error) {
+	updates := NewUpdates()
+	updates.IncludeResource(r, EventTypeUpdate)
+	if err := updates.addTransitiveUpdates(s); err != nil {
+		return nil, err
+	}
+
+	usage := &model.ResourceUsage{
+		Kind:           r.GetKind(),
+		Name:           r.Name(),
+		Sources:        usageNames(updates.Sources, r),
+		Processors:     usageNames(updates.Processors, r),
+		Destinations:   usageNames(updates.Destinations, r),
+		Configurations: usageNames(updates.Configurations, r),
+		Agents:         []string{},
+	}
+
+	agentIDs := map[string]bool{}
+	for _, event := range updates.Configurations {
+		ids, err := s.AgentsIDsMatchingConfiguration(event.Item)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			if !agentIDs[id] {
+				agentIDs[id] = true
+				usage.Agents = append(usage.Agents, id)
+			}
+		}
+	}
+	sort.Strings(usage.Agents)
+
+	return usage, nil
+}
+
+// usageNames returns the sorted names of the resources in the events, excluding the resource whose usage is being found
+func usageNames[T model.Resource](events Events[T], r model.Resource) []string {
+	names := []string{}
+	for _, event := range events {
+		if event.Item.GetKind() == r.GetKind() && event.Item.Name() == r.Name() {
+			continue
+		}
+		names = append(names, event.Item.Name())
+	}
+	sort.Strings(names)
+	return names
+}
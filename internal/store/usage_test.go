@@ -0,0 +1,149 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/observiq/bindplane-op/model"
+)
+
+func TestFindResourceUsage(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s := NewMapStore(ctx, testOptions, zap.NewNop())
+
+	// c8 uses pt3 and p1 in processors of inline sources and destinations
+	c8 := newTestConfiguration("c8", nil, []string{"st3"}, nil, []string{"dt3"})
+	c8.Spec.Sources[0].Processors = []model.ResourceConfiguration{{Type: "pt3"}}
+	c8.Spec.Destinations[0].Processors = []model.ResourceConfiguration{{Name: "p1"}}
+
+	resources := map[string]model.Resource{}
+	for _, resource := range []model.Resource{
+		newTestProcessorType("pt1"),
+		newTestProcessorType("pt3"),
+		newTestProcessor("p1", "pt1"),
+		newTestSourceType("st1"),
+		newTestSourceType("st2"),
+		newTestSourceType("st3"),
+		newTestSourceType("st5"),
+		newTestSource("s1", "st1"),
+		newTestSource("s2", "st2"),
+		newTestSourceWithProcessors("s4", "st5", []model.ResourceConfiguration{{Name: "p1"}}),
+		newTestDestinationType("dt1"),
+		newTestDestinationType("dt3"),
+		newTestDestination("d1", "dt1"),
+		newTestDestination("d3", "dt3"),
+		newTestConfiguration("c1", []string{"s1"}, []string{"st2"}, []string{"d1"}, nil),
+		newTestConfiguration("c2", []string{"s2"}, nil, nil, nil),
+		newTestConfiguration("c3", []string{"s1", "s2"}, nil, []string{"d1", "d3"}, nil),
+		newTestConfiguration("c5", nil, nil, nil, nil),
+		newTestConfiguration("c6", []string{"s4"}, nil, []string{"d3"}, nil),
+		newTestConfiguration("c7", nil, []string{"st5"}, []string{"d3"}, nil),
+		c8,
+	} {
+		// without a selector, a configuration would match all agents
+		if configuration, ok := resource.(*model.Configuration); ok {
+			configuration.Spec.Selector = model.AgentSelector{MatchLabels: map[string]string{"configuration": configuration.Name()}}
+		}
+		resources[resource.Name()] = resource
+		_, err := s.ApplyResources([]model.Resource{resource})
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, addAgent(s, &model.Agent{ID: "a2", Labels: labels(map[string]string{"configuration": "c8"})}))
+	require.NoError(t, addAgent(s, &model.Agent{ID: "a1", Labels: labels(map[string]string{"configuration": "c8"})}))
+	require.NoError(t, addAgent(s, &model.Agent{ID: "a3", Labels: labels(map[string]string{"configuration": "c1"})}))
+
+	tests := []struct {
+		name   string
+		expect model.ResourceUsage
+	}{
+		{
+			name: "d1",
+			expect: model.ResourceUsage{
+				Configurations: []string{"c1", "c3"},
+				Agents:         []string{"a3"},
+			},
+		},
+		{
+			name: "dt3",
+			expect: model.ResourceUsage{
+				Destinations:   []string{"d3"},
+				Configurations: []string{"c3", "c6", "c7", "c8"},
+				Agents:         []string{"a1", "a2"},
+			},
+		},
+		{
+			name: "st5",
+			expect: model.ResourceUsage{
+				Sources:        []string{"s4"},
+				Configurations: []string{"c6", "c7"},
+			},
+		},
+		{
+			name: "pt1",
+			expect: model.ResourceUsage{
+				Sources:        []string{"s4"},
+				Processors:     []string{"p1"},
+				Configurations: []string{"c6", "c8"},
+				Agents:         []string{"a1", "a2"},
+			},
+		},
+		{
+			name: "pt3",
+			expect: model.ResourceUsage{
+				Configurations: []string{"c8"},
+				Agents:         []string{"a1", "a2"},
+			},
+		},
+		{
+			name: "c8",
+			expect: model.ResourceUsage{
+				Agents: []string{"a1", "a2"},
+			},
+		},
+		{
+			name:   "st2",
+			expect: model.ResourceUsage{Sources: []string{"s2"}, Configurations: []string{"c1", "c2", "c3"}, Agents: []string{"a3"}},
+		},
+		{
+			name:   "c5",
+			expect: model.ResourceUsage{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resource := resources[test.name]
+			usage, err := FindResourceUsage(s, resource)
+			require.NoError(t, err)
+
+			expect := test.expect
+			expect.Kind = resource.GetKind()
+			expect.Name = resource.Name()
+			for _, names := range []*[]string{&expect.Sources, &expect.Processors, &expect.Destinations, &expect.Configurations, &expect.Agents} {
+				if *names == nil {
+					*names = []string{}
+				}
+			}
+			require.Equal(t, &expect, usage)
+		})
+	}
+}
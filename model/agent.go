@@ -58,6 +58,14 @@ const (
 	// Upgrading is set on an Agent when it has been sent a new package that is being applied. After Upgrading, it will
 	// transition back to Connected or Error unless it already has the Configuring status.
 	Upgrading AgentStatus = 7
+
+	// Pending is set on a new Agent when the server requires agents to be approved. A Pending agent does not receive
+	// configuration until it is approved.
+	Pending AgentStatus = 8
+
+	// Conflict is set on an Agent when another host connects with the same ID, e.g. a virtual machine cloned from an
+	// image that includes the agent configuration. The Conflict field describes the other host.
+	Conflict AgentStatus = 9
 )
 
 // AgentUpgradeStatus is the status of the AgentUpgrade
@@ -110,6 +118,9 @@ type Agent struct {
 	// Upgrade stores information about an agent upgrade
 	Upgrade *AgentUpgrade `json:"upgrade,omitempty" yaml:"upgrade,omitempty"`
 
+	// Conflict describes another host that connected with the ID of this agent
+	Conflict *AgentConflict `json:"conflict,omitempty" yaml:"conflict,omitempty"`
+
 	// reported by Status messages
 	Status       AgentStatus `json:"status"`
 	ErrorMessage string      `json:"errorMessage,omitempty" yaml:"errorMessage,omitempty"`
@@ -150,6 +161,10 @@ func (a *Agent) StatusDisplayText() string {
 		return "Configuring"
 	case Upgrading:
 		return "Upgrading"
+	case Pending:
+		return "Pending"
+	case Conflict:
+		return "Conflict"
 	default:
 		return "Unknown"
 	}
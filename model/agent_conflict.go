@@ -0,0 +1,75 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"net"
+	"strings"
+	"time"
+)
+
+// AgentConflict describes a host that connected with the ID of an agent that was already connected from a different
+// host. This happens when a virtual machine is cloned from an image that includes the agent configuration.
+type AgentConflict struct {
+	HostName      string `json:"hostname" yaml:"hostname"`
+	MacAddress    string `json:"macAddress" yaml:"macAddress"`
+	RemoteAddress string `json:"remoteAddress,omitempty" yaml:"remoteAddress,omitempty"`
+
+	// NodeID is the ID of the BindPlane node connected to the conflicting host
+	NodeID string `json:"nodeID,omitempty" yaml:"nodeID,omitempty"`
+
+	DetectedAt time.Time `json:"detectedAt" yaml:"detectedAt"`
+}
+
+// ConflictsWith returns true if the host name, MAC address, or remote address of the conflict differ from those of the
+// agent. Fields that are empty on either side are ignored.
+func (c *AgentConflict) ConflictsWith(agent *Agent) bool {
+	if c.HostName != "" && agent.HostName != "" && !strings.EqualFold(c.HostName, agent.HostName) {
+		return true
+	}
+	if c.MacAddress != "" && agent.MacAddress != "" && normalizeMacAddress(c.MacAddress) != normalizeMacAddress(agent.MacAddress) {
+		return true
+	}
+	if c.RemoteAddress != "" && agent.RemoteAddress != "" && remoteHost(c.RemoteAddress) != remoteHost(agent.RemoteAddress) {
+		return true
+	}
+	return false
+}
+
+// remoteHost removes the port from the remote address because a host reconnecting uses a different port
+func remoteHost(address string) string {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return address
+	}
+	return host
+}
+
+// SetConflict sets the Conflict field and the Conflict status of a connected agent
+func (a *Agent) SetConflict(conflict *AgentConflict) {
+	a.Conflict = conflict
+	if a.Status != Disconnected {
+		a.Status = Conflict
+	}
+}
+
+// ClearConflict removes the Conflict field and returns the agent to the Connected status if it has the Conflict
+// status. The status is updated when the agent next reports its status.
+func (a *Agent) ClearConflict() {
+	a.Conflict = nil
+	if a.Status == Conflict {
+		a.Status = Connected
+	}
+}
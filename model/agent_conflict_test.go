@@ -0,0 +1,99 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAgentConflictConflictsWith(t *testing.T) {
+	agent := &Agent{
+		ID:            "agent-1",
+		HostName:      "web-1",
+		MacAddress:    "0A-1B-2C-3D-4E-5F",
+		RemoteAddress: "10.0.0.1:51000",
+	}
+	tests := []struct {
+		name     string
+		conflict AgentConflict
+		expect   bool
+	}{
+		{
+			name: "same host reconnecting from another port",
+			conflict: AgentConflict{
+				HostName:      "WEB-1",
+				MacAddress:    "0a:1b:2c:3d:4e:5f",
+				RemoteAddress: "10.0.0.1:52000",
+			},
+			expect: false,
+		},
+		{
+			name: "different host name",
+			conflict: AgentConflict{
+				HostName:      "web-2",
+				MacAddress:    "0A-1B-2C-3D-4E-5F",
+				RemoteAddress: "10.0.0.1:52000",
+			},
+			expect: true,
+		},
+		{
+			name: "different mac address",
+			conflict: AgentConflict{
+				HostName:   "web-1",
+				MacAddress: "0A-1B-2C-3D-4E-60",
+			},
+			expect: true,
+		},
+		{
+			name: "different remote address",
+			conflict: AgentConflict{
+				HostName:      "web-1",
+				RemoteAddress: "10.0.0.2:51000",
+			},
+			expect: true,
+		},
+		{
+			name:     "empty fields are ignored",
+			conflict: AgentConflict{},
+			expect:   false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expect, test.conflict.ConflictsWith(agent))
+		})
+	}
+}
+
+func TestAgentSetConflict(t *testing.T) {
+	agent := &Agent{Status: Configuring}
+	conflict := &AgentConflict{HostName: "web-2"}
+
+	agent.SetConflict(conflict)
+	require.Equal(t, Conflict, agent.Status)
+	require.Equal(t, conflict, agent.Conflict)
+
+	agent.ClearConflict()
+	require.Equal(t, Connected, agent.Status)
+	require.Nil(t, agent.Conflict)
+
+	disconnected := &Agent{Status: Disconnected}
+	disconnected.SetConflict(conflict)
+	require.Equal(t, Disconnected, disconnected.Status)
+	disconnected.ClearConflict()
+	require.Equal(t, Disconnected, disconnected.Status)
+}
@@ -0,0 +1,135 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AgentDenyField is the agent field matched by an AgentDenyRule
+type AgentDenyField string
+
+const (
+	// AgentDenyFieldID matches the ID of the agent
+	AgentDenyFieldID AgentDenyField = "id"
+	// AgentDenyFieldHostName matches the host name of the agent
+	AgentDenyFieldHostName AgentDenyField = "hostname"
+	// AgentDenyFieldMacAddress matches the MAC address of the agent
+	AgentDenyFieldMacAddress AgentDenyField = "macAddress"
+)
+
+// AgentDenyRule permanently rejects agents with a matching ID, host name, or MAC address. Denied agents are
+// disconnected and cannot connect until the rule is removed.
+type AgentDenyRule struct {
+	Field  AgentDenyField `json:"field" yaml:"field"`
+	Value  string         `json:"value" yaml:"value"`
+	Reason string         `json:"reason,omitempty" yaml:"reason,omitempty"`
+
+	CreatedAt time.Time `json:"createdAt" yaml:"createdAt"`
+}
+
+var _ Printable = (*AgentDenyRule)(nil)
+
+// NewAgentDenyRule creates a new AgentDenyRule, returning an error if the field is not supported or the value is empty
+func NewAgentDenyRule(field AgentDenyField, value string, reason string) (*AgentDenyRule, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, fmt.Errorf("deny rule for %s must have a value", field)
+	}
+	switch field {
+	case AgentDenyFieldID, AgentDenyFieldHostName:
+	case AgentDenyFieldMacAddress:
+		value = normalizeMacAddress(value)
+	default:
+		return nil, fmt.Errorf("unknown deny rule field: %s", field)
+	}
+	return &AgentDenyRule{
+		Field:     field,
+		Value:     value,
+		Reason:    reason,
+		CreatedAt: time.Now().UTC(),
+	}, nil
+}
+
+// ID returns the unique ID of the rule, e.g. hostname:host-1
+func (r *AgentDenyRule) ID() string {
+	return string(r.Field) + ":" + r.Value
+}
+
+// Matches returns true if the agent matches the rule
+func (r *AgentDenyRule) Matches(agent *Agent) bool {
+	if agent == nil {
+		return false
+	}
+	switch r.Field {
+	case AgentDenyFieldID:
+		return agent.ID != "" && agent.ID == r.Value
+	case AgentDenyFieldHostName:
+		return agent.HostName != "" && strings.EqualFold(agent.HostName, r.Value)
+	case AgentDenyFieldMacAddress:
+		return agent.MacAddress != "" && normalizeMacAddress(agent.MacAddress) == r.Value
+	}
+	return false
+}
+
+// MatchingAgentDenyRule returns the first rule that matches the agent or nil if the agent is not denied
+func MatchingAgentDenyRule(rules []*AgentDenyRule, agent *Agent) *AgentDenyRule {
+	for _, rule := range rules {
+		if rule.Matches(agent) {
+			return rule
+		}
+	}
+	return nil
+}
+
+// normalizeMacAddress uses upper case hex digits separated by dashes so that addresses reported in different formats
+// match
+func normalizeMacAddress(mac string) string {
+	return strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(mac), ":", "-"))
+}
+
+// PrintableKindSingular returns the singular form of the Kind, e.g. "Configuration"
+func (r *AgentDenyRule) PrintableKindSingular() string {
+	return "AgentDenyRule"
+}
+
+// PrintableKindPlural returns the plural form of the Kind, e.g. "Configurations"
+func (r *AgentDenyRule) PrintableKindPlural() string {
+	return "AgentDenyRules"
+}
+
+// PrintableFieldTitles returns the list of field titles, used for printing a table of resources
+func (r *AgentDenyRule) PrintableFieldTitles() []string {
+	return []string{"ID", "Field", "Value", "Reason", "Created"}
+}
+
+// PrintableFieldValue returns the field value for a title, used for printing a table of resources
+func (r *AgentDenyRule) PrintableFieldValue(title string) string {
+	switch title {
+	case "ID":
+		return r.ID()
+	case "Field":
+		return string(r.Field)
+	case "Value":
+		return r.Value
+	case "Reason":
+		return r.Reason
+	case "Created":
+		return r.CreatedAt.Format(time.RFC3339)
+	}
+	return ""
+}
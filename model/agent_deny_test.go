@@ -0,0 +1,97 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAgentDenyRule(t *testing.T) {
+	tests := []struct {
+		name      string
+		field     AgentDenyField
+		value     string
+		expectID  string
+		expectErr string
+	}{
+		{
+			name:     "id",
+			field:    AgentDenyFieldID,
+			value:    "agent-1",
+			expectID: "id:agent-1",
+		},
+		{
+			name:     "mac address is normalized",
+			field:    AgentDenyFieldMacAddress,
+			value:    " 0a:1b:2c:3d:4e:5f ",
+			expectID: "macAddress:0A-1B-2C-3D-4E-5F",
+		},
+		{
+			name:      "empty value",
+			field:     AgentDenyFieldHostName,
+			value:     " ",
+			expectErr: "deny rule for hostname must have a value",
+		},
+		{
+			name:      "unknown field",
+			field:     "name",
+			value:     "agent",
+			expectErr: "unknown deny rule field: name",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rule, err := NewAgentDenyRule(test.field, test.value, "")
+			if test.expectErr != "" {
+				require.EqualError(t, err, test.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.expectID, rule.ID())
+		})
+	}
+}
+
+func TestAgentDenyRuleMatches(t *testing.T) {
+	agent := &Agent{ID: "agent-1", HostName: "Web-1", MacAddress: "0a:1b:2c:3d:4e:5f"}
+	tests := []struct {
+		name   string
+		field  AgentDenyField
+		value  string
+		expect bool
+	}{
+		{name: "id", field: AgentDenyFieldID, value: "agent-1", expect: true},
+		{name: "other id", field: AgentDenyFieldID, value: "agent-2", expect: false},
+		{name: "host name ignores case", field: AgentDenyFieldHostName, value: "web-1", expect: true},
+		{name: "mac address format", field: AgentDenyFieldMacAddress, value: "0A-1B-2C-3D-4E-5F", expect: true},
+		{name: "other mac address", field: AgentDenyFieldMacAddress, value: "0A-1B-2C-3D-4E-50", expect: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rule, err := NewAgentDenyRule(test.field, test.value, "")
+			require.NoError(t, err)
+			require.Equal(t, test.expect, rule.Matches(agent))
+		})
+	}
+
+	rule, err := NewAgentDenyRule(AgentDenyFieldHostName, "web-1", "")
+	require.NoError(t, err)
+	require.False(t, rule.Matches(nil))
+	require.False(t, rule.Matches(&Agent{ID: "agent-2"}))
+	require.Equal(t, rule, MatchingAgentDenyRule([]*AgentDenyRule{rule}, agent))
+	require.Nil(t, MatchingAgentDenyRule(nil, agent))
+}
@@ -155,23 +155,23 @@ func (vs *AgentVersionSpec) validate(name string, errs validation.Errors) {
 	if vs.validateHasTypeAndVersion(errs) {
 		// name must be equal to "Type-Version"
 		if name != fmt.Sprintf("%s-%s", vs.Type, vs.Version) {
-			errs.Add(fmt.Errorf("agent-version must have a name equal to [.spec.type]-[.spec.version] and name is %s but should be %s-%s", name, vs.Type, vs.Version))
+			errs.Add(validation.NewFieldError("metadata.name", fmt.Errorf("agent-version must have a name equal to [.spec.type]-[.spec.version] and name is %s but should be %s-%s", name, vs.Type, vs.Version)))
 		}
 	}
 	for platform, install := range vs.Installer {
-		install.validate(platform, errs)
+		install.validate(platform, validation.WithField(errs, fmt.Sprintf("spec.installer.%s", platform)))
 	}
 	for platform, download := range vs.Download {
-		download.validate(platform, errs)
+		download.validate(platform, validation.WithField(errs, fmt.Sprintf("spec.download.%s", platform)))
 	}
 }
 
 func (vs *AgentVersionSpec) validateHasTypeAndVersion(errs validation.Errors) bool {
 	if vs.Type == "" {
-		errs.Add(fmt.Errorf("agent-version must specify a type of agent as .spec.type"))
+		errs.Add(validation.NewFieldError("spec.type", fmt.Errorf("agent-version must specify a type of agent as .spec.type")))
 	}
 	if vs.Version == "" {
-		errs.Add(fmt.Errorf("agent-version must specify a version as .spec.version"))
+		errs.Add(validation.NewFieldError("spec.version", fmt.Errorf("agent-version must specify a version as .spec.version")))
 	}
 	return vs.Type != "" && vs.Version != ""
 }
@@ -122,6 +122,7 @@ func (c *Configuration) ValidateWithStore(store ResourceStore) (warnings string,
 
 	c.validate(errs)
 	c.Spec.validateSourcesAndDestinations(errs, store)
+	c.Spec.validateSourceConflicts(errs, store)
 
 	return errs.Warnings(), errs.Result()
 }
@@ -207,15 +208,13 @@ func (c *Configuration) evalComponents(store ResourceStore) (sources map[string]
 		}
 	}
 
-	sources = map[string]otel.Partials{}
-	destinations = map[string]otel.Partials{}
-
-	for i, source := range c.Spec.Sources {
-		source := source // copy to local variable to securely pass a reference to a loop variable
-		sourceName, srcParts := evalSource(&source, fmt.Sprintf("source%d", i), store, errorHandler)
-		sources[sourceName] = srcParts
+	sources, rendered := c.Spec.evalSources(store, errorHandler)
+	if conflicts := sourceConflicts(rendered); conflicts != nil {
+		errorHandler(conflicts)
 	}
 
+	destinations = map[string]otel.Partials{}
+
 	for i, destination := range c.Spec.Destinations {
 		destination := destination // copy to local variable to securely pass a reference to a loop variable
 		destName, destParts := evalDestination(&destination, fmt.Sprintf("destination%d", i), store, errorHandler)
@@ -225,6 +224,29 @@ func (c *Configuration) evalComponents(store ResourceStore) (sources map[string]
 	return sources, destinations, err
 }
 
+// evalSources evaluates each of the sources, returning the partials for each source name and the partials rendered for
+// each source in the order they appear in the configuration.
+func (cs *ConfigurationSpec) evalSources(store ResourceStore, errorHandler TemplateErrorHandler) (map[string]otel.Partials, []renderedSource) {
+	sources := map[string]otel.Partials{}
+	rendered := make([]renderedSource, 0, len(cs.Sources))
+
+	for i, source := range cs.Sources {
+		source := source // copy to local variable to securely pass a reference to a loop variable
+		sourceName, srcParts := evalSource(&source, fmt.Sprintf("source%d", i), store, errorHandler)
+		sources[sourceName] = srcParts
+		if srcParts == nil {
+			// the source could not be evaluated and the error was reported to the errorHandler
+			continue
+		}
+		rendered = append(rendered, renderedSource{
+			description: sourceDescription(i, source),
+			partials:    srcParts,
+		})
+	}
+
+	return sources, rendered
+}
+
 func evalSource(source *ResourceConfiguration, defaultName string, store ResourceStore, errorHandler TemplateErrorHandler) (string, otel.Partials) {
 	src, srcType, err := findSourceAndType(source, defaultName, store)
 	if err != nil {
@@ -347,8 +369,8 @@ func findResourceAndType(resourceKind Kind, resource *ResourceConfiguration, def
 
 func (cs *ConfigurationSpec) validate(errors validation.Errors) {
 	cs.validateSpecFields(errors)
-	cs.validateRaw(errors)
-	cs.Selector.validate(errors)
+	cs.validateRaw(validation.WithField(errors, "spec.raw"))
+	cs.Selector.validate(validation.WithField(errors, "spec.selector"))
 }
 
 func (cs *ConfigurationSpec) validateSpecFields(errors validation.Errors) {
@@ -371,11 +393,11 @@ func (cs *ConfigurationSpec) validateRaw(errors validation.Errors) {
 }
 
 func (cs *ConfigurationSpec) validateSourcesAndDestinations(errors validation.Errors, store ResourceStore) {
-	for _, source := range cs.Sources {
-		source.validate(KindSource, errors, store)
+	for i, source := range cs.Sources {
+		source.validate(KindSource, validation.WithField(errors, fmt.Sprintf("spec.sources[%d]", i)), store)
 	}
-	for _, destination := range cs.Destinations {
-		destination.validate(KindDestination, errors, store)
+	for i, destination := range cs.Destinations {
+		destination.validate(KindDestination, validation.WithField(errors, fmt.Sprintf("spec.destinations[%d]", i)), store)
 	}
 }
 
@@ -397,14 +419,18 @@ func (rc *ResourceConfiguration) validateHasNameOrType(resourceKind Kind, errors
 
 func (rc *ResourceConfiguration) validateParameters(resourceKind Kind, errors validation.Errors, store ResourceStore) {
 	// must have a name
-	for _, parameter := range rc.Parameters {
+	for i, parameter := range rc.Parameters {
 		if parameter.Name == "" {
-			errors.Add(fmt.Errorf("all %s parameters must have a name", resourceKind))
+			errors.Add(validation.NewFieldError(fmt.Sprintf("parameters[%d].name", i), fmt.Errorf("all %s parameters must have a name", resourceKind)))
 		}
 	}
-	_, resourceType, err := findResourceAndType(resourceKind, rc, string(resourceKind), store)
+	resource, resourceType, err := findResourceAndType(resourceKind, rc, string(resourceKind), store)
 	if err != nil {
-		errors.Add(err)
+		field := "type"
+		if rc.Name != "" {
+			field = "name"
+		}
+		errors.Add(validation.NewFieldError(field, err))
 		return
 	}
 	// ensure parameters are valid
@@ -412,21 +438,25 @@ func (rc *ResourceConfiguration) validateParameters(resourceKind Kind, errors va
 		if parameter.Name == "" {
 			continue
 		}
+		field := fmt.Sprintf("parameters.%s", parameter.Name)
 		def := resourceType.Spec.ParameterDefinition(parameter.Name)
 		if def == nil {
-			errors.Add(fmt.Errorf("parameter %s not defined in type %s", parameter.Name, resourceType.Name()))
+			errors.Add(validation.NewFieldError(field, fmt.Errorf("parameter %s not defined in type %s", parameter.Name, resourceType.Name())))
 			continue
 		}
-		err := def.validateValue(parameter.Value)
-		if err != nil {
-			errors.Add(err)
-		}
+		errors.Add(validation.NewFieldError(field, def.validateValue(parameter.Value)))
 	}
+	// ensure the combination of parameters is valid, including parameters of named resources
+	parameters := rc.Parameters
+	if pr, ok := resource.(parameterizedResource); ok {
+		parameters = pr.ResourceParameters()
+	}
+	resourceType.Spec.evaluateRules(parameters, validation.WithField(errors, "parameters"))
 }
 
 func (rc *ResourceConfiguration) validateProcessors(resourceKind Kind, errors validation.Errors, store ResourceStore) {
-	for _, processor := range rc.Processors {
-		processor.validate(KindProcessor, errors, store)
+	for i, processor := range rc.Processors {
+		processor.validate(KindProcessor, validation.WithField(errors, fmt.Sprintf("processors[%d]", i)), store)
 	}
 }
 
@@ -486,7 +516,8 @@ func (rc *ResourceConfiguration) indexFields(resourceName string, resourceTypeNa
 
 // Duplicate copies the value of the current configuration and returns
 // a duplicate with the new name.  It should be identical except for the
-// Metadata.Name, Metadata.ID, and Spec.Selector fields.
+// Metadata.Name, Metadata.ID, and Spec.Selector fields. The selector of the
+// current configuration is not modified.
 func (c *Configuration) Duplicate(name string) *Configuration {
 	copy := *c
 
@@ -494,8 +525,12 @@ func (c *Configuration) Duplicate(name string) *Configuration {
 	copy.Metadata.Name = name
 	copy.Metadata.ID = uuid.NewString()
 
-	// replace the configuration matchLabel
-	matchLabels := copy.Spec.Selector.MatchLabels
+	// replace the configuration matchLabel without modifying the labels of the original
+	matchLabels := MatchLabels{}
+	for k, v := range c.Spec.Selector.MatchLabels {
+		matchLabels[k] = v
+	}
 	matchLabels["configuration"] = name
+	copy.Spec.Selector.MatchLabels = matchLabels
 	return &copy
 }
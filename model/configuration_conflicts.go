@@ -0,0 +1,251 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/observiq/bindplane-op/model/otel"
+	"github.com/observiq/bindplane-op/model/validation"
+)
+
+// listenAddressKeys are the receiver configuration keys that contain the address on which a receiver listens
+var listenAddressKeys = map[string]bool{
+	"listen_address": true,
+	"listen_addr":    true,
+}
+
+// endpointListenerTypes are the receiver types that listen on their endpoint. Other receivers, e.g. mysql, use the
+// endpoint to connect to the service they scrape.
+var endpointListenerTypes = map[string]bool{
+	"carbon":        true,
+	"collectd":      true,
+	"fluentforward": true,
+	"influxdb":      true,
+	"jaeger":        true,
+	"opencensus":    true,
+	"otlp":          true,
+	"sapm":          true,
+	"signalfx":      true,
+	"skywalking":    true,
+	"splunk_hec":    true,
+	"statsd":        true,
+	"zipkin":        true,
+}
+
+// renderedSource contains the components rendered for a single source in a configuration
+type renderedSource struct {
+	// description identifies the source in error messages
+	description string
+	partials    otel.Partials
+}
+
+// listenAddress is an address on which a receiver will listen
+type listenAddress struct {
+	protocol string
+	host     string
+	port     int
+}
+
+// conflicts returns true if both addresses can't be bound at the same time
+func (a listenAddress) conflicts(o listenAddress) bool {
+	if a.protocol != o.protocol || a.port != o.port {
+		return false
+	}
+	return a.host == o.host || isWildcardHost(a.host) || isWildcardHost(o.host)
+}
+
+func isWildcardHost(host string) bool {
+	switch host {
+	case "", "0.0.0.0", "::":
+		return true
+	}
+	return false
+}
+
+// sourceDescription describes the source at the specified index of the configuration
+func sourceDescription(index int, source ResourceConfiguration) string {
+	if source.Name != "" {
+		return source.Name
+	}
+	return fmt.Sprintf("source%d (%s)", index, source.Type)
+}
+
+// validateSourceConflicts renders the sources and reports conflicting listen addresses and component IDs. Errors
+// rendering the sources are reported by validateSourcesAndDestinations and are ignored here.
+func (cs *ConfigurationSpec) validateSourceConflicts(errors validation.Errors, store ResourceStore) {
+	_, rendered := cs.evalSources(store, func(error) {})
+	errors.Add(sourceConflicts(rendered))
+}
+
+// sourceConflicts returns an error describing each pair of sources that listen on the same address or render a
+// component with the same ComponentID but different configuration.
+func sourceConflicts(rendered []renderedSource) error {
+	var errs error
+
+	type componentOwner struct {
+		source    string
+		component any
+	}
+	type addressOwner struct {
+		source  string
+		address listenAddress
+	}
+	components := map[otel.ComponentID]componentOwner{}
+	var addresses []addressOwner
+	reported := map[string]bool{}
+
+	for _, source := range rendered {
+		for _, component := range sourceComponents(source.partials) {
+			for id, value := range component {
+				if owner, ok := components[id]; ok {
+					if owner.source != source.description && !reflect.DeepEqual(owner.component, value) {
+						msg := fmt.Sprintf("sources %s and %s both render component %s with different configuration", owner.source, source.description, id)
+						if !reported[msg] {
+							reported[msg] = true
+							errs = multierror.Append(errs, fmt.Errorf("%s, rename one of the sources", msg))
+						}
+					}
+					continue
+				}
+				components[id] = componentOwner{source: source.description, component: value}
+			}
+		}
+
+		// addresses are compared across sources, a source with multiple receivers on the same address is left to the
+		// ResourceType
+		var sourceAddresses []listenAddress
+		for _, receiver := range sourceReceivers(source.partials) {
+			for id, value := range receiver {
+				receiverType, _ := otel.ParseComponentID(id)
+				sourceAddresses = append(sourceAddresses, listenAddresses(value, "tcp", endpointListenerTypes[receiverType])...)
+			}
+		}
+		for _, address := range sourceAddresses {
+			for _, owner := range addresses {
+				if owner.source == source.description || !owner.address.conflicts(address) {
+					continue
+				}
+				msg := fmt.Sprintf("sources %s and %s both listen on %s port %d", owner.source, source.description, address.protocol, address.port)
+				if !reported[msg] {
+					reported[msg] = true
+					errs = multierror.Append(errs, fmt.Errorf("%s, change the port of one of the sources", msg))
+				}
+			}
+		}
+		for _, address := range sourceAddresses {
+			addresses = append(addresses, addressOwner{source: source.description, address: address})
+		}
+	}
+
+	return errs
+}
+
+// sourceComponents returns the receivers and processors rendered for all telemetry types. Extensions are excluded
+// because they are shared by all pipelines.
+func sourceComponents(partials otel.Partials) otel.ComponentList {
+	var result otel.ComponentList
+	for _, pipelineType := range []otel.PipelineType{otel.Logs, otel.Metrics, otel.Traces} {
+		if partial, ok := partials[pipelineType]; ok && partial != nil {
+			result = append(result, partial.Receivers...)
+			result = append(result, partial.Processors...)
+		}
+	}
+	return result
+}
+
+// sourceReceivers returns the unique receivers rendered for all telemetry types. A receiver used for multiple
+// telemetry types is only included once.
+func sourceReceivers(partials otel.Partials) otel.ComponentList {
+	var result otel.ComponentList
+	seen := map[otel.ComponentID]bool{}
+	for _, pipelineType := range []otel.PipelineType{otel.Logs, otel.Metrics, otel.Traces} {
+		partial, ok := partials[pipelineType]
+		if !ok || partial == nil {
+			continue
+		}
+		for _, receiver := range partial.Receivers {
+			for id, value := range receiver {
+				if seen[id] {
+					continue
+				}
+				seen[id] = true
+				result = append(result, map[otel.ComponentID]any{id: value})
+			}
+		}
+	}
+	return result
+}
+
+// listenAddresses finds the addresses in the receiver configuration. The protocol is udp if the address is nested in
+// a udp block and tcp otherwise. If endpointListens is true, endpoint values are also listen addresses.
+func listenAddresses(config any, protocol string, endpointListens bool) []listenAddress {
+	var result []listenAddress
+	visit := func(key string, value any) {
+		if key == "udp" {
+			result = append(result, listenAddresses(value, "udp", endpointListens)...)
+			return
+		}
+		if listenAddressKeys[key] || (endpointListens && key == "endpoint") {
+			if address, ok := parseListenAddress(value, protocol); ok {
+				result = append(result, address)
+			}
+			return
+		}
+		result = append(result, listenAddresses(value, protocol, endpointListens)...)
+	}
+
+	switch c := config.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(c))
+		for key := range c {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			visit(key, c[key])
+		}
+	case map[any]any:
+		for key, value := range c {
+			visit(fmt.Sprintf("%v", key), value)
+		}
+	case []any:
+		for _, value := range c {
+			result = append(result, listenAddresses(value, protocol, endpointListens)...)
+		}
+	}
+	return result
+}
+
+func parseListenAddress(value any, protocol string) (listenAddress, bool) {
+	str, ok := value.(string)
+	if !ok {
+		return listenAddress{}, false
+	}
+	host, portStr, err := net.SplitHostPort(str)
+	if err != nil {
+		return listenAddress{}, false
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil || port == 0 {
+		return listenAddress{}, false
+	}
+	return listenAddress{protocol: protocol, host: host, port: port}, true
+}
@@ -528,15 +528,124 @@ func TestEvalConfigurationFailsMissingResource(t *testing.T) {
 	}
 }
 
+func TestConfigurationSourceConflicts(t *testing.T) {
+	store := newTestResourceStore()
+
+	syslogType := testResource[*SourceType](t, "sourcetype-syslog.yaml")
+	store.sourceTypes[syslogType.Name()] = syslogType
+
+	otlpType := testResource[*SourceType](t, "sourcetype-otlp-endpoint.yaml")
+	store.sourceTypes[otlpType.Name()] = otlpType
+
+	otlpDestinationType := testResource[*DestinationType](t, "destinationtype-otlp.yaml")
+	store.destinationTypes[otlpDestinationType.Name()] = otlpDestinationType
+
+	// named source with a default name used by inline sources
+	store.sources["source1"] = NewSource("source1", "syslog", []Parameter{{Name: "listen_port", Value: 6000}})
+
+	tests := []struct {
+		name        string
+		sources     []ResourceConfiguration
+		expectError []string
+	}{
+		{
+			name: "different ports",
+			sources: []ResourceConfiguration{
+				{Type: "syslog"},
+				{Type: "syslog", Parameters: []Parameter{{Name: "listen_port", Value: 5141}}},
+			},
+		},
+		{
+			name: "same port with different protocols",
+			sources: []ResourceConfiguration{
+				{Type: "syslog"},
+				{Type: "syslog", Parameters: []Parameter{{Name: "protocol", Value: "udp"}}},
+			},
+		},
+		{
+			name: "same port with different hosts",
+			sources: []ResourceConfiguration{
+				{Type: "syslog", Parameters: []Parameter{{Name: "listen_ip", Value: "127.0.0.1"}}},
+				{Type: "syslog", Parameters: []Parameter{{Name: "listen_ip", Value: "10.0.0.1"}}},
+			},
+		},
+		{
+			name: "same port",
+			sources: []ResourceConfiguration{
+				{Type: "syslog"},
+				{Type: "syslog", Parameters: []Parameter{{Name: "listen_ip", Value: "127.0.0.1"}}},
+			},
+			expectError: []string{
+				"sources source0 (syslog) and source1 (syslog) both listen on tcp port 5140, change the port of one of the sources",
+			},
+		},
+		{
+			name: "same port with different source types",
+			sources: []ResourceConfiguration{
+				{Type: "otlp-endpoint"},
+				{Type: "syslog", Parameters: []Parameter{{Name: "listen_port", Value: 4317}}},
+			},
+			expectError: []string{
+				"sources source0 (otlp-endpoint) and source1 (syslog) both listen on tcp port 4317, change the port of one of the sources",
+			},
+		},
+		{
+			name: "same component id",
+			sources: []ResourceConfiguration{
+				{Name: "source1"},
+				{Type: "syslog", Parameters: []Parameter{{Name: "listen_port", Value: 7000}}},
+			},
+			expectError: []string{
+				"sources source1 and source1 (syslog) both render component syslog/syslog__source1 with different configuration, rename one of the sources",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			configuration := NewConfigurationWithSpec("conflicts", ConfigurationSpec{
+				Sources:      test.sources,
+				Destinations: []ResourceConfiguration{{Type: "otlp"}},
+			})
+
+			_, validateErr := configuration.ValidateWithStore(store)
+			_, renderErr := configuration.Render(context.TODO(), store)
+			if len(test.expectError) == 0 {
+				require.NoError(t, validateErr)
+				require.NoError(t, renderErr)
+				return
+			}
+			require.Error(t, validateErr)
+			require.Error(t, renderErr)
+			for _, expect := range test.expectError {
+				require.Contains(t, validateErr.Error(), expect)
+				require.Contains(t, renderErr.Error(), expect)
+			}
+		})
+	}
+}
+
 func TestDuplicate(t *testing.T) {
 	duplicateName := "duplicate-config"
 
 	configuration := testResource[*Configuration](t, "configuration-macos-googlecloud.yaml")
 	require.NotNil(t, configuration)
+	originalMatchLabels := MatchLabels{}
+	for k, v := range configuration.Spec.Selector.MatchLabels {
+		originalMatchLabels[k] = v
+	}
 
 	new := configuration.Duplicate(duplicateName)
 	require.NotNil(t, new)
 
+	t.Run("original match labels are not modified", func(t *testing.T) {
+		require.Equal(t, originalMatchLabels, configuration.Spec.Selector.MatchLabels)
+
+		new.Spec.Selector.MatchLabels["env"] = "test"
+		defer delete(new.Spec.Selector.MatchLabels, "env")
+		require.NotContains(t, configuration.Spec.Selector.MatchLabels, "env")
+	})
+
 	t.Run("equal sources, destinations", func(t *testing.T) {
 		require.Equal(t, configuration.Spec.Sources, new.Spec.Sources)
 		require.Equal(t, configuration.Spec.Destinations, new.Spec.Destinations)
@@ -552,5 +661,6 @@ func TestDuplicate(t *testing.T) {
 		// Set the configuration matchLabel
 		require.Contains(t, new.Spec.Selector.MatchLabels, "configuration")
 		require.Equal(t, new.Spec.Selector.MatchLabels["configuration"], duplicateName)
+		require.NotEqual(t, configuration.Spec.Selector.MatchLabels["configuration"], duplicateName)
 	})
 }
@@ -0,0 +1,321 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrEnrollmentTokenExpired is returned when an agent enrolls with an expired EnrollmentToken
+	ErrEnrollmentTokenExpired = errors.New("enrollment token expired")
+
+	// ErrEnrollmentTokenUsed is returned when an agent enrolls with an EnrollmentToken that has no uses remaining
+	ErrEnrollmentTokenUsed = errors.New("enrollment token has no uses remaining")
+)
+
+// enrollmentTokenSeparator separates the ID of the EnrollmentToken from the secret in the token value
+const enrollmentTokenSeparator = "."
+
+// EnrollmentToken is used by agents to enroll with BindPlane instead of the server secret key. Tokens can be limited to
+// a number of uses, can expire, and apply their labels to every agent enrolled with them. Only a hash of the secret is
+// stored and the token value is only available when the token is created.
+type EnrollmentToken struct {
+	ID     string `json:"id" yaml:"id"`
+	Labels Labels `json:"labels,omitempty" yaml:"labels,omitempty"`
+
+	// MaxUses is the maximum number of agents that can enroll with the token. 0 is unlimited.
+	MaxUses int `json:"maxUses,omitempty" yaml:"maxUses,omitempty"`
+	// Uses is the number of agents that have enrolled with the token
+	Uses int `json:"uses" yaml:"uses"`
+
+	CreatedAt time.Time  `json:"createdAt" yaml:"createdAt"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty" yaml:"expiresAt,omitempty"`
+
+	// Hash is the hex-encoded SHA-256 hash of the token secret
+	Hash string `json:"hash,omitempty" yaml:"hash,omitempty"`
+}
+
+var _ Printable = (*EnrollmentToken)(nil)
+
+// NewEnrollmentToken creates a new EnrollmentToken and returns it with the token value to give to agents. If ttl is 0,
+// the token does not expire.
+func NewEnrollmentToken(labels Labels, maxUses int, ttl time.Duration) (*EnrollmentToken, string, error) {
+	secret, hash, err := newSecret()
+	if err != nil {
+		return nil, "", err
+	}
+	now := time.Now().UTC()
+	token := &EnrollmentToken{
+		ID:        uuid.NewString(),
+		Labels:    labels,
+		MaxUses:   maxUses,
+		CreatedAt: now,
+		Hash:      hash,
+	}
+	if ttl > 0 {
+		expiresAt := now.Add(ttl)
+		token.ExpiresAt = &expiresAt
+	}
+	return token, token.ID + enrollmentTokenSeparator + secret, nil
+}
+
+// ParseEnrollmentToken splits the token value into the ID of the EnrollmentToken and the secret. It returns false if
+// the value is not an enrollment token, e.g. if it is the server secret key.
+func ParseEnrollmentToken(value string) (id string, secret string, ok bool) {
+	id, secret, ok = strings.Cut(value, enrollmentTokenSeparator)
+	if !ok || id == "" || secret == "" {
+		return "", "", false
+	}
+	return id, secret, true
+}
+
+// Verify returns true if the secret matches the secret of the EnrollmentToken
+func (t *EnrollmentToken) Verify(secret string) bool {
+	return verifySecret(secret, t.Hash)
+}
+
+// Valid returns an error if the token has expired or has no uses remaining
+func (t *EnrollmentToken) Valid(now time.Time) error {
+	if t.Expired(now) {
+		return ErrEnrollmentTokenExpired
+	}
+	if t.MaxUses > 0 && t.Uses >= t.MaxUses {
+		return ErrEnrollmentTokenUsed
+	}
+	return nil
+}
+
+// Expired returns true if the token has expired
+func (t *EnrollmentToken) Expired(now time.Time) bool {
+	return t.ExpiresAt != nil && !now.Before(*t.ExpiresAt)
+}
+
+// Redacted returns a copy of the token without the Hash, suitable for API responses
+func (t *EnrollmentToken) Redacted() *EnrollmentToken {
+	redacted := *t
+	redacted.Hash = ""
+	return &redacted
+}
+
+// PrintableKindSingular returns the singular form of the Kind, e.g. "Configuration"
+func (t *EnrollmentToken) PrintableKindSingular() string {
+	return "EnrollmentToken"
+}
+
+// PrintableKindPlural returns the plural form of the Kind, e.g. "Configurations"
+func (t *EnrollmentToken) PrintableKindPlural() string {
+	return "EnrollmentTokens"
+}
+
+// PrintableFieldTitles returns the list of field titles, used for printing a table of resources
+func (t *EnrollmentToken) PrintableFieldTitles() []string {
+	return []string{"ID", "Uses", "Expires", "Labels"}
+}
+
+// PrintableFieldValue returns the field value for a title, used for printing a table of resources
+func (t *EnrollmentToken) PrintableFieldValue(title string) string {
+	switch title {
+	case "ID":
+		return t.ID
+	case "Uses":
+		if t.MaxUses > 0 {
+			return fmt.Sprintf("%d/%d", t.Uses, t.MaxUses)
+		}
+		return strconv.Itoa(t.Uses)
+	case "Expires":
+		if t.ExpiresAt == nil {
+			return "never"
+		}
+		return t.ExpiresAt.Format(time.RFC3339)
+	case "Labels":
+		return t.Labels.String()
+	}
+	return ""
+}
+
+// ----------------------------------------------------------------------
+
+// AgentCredential is the credential issued to an agent when it first connects. The agent uses it to authenticate
+// instead of the enrollment token or server secret key so that those can expire or be rotated without reinstalling
+// the agent. Only hashes of the credential secrets are stored. It also records whether the agent has been approved
+// when the server requires agents to be approved.
+type AgentCredential struct {
+	AgentID string `json:"agentID" yaml:"agentID"`
+
+	// TokenID is the ID of the EnrollmentToken used to enroll the agent, if any
+	TokenID string `json:"tokenID,omitempty" yaml:"tokenID,omitempty"`
+	// Labels are the labels of the EnrollmentToken used to enroll the agent. They are always applied to the agent.
+	Labels Labels `json:"labels,omitempty" yaml:"labels,omitempty"`
+
+	// Hash is the hex-encoded SHA-256 hash of the current credential secret
+	Hash string `json:"hash,omitempty" yaml:"hash,omitempty"`
+	// PreviousHash is the hash of the previous credential secret which is accepted until the agent authenticates with
+	// the current credential secret
+	PreviousHash string `json:"previousHash,omitempty" yaml:"previousHash,omitempty"`
+
+	IssuedAt *time.Time `json:"issuedAt,omitempty" yaml:"issuedAt,omitempty"`
+
+	// CertificateSerial is the hex serial number of the current client certificate issued by the agent CA
+	CertificateSerial string `json:"certificateSerial,omitempty" yaml:"certificateSerial,omitempty"`
+	// PreviousCertificateSerial is the serial number of the previous client certificate which is accepted until the
+	// agent connects with the current client certificate
+	PreviousCertificateSerial string `json:"previousCertificateSerial,omitempty" yaml:"previousCertificateSerial,omitempty"`
+	// CertificateIssuedAt is the time the current client certificate was issued
+	CertificateIssuedAt *time.Time `json:"certificateIssuedAt,omitempty" yaml:"certificateIssuedAt,omitempty"`
+	// CertificateExpiresAt is the time the current client certificate expires
+	CertificateExpiresAt *time.Time `json:"certificateExpiresAt,omitempty" yaml:"certificateExpiresAt,omitempty"`
+	// CertificateRequired is true if a client certificate was ever issued to the agent. It is kept when the certificate
+	// is revoked so that the agent can't connect without a certificate.
+	CertificateRequired bool `json:"certificateRequired,omitempty" yaml:"certificateRequired,omitempty"`
+
+	// Pending is true if the agent connected while approval was required and has not been approved
+	Pending bool `json:"pending,omitempty" yaml:"pending,omitempty"`
+	// ApprovedAt is the time the agent was approved, if it was approved
+	ApprovedAt *time.Time `json:"approvedAt,omitempty" yaml:"approvedAt,omitempty"`
+}
+
+// Approve clears the pending state of the agent and records the time it was approved
+func (c *AgentCredential) Approve() {
+	now := time.Now().UTC()
+	c.Pending = false
+	c.ApprovedAt = &now
+}
+
+// Approved returns true if the agent has been approved
+func (c *AgentCredential) Approved() bool {
+	return c.ApprovedAt != nil
+}
+
+// Issue creates a new credential secret for the agent and returns it. The current secret remains valid until the agent
+// authenticates with the new secret.
+func (c *AgentCredential) Issue() (string, error) {
+	secret, hash, err := newSecret()
+	if err != nil {
+		return "", err
+	}
+	now := time.Now().UTC()
+	if c.Hash != "" && c.PreviousHash == "" {
+		// keep the secret the agent is using. if a previous secret is already pending, the agent never used the current
+		// secret so it is replaced.
+		c.PreviousHash = c.Hash
+	}
+	c.Hash = hash
+	c.IssuedAt = &now
+	return secret, nil
+}
+
+// Issued returns true if a credential secret has been issued to the agent
+func (c *AgentCredential) Issued() bool {
+	return c.Hash != ""
+}
+
+// Verify returns true if the secret matches the current or previous credential secret. current is true if the secret
+// matches the current credential secret.
+func (c *AgentCredential) Verify(secret string) (ok bool, current bool) {
+	if verifySecret(secret, c.Hash) {
+		return true, true
+	}
+	return verifySecret(secret, c.PreviousHash), false
+}
+
+// IssueCertificate records a new client certificate issued to the agent. The current certificate remains valid until
+// the agent connects with the new certificate.
+func (c *AgentCredential) IssueCertificate(serial string, expiresAt time.Time) {
+	now := time.Now().UTC()
+	if c.CertificateSerial != "" && c.PreviousCertificateSerial == "" {
+		// same as Issue, keep the certificate the agent is using
+		c.PreviousCertificateSerial = c.CertificateSerial
+	}
+	expiresAt = expiresAt.UTC()
+	c.CertificateSerial = serial
+	c.CertificateIssuedAt = &now
+	c.CertificateExpiresAt = &expiresAt
+	c.CertificateRequired = true
+}
+
+// CertificateIssued returns true if a client certificate has been issued to the agent and has not been revoked
+func (c *AgentCredential) CertificateIssued() bool {
+	return c.CertificateSerial != ""
+}
+
+// VerifyCertificate returns true if the serial matches the current or previous client certificate. current is true if
+// the serial matches the current client certificate.
+func (c *AgentCredential) VerifyCertificate(serial string) (ok bool, current bool) {
+	if serial == "" {
+		return false, false
+	}
+	if serial == c.CertificateSerial {
+		return true, true
+	}
+	return serial == c.PreviousCertificateSerial, false
+}
+
+// CertificateRevoked returns true if the client certificate issued to the agent was revoked and no new certificate has
+// been issued
+func (c *AgentCredential) CertificateRevoked() bool {
+	return c.CertificateRequired && !c.CertificateIssued()
+}
+
+// RevokeCertificate revokes the current and previous client certificates. The agent still requires a certificate.
+func (c *AgentCredential) RevokeCertificate() {
+	c.CertificateSerial = ""
+	c.PreviousCertificateSerial = ""
+	c.CertificateIssuedAt = nil
+	c.CertificateExpiresAt = nil
+}
+
+// CertificateRenewalDue returns true if two thirds of the lifetime of the current client certificate has passed
+func (c *AgentCredential) CertificateRenewalDue(now time.Time) bool {
+	if c.CertificateIssuedAt == nil || c.CertificateExpiresAt == nil {
+		return false
+	}
+	lifetime := c.CertificateExpiresAt.Sub(*c.CertificateIssuedAt)
+	return !now.Before(c.CertificateIssuedAt.Add(lifetime * 2 / 3))
+}
+
+// ----------------------------------------------------------------------
+
+// newSecret returns a new random secret and its hash
+func newSecret() (secret string, hash string, err error) {
+	data := make([]byte, 32)
+	if _, err := rand.Read(data); err != nil {
+		return "", "", fmt.Errorf("generate secret: %w", err)
+	}
+	secret = hex.EncodeToString(data)
+	return secret, hashSecret(secret), nil
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func verifySecret(secret, hash string) bool {
+	if secret == "" || hash == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(hashSecret(secret)), []byte(hash)) == 1
+}
@@ -0,0 +1,163 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnrollmentToken(t *testing.T) {
+	labels, err := LabelsFromSelector("env=prod")
+	require.NoError(t, err)
+
+	token, value, err := NewEnrollmentToken(labels, 2, time.Hour)
+	require.NoError(t, err)
+	require.NotNil(t, token.ExpiresAt)
+	require.NotContains(t, token.Hash, value, "only the hash is stored")
+
+	id, secret, ok := ParseEnrollmentToken(value)
+	require.True(t, ok)
+	require.Equal(t, token.ID, id)
+	require.True(t, token.Verify(secret))
+	require.False(t, token.Verify("other"))
+	require.False(t, token.Verify(""))
+
+	now := time.Now()
+	require.NoError(t, token.Valid(now))
+	require.ErrorIs(t, token.Valid(now.Add(2*time.Hour)), ErrEnrollmentTokenExpired)
+
+	token.Uses = 2
+	require.ErrorIs(t, token.Valid(now), ErrEnrollmentTokenUsed)
+
+	require.Empty(t, token.Redacted().Hash)
+	require.NotEmpty(t, token.Hash, "redacting returns a copy")
+
+	require.Equal(t, "2/2", token.PrintableFieldValue("Uses"))
+	require.Equal(t, "env=prod", token.PrintableFieldValue("Labels"))
+}
+
+func TestEnrollmentTokenUnlimited(t *testing.T) {
+	token, _, err := NewEnrollmentToken(Labels{}, 0, 0)
+	require.NoError(t, err)
+	require.Nil(t, token.ExpiresAt)
+
+	token.Uses = 1000
+	require.NoError(t, token.Valid(time.Now().Add(24*365*time.Hour)))
+	require.Equal(t, "1000", token.PrintableFieldValue("Uses"))
+	require.Equal(t, "never", token.PrintableFieldValue("Expires"))
+}
+
+func TestParseEnrollmentToken(t *testing.T) {
+	tests := []struct {
+		value    string
+		expectOK bool
+	}{
+		{"id.secret", true},
+		{"a0f1db77-818a-4f1a-81a3-7b6a9613ef41", false},
+		{".secret", false},
+		{"id.", false},
+		{"", false},
+	}
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			_, _, ok := ParseEnrollmentToken(test.value)
+			require.Equal(t, test.expectOK, ok)
+		})
+	}
+}
+
+func TestAgentCredential(t *testing.T) {
+	credential := &AgentCredential{AgentID: "1"}
+	require.False(t, credential.Issued())
+
+	first, err := credential.Issue()
+	require.NoError(t, err)
+	require.True(t, credential.Issued())
+	require.Empty(t, credential.PreviousHash)
+
+	ok, current := credential.Verify(first)
+	require.True(t, ok)
+	require.True(t, current)
+
+	second, err := credential.Issue()
+	require.NoError(t, err)
+
+	ok, current = credential.Verify(first)
+	require.True(t, ok, "previous secret is accepted")
+	require.False(t, current)
+
+	ok, current = credential.Verify(second)
+	require.True(t, ok)
+	require.True(t, current)
+
+	// issuing again before the agent uses the second secret keeps the first secret
+	third, err := credential.Issue()
+	require.NoError(t, err)
+	ok, _ = credential.Verify(first)
+	require.True(t, ok)
+	ok, _ = credential.Verify(second)
+	require.False(t, ok)
+	ok, _ = credential.Verify(third)
+	require.True(t, ok)
+}
+
+func TestAgentCredentialCertificate(t *testing.T) {
+	credential := &AgentCredential{AgentID: "1"}
+	require.False(t, credential.CertificateIssued())
+	require.False(t, credential.CertificateRenewalDue(time.Now()))
+
+	ok, _ := credential.VerifyCertificate("")
+	require.False(t, ok)
+
+	now := time.Now()
+	credential.IssueCertificate("a1", now.Add(3*time.Hour))
+	require.True(t, credential.CertificateIssued())
+	require.Empty(t, credential.PreviousCertificateSerial)
+
+	ok, current := credential.VerifyCertificate("a1")
+	require.True(t, ok)
+	require.True(t, current)
+
+	require.False(t, credential.CertificateRenewalDue(now.Add(time.Hour)))
+	require.True(t, credential.CertificateRenewalDue(now.Add(2*time.Hour+time.Minute)))
+
+	credential.IssueCertificate("b2", now.Add(3*time.Hour))
+	ok, current = credential.VerifyCertificate("a1")
+	require.True(t, ok, "previous certificate is accepted")
+	require.False(t, current)
+
+	// issuing again before the agent uses the second certificate keeps the first certificate
+	credential.IssueCertificate("c3", now.Add(3*time.Hour))
+	ok, _ = credential.VerifyCertificate("a1")
+	require.True(t, ok)
+	ok, _ = credential.VerifyCertificate("b2")
+	require.False(t, ok)
+	ok, current = credential.VerifyCertificate("c3")
+	require.True(t, ok)
+	require.True(t, current)
+
+	require.False(t, credential.CertificateRevoked())
+	credential.RevokeCertificate()
+	require.False(t, credential.CertificateIssued())
+	require.True(t, credential.CertificateRequired)
+	require.True(t, credential.CertificateRevoked())
+	ok, _ = credential.VerifyCertificate("a1")
+	require.False(t, ok)
+	ok, _ = credential.VerifyCertificate("c3")
+	require.False(t, ok)
+}
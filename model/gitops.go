@@ -0,0 +1,107 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// GitOpsSyncStatus is the result of the most recent sync of resources from the GitOps repository
+type GitOpsSyncStatus struct {
+	Repository string `json:"repository" yaml:"repository"`
+	Branch     string `json:"branch" yaml:"branch"`
+	Path       string `json:"path,omitempty" yaml:"path,omitempty"`
+	Prune      bool   `json:"prune" yaml:"prune"`
+
+	// Commit is the commit of the repository that was synced
+	Commit string `json:"commit,omitempty" yaml:"commit,omitempty"`
+
+	// SyncedAt is the time of the most recent sync, successful or not
+	SyncedAt time.Time `json:"syncedAt" yaml:"syncedAt"`
+
+	// Changes are the resources that were created, configured, or deleted by the most recent sync. Unchanged resources
+	// are not included.
+	Changes []GitOpsChange `json:"changes,omitempty" yaml:"changes,omitempty"`
+
+	// Errors are the errors of the most recent sync, e.g. the repository could not be pulled or a resource is invalid
+	Errors []string `json:"errors,omitempty" yaml:"errors,omitempty"`
+}
+
+// GitOpsChange is a resource changed by a GitOps sync
+type GitOpsChange struct {
+	Kind   Kind         `json:"kind" yaml:"kind"`
+	Name   string       `json:"name" yaml:"name"`
+	Status UpdateStatus `json:"status" yaml:"status"`
+	// Reason will be set if status is invalid or error
+	Reason string `json:"reason,omitempty" yaml:"reason,omitempty"`
+}
+
+var _ Printable = (*GitOpsSyncStatus)(nil)
+
+// Succeeded returns true if the most recent sync did not have any errors
+func (s *GitOpsSyncStatus) Succeeded() bool {
+	return len(s.Errors) == 0
+}
+
+// PrintableKindSingular returns the singular form of the Kind, e.g. "Configuration"
+func (s *GitOpsSyncStatus) PrintableKindSingular() string {
+	return "GitOpsSync"
+}
+
+// PrintableKindPlural returns the plural form of the Kind, e.g. "Configurations"
+func (s *GitOpsSyncStatus) PrintableKindPlural() string {
+	return "GitOpsSyncs"
+}
+
+// PrintableFieldTitles returns the list of field titles, used for printing a table of resources
+func (s *GitOpsSyncStatus) PrintableFieldTitles() []string {
+	return []string{"Repository", "Branch", "Path", "Commit", "Synced", "Changes", "Errors"}
+}
+
+// PrintableFieldValue returns the field value for a title, used for printing a table of resources
+func (s *GitOpsSyncStatus) PrintableFieldValue(title string) string {
+	switch title {
+	case "Repository":
+		return s.Repository
+	case "Branch":
+		return s.Branch
+	case "Path":
+		if s.Path == "" {
+			return "/"
+		}
+		return s.Path
+	case "Commit":
+		return shortCommit(s.Commit)
+	case "Synced":
+		if s.SyncedAt.IsZero() {
+			return "never"
+		}
+		return s.SyncedAt.Format(time.RFC3339)
+	case "Changes":
+		return fmt.Sprintf("%d", len(s.Changes))
+	case "Errors":
+		return fmt.Sprintf("%d", len(s.Errors))
+	}
+	return ""
+}
+
+// shortCommit abbreviates a commit hash the way git does
+func shortCommit(commit string) string {
+	if len(commit) > 7 {
+		return commit[:7]
+	}
+	return commit
+}
@@ -47,6 +47,13 @@ const (
 
 	// LabelBindPlaneAgentArch is the label name for agent cpu architecture
 	LabelBindPlaneAgentArch = "bindplane/agent-arch"
+
+	// LabelBindPlaneOrigin is the label name for the origin of a ResourceType, e.g. builtin, directory, or catalog
+	LabelBindPlaneOrigin = "bindplane/origin"
+
+	// LabelBindPlaneGitOpsCommit is the label name for the commit of the GitOps repository a resource was last synced
+	// from. Resources with this label are managed by GitOps sync.
+	LabelBindPlaneGitOpsCommit = "bindplane/gitops-commit"
 )
 
 // Labeled TODO(doc)
@@ -16,8 +16,10 @@ package otel
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 
+	"github.com/hashicorp/go-multierror"
 	"golang.org/x/exp/slices"
 	"gopkg.in/yaml.v3"
 )
@@ -145,6 +147,57 @@ func (p *Partial) Add(o *Partial) {
 	p.Extensions = append(p.Extensions, o.Extensions...)
 }
 
+// componentTypePattern matches the type of a component, which must start with a letter and contain only letters,
+// numbers, and underscores
+var componentTypePattern = regexp.MustCompile(`^[a-zA-Z][0-9a-zA-Z_]{0,62}$`)
+
+// Validate ensures that each component in the partial configuration has a well-formed, unique ComponentID and a
+// configuration that is either empty or a map.
+func (p *Partial) Validate() error {
+	var errs error
+	for _, list := range []struct {
+		name       string
+		components ComponentList
+	}{
+		{"receivers", p.Receivers},
+		{"processors", p.Processors},
+		{"exporters", p.Exporters},
+		{"extensions", p.Extensions},
+	} {
+		if err := list.components.validate(list.name); err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
+	return errs
+}
+
+func (l ComponentList) validate(name string) error {
+	var errs error
+	ids := map[ComponentID]struct{}{}
+	for _, components := range l {
+		for id, component := range components {
+			if _, ok := ids[id]; ok {
+				errs = multierror.Append(errs, fmt.Errorf("%s contains duplicate component %s", name, id))
+			}
+			ids[id] = struct{}{}
+
+			componentType, componentName := ParseComponentID(id)
+			if !componentTypePattern.MatchString(componentType) {
+				errs = multierror.Append(errs, fmt.Errorf("%s component %s has an invalid type %q", name, id, componentType))
+			}
+			if strings.Contains(string(id), "/") && componentName == "" {
+				errs = multierror.Append(errs, fmt.Errorf("%s component %s has an empty name", name, id))
+			}
+			switch component.(type) {
+			case nil, map[string]any, map[any]any:
+			default:
+				errs = multierror.Append(errs, fmt.Errorf("%s component %s must be a map, got %T", name, id, component))
+			}
+		}
+	}
+	return errs
+}
+
 // Partials represents a fragments of configuration for each type of telemetry.
 type Partials map[PipelineType]*Partial
 
@@ -83,3 +83,58 @@ func TestNilConfiguration(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, NoopConfig, yaml)
 }
+
+func TestPartialValidate(t *testing.T) {
+	tests := []struct {
+		description string
+		partial     Partial
+		expectError []string
+	}{
+		{
+			description: "valid",
+			partial: Partial{
+				Receivers: ComponentList{
+					{"filelog/source0": map[string]any{"include": []any{"/var/log/*.log"}}},
+					{"hostmetrics": nil},
+				},
+				Processors: ComponentList{
+					{"batch/source0": map[any]any{}},
+				},
+			},
+		},
+		{
+			description: "invalid",
+			partial: Partial{
+				Receivers: ComponentList{
+					{"filelog/source0": map[string]any{}},
+					{"filelog/source0": map[string]any{}},
+				},
+				Exporters: ComponentList{
+					{"0tlp": map[string]any{}},
+					{"logging/": map[string]any{}},
+					{"otlp": "endpoint"},
+				},
+			},
+			expectError: []string{
+				"receivers contains duplicate component filelog/source0",
+				`exporters component 0tlp has an invalid type "0tlp"`,
+				"exporters component logging/ has an empty name",
+				"exporters component otlp must be a map, got string",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			err := test.partial.Validate()
+			if len(test.expectError) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			for _, expect := range test.expectError {
+				require.Contains(t, err.Error(), expect)
+			}
+		})
+	}
+}
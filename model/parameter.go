@@ -17,9 +17,14 @@ package model
 import (
 	"fmt"
 	"go/token"
+	"net"
+	"net/url"
 	"reflect"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/observiq/bindplane-op/model/validation"
@@ -37,6 +42,12 @@ const (
 	yamlType     = "yaml"
 	mapType      = "map"
 	timezoneType = "timezone"
+	floatType    = "float"
+	durationType = "duration"
+	hostportType = "hostport"
+	urlType      = "url"
+	regexType    = "regex"
+	objectsType  = "objects"
 )
 
 // ParameterDefinition is a basic description of a definition's parameter. This implementation comes directly from
@@ -53,6 +64,18 @@ type ParameterDefinition struct {
 	// only useable if Type == "enum"
 	ValidValues []string `json:"validValues,omitempty" yaml:"validValues,omitempty" mapstructure:"validValues"`
 
+	// Min and Max are inclusive bounds for "int", "float", and "duration" parameters. Bounds for "duration" parameters
+	// are specified in seconds and bounds for "objects" parameters limit the number of objects.
+	Min *float64 `json:"min,omitempty" yaml:"min,omitempty" mapstructure:"min"`
+	Max *float64 `json:"max,omitempty" yaml:"max,omitempty" mapstructure:"max"`
+
+	// Pattern is a regular expression that values of "string" and "strings" parameters must match
+	Pattern string `json:"pattern,omitempty" yaml:"pattern,omitempty" mapstructure:"pattern"`
+
+	// Parameters describe the fields of each object in the value of an "objects" parameter. RelevantIf conditions of
+	// these parameters refer to other fields of the same object.
+	Parameters []ParameterDefinition `json:"parameters,omitempty" yaml:"parameters,omitempty" mapstructure:"parameters"`
+
 	// Must be valid according to Type & ValidValues
 	Default        interface{}           `json:"default,omitempty" yaml:"default,omitempty"`
 	RelevantIf     []RelevantIfCondition `json:"relevantIf,omitempty" yaml:"relevantIf,omitempty" mapstructure:"relevantIf"`
@@ -81,11 +104,135 @@ type ParameterOptions struct {
 	TrackUnchecked bool `json:"trackUnchecked" yaml:"trackUnchecked"`
 }
 
-// RelevantIfCondition specifies a condition under which a parameter is deemed relevant.
+// RelevantIfOperator values are the operators supported by RelevantIfCondition
+const (
+	// RelevantIfOperatorEquals is satisfied if the parameter value is equal to the condition value
+	RelevantIfOperatorEquals = "equals"
+
+	// RelevantIfOperatorNotEquals is satisfied if the parameter value is not equal to the condition value
+	RelevantIfOperatorNotEquals = "notEquals"
+
+	// RelevantIfOperatorIn is satisfied if the parameter value is equal to one of the values in the condition value
+	RelevantIfOperatorIn = "in"
+
+	// RelevantIfOperatorContainsAny is satisfied if the parameter value is a list containing any of the values in the
+	// condition value
+	RelevantIfOperatorContainsAny = "containsAny"
+
+	// RelevantIfOperatorExists is satisfied if the parameter has a value that is not empty
+	RelevantIfOperatorExists = "exists"
+
+	// RelevantIfOperatorAnd is satisfied if all of the nested conditions are satisfied
+	RelevantIfOperatorAnd = "and"
+
+	// RelevantIfOperatorOr is satisfied if any of the nested conditions are satisfied
+	RelevantIfOperatorOr = "or"
+)
+
+// RelevantIfCondition specifies a condition under which a parameter is deemed relevant. Conditions using the "and" and
+// "or" operators group the nested Conditions and do not specify a Name or Value.
 type RelevantIfCondition struct {
-	Name     string `json:"name" yaml:"name" mapstructure:"name"`
-	Operator string `json:"operator" yaml:"operator" mapstructure:"operator"`
-	Value    any    `json:"value" yaml:"value" mapstructure:"value"`
+	Name       string                `json:"name" yaml:"name" mapstructure:"name"`
+	Operator   string                `json:"operator" yaml:"operator" mapstructure:"operator"`
+	Value      any                   `json:"value" yaml:"value" mapstructure:"value"`
+	Conditions []RelevantIfCondition `json:"conditions,omitempty" yaml:"conditions,omitempty" mapstructure:"conditions"`
+}
+
+// isGroup returns true if the condition combines nested conditions with "and" or "or"
+func (c RelevantIfCondition) isGroup() bool {
+	return c.Operator == RelevantIfOperatorAnd || c.Operator == RelevantIfOperatorOr
+}
+
+// satisfied returns true if the condition is satisfied by the specified parameter values
+func (c RelevantIfCondition) satisfied(values map[string]any) bool {
+	switch c.Operator {
+	case RelevantIfOperatorAnd:
+		return allSatisfied(c.Conditions, values)
+	case RelevantIfOperatorOr:
+		for _, condition := range c.Conditions {
+			if condition.satisfied(values) {
+				return true
+			}
+		}
+		return false
+	}
+
+	value := values[c.Name]
+	switch c.Operator {
+	case RelevantIfOperatorEquals:
+		return valuesEqual(value, c.Value)
+	case RelevantIfOperatorNotEquals:
+		return !valuesEqual(value, c.Value)
+	case RelevantIfOperatorIn:
+		return containsValue(listValues(c.Value), value)
+	case RelevantIfOperatorContainsAny:
+		list := listValues(value)
+		for _, v := range listValues(c.Value) {
+			if containsValue(list, v) {
+				return true
+			}
+		}
+		return false
+	case RelevantIfOperatorExists:
+		return !isEmptyValue(value)
+	}
+	return false
+}
+
+// allSatisfied returns true if all of the conditions are satisfied by the specified parameter values
+func allSatisfied(conditions []RelevantIfCondition, values map[string]any) bool {
+	for _, condition := range conditions {
+		if !condition.satisfied(values) {
+			return false
+		}
+	}
+	return true
+}
+
+// valuesEqual compares parameter values by their string representation because values submitted from a form may be
+// strings even though they represent numbers or bools.
+func valuesEqual(a, b any) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return fmt.Sprintf("%v", a) == fmt.Sprintf("%v", b)
+}
+
+// listValues returns the elements of a list value or nil if the value is not a list
+func listValues(value any) []any {
+	reflectValue := reflect.ValueOf(value)
+	if reflectValue.Kind() != reflect.Slice && reflectValue.Kind() != reflect.Array {
+		return nil
+	}
+	result := make([]any, 0, reflectValue.Len())
+	for i := 0; i < reflectValue.Len(); i++ {
+		result = append(result, reflectValue.Index(i).Interface())
+	}
+	return result
+}
+
+func containsValue(list []any, value any) bool {
+	for _, v := range list {
+		if valuesEqual(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// isEmptyValue returns true if the value is nil, false, an empty string, or an empty list or map
+func isEmptyValue(value any) bool {
+	if value == nil {
+		return true
+	}
+	reflectValue := reflect.ValueOf(value)
+	switch reflectValue.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return reflectValue.Len() == 0
+	case reflect.Bool:
+		return !reflectValue.Bool()
+	}
+	return false
 }
 
 func (p ParameterDefinition) validateValue(value interface{}) error {
@@ -109,13 +256,58 @@ func (p ParameterDefinition) validateDefinition(kind Kind, errs validation.Error
 		errs.Add(err)
 	}
 
+	if err := p.validateConstraints(); err != nil {
+		errs.Add(err)
+	}
+
 	if err := p.validateDefault(); err != nil {
 		errs.Add(err)
 	}
 
+	p.validateNestedParameters(errs)
 	p.validateSpecialParameters(kind, errs)
 }
 
+// validateNestedParameters validates the definitions of the fields of an "objects" parameter
+func (p ParameterDefinition) validateNestedParameters(errs validation.Errors) {
+	if p.Type != objectsType {
+		if len(p.Parameters) > 0 {
+			errs.Add(errors.NewError(
+				fmt.Sprintf("parameters are undefined for parameter of type '%s'", p.Type),
+				"remove 'parameters' field or change type to 'objects'",
+			))
+		}
+		return
+	}
+	if len(p.Parameters) == 0 {
+		errs.Add(errors.NewError(
+			fmt.Sprintf("parameter '%s' of type 'objects' must have 'parameters' specified", p.Name),
+			"specify the parameters for the fields of each object",
+		))
+		return
+	}
+	nested := ResourceTypeSpec{Parameters: p.Parameters}
+	nested.validateParameterDefinitions(KindUnknown, &prefixedErrors{Errors: errs, prefix: p.Name})
+}
+
+// prefixedErrors adds a prefix to each error and warning to identify the parameter containing nested parameters
+type prefixedErrors struct {
+	validation.Errors
+	prefix string
+}
+
+func (e *prefixedErrors) Add(err error) {
+	if err != nil {
+		e.Errors.Add(fmt.Errorf("%s: %w", e.prefix, err))
+	}
+}
+
+func (e *prefixedErrors) Warn(err error) {
+	if err != nil {
+		e.Errors.Warn(fmt.Errorf("%s: %w", e.prefix, err))
+	}
+}
+
 // validateSpecialParameters ensures that for consistency, common parameters like start_at appear the same in all sources
 func (p ParameterDefinition) validateSpecialParameters(kind Kind, errs validation.Errors) {
 	if kind == KindSourceType {
@@ -196,7 +388,8 @@ func (p ParameterDefinition) validateType() error {
 		)
 	}
 	switch p.Type {
-	case stringType, intType, boolType, stringsType, enumType, enumsType, mapType, yamlType, timezoneType: // ok
+	case stringType, intType, boolType, stringsType, enumType, enumsType, mapType, yamlType, timezoneType,
+		floatType, durationType, hostportType, urlType, regexType, objectsType: // ok
 	default:
 		return errors.NewError(
 			fmt.Sprintf("invalid type '%s' for '%s'", p.Type, p.Name),
@@ -233,7 +426,8 @@ func (p ParameterDefinition) validateOptions() error {
 
 func (p ParameterDefinition) validateValidValues() error {
 	switch p.Type {
-	case stringType, intType, boolType, stringsType, yamlType, mapType:
+	case stringType, intType, boolType, stringsType, yamlType, mapType,
+		floatType, durationType, hostportType, urlType, regexType, objectsType:
 		if len(p.ValidValues) > 0 {
 			return errors.NewError(
 				fmt.Sprintf("validValues is undefined for parameter of type '%s'", p.Type),
@@ -251,6 +445,55 @@ func (p ParameterDefinition) validateValidValues() error {
 	return nil
 }
 
+func (p ParameterDefinition) validateConstraints() error {
+	err := &multierror.Error{}
+
+	if p.Min != nil || p.Max != nil {
+		switch p.Type {
+		case intType, floatType, durationType, objectsType: // ok
+		default:
+			multierror.Append(err,
+				errors.NewError(
+					fmt.Sprintf("min and max are undefined for parameter of type '%s'", p.Type),
+					"remove 'min' and 'max' fields or change type to 'int', 'float', 'duration', or 'objects'",
+				),
+			)
+		}
+	}
+
+	if p.Min != nil && p.Max != nil && *p.Min > *p.Max {
+		multierror.Append(err,
+			errors.NewError(
+				fmt.Sprintf("min %v is greater than max %v for parameter '%s'", *p.Min, *p.Max, p.Name),
+				"ensure that min is less than or equal to max",
+			),
+		)
+	}
+
+	if p.Pattern != "" {
+		switch p.Type {
+		case stringType, stringsType:
+			if _, patternErr := regexp.Compile(p.Pattern); patternErr != nil {
+				multierror.Append(err,
+					errors.NewError(
+						fmt.Sprintf("invalid pattern for parameter '%s': %s", p.Name, patternErr),
+						"ensure that the pattern is a valid regular expression",
+					),
+				)
+			}
+		default:
+			multierror.Append(err,
+				errors.NewError(
+					fmt.Sprintf("pattern is undefined for parameter of type '%s'", p.Type),
+					"remove 'pattern' field or change type to 'string' or 'strings'",
+				),
+			)
+		}
+	}
+
+	return err.ErrorOrNil()
+}
+
 func (p ParameterDefinition) validateDefault() error {
 	if p.Default == nil {
 		return nil
@@ -289,6 +532,18 @@ func (p ParameterDefinition) validateValueType(fieldType parameterFieldType, val
 		return p.validateYamlValue(fieldType, value)
 	case timezoneType:
 		return p.validateTimezoneType(fieldType, value)
+	case floatType:
+		return p.validateFloatValue(fieldType, value)
+	case durationType:
+		return p.validateDurationValue(fieldType, value)
+	case hostportType:
+		return p.validateHostPortValue(fieldType, value)
+	case urlType:
+		return p.validateURLValue(fieldType, value)
+	case regexType:
+		return p.validateRegexValue(fieldType, value)
+	case objectsType:
+		return p.validateObjectsValue(fieldType, value)
 	default:
 		return errors.NewError(
 			"invalid type for parameter",
@@ -298,29 +553,34 @@ func (p ParameterDefinition) validateValueType(fieldType parameterFieldType, val
 }
 
 func (p ParameterDefinition) validateStringValue(fieldType parameterFieldType, value any) error {
-	if _, ok := value.(string); !ok {
+	str, ok := value.(string)
+	if !ok {
 		return errors.NewError(
 			fmt.Sprintf("%s value for '%s' must be a string", fieldType, p.Name),
 			fmt.Sprintf("ensure that the %s value is a string", fieldType),
 		)
 	}
-	return nil
+	return p.validatePattern(fieldType, str)
 }
 
 func (p ParameterDefinition) validateIntValue(fieldType parameterFieldType, value any) error {
 	isIntValue := false
+	var intValue int
 
-	if _, ok := value.(int); ok {
+	if v, ok := value.(int); ok {
 		// obvious case of integer
 		isIntValue = true
+		intValue = v
 	} else if floatValue, ok := value.(float64); ok {
 		// less obvious case of float64
 		if floatValue == float64(int(floatValue)) {
 			isIntValue = true
+			intValue = int(floatValue)
 		}
 	} else if stringValue, ok := value.(string); ok {
-		_, err := strconv.Atoi(stringValue)
+		v, err := strconv.Atoi(stringValue)
 		isIntValue = err == nil
+		intValue = v
 	}
 
 	if !isIntValue {
@@ -329,6 +589,203 @@ func (p ParameterDefinition) validateIntValue(fieldType parameterFieldType, valu
 			fmt.Sprintf("ensure that the %s value is an integer", fieldType),
 		)
 	}
+	return p.validateRange(fieldType, float64(intValue))
+}
+
+func (p ParameterDefinition) validateFloatValue(fieldType parameterFieldType, value any) error {
+	floatValue, ok := parseFloatValue(value)
+	if !ok {
+		return errors.NewError(
+			fmt.Sprintf("%s value for '%s' must be a number", fieldType, p.Name),
+			fmt.Sprintf("ensure that the %s value is a number", fieldType),
+		)
+	}
+	return p.validateRange(fieldType, floatValue)
+}
+
+func (p ParameterDefinition) validateDurationValue(fieldType parameterFieldType, value any) error {
+	durationErr := errors.NewError(
+		fmt.Sprintf("%s value for '%s' must be a duration", fieldType, p.Name),
+		fmt.Sprintf("ensure that the %s value is a duration with a unit suffix, e.g. 30s, 5m, or 1h", fieldType),
+	)
+
+	str, ok := value.(string)
+	if !ok {
+		return durationErr
+	}
+	duration, err := time.ParseDuration(str)
+	if err != nil {
+		return durationErr
+	}
+	return p.validateRange(fieldType, duration.Seconds())
+}
+
+func (p ParameterDefinition) validateHostPortValue(fieldType parameterFieldType, value any) error {
+	hostportErr := errors.NewError(
+		fmt.Sprintf("%s value for '%s' must be a host and port", fieldType, p.Name),
+		fmt.Sprintf("ensure that the %s value is in the form host:port, e.g. 0.0.0.0:4317", fieldType),
+	)
+
+	str, ok := value.(string)
+	if !ok {
+		return hostportErr
+	}
+	_, port, err := net.SplitHostPort(str)
+	if err != nil {
+		return hostportErr
+	}
+	if portNumber, err := strconv.ParseUint(port, 10, 16); err != nil || portNumber == 0 {
+		return hostportErr
+	}
+	return nil
+}
+
+func (p ParameterDefinition) validateURLValue(fieldType parameterFieldType, value any) error {
+	urlErr := errors.NewError(
+		fmt.Sprintf("%s value for '%s' must be a url", fieldType, p.Name),
+		fmt.Sprintf("ensure that the %s value is an absolute url including the scheme, e.g. https://example.com", fieldType),
+	)
+
+	str, ok := value.(string)
+	if !ok {
+		return urlErr
+	}
+	parsed, err := url.Parse(str)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return urlErr
+	}
+	return nil
+}
+
+func (p ParameterDefinition) validateRegexValue(fieldType parameterFieldType, value any) error {
+	str, ok := value.(string)
+	if !ok {
+		return errors.NewError(
+			fmt.Sprintf("%s value for '%s' must be a regular expression", fieldType, p.Name),
+			fmt.Sprintf("ensure that the %s value is a string", fieldType),
+		)
+	}
+	if _, err := regexp.Compile(str); err != nil {
+		return errors.NewError(
+			fmt.Sprintf("%s value for '%s' is not a valid regular expression: %s", fieldType, p.Name, err),
+			fmt.Sprintf("ensure that the %s value is a valid regular expression", fieldType),
+		)
+	}
+	return nil
+}
+
+func (p ParameterDefinition) validateObjectsValue(fieldType parameterFieldType, value any) error {
+	objectsErr := errors.NewError(
+		fmt.Sprintf("%s value for '%s' must be a list of objects", fieldType, p.Name),
+		fmt.Sprintf("ensure that the %s value is a list of objects with fields matching the parameters of '%s'", fieldType, p.Name),
+	)
+
+	list := listValues(value)
+	if list == nil {
+		return objectsErr
+	}
+	if err := p.validateRange(fieldType, float64(len(list))); err != nil {
+		return err
+	}
+
+	err := &multierror.Error{}
+	for i, item := range list {
+		object, ok := objectValue(item)
+		if !ok {
+			return objectsErr
+		}
+		// relevantIf conditions of nested parameters refer to other fields of the same object
+		values := p.objectValues(object)
+		names := make([]string, 0, len(object))
+		for name := range object {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fieldValue := object[name]
+			def := p.nestedParameter(name)
+			if def == nil {
+				multierror.Append(err, errors.NewError(
+					fmt.Sprintf("%s value for '%s[%d]' has undefined field '%s'", fieldType, p.Name, i, name),
+					fmt.Sprintf("ensure that the fields of each object are parameters of '%s'", p.Name),
+				))
+				continue
+			}
+			def.Name = fmt.Sprintf("%s[%d].%s", p.Name, i, def.Name)
+			if fieldErr := def.validateValueType(fieldType, fieldValue); fieldErr != nil {
+				multierror.Append(err, fieldErr)
+			}
+		}
+		for _, def := range p.Parameters {
+			if def.Required && isMissingValue(values[def.Name]) && allSatisfied(def.RelevantIf, values) {
+				multierror.Append(err, errors.NewError(
+					fmt.Sprintf("%s value for '%s[%d]' is missing required field '%s'", fieldType, p.Name, i, def.Name),
+					fmt.Sprintf("ensure that each object specifies '%s'", def.Name),
+				))
+			}
+		}
+	}
+	return err.ErrorOrNil()
+}
+
+// nestedParameter returns a copy of the nested ParameterDefinition with the specified name or nil if no such parameter
+// exists
+func (p ParameterDefinition) nestedParameter(name string) *ParameterDefinition {
+	for _, def := range p.Parameters {
+		if def.Name == name {
+			return &def
+		}
+	}
+	return nil
+}
+
+// objectValues returns the default values of the nested parameters with the fields of the object applied
+func (p ParameterDefinition) objectValues(object map[string]any) map[string]any {
+	values := map[string]any{}
+	for _, def := range p.Parameters {
+		if def.Default != nil {
+			values[def.Name] = def.Default
+		}
+	}
+	for name, value := range object {
+		values[name] = value
+	}
+	return values
+}
+
+// validateRange ensures that the number is within the Min and Max bounds of the parameter, if specified
+func (p ParameterDefinition) validateRange(fieldType parameterFieldType, number float64) error {
+	if p.Min != nil && number < *p.Min {
+		return errors.NewError(
+			fmt.Sprintf("%s value for '%s' must be at least %v", fieldType, p.Name, *p.Min),
+			fmt.Sprintf("ensure that the %s value is between the min and max", fieldType),
+		)
+	}
+	if p.Max != nil && number > *p.Max {
+		return errors.NewError(
+			fmt.Sprintf("%s value for '%s' must be at most %v", fieldType, p.Name, *p.Max),
+			fmt.Sprintf("ensure that the %s value is between the min and max", fieldType),
+		)
+	}
+	return nil
+}
+
+// validatePattern ensures that the string matches the Pattern of the parameter, if specified
+func (p ParameterDefinition) validatePattern(fieldType parameterFieldType, str string) error {
+	if p.Pattern == "" {
+		return nil
+	}
+	pattern, err := regexp.Compile(p.Pattern)
+	if err != nil {
+		// invalid patterns are reported by validateConstraints
+		return nil
+	}
+	if !pattern.MatchString(str) {
+		return errors.NewError(
+			fmt.Sprintf("%s value '%s' for '%s' must match the pattern %s", fieldType, str, p.Name, p.Pattern),
+			fmt.Sprintf("ensure that the %s value matches the pattern", fieldType),
+		)
+	}
 	return nil
 }
 
@@ -352,7 +809,12 @@ func (p ParameterDefinition) validateBoolValue(fieldType parameterFieldType, val
 }
 
 func (p ParameterDefinition) validateStringArrayValue(fieldType parameterFieldType, value any) error {
-	if _, ok := value.([]string); ok {
+	if strs, ok := value.([]string); ok {
+		for _, s := range strs {
+			if err := p.validatePattern(fieldType, s); err != nil {
+				return err
+			}
+		}
 		return nil
 	}
 	valueList, ok := value.([]interface{})
@@ -363,12 +825,16 @@ func (p ParameterDefinition) validateStringArrayValue(fieldType parameterFieldTy
 		)
 	}
 	for _, s := range valueList {
-		if _, ok := s.(string); !ok {
+		str, ok := s.(string)
+		if !ok {
 			return errors.NewError(
 				fmt.Sprintf("%s value for '%s' must be an array of strings", fieldType, p.Name),
 				fmt.Sprintf("ensure that the %s value is an array of string", fieldType),
 			)
 		}
+		if err := p.validatePattern(fieldType, str); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -483,3 +949,111 @@ func (p ParameterDefinition) validateMapValue(fieldType parameterFieldType, valu
 	}
 	return nil
 }
+
+// templateValue returns the value to use when rendering templates. Numeric values may be provided as strings, e.g. from
+// a form in the UI, so they are converted here to render with the correct YAML type. Objects include the default values
+// of their nested parameters so that templates can refer to any field.
+func (p ParameterDefinition) templateValue(value any) any {
+	switch p.Type {
+	case floatType:
+		if floatValue, ok := parseFloatValue(value); ok {
+			return floatValue
+		}
+	case objectsType:
+		list := listValues(value)
+		if list == nil {
+			return value
+		}
+		result := make([]any, 0, len(list))
+		for _, item := range list {
+			object, ok := objectValue(item)
+			if !ok {
+				result = append(result, item)
+				continue
+			}
+			values := p.objectValues(object)
+			for _, def := range p.Parameters {
+				if v, ok := values[def.Name]; ok {
+					values[def.Name] = def.templateValue(v)
+				} else {
+					values[def.Name] = def.templateDefaultValue()
+				}
+			}
+			result = append(result, values)
+		}
+		return result
+	}
+	return value
+}
+
+// templateDefaultValue returns a reasonable value based on the type for parameters without a default. It is used for
+// template validation and for fields of objects so that templates can refer to fields that are not specified.
+func (p ParameterDefinition) templateDefaultValue() any {
+	switch p.Type {
+	case boolType:
+		return false
+	case enumType:
+		return "" // p.ValidValues[0] // cannot guarantee this is valid and "" is fine
+	case enumsType:
+		return []string{}
+	case intType:
+		return 0
+	case mapType:
+		return make(map[string]string)
+	case stringType:
+		return ""
+	case stringsType:
+		return []string{}
+	case yamlType:
+		return ""
+	case floatType:
+		return 0.0
+	case durationType, hostportType, urlType, regexType:
+		return ""
+	case objectsType:
+		return []any{}
+	}
+	return nil
+}
+
+// objectValue returns the value as a map[string]any if it is an object. Objects parsed from yaml may have keys of type
+// any which are converted to strings.
+func objectValue(value any) (map[string]any, bool) {
+	switch v := value.(type) {
+	case map[string]any:
+		return v, true
+	case map[any]any:
+		result := make(map[string]any, len(v))
+		for key, value := range v {
+			result[fmt.Sprintf("%v", key)] = value
+		}
+		return result, true
+	}
+	return nil, false
+}
+
+// isMissingValue returns true if the value is nil or an empty string
+func isMissingValue(value any) bool {
+	if str, ok := value.(string); ok {
+		return str == ""
+	}
+	return value == nil
+}
+
+// parseFloatValue returns the value as a float64 if it is a number or a string containing a number
+func parseFloatValue(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
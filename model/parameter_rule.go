@@ -0,0 +1,192 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/observiq/bindplane-op/model/validation"
+)
+
+// ParameterRuleType values are the types of rules supported by ParameterRule
+const (
+	// ParameterRuleMutuallyExclusive is violated if more than one of the parameters has a value
+	ParameterRuleMutuallyExclusive = "mutuallyExclusive"
+
+	// ParameterRuleRequires is violated if any of the parameters does not have a value
+	ParameterRuleRequires = "requires"
+
+	// ParameterRuleAtLeastOneOf is violated if none of the parameters has a value
+	ParameterRuleAtLeastOneOf = "atLeastOneOf"
+)
+
+// ParameterRule is a constraint across multiple parameters of a ResourceType. Rules are enforced when a Source,
+// Processor, or Destination is validated with a store so that invalid combinations of parameters are rejected before the
+// configuration is sent to an agent. Rules are evaluated with the effective parameter values, which are the explicitly set
+// values merged with the default values. A parameter is considered to have a value if it is relevant and its effective
+// value is not empty. A bool parameter has a value whenever it is set, even if it is false.
+type ParameterRule struct {
+	// Type is one of "mutuallyExclusive", "requires", or "atLeastOneOf"
+	Type string `json:"type" yaml:"type" mapstructure:"type"`
+
+	// Parameters are the names of the parameters constrained by this rule
+	Parameters []string `json:"parameters" yaml:"parameters" mapstructure:"parameters"`
+
+	// When limits the rule to apply only if all of the conditions are satisfied. This is typically used with "requires"
+	// to require parameters only when another parameter has a specific value.
+	When []RelevantIfCondition `json:"when,omitempty" yaml:"when,omitempty" mapstructure:"when"`
+
+	// Message replaces the default error message when the rule is violated
+	Message string `json:"message,omitempty" yaml:"message,omitempty" mapstructure:"message"`
+}
+
+// evaluate returns an error if the rule is violated by the relevant parameter values. The conditions of When are
+// evaluated with values, which include parameters that are not relevant.
+func (r ParameterRule) evaluate(values map[string]any, relevant map[string]any) error {
+	if !allSatisfied(r.When, values) {
+		return nil
+	}
+
+	var set, unset []string
+	for _, name := range r.Parameters {
+		if !hasRuleValue(relevant[name]) {
+			unset = append(unset, name)
+		} else {
+			set = append(set, name)
+		}
+	}
+
+	var violation string
+	switch r.Type {
+	case ParameterRuleMutuallyExclusive:
+		if len(set) > 1 {
+			violation = fmt.Sprintf("parameters %s are mutually exclusive", quotedList(set))
+		}
+	case ParameterRuleRequires:
+		switch len(unset) {
+		case 0:
+		case 1:
+			violation = fmt.Sprintf("parameter %s is required", quotedList(unset))
+		default:
+			violation = fmt.Sprintf("parameters %s are required", quotedList(unset))
+		}
+	case ParameterRuleAtLeastOneOf:
+		if len(set) == 0 {
+			violation = fmt.Sprintf("at least one of parameters %s is required", quotedList(r.Parameters))
+		}
+	}
+
+	switch {
+	case violation == "":
+		return nil
+	case r.Message != "":
+		return fmt.Errorf("%s: %s", violation, r.Message)
+	default:
+		return fmt.Errorf("%s", violation)
+	}
+}
+
+// validateRules ensures that the rules are well formed and refer to existing parameters
+func (s *ResourceTypeSpec) validateRules(errs validation.Errors) {
+	for i, rule := range s.Rules {
+		owner := fmt.Sprintf("rules[%d]", i)
+		errs := validation.WithField(errs, owner)
+		switch rule.Type {
+		case ParameterRuleMutuallyExclusive, ParameterRuleAtLeastOneOf:
+			if len(rule.Parameters) < 2 {
+				errs.Add(fmt.Errorf("%s rule '%s' must specify at least two parameters", owner, rule.Type))
+			}
+		case ParameterRuleRequires:
+			if len(rule.Parameters) == 0 {
+				errs.Add(fmt.Errorf("%s rule '%s' must specify at least one parameter", owner, rule.Type))
+			}
+		case "":
+			errs.Add(fmt.Errorf("%s must have a type", owner))
+		default:
+			errs.Add(fmt.Errorf("%s has unsupported type '%s'", owner, rule.Type))
+		}
+		var defaulted []string
+		for _, name := range rule.Parameters {
+			def := s.ParameterDefinition(name)
+			if def == nil {
+				errs.Add(fmt.Errorf("%s refers to nonexistant parameter '%s'", owner, name))
+				continue
+			}
+			if hasRuleValue(def.Default) && len(def.RelevantIf) == 0 {
+				defaulted = append(defaulted, name)
+			}
+		}
+		// a parameter with a default always has a value, so the other parameters could never be set
+		if rule.Type == ParameterRuleMutuallyExclusive && len(defaulted) > 0 {
+			errs.Add(fmt.Errorf("%s rule '%s' cannot include parameters with default values: %s", owner, rule.Type, quotedList(defaulted)))
+		}
+		s.validateConditions("when", owner, rule.When, errs)
+	}
+}
+
+// evaluateRules adds an error for each rule violated by the specified parameters
+func (s *ResourceTypeSpec) evaluateRules(parameters []Parameter, errs validation.Errors) {
+	values := s.parameterValues(parameters)
+	relevant := s.relevantValues(values)
+	for _, rule := range s.Rules {
+		if err := rule.evaluate(values, relevant); err != nil {
+			errs.Add(err)
+		}
+	}
+}
+
+// relevantValues returns the parameter values that are relevant. Relevance is determined with values, which include
+// default values.
+func (s *ResourceTypeSpec) relevantValues(values map[string]any) map[string]any {
+	relevant := map[string]any{}
+	for name, value := range values {
+		if def := s.ParameterDefinition(name); def != nil && !allSatisfied(def.RelevantIf, values) {
+			continue
+		}
+		relevant[name] = value
+	}
+	return relevant
+}
+
+// hasRuleValue returns true if the value counts as set for a ParameterRule. Unlike isEmptyValue, false is a value.
+func hasRuleValue(value any) bool {
+	if _, ok := value.(bool); ok {
+		return true
+	}
+	return !isEmptyValue(value)
+}
+
+// parameterValues returns the default parameter values with the specified parameters applied
+func (s *ResourceTypeSpec) parameterValues(parameters []Parameter) map[string]any {
+	values := map[string]any{}
+	for _, p := range s.Parameters {
+		if p.Default != nil {
+			values[p.Name] = p.Default
+		}
+	}
+	for _, p := range parameters {
+		values[p.Name] = p.Value
+	}
+	return values
+}
+
+func quotedList(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = fmt.Sprintf("'%s'", name)
+	}
+	return strings.Join(quoted, ", ")
+}
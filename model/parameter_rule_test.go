@@ -0,0 +1,251 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/observiq/bindplane-op/model/validation"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParameterRuleEvaluate(t *testing.T) {
+	testCases := []struct {
+		name        string
+		rule        ParameterRule
+		values      map[string]any
+		expectError string
+	}{
+		{
+			name:   "mutuallyExclusive none set",
+			rule:   ParameterRule{Type: "mutuallyExclusive", Parameters: []string{"a", "b"}},
+			values: map[string]any{},
+		},
+		{
+			name:   "mutuallyExclusive one set",
+			rule:   ParameterRule{Type: "mutuallyExclusive", Parameters: []string{"a", "b"}},
+			values: map[string]any{"a": "value", "b": ""},
+		},
+		{
+			name:        "mutuallyExclusive false is set",
+			rule:        ParameterRule{Type: "mutuallyExclusive", Parameters: []string{"a", "b"}},
+			values:      map[string]any{"a": "value", "b": false},
+			expectError: "parameters 'a', 'b' are mutually exclusive",
+		},
+		{
+			name:        "mutuallyExclusive both set",
+			rule:        ParameterRule{Type: "mutuallyExclusive", Parameters: []string{"a", "b"}},
+			values:      map[string]any{"a": "value", "b": true},
+			expectError: "parameters 'a', 'b' are mutually exclusive",
+		},
+		{
+			name:   "requires set",
+			rule:   ParameterRule{Type: "requires", Parameters: []string{"a", "b"}},
+			values: map[string]any{"a": "value", "b": []any{"value"}},
+		},
+		{
+			name:   "requires false is set",
+			rule:   ParameterRule{Type: "requires", Parameters: []string{"a"}},
+			values: map[string]any{"a": false},
+		},
+		{
+			name:        "requires not set",
+			rule:        ParameterRule{Type: "requires", Parameters: []string{"a", "b"}},
+			values:      map[string]any{"a": "value", "b": []any{}},
+			expectError: "parameter 'b' is required",
+		},
+		{
+			name: "requires when not satisfied",
+			rule: ParameterRule{
+				Type:       "requires",
+				Parameters: []string{"cert"},
+				When:       []RelevantIfCondition{{Name: "tls", Operator: "equals", Value: true}},
+			},
+			values: map[string]any{"tls": false},
+		},
+		{
+			name: "requires when satisfied",
+			rule: ParameterRule{
+				Type:       "requires",
+				Parameters: []string{"cert"},
+				When:       []RelevantIfCondition{{Name: "tls", Operator: "equals", Value: true}},
+				Message:    "TLS requires a certificate",
+			},
+			values:      map[string]any{"tls": true},
+			expectError: "parameter 'cert' is required: TLS requires a certificate",
+		},
+		{
+			name:   "atLeastOneOf set",
+			rule:   ParameterRule{Type: "atLeastOneOf", Parameters: []string{"a", "b"}},
+			values: map[string]any{"b": 1},
+		},
+		{
+			name:        "atLeastOneOf not set",
+			rule:        ParameterRule{Type: "atLeastOneOf", Parameters: []string{"a", "b"}},
+			values:      map[string]any{"a": ""},
+			expectError: "at least one of parameters 'a', 'b' is required",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.rule.evaluate(tc.values, tc.values)
+			if tc.expectError == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tc.expectError)
+			}
+		})
+	}
+}
+
+func TestResourceTypeSpecEvaluateRules(t *testing.T) {
+	spec := ResourceTypeSpec{
+		Parameters: []ParameterDefinition{
+			{Name: "token", Type: "string"},
+			{Name: "use_env_token", Type: "bool"},
+			{Name: "endpoint", Type: "string", Default: "localhost:4317"},
+			{Name: "enable_tls", Type: "bool", Default: false},
+			{
+				Name: "cert_file",
+				Type: "string",
+				RelevantIf: []RelevantIfCondition{
+					{Name: "enable_tls", Operator: "equals", Value: true},
+				},
+			},
+		},
+		Rules: []ParameterRule{
+			{Type: "mutuallyExclusive", Parameters: []string{"token", "use_env_token"}},
+			{Type: "atLeastOneOf", Parameters: []string{"token", "cert_file"}},
+			{Type: "requires", Parameters: []string{"endpoint", "enable_tls"}},
+		},
+	}
+
+	testCases := []struct {
+		name        string
+		parameters  []Parameter
+		expectError string
+	}{
+		{
+			name:       "defaulted parameters are set",
+			parameters: []Parameter{{Name: "token", Value: "secret"}},
+		},
+		{
+			name: "explicitly set parameters",
+			parameters: []Parameter{
+				{Name: "token", Value: "secret"},
+				{Name: "use_env_token", Value: true},
+			},
+			expectError: "parameters 'token', 'use_env_token' are mutually exclusive",
+		},
+		{
+			name: "explicitly set false",
+			parameters: []Parameter{
+				{Name: "token", Value: "secret"},
+				{Name: "use_env_token", Value: false},
+			},
+			expectError: "parameters 'token', 'use_env_token' are mutually exclusive",
+		},
+		{
+			name: "explicitly cleared default",
+			parameters: []Parameter{
+				{Name: "token", Value: "secret"},
+				{Name: "endpoint", Value: ""},
+			},
+			expectError: "parameter 'endpoint' is required",
+		},
+		{
+			name:        "irrelevant parameter is not set",
+			parameters:  []Parameter{{Name: "cert_file", Value: "/tmp/cert.pem"}},
+			expectError: "at least one of parameters 'token', 'cert_file' is required",
+		},
+		{
+			name: "relevant parameter is set",
+			parameters: []Parameter{
+				{Name: "enable_tls", Value: true},
+				{Name: "cert_file", Value: "/tmp/cert.pem"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := validation.NewErrors()
+			spec.evaluateRules(tc.parameters, errs)
+			err := errs.Result()
+			if tc.expectError == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.expectError)
+			}
+		})
+	}
+}
+
+func TestResourceTypeSpecValidateRules(t *testing.T) {
+	testCases := []struct {
+		name        string
+		parameters  []ParameterDefinition
+		rule        ParameterRule
+		expectError string
+	}{
+		{
+			name: "mutuallyExclusive without defaults",
+			parameters: []ParameterDefinition{
+				{Name: "a", Type: "string"},
+				{Name: "b", Type: "bool"},
+			},
+			rule: ParameterRule{Type: "mutuallyExclusive", Parameters: []string{"a", "b"}},
+		},
+		{
+			name: "mutuallyExclusive with default",
+			parameters: []ParameterDefinition{
+				{Name: "a", Type: "string"},
+				{Name: "b", Type: "bool", Default: false},
+			},
+			rule:        ParameterRule{Type: "mutuallyExclusive", Parameters: []string{"a", "b"}},
+			expectError: "rules[0] rule 'mutuallyExclusive' cannot include parameters with default values: 'b'",
+		},
+		{
+			name: "mutuallyExclusive with relevantIf default",
+			parameters: []ParameterDefinition{
+				{Name: "a", Type: "string"},
+				{Name: "b", Type: "string", Default: "value", RelevantIf: []RelevantIfCondition{{Name: "a", Operator: "exists"}}},
+			},
+			rule: ParameterRule{Type: "mutuallyExclusive", Parameters: []string{"a", "b"}},
+		},
+		{
+			name: "requires with default",
+			parameters: []ParameterDefinition{
+				{Name: "a", Type: "string", Default: "value"},
+			},
+			rule: ParameterRule{Type: "requires", Parameters: []string{"a"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			spec := ResourceTypeSpec{Parameters: tc.parameters, Rules: []ParameterRule{tc.rule}}
+			errs := validation.NewErrors()
+			spec.validateRules(errs)
+			err := errs.Result()
+			if tc.expectError == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.expectError)
+			}
+		})
+	}
+}
@@ -17,6 +17,7 @@ package model
 import (
 	"testing"
 
+	"github.com/observiq/bindplane-op/model/validation"
 	"github.com/stretchr/testify/require"
 )
 
@@ -139,10 +140,61 @@ func TestValidateDefault(t *testing.T) {
 			"InvalidTypeDefault",
 			true,
 			ParameterDefinition{
-				Type:    "float",
+				Type:    "double",
 				Default: 5,
 			},
 		},
+		{
+			"ValidFloatDefault",
+			false,
+			ParameterDefinition{
+				Type:    "float",
+				Default: 0.5,
+			},
+		},
+		{
+			"InvalidFloatDefault",
+			true,
+			ParameterDefinition{
+				Type:    "float",
+				Default: "half",
+			},
+		},
+		{
+			"FloatDefaultOutOfRange",
+			true,
+			ParameterDefinition{
+				Type:    "float",
+				Default: 1.5,
+				Min:     floatPtr(0),
+				Max:     floatPtr(1),
+			},
+		},
+		{
+			"ValidDurationDefault",
+			false,
+			ParameterDefinition{
+				Type:    "duration",
+				Default: "30s",
+			},
+		},
+		{
+			"InvalidDurationDefault",
+			true,
+			ParameterDefinition{
+				Type:    "duration",
+				Default: 30,
+			},
+		},
+		{
+			"StringDefaultNotMatchingPattern",
+			true,
+			ParameterDefinition{
+				Type:    "string",
+				Default: "test",
+				Pattern: "^[0-9]+$",
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -244,6 +296,145 @@ func TestValidateOptions(t *testing.T) {
 	}
 }
 
+func TestValidateConstraints(t *testing.T) {
+	testCases := []struct {
+		name      string
+		expectErr bool
+		param     ParameterDefinition
+	}{
+		{
+			"Int Min Max OK",
+			false,
+			ParameterDefinition{
+				Type: "int",
+				Min:  floatPtr(1),
+				Max:  floatPtr(10),
+			},
+		},
+		{
+			"Duration Min OK",
+			false,
+			ParameterDefinition{
+				Type: "duration",
+				Min:  floatPtr(1),
+			},
+		},
+		{
+			"String Min Error",
+			true,
+			ParameterDefinition{
+				Type: "string",
+				Min:  floatPtr(1),
+			},
+		},
+		{
+			"Min Greater Than Max Error",
+			true,
+			ParameterDefinition{
+				Type: "float",
+				Min:  floatPtr(1),
+				Max:  floatPtr(0),
+			},
+		},
+		{
+			"Strings Pattern OK",
+			false,
+			ParameterDefinition{
+				Type:    "strings",
+				Pattern: "^[a-z]+$",
+			},
+		},
+		{
+			"Invalid Pattern Error",
+			true,
+			ParameterDefinition{
+				Type:    "string",
+				Pattern: "[a-z",
+			},
+		},
+		{
+			"Int Pattern Error",
+			true,
+			ParameterDefinition{
+				Type:    "int",
+				Pattern: "^[0-9]+$",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.param.validateConstraints()
+			if test.expectErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateNestedParameters(t *testing.T) {
+	testCases := []struct {
+		name        string
+		param       ParameterDefinition
+		expectError string
+	}{
+		{
+			name: "Objects OK",
+			param: ParameterDefinition{
+				Name: "policies",
+				Type: "objects",
+				Parameters: []ParameterDefinition{
+					{Name: "name", Type: "string"},
+					{Name: "enabled", Type: "bool", RelevantIf: []RelevantIfCondition{{Name: "name", Operator: "exists"}}},
+				},
+			},
+		},
+		{
+			name: "Objects Without Parameters",
+			param: ParameterDefinition{
+				Name: "policies",
+				Type: "objects",
+			},
+			expectError: "1 error occurred:\n\t* parameter 'policies' of type 'objects' must have 'parameters' specified\n\n",
+		},
+		{
+			name: "Invalid Nested Parameters",
+			param: ParameterDefinition{
+				Name: "policies",
+				Type: "objects",
+				Parameters: []ParameterDefinition{
+					{Name: "name", Type: "text"},
+					{Name: "enabled", Type: "bool", RelevantIf: []RelevantIfCondition{{Name: "missing", Operator: "exists"}}},
+				},
+			},
+			expectError: "2 errors occurred:\n\t* policies: invalid type 'text' for 'name'\n\t* policies: relevantIf for 'enabled' refers to nonexistant parameter 'missing'\n\n",
+		},
+		{
+			name: "Parameters For Non-Objects",
+			param: ParameterDefinition{
+				Name:       "policies",
+				Type:       "map",
+				Parameters: []ParameterDefinition{{Name: "name", Type: "string"}},
+			},
+			expectError: "1 error occurred:\n\t* parameters are undefined for parameter of type 'map'\n\n",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			errs := validation.NewErrors()
+			test.param.validateNestedParameters(errs)
+			if test.expectError == "" {
+				require.NoError(t, errs.Result())
+			} else {
+				require.EqualError(t, errs.Result(), test.expectError)
+			}
+		})
+	}
+}
+
 func TestValidateValue(t *testing.T) {
 	testCases := []struct {
 		name      string
@@ -387,7 +578,7 @@ func TestValidateValue(t *testing.T) {
 			"InvalidType",
 			true,
 			ParameterDefinition{
-				Type:    "float",
+				Type:    "double",
 				Default: 5,
 			},
 			5,
@@ -516,6 +707,334 @@ eleven:
 			},
 			"America/NewJersey",
 		},
+		{
+			"ValidFloat",
+			false,
+			ParameterDefinition{
+				Type: "float",
+			},
+			0.25,
+		},
+		{
+			"ValidFloatAsInt",
+			false,
+			ParameterDefinition{
+				Type: "float",
+			},
+			1,
+		},
+		{
+			"ValidFloatAsString",
+			false,
+			ParameterDefinition{
+				Type: "float",
+			},
+			"0.25",
+		},
+		{
+			"InvalidFloat",
+			true,
+			ParameterDefinition{
+				Type: "float",
+			},
+			"quarter",
+		},
+		{
+			"FloatBelowMin",
+			true,
+			ParameterDefinition{
+				Type: "float",
+				Min:  floatPtr(0),
+				Max:  floatPtr(1),
+			},
+			-0.1,
+		},
+		{
+			"FloatAboveMax",
+			true,
+			ParameterDefinition{
+				Type: "float",
+				Min:  floatPtr(0),
+				Max:  floatPtr(1),
+			},
+			"1.1",
+		},
+		{
+			"IntAboveMax",
+			true,
+			ParameterDefinition{
+				Type: "int",
+				Max:  floatPtr(65535),
+			},
+			65536,
+		},
+		{
+			"ValidDuration",
+			false,
+			ParameterDefinition{
+				Type: "duration",
+			},
+			"1m30s",
+		},
+		{
+			"InvalidDuration",
+			true,
+			ParameterDefinition{
+				Type: "duration",
+			},
+			"90",
+		},
+		{
+			"DurationBelowMin",
+			true,
+			ParameterDefinition{
+				Type: "duration",
+				Min:  floatPtr(10),
+			},
+			"500ms",
+		},
+		{
+			"ValidHostPort",
+			false,
+			ParameterDefinition{
+				Type: "hostport",
+			},
+			"0.0.0.0:4317",
+		},
+		{
+			"ValidHostPortIPv6",
+			false,
+			ParameterDefinition{
+				Type: "hostport",
+			},
+			"[::1]:4317",
+		},
+		{
+			"InvalidHostPortMissingPort",
+			true,
+			ParameterDefinition{
+				Type: "hostport",
+			},
+			"localhost",
+		},
+		{
+			"InvalidHostPortPortRange",
+			true,
+			ParameterDefinition{
+				Type: "hostport",
+			},
+			"localhost:70000",
+		},
+		{
+			"ValidURL",
+			false,
+			ParameterDefinition{
+				Type: "url",
+			},
+			"https://example.com:8443/v1/logs",
+		},
+		{
+			"InvalidURLMissingScheme",
+			true,
+			ParameterDefinition{
+				Type: "url",
+			},
+			"example.com/v1/logs",
+		},
+		{
+			"ValidRegex",
+			false,
+			ParameterDefinition{
+				Type: "regex",
+			},
+			`^(?P<level>\w+):`,
+		},
+		{
+			"InvalidRegex",
+			true,
+			ParameterDefinition{
+				Type: "regex",
+			},
+			"(unclosed",
+		},
+		{
+			"StringMatchingPattern",
+			false,
+			ParameterDefinition{
+				Type:    "string",
+				Pattern: "^[a-z]+$",
+			},
+			"test",
+		},
+		{
+			"StringNotMatchingPattern",
+			true,
+			ParameterDefinition{
+				Type:    "string",
+				Pattern: "^[a-z]+$",
+			},
+			"Test",
+		},
+		{
+			"ValidObjects",
+			false,
+			ParameterDefinition{
+				Type: "objects",
+				Min:  floatPtr(1),
+				Parameters: []ParameterDefinition{
+					{Name: "name", Type: "string", Required: true},
+					{Name: "kind", Type: "enum", ValidValues: []string{"latency", "status_code"}, Default: "latency"},
+					{Name: "threshold", Type: "int", Min: floatPtr(1)},
+					{
+						Name:       "codes",
+						Type:       "strings",
+						Required:   true,
+						RelevantIf: []RelevantIfCondition{{Name: "kind", Operator: "equals", Value: "status_code"}},
+					},
+				},
+			},
+			[]any{
+				map[string]any{"name": "slow", "threshold": 500},
+				map[any]any{"name": "errors", "kind": "status_code", "codes": []any{"ERROR"}},
+			},
+		},
+		{
+			"InvalidObjectsNotList",
+			true,
+			ParameterDefinition{
+				Type: "objects",
+				Min:  floatPtr(1),
+				Parameters: []ParameterDefinition{
+					{Name: "name", Type: "string", Required: true},
+					{Name: "kind", Type: "enum", ValidValues: []string{"latency", "status_code"}, Default: "latency"},
+					{Name: "threshold", Type: "int", Min: floatPtr(1)},
+					{
+						Name:       "codes",
+						Type:       "strings",
+						Required:   true,
+						RelevantIf: []RelevantIfCondition{{Name: "kind", Operator: "equals", Value: "status_code"}},
+					},
+				},
+			},
+			map[string]any{"name": "slow"},
+		},
+		{
+			"InvalidObjectsTooFew",
+			true,
+			ParameterDefinition{
+				Type: "objects",
+				Min:  floatPtr(1),
+				Parameters: []ParameterDefinition{
+					{Name: "name", Type: "string", Required: true},
+					{Name: "kind", Type: "enum", ValidValues: []string{"latency", "status_code"}, Default: "latency"},
+					{Name: "threshold", Type: "int", Min: floatPtr(1)},
+					{
+						Name:       "codes",
+						Type:       "strings",
+						Required:   true,
+						RelevantIf: []RelevantIfCondition{{Name: "kind", Operator: "equals", Value: "status_code"}},
+					},
+				},
+			},
+			[]any{},
+		},
+		{
+			"InvalidObjectsUndefinedField",
+			true,
+			ParameterDefinition{
+				Type: "objects",
+				Min:  floatPtr(1),
+				Parameters: []ParameterDefinition{
+					{Name: "name", Type: "string", Required: true},
+					{Name: "kind", Type: "enum", ValidValues: []string{"latency", "status_code"}, Default: "latency"},
+					{Name: "threshold", Type: "int", Min: floatPtr(1)},
+					{
+						Name:       "codes",
+						Type:       "strings",
+						Required:   true,
+						RelevantIf: []RelevantIfCondition{{Name: "kind", Operator: "equals", Value: "status_code"}},
+					},
+				},
+			},
+			[]any{
+				map[string]any{"name": "slow", "unknown": true},
+			},
+		},
+		{
+			"InvalidObjectsFieldValue",
+			true,
+			ParameterDefinition{
+				Type: "objects",
+				Min:  floatPtr(1),
+				Parameters: []ParameterDefinition{
+					{Name: "name", Type: "string", Required: true},
+					{Name: "kind", Type: "enum", ValidValues: []string{"latency", "status_code"}, Default: "latency"},
+					{Name: "threshold", Type: "int", Min: floatPtr(1)},
+					{
+						Name:       "codes",
+						Type:       "strings",
+						Required:   true,
+						RelevantIf: []RelevantIfCondition{{Name: "kind", Operator: "equals", Value: "status_code"}},
+					},
+				},
+			},
+			[]any{
+				map[string]any{"name": "slow", "threshold": 0},
+			},
+		},
+		{
+			"InvalidObjectsMissingRelevantRequiredField",
+			true,
+			ParameterDefinition{
+				Type: "objects",
+				Min:  floatPtr(1),
+				Parameters: []ParameterDefinition{
+					{Name: "name", Type: "string", Required: true},
+					{Name: "kind", Type: "enum", ValidValues: []string{"latency", "status_code"}, Default: "latency"},
+					{Name: "threshold", Type: "int", Min: floatPtr(1)},
+					{
+						Name:       "codes",
+						Type:       "strings",
+						Required:   true,
+						RelevantIf: []RelevantIfCondition{{Name: "kind", Operator: "equals", Value: "status_code"}},
+					},
+				},
+			},
+			[]any{
+				map[string]any{"name": "errors", "kind": "status_code"},
+			},
+		},
+		{
+			"InvalidObjectsMissingRequiredField",
+			true,
+			ParameterDefinition{
+				Type: "objects",
+				Min:  floatPtr(1),
+				Parameters: []ParameterDefinition{
+					{Name: "name", Type: "string", Required: true},
+					{Name: "kind", Type: "enum", ValidValues: []string{"latency", "status_code"}, Default: "latency"},
+					{Name: "threshold", Type: "int", Min: floatPtr(1)},
+					{
+						Name:       "codes",
+						Type:       "strings",
+						Required:   true,
+						RelevantIf: []RelevantIfCondition{{Name: "kind", Operator: "equals", Value: "status_code"}},
+					},
+				},
+			},
+			[]any{
+				map[string]any{"threshold": 100},
+			},
+		},
+		{
+			"StringsNotMatchingPattern",
+			true,
+			ParameterDefinition{
+				Type:    "strings",
+				Pattern: "^[a-z]+$",
+			},
+			[]any{"one", "Two"},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -531,3 +1050,144 @@ eleven:
 		})
 	}
 }
+
+func TestValidateObjectsValueErrorOrder(t *testing.T) {
+	param := ParameterDefinition{
+		Name: "policies",
+		Type: "objects",
+		Parameters: []ParameterDefinition{
+			{Name: "name", Type: "string"},
+			{Name: "threshold", Type: "int"},
+		},
+	}
+	value := []any{
+		map[string]any{"name": 1, "extra": true, "threshold": "high", "another": true},
+	}
+	for i := 0; i < 10; i++ {
+		err := param.validateValue(value)
+		require.EqualError(t, err, "4 errors occurred:\n"+
+			"\t* parameter value for 'policies[0]' has undefined field 'another'\n"+
+			"\t* parameter value for 'policies[0]' has undefined field 'extra'\n"+
+			"\t* parameter value for 'policies[0].name' must be a string\n"+
+			"\t* parameter value for 'policies[0].threshold' must be an integer\n\n")
+	}
+}
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
+
+func TestRelevantIfConditionSatisfied(t *testing.T) {
+	values := map[string]any{
+		"protocol":   "tcp",
+		"enable_tls": true,
+		"port":       "5140",
+		"attributes": []any{"host", "peer"},
+		"empty":      "",
+	}
+
+	testCases := []struct {
+		name      string
+		condition RelevantIfCondition
+		expect    bool
+	}{
+		{
+			"equals",
+			RelevantIfCondition{Name: "protocol", Operator: "equals", Value: "tcp"},
+			true,
+		},
+		{
+			"equals number as string",
+			RelevantIfCondition{Name: "port", Operator: "equals", Value: 5140},
+			true,
+		},
+		{
+			"equals missing parameter",
+			RelevantIfCondition{Name: "missing", Operator: "equals", Value: "tcp"},
+			false,
+		},
+		{
+			"notEquals",
+			RelevantIfCondition{Name: "protocol", Operator: "notEquals", Value: "udp"},
+			true,
+		},
+		{
+			"notEquals same value",
+			RelevantIfCondition{Name: "protocol", Operator: "notEquals", Value: "tcp"},
+			false,
+		},
+		{
+			"in",
+			RelevantIfCondition{Name: "protocol", Operator: "in", Value: []any{"udp", "tcp"}},
+			true,
+		},
+		{
+			"in not found",
+			RelevantIfCondition{Name: "protocol", Operator: "in", Value: []any{"udp", "unix"}},
+			false,
+		},
+		{
+			"containsAny",
+			RelevantIfCondition{Name: "attributes", Operator: "containsAny", Value: []any{"port", "peer"}},
+			true,
+		},
+		{
+			"containsAny not found",
+			RelevantIfCondition{Name: "attributes", Operator: "containsAny", Value: []any{"port"}},
+			false,
+		},
+		{
+			"exists",
+			RelevantIfCondition{Name: "protocol", Operator: "exists"},
+			true,
+		},
+		{
+			"exists empty",
+			RelevantIfCondition{Name: "empty", Operator: "exists"},
+			false,
+		},
+		{
+			"and",
+			RelevantIfCondition{Operator: "and", Conditions: []RelevantIfCondition{
+				{Name: "protocol", Operator: "equals", Value: "tcp"},
+				{Name: "enable_tls", Operator: "equals", Value: true},
+			}},
+			true,
+		},
+		{
+			"and not satisfied",
+			RelevantIfCondition{Operator: "and", Conditions: []RelevantIfCondition{
+				{Name: "protocol", Operator: "equals", Value: "tcp"},
+				{Name: "enable_tls", Operator: "equals", Value: false},
+			}},
+			false,
+		},
+		{
+			"or",
+			RelevantIfCondition{Operator: "or", Conditions: []RelevantIfCondition{
+				{Name: "protocol", Operator: "equals", Value: "udp"},
+				{Name: "enable_tls", Operator: "equals", Value: true},
+			}},
+			true,
+		},
+		{
+			"or not satisfied",
+			RelevantIfCondition{Operator: "or", Conditions: []RelevantIfCondition{
+				{Name: "protocol", Operator: "equals", Value: "udp"},
+				{Name: "empty", Operator: "exists"},
+			}},
+			false,
+		},
+		{
+			"unknown operator",
+			RelevantIfCondition{Name: "protocol", Operator: "greaterThan", Value: "tcp"},
+			false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expect, tc.condition.satisfied(values))
+		})
+	}
+}
@@ -71,6 +71,7 @@ func (s *ParameterizedSpec) validateTypeAndParameters(kind Kind, errors validati
 		Parameters: s.Parameters,
 		Processors: s.Processors,
 	}
+	errors = validation.WithField(errors, "spec")
 	rc.validateParameters(kind, errors, store)
 	rc.validateProcessors(kind, errors, store)
 }
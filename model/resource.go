@@ -15,6 +15,9 @@
 package model
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -259,14 +262,14 @@ func (r *ResourceMeta) validateIcon(kind Kind, errs validation.Errors) {
 }
 
 func (m *Metadata) validate(errs validation.Errors) {
-	validation.IsName(errs, m.Name)
-	m.Labels.validate(errs)
+	validation.IsName(validation.WithField(errs, "metadata.name"), m.Name)
+	m.Labels.validate(validation.WithField(errs, "metadata.labels"))
 }
 
 func validateKind(errors validation.Errors, kind string) {
 	// it's possible for parsed to be unmarshaled to a string that isn't a valid type
 	if parsed := ParseKind(string(kind)); parsed == KindUnknown {
-		errors.Add(fmt.Errorf("%s is not a valid resource kind", kind))
+		errors.Add(validation.NewFieldError("kind", fmt.Errorf("%s is not a valid resource kind", kind)))
 	}
 }
 
@@ -281,6 +284,25 @@ func ParseKind(kind string) Kind {
 	return KindUnknown
 }
 
+// kindDependencyOrder orders kinds so that resources come after the kinds of resources they can depend on
+var kindDependencyOrder = map[Kind]int{
+	KindSourceType:      0,
+	KindProcessorType:   0,
+	KindDestinationType: 0,
+	KindSource:          1,
+	KindProcessor:       1,
+	KindDestination:     1,
+	KindConfiguration:   2,
+	KindAgentVersion:    3,
+}
+
+// DependencyOrder returns the order in which resources of this kind must be applied so that they are applied after the
+// resources they depend on, e.g. types before sources and sources before configurations. Resources must be deleted in
+// the reverse order.
+func (k Kind) DependencyOrder() int {
+	return kindDependencyOrder[k]
+}
+
 // ParseResource maps the Spec of the provided resource to a specific type of Resource
 func ParseResource(r *AnyResource) (Resource, error) {
 	switch r.Kind {
@@ -404,6 +426,17 @@ func (r *ResourceMeta) PrintableFieldValue(title string) string {
 	}
 }
 
+// ResourceHash returns a hash of the JSON encoding of the resource. It is used to detect changes to a resource between
+// the time it was read and the time it is applied.
+func ResourceHash(r Resource) (string, error) {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
 // ----------------------------------------------------------------------
 // Indexed
 
@@ -44,6 +44,9 @@ type ResourceTypeSpec struct {
 	Parameters         []ParameterDefinition `json:"parameters"  yaml:"parameters"  mapstructure:"parameters"`
 	SupportedPlatforms []string              `json:"supportedPlatforms" yaml:"supportedPlatforms" mapstructure:"supportedPlatforms"`
 
+	// Rules are constraints across multiple parameters that are enforced when resources of this type are validated
+	Rules []ParameterRule `json:"rules,omitempty" yaml:"rules,omitempty" mapstructure:"rules"`
+
 	// individual
 	Logs    ResourceTypeOutput `json:"logs,omitempty"    yaml:"logs,omitempty"    mapstructure:"logs"`
 	Metrics ResourceTypeOutput `json:"metrics,omitempty" yaml:"metrics,omitempty" mapstructure:"metrics"`
@@ -126,12 +129,16 @@ func (rt *ResourceType) evalOutput(output *ResourceTypeOutput, resource paramete
 	// start with default parameters
 	for _, p := range rt.Spec.Parameters {
 		if p.Default != nil {
-			params[p.Name] = p.Default
+			params[p.Name] = p.templateValue(p.Default)
 		}
 	}
 	// resource can overrides the parameters
 	for _, p := range resource.ResourceParameters() {
-		params[p.Name] = p.Value
+		if def := rt.Spec.ParameterDefinition(p.Name); def != nil {
+			params[p.Name] = def.templateValue(p.Value)
+		} else {
+			params[p.Name] = p.Value
+		}
 	}
 	// eval all of the components
 	return &otel.Partial{
@@ -198,7 +205,7 @@ func (rt *ResourceType) Validate() (warnings string, errors error) {
 	errs := validation.NewErrors()
 
 	rt.ResourceMeta.validate(errs)
-	rt.Spec.validate(rt.Kind, errs)
+	rt.Spec.validate(rt.Kind, validation.WithField(errs, "spec"))
 
 	return errs.Warnings(), errs.Result()
 }
@@ -210,32 +217,19 @@ func (rt *ResourceType) ValidateWithStore(store ResourceStore) (warnings string,
 
 func (s *ResourceTypeSpec) validate(kind Kind, errs validation.Errors) {
 	s.validateParameterDefinitions(kind, errs)
+	s.validateRules(errs)
 
 	// assemble default parameter values for validation
 	params := map[string]any{}
 	for _, p := range s.Parameters {
 		if p.Default != nil {
-			params[p.Name] = p.Default
+			params[p.Name] = p.templateValue(p.Default)
+		} else if p.Type == objectsType {
+			// for template validation, provide a single object so that templates within range are executed
+			params[p.Name] = p.templateValue([]any{map[string]any{}})
 		} else {
 			// for template validation, just provide a reasonable default based on the type
-			switch p.Type {
-			case boolType:
-				params[p.Name] = false
-			case enumType:
-				params[p.Name] = "" // p.ValidValues[0] // cannot guarantee this is valid and "" is fine
-			case enumsType:
-				params[p.Name] = []string{}
-			case intType:
-				params[p.Name] = 0
-			case mapType:
-				params[p.Name] = make(map[string]string)
-			case stringType:
-				params[p.Name] = ""
-			case stringsType:
-				params[p.Name] = []string{}
-			case yamlType:
-				params[p.Name] = ""
-			}
+			params[p.Name] = p.templateDefaultValue()
 		}
 	}
 
@@ -245,34 +239,85 @@ func (s *ResourceTypeSpec) validate(kind Kind, errs validation.Errors) {
 }
 
 func (s *ResourceTypeSpec) validateParameterDefinitions(kind Kind, errs validation.Errors) {
-	for _, parameter := range s.Parameters {
-		parameter.validateDefinition(kind, errs)
-		s.validateParameterRelevantIf(parameter, errs)
+	for i, parameter := range s.Parameters {
+		parameterErrs := validation.WithField(errs, fmt.Sprintf("parameters[%d]", i))
+		parameter.validateDefinition(kind, parameterErrs)
+		s.validateParameterRelevantIf(parameter, parameterErrs)
 	}
 }
 
 // validateParameterRelevantIf in ResourceTypeSpec because we need to check against other parameter names
 func (s *ResourceTypeSpec) validateParameterRelevantIf(parameter ParameterDefinition, errs validation.Errors) {
-	for _, relevantIf := range parameter.RelevantIf {
-		if relevantIf.Name == "" {
-			errs.Add(fmt.Errorf("relevantIf for '%s' must have a name", parameter.Name))
+	s.validateConditions("relevantIf", parameter.Name, parameter.RelevantIf, errs)
+}
+
+// validateConditions validates relevantIf conditions and the conditions used by rules. field and owner are used to
+// identify the conditions in error messages.
+func (s *ResourceTypeSpec) validateConditions(field string, owner string, conditions []RelevantIfCondition, errs validation.Errors) {
+	for _, condition := range conditions {
+		if condition.isGroup() {
+			if len(condition.Conditions) == 0 {
+				errs.Add(fmt.Errorf("%s '%s' for '%s' must have conditions", field, condition.Operator, owner))
+				continue
+			}
+			s.validateConditions(field, owner, condition.Conditions, errs)
+			continue
+		}
+		if condition.Name == "" {
+			errs.Add(fmt.Errorf("%s for '%s' must have a name", field, owner))
 			continue
 		}
-		ref := s.ParameterDefinition(relevantIf.Name)
+		ref := s.ParameterDefinition(condition.Name)
 		if ref == nil {
-			errs.Add(fmt.Errorf("relevantIf for '%s' refers to nonexistant parameter '%s'", parameter.Name, relevantIf.Name))
+			errs.Add(fmt.Errorf("%s for '%s' refers to nonexistant parameter '%s'", field, owner, condition.Name))
 			continue
 		}
-		if relevantIf.Operator == "" {
-			errs.Add(fmt.Errorf("relevantIf '%s' for '%s' must have an operator", ref.Name, parameter.Name))
+		switch condition.Operator {
+		case "":
+			errs.Add(fmt.Errorf("%s '%s' for '%s' must have an operator", field, ref.Name, owner))
+		case RelevantIfOperatorEquals, RelevantIfOperatorNotEquals:
+		case RelevantIfOperatorExists:
+			// exists does not use the value
+			continue
+		case RelevantIfOperatorIn:
+			s.validateConditionValues(field, owner, condition, ref, errs)
+			continue
+		case RelevantIfOperatorContainsAny:
+			if ref.Type != stringsType && ref.Type != enumsType {
+				errs.Add(fmt.Errorf("%s '%s' for '%s' uses containsAny with parameter of type '%s'", field, ref.Name, owner, ref.Type))
+				continue
+			}
+			s.validateConditionValues(field, owner, condition, nil, errs)
+			continue
+		default:
+			errs.Add(fmt.Errorf("%s '%s' for '%s' has unsupported operator '%s'", field, ref.Name, owner, condition.Operator))
+			continue
 		}
-		if relevantIf.Value == nil {
-			errs.Add(fmt.Errorf("relevantIf '%s' for '%s' must have a value", ref.Name, parameter.Name))
+		if condition.Value == nil {
+			errs.Add(fmt.Errorf("%s '%s' for '%s' must have a value", field, ref.Name, owner))
 			continue
 		}
-		err := ref.validateValueType(parameterFieldRelevantIf, relevantIf.Value)
+		err := ref.validateValueType(parameterFieldRelevantIf, condition.Value)
 		if err != nil {
-			errs.Add(fmt.Errorf("relevantIf '%s' for '%s': %w", ref.Name, parameter.Name, err))
+			errs.Add(fmt.Errorf("%s '%s' for '%s': %w", field, ref.Name, owner, err))
+		}
+	}
+}
+
+// validateConditionValues validates conditions with operators that expect a list of values. If ref is specified, each
+// value must be a valid value for the referenced parameter.
+func (s *ResourceTypeSpec) validateConditionValues(field string, owner string, condition RelevantIfCondition, ref *ParameterDefinition, errs validation.Errors) {
+	values := listValues(condition.Value)
+	if len(values) == 0 {
+		errs.Add(fmt.Errorf("%s '%s' for '%s' must have a list of values", field, condition.Name, owner))
+		return
+	}
+	if ref == nil {
+		return
+	}
+	for _, value := range values {
+		if err := ref.validateValueType(parameterFieldRelevantIf, value); err != nil {
+			errs.Add(fmt.Errorf("%s '%s' for '%s': %w", field, ref.Name, owner, err))
 		}
 	}
 }
@@ -0,0 +1,127 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/observiq/bindplane-op/model/otel"
+)
+
+// RenderResourceType renders the SourceType, ProcessorType, or DestinationType with the specified parameters, returning
+// the partial configuration for each type of telemetry. The parameters are validated against the parameter definitions
+// and rules of the ResourceType. Any template errors are returned and the rendered components are checked to ensure
+// that they are well-formed OpenTelemetry configuration.
+func RenderResourceType(resourceType Resource, name string, parameters []Parameter) (otel.Partials, error) {
+	store := &resourceTypeStore{}
+	var rt *ResourceType
+	var resource interface {
+		parameterizedResource
+		ValidateWithStore(store ResourceStore) (warnings string, errors error)
+	}
+	var required func(p *otel.Partial) error
+
+	switch r := resourceType.(type) {
+	case *SourceType:
+		store.sourceType = r
+		rt = &r.ResourceType
+		resource = NewSource(name, r.Name(), parameters)
+		required = requireComponents("receivers", func(p *otel.Partial) otel.ComponentList { return p.Receivers })
+	case *ProcessorType:
+		store.processorType = r
+		rt = &r.ResourceType
+		resource = NewProcessor(name, r.Name(), parameters)
+		required = requireComponents("processors", func(p *otel.Partial) otel.ComponentList { return p.Processors })
+	case *DestinationType:
+		store.destinationType = r
+		rt = &r.ResourceType
+		resource = NewDestination(name, r.Name(), parameters)
+		required = requireComponents("exporters", func(p *otel.Partial) otel.ComponentList { return p.Exporters })
+	default:
+		return nil, fmt.Errorf("%s %s is not a SourceType, ProcessorType, or DestinationType", resourceType.GetKind(), resourceType.Name())
+	}
+
+	if _, err := resource.ValidateWithStore(store); err != nil {
+		return nil, err
+	}
+
+	var errs error
+	partials := rt.eval(resource, func(err error) {
+		errs = multierror.Append(errs, err)
+	})
+	if errs != nil {
+		return nil, errs
+	}
+
+	for _, pipelineType := range []otel.PipelineType{otel.Logs, otel.Metrics, otel.Traces} {
+		partial := partials[pipelineType]
+		if partial.Size() == 0 {
+			continue
+		}
+		if err := partial.Validate(); err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("%s: %w", pipelineType, err))
+		}
+		if err := required(partial); err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("%s: %w", pipelineType, err))
+		}
+	}
+	if errs != nil {
+		return nil, errs
+	}
+
+	return partials, nil
+}
+
+// requireComponents returns a function that requires at least one of the components provided by list
+func requireComponents(name string, list func(p *otel.Partial) otel.ComponentList) func(p *otel.Partial) error {
+	return func(p *otel.Partial) error {
+		if len(list(p)) == 0 {
+			return fmt.Errorf("must render at least one of %s", name)
+		}
+		return nil
+	}
+}
+
+// resourceTypeStore is a ResourceStore that contains a single ResourceType
+type resourceTypeStore struct {
+	sourceType      *SourceType
+	processorType   *ProcessorType
+	destinationType *DestinationType
+}
+
+var _ ResourceStore = (*resourceTypeStore)(nil)
+
+func (s *resourceTypeStore) Source(name string) (*Source, error) { return nil, nil }
+func (s *resourceTypeStore) SourceType(name string) (*SourceType, error) {
+	if s.sourceType != nil && s.sourceType.Name() == name {
+		return s.sourceType, nil
+	}
+	return nil, nil
+}
+func (s *resourceTypeStore) Processor(name string) (*Processor, error) { return nil, nil }
+func (s *resourceTypeStore) ProcessorType(name string) (*ProcessorType, error) {
+	if s.processorType != nil && s.processorType.Name() == name {
+		return s.processorType, nil
+	}
+	return nil, nil
+}
+func (s *resourceTypeStore) Destination(name string) (*Destination, error) { return nil, nil }
+func (s *resourceTypeStore) DestinationType(name string) (*DestinationType, error) {
+	if s.destinationType != nil && s.destinationType.Name() == name {
+		return s.destinationType, nil
+	}
+	return nil, nil
+}
@@ -71,6 +71,178 @@ func TestEvalGoogleCloud(t *testing.T) {
 	require.Len(t, values[otel.Traces].Extensions, 0)
 }
 
+func TestEvalSamplingProcessor(t *testing.T) {
+	pt := fileResource[*ProcessorType](t, "testfiles/processortype-sampling.yaml")
+	_, err := pt.Validate()
+	require.NoError(t, err)
+
+	tests := []struct {
+		description string
+		parameters  []Parameter
+		expectYaml  string
+	}{
+		{
+			description: "defaults",
+			parameters:  nil,
+			expectYaml: `
+- logstransform/sampling__sampling:
+    operators:
+        - drop_ratio: 0.5
+          expr: body matches ".*"
+          flush_interval: 5s
+          type: filter
+`,
+		},
+		{
+			description: "float provided as a string",
+			parameters: []Parameter{
+				{Name: "drop_ratio", Value: "0.25"},
+				{Name: "flush_interval", Value: "30s"},
+			},
+			expectYaml: `
+- logstransform/sampling__sampling:
+    operators:
+        - drop_ratio: 0.25
+          expr: body matches ".*"
+          flush_interval: 30s
+          type: filter
+`,
+		},
+		{
+			description: "float provided as an int",
+			parameters: []Parameter{
+				{Name: "drop_ratio", Value: 1},
+			},
+			expectYaml: `
+- logstransform/sampling__sampling:
+    operators:
+        - drop_ratio: 1
+          expr: body matches ".*"
+          flush_interval: 5s
+          type: filter
+`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			p := NewProcessor("sampling", "sampling", test.parameters)
+			values := pt.evalOutput(&pt.Spec.Logs, p, func(e error) {
+				require.NoError(t, e)
+			})
+			require.Len(t, values.Processors, 1)
+
+			processorsYaml, err := yaml.Marshal(values.Processors)
+			require.NoError(t, err)
+			require.Equal(t, strings.TrimLeft(test.expectYaml, "\n"), string(processorsYaml))
+		})
+	}
+}
+
+func TestRenderResourceType(t *testing.T) {
+	sampling := fileResource[*ProcessorType](t, "testfiles/processortype-sampling.yaml")
+	cabin := fileResource[*DestinationType](t, "testfiles/destinationtype-cabin.yaml")
+
+	tests := []struct {
+		description  string
+		resourceType Resource
+		parameters   []Parameter
+		expectYaml   string
+		expectError  string
+	}{
+		{
+			description:  "processor type",
+			resourceType: sampling,
+			parameters: []Parameter{
+				{Name: "drop_ratio", Value: 0.25},
+			},
+			expectYaml: `
+- logstransform/sampling__sampling:
+    operators:
+        - drop_ratio: 0.25
+          expr: body matches ".*"
+          flush_interval: 5s
+          type: filter
+`,
+		},
+		{
+			description:  "invalid parameter",
+			resourceType: sampling,
+			parameters: []Parameter{
+				{Name: "drop_ratio", Value: 2},
+			},
+			expectError: "parameter value for 'drop_ratio' must be at most 1",
+		},
+		{
+			description:  "template error",
+			resourceType: cabin,
+			expectError:  `map has no entry for key "secret_key"`,
+		},
+		{
+			description:  "not a resource type",
+			resourceType: NewSource("sampling", "sampling", nil),
+			expectError:  "Source sampling is not a SourceType, ProcessorType, or DestinationType",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			partials, err := RenderResourceType(test.resourceType, test.resourceType.Name(), test.parameters)
+			if test.expectError != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), test.expectError)
+				return
+			}
+			require.NoError(t, err)
+
+			processorsYaml, err := yaml.Marshal(partials[otel.Logs].Processors)
+			require.NoError(t, err)
+			require.Equal(t, strings.TrimLeft(test.expectYaml, "\n"), string(processorsYaml))
+		})
+	}
+}
+
+func TestEvalTailSamplingProcessor(t *testing.T) {
+	pt := fileResource[*ProcessorType](t, "testfiles/processortype-tail-sampling.yaml")
+	_, err := pt.Validate()
+	require.NoError(t, err)
+
+	p := fileResource[*Processor](t, "testfiles/processor-tail-sampling.yaml")
+	_, err = p.ValidateWithStore(&testResourceStore{
+		processorTypes: map[string]*ProcessorType{pt.Name(): pt},
+	})
+	require.NoError(t, err)
+
+	values := pt.evalOutput(&pt.Spec.Traces, p, func(e error) {
+		require.NoError(t, e)
+	})
+	require.Len(t, values.Processors, 1)
+
+	processorsYaml, err := yaml.Marshal(values.Processors)
+	require.NoError(t, err)
+
+	expectYaml := strings.TrimLeft(`
+- tail_sampling/tail_sampling__tail-sampling:
+    decision_wait: 30s
+    policies:
+        - latency:
+            threshold_ms: 500
+          name: slow
+          type: latency
+        - name: errors
+          status_code:
+            status_codes:
+                - ERROR
+          type: status_code
+        - name: everything-else
+          probabilistic:
+            sampling_percentage: 2.5
+          type: probabilistic
+`, "\n")
+
+	require.Equal(t, expectYaml, string(processorsYaml))
+}
+
 func TestTelemetryTypes(t *testing.T) {
 	macosSourceType := fileResource[*SourceType](t, "testfiles/sourcetype-macos.yaml")
 	otlpSourceType := fileResource[*SourceType](t, "testfiles/sourcetype-otlp.yaml")
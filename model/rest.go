@@ -22,6 +22,8 @@ type AgentResponse struct {
 // AgentsResponse is the REST API response to GET /v1/agents endpoint.
 type AgentsResponse struct {
 	Agents []*Agent `json:"agents"`
+	// Continue is the token used to request the next page of results. It is empty if there are no more results.
+	Continue string `json:"continue,omitempty"`
 }
 
 // DeleteAgentsPayload is the REST API body to DELETE /v1/agents
@@ -58,6 +60,8 @@ type BulkAgentLabelsResponse struct {
 // AgentVersionsResponse is the REST API response to GET /v1/agent-versions
 type AgentVersionsResponse struct {
 	AgentVersions []*AgentVersion `json:"agentVersions"`
+	// Continue is the token used to request the next page of results. It is empty if there are no more results.
+	Continue string `json:"continue,omitempty"`
 }
 
 // AgentVersionResponse is the REST API response to GET /v1/agent-versions/:name
@@ -68,6 +72,8 @@ type AgentVersionResponse struct {
 // ConfigurationsResponse is the REST API response to GET /v1/configurations
 type ConfigurationsResponse struct {
 	Configurations []*Configuration `json:"configurations"`
+	// Continue is the token used to request the next page of results. It is empty if there are no more results.
+	Continue string `json:"continue,omitempty"`
 }
 
 // ConfigurationResponse is the REST API response to GET /v1/configuration/:name
@@ -79,6 +85,8 @@ type ConfigurationResponse struct {
 // SourcesResponse is the REST API response to GET /v1/sources
 type SourcesResponse struct {
 	Sources []*Source `json:"sources"`
+	// Continue is the token used to request the next page of results. It is empty if there are no more results.
+	Continue string `json:"continue,omitempty"`
 }
 
 // SourceResponse is the REST API response to GET /v1/sources/:name
@@ -89,6 +97,8 @@ type SourceResponse struct {
 // SourceTypesResponse is the REST API response to GET /v1/sourceTypes
 type SourceTypesResponse struct {
 	SourceTypes []*SourceType `json:"sourceTypes"`
+	// Continue is the token used to request the next page of results. It is empty if there are no more results.
+	Continue string `json:"continue,omitempty"`
 }
 
 // SourceTypeResponse is the REST API response to GET /v1/sourceType/:name
@@ -99,6 +109,8 @@ type SourceTypeResponse struct {
 // ProcessorsResponse is the REST API response to GET /v1/processors
 type ProcessorsResponse struct {
 	Processors []*Processor `json:"processors"`
+	// Continue is the token used to request the next page of results. It is empty if there are no more results.
+	Continue string `json:"continue,omitempty"`
 }
 
 // ProcessorResponse is the REST API response to GET /v1/processors/:name
@@ -109,6 +121,8 @@ type ProcessorResponse struct {
 // ProcessorTypesResponse is the REST API response to GET /v1/processorTypes
 type ProcessorTypesResponse struct {
 	ProcessorTypes []*ProcessorType `json:"processorTypes"`
+	// Continue is the token used to request the next page of results. It is empty if there are no more results.
+	Continue string `json:"continue,omitempty"`
 }
 
 // ProcessorTypeResponse is the REST API response to GET /v1/processorType/:name
@@ -119,6 +133,8 @@ type ProcessorTypeResponse struct {
 // DestinationsResponse is the REST API response to GET /v1/destinations
 type DestinationsResponse struct {
 	Destinations []*Destination `json:"destinations"`
+	// Continue is the token used to request the next page of results. It is empty if there are no more results.
+	Continue string `json:"continue,omitempty"`
 }
 
 // DestinationResponse is the REST API response to GET /v1/destinations/:name
@@ -129,6 +145,8 @@ type DestinationResponse struct {
 // DestinationTypesResponse is the REST API response to GET /v1/destinationTypes
 type DestinationTypesResponse struct {
 	DestinationTypes []*DestinationType `json:"destinationTypes"`
+	// Continue is the token used to request the next page of results. It is empty if there are no more results.
+	Continue string `json:"continue,omitempty"`
 }
 
 // DestinationTypeResponse is the REST API response to GET /v1/destinationType/:name
@@ -136,6 +154,11 @@ type DestinationTypeResponse struct {
 	DestinationType *DestinationType `json:"destinationType"`
 }
 
+// ResourceUsageResponse is the REST API response to GET /v1/{resources}/:name/usage
+type ResourceUsageResponse struct {
+	Usage *ResourceUsage `json:"usage"`
+}
+
 // ApplyResponse is the REST API response to POST /v1/apply.  This is used on
 // the server side to return updates consisting of generic ResourceStatuses.
 type ApplyResponse struct {
@@ -151,6 +174,17 @@ type ApplyResponseClientSide struct {
 // ApplyPayload is the REST API body for POST /v1/apply
 type ApplyPayload struct {
 	Resources []*AnyResource `json:"resources"`
+	// Preconditions are checked before the resources are applied. If any of them fail, no resources are applied and
+	// the response is 409 Conflict.
+	Preconditions []ResourcePrecondition `json:"preconditions,omitempty"`
+}
+
+// ResourcePrecondition requires a resource on the server to be unchanged since it was read
+type ResourcePrecondition struct {
+	Kind Kind   `json:"kind"`
+	Name string `json:"name"`
+	// Hash is the ResourceHash of the resource when it was read
+	Hash string `json:"hash"`
 }
 
 // DeletePayload is the REST API body for POST /v1/delete.  Though resources
@@ -197,6 +231,98 @@ type PostCopyConfigRequest struct {
 // PostCopyConfigResponse is the REST API response to PUT /v1/configurations/{name}/copy
 type PostCopyConfigResponse = PostCopyConfigRequest
 
+// EnrollmentTokensResponse is the REST API response to GET /v1/enrollment-tokens
+type EnrollmentTokensResponse struct {
+	EnrollmentTokens []*EnrollmentToken `json:"enrollmentTokens"`
+}
+
+// PostEnrollmentTokenRequest is the REST API body for POST /v1/enrollment-tokens
+type PostEnrollmentTokenRequest struct {
+	// Labels are applied to every agent enrolled with the token
+	Labels map[string]string `json:"labels,omitempty"`
+	// MaxUses is the maximum number of agents that can enroll with the token. 0 is unlimited.
+	MaxUses int `json:"maxUses,omitempty"`
+	// ExpiresIn is the duration until the token expires, e.g. 24h. If empty, the token does not expire.
+	ExpiresIn string `json:"expiresIn,omitempty"`
+}
+
+// EnrollmentTokenResponse is the REST API response to POST /v1/enrollment-tokens
+type EnrollmentTokenResponse struct {
+	EnrollmentToken *EnrollmentToken `json:"enrollmentToken"`
+	// Token is the value given to agents to enroll. It is only available when the token is created.
+	Token string `json:"token,omitempty"`
+}
+
+// PostApproveAgentsRequest is the REST API body for POST /v1/agents/approve. Pending agents with the specified IDs and
+// pending agents matching the selector and query are approved.
+type PostApproveAgentsRequest struct {
+	IDs []string `json:"ids,omitempty"`
+	// Selector is a label selector, e.g. env=prod
+	Selector string `json:"selector,omitempty"`
+	// Query is a search query, e.g. hostname:web-1
+	Query string `json:"query,omitempty"`
+}
+
+// ApproveAgentsResponse is the REST API response to POST /v1/agents/approve
+type ApproveAgentsResponse struct {
+	// Approved are the IDs of the agents that were approved
+	Approved []string `json:"approved"`
+}
+
+// AgentDenyRulesResponse is the REST API response to GET /v1/agent-deny-rules
+type AgentDenyRulesResponse struct {
+	Rules []*AgentDenyRule `json:"rules"`
+}
+
+// PostAgentDenyRuleRequest is the REST API body for POST /v1/agent-deny-rules
+type PostAgentDenyRuleRequest struct {
+	// Field is the agent field to match, one of id, hostname, or macAddress
+	Field AgentDenyField `json:"field"`
+	Value string         `json:"value"`
+	// Reason is an optional description of why the agent is denied
+	Reason string `json:"reason,omitempty"`
+}
+
+// AgentDenyRuleResponse is the REST API response to POST /v1/agent-deny-rules
+type AgentDenyRuleResponse struct {
+	Rule *AgentDenyRule `json:"rule"`
+	// Disconnected are the IDs of the agents that were disconnected because they match the rule
+	Disconnected []string `json:"disconnected"`
+}
+
+// UserSessionsResponse is the REST API response to GET /v1/sessions
+type UserSessionsResponse struct {
+	Sessions []*UserSession `json:"sessions"`
+}
+
+// PostRevokeUserSessionsRequest is the REST API body for POST /v1/sessions/revoke
+type PostRevokeUserSessionsRequest struct {
+	// User is the user whose sessions are revoked
+	User string `json:"user"`
+}
+
+// RevokeUserSessionsResponse is the REST API response to POST /v1/sessions/revoke
+type RevokeUserSessionsResponse struct {
+	// Revoked are the IDs of the sessions that were revoked
+	Revoked []string `json:"revoked"`
+}
+
+// GitOpsSyncResponse is the REST API response to GET /v1/gitops/sync
+type GitOpsSyncResponse struct {
+	Status *GitOpsSyncStatus `json:"status"`
+}
+
+// OIDCConfigResponse is the response to GET /oidc/config. It contains the OpenID Connect provider configuration used
+// by bindplanectl login.
+type OIDCConfigResponse struct {
+	Issuer   string `json:"issuer"`
+	ClientID string `json:"clientID"`
+	// DeviceAuthorizationEndpoint is empty if the provider does not support the device authorization flow
+	DeviceAuthorizationEndpoint string   `json:"deviceAuthorizationEndpoint,omitempty"`
+	TokenEndpoint               string   `json:"tokenEndpoint"`
+	Scopes                      []string `json:"scopes"`
+}
+
 // ErrorResponse is the expected response when receiving non 2xx status codes.
 type ErrorResponse struct {
 	Errors []string `json:"errors"`
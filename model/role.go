@@ -0,0 +1,55 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+// Role determines what a user is allowed to do in BindPlane
+type Role string
+
+const (
+	// RoleAdmin can view and modify all resources. Users logging in with the configured username and password are
+	// admins.
+	RoleAdmin Role = "admin"
+
+	// RoleViewer can view resources but not modify them
+	RoleViewer Role = "viewer"
+)
+
+// roleRank orders roles by access, higher has more access
+var roleRank = map[Role]int{
+	RoleViewer: 1,
+	RoleAdmin:  2,
+}
+
+// Valid returns true if the role is one of the known roles
+func (r Role) Valid() bool {
+	_, ok := roleRank[r]
+	return ok
+}
+
+// CanWrite returns true if the role can modify resources
+func (r Role) CanWrite() bool {
+	return r == RoleAdmin
+}
+
+// HighestRole returns the role with the most access or an empty role if none of the roles are valid
+func HighestRole(roles ...Role) Role {
+	var highest Role
+	for _, role := range roles {
+		if roleRank[role] > roleRank[highest] {
+			highest = role
+		}
+	}
+	return highest
+}
@@ -0,0 +1,58 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHighestRole(t *testing.T) {
+	tests := []struct {
+		name   string
+		roles  []Role
+		expect Role
+	}{
+		{
+			name:   "none",
+			expect: "",
+		},
+		{
+			name:   "invalid",
+			roles:  []Role{"owner"},
+			expect: "",
+		},
+		{
+			name:   "viewer",
+			roles:  []Role{RoleViewer, "owner"},
+			expect: RoleViewer,
+		},
+		{
+			name:   "admin",
+			roles:  []Role{RoleViewer, RoleAdmin, RoleViewer},
+			expect: RoleAdmin,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expect, HighestRole(test.roles...))
+		})
+	}
+
+	require.True(t, RoleAdmin.CanWrite())
+	require.False(t, RoleViewer.CanWrite())
+	require.False(t, Role("owner").Valid())
+}
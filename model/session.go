@@ -0,0 +1,124 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// UserSession is the server side record of a user logged in to the UI. The session cookie only contains the ID of the
+// record, so deleting the record revokes the session on every BindPlane node.
+type UserSession struct {
+	ID   string `json:"id" yaml:"id"`
+	User string `json:"user" yaml:"user"`
+	Role Role   `json:"role" yaml:"role"`
+
+	RemoteAddress string `json:"remoteAddress,omitempty" yaml:"remoteAddress,omitempty"`
+	UserAgent     string `json:"userAgent,omitempty" yaml:"userAgent,omitempty"`
+
+	CreatedAt  time.Time `json:"createdAt" yaml:"createdAt"`
+	LastSeenAt time.Time `json:"lastSeenAt" yaml:"lastSeenAt"`
+	// ExpiresAt is the time at which the session expires regardless of activity
+	ExpiresAt time.Time `json:"expiresAt" yaml:"expiresAt"`
+	// IdleTimeout is the time after LastSeenAt at which the session expires
+	IdleTimeout time.Duration `json:"idleTimeout" yaml:"idleTimeout"`
+
+	// CSRFHash is the hex-encoded SHA-256 hash of the CSRF token that must accompany requests that modify resources
+	CSRFHash string `json:"csrfHash,omitempty" yaml:"csrfHash,omitempty"`
+}
+
+var _ Printable = (*UserSession)(nil)
+
+// NewUserSession creates a new UserSession and returns it with the CSRF token given to the browser
+func NewUserSession(user string, role Role, timeout, idleTimeout time.Duration) (*UserSession, string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, "", fmt.Errorf("generate session id: %w", err)
+	}
+	csrfToken, csrfHash, err := newSecret()
+	if err != nil {
+		return nil, "", err
+	}
+	now := time.Now().UTC()
+	return &UserSession{
+		ID:          hex.EncodeToString(id),
+		User:        user,
+		Role:        role,
+		CreatedAt:   now,
+		LastSeenAt:  now,
+		ExpiresAt:   now.Add(timeout),
+		IdleTimeout: idleTimeout,
+		CSRFHash:    csrfHash,
+	}, csrfToken, nil
+}
+
+// Expired returns true if the session has reached its absolute timeout or has been idle longer than its idle timeout
+func (s *UserSession) Expired(now time.Time) bool {
+	if !now.Before(s.ExpiresAt) {
+		return true
+	}
+	return s.IdleTimeout > 0 && !now.Before(s.LastSeenAt.Add(s.IdleTimeout))
+}
+
+// VerifyCSRF returns true if the token is the CSRF token of the session
+func (s *UserSession) VerifyCSRF(token string) bool {
+	return verifySecret(token, s.CSRFHash)
+}
+
+// Redacted returns a copy of the session without the CSRFHash, suitable for API responses
+func (s *UserSession) Redacted() *UserSession {
+	redacted := *s
+	redacted.CSRFHash = ""
+	return &redacted
+}
+
+// PrintableKindSingular returns the singular form of the Kind, e.g. "Configuration"
+func (s *UserSession) PrintableKindSingular() string {
+	return "UserSession"
+}
+
+// PrintableKindPlural returns the plural form of the Kind, e.g. "Configurations"
+func (s *UserSession) PrintableKindPlural() string {
+	return "UserSessions"
+}
+
+// PrintableFieldTitles returns the list of field titles, used for printing a table of resources
+func (s *UserSession) PrintableFieldTitles() []string {
+	return []string{"ID", "User", "Role", "Address", "Created", "Last Seen", "Expires"}
+}
+
+// PrintableFieldValue returns the field value for a title, used for printing a table of resources
+func (s *UserSession) PrintableFieldValue(title string) string {
+	switch title {
+	case "ID":
+		return s.ID
+	case "User":
+		return s.User
+	case "Role":
+		return string(s.Role)
+	case "Address":
+		return s.RemoteAddress
+	case "Created":
+		return s.CreatedAt.Format(time.RFC3339)
+	case "Last Seen":
+		return s.LastSeenAt.Format(time.RFC3339)
+	case "Expires":
+		return s.ExpiresAt.Format(time.RFC3339)
+	}
+	return ""
+}
@@ -0,0 +1,86 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewUserSession(t *testing.T) {
+	session, csrfToken, err := NewUserSession("user", RoleViewer, 8*time.Hour, time.Hour)
+	require.NoError(t, err)
+	require.NotEmpty(t, session.ID)
+	require.Equal(t, "user", session.User)
+	require.Equal(t, RoleViewer, session.Role)
+	require.Equal(t, session.CreatedAt.Add(8*time.Hour), session.ExpiresAt)
+
+	require.True(t, session.VerifyCSRF(csrfToken))
+	require.False(t, session.VerifyCSRF(""))
+	require.False(t, session.VerifyCSRF("other"))
+	require.Empty(t, session.Redacted().CSRFHash)
+	require.NotEmpty(t, session.CSRFHash)
+
+	other, _, err := NewUserSession("user", RoleViewer, 8*time.Hour, time.Hour)
+	require.NoError(t, err)
+	require.NotEqual(t, session.ID, other.ID)
+}
+
+func TestUserSessionExpired(t *testing.T) {
+	now := time.Date(2022, 9, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		lastSeenAt  time.Time
+		expiresAt   time.Time
+		idleTimeout time.Duration
+		expect      bool
+	}{
+		{
+			name:        "active",
+			lastSeenAt:  now.Add(-time.Minute),
+			expiresAt:   now.Add(time.Hour),
+			idleTimeout: time.Hour,
+		},
+		{
+			name:        "idle",
+			lastSeenAt:  now.Add(-time.Hour),
+			expiresAt:   now.Add(time.Hour),
+			idleTimeout: time.Hour,
+			expect:      true,
+		},
+		{
+			name:        "absolute timeout",
+			lastSeenAt:  now,
+			expiresAt:   now,
+			idleTimeout: time.Hour,
+			expect:      true,
+		},
+		{
+			name:       "no idle timeout",
+			lastSeenAt: now.Add(-24 * time.Hour),
+			expiresAt:  now.Add(time.Hour),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			session := &UserSession{LastSeenAt: test.lastSeenAt, ExpiresAt: test.expiresAt, IdleTimeout: test.idleTimeout}
+			require.Equal(t, test.expect, session.Expired(now))
+		})
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ResourceUsage contains the names of the resources that use a resource, directly or transitively, and the IDs of the
+// agents with a configuration that uses it. For example, a DestinationType is used by the Destinations of that type, the
+// Configurations that use those Destinations or use the DestinationType directly, and the Agents with those
+// Configurations.
+type ResourceUsage struct {
+	Kind           Kind     `json:"kind" yaml:"kind"`
+	Name           string   `json:"name" yaml:"name"`
+	Sources        []string `json:"sources" yaml:"sources"`
+	Processors     []string `json:"processors" yaml:"processors"`
+	Destinations   []string `json:"destinations" yaml:"destinations"`
+	Configurations []string `json:"configurations" yaml:"configurations"`
+	Agents         []string `json:"agents" yaml:"agents"`
+}
+
+// InUse returns true if any resources or agents use the resource
+func (u *ResourceUsage) InUse() bool {
+	return len(u.Sources)+len(u.Processors)+len(u.Destinations)+len(u.Configurations)+len(u.Agents) > 0
+}
+
+// ----------------------------------------------------------------------
+// Printable
+
+// PrintableKindSingular returns the singular form of the Kind, e.g. "Configuration"
+func (u *ResourceUsage) PrintableKindSingular() string {
+	return "Usage"
+}
+
+// PrintableKindPlural returns the plural form of the Kind, e.g. "Configurations"
+func (u *ResourceUsage) PrintableKindPlural() string {
+	return "Usage"
+}
+
+// PrintableFieldTitles returns the list of field titles, used for printing a table of resources
+func (u *ResourceUsage) PrintableFieldTitles() []string {
+	return []string{"Kind", "Name", "Sources", "Processors", "Destinations", "Configurations", "Agents"}
+}
+
+// PrintableFieldValue returns the field value for a title, used for printing a table of resources. Agents are counted
+// because there may be thousands of them.
+func (u *ResourceUsage) PrintableFieldValue(title string) string {
+	switch title {
+	case "Kind":
+		return string(u.Kind)
+	case "Name":
+		return u.Name
+	case "Sources":
+		return printableNames(u.Sources)
+	case "Processors":
+		return printableNames(u.Processors)
+	case "Destinations":
+		return printableNames(u.Destinations)
+	case "Configurations":
+		return printableNames(u.Configurations)
+	case "Agents":
+		return strconv.Itoa(len(u.Agents))
+	default:
+		return "-"
+	}
+}
+
+func printableNames(names []string) string {
+	if len(names) == 0 {
+		return "-"
+	}
+	return strings.Join(names, ",")
+}
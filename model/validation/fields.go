@@ -0,0 +1,115 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validation
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// FieldError is a validation error for a single field of a resource. Field is the path of the field, e.g.
+// metadata.name or spec.sources[0].parameters.port. The message of the error is unchanged so that it reads the same
+// with or without the field.
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+// NewFieldError returns a FieldError for the specified field or nil if err is nil
+func NewFieldError(field string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &FieldError{Field: field, Err: err}
+}
+
+// Error returns the message of the underlying error
+func (e *FieldError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// WithField returns Errors that adds the specified field to each error before adding it to errs. Fields of errors
+// that already have a field are appended to the specified field so that nested validation can report relative paths.
+// Warnings are passed through unchanged.
+func WithField(errs Errors, field string) Errors {
+	return &fieldErrors{Errors: errs, field: field}
+}
+
+type fieldErrors struct {
+	Errors
+	field string
+}
+
+func (e *fieldErrors) Add(err error) {
+	if err == nil {
+		return
+	}
+	// add each error of a multierror separately so that they are flattened like other errors
+	if merr, ok := err.(*multierror.Error); ok {
+		for _, err := range merr.Errors {
+			e.Add(err)
+		}
+		return
+	}
+	var fieldErr *FieldError
+	if errors.As(err, &fieldErr) {
+		e.Errors.Add(NewFieldError(joinField(e.field, fieldErr.Field), err))
+		return
+	}
+	e.Errors.Add(NewFieldError(e.field, err))
+}
+
+func joinField(parent, child string) string {
+	switch {
+	case parent == "":
+		return child
+	case child == "":
+		return parent
+	case child[0] == '[':
+		return parent + child
+	default:
+		return fmt.Sprintf("%s.%s", parent, child)
+	}
+}
+
+// FieldErrors splits an error returned by validation into a FieldError for each error. Errors that are not associated
+// with a field are returned with an empty Field.
+func FieldErrors(err error) []*FieldError {
+	if err == nil {
+		return nil
+	}
+	errs := []error{err}
+	var merr *multierror.Error
+	if errors.As(err, &merr) {
+		errs = merr.Errors
+	}
+	result := make([]*FieldError, 0, len(errs))
+	for _, err := range errs {
+		var fieldErr *FieldError
+		if errors.As(err, &fieldErr) {
+			result = append(result, &FieldError{Field: fieldErr.Field, Err: err})
+			continue
+		}
+		result = append(result, &FieldError{Err: err})
+	}
+	return result
+}
@@ -0,0 +1,102 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validation
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldErrors(t *testing.T) {
+	type fieldError struct {
+		field   string
+		message string
+	}
+	tests := []struct {
+		name   string
+		add    func(errs Errors)
+		expect []fieldError
+	}{
+		{
+			name:   "no errors",
+			add:    func(errs Errors) {},
+			expect: nil,
+		},
+		{
+			name: "error without field",
+			add: func(errs Errors) {
+				errs.Add(errors.New("bad"))
+			},
+			expect: []fieldError{{"", "bad"}},
+		},
+		{
+			name: "field error",
+			add: func(errs Errors) {
+				errs.Add(NewFieldError("metadata.name", errors.New("bad name")))
+			},
+			expect: []fieldError{{"metadata.name", "bad name"}},
+		},
+		{
+			name: "nested fields",
+			add: func(errs Errors) {
+				spec := WithField(errs, "spec")
+				sources := WithField(spec, "sources[0]")
+				sources.Add(NewFieldError("parameters.port", errors.New("bad port")))
+				sources.Add(errors.New("bad source"))
+				WithField(spec, "parameters").Add(NewFieldError("[1]", errors.New("bad parameter")))
+			},
+			expect: []fieldError{
+				{"spec.sources[0].parameters.port", "bad port"},
+				{"spec.sources[0]", "bad source"},
+				{"spec.parameters[1]", "bad parameter"},
+			},
+		},
+		{
+			name: "multierror is flattened",
+			add: func(errs Errors) {
+				var merr error
+				merr = multierror.Append(merr, errors.New("first"), errors.New("second"))
+				WithField(errs, "metadata.labels").Add(merr)
+			},
+			expect: []fieldError{
+				{"metadata.labels", "first"},
+				{"metadata.labels", "second"},
+			},
+		},
+		{
+			name: "nil is ignored",
+			add: func(errs Errors) {
+				WithField(errs, "spec").Add(nil)
+				errs.Add(NewFieldError("spec", nil))
+			},
+			expect: nil,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errs := NewErrors()
+			test.add(errs)
+
+			var result []fieldError
+			for _, err := range FieldErrors(errs.Result()) {
+				result = append(result, fieldError{err.Field, err.Error()})
+			}
+			require.Equal(t, test.expect, result)
+		})
+	}
+}
@@ -19,6 +19,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/observiq/bindplane-op/model/validation"
 )
 
 func TestConfigurationValidate(t *testing.T) {
@@ -136,6 +138,10 @@ func TestSourceTypeValidate(t *testing.T) {
 			expectErrorMessage:     "20 errors occurred:\n\t* missing type for 'no_type'\n\t* missing name for parameter\n\t* invalid name 'bad-name' for parameter\n\t* missing type for 'bad-name'\n\t* invalid type 'bad-type' for 'bad_type'\n\t* parameter of type 'enum' or 'enums' must have 'validValues' specified\n\t* validValues is undefined for parameter of type 'strings'\n\t* default value for 'bad_string_default' must be a string\n\t* default value for 'bad_bool_default' must be a bool\n\t* default value for 'bad_strings_default' must be an array of strings\n\t* default value for 'bad_int_default' must be an integer\n\t* default value for 'bad_int_default_as_float' must be an integer\n\t* default value for 'bad_enum_default' must be one of [1 2 3]\n\t* relevantIf for 'bad_relevant_if_2' must have a name\n\t* relevantIf for 'bad_relevant_if_2' refers to nonexistant parameter 'does_not_exist'\n\t* relevantIf 'string_default_1' for 'bad_relevant_if_2': relevantIf value for 'string_default_1' must be a string\n\t* relevantIf 'string_default_2' for 'bad_relevant_if_2' must have an operator\n\t* relevantIf 'string_default_3' for 'bad_relevant_if_2' must have a value\n\t* relevantIf 'bad_enum_default' for 'bad_relevant_if_2': relevantIf value for 'bad_enum_default' must be one of [1 2 3]\n\t* relevantIf 'bad_bool_default' for 'bad_relevant_if_2': relevantIf value for 'bad_bool_default' must be a bool\n\n",
 			expectValidateWarnings: "1 warning occurred:\n\t* SourceType MacOS is missing .metadata.icon\n\n",
 		},
+		{
+			testfile:           "sourcetype-bad-rules.yaml",
+			expectErrorMessage: "12 errors occurred:\n\t* relevantIf 'protocol' for 'bad_relevant_if' has unsupported operator 'greaterThan'\n\t* relevantIf 'protocol' for 'bad_relevant_if' must have a list of values\n\t* relevantIf 'protocol' for 'bad_relevant_if': relevantIf value for 'protocol' must be one of [tcp udp]\n\t* relevantIf 'protocol' for 'bad_relevant_if' uses containsAny with parameter of type 'enum'\n\t* relevantIf 'and' for 'bad_relevant_if' must have conditions\n\t* relevantIf for 'bad_relevant_if' refers to nonexistant parameter 'does_not_exist'\n\t* rules[0] must have a type\n\t* rules[1] has unsupported type 'exactlyOneOf'\n\t* rules[2] rule 'mutuallyExclusive' must specify at least two parameters\n\t* rules[3] rule 'mutuallyExclusive' cannot include parameters with default values: 'protocol'\n\t* rules[4] refers to nonexistant parameter 'does_not_exist'\n\t* when 'protocol' for 'rules[4]': relevantIf value for 'protocol' must be one of [tcp udp]\n\n",
+		},
 		{
 			testfile:           "sourcetype-bad-templates.yaml",
 			expectErrorMessage: "2 errors occurred:\n\t* template: logs.receivers:6: unexpected \"}\" in operand\n\t* template: logs.processors:1:5: executing \"logs.processors\" at <.not_a_variable>: map has no entry for key \"not_a_variable\"\n\n",
@@ -193,6 +199,16 @@ func TestSourceValidate(t *testing.T) {
 			expectValidateError:          "",
 			expectValidateWithStoreError: "3 errors occurred:\n\t* parameter value for 'install_log_path' must be a string\n\t* parameter value for 'start_at' must be one of [beginning end]\n\t* parameter unknown not defined in type MacOS\n\n",
 		},
+		{
+			testfile:                     "source-ok-rules.yaml",
+			expectValidateError:          "",
+			expectValidateWithStoreError: "",
+		},
+		{
+			testfile:                     "source-bad-rules.yaml",
+			expectValidateError:          "",
+			expectValidateWithStoreError: "3 errors occurred:\n\t* at least one of parameters 'listen_address', 'socket_path' is required\n\t* parameter 'key_file' is required\n\t* parameters 'token', 'use_env_token' are mutually exclusive: specify a token or read it from the environment\n\n",
+		},
 		{
 			testfile:                     "source-bad-processor-type.yaml",
 			expectValidateError:          "",
@@ -215,6 +231,9 @@ func TestSourceValidate(t *testing.T) {
 	macos := testResource[*SourceType](t, "sourcetype-macos.yaml")
 	store.sourceTypes[macos.Name()] = macos
 
+	tcpRules := testResource[*SourceType](t, "sourcetype-tcp-rules.yaml")
+	store.sourceTypes[tcpRules.Name()] = tcpRules
+
 	for _, test := range tests {
 		t.Run(test.testfile, func(t *testing.T) {
 			src := validateResource[*Source](t, test.testfile)
@@ -240,3 +259,56 @@ func TestSourceValidate(t *testing.T) {
 	}
 
 }
+
+func TestSourceValidateFields(t *testing.T) {
+	tests := []struct {
+		testfile     string
+		expectFields []string
+	}{
+		{
+			testfile:     "source-ok.yaml",
+			expectFields: []string{},
+		},
+		{
+			testfile:     "source-bad-name.yaml",
+			expectFields: []string{"metadata.name"},
+		},
+		{
+			testfile:     "source-bad-labels.yaml",
+			expectFields: []string{"metadata.labels"},
+		},
+		{
+			testfile:     "source-bad-parameter-values.yaml",
+			expectFields: []string{"spec.parameters.install_log_path", "spec.parameters.start_at", "spec.parameters.unknown"},
+		},
+		{
+			testfile:     "source-bad-rules.yaml",
+			expectFields: []string{"spec.parameters", "spec.parameters", "spec.parameters"},
+		},
+		{
+			testfile:     "source-bad-processor-type.yaml",
+			expectFields: []string{"spec.processors[0].type"},
+		},
+	}
+
+	store := newTestResourceStore()
+
+	macos := testResource[*SourceType](t, "sourcetype-macos.yaml")
+	store.sourceTypes[macos.Name()] = macos
+
+	tcpRules := testResource[*SourceType](t, "sourcetype-tcp-rules.yaml")
+	store.sourceTypes[tcpRules.Name()] = tcpRules
+
+	for _, test := range tests {
+		t.Run(test.testfile, func(t *testing.T) {
+			src := validateResource[*Source](t, test.testfile)
+
+			_, err := src.ValidateWithStore(store)
+			fields := []string{}
+			for _, fieldErr := range validation.FieldErrors(err) {
+				fields = append(fields, fieldErr.Field)
+			}
+			require.Equal(t, test.expectFields, fields)
+		})
+	}
+}
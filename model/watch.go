@@ -0,0 +1,41 @@
+// Copyright  observIQ, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import "encoding/json"
+
+// WatchEventType is the type of change to a resource in a WatchEvent
+type WatchEventType string
+
+const (
+	// WatchEventInsert indicates that the resource was created. Resources that exist when the watch starts are also
+	// sent as inserts.
+	WatchEventInsert WatchEventType = "insert"
+	// WatchEventUpdate indicates that the resource was modified
+	WatchEventUpdate WatchEventType = "update"
+	// WatchEventRemove indicates that the resource was deleted or no longer matches the selector of the watch
+	WatchEventRemove WatchEventType = "remove"
+)
+
+// WatchEvent is a change to a resource sent by the /v1/watch websocket. Each message is a JSON array of events.
+type WatchEvent struct {
+	Type WatchEventType `json:"type" yaml:"type"`
+	Kind Kind           `json:"kind" yaml:"kind"`
+	// Name is the unique key of the resource, which is the ID of an Agent and the name of other resources
+	Name string `json:"name" yaml:"name"`
+	// Resource is the JSON of the resource. Agents are sent in the format of GET /v1/agents/{id} and other resources in
+	// the format of apply.
+	Resource json.RawMessage `json:"resource" yaml:"-"`
+}
@@ -8,8 +8,10 @@ import {
 import { GraphQLWsLink } from "@apollo/client/link/subscriptions";
 import { getMainDefinition } from "@apollo/client/utilities";
 import { onError } from "@apollo/client/link/error";
+import { setContext } from "@apollo/client/link/context";
 import { isFunction } from "lodash";
 import { createClient } from "graphql-ws";
+import { csrfHeaders } from "./utils/csrf";
 
 const httpLink = new HttpLink({
   uri: "/v1/graphql",
@@ -52,8 +54,16 @@ const authErrorLink = onError(({ operation }) => {
   }
 });
 
-// Chain the auth link and request link together
-const link = from([authErrorLink, requestLink]);
+// csrfLink adds the CSRF token of the session, required for mutations.
+const csrfLink = setContext((_, { headers }) => ({
+  headers: {
+    ...headers,
+    ...csrfHeaders(),
+  },
+}));
+
+// Chain the auth link, csrf link, and request link together
+const link = from([authErrorLink, csrfLink, requestLink]);
 
 const APOLLO_CLIENT = new ApolloClient({
   link: link,
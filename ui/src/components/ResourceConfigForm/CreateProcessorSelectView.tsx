@@ -32,6 +32,9 @@ gql`
             value
           }
           validValues
+          min
+          max
+          pattern
           options {
             creatable
             trackUnchecked
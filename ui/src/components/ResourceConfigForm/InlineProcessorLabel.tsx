@@ -53,6 +53,9 @@ gql`
             url
           }
           validValues
+          min
+          max
+          pattern
         }
       }
     }
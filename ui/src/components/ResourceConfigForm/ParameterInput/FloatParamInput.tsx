@@ -0,0 +1,42 @@
+import { TextField } from "@mui/material";
+import { isFunction } from "lodash";
+import { ChangeEvent, memo } from "react";
+import { ParamInputProps } from "./ParameterInput";
+
+import styles from "./parameter-input.module.scss";
+
+const FloatParamInputComponent: React.FC<ParamInputProps<number>> = ({
+  definition,
+  value,
+  onValueChange,
+}) => {
+  return (
+    <TextField
+      classes={{
+        root: definition.relevantIf ? styles.indent : undefined,
+      }}
+      value={value}
+      onChange={(e: ChangeEvent<HTMLInputElement>) =>
+        isFunction(onValueChange) && onValueChange(Number(e.target.value))
+      }
+      name={definition.name}
+      fullWidth
+      size="small"
+      label={definition.label}
+      helperText={definition.description}
+      required={definition.required}
+      autoComplete="off"
+      autoCorrect="off"
+      autoCapitalize="off"
+      spellCheck="false"
+      type={"number"}
+      inputProps={{
+        min: definition.min ?? undefined,
+        max: definition.max ?? undefined,
+        step: "any",
+      }}
+    />
+  );
+};
+
+export const FloatParamInput = memo(FloatParamInputComponent);
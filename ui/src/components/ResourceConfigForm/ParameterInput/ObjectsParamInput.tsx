@@ -0,0 +1,100 @@
+import { FormControl, InputLabel, FormHelperText } from "@mui/material";
+import { isEmpty, isFunction } from "lodash";
+import { useState, ChangeEvent, memo } from "react";
+import { YamlEditor } from "../../YamlEditor";
+import { ParamInputProps } from "./ParameterInput";
+import { useValidationContext } from "../ValidationContext";
+import {
+  OBJECTS_ERROR_MSG,
+  validateObjectsField,
+} from "../validation-functions";
+
+import styles from "./parameter-input.module.scss";
+
+// ObjectsParamInput edits the list of objects as JSON. The form value is only
+// updated when the text is valid JSON.
+const ObjectsParamInputComponent: React.FC<
+  ParamInputProps<Record<string, any>[]>
+> = ({ definition, value, onValueChange }) => {
+  const [isFocused, setFocused] = useState(false);
+  const [text, setText] = useState(
+    value == null ? "" : JSON.stringify(value, null, 2)
+  );
+  const { errors, setError, touched, touch } = useValidationContext();
+
+  const shrinkLabel = isFocused || !isEmpty(text);
+
+  function handleValueChange(e: ChangeEvent<HTMLTextAreaElement>) {
+    if (!touched[definition.name]) {
+      touch(definition.name);
+    }
+    setText(e.target.value);
+
+    if (isEmpty(e.target.value.trim())) {
+      setError(
+        definition.name,
+        validateObjectsField(null, definition.required)
+      );
+      isFunction(onValueChange) && onValueChange([]);
+      return;
+    }
+
+    let parsed: Record<string, any>[];
+    try {
+      parsed = JSON.parse(e.target.value);
+    } catch {
+      setError(definition.name, OBJECTS_ERROR_MSG);
+      return;
+    }
+
+    const error = validateObjectsField(parsed, definition.required);
+    setError(definition.name, error);
+    if (error !== OBJECTS_ERROR_MSG) {
+      isFunction(onValueChange) && onValueChange(parsed);
+    }
+  }
+
+  return (
+    <FormControl
+      fullWidth
+      classes={{ root: definition.relevantIf ? styles.indent : undefined }}
+      required={definition.required}
+    >
+      <InputLabel
+        shrink={shrinkLabel}
+        htmlFor={definition.name}
+        style={{
+          backgroundColor: "#fff",
+          color: shrinkLabel ? "#4abaeb" : undefined,
+          padding: shrinkLabel ? "0 10px 0 5px" : undefined,
+        }}
+      >
+        {definition.label}
+      </InputLabel>
+      <YamlEditor
+        required={definition.required}
+        name={definition.name}
+        value={text}
+        onValueChange={handleValueChange}
+        onFocus={() => setFocused(true)}
+        onBlur={() => setFocused(false)}
+        minHeight={200}
+      />
+      <FormHelperText>{definition.description}</FormHelperText>
+      {touched[definition.name] && errors[definition.name] && (
+        <FormHelperText error>{errors[definition.name]}</FormHelperText>
+      )}
+      {(definition.documentation ?? []).map((d) => {
+        return (
+          <FormHelperText key={d.text}>
+            <a href={d.url} rel="noreferrer" target="_blank">
+              {d.text}
+            </a>
+          </FormHelperText>
+        );
+      })}
+    </FormControl>
+  );
+};
+
+export const ObjectsParamInput = memo(ObjectsParamInputComponent);
@@ -3,8 +3,10 @@ import {
   BoolParamInput,
   EnumParamInput,
   EnumsParamInput,
+  FloatParamInput,
   IntParamInput,
   MapParamInput,
+  ObjectsParamInput,
   StringParamInput,
   StringsParamInput,
   TimezoneParamInput,
@@ -32,6 +34,10 @@ export const ParameterInput: React.FC<{ definition: ParameterDefinition }> = ({
 
   switch (definition.type) {
     case ParameterType.String:
+    case ParameterType.Duration:
+    case ParameterType.Hostport:
+    case ParameterType.Url:
+    case ParameterType.Regex:
       return (
         <StringParamInput
           definition={definition}
@@ -79,6 +85,14 @@ export const ParameterInput: React.FC<{ definition: ParameterDefinition }> = ({
           onValueChange={onValueChange}
         />
       );
+    case ParameterType.Float:
+      return (
+        <FloatParamInput
+          definition={definition}
+          value={formValues[definition.name]}
+          onValueChange={onValueChange}
+        />
+      );
     case ParameterType.Map:
       return (
         <MapParamInput
@@ -103,5 +117,15 @@ export const ParameterInput: React.FC<{ definition: ParameterDefinition }> = ({
           onValueChange={onValueChange}
         />
       );
+    case ParameterType.Objects:
+      return (
+        <ObjectsParamInput
+          definition={definition}
+          value={formValues[definition.name]}
+          onValueChange={onValueChange}
+        />
+      );
+    default:
+      return null;
   }
 };
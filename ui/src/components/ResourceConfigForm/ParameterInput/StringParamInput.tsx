@@ -1,6 +1,8 @@
 import { TextField } from "@mui/material";
 import { isFunction } from "lodash";
 import { ChangeEvent, memo } from "react";
+import { validateStringField } from "../validation-functions";
+import { useValidationContext } from "../ValidationContext";
 import { ParamInputProps } from "./ParameterInput";
 
 import styles from "./parameter-input.module.scss";
@@ -10,20 +12,42 @@ const StringParamInputComponent: React.FC<ParamInputProps<string>> = ({
   value,
   onValueChange,
 }) => {
+  const { errors, setError, touched, touch } = useValidationContext();
+
+  function handleChange(e: ChangeEvent<HTMLInputElement>) {
+    isFunction(onValueChange) && onValueChange(e.target.value);
+    setError(
+      definition.name,
+      validateStringField(e.target.value, definition.pattern)
+    );
+  }
+
+  const error = touched[definition.name] ? errors[definition.name] : null;
+
   return (
     <TextField
       classes={{
         root: definition.relevantIf ? styles.indent : undefined,
       }}
       value={value}
-      onChange={(e: ChangeEvent<HTMLInputElement>) =>
-        isFunction(onValueChange) && onValueChange(e.target.value)
-      }
+      onChange={handleChange}
+      onBlur={() => touch(definition.name)}
+      error={error != null}
       name={definition.name}
       fullWidth
       size="small"
       label={definition.label}
-      helperText={definition.description}
+      helperText={
+        error != null ? (
+          <>
+            {definition.description}
+            <br />
+            {error}
+          </>
+        ) : (
+          definition.description
+        )
+      }
       required={definition.required}
       autoComplete="off"
       autoCorrect="off"
@@ -51,7 +51,7 @@ const StringsParamInputComponent: React.FC<ParamInputProps<string[]>> = ({
     setInputValue("");
     setError(
       definition.name,
-      validateStringsField(newValue, definition.required)
+      validateStringsField(newValue, definition.required, definition.pattern)
     );
   }
 
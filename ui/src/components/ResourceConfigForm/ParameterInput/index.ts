@@ -6,6 +6,7 @@ export { BoolParamInput } from "./BoolParamInput";
 export { EnumParamInput } from "./EnumParamInput";
 export { EnumsParamInput } from "./EnumsParamInput";
 export { IntParamInput } from "./IntParamInput";
+export { FloatParamInput } from "./FloatParamInput";
 export {
   MapParamInput,
   tupleArrayToMap,
@@ -13,4 +14,5 @@ export {
 } from "./MapParamInput";
 export type { Tuple } from "./MapParamInput";
 export { YamlParamInput } from "./YamlParamInput";
+export { ObjectsParamInput } from "./ObjectsParamInput";
 export { ResourceNameInput } from "./ResourceNameParamInput";
@@ -21,7 +21,12 @@ import {
   FormValueContextProvider,
   useResourceFormValues,
 } from "./ResourceFormContext";
-import { validateStringsField, validateMapField } from "./validation-functions";
+import {
+  validateStringField,
+  validateStringsField,
+  validateMapField,
+  validateObjectsField,
+} from "./validation-functions";
 
 enum Page {
   MAIN,
@@ -243,10 +248,17 @@ export const ResourceConfigForm: React.FC<ResourceFormProps> = (props) => {
   const initErrors: Record<string, string | null> = {};
   for (const definition of props.parameterDefinitions) {
     switch (definition.type) {
+      case ParameterType.String:
+        initErrors[definition.name] = validateStringField(
+          initValues[definition.name],
+          definition.pattern
+        );
+        break;
       case ParameterType.Strings:
         initErrors[definition.name] = validateStringsField(
           initValues[definition.name],
-          definition.required
+          definition.required,
+          definition.pattern
         );
         break;
       case ParameterType.Map:
@@ -255,6 +267,12 @@ export const ResourceConfigForm: React.FC<ResourceFormProps> = (props) => {
           definition.required
         );
         break;
+      case ParameterType.Objects:
+        initErrors[definition.name] = validateObjectsField(
+          initValues[definition.name],
+          definition.required
+        );
+        break;
       default:
         initErrors[definition.name] = null;
     }
@@ -1,5 +1,9 @@
 import { isEqual } from "lodash";
-import { ParameterDefinition } from "../../graphql/generated";
+import {
+  ParameterDefinition,
+  RelevantIfCondition,
+  RelevantIfOperatorType,
+} from "../../graphql/generated";
 
 export function satisfiesRelevantIf(
   formValues: { [name: string]: any },
@@ -9,15 +13,56 @@ export function satisfiesRelevantIf(
     return true;
   }
 
-  const relevantIf = definition.relevantIf;
+  return definition.relevantIf.every((condition) =>
+    satisfiesCondition(formValues, condition)
+  );
+}
+
+function satisfiesCondition(
+  formValues: { [name: string]: any },
+  condition: RelevantIfCondition
+): boolean {
+  const value = formValues[condition.name];
 
-  for (const condition of relevantIf) {
-    // TODO (dsvanlani) Right now we only support and expect the "equals" operator
-    // Add a capability to satisfy other operators like "less than" or "greater than".
-    if (!isEqual(formValues[condition.name], condition.value)) {
+  switch (condition.operator) {
+    case RelevantIfOperatorType.And:
+      return (condition.conditions ?? []).every((c) =>
+        satisfiesCondition(formValues, c)
+      );
+    case RelevantIfOperatorType.Or:
+      return (condition.conditions ?? []).some((c) =>
+        satisfiesCondition(formValues, c)
+      );
+    case RelevantIfOperatorType.Equals:
+      return isEqual(value, condition.value);
+    case RelevantIfOperatorType.NotEquals:
+      return !isEqual(value, condition.value);
+    case RelevantIfOperatorType.In:
+      return asArray(condition.value).some((v) => isEqual(value, v));
+    case RelevantIfOperatorType.ContainsAny:
+      return asArray(condition.value).some((v) =>
+        asArray(value).some((item) => isEqual(item, v))
+      );
+    case RelevantIfOperatorType.Exists:
+      return !isEmpty(value);
+    default:
       return false;
-    }
   }
+}
 
-  return true;
+function asArray(value: any): any[] {
+  return Array.isArray(value) ? value : [];
+}
+
+function isEmpty(value: any): boolean {
+  if (value == null || value === false || value === "") {
+    return true;
+  }
+  if (Array.isArray(value)) {
+    return value.length === 0;
+  }
+  if (typeof value === "object") {
+    return Object.keys(value).length === 0;
+  }
+  return false;
 }
@@ -1,4 +1,31 @@
-import { validateMapField, validateStringsField } from "./validation-functions";
+import {
+  validateMapField,
+  validateObjectsField,
+  validateStringField,
+  validateStringsField,
+} from "./validation-functions";
+
+describe("validateStringField", () => {
+  it("empty, pattern", () => {
+    const error = validateStringField("", "^[a-z]+$");
+    expect(error).toBeNull();
+  });
+
+  it("matches pattern", () => {
+    const error = validateStringField("abc", "^[a-z]+$");
+    expect(error).toBeNull();
+  });
+
+  it("doesn't match pattern => error", () => {
+    const error = validateStringField("ABC", "^[a-z]+$");
+    expect(error).toBe("Must match the pattern ^[a-z]+$.");
+  });
+
+  it("invalid pattern", () => {
+    const error = validateStringField("abc", "[");
+    expect(error).toBeNull();
+  });
+});
 
 describe("validateStringsField", () => {
   it("[], required", () => {
@@ -10,6 +37,16 @@ describe("validateStringsField", () => {
     const error = validateStringsField([], false);
     expect(error).toBeNull();
   });
+
+  it("matches pattern", () => {
+    const error = validateStringsField(["a", "b"], false, "^[a-z]$");
+    expect(error).toBeNull();
+  });
+
+  it("doesn't match pattern => error", () => {
+    const error = validateStringsField(["a", "B"], false, "^[a-z]$");
+    expect(error).not.toBeNull();
+  });
 });
 
 describe("validateMapField", () => {
@@ -23,3 +60,25 @@ describe("validateMapField", () => {
     expect(error).not.toBeNull();
   });
 });
+
+describe("validateObjectsField", () => {
+  it("[], required => error", () => {
+    const error = validateObjectsField([], true);
+    expect(error).not.toBeNull();
+  });
+
+  it("[], not required", () => {
+    const error = validateObjectsField([], false);
+    expect(error).toBeNull();
+  });
+
+  it("[1], not required => error", () => {
+    const error = validateObjectsField([1] as any, false);
+    expect(error).not.toBeNull();
+  });
+
+  it(`[{"name":"policy"}], required`, () => {
+    const error = validateObjectsField([{ name: "policy" }], true);
+    expect(error).toBeNull();
+  });
+});
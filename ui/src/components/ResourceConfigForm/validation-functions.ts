@@ -1,18 +1,57 @@
 import { isEmpty } from "lodash";
 
 const REQUIRED_ERROR_MSG = "Required.";
+export const OBJECTS_ERROR_MSG = "Must be a JSON list of objects.";
+
+export function validateStringField(
+  value: string | null,
+  pattern?: string | null
+): string | null {
+  if (value == null || isEmpty(value)) {
+    return null;
+  }
+
+  if (!matchesPattern(value, pattern)) {
+    return patternErrorMessage(pattern!);
+  }
+
+  return null;
+}
 
 export function validateStringsField(
   value: string[],
-  required?: boolean
+  required?: boolean,
+  pattern?: string | null
 ): string | null {
   if (required && isEmpty(value)) {
     return REQUIRED_ERROR_MSG;
   }
 
+  if (value?.some((v) => !matchesPattern(v, pattern))) {
+    return patternErrorMessage(pattern!);
+  }
+
   return null;
 }
 
+// matchesPattern returns true if the pattern is found anywhere in the value,
+// the same as the server. Invalid patterns are reported by the server.
+function matchesPattern(value: string, pattern?: string | null): boolean {
+  if (isEmpty(pattern)) {
+    return true;
+  }
+
+  try {
+    return new RegExp(pattern!).test(value);
+  } catch (err) {
+    return true;
+  }
+}
+
+function patternErrorMessage(pattern: string): string {
+  return `Must match the pattern ${pattern}.`;
+}
+
 export function validateMapField(
   value: Record<string, string> | null,
   required?: boolean
@@ -42,3 +81,25 @@ export function validateMapField(
 
   return null;
 }
+
+export function validateObjectsField(
+  value: Record<string, any>[] | null,
+  required?: boolean
+): string | null {
+  if (value == null) {
+    return required ? REQUIRED_ERROR_MSG : null;
+  }
+
+  if (
+    !Array.isArray(value) ||
+    value.some((v) => v == null || typeof v !== "object" || Array.isArray(v))
+  ) {
+    return OBJECTS_ERROR_MSG;
+  }
+
+  if (required && isEmpty(value)) {
+    return REQUIRED_ERROR_MSG;
+  }
+
+  return null;
+}
@@ -39,6 +39,9 @@ gql`
             value
           }
           validValues
+          min
+          max
+          pattern
           options {
             creatable
             trackUnchecked
@@ -50,6 +50,9 @@ gql`
               value
             }
             validValues
+            min
+            max
+            pattern
             options {
               creatable
               trackUnchecked
@@ -66,6 +66,9 @@ gql`
           description
           default
           validValues
+          min
+          max
+          pattern
           relevantIf {
             name
             value
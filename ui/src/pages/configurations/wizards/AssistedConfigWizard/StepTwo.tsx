@@ -58,6 +58,9 @@ gql`
           required
           type
           validValues
+          min
+          max
+          pattern
           default
           options {
             creatable
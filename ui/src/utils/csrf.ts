@@ -0,0 +1,20 @@
+const CSRF_COOKIE = "BP_OP_CSRF";
+const CSRF_HEADER = "X-CSRF-Token";
+
+/**
+ * csrfHeaders returns the header with the CSRF token of the session, read from
+ * the cookie set at login. Requests that modify resources are rejected
+ * without it.
+ */
+export function csrfHeaders(): Record<string, string> {
+  const prefix = `${CSRF_COOKIE}=`;
+  const cookie = document.cookie
+    .split(";")
+    .map((c) => c.trim())
+    .find((c) => c.startsWith(prefix));
+
+  if (cookie == null) {
+    return {};
+  }
+  return { [CSRF_HEADER]: decodeURIComponent(cookie.slice(prefix.length)) };
+}
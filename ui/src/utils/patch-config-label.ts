@@ -1,4 +1,5 @@
 import { PatchLabelsPayload, PatchLabelsResponse } from "../types/rest";
+import { csrfHeaders } from "./csrf";
 
 export async function patchConfigLabel(
   agentId: string,
@@ -13,6 +14,7 @@ export async function patchConfigLabel(
 
   const resp = await fetch(url, {
     method: "PATCH",
+    headers: csrfHeaders(),
     body: JSON.stringify(body),
   });
 
@@ -1,5 +1,6 @@
 import { Resource, ResourceStatus } from "../../types/resources";
 import { ApplyPayload, ApplyResponse } from "../../types/rest";
+import { csrfHeaders } from "../csrf";
 
 /**
  * applyResources posts to the api apply endpoint.  It will throw an error
@@ -14,6 +15,7 @@ export async function applyResources(
 
   const resp = await fetch("/v1/apply", {
     method: "POST",
+    headers: csrfHeaders(),
     body: JSON.stringify(payload),
   });
 
@@ -1,4 +1,5 @@
 import { CopyConfigPayload } from "../../types/rest";
+import { csrfHeaders } from "../csrf";
 
 export async function copyConfig({
   existingName,
@@ -13,6 +14,7 @@ export async function copyConfig({
   try {
     const resp = await fetch(`/v1/configurations/${existingName}/copy`, {
       method: "POST",
+      headers: csrfHeaders(),
       body: JSON.stringify(payload),
     });
 
@@ -1,4 +1,5 @@
 import { Agent } from "../../graphql/generated";
+import { csrfHeaders } from "../csrf";
 
 interface DeleteAgentsPayload {
   ids: string[];
@@ -19,6 +20,7 @@ export async function deleteAgents(
 
   const resp = await fetch(DELETE_ENDPOINT, {
     method: "DELETE",
+    headers: csrfHeaders(),
     body: JSON.stringify(payload),
   });
 
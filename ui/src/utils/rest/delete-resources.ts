@@ -1,5 +1,6 @@
 import { ResourceKind } from "../../types/resources";
 import { DeletePayload, DeleteResponse } from "../../types/rest";
+import { csrfHeaders } from "../csrf";
 
 export interface MinimumDeleteResource {
   metadata: {
@@ -21,6 +22,7 @@ export async function deleteResources(
 
   const resp = await fetch("/v1/delete", {
     method: "POST",
+    headers: csrfHeaders(),
     body: JSON.stringify(payload),
   });
 
@@ -1,4 +1,5 @@
 import { LabelAgentsPayload, LabelAgentsResponse } from "../../types/rest";
+import { csrfHeaders } from "../csrf";
 
 /**
  * labelAgents Patches agent labels, returning errors or throwing if response is not 200 OK.
@@ -22,6 +23,7 @@ export async function labelAgents(
   try {
     const resp = await fetch("/v1/agents/labels", {
       method: "PATCH",
+      headers: csrfHeaders(),
       body: JSON.stringify(body),
     });
 
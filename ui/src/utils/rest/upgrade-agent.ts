@@ -1,4 +1,5 @@
 import { UpgradeAgentResponse } from "../../types/rest";
+import { csrfHeaders } from "../csrf";
 
 export async function upgradeAgent(
   id: string,
@@ -9,6 +10,7 @@ export async function upgradeAgent(
 
   const resp = await fetch(endpoint, {
     method: "POST",
+    headers: csrfHeaders(),
     body: JSON.stringify(body),
   });
 
@@ -29,6 +31,7 @@ export async function upgradeAgents(ids: string[], version?: string) {
 
   const resp = await fetch(endpoint, {
     method: "PATCH",
+    headers: csrfHeaders(),
     body: JSON.stringify(body),
   });
 